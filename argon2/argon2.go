@@ -60,22 +60,13 @@ func GenerateSaltedHashWithTypeAndOpt(password string, typ string, opt Opt) (str
 
     salt, _ := generateSalt(saltLen)
 
-    var unencodedPassword []byte
-    switch typ {
-        case "argon2id":
-            unencodedPassword = argon2.IDKey(
-                []byte(password), []byte(salt),
-                argon2Time, argon2Memory,
-                argon2Threads, argon2KeyLen,
-            )
-        case "argon2i", "argon2":
-            unencodedPassword = argon2.Key(
-                []byte(password), []byte(salt),
-                argon2Time, argon2Memory,
-                argon2Threads, argon2KeyLen,
-            )
-        default:
-            return "", errors.New("Invalid Hash Type")
+    unencodedPassword, err := Key(
+        typ, []byte(password), []byte(salt),
+        argon2Time, argon2Memory,
+        argon2Threads, argon2KeyLen,
+    )
+    if err != nil {
+        return "", errors.New("Invalid Hash Type")
     }
 
     encodedPassword := base64.StdEncoding.EncodeToString(unencodedPassword)
@@ -110,22 +101,13 @@ func CompareHashWithPassword(hash, password string) (bool, error) {
     salt := []byte(hashParts[5])
     key, _ := base64.StdEncoding.DecodeString(hashParts[6])
 
-    var calculatedKey []byte
-    switch passwordType {
-        case "argon2id":
-            calculatedKey = argon2.IDKey(
-                []byte(password), salt,
-                uint32(time), uint32(memory),
-                uint8(threads), uint32(keyLen),
-            )
-        case "argon2i", "argon2":
-            calculatedKey = argon2.Key(
-                []byte(password), salt,
-                uint32(time), uint32(memory),
-                uint8(threads), uint32(keyLen),
-            )
-        default:
-            return false, errors.New("Invalid Password Hash")
+    calculatedKey, err := Key(
+        passwordType, []byte(password), salt,
+        uint32(time), uint32(memory),
+        uint8(threads), uint32(keyLen),
+    )
+    if err != nil {
+        return false, errors.New("Invalid Password Hash")
     }
 
     if subtle.ConstantTimeCompare(key, calculatedKey) != 1 {
@@ -135,6 +117,18 @@ func CompareHashWithPassword(hash, password string) (bool, error) {
     return true, nil
 }
 
+// 生成原始密钥, typ 可用 [argon2id | argon2i | argon2]
+func Key(typ string, password, salt []byte, time, memory uint32, threads uint8, keyLen uint32) ([]byte, error) {
+    switch typ {
+        case "argon2id":
+            return argon2.IDKey(password, salt, time, memory, threads, keyLen), nil
+        case "argon2i", "argon2":
+            return argon2.Key(password, salt, time, memory, threads, keyLen), nil
+    }
+
+    return nil, errors.New("Invalid Hash Type")
+}
+
 func generateSalt(len int) (string, error) {
     unencodedSalt := make([]byte, len)
 
@@ -0,0 +1,177 @@
+package fpe
+
+import (
+    "math/big"
+    "crypto/cipher"
+    "encoding/binary"
+)
+
+// FF1 最大 tweak 长度
+const maxFF1TweakSize = 1 << 16
+
+// FF1
+type FF1 struct {
+    block    cipher.Block
+    alphabet *alphabet
+    tweak    []byte
+    minLen   int
+}
+
+// 使用分组生成 FF1, alphabet 为字符集, 其长度即为基数
+func NewFF1(block cipher.Block, alphabet string, tweak []byte) (*FF1, error) {
+    if block.BlockSize() != BlockSize {
+        return nil, ErrBlockSize
+    }
+
+    a, err := newAlphabet(alphabet)
+    if err != nil {
+        return nil, err
+    }
+
+    if len(tweak) > maxFF1TweakSize {
+        return nil, ErrTweakSize
+    }
+
+    f := &FF1{
+        block:    block,
+        alphabet: a,
+        tweak:    append([]byte(nil), tweak...),
+        minLen:   a.minLength(),
+    }
+
+    return f, nil
+}
+
+// 加密
+func (this *FF1) Encrypt(src string) (string, error) {
+    return this.crypt(src, true)
+}
+
+// 解密
+func (this *FF1) Decrypt(src string) (string, error) {
+    return this.crypt(src, false)
+}
+
+func (this *FF1) crypt(src string, encrypt bool) (string, error) {
+    x, err := this.alphabet.numerals(src)
+    if err != nil {
+        return "", err
+    }
+
+    n := len(x)
+    if n < this.minLen || uint64(n) > 0xFFFFFFFF {
+        return "", ErrInputSize
+    }
+
+    radix := this.alphabet.radix
+    t := len(this.tweak)
+
+    u := n / 2
+    v := n - u
+
+    a := num(x[:u], radix)
+    b := num(x[u:], radix)
+
+    modU := pow(radix, u)
+    modV := pow(radix, v)
+
+    // b = ceil(ceil(v * log2(radix)) / 8)
+    bLen := (new(big.Int).Sub(modV, big.NewInt(1)).BitLen() + 7) / 8
+    d := 4*((bLen + 3) / 4) + 4
+
+    p := make([]byte, BlockSize)
+    p[0] = 1
+    p[1] = 2
+    p[2] = 1
+    p[3] = byte(radix.Uint64() >> 16)
+    p[4] = byte(radix.Uint64() >> 8)
+    p[5] = byte(radix.Uint64())
+    p[6] = 10
+    p[7] = byte(u)
+    binary.BigEndian.PutUint32(p[8:], uint32(n))
+    binary.BigEndian.PutUint32(p[12:], uint32(t))
+
+    padLen := (16 - (t + bLen + 1) % 16) % 16
+
+    q := make([]byte, t + padLen + 1 + bLen)
+    copy(q, this.tweak)
+
+    y := new(big.Int)
+
+    for j := 0; j < 10; j++ {
+        i := j
+        if !encrypt {
+            i = 9 - j
+        }
+
+        q[t + padLen] = byte(i)
+
+        if encrypt {
+            copy(q[t + padLen + 1:], fillBytes(b, bLen))
+        } else {
+            copy(q[t + padLen + 1:], fillBytes(a, bLen))
+        }
+
+        r := this.prf(p, q)
+        s := this.expand(r, d)
+
+        y.SetBytes(s)
+
+        m := modV
+        if i % 2 == 0 {
+            m = modU
+        }
+
+        if encrypt {
+            c := mod(y.Add(a, y), m)
+            a, b = b, new(big.Int).Set(c)
+        } else {
+            c := mod(y.Sub(b, y), m)
+            b, a = a, new(big.Int).Set(c)
+        }
+    }
+
+    out := append(str(a, u, radix), str(b, v, radix)...)
+
+    return this.alphabet.string(out), nil
+}
+
+// PRF, 零向量的 CBC-MAC
+func (this *FF1) prf(p, q []byte) []byte {
+    r := make([]byte, BlockSize)
+
+    this.block.Encrypt(r, p)
+
+    for i := 0; i < len(q); i += BlockSize {
+        for k := 0; k < BlockSize; k++ {
+            r[k] ^= q[i + k]
+        }
+
+        this.block.Encrypt(r, r)
+    }
+
+    return r
+}
+
+// S = R || CIPH(R ^ [1]) || CIPH(R ^ [2]) ..., 取前 d 字节
+func (this *FF1) expand(r []byte, d int) []byte {
+    s := make([]byte, 0, d + BlockSize)
+    s = append(s, r...)
+
+    tmp := make([]byte, BlockSize)
+    for j := 1; len(s) < d; j++ {
+        copy(tmp, r)
+
+        var ctr [BlockSize]byte
+        binary.BigEndian.PutUint64(ctr[8:], uint64(j))
+
+        for k := 0; k < BlockSize; k++ {
+            tmp[k] ^= ctr[k]
+        }
+
+        this.block.Encrypt(tmp, tmp)
+        s = append(s, tmp...)
+    }
+
+    return s[:d]
+}
@@ -0,0 +1,167 @@
+package fpe
+
+import (
+    "math/big"
+    "crypto/cipher"
+)
+
+// FF3-1 tweak 长度
+const FF31TweakSize = 7
+
+// FF3-1
+type FF31 struct {
+    block    cipher.Block
+    alphabet *alphabet
+    tweakL   []byte
+    tweakR   []byte
+    minLen   int
+    maxLen   int
+}
+
+// 生成 FF3-1, 算法使用反序的密钥生成分组
+// alphabet 为字符集, 其长度即为基数, tweak 为 7 字节
+func NewFF31(key []byte, newCipher CipherFunc, alphabet string, tweak []byte) (*FF31, error) {
+    if len(tweak) != FF31TweakSize {
+        return nil, ErrTweakSize
+    }
+
+    // T_L = T[0..27] || 0^4, T_R = T[32..55] || T[28..31] || 0^4
+    tweakL := []byte{tweak[0], tweak[1], tweak[2], tweak[3] & 0xF0}
+    tweakR := []byte{tweak[4], tweak[5], tweak[6], tweak[3] << 4}
+
+    return newFF3(key, newCipher, alphabet, tweakL, tweakR)
+}
+
+func newFF3(key []byte, newCipher CipherFunc, alphabet string, tweakL, tweakR []byte) (*FF31, error) {
+    block, err := newCipher(reverse(key))
+    if err != nil {
+        return nil, err
+    }
+
+    if block.BlockSize() != BlockSize {
+        return nil, ErrBlockSize
+    }
+
+    a, err := newAlphabet(alphabet)
+    if err != nil {
+        return nil, err
+    }
+
+    // maxlen = 2 * floor(log_radix(2^96))
+    limit := new(big.Int).Lsh(big.NewInt(1), 96)
+    d := new(big.Int).Set(a.radix)
+
+    maxLen := 0
+    for d.Cmp(limit) <= 0 {
+        d.Mul(d, a.radix)
+        maxLen++
+    }
+
+    f := &FF31{
+        block:    block,
+        alphabet: a,
+        tweakL:   tweakL,
+        tweakR:   tweakR,
+        minLen:   a.minLength(),
+        maxLen:   2 * maxLen,
+    }
+
+    return f, nil
+}
+
+// 加密
+func (this *FF31) Encrypt(src string) (string, error) {
+    return this.crypt(src, true)
+}
+
+// 解密
+func (this *FF31) Decrypt(src string) (string, error) {
+    return this.crypt(src, false)
+}
+
+func (this *FF31) crypt(src string, encrypt bool) (string, error) {
+    x, err := this.alphabet.numerals(src)
+    if err != nil {
+        return "", err
+    }
+
+    n := len(x)
+    if n < this.minLen || n > this.maxLen {
+        return "", ErrInputSize
+    }
+
+    radix := this.alphabet.radix
+
+    u := (n + 1) / 2
+    v := n - u
+
+    a := num(reverseNumerals(x[:u]), radix)
+    b := num(reverseNumerals(x[u:]), radix)
+
+    modU := pow(radix, u)
+    modV := pow(radix, v)
+
+    p := make([]byte, BlockSize)
+    s := make([]byte, BlockSize)
+    y := new(big.Int)
+
+    for j := 0; j < 8; j++ {
+        i := j
+        if !encrypt {
+            i = 7 - j
+        }
+
+        m, w := modU, this.tweakR
+        if i % 2 == 1 {
+            m, w = modV, this.tweakL
+        }
+
+        copy(p, w)
+        p[3] ^= byte(i)
+
+        if encrypt {
+            copy(p[4:], fillBytes(b, 12))
+        } else {
+            copy(p[4:], fillBytes(a, 12))
+        }
+
+        // S = REVB(CIPH(REVB(P)))
+        this.block.Encrypt(s, reverse(p))
+        y.SetBytes(reverse(s))
+
+        if encrypt {
+            c := mod(y.Add(a, y), m)
+            a, b = b, new(big.Int).Set(c)
+        } else {
+            c := mod(y.Sub(b, y), m)
+            b, a = a, new(big.Int).Set(c)
+        }
+    }
+
+    out := append(
+        reverseNumerals(str(a, u, radix)),
+        reverseNumerals(str(b, v, radix))...,
+    )
+
+    return this.alphabet.string(out), nil
+}
+
+// 字节反序
+func reverse(b []byte) []byte {
+    out := make([]byte, len(b))
+    for i, c := range b {
+        out[len(b) - 1 - i] = c
+    }
+
+    return out
+}
+
+// 数字串反序
+func reverseNumerals(x []uint16) []uint16 {
+    out := make([]uint16, len(x))
+    for i, c := range x {
+        out[len(x) - 1 - i] = c
+    }
+
+    return out
+}
@@ -0,0 +1,268 @@
+package fpe
+
+import (
+    "testing"
+    "crypto/aes"
+    "crypto/cipher"
+    "encoding/hex"
+
+    "github.com/deatil/go-cryptobin/cipher/sm4"
+    "github.com/deatil/go-cryptobin/cipher/aria"
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+func Test_FPEs(t *testing.T) {
+    test_FPE(t, aes.NewCipher, "AES")
+    test_FPE(t, sm4.NewCipher, "SM4")
+    test_FPE(t, aria.NewCipher, "Aria")
+}
+
+func test_FPE(t *testing.T, fn func([]byte) (cipher.Block, error), name string) {
+    t.Run(name, func(t *testing.T) {
+        assertEqual := cryptobin_test.AssertEqualT(t)
+        assertError := cryptobin_test.AssertErrorT(t)
+        assertTrue := cryptobin_test.AssertTrueT(t)
+
+        key := []byte("kkinjkijeel22plo")
+        tweak := []byte("tweak12")
+
+        inputs := map[string]string{
+            Digits:                "6222020200112233445",
+            Digits + "X":          "11010519491231002X",
+            AlphaNumeric:          "Hello2World",
+            "零一二三四五六七八九": "三一四一五九二六",
+        }
+
+        block, err := fn(key)
+        assertError(err, "NewCipher")
+
+        for alphabet, data := range inputs {
+            ff1, err := NewFF1(block, alphabet, tweak)
+            assertError(err, "NewFF1")
+
+            out, err := ff1.Encrypt(data)
+            assertError(err, "FF1-Encrypt")
+            assertEqual(len([]rune(out)), len([]rune(data)), "FF1-Len")
+            assertTrue(out != data, "FF1-Encrypt")
+
+            res, err := ff1.Decrypt(out)
+            assertError(err, "FF1-Decrypt")
+            assertEqual(res, data, "FF1-Decrypt")
+
+            ff3, err := NewFF31(key, fn, alphabet, tweak)
+            assertError(err, "NewFF31")
+
+            out, err = ff3.Encrypt(data)
+            assertError(err, "FF31-Encrypt")
+            assertEqual(len([]rune(out)), len([]rune(data)), "FF31-Len")
+            assertTrue(out != data, "FF31-Encrypt")
+
+            res, err = ff3.Decrypt(out)
+            assertError(err, "FF31-Decrypt")
+            assertEqual(res, data, "FF31-Decrypt")
+        }
+    })
+}
+
+// NIST SP 800-38G FF1 samples
+func Test_FF1Samples(t *testing.T) {
+    check := func(t *testing.T, key, tweak, alphabet, plaintext, ciphertext string) {
+        k, _ := hex.DecodeString(key)
+        tw, _ := hex.DecodeString(tweak)
+
+        block, err := aes.NewCipher(k)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        f, err := NewFF1(block, alphabet, tw)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        out, err := f.Encrypt(plaintext)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if out != ciphertext {
+            t.Errorf("Encrypt got %s, want %s", out, ciphertext)
+        }
+
+        res, err := f.Decrypt(out)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if res != plaintext {
+            t.Errorf("Decrypt got %s, want %s", res, plaintext)
+        }
+    }
+
+    t.Run("Sample 1", func(t *testing.T) {
+        check(t, "2B7E151628AED2A6ABF7158809CF4F3C", "",
+            Digits, "0123456789", "2433477484")
+    })
+
+    t.Run("Sample 2", func(t *testing.T) {
+        check(t, "2B7E151628AED2A6ABF7158809CF4F3C", "39383736353433323130",
+            Digits, "0123456789", "6124200773")
+    })
+
+    t.Run("Sample 3", func(t *testing.T) {
+        check(t, "2B7E151628AED2A6ABF7158809CF4F3C", "3737373770717273373737",
+            Digits + LowerLetters, "0123456789abcdefghi", "a9tv40mll9kdu509eum")
+    })
+
+    t.Run("Sample 4", func(t *testing.T) {
+        check(t, "2B7E151628AED2A6ABF7158809CF4F3CEF4359D8D580AA4F", "",
+            Digits, "0123456789", "2830668132")
+    })
+
+    t.Run("Sample 5", func(t *testing.T) {
+        check(t, "2B7E151628AED2A6ABF7158809CF4F3CEF4359D8D580AA4F", "39383736353433323130",
+            Digits, "0123456789", "2496655549")
+    })
+
+    t.Run("Sample 6", func(t *testing.T) {
+        check(t, "2B7E151628AED2A6ABF7158809CF4F3CEF4359D8D580AA4F", "3737373770717273373737",
+            Digits + LowerLetters, "0123456789abcdefghi", "xbj3kv35jrawxv32ysr")
+    })
+
+    t.Run("Sample 7", func(t *testing.T) {
+        check(t, "2B7E151628AED2A6ABF7158809CF4F3CEF4359D8D580AA4F7F036D6F04FC6A94", "",
+            Digits, "0123456789", "6657667009")
+    })
+
+    t.Run("Sample 8", func(t *testing.T) {
+        check(t, "2B7E151628AED2A6ABF7158809CF4F3CEF4359D8D580AA4F7F036D6F04FC6A94", "39383736353433323130",
+            Digits, "0123456789", "1001623463")
+    })
+
+    t.Run("Sample 9", func(t *testing.T) {
+        check(t, "2B7E151628AED2A6ABF7158809CF4F3CEF4359D8D580AA4F7F036D6F04FC6A94", "3737373770717273373737",
+            Digits + LowerLetters, "0123456789abcdefghi", "xs8a0azh2avyalyzuwd")
+    })
+}
+
+// NIST SP 800-38G FF3 samples, 使用 64 位 tweak, 只用于检查轮函数
+func Test_FF3Samples(t *testing.T) {
+    check := func(t *testing.T, key, tweak, plaintext, ciphertext string) {
+        k, _ := hex.DecodeString(key)
+        tw, _ := hex.DecodeString(tweak)
+
+        f, err := newFF3(k, aes.NewCipher, Digits, tw[:4], tw[4:])
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        out, err := f.Encrypt(plaintext)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if out != ciphertext {
+            t.Errorf("Encrypt got %s, want %s", out, ciphertext)
+        }
+
+        res, err := f.Decrypt(out)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if res != plaintext {
+            t.Errorf("Decrypt got %s, want %s", res, plaintext)
+        }
+    }
+
+    t.Run("Sample 1", func(t *testing.T) {
+        check(t, "EF4359D8D580AA4F7F036D6F04FC6A94", "D8E7920AFA330A73",
+            "890121234567890000", "750918814058654607")
+    })
+
+    t.Run("Sample 2", func(t *testing.T) {
+        check(t, "EF4359D8D580AA4F7F036D6F04FC6A94", "9A768A92F60E12D8",
+            "890121234567890000", "018989839189395384")
+    })
+}
+
+// NIST ACVP FF3-1 vectors, 使用 56 位 tweak
+func Test_FF31Vectors(t *testing.T) {
+    check := func(t *testing.T, key, tweak, plaintext, ciphertext string) {
+        k, _ := hex.DecodeString(key)
+        tw, _ := hex.DecodeString(tweak)
+
+        f, err := NewFF31(k, aes.NewCipher, Digits, tw)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        out, err := f.Encrypt(plaintext)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if out != ciphertext {
+            t.Errorf("Encrypt got %s, want %s", out, ciphertext)
+        }
+
+        res, err := f.Decrypt(out)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if res != plaintext {
+            t.Errorf("Decrypt got %s, want %s", res, plaintext)
+        }
+    }
+
+    t.Run("1", func(t *testing.T) {
+        check(t, "2DE79D232DF5585D68CE47882AE256D6", "CBD09280979564",
+            "3992520240", "8901801106")
+    })
+
+    t.Run("2", func(t *testing.T) {
+        check(t, "01C63017111438F7FC8E24EB16C71AB5", "C4E822DCD09F27",
+            "60761757463116869318437658042297305934914824457484538562",
+            "35637144092473838892796702739628394376915177448290847293")
+    })
+}
+
+func Test_AlphabetError(t *testing.T) {
+    block, _ := aes.NewCipher([]byte("kkinjkijeel22plo"))
+
+    if _, err := NewFF1(block, "0", nil); err != ErrAlphabet {
+        t.Errorf("NewFF1 alphabet got %v", err)
+    }
+
+    if _, err := NewFF1(block, "001", nil); err != ErrAlphabet {
+        t.Errorf("NewFF1 duplicate alphabet got %v", err)
+    }
+}
+
+func Test_FF1InputError(t *testing.T) {
+    block, _ := aes.NewCipher([]byte("kkinjkijeel22plo"))
+    ff1, _ := NewFF1(block, Digits, nil)
+
+    if _, err := ff1.Encrypt("12345"); err != ErrInputSize {
+        t.Errorf("FF1 short input got %v", err)
+    }
+
+    if _, err := ff1.Encrypt("12345a"); err != ErrInputString {
+        t.Errorf("FF1 input string got %v", err)
+    }
+}
+
+func Test_FF31TweakSize(t *testing.T) {
+    if _, err := NewFF31([]byte("kkinjkijeel22plo"), aes.NewCipher, Digits, []byte("tweak")); err != ErrTweakSize {
+        t.Errorf("NewFF31 tweak got %v", err)
+    }
+}
+
+func Test_FF31InputError(t *testing.T) {
+    ff3, _ := NewFF31([]byte("kkinjkijeel22plo"), aes.NewCipher, Digits, []byte("tweak12"))
+
+    if _, err := ff3.Encrypt("123456789012345678901234567890123456789012345678901234567890"); err != ErrInputSize {
+        t.Errorf("FF31 long input got %v", err)
+    }
+}
@@ -0,0 +1,236 @@
+package gcmsiv
+
+import (
+    "errors"
+    "crypto/cipher"
+    "crypto/subtle"
+    "encoding/binary"
+
+    "github.com/deatil/go-cryptobin/hash/polyval"
+    "github.com/deatil/go-cryptobin/tool/alias"
+)
+
+/**
+ * GCM-SIV 模式 (RFC 8452)
+ * AES-GCM-SIV: Nonce Misuse-Resistant Authenticated Encryption
+ *
+ * 每条消息使用 nonce 从密钥派生认证密钥及加密密钥,
+ * 加密密钥需要重新生成分组, 所以需要传入生成分组的方法
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+
+const (
+    // 分组长度
+    BlockSize = 16
+
+    // nonce 长度
+    NonceSize = 12
+
+    // tag 长度
+    TagSize = 16
+
+    // 最大数据长度
+    maxPlaintextSize  = 1 << 36
+    maxAdditionalSize = 1 << 36
+)
+
+var (
+    ErrOpen      = errors.New("cryptobin/gcmsiv: message authentication failed")
+    ErrBlockSize = errors.New("cryptobin/gcmsiv: block size must be 16 bytes")
+    ErrKeySize   = errors.New("cryptobin/gcmsiv: key size must be 16 or 32 bytes")
+)
+
+// 生成分组
+type CipherFunc = func(key []byte) (cipher.Block, error)
+
+type gcmsiv struct {
+    block     cipher.Block
+    keySize   int
+    newCipher CipherFunc
+}
+
+// 使用密钥生成 GCM-SIV
+// key 长度为 16 或者 32 字节
+func NewGCMSIV(key []byte, newCipher CipherFunc) (cipher.AEAD, error) {
+    block, err := newCipher(key)
+    if err != nil {
+        return nil, err
+    }
+
+    return NewGCMSIVWithBlock(block, len(key), newCipher)
+}
+
+// 使用密钥生成分组生成 GCM-SIV
+// block 为 key-generating key 生成的分组, keySize 为加密密钥长度
+func NewGCMSIVWithBlock(block cipher.Block, keySize int, newCipher CipherFunc) (cipher.AEAD, error) {
+    if block.BlockSize() != BlockSize {
+        return nil, ErrBlockSize
+    }
+
+    if keySize != 16 && keySize != 32 {
+        return nil, ErrKeySize
+    }
+
+    g := &gcmsiv{
+        block:     block,
+        keySize:   keySize,
+        newCipher: newCipher,
+    }
+
+    return g, nil
+}
+
+func (this *gcmsiv) NonceSize() int {
+    return NonceSize
+}
+
+func (this *gcmsiv) Overhead() int {
+    return TagSize
+}
+
+func (this *gcmsiv) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+    if len(nonce) != NonceSize {
+        panic("cryptobin/gcmsiv: incorrect nonce length given to GCM-SIV")
+    }
+
+    if uint64(len(plaintext)) > maxPlaintextSize {
+        panic("cryptobin/gcmsiv: message too large for GCM-SIV")
+    }
+
+    if uint64(len(additionalData)) > maxAdditionalSize {
+        panic("cryptobin/gcmsiv: additional data too large for GCM-SIV")
+    }
+
+    authKey, block, err := this.deriveKeys(nonce)
+    if err != nil {
+        panic("cryptobin/gcmsiv: " + err.Error())
+    }
+
+    tag, err := this.tag(block, authKey, nonce, plaintext, additionalData)
+    if err != nil {
+        panic("cryptobin/gcmsiv: " + err.Error())
+    }
+
+    ret, out := alias.SliceForAppend(dst, len(plaintext) + TagSize)
+
+    ctr(block, tag, out[:len(plaintext)], plaintext)
+    copy(out[len(plaintext):], tag)
+
+    return ret
+}
+
+func (this *gcmsiv) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+    if len(nonce) != NonceSize {
+        panic("cryptobin/gcmsiv: incorrect nonce length given to GCM-SIV")
+    }
+
+    if len(ciphertext) < TagSize ||
+        uint64(len(ciphertext)) > maxPlaintextSize + TagSize ||
+        uint64(len(additionalData)) > maxAdditionalSize {
+        return nil, ErrOpen
+    }
+
+    authKey, block, err := this.deriveKeys(nonce)
+    if err != nil {
+        return nil, err
+    }
+
+    tag := ciphertext[len(ciphertext)-TagSize:]
+    ciphertext = ciphertext[:len(ciphertext)-TagSize]
+
+    ret, out := alias.SliceForAppend(dst, len(ciphertext))
+
+    ctr(block, tag, out, ciphertext)
+
+    expectedTag, err := this.tag(block, authKey, nonce, out, additionalData)
+    if err != nil {
+        return nil, err
+    }
+
+    if subtle.ConstantTimeCompare(expectedTag, tag) != 1 {
+        for i := range out {
+            out[i] = 0
+        }
+
+        return nil, ErrOpen
+    }
+
+    return ret, nil
+}
+
+// 派生认证密钥及加密密钥
+func (this *gcmsiv) deriveKeys(nonce []byte) ([]byte, cipher.Block, error) {
+    var in, out [BlockSize]byte
+    copy(in[4:], nonce)
+
+    keys := make([]byte, 0, BlockSize + this.keySize)
+
+    count := (BlockSize + this.keySize) / 8
+    for i := 0; i < count; i++ {
+        binary.LittleEndian.PutUint32(in[:4], uint32(i))
+
+        this.block.Encrypt(out[:], in[:])
+        keys = append(keys, out[:8]...)
+    }
+
+    authKey := keys[:BlockSize]
+
+    block, err := this.newCipher(keys[BlockSize:])
+    if err != nil {
+        return nil, nil, err
+    }
+
+    if block.BlockSize() != BlockSize {
+        return nil, nil, ErrBlockSize
+    }
+
+    return authKey, block, nil
+}
+
+// 生成 tag
+func (this *gcmsiv) tag(block cipher.Block, authKey, nonce, plaintext, additionalData []byte) ([]byte, error) {
+    var lengthBlock [BlockSize]byte
+    binary.LittleEndian.PutUint64(lengthBlock[:8], uint64(len(additionalData)) * 8)
+    binary.LittleEndian.PutUint64(lengthBlock[8:], uint64(len(plaintext)) * 8)
+
+    p, err := polyval.New(authKey)
+    if err != nil {
+        return nil, err
+    }
+
+    p.UpdatePadded(additionalData)
+    p.UpdatePadded(plaintext)
+    p.Update(lengthBlock[:])
+
+    s := p.Sum(nil)
+    for i := 0; i < NonceSize; i++ {
+        s[i] ^= nonce[i]
+    }
+
+    s[15] &= 0x7f
+
+    tag := make([]byte, TagSize)
+    block.Encrypt(tag, s)
+
+    return tag, nil
+}
+
+// CTR 加密, 计数器为前 32 位小端序
+func ctr(block cipher.Block, tag, dst, src []byte) {
+    var counter, keystream [BlockSize]byte
+    copy(counter[:], tag)
+    counter[15] |= 0x80
+
+    for len(src) > 0 {
+        block.Encrypt(keystream[:], counter[:])
+
+        n := subtle.XORBytes(dst, src, keystream[:])
+        dst = dst[n:]
+        src = src[n:]
+
+        c := binary.LittleEndian.Uint32(counter[:4])
+        binary.LittleEndian.PutUint32(counter[:4], c + 1)
+    }
+}
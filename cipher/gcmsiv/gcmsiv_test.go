@@ -0,0 +1,205 @@
+package gcmsiv
+
+import (
+    "bytes"
+    "testing"
+    "crypto/aes"
+    "crypto/cipher"
+    "encoding/hex"
+
+    "github.com/deatil/go-cryptobin/cipher/sm4"
+    "github.com/deatil/go-cryptobin/cipher/aria"
+    "github.com/deatil/go-cryptobin/cipher/camellia"
+    "github.com/deatil/go-cryptobin/hash/polyval"
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+// RFC 8452 Appendix A
+func Test_Polyval(t *testing.T) {
+    h, _ := hex.DecodeString("25629347589242761d31f826ba4b757b")
+    x1, _ := hex.DecodeString("4f4f95668c83dfb6401762bb2d01a262")
+    x2, _ := hex.DecodeString("d1a24ddd2721d006bbe45f20d3c9f362")
+
+    p, err := polyval.New(h)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    p.UpdatePadded(x1)
+    p.UpdatePadded(x2)
+
+    sum := p.Sum(nil)
+
+    want := "f7a3b47b846119fae5b7866cf5e5b77e"
+    if hex.EncodeToString(sum) != want {
+        t.Errorf("POLYVAL got %x, want %s", sum, want)
+    }
+}
+
+func Test_GCMSIVs(t *testing.T) {
+    test_GCMSIV(t, aes.NewCipher, "AES")
+    test_GCMSIV(t, sm4.NewCipher, "SM4")
+    test_GCMSIV(t, aria.NewCipher, "Aria")
+    test_GCMSIV(t, camellia.NewCipher, "Camellia")
+}
+
+func test_GCMSIV(t *testing.T, fn func([]byte) (cipher.Block, error), name string) {
+    t.Run(name, func(t *testing.T) {
+        assertEqual := cryptobin_test.AssertEqualT(t)
+        assertError := cryptobin_test.AssertErrorT(t)
+        assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+        key := []byte("kkinjkijeel22plo")
+        nonce := []byte("kkinjkijeel2")
+        additional := []byte("additional")
+
+        aead, err := NewGCMSIV(key, fn)
+        assertError(err, "NewGCMSIV")
+
+        for _, size := range []int{0, 1, 15, 16, 17, 100} {
+            plaintext := bytes.Repeat([]byte{'a'}, size)
+
+            out := aead.Seal(nil, nonce, plaintext, additional)
+
+            res, err := aead.Open(nil, nonce, out, additional)
+            assertError(err, "Open")
+            assertEqual(bytes.Equal(res, plaintext), true, "Open-Equal")
+
+            _, err = aead.Open(nil, nonce, out, nil)
+            assertNotErrorNil(err, "Open-Additional")
+        }
+    })
+}
+
+// RFC 8452 Appendix C
+func Test_RFC8452(t *testing.T) {
+    check := func(t *testing.T, key, nonce, plaintext, additional, result string) {
+        k, _ := hex.DecodeString(key)
+        n, _ := hex.DecodeString(nonce)
+        pt, _ := hex.DecodeString(plaintext)
+        ad, _ := hex.DecodeString(additional)
+
+        aead, err := NewGCMSIV(k, aes.NewCipher)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        out := aead.Seal(nil, n, pt, ad)
+        if hex.EncodeToString(out) != result {
+            t.Errorf("Seal got %x, want %s", out, result)
+        }
+
+        res, err := aead.Open(nil, n, out, ad)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if !bytes.Equal(res, pt) {
+            t.Errorf("Open got %x, want %s", res, plaintext)
+        }
+    }
+
+    // C.1. AEAD_AES_128_GCM_SIV
+    t.Run("C.1-1", func(t *testing.T) {
+        check(t,
+            "01000000000000000000000000000000",
+            "030000000000000000000000",
+            "",
+            "",
+            "dc20e2d83f25705bb49e439eca56de25",
+        )
+    })
+
+    t.Run("C.1-2", func(t *testing.T) {
+        check(t,
+            "01000000000000000000000000000000",
+            "030000000000000000000000",
+            "0100000000000000",
+            "",
+            "b5d839330ac7b786578782fff6013b815b287c22493a364c",
+        )
+    })
+
+    t.Run("C.1-3", func(t *testing.T) {
+        check(t,
+            "01000000000000000000000000000000",
+            "030000000000000000000000",
+            "010000000000000000000000",
+            "",
+            "7323ea61d05932260047d942a4978db357391a0bc4fdec8b0d106639",
+        )
+    })
+
+    t.Run("C.1-4", func(t *testing.T) {
+        check(t,
+            "01000000000000000000000000000000",
+            "030000000000000000000000",
+            "01000000000000000000000000000000",
+            "",
+            "743f7c8077ab25f8624e2e948579cf77303aaf90f6fe21199c6068577437a0c4",
+        )
+    })
+
+    t.Run("C.1-5", func(t *testing.T) {
+        check(t,
+            "01000000000000000000000000000000",
+            "030000000000000000000000",
+            "0200000000000000",
+            "01",
+            "1e6daba35669f4273b0a1a2560969cdf790d99759abd1508",
+        )
+    })
+
+    // C.2. AEAD_AES_256_GCM_SIV
+    t.Run("C.2-1", func(t *testing.T) {
+        check(t,
+            "0100000000000000000000000000000000000000000000000000000000000000",
+            "030000000000000000000000",
+            "",
+            "",
+            "07f5f4169bbf55a8400cd47ea6fd400f",
+        )
+    })
+
+    t.Run("C.2-2", func(t *testing.T) {
+        check(t,
+            "0100000000000000000000000000000000000000000000000000000000000000",
+            "030000000000000000000000",
+            "0100000000000000",
+            "",
+            "c2ef328e5c71c83b843122130f7364b761e0b97427e3df28",
+        )
+    })
+
+    t.Run("C.2-3", func(t *testing.T) {
+        check(t,
+            "0100000000000000000000000000000000000000000000000000000000000000",
+            "030000000000000000000000",
+            "010000000000000000000000",
+            "",
+            "9aab2aeb3faa0a34aea8e2b18ca50da9ae6559e48fd10f6e5c9ca17e",
+        )
+    })
+}
+
+func Test_OpenTampered(t *testing.T) {
+    aead, _ := NewGCMSIV([]byte("kkinjkijeel22plo"), aes.NewCipher)
+    nonce := []byte("kkinjkijeel2")
+
+    out := aead.Seal(nil, nonce, []byte("kjinjkijkolkdplo"), nil)
+
+    out[0] ^= 1
+    if _, err := aead.Open(nil, nonce, out, nil); err != ErrOpen {
+        t.Errorf("Open got %v, want ErrOpen", err)
+    }
+
+    if _, err := aead.Open(nil, nonce, out[:TagSize-1], nil); err != ErrOpen {
+        t.Errorf("Open short got %v, want ErrOpen", err)
+    }
+}
+
+func Test_KeySize(t *testing.T) {
+    if _, err := NewGCMSIV([]byte("kkinjkijeel22plokkinjkij"), aes.NewCipher); err != ErrKeySize {
+        t.Errorf("NewGCMSIV got %v, want ErrKeySize", err)
+    }
+}
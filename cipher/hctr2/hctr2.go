@@ -0,0 +1,184 @@
+package hctr2
+
+import (
+    "errors"
+    "crypto/cipher"
+    "crypto/subtle"
+    "encoding/binary"
+
+    "github.com/deatil/go-cryptobin/hash/polyval"
+    "github.com/deatil/go-cryptobin/tool/alias"
+)
+
+/**
+ * HCTR2 宽分组模式
+ * Length-preserving encryption with HCTR2 (Crowley, Huckleberry, Biggers)
+ *
+ * HCTR 的改进, 使用分组加密 1 个分组, 其余数据使用 XCTR 加密,
+ * 哈希使用 POLYVAL, 修改任意一位会影响全部密文
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+
+const (
+    // 分组长度
+    BlockSize = 16
+)
+
+var (
+    ErrBlockSize = errors.New("cryptobin/hctr2: block size must be 16 bytes")
+    ErrDataSize  = errors.New("cryptobin/hctr2: data size must be at least 16 bytes")
+)
+
+type HCTR2 struct {
+    block cipher.Block
+    h     []byte
+    l     [BlockSize]byte
+}
+
+// 使用分组生成 HCTR2
+func NewHCTR2(block cipher.Block) (*HCTR2, error) {
+    if block.BlockSize() != BlockSize {
+        return nil, ErrBlockSize
+    }
+
+    // h = E(bin(0)), L = E(bin(1))
+    var in [BlockSize]byte
+
+    h := make([]byte, BlockSize)
+    block.Encrypt(h, in[:])
+
+    c := &HCTR2{
+        block: block,
+        h:     h,
+    }
+
+    in[0] = 1
+    block.Encrypt(c.l[:], in[:])
+
+    return c, nil
+}
+
+// 加密, 数据长度不小于 16 字节
+func (this *HCTR2) Encrypt(dst, src, tweak []byte) error {
+    if len(src) < BlockSize {
+        return ErrDataSize
+    }
+
+    if len(dst) < len(src) {
+        panic("cryptobin/hctr2: output smaller than input")
+    }
+
+    if alias.InexactOverlap(dst[:len(src)], src) {
+        panic("cryptobin/hctr2: invalid buffer overlap")
+    }
+
+    m, n := src[:BlockSize], src[BlockSize:]
+
+    // UU = M ^ H(T, N)
+    var uu, uu2, s [BlockSize]byte
+    subtle.XORBytes(uu[:], m, this.hash(tweak, n))
+
+    // UU' = E(UU)
+    this.block.Encrypt(uu2[:], uu[:])
+
+    // S = UU ^ UU' ^ L
+    subtle.XORBytes(s[:], uu[:], uu2[:])
+    subtle.XORBytes(s[:], s[:], this.l[:])
+
+    // V = N ^ XCTR(S)
+    v := dst[BlockSize:len(src)]
+    this.xctr(v, n, s)
+
+    // U = UU' ^ H(T, V)
+    subtle.XORBytes(dst[:BlockSize], uu2[:], this.hash(tweak, v))
+
+    return nil
+}
+
+// 解密
+func (this *HCTR2) Decrypt(dst, src, tweak []byte) error {
+    if len(src) < BlockSize {
+        return ErrDataSize
+    }
+
+    if len(dst) < len(src) {
+        panic("cryptobin/hctr2: output smaller than input")
+    }
+
+    if alias.InexactOverlap(dst[:len(src)], src) {
+        panic("cryptobin/hctr2: invalid buffer overlap")
+    }
+
+    u, v := src[:BlockSize], src[BlockSize:]
+
+    // UU' = U ^ H(T, V)
+    var uu, uu2, s [BlockSize]byte
+    subtle.XORBytes(uu2[:], u, this.hash(tweak, v))
+
+    // UU = D(UU')
+    this.block.Decrypt(uu[:], uu2[:])
+
+    // S = UU ^ UU' ^ L
+    subtle.XORBytes(s[:], uu[:], uu2[:])
+    subtle.XORBytes(s[:], s[:], this.l[:])
+
+    // N = V ^ XCTR(S)
+    n := dst[BlockSize:len(src)]
+    this.xctr(n, v, s)
+
+    // M = UU ^ H(T, N)
+    subtle.XORBytes(dst[:BlockSize], uu[:], this.hash(tweak, n))
+
+    return nil
+}
+
+// H(T, M) = POLYVAL(h, bin(2|T| + 2) || pad(T) || M), |M| 为 16 的倍数
+// H(T, M) = POLYVAL(h, bin(2|T| + 3) || pad(T) || pad(M || 1)), 其他情况
+func (this *HCTR2) hash(tweak, msg []byte) []byte {
+    var lengthBlock [BlockSize]byte
+
+    tweakLen := uint64(len(tweak)) * 8 * 2 + 2
+    if len(msg) % BlockSize != 0 {
+        tweakLen++
+    }
+
+    binary.LittleEndian.PutUint64(lengthBlock[:8], tweakLen)
+
+    p, _ := polyval.New(this.h)
+    p.Update(lengthBlock[:])
+    p.UpdatePadded(tweak)
+
+    full := len(msg) - len(msg) % BlockSize
+    p.Update(msg[:full])
+
+    if full < len(msg) {
+        var last [BlockSize]byte
+        n := copy(last[:], msg[full:])
+        last[n] = 1
+
+        p.Update(last[:])
+    }
+
+    return p.Sum(nil)
+}
+
+// XCTR, 密钥流为 E(S ^ bin(i)), i 从 1 开始
+func (this *HCTR2) xctr(dst, src []byte, s [BlockSize]byte) {
+    var ctr, keystream [BlockSize]byte
+
+    lo := binary.LittleEndian.Uint64(s[:8])
+    hi := binary.LittleEndian.Uint64(s[8:])
+
+    for i := uint64(1); len(src) > 0; i++ {
+        binary.LittleEndian.PutUint64(ctr[:8], lo ^ i)
+        binary.LittleEndian.PutUint64(ctr[8:], hi)
+
+        this.block.Encrypt(keystream[:], ctr[:])
+
+        n := subtle.XORBytes(dst, src, keystream[:])
+        dst = dst[n:]
+        src = src[n:]
+    }
+}
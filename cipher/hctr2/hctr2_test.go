@@ -0,0 +1,274 @@
+package hctr2
+
+import (
+    "bytes"
+    "testing"
+    "crypto/aes"
+    "crypto/des"
+    "crypto/cipher"
+    "encoding/hex"
+
+    "github.com/deatil/go-cryptobin/cipher/sm4"
+    "github.com/deatil/go-cryptobin/cipher/camellia"
+    "github.com/deatil/go-cryptobin/cipher/serpent"
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+func Test_HCTR2s(t *testing.T) {
+    test_HCTR2(t, aes.NewCipher, "AES")
+    test_HCTR2(t, sm4.NewCipher, "SM4")
+    test_HCTR2(t, camellia.NewCipher, "Camellia")
+    test_HCTR2(t, serpent.NewCipher, "Serpent")
+}
+
+func test_HCTR2(t *testing.T, fn func([]byte) (cipher.Block, error), name string) {
+    t.Run(name, func(t *testing.T) {
+        assertEqual := cryptobin_test.AssertEqualT(t)
+        assertError := cryptobin_test.AssertErrorT(t)
+
+        block, err := fn([]byte("kkinjkijeel22plo"))
+        assertError(err, "NewCipher")
+
+        c, err := NewHCTR2(block)
+        assertError(err, "NewHCTR2")
+
+        for _, tweak := range [][]byte{nil, []byte("tweak"), bytes.Repeat([]byte{'t'}, 32)} {
+            for _, size := range []int{16, 17, 31, 32, 33, 100, 512} {
+                plaintext := make([]byte, size)
+                for i := range plaintext {
+                    plaintext[i] = byte(i)
+                }
+
+                out := make([]byte, size)
+                err := c.Encrypt(out, plaintext, tweak)
+                assertError(err, "Encrypt")
+
+                res := make([]byte, size)
+                err = c.Decrypt(res, out, tweak)
+                assertError(err, "Decrypt")
+                assertEqual(res, plaintext, "Decrypt")
+
+                buf := append([]byte(nil), plaintext...)
+                c.Encrypt(buf, buf, tweak)
+                assertEqual(buf, out, "Encrypt-InPlace")
+
+                c.Decrypt(buf, buf, tweak)
+                assertEqual(buf, plaintext, "Decrypt-InPlace")
+            }
+        }
+    })
+}
+
+// AES-HCTR2 测试向量, 未能取得论文或 Linux testmgr 的官方向量,
+// 期望值由按论文定义独立实现的 Python 版本 HCTR2 生成,
+// 其中 POLYVAL 使用 RFC 8452 Appendix A 的数据验证.
+// 用例名称为 算法-tweak 长度-数据长度
+func Test_AESVectors(t *testing.T) {
+    check := func(t *testing.T, key, tweak, plaintext, ciphertext string) {
+        k, _ := hex.DecodeString(key)
+        tw, _ := hex.DecodeString(tweak)
+        pt, _ := hex.DecodeString(plaintext)
+
+        block, err := aes.NewCipher(k)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        c, err := NewHCTR2(block)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        out := make([]byte, len(pt))
+        if err := c.Encrypt(out, pt, tw); err != nil {
+            t.Fatal(err)
+        }
+
+        if hex.EncodeToString(out) != ciphertext {
+            t.Errorf("Encrypt got %x, want %s", out, ciphertext)
+        }
+
+        res := make([]byte, len(out))
+        if err := c.Decrypt(res, out, tw); err != nil {
+            t.Fatal(err)
+        }
+
+        if !bytes.Equal(res, pt) {
+            t.Errorf("Decrypt got %x, want %s", res, plaintext)
+        }
+    }
+
+    t.Run("AES-128-0-16", func(t *testing.T) {
+        check(t,
+            "2b0a78201e9ce4b9c1918333b260ec00",
+            "",
+            "9265ac9d1a982f161cf8c9f84361f7be",
+            "353c4c1ad8a7a5fa7d21aa30d743dc26",
+        )
+    })
+
+    t.Run("AES-128-32-16", func(t *testing.T) {
+        check(t,
+            "0079ceedc8a22cb4dd0c8bada9ab9aae",
+            "d1e58ca67ce92fed90e70d4215bfb9655deda6e41a74dac35e76a7f41e63fe90",
+            "9536c9343f239e4f4f593439832c1fbb",
+            "4d97c8a643b3acb22ba5f31cd2665246",
+        )
+    })
+
+    t.Run("AES-128-32-17", func(t *testing.T) {
+        check(t,
+            "c025ec98ac2c7adf1fd1a6dc97261f67",
+            "941c928dd5bbae51370494e55d5bae29c792a5179d2a3391a65cbda4ecdfe745",
+            "c18843fd0c5db87b7401d022678211569c",
+            "ff9ac4f033361fa1367c1e9b269cc6798c",
+        )
+    })
+
+    t.Run("AES-128-32-32", func(t *testing.T) {
+        check(t,
+            "042670cf6202b5ccb298e02d16f8e634",
+            "229aba1138f0c423bacfb8fbafa840b30810a4286afc33b866cb1f08ed78ca8f",
+            "2df0f239c46abb1e4115f528fe9c248ce80b392afd8676f0e588564bdc1be991",
+            "2f3ebf7a540e568d23b16df38a82bc660aa540bb76b1d9f432dedce192b7ffef",
+        )
+    })
+
+    t.Run("AES-128-0-47", func(t *testing.T) {
+        check(t,
+            "95cf19c83a875bb4ae6ca5d78ab22ad3",
+            "",
+            "de95ae14ffec571ecfc26e100d42cd8b6647bd28ee950cda41baf3448c88cb11555ebae1f1f2bb4dcbf6b297b81a31",
+            "3e5e38d4a9431c3f70358bf9dc1b4159545b8595a2e36218e9a93b616612d07dad86eac4972ec072cadb4da19b67f1",
+        )
+    })
+
+    t.Run("AES-128-32-64", func(t *testing.T) {
+        check(t,
+            "a9f1871c6c453c1fa4d70d44ef17f81d",
+            "37c3ea4175ff23fee8b4b2a3ff061edd3ae77ed7ee4dd0cb7be9f7c8fdf03b10",
+            "057507e8a5742116586a8723c46ef91a030660c766e997dcdabe8640d1c3d87884d6f4d834eb386da92c765db7f67e04c67814a7b999210cc09aed56ebbc5a74",
+            "31d263bac394d60bd241fe02a2963a037a0a9ee8ec2452e9df41714f83d11474234e93a3fd237c8f0b37ec547e62fb0c4dab105cbb349614e4e4d83640073bc0",
+        )
+    })
+
+    t.Run("AES-128-16-255", func(t *testing.T) {
+        check(t,
+            "448d5c9a6e0cb595b7f9bb7a4039f14a",
+            "6952529ae34ed19e457fab0000ce8c88",
+            "8aeb5ee2af4a671394e9563ec3227aed5c882c1d3b0342a4eaaa522128bed130f8b992987fd490ce0dacdb4994ba8f5ec74c52a763cb1b9b4450220061cd3ca5aee8af6bd74a38dfa74cea750e83933f52ee858f08a87ee5c49c2bb9f3ddd87b6f84b545476231b403c296f38caabfdb63927b09e00707e883df6cf9ea5d67feb2e87227b9a266f7a6bddd068d595f5d2410765328edf3e174555450885777e074355c6efab5a7097f2d22d1877adfe8b3b25f62af0c0367e71e253a2b9c0bbfd1c7ffd752c11c8759c9a00a9b83546a730c3d7cc21609e223a575ea7f98b3620bd4dc82c7d73ca877112adce340b98c72132fe8b9ea2b4f384891cfcbe1ec",
+            "3d587c8eb5d96e53de85e65f27128ec2f5a3e6ab532907d808659997fa04bcea5f891af46e72b44e19a6a3f124bf7a4fd26b065cebaea882fd6e7191520fdeeb4333c719ff066ff67bf4f494abbb275056c666ff79c1c549c80ae5ad92a3a6d5eb72c92881d25c0e5f3c924bcad1e119cade3fb5b2f64abae0304c3b6d35bcaba92c13d0939ddb62ed3827ab27a73331401e9a963ef7a3a6286efd426d6314d2451052b54916d7057823a4ba9f9145994573c70699ec99924834da6ad4d3868a26dbc1a43b72e0edbc0f145ab5983434fda6f8225236bcf4169bb1a9585e7a74fb50e47f31f5e48e2780b4d4af7e7bf40e67aa9e88c8edfe6bea16e141096b",
+        )
+    })
+
+    t.Run("AES-256-0-16", func(t *testing.T) {
+        check(t,
+            "2aa402acba94a79696965b91461533c8b34bc051bd6e448c63edb9b969369386",
+            "",
+            "f213ca301a79d50fab760607eeec2f21",
+            "534401d71afebb19fd9178f5d3e2c59d",
+        )
+    })
+
+    t.Run("AES-256-32-16", func(t *testing.T) {
+        check(t,
+            "d1f2255ef9fee5b9e383bf52c9ec588665ca4850bb4ef4e86bd41d1fa2e8fb7b",
+            "edc80e82e4d3b1a859177a4ed5b4f64f57be884f20566f3519a3a20eafe2e335",
+            "bf25f658ce7d8b4e471e1590a4069621",
+            "93ba31f4bad4c4192a98aba2b8ed7486",
+        )
+    })
+
+    t.Run("AES-256-32-17", func(t *testing.T) {
+        check(t,
+            "f82502a4a70e4b34d0fb2fa782f0b0b9c9c1dab6e3d573ba30f45287610241b7",
+            "b336012cd14f4a6066ccaeb205f2fd64aefa769753fe7abd441a23084a2bb930",
+            "736bcd148ad0c3be842042a69bfa5d4270",
+            "bd9201a592cd73e5eff8cb0a2e032ea995",
+        )
+    })
+
+    t.Run("AES-256-32-32", func(t *testing.T) {
+        check(t,
+            "d1d413d61e93019b0cc6cf91b9042d8e330bc3afc81b9315831de1f68fe38011",
+            "ed67b82f190ab8679d4638675339bee57cb9fdef6481c95ae9982c36a5f2d6e0",
+            "d47df805be8f9668917ac0df4060b8f7b8ee01780db0b9a5d56d5d898129dc8d",
+            "8edf2ea6a9533ecbe12bdaf64c48d486973da1e38641113e150681ac822a3553",
+        )
+    })
+
+    t.Run("AES-256-0-47", func(t *testing.T) {
+        check(t,
+            "35e9e1e4a5694c7b84d98f9a66d0882d61f6058990550ef35a0f30c40cb404b7",
+            "",
+            "697e3c72202a0a49d42b7e4e2bf57b54cf5f594beaf5c1dc2e3ce7d52004a63ed8409eada0595077bccb35edcff5d1",
+            "00e00d913c47a50027fe04b835c541cebd3f7feb5e9742c2beb047bb44389acb044c2bdf6c9ef12e80dfd22501d7c7",
+        )
+    })
+
+    t.Run("AES-256-32-64", func(t *testing.T) {
+        check(t,
+            "db83c1b0f6be9e7aa271f99680347258c5a8bd4cc0866b6ba14fcecd077a4fbc",
+            "0c923735741fa964c9bab12a6958f5653fefdfe6f912c609fbbfa47bfebb9390",
+            "c4a9bb79d255a912b5146a9556377a0008e4bd27772d9b2661f9cd3e2daaf00bd897334a178eb279a81cb71b76c4c41b70f7bbe3099fb4ac51bb4dce59470ad6",
+            "62d9fa729f75466ece576a9f971e16bfad272f1d0333458df7847fa97336800755dc7b27d36c838f8b91181c98647a3fbecb61e3eb2282a56f6a6a4e946a824d",
+        )
+    })
+
+    t.Run("AES-256-16-255", func(t *testing.T) {
+        check(t,
+            "278bea3f7a3743cbadef781f2fef2d415fec15a2a497dc466a1e1b256fc67898",
+            "9eebce08542eb7c8e0b491aa4860e8e8",
+            "c100de094d5701ab2627cb5b4bb9fee87176ea3846f1fa3b69b881c01c1073f13d8819dd6b3d4c81a8f9a6b6c57ba2a2a01d1e6c18ea00f0a1bf419535392f1b7438ce9664a80bfd6fb0cad8e5e86e01ec68b725f5f5582c07856a68ce505ee6b538eeb137a88f401006bbaffb10e856a7c1d9b971a38121d901ab845509a62fc3ce90272e6c50d50adb7de8bd53db757511958b137580076c3be5eef68190ba983b8ad281be4a69810b31a37f347946debad123e597364d0bd482147d520e72aef92792c25c93be73a1d22205fe8aaaa00e225972180157c1b3428340fe88e58b99770b074db29e9786ca7ded6f76f6b2fc2b28fbb8399d76a5dc6d1fe4b2",
+            "3b4c3f0170b5f3884093aab6a762689fb5f57127d47618aa772f3721353c12400407eb9bf32fc2d903188b1cdc4e1e60491310273a89de5c0a170fbfc9af5fbeef20633efdce81c1ad4dd38bf1a5e7dfaa5cbf351d346a61c1a9528d2b708f10f91eb3a91bc381e3a2152448c607bab3b57b4a0d58046bea62ff2d99c6259655be0ad910da2e28e84bd041b045e049e65db7b1058fbe26ce4bade297b6b00fb8db95544eb1686122d2b82567e113ca4def4b1874879c0de71feee4ae8145e73ab51f35da21a915c92797956e8703482fa0b3109595c6daaf8cf11ab89415e5cc251d26827cffd409345e4e9b074ecb51a2cefc9ac6f0b37f8dde3edcc4a535",
+        )
+    })
+}
+
+func Test_WideBlock(t *testing.T) {
+    block, _ := aes.NewCipher([]byte("kkinjkijeel22plo"))
+    c, _ := NewHCTR2(block)
+
+    tweak := []byte("tweak")
+
+    plaintext := make([]byte, 64)
+    out1 := make([]byte, 64)
+    c.Encrypt(out1, plaintext, tweak)
+
+    // 修改最后一个字节, 全部分组都会改变
+    plaintext[63] ^= 1
+    out2 := make([]byte, 64)
+    c.Encrypt(out2, plaintext, tweak)
+
+    for i := 0; i < 64; i += BlockSize {
+        if bytes.Equal(out1[i:i+BlockSize], out2[i:i+BlockSize]) {
+            t.Errorf("block %d not changed", i/BlockSize)
+        }
+    }
+
+    // 修改 tweak
+    plaintext[63] ^= 1
+    out3 := make([]byte, 64)
+    c.Encrypt(out3, plaintext, []byte("tweal"))
+
+    if bytes.Equal(out1[:BlockSize], out3[:BlockSize]) {
+        t.Error("tweak not used")
+    }
+}
+
+func Test_BlockSize(t *testing.T) {
+    block, _ := des.NewCipher([]byte("12345678"))
+
+    if _, err := NewHCTR2(block); err != ErrBlockSize {
+        t.Errorf("NewHCTR2 got %v, want ErrBlockSize", err)
+    }
+}
+
+func Test_DataSize(t *testing.T) {
+    block, _ := aes.NewCipher(make([]byte, 16))
+    c, _ := NewHCTR2(block)
+
+    if err := c.Encrypt(make([]byte, 15), make([]byte, 15), nil); err != ErrDataSize {
+        t.Errorf("Encrypt got %v, want ErrDataSize", err)
+    }
+}
@@ -0,0 +1,244 @@
+package keywrap
+
+import (
+    "errors"
+    "crypto/cipher"
+    "crypto/subtle"
+    "encoding/binary"
+)
+
+/**
+ * 密钥包装 KW (RFC 3394) 及带填充的密钥包装 KWP (RFC 5649)
+ *
+ * 可用于 16 字节分组的加密算法, 使用 SM4 时即为 GB/T 36624 中的密钥包装
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+
+const (
+    // 分组长度
+    BlockSize = 16
+
+    // 半块长度
+    semiblockSize = 8
+)
+
+var (
+    // RFC 3394 默认 IV
+    DefaultIV = []byte{0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6}
+
+    // RFC 5649 默认 AIV 前缀
+    DefaultPadIV = []byte{0xA6, 0x59, 0x59, 0xA6}
+)
+
+var (
+    ErrBlockSize     = errors.New("cryptobin/keywrap: block size must be 16 bytes")
+    ErrIVSize        = errors.New("cryptobin/keywrap: invalid iv size")
+    ErrPlaintextSize = errors.New("cryptobin/keywrap: invalid plaintext size")
+    ErrWrappedSize   = errors.New("cryptobin/keywrap: invalid wrapped key size")
+    ErrUnwrap        = errors.New("cryptobin/keywrap: integrity check failed")
+)
+
+// KW 包装密钥, iv 为空时使用默认 IV
+// 明文长度需为 8 的倍数且不小于 16 字节
+func Wrap(block cipher.Block, iv, plaintext []byte) ([]byte, error) {
+    if block.BlockSize() != BlockSize {
+        return nil, ErrBlockSize
+    }
+
+    if iv == nil {
+        iv = DefaultIV
+    }
+
+    if len(iv) != semiblockSize {
+        return nil, ErrIVSize
+    }
+
+    if len(plaintext) < 2*semiblockSize || len(plaintext)%semiblockSize != 0 {
+        return nil, ErrPlaintextSize
+    }
+
+    return wrap(block, iv, plaintext), nil
+}
+
+// KW 解包装密钥, iv 为空时使用默认 IV
+func Unwrap(block cipher.Block, iv, ciphertext []byte) ([]byte, error) {
+    if block.BlockSize() != BlockSize {
+        return nil, ErrBlockSize
+    }
+
+    if iv == nil {
+        iv = DefaultIV
+    }
+
+    if len(iv) != semiblockSize {
+        return nil, ErrIVSize
+    }
+
+    if len(ciphertext) < 3*semiblockSize || len(ciphertext)%semiblockSize != 0 {
+        return nil, ErrWrappedSize
+    }
+
+    a, out := unwrap(block, ciphertext)
+
+    if subtle.ConstantTimeCompare(a, iv) != 1 {
+        return nil, ErrUnwrap
+    }
+
+    return out, nil
+}
+
+// KWP 包装密钥, iv 为空时使用默认 AIV 前缀
+// 明文长度为 1 到 2^32-1 字节
+func WrapPad(block cipher.Block, iv, plaintext []byte) ([]byte, error) {
+    if block.BlockSize() != BlockSize {
+        return nil, ErrBlockSize
+    }
+
+    if iv == nil {
+        iv = DefaultPadIV
+    }
+
+    if len(iv) != 4 {
+        return nil, ErrIVSize
+    }
+
+    if len(plaintext) == 0 || uint64(len(plaintext)) > 0xFFFFFFFF {
+        return nil, ErrPlaintextSize
+    }
+
+    aiv := make([]byte, semiblockSize)
+    copy(aiv, iv)
+    binary.BigEndian.PutUint32(aiv[4:], uint32(len(plaintext)))
+
+    padLen := (semiblockSize - len(plaintext)%semiblockSize) % semiblockSize
+
+    padded := make([]byte, len(plaintext) + padLen)
+    copy(padded, plaintext)
+
+    // 只有一个半块时直接加密
+    if len(padded) == semiblockSize {
+        out := make([]byte, BlockSize)
+        copy(out, aiv)
+        copy(out[semiblockSize:], padded)
+
+        block.Encrypt(out, out)
+
+        return out, nil
+    }
+
+    return wrap(block, aiv, padded), nil
+}
+
+// KWP 解包装密钥, iv 为空时使用默认 AIV 前缀
+func UnwrapPad(block cipher.Block, iv, ciphertext []byte) ([]byte, error) {
+    if block.BlockSize() != BlockSize {
+        return nil, ErrBlockSize
+    }
+
+    if iv == nil {
+        iv = DefaultPadIV
+    }
+
+    if len(iv) != 4 {
+        return nil, ErrIVSize
+    }
+
+    if len(ciphertext) < 2*semiblockSize || len(ciphertext)%semiblockSize != 0 {
+        return nil, ErrWrappedSize
+    }
+
+    var a, padded []byte
+    if len(ciphertext) == 2*semiblockSize {
+        out := make([]byte, BlockSize)
+        block.Decrypt(out, ciphertext)
+
+        a, padded = out[:semiblockSize], out[semiblockSize:]
+    } else {
+        a, padded = unwrap(block, ciphertext)
+    }
+
+    // 检测 AIV, 长度及填充
+    ok := subtle.ConstantTimeCompare(a[:4], iv)
+
+    mli := binary.BigEndian.Uint32(a[4:])
+    n := len(padded)
+
+    if uint64(mli) <= uint64(n - semiblockSize) || uint64(mli) > uint64(n) {
+        return nil, ErrUnwrap
+    }
+
+    var pad byte
+    for _, b := range padded[mli:] {
+        pad |= b
+    }
+
+    ok &= subtle.ConstantTimeByteEq(pad, 0)
+    if ok != 1 {
+        return nil, ErrUnwrap
+    }
+
+    return padded[:mli], nil
+}
+
+// W 函数
+func wrap(block cipher.Block, iv, plaintext []byte) []byte {
+    n := len(plaintext) / semiblockSize
+
+    out := make([]byte, semiblockSize + len(plaintext))
+    copy(out[semiblockSize:], plaintext)
+
+    var b [BlockSize]byte
+    copy(b[:semiblockSize], iv)
+
+    for j := 0; j < 6; j++ {
+        for i := 1; i <= n; i++ {
+            r := out[i*semiblockSize:(i+1)*semiblockSize]
+
+            copy(b[semiblockSize:], r)
+            block.Encrypt(b[:], b[:])
+
+            t := uint64(n*j + i)
+            a := binary.BigEndian.Uint64(b[:semiblockSize]) ^ t
+            binary.BigEndian.PutUint64(b[:semiblockSize], a)
+
+            copy(r, b[semiblockSize:])
+        }
+    }
+
+    copy(out[:semiblockSize], b[:semiblockSize])
+
+    return out
+}
+
+// W^-1 函数, 返回 A 及解包装数据
+func unwrap(block cipher.Block, ciphertext []byte) ([]byte, []byte) {
+    n := len(ciphertext) / semiblockSize - 1
+
+    out := make([]byte, len(ciphertext) - semiblockSize)
+    copy(out, ciphertext[semiblockSize:])
+
+    var b [BlockSize]byte
+    copy(b[:semiblockSize], ciphertext[:semiblockSize])
+
+    for j := 5; j >= 0; j-- {
+        for i := n; i >= 1; i-- {
+            r := out[(i-1)*semiblockSize:i*semiblockSize]
+
+            t := uint64(n*j + i)
+            a := binary.BigEndian.Uint64(b[:semiblockSize]) ^ t
+            binary.BigEndian.PutUint64(b[:semiblockSize], a)
+
+            copy(b[semiblockSize:], r)
+            block.Decrypt(b[:], b[:])
+
+            copy(r, b[semiblockSize:])
+        }
+    }
+
+    a := make([]byte, semiblockSize)
+    copy(a, b[:semiblockSize])
+
+    return a, out
+}
@@ -0,0 +1,293 @@
+package keywrap
+
+import (
+    "bytes"
+    "testing"
+    "crypto/aes"
+    "crypto/cipher"
+    "encoding/hex"
+
+    "github.com/deatil/go-cryptobin/cipher/sm4"
+    "github.com/deatil/go-cryptobin/cipher/aria"
+    "github.com/deatil/go-cryptobin/cipher/camellia"
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+func Test_KeyWraps(t *testing.T) {
+    test_KeyWrap(t, sm4.NewCipher, "SM4")
+    test_KeyWrap(t, aria.NewCipher, "Aria")
+    test_KeyWrap(t, camellia.NewCipher, "Camellia")
+}
+
+func test_KeyWrap(t *testing.T, fn func([]byte) (cipher.Block, error), name string) {
+    t.Run(name, func(t *testing.T) {
+        assertEqual := cryptobin_test.AssertEqualT(t)
+        assertError := cryptobin_test.AssertErrorT(t)
+        assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+        block, err := fn([]byte("kkinjkijeel22plo"))
+        assertError(err, "NewCipher")
+
+        for _, size := range []int{16, 24, 32, 64} {
+            key := bytes.Repeat([]byte{'k'}, size)
+
+            out, err := Wrap(block, nil, key)
+            assertError(err, "Wrap")
+
+            res, err := Unwrap(block, nil, out)
+            assertError(err, "Unwrap")
+            assertEqual(res, key, "Unwrap")
+
+            _, err = Unwrap(block, []byte("12345678"), out)
+            assertNotErrorNil(err, "Unwrap-IV")
+        }
+
+        for _, size := range []int{1, 7, 8, 9, 16, 20, 33} {
+            key := bytes.Repeat([]byte{'k'}, size)
+
+            out, err := WrapPad(block, nil, key)
+            assertError(err, "WrapPad")
+
+            res, err := UnwrapPad(block, nil, out)
+            assertError(err, "UnwrapPad")
+            assertEqual(res, key, "UnwrapPad")
+        }
+    })
+}
+
+// RFC 3394 Section 4
+func Test_RFC3394(t *testing.T) {
+    check := func(t *testing.T, kek, key, output string) {
+        k, _ := hex.DecodeString(kek)
+        p, _ := hex.DecodeString(key)
+        want, _ := hex.DecodeString(output)
+
+        block, err := aes.NewCipher(k)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        out, err := Wrap(block, nil, p)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if !bytes.Equal(out, want) {
+            t.Errorf("Wrap got %X, want %s", out, output)
+        }
+
+        res, err := Unwrap(block, nil, out)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if !bytes.Equal(res, p) {
+            t.Errorf("Unwrap got %X, want %s", res, key)
+        }
+
+        out[0] ^= 1
+        if _, err := Unwrap(block, nil, out); err != ErrUnwrap {
+            t.Errorf("Unwrap tampered got %v, want ErrUnwrap", err)
+        }
+    }
+
+    t.Run("4.1", func(t *testing.T) {
+        check(t,
+            "000102030405060708090A0B0C0D0E0F",
+            "00112233445566778899AABBCCDDEEFF",
+            "1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5",
+        )
+    })
+
+    t.Run("4.2", func(t *testing.T) {
+        check(t,
+            "000102030405060708090A0B0C0D0E0F1011121314151617",
+            "00112233445566778899AABBCCDDEEFF",
+            "96778B25AE6CA435F92B5B97C050AED2468AB8A17AD84E5D",
+        )
+    })
+
+    t.Run("4.3", func(t *testing.T) {
+        check(t,
+            "000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+            "00112233445566778899AABBCCDDEEFF",
+            "64E8C3F9CE0F5BA263E9777905818A2A93C8191E7D6E8AE7",
+        )
+    })
+
+    t.Run("4.4", func(t *testing.T) {
+        check(t,
+            "000102030405060708090A0B0C0D0E0F1011121314151617",
+            "00112233445566778899AABBCCDDEEFF0001020304050607",
+            "031D33264E15D33268F24EC260743EDCE1C6C7DDEE725A936BA814915C6762D2",
+        )
+    })
+
+    t.Run("4.5", func(t *testing.T) {
+        check(t,
+            "000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+            "00112233445566778899AABBCCDDEEFF0001020304050607",
+            "A8F9BC1612C68B3FF6E6F4FBE30E71E4769C8B80A32CB8958CD5D17D6B254DA1",
+        )
+    })
+
+    t.Run("4.6", func(t *testing.T) {
+        check(t,
+            "000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+            "00112233445566778899AABBCCDDEEFF000102030405060708090A0B0C0D0E0F",
+            "28C9F404C4B810F4CBCCB35CFB87F8263F5786E2D80ED326CBC7F0E71A99F43BFB988B9B7A02DD21",
+        )
+    })
+}
+
+// RFC 5649 Section 6
+func Test_RFC5649(t *testing.T) {
+    check := func(t *testing.T, kek, key, output string) {
+        k, _ := hex.DecodeString(kek)
+        p, _ := hex.DecodeString(key)
+        want, _ := hex.DecodeString(output)
+
+        block, err := aes.NewCipher(k)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        out, err := WrapPad(block, nil, p)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if !bytes.Equal(out, want) {
+            t.Errorf("WrapPad got %x, want %s", out, output)
+        }
+
+        res, err := UnwrapPad(block, nil, out)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if !bytes.Equal(res, p) {
+            t.Errorf("UnwrapPad got %x, want %s", res, key)
+        }
+
+        out[len(out)-1] ^= 1
+        if _, err := UnwrapPad(block, nil, out); err != ErrUnwrap {
+            t.Errorf("UnwrapPad tampered got %v, want ErrUnwrap", err)
+        }
+    }
+
+    t.Run("20-octets", func(t *testing.T) {
+        check(t,
+            "5840df6e29b02af1ab493b705bf16ea1ae8338f4dcc176a8",
+            "c37b7e6492584340bed12207808941155068f738",
+            "138bdeaa9b8fa7fc61f97742e72248ee5ae6ae5360d1ae6a5f54f373fa543b6a",
+        )
+    })
+
+    t.Run("7-octets", func(t *testing.T) {
+        check(t,
+            "5840df6e29b02af1ab493b705bf16ea1ae8338f4dcc176a8",
+            "466f7250617369",
+            "afbeb0f07dfbf5419200f2ccb50bb24f",
+        )
+    })
+}
+
+// SM4 密钥包装, 密钥为 GB/T 32907 示例密钥,
+// 期望值由独立的 RFC 3394 / RFC 5649 实现配合 OpenSSL 的 SM4 计算
+func Test_SM4(t *testing.T) {
+    kek, _ := hex.DecodeString("0123456789abcdeffedcba9876543210")
+
+    block, err := sm4.NewCipher(kek)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    t.Run("Wrap-16", func(t *testing.T) {
+        key, _ := hex.DecodeString("00112233445566778899aabbccddeeff")
+        want := "2f92140188bb01970a726046b111c5fa427ced34d73dcab8"
+
+        out, err := Wrap(block, nil, key)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if hex.EncodeToString(out) != want {
+            t.Errorf("Wrap got %x, want %s", out, want)
+        }
+    })
+
+    t.Run("Wrap-24", func(t *testing.T) {
+        key, _ := hex.DecodeString("00112233445566778899aabbccddeeff0001020304050607")
+        want := "5a825ad1efecd64d5f44dd8dbd9676a5195b0cb135cf50477fe5ce65f2d73e16"
+
+        out, err := Wrap(block, nil, key)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if hex.EncodeToString(out) != want {
+            t.Errorf("Wrap got %x, want %s", out, want)
+        }
+    })
+
+    t.Run("WrapPad-20", func(t *testing.T) {
+        key, _ := hex.DecodeString("c37b7e6492584340bed12207808941155068f738")
+        want := "134dfdd962bf2450d070aba893ea8af7b82a2316b6a34ceb9ab456a5bd5fac44"
+
+        out, err := WrapPad(block, nil, key)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if hex.EncodeToString(out) != want {
+            t.Errorf("WrapPad got %x, want %s", out, want)
+        }
+    })
+
+    t.Run("WrapPad-7", func(t *testing.T) {
+        key, _ := hex.DecodeString("466f7250617369")
+        want := "43e3b77a56dc8fe9cf577906ab0bfb1a"
+
+        out, err := WrapPad(block, nil, key)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if hex.EncodeToString(out) != want {
+            t.Errorf("WrapPad got %x, want %s", out, want)
+        }
+    })
+}
+
+func Test_WrapSize(t *testing.T) {
+    block, _ := aes.NewCipher([]byte("kkinjkijeel22plo"))
+
+    if _, err := Wrap(block, nil, make([]byte, 8)); err != ErrPlaintextSize {
+        t.Errorf("Wrap short key got %v", err)
+    }
+
+    if _, err := Wrap(block, nil, make([]byte, 20)); err != ErrPlaintextSize {
+        t.Errorf("Wrap unaligned key got %v", err)
+    }
+
+    if _, err := WrapPad(block, nil, nil); err != ErrPlaintextSize {
+        t.Errorf("WrapPad empty key got %v", err)
+    }
+}
+
+func Test_UnwrapSize(t *testing.T) {
+    block, _ := aes.NewCipher([]byte("kkinjkijeel22plo"))
+
+    if _, err := Unwrap(block, nil, make([]byte, 20)); err != ErrWrappedSize {
+        t.Errorf("Unwrap unaligned data got %v", err)
+    }
+}
+
+func Test_IVSize(t *testing.T) {
+    block, _ := aes.NewCipher([]byte("kkinjkijeel22plo"))
+
+    if _, err := Wrap(block, []byte("123"), make([]byte, 16)); err != ErrIVSize {
+        t.Errorf("Wrap iv size got %v", err)
+    }
+}
@@ -0,0 +1,141 @@
+package lrw
+
+import (
+    "errors"
+    "crypto/cipher"
+
+    "github.com/deatil/go-cryptobin/tool/alias"
+)
+
+/**
+ * LRW 模式 (IEEE P1619 LRW-AES)
+ * C = E_K1(P ^ T) ^ T, T = K2 * I, I 为分组序号
+ * 可用于 16 字节分组的加密算法
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+
+const (
+    // 分组长度
+    BlockSize = 16
+)
+
+var (
+    ErrBlockSize = errors.New("cryptobin/lrw: block size must be 16 bytes")
+    ErrKeySize   = errors.New("cryptobin/lrw: tweak key size must be 16 bytes")
+    ErrIndexSize = errors.New("cryptobin/lrw: index size must be 16 bytes")
+    ErrDataSize  = errors.New("cryptobin/lrw: data size must be a multiple of 16 bytes")
+)
+
+type LRW struct {
+    block cipher.Block
+    key   [BlockSize]byte
+}
+
+// block 为 K1 生成的分组, tweakKey 为 16 字节的 K2
+func NewLRW(block cipher.Block, tweakKey []byte) (*LRW, error) {
+    if block.BlockSize() != BlockSize {
+        return nil, ErrBlockSize
+    }
+
+    if len(tweakKey) != BlockSize {
+        return nil, ErrKeySize
+    }
+
+    l := &LRW{
+        block: block,
+    }
+    copy(l.key[:], tweakKey)
+
+    return l, nil
+}
+
+// 加密, index 为第一个分组的序号, 大端序
+func (this *LRW) Encrypt(dst, src, index []byte) error {
+    return this.crypt(dst, src, index, true)
+}
+
+// 解密
+func (this *LRW) Decrypt(dst, src, index []byte) error {
+    return this.crypt(dst, src, index, false)
+}
+
+func (this *LRW) crypt(dst, src, index []byte, encrypt bool) error {
+    if len(index) != BlockSize {
+        return ErrIndexSize
+    }
+
+    if len(src) % BlockSize != 0 {
+        return ErrDataSize
+    }
+
+    if len(dst) < len(src) {
+        panic("cryptobin/lrw: output smaller than input")
+    }
+
+    if alias.InexactOverlap(dst[:len(src)], src) {
+        panic("cryptobin/lrw: invalid buffer overlap")
+    }
+
+    var i, t, buf [BlockSize]byte
+    copy(i[:], index)
+
+    for len(src) > 0 {
+        t = mul(this.key, i)
+
+        for k := range buf {
+            buf[k] = src[k] ^ t[k]
+        }
+
+        if encrypt {
+            this.block.Encrypt(buf[:], buf[:])
+        } else {
+            this.block.Decrypt(buf[:], buf[:])
+        }
+
+        for k := range buf {
+            dst[k] = buf[k] ^ t[k]
+        }
+
+        inc(&i)
+
+        src = src[BlockSize:]
+        dst = dst[BlockSize:]
+    }
+
+    return nil
+}
+
+// GF(2^128) 乘法, 大端序, 多项式为 x^128 + x^7 + x^2 + x + 1
+func mul(x, y [BlockSize]byte) [BlockSize]byte {
+    var z [BlockSize]byte
+
+    for i := 0; i < 128; i++ {
+        // z = z * x
+        carry := z[0] >> 7
+        for k := 0; k < BlockSize - 1; k++ {
+            z[k] = z[k] << 1 | z[k+1] >> 7
+        }
+        z[BlockSize-1] <<= 1
+        z[BlockSize-1] ^= 0x87 & -carry
+
+        bit := (y[i/8] >> (7 - uint(i%8))) & 1
+        mask := -bit
+        for k := range z {
+            z[k] ^= x[k] & mask
+        }
+    }
+
+    return z
+}
+
+// 序号加 1
+func inc(i *[BlockSize]byte) {
+    for k := BlockSize - 1; k >= 0; k-- {
+        i[k]++
+        if i[k] != 0 {
+            return
+        }
+    }
+}
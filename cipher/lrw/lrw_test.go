@@ -0,0 +1,150 @@
+package lrw
+
+import (
+    "bytes"
+    "testing"
+    "crypto/aes"
+    "crypto/des"
+    "crypto/cipher"
+    "encoding/hex"
+
+    "github.com/deatil/go-cryptobin/cipher/sm4"
+    "github.com/deatil/go-cryptobin/cipher/camellia"
+    "github.com/deatil/go-cryptobin/cipher/serpent"
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+func Test_LRWs(t *testing.T) {
+    test_LRW(t, aes.NewCipher, "AES")
+    test_LRW(t, sm4.NewCipher, "SM4")
+    test_LRW(t, camellia.NewCipher, "Camellia")
+    test_LRW(t, serpent.NewCipher, "Serpent")
+}
+
+func test_LRW(t *testing.T, fn func([]byte) (cipher.Block, error), name string) {
+    t.Run(name, func(t *testing.T) {
+        assertEqual := cryptobin_test.AssertEqualT(t)
+        assertError := cryptobin_test.AssertErrorT(t)
+
+        index := make([]byte, BlockSize)
+        index[15] = 1
+
+        block, err := fn([]byte("kkinjkijeel22plo"))
+        assertError(err, "NewCipher")
+
+        l, err := NewLRW(block, []byte("11injkijkol22plo"))
+        assertError(err, "NewLRW")
+
+        for _, size := range []int{16, 32, 64, 512} {
+            plaintext := bytes.Repeat([]byte{'a'}, size)
+
+            out := make([]byte, size)
+            err := l.Encrypt(out, plaintext, index)
+            assertError(err, "Encrypt")
+
+            // 相同明文分组加密结果不同
+            if size > BlockSize {
+                assertEqual(bytes.Equal(out[:16], out[16:32]), false, "Tweak")
+            }
+
+            res := make([]byte, size)
+            err = l.Decrypt(res, out, index)
+            assertError(err, "Decrypt")
+            assertEqual(res, plaintext, "Decrypt")
+
+            buf := append([]byte(nil), plaintext...)
+            l.Encrypt(buf, buf, index)
+            assertEqual(buf, out, "Encrypt-InPlace")
+
+            l.Decrypt(buf, buf, index)
+            assertEqual(buf, plaintext, "Decrypt-InPlace")
+        }
+    })
+}
+
+// IEEE P1619 LRW-32-AES
+func Test_P1619(t *testing.T) {
+    check := func(t *testing.T, key1, key2, index, plaintext, ciphertext string) {
+        k1, _ := hex.DecodeString(key1)
+        k2, _ := hex.DecodeString(key2)
+        idx, _ := hex.DecodeString(index)
+        pt, _ := hex.DecodeString(plaintext)
+
+        block, err := aes.NewCipher(k1)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        l, err := NewLRW(block, k2)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        out := make([]byte, len(pt))
+        if err := l.Encrypt(out, pt, idx); err != nil {
+            t.Fatal(err)
+        }
+
+        if hex.EncodeToString(out) != ciphertext {
+            t.Errorf("Encrypt got %x, want %s", out, ciphertext)
+        }
+
+        res := make([]byte, len(out))
+        if err := l.Decrypt(res, out, idx); err != nil {
+            t.Fatal(err)
+        }
+
+        if !bytes.Equal(res, pt) {
+            t.Errorf("Decrypt got %x, want %s", res, plaintext)
+        }
+    }
+
+    t.Run("1", func(t *testing.T) {
+        check(t,
+            "4562ac25f828176d4c268414b5680185",
+            "258e2a05e73e9d03ee5a830ccc094c87",
+            "00000000000000000000000000000001",
+            "30313233343536373839414243444546",
+            "f1b273cd65a3df5fe95d489254634eb8",
+        )
+    })
+
+    t.Run("2", func(t *testing.T) {
+        check(t,
+            "d82a9134b26a565030fe69e2377f9847",
+            "cdf90b160c648fb6b00d0d1bae85871f",
+            "00000000000000000000000200000000",
+            "30313233343536373839414243444546",
+            "76322183ed8ff182f9596203690e5e01",
+        )
+    })
+}
+
+func Test_BlockSize(t *testing.T) {
+    block, _ := des.NewCipher([]byte("12345678"))
+
+    if _, err := NewLRW(block, make([]byte, 16)); err != ErrBlockSize {
+        t.Errorf("NewLRW got %v, want ErrBlockSize", err)
+    }
+}
+
+func Test_KeySize(t *testing.T) {
+    block, _ := aes.NewCipher(make([]byte, 16))
+
+    if _, err := NewLRW(block, make([]byte, 8)); err != ErrKeySize {
+        t.Errorf("NewLRW got %v, want ErrKeySize", err)
+    }
+}
+
+func Test_DataSize(t *testing.T) {
+    block, _ := aes.NewCipher(make([]byte, 16))
+    l, _ := NewLRW(block, make([]byte, 16))
+
+    if err := l.Encrypt(make([]byte, 17), make([]byte, 17), make([]byte, 16)); err != ErrDataSize {
+        t.Errorf("Encrypt got %v, want ErrDataSize", err)
+    }
+
+    if err := l.Encrypt(make([]byte, 16), make([]byte, 16), make([]byte, 8)); err != ErrIndexSize {
+        t.Errorf("Encrypt got %v, want ErrIndexSize", err)
+    }
+}
@@ -0,0 +1,173 @@
+package siv
+
+import (
+    "errors"
+    "crypto/cipher"
+    "crypto/subtle"
+
+    "github.com/deatil/go-cryptobin/hash/cmac"
+    "github.com/deatil/go-cryptobin/tool/alias"
+)
+
+/**
+ * SIV 模式 (RFC 5297)
+ * Synthetic Initialization Vector (SIV) Authenticated Encryption
+ *
+ * 密钥 K = K1 || K2, K1 用于 S2V (CMAC), K2 用于 CTR 加密,
+ * 输出数据为 V || C, 可以用于确定性加密及防 nonce 重用
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+
+const (
+    // 分组长度
+    BlockSize = 16
+
+    // 最多可用的附加数据数量
+    MaxAdditional = 126
+)
+
+var (
+    ErrOpen              = errors.New("cryptobin/siv: message authentication failed")
+    ErrBlockSize         = errors.New("cryptobin/siv: block size must be 16 bytes")
+    ErrTooManyAdditional = errors.New("cryptobin/siv: too many additional data")
+)
+
+type SIV struct {
+    mac cipher.Block
+    ctr cipher.Block
+}
+
+// macBlock 为 K1 生成的分组, ctrBlock 为 K2 生成的分组
+func NewSIV(macBlock, ctrBlock cipher.Block) (*SIV, error) {
+    if macBlock.BlockSize() != BlockSize ||
+        ctrBlock.BlockSize() != BlockSize {
+        return nil, ErrBlockSize
+    }
+
+    s := &SIV{
+        mac: macBlock,
+        ctr: ctrBlock,
+    }
+
+    return s, nil
+}
+
+// 附加数据长度
+func (this *SIV) Overhead() int {
+    return BlockSize
+}
+
+// 加密, 返回 V || C
+func (this *SIV) Seal(dst, plaintext []byte, additional ...[]byte) ([]byte, error) {
+    if len(additional) > MaxAdditional {
+        return nil, ErrTooManyAdditional
+    }
+
+    v := this.s2v(plaintext, additional)
+
+    ret, out := alias.SliceForAppend(dst, len(v) + len(plaintext))
+    copy(out, v)
+
+    cipher.NewCTR(this.ctr, ctrIV(v)).XORKeyStream(out[BlockSize:], plaintext)
+
+    return ret, nil
+}
+
+// 解密
+func (this *SIV) Open(dst, ciphertext []byte, additional ...[]byte) ([]byte, error) {
+    if len(additional) > MaxAdditional {
+        return nil, ErrTooManyAdditional
+    }
+
+    if len(ciphertext) < BlockSize {
+        return nil, ErrOpen
+    }
+
+    v := ciphertext[:BlockSize]
+    ciphertext = ciphertext[BlockSize:]
+
+    ret, out := alias.SliceForAppend(dst, len(ciphertext))
+
+    cipher.NewCTR(this.ctr, ctrIV(v)).XORKeyStream(out, ciphertext)
+
+    t := this.s2v(out, additional)
+    if subtle.ConstantTimeCompare(t, v) != 1 {
+        for i := range out {
+            out[i] = 0
+        }
+
+        return nil, ErrOpen
+    }
+
+    return ret, nil
+}
+
+// S2V, 附加数据为 S1...Sn-1, 明文为 Sn
+func (this *SIV) s2v(plaintext []byte, additional [][]byte) []byte {
+    var zero [BlockSize]byte
+
+    d := this.cmac(zero[:])
+
+    for _, ad := range additional {
+        d = dbl(d)
+        xorBytes(d, this.cmac(ad))
+    }
+
+    var t []byte
+    if len(plaintext) >= BlockSize {
+        t = make([]byte, len(plaintext))
+        copy(t, plaintext)
+
+        xorBytes(t[len(t)-BlockSize:], d)
+    } else {
+        t = dbl(d)
+
+        var padded [BlockSize]byte
+        copy(padded[:], plaintext)
+        padded[len(plaintext)] = 0x80
+
+        xorBytes(t, padded[:])
+    }
+
+    return this.cmac(t)
+}
+
+func (this *SIV) cmac(data []byte) []byte {
+    h, _ := cmac.New(this.mac)
+    h.Write(data)
+
+    return h.Sum(nil)
+}
+
+// CTR 向量, 清除 V 的第 63 位及第 31 位
+func ctrIV(v []byte) []byte {
+    q := make([]byte, BlockSize)
+    copy(q, v)
+
+    q[8] &= 0x7f
+    q[12] &= 0x7f
+
+    return q
+}
+
+// GF(2^128) 乘以 x
+func dbl(b []byte) []byte {
+    out := make([]byte, len(b))
+
+    var carry byte
+    for i := len(b) - 1; i >= 0; i-- {
+        out[i] = b[i]<<1 | carry
+        carry = b[i] >> 7
+    }
+
+    // x^128 + x^7 + x^2 + x + 1
+    out[len(out)-1] ^= byte(subtle.ConstantTimeSelect(int(carry), 0x87, 0))
+
+    return out
+}
+
+func xorBytes(dst, src []byte) {
+    subtle.XORBytes(dst, dst, src)
+}
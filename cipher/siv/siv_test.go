@@ -0,0 +1,158 @@
+package siv
+
+import (
+    "bytes"
+    "testing"
+    "crypto/aes"
+    "crypto/cipher"
+    "crypto/des"
+    "encoding/hex"
+
+    "github.com/deatil/go-cryptobin/cipher/sm4"
+    "github.com/deatil/go-cryptobin/cipher/aria"
+    "github.com/deatil/go-cryptobin/cipher/camellia"
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+func Test_SIVs(t *testing.T) {
+    test_SIV(t, aes.NewCipher, "AES")
+    test_SIV(t, sm4.NewCipher, "SM4")
+    test_SIV(t, aria.NewCipher, "Aria")
+    test_SIV(t, camellia.NewCipher, "Camellia")
+}
+
+func test_SIV(t *testing.T, fn func([]byte) (cipher.Block, error), name string) {
+    t.Run(name, func(t *testing.T) {
+        assertEqual := cryptobin_test.AssertEqualT(t)
+        assertError := cryptobin_test.AssertErrorT(t)
+        assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+        key := []byte("kkinjkijeel22plokkinjkijeel22plo")
+
+        macBlock, err := fn(key[:16])
+        assertError(err, "NewCipher")
+
+        ctrBlock, err := fn(key[16:])
+        assertError(err, "NewCipher")
+
+        s, err := NewSIV(macBlock, ctrBlock)
+        assertError(err, "NewSIV")
+
+        for _, size := range []int{0, 1, 15, 16, 17, 100} {
+            plaintext := bytes.Repeat([]byte{'a'}, size)
+
+            out, err := s.Seal(nil, plaintext, []byte("ad1"), []byte("ad2"))
+            assertError(err, "Seal")
+
+            // 相同输入得到相同密文
+            out2, _ := s.Seal(nil, plaintext, []byte("ad1"), []byte("ad2"))
+            assertEqual(out, out2, "Seal-Deterministic")
+
+            res, err := s.Open(nil, out, []byte("ad1"), []byte("ad2"))
+            assertError(err, "Open")
+            assertEqual(bytes.Equal(res, plaintext), true, "Open-Equal")
+
+            _, err = s.Open(nil, out, []byte("ad1"))
+            assertNotErrorNil(err, "Open-Additional")
+        }
+    })
+}
+
+// RFC 5297 Appendix A
+func Test_RFC5297(t *testing.T) {
+    t.Run("A.1", func(t *testing.T) {
+        key, _ := hex.DecodeString("fffefdfcfbfaf9f8f7f6f5f4f3f2f1f0f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff")
+        ad, _ := hex.DecodeString("101112131415161718191a1b1c1d1e1f2021222324252627")
+        plaintext, _ := hex.DecodeString("112233445566778899aabbccddee")
+        output := "85632d07c6e8f37f950acd320a2ecc9340c02b9690c4dc04daef7f6afe5c"
+
+        macBlock, _ := aes.NewCipher(key[:16])
+        ctrBlock, _ := aes.NewCipher(key[16:])
+
+        s, err := NewSIV(macBlock, ctrBlock)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        out, err := s.Seal(nil, plaintext, ad)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if hex.EncodeToString(out) != output {
+            t.Errorf("Seal got %x, want %s", out, output)
+        }
+
+        res, err := s.Open(nil, out, ad)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if !bytes.Equal(res, plaintext) {
+            t.Errorf("Open got %x, want %x", res, plaintext)
+        }
+    })
+
+    t.Run("A.2", func(t *testing.T) {
+        key, _ := hex.DecodeString("7f7e7d7c7b7a79787776757473727170404142434445464748494a4b4c4d4e4f")
+        ad1, _ := hex.DecodeString("00112233445566778899aabbccddeeffdeaddadadeaddadaffeeddccbbaa99887766554433221100")
+        ad2, _ := hex.DecodeString("102030405060708090a0")
+        nonce, _ := hex.DecodeString("09f911029d74e35bd84156c5635688c0")
+        plaintext, _ := hex.DecodeString("7468697320697320736f6d6520706c61696e7465787420746f20656e6372797074207573696e67205349562d414553")
+        output := "7bdb6e3b432667eb06f4d14bff2fbd0fcb900f2fddbe404326601965c889bf17dba77ceb094fa663b7a3f748ba8af829ea64ad544a272e9c485b62a3fd5c0d"
+
+        macBlock, _ := aes.NewCipher(key[:16])
+        ctrBlock, _ := aes.NewCipher(key[16:])
+
+        s, err := NewSIV(macBlock, ctrBlock)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        out, err := s.Seal(nil, plaintext, ad1, ad2, nonce)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if hex.EncodeToString(out) != output {
+            t.Errorf("Seal got %x, want %s", out, output)
+        }
+
+        res, err := s.Open(nil, out, ad1, ad2, nonce)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if !bytes.Equal(res, plaintext) {
+            t.Errorf("Open got %x, want %x", res, plaintext)
+        }
+    })
+}
+
+func Test_OpenTampered(t *testing.T) {
+    key := []byte("kkinjkijeel22plokkinjkijeel22plo")
+
+    macBlock, _ := aes.NewCipher(key[:16])
+    ctrBlock, _ := aes.NewCipher(key[16:])
+
+    s, _ := NewSIV(macBlock, ctrBlock)
+
+    out, _ := s.Seal(nil, []byte("kjinjkijkolkdplo"), []byte("ad"))
+
+    out[len(out)-1] ^= 1
+    if _, err := s.Open(nil, out, []byte("ad")); err != ErrOpen {
+        t.Errorf("Open got %v, want ErrOpen", err)
+    }
+
+    if _, err := s.Open(nil, out[:BlockSize-1], []byte("ad")); err != ErrOpen {
+        t.Errorf("Open short got %v, want ErrOpen", err)
+    }
+}
+
+func Test_BlockSize(t *testing.T) {
+    block, _ := des.NewCipher([]byte("12345678"))
+
+    if _, err := NewSIV(block, block); err != ErrBlockSize {
+        t.Errorf("NewSIV got %v, want ErrBlockSize", err)
+    }
+}
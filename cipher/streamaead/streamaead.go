@@ -0,0 +1,381 @@
+package streamaead
+
+import (
+    "io"
+    "errors"
+    "crypto/cipher"
+    "crypto/sha256"
+    "encoding/binary"
+
+    "golang.org/x/crypto/hkdf"
+
+    "github.com/deatil/go-cryptobin/tool"
+)
+
+/**
+ * 分段 AEAD 流式加密
+ *
+ * 数据格式:
+ * header = headerLength(1) || id(2) || salt(keySize) || noncePrefix(nonceSize - 5)
+ * segment_i = AEAD.Seal(key, noncePrefix || i(4) || last(1), plaintext_i)
+ * ciphertext = header || segment_0 || segment_1 || ... || segment_n
+ *
+ * 分段密钥由 HKDF-SHA256(key, salt, id || additional) 生成,
+ * 每段 nonce 包含分段序号及最后一段标识, 可以检测分段被截断及重排.
+ * nonce 前缀为随机生成, 和算法标识一起写入头部.
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+
+const (
+    // 默认分段长度
+    DefaultSegmentSize = 64 * 1024
+
+    // nonce 中分段序号及最后一段标识长度
+    noncePostfixSize = 4 + 1
+
+    // 算法标识长度
+    idSize = 2
+)
+
+var (
+    ErrInvalidHeader   = errors.New("cryptobin/streamaead: invalid header")
+    ErrInvalidID       = errors.New("cryptobin/streamaead: invalid cipher id")
+    ErrOpen            = errors.New("cryptobin/streamaead: message authentication failed")
+    ErrTooManySegments = errors.New("cryptobin/streamaead: too many segments")
+    ErrClosed          = errors.New("cryptobin/streamaead: write to closed writer")
+)
+
+// 生成 AEAD, key 为派生的分段密钥
+type AEADFunc = func(key []byte) (cipher.AEAD, error)
+
+// 分段 AEAD
+type StreamAEAD struct {
+    id          uint16
+    key         []byte
+    segmentSize int
+    newAEAD     AEADFunc
+}
+
+// 构造函数
+// id 为算法标识, 会写入数据头部, segmentSize 为每段密文长度
+func New(id uint16, key []byte, segmentSize int, newAEAD AEADFunc) (*StreamAEAD, error) {
+    if len(key) == 0 {
+        return nil, errors.New("cryptobin/streamaead: key is empty")
+    }
+
+    if segmentSize <= 0 {
+        segmentSize = DefaultSegmentSize
+    }
+
+    if newAEAD == nil {
+        return nil, errors.New("cryptobin/streamaead: AEADFunc is nil")
+    }
+
+    s := &StreamAEAD{
+        id:          id,
+        key:         append([]byte(nil), key...),
+        segmentSize: segmentSize,
+        newAEAD:     newAEAD,
+    }
+
+    return s, nil
+}
+
+// 头部长度
+func (this *StreamAEAD) HeaderSize(nonceSize int) int {
+    return 1 + idSize + len(this.key) + nonceSize - noncePostfixSize
+}
+
+// 派生分段密钥
+func (this *StreamAEAD) deriveAEAD(salt, additional []byte) (cipher.AEAD, error) {
+    info := make([]byte, idSize, idSize + len(additional))
+    binary.BigEndian.PutUint16(info, this.id)
+    info = append(info, additional...)
+
+    key := make([]byte, len(this.key))
+
+    kdf := hkdf.New(sha256.New, this.key, salt, info)
+    if _, err := io.ReadFull(kdf, key); err != nil {
+        return nil, err
+    }
+
+    aead, err := this.newAEAD(key)
+    if err != nil {
+        return nil, err
+    }
+
+    if aead.NonceSize() < noncePostfixSize + 1 {
+        return nil, errors.New("cryptobin/streamaead: nonce size is too small")
+    }
+
+    if this.segmentSize <= aead.Overhead() {
+        return nil, errors.New("cryptobin/streamaead: segment size is too small")
+    }
+
+    return aead, nil
+}
+
+// 生成加密写入器, 需要调用 Close 写入最后一段数据
+func (this *StreamAEAD) NewEncryptingWriter(w io.Writer, additional []byte) (io.WriteCloser, error) {
+    salt, err := tool.GenRandom(len(this.key))
+    if err != nil {
+        return nil, err
+    }
+
+    aead, err := this.deriveAEAD(salt, additional)
+    if err != nil {
+        return nil, err
+    }
+
+    noncePrefix, err := tool.GenRandom(aead.NonceSize() - noncePostfixSize)
+    if err != nil {
+        return nil, err
+    }
+
+    headerSize := this.HeaderSize(aead.NonceSize())
+    if headerSize > 255 {
+        return nil, ErrInvalidHeader
+    }
+
+    header := make([]byte, 1 + idSize, headerSize)
+    header[0] = byte(headerSize)
+    binary.BigEndian.PutUint16(header[1:], this.id)
+    header = append(header, salt...)
+    header = append(header, noncePrefix...)
+
+    if _, err := w.Write(header); err != nil {
+        return nil, err
+    }
+
+    plainSize := this.segmentSize - aead.Overhead()
+
+    ew := &encryptingWriter{
+        w:           w,
+        aead:        aead,
+        noncePrefix: noncePrefix,
+        plain:       make([]byte, 0, plainSize),
+        cipher:      make([]byte, 0, this.segmentSize),
+    }
+
+    return ew, nil
+}
+
+// 生成解密读取器
+func (this *StreamAEAD) NewDecryptingReader(r io.Reader, additional []byte) (io.Reader, error) {
+    var headerSize [1]byte
+    if _, err := io.ReadFull(r, headerSize[:]); err != nil {
+        return nil, ErrInvalidHeader
+    }
+
+    size := int(headerSize[0])
+    if size < 1 + idSize + len(this.key) {
+        return nil, ErrInvalidHeader
+    }
+
+    header := make([]byte, size - 1)
+    if _, err := io.ReadFull(r, header); err != nil {
+        return nil, ErrInvalidHeader
+    }
+
+    if binary.BigEndian.Uint16(header) != this.id {
+        return nil, ErrInvalidID
+    }
+
+    salt := header[idSize:idSize + len(this.key)]
+    noncePrefix := header[idSize + len(this.key):]
+
+    aead, err := this.deriveAEAD(salt, additional)
+    if err != nil {
+        return nil, err
+    }
+
+    if len(noncePrefix) != aead.NonceSize() - noncePostfixSize {
+        return nil, ErrInvalidHeader
+    }
+
+    dr := &decryptingReader{
+        r:           r,
+        aead:        aead,
+        noncePrefix: append([]byte(nil), noncePrefix...),
+        segmentSize: this.segmentSize,
+        buf:         make([]byte, 0, this.segmentSize + 1),
+    }
+
+    return dr, nil
+}
+
+// 分段 nonce
+func segmentNonce(prefix []byte, index uint32, last bool) []byte {
+    nonce := make([]byte, len(prefix) + noncePostfixSize)
+    copy(nonce, prefix)
+
+    binary.BigEndian.PutUint32(nonce[len(prefix):], index)
+
+    if last {
+        nonce[len(nonce)-1] = 1
+    }
+
+    return nonce
+}
+
+// 加密写入器
+type encryptingWriter struct {
+    w           io.Writer
+    aead        cipher.AEAD
+    noncePrefix []byte
+    plain       []byte
+    cipher      []byte
+    index       uint32
+    closed      bool
+    err         error
+}
+
+func (this *encryptingWriter) Write(p []byte) (int, error) {
+    if this.closed {
+        return 0, ErrClosed
+    }
+
+    if this.err != nil {
+        return 0, this.err
+    }
+
+    n := 0
+    for len(p) > 0 {
+        // 缓存已满且还有数据时, 写入非最后一段
+        if len(this.plain) == cap(this.plain) {
+            if err := this.flush(false); err != nil {
+                this.err = err
+                return n, err
+            }
+        }
+
+        m := copy(this.plain[len(this.plain):cap(this.plain)], p)
+        this.plain = this.plain[:len(this.plain) + m]
+
+        n += m
+        p = p[m:]
+    }
+
+    return n, nil
+}
+
+// 写入最后一段数据
+func (this *encryptingWriter) Close() error {
+    if this.closed {
+        return nil
+    }
+
+    if this.err != nil {
+        return this.err
+    }
+
+    this.closed = true
+
+    return this.flush(true)
+}
+
+func (this *encryptingWriter) flush(last bool) error {
+    nonce := segmentNonce(this.noncePrefix, this.index, last)
+
+    this.cipher = this.aead.Seal(this.cipher[:0], nonce, this.plain, nil)
+    if _, err := this.w.Write(this.cipher); err != nil {
+        return err
+    }
+
+    this.plain = this.plain[:0]
+
+    if !last {
+        if this.index == 1<<32 - 1 {
+            return ErrTooManySegments
+        }
+
+        this.index++
+    }
+
+    return nil
+}
+
+// 解密读取器
+type decryptingReader struct {
+    r           io.Reader
+    aead        cipher.AEAD
+    noncePrefix []byte
+    segmentSize int
+    buf         []byte
+    plain       []byte
+    index       uint32
+    done        bool
+    err         error
+}
+
+func (this *decryptingReader) Read(p []byte) (int, error) {
+    for len(this.plain) == 0 {
+        if this.err != nil {
+            return 0, this.err
+        }
+
+        if this.done {
+            return 0, io.EOF
+        }
+
+        if err := this.readSegment(); err != nil {
+            this.err = err
+            return 0, err
+        }
+    }
+
+    n := copy(p, this.plain)
+    this.plain = this.plain[n:]
+
+    return n, nil
+}
+
+// 读取一段数据, 多读取一个字节用于判断是否为最后一段
+func (this *decryptingReader) readSegment() error {
+    start := len(this.buf)
+    this.buf = this.buf[:this.segmentSize + 1]
+
+    n, err := io.ReadFull(this.r, this.buf[start:])
+    if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+        return err
+    }
+
+    size := start + n
+
+    last := size <= this.segmentSize
+
+    segment := this.buf[:size]
+    if !last {
+        segment = this.buf[:this.segmentSize]
+    }
+
+    nonce := segmentNonce(this.noncePrefix, this.index, last)
+
+    plain, err := this.aead.Open(nil, nonce, segment, nil)
+    if err != nil {
+        return ErrOpen
+    }
+
+    this.plain = plain
+
+    if last {
+        this.done = true
+        this.buf = this.buf[:0]
+
+        return nil
+    }
+
+    if this.index == 1<<32 - 1 {
+        return ErrTooManySegments
+    }
+
+    this.index++
+
+    // 保留多读取的一个字节
+    this.buf[0] = this.buf[this.segmentSize]
+    this.buf = this.buf[:1]
+
+    return nil
+}
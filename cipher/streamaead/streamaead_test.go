@@ -0,0 +1,217 @@
+package streamaead
+
+import (
+    "io"
+    "bytes"
+    "testing"
+    "crypto/aes"
+    "crypto/rand"
+    "crypto/cipher"
+    "testing/iotest"
+
+    "golang.org/x/crypto/chacha20poly1305"
+
+    "github.com/deatil/go-cryptobin/cipher/sm4"
+    "github.com/deatil/go-cryptobin/cipher/ccm"
+    "github.com/deatil/go-cryptobin/cipher/ocb"
+    "github.com/deatil/go-cryptobin/cipher/eax"
+    "github.com/deatil/go-cryptobin/cipher/aria"
+    "github.com/deatil/go-cryptobin/cipher/camellia"
+    "github.com/deatil/go-cryptobin/cipher/kuznyechik"
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+func blockAEAD(
+    newBlock func([]byte) (cipher.Block, error),
+    newMode func(cipher.Block) (cipher.AEAD, error),
+) AEADFunc {
+    return func(key []byte) (cipher.AEAD, error) {
+        block, err := newBlock(key)
+        if err != nil {
+            return nil, err
+        }
+
+        return newMode(block)
+    }
+}
+
+func encrypt(t *testing.T, s *StreamAEAD, data, additional []byte) []byte {
+    var buf bytes.Buffer
+
+    w, err := s.NewEncryptingWriter(&buf, additional)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if _, err := io.Copy(w, iotest.HalfReader(bytes.NewReader(data))); err != nil {
+        t.Fatal(err)
+    }
+
+    if err := w.Close(); err != nil {
+        t.Fatal(err)
+    }
+
+    return buf.Bytes()
+}
+
+func decrypt(s *StreamAEAD, data, additional []byte) ([]byte, error) {
+    r, err := s.NewDecryptingReader(iotest.OneByteReader(bytes.NewReader(data)), additional)
+    if err != nil {
+        return nil, err
+    }
+
+    return io.ReadAll(r)
+}
+
+func Test_StreamAEAD(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    key16 := []byte("kkinjkijeel22plo")
+    key32 := []byte("kkinjkijeel22plokkinjkijeel22plo")
+
+    cases := []struct{
+        name string
+        key  []byte
+        fn   AEADFunc
+    }{
+        {"AES-GCM", key16, blockAEAD(aes.NewCipher, cipher.NewGCM)},
+        {"AES-CCM", key16, blockAEAD(aes.NewCipher, ccm.NewCCM)},
+        {"AES-OCB", key16, blockAEAD(aes.NewCipher, ocb.NewOCB)},
+        {"AES-EAX", key16, blockAEAD(aes.NewCipher, eax.NewEAX)},
+        {"SM4-GCM", key16, blockAEAD(sm4.NewCipher, cipher.NewGCM)},
+        {"Aria-GCM", key16, blockAEAD(aria.NewCipher, cipher.NewGCM)},
+        {"Camellia-GCM", key16, blockAEAD(camellia.NewCipher, cipher.NewGCM)},
+        {"Kuznyechik-GCM", key32, blockAEAD(kuznyechik.NewCipher, cipher.NewGCM)},
+        {"Chacha20poly1305", key32, chacha20poly1305.New},
+        {"Chacha20poly1305X", key32, chacha20poly1305.NewX},
+    }
+
+    additional := []byte("additional")
+
+    for _, c := range cases {
+        for _, size := range []int{0, 1, 100, 4096 - 16, 4096, 3*4096 + 7} {
+            data := make([]byte, size)
+            rand.Read(data)
+
+            s, err := New(1, c.key, 4096, c.fn)
+            assertError(err, c.name + "-New")
+
+            ciphertext := encrypt(t, s, data, additional)
+
+            plaintext, err := decrypt(s, ciphertext, additional)
+            assertError(err, c.name + "-Decrypt")
+
+            assertEqual(len(plaintext), size, c.name + "-Len")
+            assertEqual(bytes.Equal(plaintext, data), true, c.name + "-Equal")
+        }
+    }
+}
+
+func Test_StreamAEAD_Check(t *testing.T) {
+    key := []byte("kkinjkijeel22plo")
+    segmentSize := 256
+    additional := []byte("additional")
+
+    s, err := New(1, key, segmentSize, blockAEAD(aes.NewCipher, cipher.NewGCM))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    data := make([]byte, 3*segmentSize)
+    rand.Read(data)
+
+    ciphertext := encrypt(t, s, data, additional)
+    headerSize := s.HeaderSize(12)
+
+    header := ciphertext[:headerSize]
+    body := ciphertext[headerSize:]
+
+    // 3 个完整分段及带有结束标识的最后一段
+    segment := func(i int) []byte {
+        end := (i + 1) * segmentSize
+        if end > len(body) {
+            end = len(body)
+        }
+
+        return body[i*segmentSize:end]
+    }
+
+    join := func(parts ...[]byte) []byte {
+        var buf []byte
+        for _, p := range parts {
+            buf = append(buf, p...)
+        }
+
+        return buf
+    }
+
+    check := func(t *testing.T, data []byte, want error) {
+        _, err := decrypt(s, data, additional)
+        if err != want {
+            t.Errorf("Decrypt got %v, want %v", err, want)
+        }
+    }
+
+    t.Run("Reorder", func(t *testing.T) {
+        check(t, join(header, segment(1), segment(0), segment(2), segment(3)), ErrOpen)
+    })
+
+    t.Run("Reorder-Last", func(t *testing.T) {
+        check(t, join(header, segment(0), segment(1), segment(3), segment(2)), ErrOpen)
+    })
+
+    t.Run("Drop", func(t *testing.T) {
+        check(t, join(header, segment(0), segment(2), segment(3)), ErrOpen)
+    })
+
+    t.Run("Duplicate", func(t *testing.T) {
+        check(t, join(header, segment(0), segment(0), segment(1), segment(2), segment(3)), ErrOpen)
+    })
+
+    t.Run("Truncate-Segment", func(t *testing.T) {
+        check(t, join(header, segment(0), segment(1), segment(2)), ErrOpen)
+    })
+
+    t.Run("Truncate-Byte", func(t *testing.T) {
+        check(t, ciphertext[:len(ciphertext)-1], ErrOpen)
+    })
+
+    t.Run("Append", func(t *testing.T) {
+        check(t, join(ciphertext, segment(0)), ErrOpen)
+    })
+
+    t.Run("Header-Only", func(t *testing.T) {
+        check(t, header, ErrOpen)
+    })
+
+    t.Run("Header-Short", func(t *testing.T) {
+        check(t, header[:headerSize-1], ErrInvalidHeader)
+    })
+
+    t.Run("Tamper", func(t *testing.T) {
+        tamper := append([]byte(nil), ciphertext...)
+        tamper[len(tamper)-3] ^= 0x01
+
+        check(t, tamper, ErrOpen)
+    })
+
+    t.Run("Additional", func(t *testing.T) {
+        _, err := decrypt(s, ciphertext, []byte("other"))
+        if err != ErrOpen {
+            t.Errorf("Decrypt got %v, want %v", err, ErrOpen)
+        }
+    })
+
+    t.Run("ID", func(t *testing.T) {
+        s2, err := New(2, key, segmentSize, blockAEAD(aes.NewCipher, cipher.NewGCM))
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        _, err = decrypt(s2, ciphertext, additional)
+        if err != ErrInvalidID {
+            t.Errorf("Decrypt got %v, want %v", err, ErrInvalidID)
+        }
+    })
+}
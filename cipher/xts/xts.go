@@ -0,0 +1,210 @@
+package xts
+
+import (
+    "errors"
+    "crypto/cipher"
+    "encoding/binary"
+
+    "github.com/deatil/go-cryptobin/tool/alias"
+)
+
+/**
+ * XTS 模式 (IEEE 1619)
+ * 可用于 16 字节分组的加密算法, 支持密文窃取及多个连续扇区
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+
+const (
+    // 分组长度
+    BlockSize = 16
+)
+
+var (
+    ErrBlockSize  = errors.New("cryptobin/xts: block size must be 16 bytes")
+    ErrDataSize   = errors.New("cryptobin/xts: data size must be at least 16 bytes")
+    ErrSectorSize = errors.New("cryptobin/xts: sector size must be at least 16 bytes")
+    ErrLastSector = errors.New("cryptobin/xts: last sector size must be at least 16 bytes")
+    ErrDstSize    = errors.New("cryptobin/xts: output smaller than input")
+)
+
+type XTS struct {
+    block cipher.Block
+    tweak cipher.Block
+}
+
+// block 为 K1 生成的数据分组, tweak 为 K2 生成的调整值分组
+func NewXTS(block, tweak cipher.Block) (*XTS, error) {
+    if block.BlockSize() != BlockSize ||
+        tweak.BlockSize() != BlockSize {
+        return nil, ErrBlockSize
+    }
+
+    x := &XTS{
+        block: block,
+        tweak: tweak,
+    }
+
+    return x, nil
+}
+
+// 加密一个扇区, 数据长度不是 16 的倍数时使用密文窃取
+// 数据长度最少为 16 字节, dst 长度不能小于 src
+func (this *XTS) Encrypt(dst, src []byte, sectorNum uint64) error {
+    return this.crypt(dst, src, sectorNum, true)
+}
+
+// 解密一个扇区
+func (this *XTS) Decrypt(dst, src []byte, sectorNum uint64) error {
+    return this.crypt(dst, src, sectorNum, false)
+}
+
+// 加密连续扇区, 扇区号依次加 1, sectorSize 最少为 16 字节
+// 最后一个扇区可以小于 sectorSize, 但最少为 16 字节
+// sectorSize 为 0 时整个数据作为一个扇区
+func (this *XTS) EncryptSectors(dst, src []byte, sectorNum uint64, sectorSize int) error {
+    return this.cryptSectors(dst, src, sectorNum, sectorSize, true)
+}
+
+// 解密连续扇区
+func (this *XTS) DecryptSectors(dst, src []byte, sectorNum uint64, sectorSize int) error {
+    return this.cryptSectors(dst, src, sectorNum, sectorSize, false)
+}
+
+func (this *XTS) cryptSectors(dst, src []byte, sectorNum uint64, sectorSize int, encrypt bool) error {
+    if sectorSize == 0 {
+        return this.crypt(dst, src, sectorNum, encrypt)
+    }
+
+    if sectorSize < BlockSize {
+        return ErrSectorSize
+    }
+
+    if len(dst) < len(src) {
+        return ErrDstSize
+    }
+
+    // 在处理前检查最后一个扇区, 避免只写入部分数据
+    if last := len(src) % sectorSize; last > 0 && last < BlockSize {
+        return ErrLastSector
+    }
+
+    for len(src) > 0 {
+        n := sectorSize
+        if n > len(src) {
+            n = len(src)
+        }
+
+        err := this.crypt(dst[:n], src[:n], sectorNum, encrypt)
+        if err != nil {
+            return err
+        }
+
+        src = src[n:]
+        dst = dst[n:]
+        sectorNum++
+    }
+
+    return nil
+}
+
+func (this *XTS) crypt(dst, src []byte, sectorNum uint64, encrypt bool) error {
+    if len(src) < BlockSize {
+        return ErrDataSize
+    }
+
+    if len(dst) < len(src) {
+        return ErrDstSize
+    }
+
+    if alias.InexactOverlap(dst[:len(src)], src) {
+        panic("cryptobin/xts: invalid buffer overlap")
+    }
+
+    var tweak [BlockSize]byte
+    binary.LittleEndian.PutUint64(tweak[:8], sectorNum)
+
+    this.tweak.Encrypt(tweak[:], tweak[:])
+
+    full := len(src) / BlockSize
+    tail := len(src) % BlockSize
+
+    // 密文窃取时最后一个完整分组单独处理
+    if tail > 0 {
+        full--
+    }
+
+    for i := 0; i < full; i++ {
+        this.xex(dst[:BlockSize], src[:BlockSize], &tweak, encrypt)
+        mul2(&tweak)
+
+        src = src[BlockSize:]
+        dst = dst[BlockSize:]
+    }
+
+    if tail == 0 {
+        return nil
+    }
+
+    var cc, pp [BlockSize]byte
+
+    if encrypt {
+        // CC = XEX(P_{m-1}, T_{m-1}), C_m = CC[:r], C_{m-1} = XEX(P_m || CC[r:], T_m)
+        this.xex(cc[:], src[:BlockSize], &tweak, true)
+        mul2(&tweak)
+
+        copy(pp[:], src[BlockSize:])
+        copy(pp[tail:], cc[tail:])
+
+        copy(dst[BlockSize:], cc[:tail])
+        this.xex(dst[:BlockSize], pp[:], &tweak, true)
+    } else {
+        // 解密时先使用 T_m 再使用 T_{m-1}
+        last := tweak
+        mul2(&last)
+
+        this.xex(pp[:], src[:BlockSize], &last, false)
+
+        copy(cc[:], src[BlockSize:])
+        copy(cc[tail:], pp[tail:])
+
+        copy(dst[BlockSize:], pp[:tail])
+        this.xex(dst[:BlockSize], cc[:], &tweak, false)
+    }
+
+    return nil
+}
+
+// C = E(P ^ T) ^ T
+func (this *XTS) xex(dst, src []byte, tweak *[BlockSize]byte, encrypt bool) {
+    var buf [BlockSize]byte
+    for i := range buf {
+        buf[i] = src[i] ^ tweak[i]
+    }
+
+    if encrypt {
+        this.block.Encrypt(buf[:], buf[:])
+    } else {
+        this.block.Decrypt(buf[:], buf[:])
+    }
+
+    for i := range buf {
+        dst[i] = buf[i] ^ tweak[i]
+    }
+}
+
+// GF(2^128) 乘以 x, 小端序
+func mul2(tweak *[BlockSize]byte) {
+    var carryIn byte
+    for j := range tweak {
+        carryOut := tweak[j] >> 7
+        tweak[j] = (tweak[j] << 1) + carryIn
+        carryIn = carryOut
+    }
+
+    if carryIn != 0 {
+        // x^128 = x^7 + x^2 + x + 1
+        tweak[0] ^= 1<<7 | 1<<2 | 1<<1 | 1
+    }
+}
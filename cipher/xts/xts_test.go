@@ -0,0 +1,237 @@
+package xts
+
+import (
+    "bytes"
+    "testing"
+    "crypto/aes"
+    "crypto/des"
+    "crypto/cipher"
+    "encoding/hex"
+
+    go_xts "golang.org/x/crypto/xts"
+
+    "github.com/deatil/go-cryptobin/cipher/sm4"
+    "github.com/deatil/go-cryptobin/cipher/camellia"
+    "github.com/deatil/go-cryptobin/cipher/serpent"
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+func Test_XTSs(t *testing.T) {
+    test_XTS(t, aes.NewCipher, "AES")
+    test_XTS(t, sm4.NewCipher, "SM4")
+    test_XTS(t, camellia.NewCipher, "Camellia")
+    test_XTS(t, serpent.NewCipher, "Serpent")
+}
+
+func test_XTS(t *testing.T, fn func([]byte) (cipher.Block, error), name string) {
+    t.Run(name, func(t *testing.T) {
+        assertEqual := cryptobin_test.AssertEqualT(t)
+        assertError := cryptobin_test.AssertErrorT(t)
+
+        block, err := fn([]byte("kkinjkijeel22plo"))
+        assertError(err, "NewCipher")
+
+        tweak, err := fn([]byte("11injkijkol22plo"))
+        assertError(err, "NewCipher")
+
+        x, err := NewXTS(block, tweak)
+        assertError(err, "NewXTS")
+
+        for _, size := range []int{16, 17, 31, 32, 100, 1000} {
+            plaintext := bytes.Repeat([]byte{'a'}, size)
+
+            out := make([]byte, size)
+            err := x.EncryptSectors(out, plaintext, 1, 64)
+            assertError(err, "EncryptSectors")
+
+            res := make([]byte, size)
+            err = x.DecryptSectors(res, out, 1, 64)
+            assertError(err, "DecryptSectors")
+            assertEqual(res, plaintext, "DecryptSectors")
+
+            buf := append([]byte(nil), plaintext...)
+            x.Encrypt(buf, buf, 1)
+            x.Decrypt(buf, buf, 1)
+            assertEqual(buf, plaintext, "InPlace")
+        }
+    })
+}
+
+// IEEE 1619-2007 Appendix B
+func Test_IEEE1619(t *testing.T) {
+    check := func(t *testing.T, key1, key2 string, sectorNum uint64, plaintext, ciphertext string) {
+        k1, _ := hex.DecodeString(key1)
+        k2, _ := hex.DecodeString(key2)
+        pt, _ := hex.DecodeString(plaintext)
+
+        block, _ := aes.NewCipher(k1)
+        tweak, _ := aes.NewCipher(k2)
+
+        x, err := NewXTS(block, tweak)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        out := make([]byte, len(pt))
+        if err := x.Encrypt(out, pt, sectorNum); err != nil {
+            t.Fatal(err)
+        }
+
+        if hex.EncodeToString(out) != ciphertext {
+            t.Errorf("Encrypt got %x, want %s", out, ciphertext)
+        }
+
+        res := make([]byte, len(out))
+        if err := x.Decrypt(res, out, sectorNum); err != nil {
+            t.Fatal(err)
+        }
+
+        if !bytes.Equal(res, pt) {
+            t.Errorf("Decrypt got %x, want %s", res, plaintext)
+        }
+    }
+
+    t.Run("Vector 1", func(t *testing.T) {
+        check(t,
+            "00000000000000000000000000000000",
+            "00000000000000000000000000000000",
+            0,
+            "0000000000000000000000000000000000000000000000000000000000000000",
+            "917cf69ebd68b2ec9b9fe9a3eadda692cd43d2f59598ed858c02c2652fbf922e",
+        )
+    })
+
+    t.Run("Vector 2", func(t *testing.T) {
+        check(t,
+            "11111111111111111111111111111111",
+            "22222222222222222222222222222222",
+            0x3333333333,
+            "4444444444444444444444444444444444444444444444444444444444444444",
+            "c454185e6a16936e39334038acef838bfb186fff7480adc4289382ecd6d394f0",
+        )
+    })
+
+    t.Run("Vector 15", func(t *testing.T) {
+        check(t,
+            "fffefdfcfbfaf9f8f7f6f5f4f3f2f1f0",
+            "bfbebdbcbbbab9b8b7b6b5b4b3b2b1b0",
+            0x123456789a,
+            "000102030405060708090a0b0c0d0e0f10",
+            "6c1625db4671522d3d7599601de7ca09ed",
+        )
+    })
+
+    t.Run("Vector 16", func(t *testing.T) {
+        check(t,
+            "fffefdfcfbfaf9f8f7f6f5f4f3f2f1f0",
+            "bfbebdbcbbbab9b8b7b6b5b4b3b2b1b0",
+            0x123456789a,
+            "000102030405060708090a0b0c0d0e0f1011",
+            "d069444b7a7e0cab09e24447d24deb1fedbf",
+        )
+    })
+
+    t.Run("Vector 17", func(t *testing.T) {
+        check(t,
+            "fffefdfcfbfaf9f8f7f6f5f4f3f2f1f0",
+            "bfbebdbcbbbab9b8b7b6b5b4b3b2b1b0",
+            0x123456789a,
+            "000102030405060708090a0b0c0d0e0f101112",
+            "e5df1351c0544ba1350b3363cd8ef4beedbf9d",
+        )
+    })
+
+    t.Run("Vector 18", func(t *testing.T) {
+        check(t,
+            "fffefdfcfbfaf9f8f7f6f5f4f3f2f1f0",
+            "bfbebdbcbbbab9b8b7b6b5b4b3b2b1b0",
+            0x123456789a,
+            "000102030405060708090a0b0c0d0e0f10111213",
+            "9d84c813f719aa2c7be3f66171c7c5c2edbf9dac",
+        )
+    })
+}
+
+// 连续扇区结果和 golang.org/x/crypto/xts 逐个扇区加密相同
+func Test_Sectors(t *testing.T) {
+    key, _ := hex.DecodeString("fffefdfcfbfaf9f8f7f6f5f4f3f2f1f0bfbebdbcbbbab9b8b7b6b5b4b3b2b1b0")
+
+    block, _ := aes.NewCipher(key[:16])
+    tweak, _ := aes.NewCipher(key[16:])
+
+    x, _ := NewXTS(block, tweak)
+
+    c, err := go_xts.NewCipher(aes.NewCipher, key)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    sectorSize := 512
+    plaintext := make([]byte, sectorSize*4)
+    for i := range plaintext {
+        plaintext[i] = byte(i)
+    }
+
+    out := make([]byte, len(plaintext))
+    if err := x.EncryptSectors(out, plaintext, 7, sectorSize); err != nil {
+        t.Fatal(err)
+    }
+
+    for i := 0; i < 4; i++ {
+        check := make([]byte, sectorSize)
+        c.Encrypt(check, plaintext[i*sectorSize:(i+1)*sectorSize], uint64(7 + i))
+
+        if !bytes.Equal(out[i*sectorSize:(i+1)*sectorSize], check) {
+            t.Errorf("sector %d not match", i)
+        }
+    }
+
+    res := make([]byte, len(out))
+    if err := x.DecryptSectors(res, out, 7, sectorSize); err != nil {
+        t.Fatal(err)
+    }
+
+    if !bytes.Equal(res, plaintext) {
+        t.Error("DecryptSectors not match")
+    }
+}
+
+func Test_BlockSize(t *testing.T) {
+    block, _ := des.NewCipher([]byte("12345678"))
+
+    if _, err := NewXTS(block, block); err != ErrBlockSize {
+        t.Errorf("NewXTS got %v, want ErrBlockSize", err)
+    }
+}
+
+func Test_Errors(t *testing.T) {
+    block, _ := aes.NewCipher(make([]byte, 16))
+
+    x, _ := NewXTS(block, block)
+
+    if err := x.Encrypt(make([]byte, 15), make([]byte, 15), 0); err != ErrDataSize {
+        t.Errorf("Encrypt got %v", err)
+    }
+
+    if err := x.EncryptSectors(make([]byte, 32), make([]byte, 32), 0, 8); err != ErrSectorSize {
+        t.Errorf("EncryptSectors got %v", err)
+    }
+
+    // last sector less than 16 bytes, nothing is written
+    dst := make([]byte, 40)
+    if err := x.EncryptSectors(dst, bytes.Repeat([]byte{1}, 40), 0, 32); err != ErrLastSector {
+        t.Errorf("EncryptSectors last sector got %v", err)
+    }
+
+    if !bytes.Equal(dst, make([]byte, 40)) {
+        t.Error("EncryptSectors should not write data when last sector is short")
+    }
+
+    if err := x.EncryptSectors(make([]byte, 63), make([]byte, 64), 0, 32); err != ErrDstSize {
+        t.Errorf("EncryptSectors short dst got %v", err)
+    }
+
+    if err := x.Encrypt(make([]byte, 16), make([]byte, 17), 0); err != ErrDstSize {
+        t.Errorf("Encrypt short dst got %v", err)
+    }
+}
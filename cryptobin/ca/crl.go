@@ -0,0 +1,364 @@
+package ca
+
+import (
+    "sort"
+    "time"
+    "errors"
+    "math/big"
+    "crypto"
+    "crypto/rand"
+    "crypto/x509"
+    "encoding/pem"
+    "encoding/asn1"
+    "crypto/x509/pkix"
+
+    sm2X509 "github.com/deatil/go-cryptobin/gm/x509"
+)
+
+var (
+    oidExtensionCRLNumber         = asn1.ObjectIdentifier{2, 5, 29, 20}
+    oidExtensionReasonCode        = asn1.ObjectIdentifier{2, 5, 29, 21}
+    oidExtensionDeltaCRLIndicator = asn1.ObjectIdentifier{2, 5, 29, 27}
+)
+
+// 吊销原因 (RFC 5280 5.3.1)
+const (
+    ReasonUnspecified          = 0
+    ReasonKeyCompromise        = 1
+    ReasonCACompromise         = 2
+    ReasonAffiliationChanged   = 3
+    ReasonSuperseded           = 4
+    ReasonCessationOfOperation = 5
+    ReasonCertificateHold      = 6
+    ReasonRemoveFromCRL        = 8
+    ReasonPrivilegeWithdrawn   = 9
+    ReasonAACompromise         = 10
+)
+
+// 吊销的证书
+type RevokedCert struct {
+    // 证书序列号
+    SerialNumber *big.Int
+
+    // 吊销时间
+    RevocationTime time.Time
+
+    // 吊销原因
+    ReasonCode int
+}
+
+// 证书已被吊销的错误
+type RevocationError struct {
+    SerialNumber   *big.Int
+    RevocationTime time.Time
+    ReasonCode     int
+}
+
+func (e *RevocationError) Error() string {
+    return "CA: certificate " + e.SerialNumber.String() + " has been revoked at " + e.RevocationTime.Format(time.RFC3339)
+}
+
+// 生成 CRL, 使用 CA 私钥签名
+// ca 为颁发者证书, 可用 [*x509.Certificate | *sm2X509.Certificate]
+// number 为 CRL 序号, 需要递增
+func (this CA) CreateCRL(
+    ca any,
+    revokedCerts []RevokedCert,
+    number *big.Int,
+    thisUpdate time.Time,
+    nextUpdate time.Time,
+) CA {
+    return this.createCRL(ca, revokedCerts, number, nil, thisUpdate, nextUpdate)
+}
+
+// 生成增量 CRL, 使用 CA 私钥签名
+// baseNumber 为基础 CRL 的序号, 取消吊销时使用 ReasonRemoveFromCRL
+func (this CA) CreateDeltaCRL(
+    ca any,
+    revokedCerts []RevokedCert,
+    number *big.Int,
+    baseNumber *big.Int,
+    thisUpdate time.Time,
+    nextUpdate time.Time,
+) CA {
+    if baseNumber == nil {
+        err := errors.New("CA: delta CRL base number error.")
+        return this.AppendError(err)
+    }
+
+    return this.createCRL(ca, revokedCerts, number, baseNumber, thisUpdate, nextUpdate)
+}
+
+func (this CA) createCRL(
+    ca any,
+    revokedCerts []RevokedCert,
+    number *big.Int,
+    baseNumber *big.Int,
+    thisUpdate time.Time,
+    nextUpdate time.Time,
+) CA {
+    privateKey, ok := this.privateKey.(crypto.Signer)
+    if !ok {
+        err := errors.New("CA: privateKey error.")
+        return this.AppendError(err)
+    }
+
+    if number == nil {
+        err := errors.New("CA: CRL number error.")
+        return this.AppendError(err)
+    }
+
+    revoked, err := makeRevokedCertificates(revokedCerts)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    var extensions []pkix.Extension
+    if baseNumber != nil {
+        value, err := asn1.Marshal(baseNumber)
+        if err != nil {
+            return this.AppendError(err)
+        }
+
+        extensions = append(extensions, pkix.Extension{
+            Id:       oidExtensionDeltaCRLIndicator,
+            Critical: true,
+            Value:    value,
+        })
+    }
+
+    var crlBytes []byte
+
+    switch issuer := ca.(type) {
+        case *sm2X509.Certificate:
+            crlBytes, err = sm2X509.CreateRevocationList(rand.Reader, &sm2X509.RevocationList{
+                RevokedCertificates: revoked,
+                Number:              number,
+                ThisUpdate:          thisUpdate,
+                NextUpdate:          nextUpdate,
+                ExtraExtensions:     extensions,
+            }, issuer, privateKey)
+
+        case *x509.Certificate:
+            crlBytes, err = x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+                RevokedCertificates: revoked,
+                Number:              number,
+                ThisUpdate:          thisUpdate,
+                NextUpdate:          nextUpdate,
+                ExtraExtensions:     extensions,
+            }, issuer, privateKey)
+
+        default:
+            err = errors.New("CA: ca error.")
+    }
+
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    crlBlock := &pem.Block{
+        Type: "X509 CRL",
+        Bytes: crlBytes,
+    }
+
+    this.keyData = pem.EncodeToMemory(crlBlock)
+
+    return this
+}
+
+// 吊销列表, 吊销原因为 ReasonUnspecified 时不添加原因扩展
+func makeRevokedCertificates(revokedCerts []RevokedCert) ([]pkix.RevokedCertificate, error) {
+    revoked := make([]pkix.RevokedCertificate, 0, len(revokedCerts))
+
+    for _, cert := range revokedCerts {
+        if cert.SerialNumber == nil {
+            return nil, errors.New("CA: revoked cert serial number error.")
+        }
+
+        entry := pkix.RevokedCertificate{
+            SerialNumber:   cert.SerialNumber,
+            RevocationTime: cert.RevocationTime,
+        }
+
+        if cert.ReasonCode != ReasonUnspecified {
+            value, err := asn1.Marshal(asn1.Enumerated(cert.ReasonCode))
+            if err != nil {
+                return nil, err
+            }
+
+            entry.Extensions = []pkix.Extension{
+                {
+                    Id:    oidExtensionReasonCode,
+                    Value: value,
+                },
+            }
+        }
+
+        revoked = append(revoked, entry)
+    }
+
+    return revoked, nil
+}
+
+// 获取吊销原因
+func getReasonCode(entry pkix.RevokedCertificate) int {
+    for _, ext := range entry.Extensions {
+        if ext.Id.Equal(oidExtensionReasonCode) {
+            var reason asn1.Enumerated
+            if _, err := asn1.Unmarshal(ext.Value, &reason); err == nil {
+                return int(reason)
+            }
+        }
+    }
+
+    return ReasonUnspecified
+}
+
+// 已验证的 CRL
+type crlInfo struct {
+    // CRL 序号
+    number *big.Int
+
+    // 增量 CRL 的基础 CRL 序号, 完整 CRL 为 nil
+    baseNumber *big.Int
+
+    // 吊销列表
+    revoked []pkix.RevokedCertificate
+}
+
+// 检查证书是否在吊销列表中
+// 使用序号最大的完整 CRL, 增量 CRL 只使用基础序号不大于该完整 CRL 序号,
+// 且自身序号大于该完整 CRL 序号的, 并按自身序号依次检查
+// 增量 CRL 中原因为 ReasonRemoveFromCRL 的条目只能取消 ReasonCertificateHold 的吊销 (RFC 5280 5.3.1)
+func checkRevoked(serial *big.Int, crls []crlInfo) error {
+    var base *crlInfo
+    var deltas []crlInfo
+
+    for i := range crls {
+        if crls[i].baseNumber == nil {
+            if base == nil || crls[i].number.Cmp(base.number) > 0 {
+                base = &crls[i]
+            }
+        }
+    }
+
+    for _, crl := range crls {
+        if crl.baseNumber == nil {
+            continue
+        }
+
+        if base == nil {
+            return errors.New("CA: delta CRL without complete CRL")
+        }
+
+        if crl.baseNumber.Cmp(base.number) > 0 || crl.number.Cmp(base.number) <= 0 {
+            continue
+        }
+
+        deltas = append(deltas, crl)
+    }
+
+    if base == nil {
+        return nil
+    }
+
+    sort.Slice(deltas, func(i, j int) bool {
+        return deltas[i].number.Cmp(deltas[j].number) < 0
+    })
+
+    var revoked *RevocationError
+
+    check := func(entries []pkix.RevokedCertificate, isDelta bool) {
+        for _, entry := range entries {
+            if entry.SerialNumber == nil || entry.SerialNumber.Cmp(serial) != 0 {
+                continue
+            }
+
+            reason := getReasonCode(entry)
+            if reason == ReasonRemoveFromCRL {
+                if isDelta && revoked != nil && revoked.ReasonCode == ReasonCertificateHold {
+                    revoked = nil
+                }
+
+                continue
+            }
+
+            revoked = &RevocationError{
+                SerialNumber:   serial,
+                RevocationTime: entry.RevocationTime,
+                ReasonCode:     reason,
+            }
+        }
+    }
+
+    check(base.revoked, false)
+    for _, delta := range deltas {
+        check(delta.revoked, true)
+    }
+
+    if revoked != nil {
+        return revoked
+    }
+
+    return nil
+}
+
+// 解析 PEM 或者 DER 编码的 CRL
+func decodeCRL(data string) []byte {
+    if block, _ := pem.Decode([]byte(data)); block != nil {
+        return block.Bytes
+    }
+
+    return []byte(data)
+}
+
+// 获取 CRL 序号扩展, 没有时为 0
+func getCRLNumber(extensions []pkix.Extension) (*big.Int, error) {
+    number, err := getBigIntExtension(extensions, oidExtensionCRLNumber)
+    if err != nil {
+        return nil, err
+    }
+
+    if number == nil {
+        number = big.NewInt(0)
+    }
+
+    return number, nil
+}
+
+// 获取增量 CRL 的基础 CRL 序号, 不是增量 CRL 时为 nil
+func getDeltaCRLIndicator(extensions []pkix.Extension) (*big.Int, error) {
+    return getBigIntExtension(extensions, oidExtensionDeltaCRLIndicator)
+}
+
+func getBigIntExtension(extensions []pkix.Extension, oid asn1.ObjectIdentifier) (*big.Int, error) {
+    for _, ext := range extensions {
+        if ext.Id.Equal(oid) {
+            number := new(big.Int)
+            if rest, err := asn1.Unmarshal(ext.Value, &number); err != nil {
+                return nil, errors.New("CA: failed to parse CRL extension: " + err.Error())
+            } else if len(rest) > 0 {
+                return nil, errors.New("CA: trailing data after CRL extension")
+            }
+
+            return number, nil
+        }
+    }
+
+    return nil, nil
+}
+
+// CRL 签发者的原始数据
+func getCRLRawIssuer(tbsCertList []byte) ([]byte, error) {
+    var tbs struct {
+        Version   int `asn1:"optional,default:0"`
+        Signature pkix.AlgorithmIdentifier
+        Issuer    asn1.RawValue
+    }
+
+    if _, err := asn1.Unmarshal(tbsCertList, &tbs); err != nil {
+        return nil, errors.New("CA: failed to parse CRL issuer: " + err.Error())
+    }
+
+    return tbs.Issuer.FullBytes, nil
+}
@@ -0,0 +1,420 @@
+package ca
+
+import (
+    "time"
+    "errors"
+    "testing"
+    "math/big"
+    "crypto/x509"
+    "encoding/pem"
+    "crypto/x509/pkix"
+
+    sm2X509 "github.com/deatil/go-cryptobin/gm/x509"
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+func parseTestCert(t *testing.T, certPEM string) *x509.Certificate {
+    block, _ := pem.Decode([]byte(certPEM))
+    if block == nil {
+        t.Fatal("decode cert PEM fail")
+    }
+
+    cert, err := x509.ParseCertificate(block.Bytes)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return cert
+}
+
+func parseTestSM2Cert(t *testing.T, certPEM string) *sm2X509.Certificate {
+    block, _ := pem.Decode([]byte(certPEM))
+    if block == nil {
+        t.Fatal("decode cert PEM fail")
+    }
+
+    cert, err := sm2X509.ParseCertificate(block.Bytes)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return cert
+}
+
+func Test_CreateCRL(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertTrue := cryptobin_test.AssertTrueT(t)
+
+    subject := &pkix.Name{
+        CommonName: "test-ca",
+    }
+
+    obj := New().GenerateECDSAKey("P256")
+
+    caObj := obj.MakeCA(subject, 1, "ECDSAWithSHA256").CreateCA()
+    assertError(caObj.Error(), "Test_CreateCRL-CreateCA")
+
+    caPEM := caObj.ToKeyString()
+    caCert := parseTestCert(t, caPEM)
+
+    certObj := obj.MakeCert(&pkix.Name{
+        CommonName: "test-cert",
+    }, 1, []string{"test.com"}, nil, "ECDSAWithSHA256").CreateCert(caCert)
+    assertError(certObj.Error(), "Test_CreateCRL-CreateCert")
+
+    certPEM := certObj.ToKeyString()
+    cert := parseTestCert(t, certPEM)
+
+    now := time.Now()
+    opts := x509.VerifyOptions{
+        KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+    }
+
+    // 未吊销
+    ok, err := obj.VerifyWithCRL(caPEM, certPEM, nil, opts)
+    assertError(err, "Test_CreateCRL-VerifyWithCRL-empty")
+    assertTrue(ok, "Test_CreateCRL-VerifyWithCRL-empty")
+
+    crlObj := obj.CreateCRL(caCert, []RevokedCert{
+        {
+            SerialNumber:   cert.SerialNumber,
+            RevocationTime: now,
+            ReasonCode:     ReasonKeyCompromise,
+        },
+    }, big.NewInt(1), now, now.Add(time.Hour))
+    assertError(crlObj.Error(), "Test_CreateCRL-CreateCRL")
+
+    crlPEM := crlObj.ToKeyString()
+
+    crl, err := x509.ParseRevocationList(decodeCRL(crlPEM))
+    assertError(err, "Test_CreateCRL-ParseRevocationList")
+    assertEqual(crl.Number, big.NewInt(1), "Test_CreateCRL-Number")
+    assertEqual(len(crl.RevokedCertificates), 1, "Test_CreateCRL-RevokedCertificates")
+
+    // 已吊销
+    ok, err = obj.VerifyWithCRL(caPEM, certPEM, []string{crlPEM}, opts)
+    assertNotErrorNil(err, "Test_CreateCRL-VerifyWithCRL-revoked")
+    assertTrue(!ok, "Test_CreateCRL-VerifyWithCRL-revoked")
+
+    var revErr *RevocationError
+    assertTrue(errors.As(err, &revErr), "Test_CreateCRL-RevocationError")
+    if revErr != nil {
+        assertEqual(revErr.ReasonCode, ReasonKeyCompromise, "Test_CreateCRL-ReasonCode")
+        assertEqual(revErr.SerialNumber, cert.SerialNumber, "Test_CreateCRL-SerialNumber")
+    }
+
+    // 增量 CRL 不能取消 ReasonKeyCompromise 的吊销
+    deltaObj := obj.CreateDeltaCRL(caCert, []RevokedCert{
+        {
+            SerialNumber:   cert.SerialNumber,
+            RevocationTime: now,
+            ReasonCode:     ReasonRemoveFromCRL,
+        },
+    }, big.NewInt(2), big.NewInt(1), now, now.Add(time.Hour))
+    assertError(deltaObj.Error(), "Test_CreateCRL-CreateDeltaCRL")
+
+    deltaPEM := deltaObj.ToKeyString()
+
+    ok, err = obj.VerifyWithCRL(caPEM, certPEM, []string{deltaPEM, crlPEM}, opts)
+    assertNotErrorNil(err, "Test_CreateCRL-VerifyWithCRL-delta")
+    assertTrue(!ok, "Test_CreateCRL-VerifyWithCRL-delta")
+
+    // 增量 CRL 取消 ReasonCertificateHold 的吊销
+    holdObj := obj.CreateCRL(caCert, []RevokedCert{
+        {
+            SerialNumber:   cert.SerialNumber,
+            RevocationTime: now,
+            ReasonCode:     ReasonCertificateHold,
+        },
+    }, big.NewInt(1), now, now.Add(time.Hour))
+    assertError(holdObj.Error(), "Test_CreateCRL-CreateCRL-hold")
+
+    ok, err = obj.VerifyWithCRL(caPEM, certPEM, []string{deltaPEM, holdObj.ToKeyString()}, opts)
+    assertError(err, "Test_CreateCRL-VerifyWithCRL-hold")
+    assertTrue(ok, "Test_CreateCRL-VerifyWithCRL-hold")
+
+    // 只有增量 CRL
+    _, err = obj.VerifyWithCRL(caPEM, certPEM, []string{deltaPEM}, opts)
+    assertNotErrorNil(err, "Test_CreateCRL-VerifyWithCRL-delta-only")
+
+    // 过期 CRL
+    opts.CurrentTime = now.Add(2 * time.Hour)
+    _, err = obj.VerifyWithCRL(caPEM, certPEM, []string{crlPEM}, opts)
+    assertNotErrorNil(err, "Test_CreateCRL-VerifyWithCRL-expired")
+}
+
+func Test_CreateSM2CRL(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertTrue := cryptobin_test.AssertTrueT(t)
+
+    subject := &pkix.Name{
+        CommonName: "test-sm2-ca",
+    }
+
+    obj := New().GenerateSM2Key()
+
+    caObj := obj.MakeSM2CA(subject, 1, "SM2WithSM3").CreateCA()
+    assertError(caObj.Error(), "Test_CreateSM2CRL-CreateCA")
+
+    caPEM := caObj.ToKeyString()
+    caCert := parseTestSM2Cert(t, caPEM)
+
+    certObj := obj.MakeSM2Cert(&pkix.Name{
+        CommonName: "test-sm2-cert",
+    }, 1, []string{"test.com"}, nil, "SM2WithSM3").CreateCert(caCert)
+    assertError(certObj.Error(), "Test_CreateSM2CRL-CreateCert")
+
+    certPEM := certObj.ToKeyString()
+    cert := parseTestSM2Cert(t, certPEM)
+
+    now := time.Now()
+    opts := sm2X509.VerifyOptions{
+        KeyUsages: []sm2X509.ExtKeyUsage{sm2X509.ExtKeyUsageAny},
+    }
+
+    crlObj := obj.CreateCRL(caCert, []RevokedCert{
+        {
+            SerialNumber:   cert.SerialNumber,
+            RevocationTime: now,
+            ReasonCode:     ReasonCertificateHold,
+        },
+    }, big.NewInt(1), now, now.Add(time.Hour))
+    assertError(crlObj.Error(), "Test_CreateSM2CRL-CreateCRL")
+
+    crlPEM := crlObj.ToKeyString()
+
+    ok, err := obj.SM2VerifyWithCRL(caPEM, certPEM, []string{crlPEM}, opts)
+    assertNotErrorNil(err, "Test_CreateSM2CRL-SM2VerifyWithCRL-revoked")
+    assertTrue(!ok, "Test_CreateSM2CRL-SM2VerifyWithCRL-revoked")
+
+    var revErr *RevocationError
+    assertTrue(errors.As(err, &revErr), "Test_CreateSM2CRL-RevocationError")
+    if revErr != nil {
+        assertEqual(revErr.ReasonCode, ReasonCertificateHold, "Test_CreateSM2CRL-ReasonCode")
+    }
+
+    deltaObj := obj.CreateDeltaCRL(caCert, []RevokedCert{
+        {
+            SerialNumber:   cert.SerialNumber,
+            RevocationTime: now,
+            ReasonCode:     ReasonRemoveFromCRL,
+        },
+    }, big.NewInt(2), big.NewInt(1), now, now.Add(time.Hour))
+    assertError(deltaObj.Error(), "Test_CreateSM2CRL-CreateDeltaCRL")
+
+    ok, err = obj.SM2VerifyWithCRL(caPEM, certPEM, []string{crlPEM, deltaObj.ToKeyString()}, opts)
+    assertError(err, "Test_CreateSM2CRL-SM2VerifyWithCRL-delta")
+    assertTrue(ok, "Test_CreateSM2CRL-SM2VerifyWithCRL-delta")
+}
+
+func Test_CRLStaleDelta(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertTrue := cryptobin_test.AssertTrueT(t)
+
+    obj := New().GenerateECDSAKey("P256")
+
+    caObj := obj.MakeCA(&pkix.Name{
+        CommonName: "test-ca",
+    }, 1, "ECDSAWithSHA256").CreateCA()
+    assertError(caObj.Error(), "Test_CRLStaleDelta-CreateCA")
+
+    caPEM := caObj.ToKeyString()
+    caCert := parseTestCert(t, caPEM)
+
+    certObj := obj.MakeCert(&pkix.Name{
+        CommonName: "test-cert",
+    }, 1, []string{"test.com"}, nil, "ECDSAWithSHA256").CreateCert(caCert)
+    assertError(certObj.Error(), "Test_CRLStaleDelta-CreateCert")
+
+    certPEM := certObj.ToKeyString()
+    cert := parseTestCert(t, certPEM)
+
+    now := time.Now()
+    opts := x509.VerifyOptions{
+        KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+    }
+
+    makeCRL := func(reason int, number, baseNumber int64) string {
+        revoked := []RevokedCert{
+            {
+                SerialNumber:   cert.SerialNumber,
+                RevocationTime: now,
+                ReasonCode:     reason,
+            },
+        }
+
+        var res CA
+        if baseNumber == 0 {
+            res = obj.CreateCRL(caCert, revoked, big.NewInt(number), now, now.Add(time.Hour))
+        } else {
+            res = obj.CreateDeltaCRL(caCert, revoked, big.NewInt(number), big.NewInt(baseNumber), now, now.Add(time.Hour))
+        }
+
+        assertError(res.Error(), "Test_CRLStaleDelta-CreateCRL")
+
+        return res.ToKeyString()
+    }
+
+    holdCRL := makeCRL(ReasonCertificateHold, 1, 0)
+    keyCompromiseCRL := makeCRL(ReasonKeyCompromise, 3, 0)
+
+    // 旧的增量 CRL, 序号小于完整 CRL
+    staleDelta := makeCRL(ReasonRemoveFromCRL, 2, 1)
+
+    // 基础 CRL 比完整 CRL 新的增量 CRL
+    unrelatedDelta := makeCRL(ReasonRemoveFromCRL, 6, 5)
+
+    tests := [][]string{
+        {staleDelta, keyCompromiseCRL, holdCRL},
+        {holdCRL, staleDelta, keyCompromiseCRL},
+        {keyCompromiseCRL, unrelatedDelta, holdCRL},
+    }
+
+    for _, crls := range tests {
+        ok, err := obj.VerifyWithCRL(caPEM, certPEM, crls, opts)
+        assertNotErrorNil(err, "Test_CRLStaleDelta-VerifyWithCRL")
+        assertTrue(!ok, "Test_CRLStaleDelta-VerifyWithCRL")
+
+        var revErr *RevocationError
+        assertTrue(errors.As(err, &revErr), "Test_CRLStaleDelta-RevocationError")
+        if revErr != nil {
+            assertEqual(revErr.ReasonCode, ReasonKeyCompromise, "Test_CRLStaleDelta-ReasonCode")
+        }
+    }
+
+    // 完整 CRL 为 certificateHold 时, 增量 CRL 可以取消吊销
+    ok, err := obj.VerifyWithCRL(caPEM, certPEM, []string{staleDelta, holdCRL}, opts)
+    assertError(err, "Test_CRLStaleDelta-VerifyWithCRL-hold")
+    assertTrue(ok, "Test_CRLStaleDelta-VerifyWithCRL-hold")
+}
+
+func Test_CRLIntermediate(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertTrue := cryptobin_test.AssertTrueT(t)
+
+    obj := New().GenerateECDSAKey("P256")
+
+    caObj := obj.MakeCA(&pkix.Name{CommonName: "test-root"}, 1, "ECDSAWithSHA256").CreateCA()
+    assertError(caObj.Error(), "Test_CRLIntermediate-CreateCA")
+
+    caPEM := caObj.ToKeyString()
+    caCert := parseTestCert(t, caPEM)
+
+    interObj := obj.MakeCA(&pkix.Name{CommonName: "test-intermediate"}, 1, "ECDSAWithSHA256").CreateCert(caCert)
+    assertError(interObj.Error(), "Test_CRLIntermediate-CreateIntermediate")
+
+    interPEM := interObj.ToKeyString()
+    interCert := parseTestCert(t, interPEM)
+
+    certObj := obj.MakeCert(&pkix.Name{
+        CommonName: "test-cert",
+    }, 1, []string{"test.com"}, nil, "ECDSAWithSHA256").CreateCert(interCert)
+    assertError(certObj.Error(), "Test_CRLIntermediate-CreateCert")
+
+    certPEM := certObj.ToKeyString()
+
+    intermediates := x509.NewCertPool()
+    intermediates.AddCert(interCert)
+
+    now := time.Now()
+    opts := x509.VerifyOptions{
+        Intermediates: intermediates,
+        KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+    }
+
+    // 中间证书的颁发者 CRL 为空
+    rootCRL := obj.CreateCRL(caCert, nil, big.NewInt(1), now, now.Add(time.Hour))
+    assertError(rootCRL.Error(), "Test_CRLIntermediate-CreateCRL-empty")
+
+    ok, err := obj.VerifyWithCRL(caPEM, certPEM, []string{rootCRL.ToKeyString()}, opts)
+    assertError(err, "Test_CRLIntermediate-VerifyWithCRL")
+    assertTrue(ok, "Test_CRLIntermediate-VerifyWithCRL")
+
+    // 中间证书被根证书吊销
+    rootCRL = obj.CreateCRL(caCert, []RevokedCert{
+        {
+            SerialNumber:   interCert.SerialNumber,
+            RevocationTime: now,
+            ReasonCode:     ReasonCACompromise,
+        },
+    }, big.NewInt(2), now, now.Add(time.Hour))
+    assertError(rootCRL.Error(), "Test_CRLIntermediate-CreateCRL")
+
+    ok, err = obj.VerifyWithCRL(caPEM, certPEM, []string{rootCRL.ToKeyString()}, opts)
+    assertNotErrorNil(err, "Test_CRLIntermediate-VerifyWithCRL-revoked")
+    assertTrue(!ok, "Test_CRLIntermediate-VerifyWithCRL-revoked")
+
+    var revErr *RevocationError
+    assertTrue(errors.As(err, &revErr), "Test_CRLIntermediate-RevocationError")
+    if revErr != nil {
+        assertEqual(revErr.SerialNumber, interCert.SerialNumber, "Test_CRLIntermediate-SerialNumber")
+    }
+}
+
+func Test_SM2CRLIntermediate(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertTrue := cryptobin_test.AssertTrueT(t)
+
+    obj := New().GenerateSM2Key()
+
+    caObj := obj.MakeSM2CA(&pkix.Name{CommonName: "test-sm2-root"}, 1, "SM2WithSM3").CreateCA()
+    assertError(caObj.Error(), "Test_SM2CRLIntermediate-CreateCA")
+
+    caPEM := caObj.ToKeyString()
+    caCert := parseTestSM2Cert(t, caPEM)
+
+    interObj := obj.MakeSM2CA(&pkix.Name{CommonName: "test-sm2-intermediate"}, 1, "SM2WithSM3").CreateCert(caCert)
+    assertError(interObj.Error(), "Test_SM2CRLIntermediate-CreateIntermediate")
+
+    interCert := parseTestSM2Cert(t, interObj.ToKeyString())
+
+    certObj := obj.MakeSM2Cert(&pkix.Name{
+        CommonName: "test-sm2-cert",
+    }, 1, []string{"test.com"}, nil, "SM2WithSM3").CreateCert(interCert)
+    assertError(certObj.Error(), "Test_SM2CRLIntermediate-CreateCert")
+
+    certPEM := certObj.ToKeyString()
+
+    intermediates := sm2X509.NewCertPool()
+    intermediates.AddCert(interCert)
+
+    now := time.Now()
+    opts := sm2X509.VerifyOptions{
+        Intermediates: intermediates,
+        KeyUsages:     []sm2X509.ExtKeyUsage{sm2X509.ExtKeyUsageAny},
+    }
+
+    ok, err := obj.SM2VerifyWithCRL(caPEM, certPEM, nil, opts)
+    assertError(err, "Test_SM2CRLIntermediate-SM2VerifyWithCRL")
+    assertTrue(ok, "Test_SM2CRLIntermediate-SM2VerifyWithCRL")
+
+    // 中间证书被根证书吊销
+    rootCRL := obj.CreateCRL(caCert, []RevokedCert{
+        {
+            SerialNumber:   interCert.SerialNumber,
+            RevocationTime: now,
+            ReasonCode:     ReasonCACompromise,
+        },
+    }, big.NewInt(1), now, now.Add(time.Hour))
+    assertError(rootCRL.Error(), "Test_SM2CRLIntermediate-CreateCRL")
+
+    ok, err = obj.SM2VerifyWithCRL(caPEM, certPEM, []string{rootCRL.ToKeyString()}, opts)
+    assertNotErrorNil(err, "Test_SM2CRLIntermediate-SM2VerifyWithCRL-revoked")
+    assertTrue(!ok, "Test_SM2CRLIntermediate-SM2VerifyWithCRL-revoked")
+
+    var revErr *RevocationError
+    assertTrue(errors.As(err, &revErr), "Test_SM2CRLIntermediate-RevocationError")
+    if revErr != nil {
+        assertEqual(revErr.SerialNumber, interCert.SerialNumber, "Test_SM2CRLIntermediate-SerialNumber")
+    }
+}
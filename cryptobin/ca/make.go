@@ -64,7 +64,7 @@ func (this CA) MakeCA(
             x509.ExtKeyUsageServerAuth,
         },
         // openssl 中的 keyUsage 字段
-        KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+        KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
 
         // 表示用于CA
         IsCA:                  true,
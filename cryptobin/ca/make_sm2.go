@@ -65,7 +65,7 @@ func (this CA) MakeSM2CA(
             x509.ExtKeyUsageServerAuth,
         },
         // openssl 中的 keyUsage 字段
-        KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+        KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
 
         // 表示用于CA
         IsCA:                  true,
@@ -1,8 +1,12 @@
 package ca
 
 import (
+    "time"
+    "bytes"
     "errors"
+    "math/big"
     "crypto/x509"
+    "crypto/x509/pkix"
     "encoding/pem"
 
     sm2X509 "github.com/deatil/go-cryptobin/gm/x509"
@@ -63,3 +67,197 @@ func (this CA) SM2Verify(rootPEM string, certPEM string, opts sm2X509.VerifyOpti
 
     return true, nil
 }
+
+// 验证, 并使用 CRL 检查证书是否被吊销
+// crls 为 PEM 或者 DER 编码的 CRL, 证书链中除根证书外的证书都使用其颁发者签发的 CRL 检查
+// 证书被吊销时返回 *RevocationError
+func (this CA) VerifyWithCRL(rootPEM string, certPEM string, crls []string, opts x509.VerifyOptions) (bool, error) {
+    roots := x509.NewCertPool()
+    ok := roots.AppendCertsFromPEM([]byte(rootPEM))
+    if !ok {
+        return false, errors.New("CA: failed to parse root certificate")
+    }
+
+    block, _ := pem.Decode([]byte(certPEM))
+    if block == nil {
+        return false, errors.New("CA: failed to parse certificate PEM")
+    }
+
+    cert, err := x509.ParseCertificate(block.Bytes)
+    if err != nil {
+        return false, errors.New("CA: failed to parse certificate: " + err.Error())
+    }
+
+    // 重设
+    opts.Roots = roots
+
+    chains, err := cert.Verify(opts)
+    if err != nil {
+        return false, errors.New("CA: failed to verify certificate: " + err.Error())
+    }
+
+    revocationLists := make([]*x509.RevocationList, 0, len(crls))
+    for _, data := range crls {
+        crl, err := x509.ParseRevocationList(decodeCRL(data))
+        if err != nil {
+            return false, errors.New("CA: failed to parse CRL: " + err.Error())
+        }
+
+        revocationLists = append(revocationLists, crl)
+    }
+
+    now := opts.CurrentTime
+    if now.IsZero() {
+        now = time.Now()
+    }
+
+    // 有一条证书链没有被吊销的证书即可
+    for _, chain := range chains {
+        err = nil
+        for i := 0; i < len(chain) - 1 && err == nil; i++ {
+            err = checkCRLs(chain[i], chain[i+1], revocationLists, now)
+        }
+
+        if err == nil {
+            return true, nil
+        }
+    }
+
+    return false, err
+}
+
+// SM2 验证, 并使用 CRL 检查证书是否被吊销
+// crls 为 PEM 或者 DER 编码的 CRL, 证书链中除根证书外的证书都使用其颁发者签发的 CRL 检查
+// 证书被吊销时返回 *RevocationError
+func (this CA) SM2VerifyWithCRL(rootPEM string, certPEM string, crls []string, opts sm2X509.VerifyOptions) (bool, error) {
+    roots := sm2X509.NewCertPool()
+    ok := roots.AppendCertsFromPEM([]byte(rootPEM))
+    if !ok {
+        return false, errors.New("CA: failed to parse root certificate")
+    }
+
+    block, _ := pem.Decode([]byte(certPEM))
+    if block == nil {
+        return false, errors.New("CA: failed to parse certificate PEM")
+    }
+
+    cert, err := sm2X509.ParseCertificate(block.Bytes)
+    if err != nil {
+        return false, errors.New("CA: failed to parse certificate: " + err.Error())
+    }
+
+    // 重设
+    opts.Roots = roots
+
+    chains, err := cert.Verify(opts)
+    if err != nil {
+        return false, errors.New("CA: failed to verify certificate: " + err.Error())
+    }
+
+    certLists := make([]*pkix.CertificateList, 0, len(crls))
+    for _, data := range crls {
+        crl, err := sm2X509.ParseDERCRL(decodeCRL(data))
+        if err != nil {
+            return false, errors.New("CA: failed to parse CRL: " + err.Error())
+        }
+
+        certLists = append(certLists, crl)
+    }
+
+    now := opts.CurrentTime
+    if now.IsZero() {
+        now = time.Now()
+    }
+
+    // 有一条证书链没有被吊销的证书即可
+    for _, chain := range chains {
+        err = nil
+        for i := 0; i < len(chain) - 1 && err == nil; i++ {
+            err = checkSM2CRLs(chain[i], chain[i+1], certLists, now)
+        }
+
+        if err == nil {
+            return true, nil
+        }
+    }
+
+    return false, err
+}
+
+// 使用颁发者签发的 CRL 检查证书是否被吊销
+func checkCRLs(cert, issuer *x509.Certificate, crls []*x509.RevocationList, now time.Time) error {
+    var crlInfos []crlInfo
+    for _, crl := range crls {
+        if !bytes.Equal(crl.RawIssuer, issuer.RawSubject) {
+            continue
+        }
+
+        if err := crl.CheckSignatureFrom(issuer); err != nil {
+            return errors.New("CA: failed to verify CRL: " + err.Error())
+        }
+
+        if !crl.NextUpdate.IsZero() && now.After(crl.NextUpdate) {
+            return errors.New("CA: CRL has expired")
+        }
+
+        number := crl.Number
+        if number == nil {
+            number = big.NewInt(0)
+        }
+
+        baseNumber, err := getDeltaCRLIndicator(crl.Extensions)
+        if err != nil {
+            return err
+        }
+
+        crlInfos = append(crlInfos, crlInfo{
+            number:     number,
+            baseNumber: baseNumber,
+            revoked:    crl.RevokedCertificates,
+        })
+    }
+
+    return checkRevoked(cert.SerialNumber, crlInfos)
+}
+
+// 使用颁发者签发的 CRL 检查 SM2 证书是否被吊销
+func checkSM2CRLs(cert, issuer *sm2X509.Certificate, crls []*pkix.CertificateList, now time.Time) error {
+    var crlInfos []crlInfo
+    for _, crl := range crls {
+        rawIssuer, err := getCRLRawIssuer(crl.TBSCertList.Raw)
+        if err != nil {
+            return err
+        }
+
+        if !bytes.Equal(rawIssuer, issuer.RawSubject) {
+            continue
+        }
+
+        if err := issuer.CheckCRLSignature(crl); err != nil {
+            return errors.New("CA: failed to verify CRL: " + err.Error())
+        }
+
+        nextUpdate := crl.TBSCertList.NextUpdate
+        if !nextUpdate.IsZero() && now.After(nextUpdate) {
+            return errors.New("CA: CRL has expired")
+        }
+
+        number, err := getCRLNumber(crl.TBSCertList.Extensions)
+        if err != nil {
+            return err
+        }
+
+        baseNumber, err := getDeltaCRLIndicator(crl.TBSCertList.Extensions)
+        if err != nil {
+            return err
+        }
+
+        crlInfos = append(crlInfos, crlInfo{
+            number:     number,
+            baseNumber: baseNumber,
+            revoked:    crl.TBSCertList.RevokedCertificates,
+        })
+    }
+
+    return checkRevoked(cert.SerialNumber, crlInfos)
+}
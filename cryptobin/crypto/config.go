@@ -50,3 +50,15 @@ func (this Config) Padding() Padding {
 func (this Config) Config() *tool.Config {
     return this.crypto.config
 }
+
+// 替换密钥的配置
+type keyOption struct {
+    IOption
+
+    key []byte
+}
+
+// 密钥
+func (this keyOption) Key() []byte {
+    return this.key
+}
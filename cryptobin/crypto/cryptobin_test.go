@@ -2,6 +2,7 @@ package crypto
 
 import (
     "testing"
+    "encoding/hex"
 
     cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
 )
@@ -1837,6 +1838,448 @@ func Test_AesHCTR(t *testing.T) {
     assert(data, cyptdeStr, "AesHCTR")
 }
 
+func Test_AesSIV(t *testing.T) {
+    assert := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    // RFC 5297 A.1
+    key, _ := hex.DecodeString("fffefdfcfbfaf9f8f7f6f5f4f3f2f1f0f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff")
+    ad, _ := hex.DecodeString("101112131415161718191a1b1c1d1e1f2021222324252627")
+    plain, _ := hex.DecodeString("112233445566778899aabbccddee")
+    check := "85632d07c6e8f37f950acd320a2ecc9340c02b9690c4dc04daef7f6afe5c"
+
+    cypt := FromBytes(plain).
+        WithKey(key[16:]).
+        Aes().
+        SIV(key[:16], ad).
+        Encrypt()
+    assertError(cypt.Error(), "AesSIV-Encode")
+    assert(check, cypt.ToHexString(), "AesSIV-Encode")
+
+    cyptde := FromHexString(check).
+        WithKey(key[16:]).
+        Aes().
+        SIV(key[:16], ad).
+        Decrypt()
+    assertError(cyptde.Error(), "AesSIV-Decode")
+    assert(plain, cyptde.ToBytes(), "AesSIV-Decode")
+
+    cyptde2 := FromHexString(check).
+        WithKey(key[16:]).
+        Aes().
+        SIV(key[:16], []byte("wrong")).
+        Decrypt()
+    if cyptde2.Error() == nil {
+        t.Error("AesSIV-Decode should fail with wrong additional")
+    }
+}
+
+func Test_SIV(t *testing.T) {
+    assert := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    key := "dfertf12dfertf12"
+    macKey := []byte("11injkijkol22plo")
+    data := "test-pass"
+
+    cases := []Cryptobin{
+        New().SetKey(key).Aes(),
+        New().SetKey(key).SM4(),
+        New().SetKey(key).Aria(),
+        New().SetKey(key).Camellia(),
+    }
+
+    for _, c := range cases {
+        name := c.GetMultiple().String() + "-SIV"
+
+        cypt := c.FromString(data).
+            SIV(macKey, []byte("ad1"), []byte("ad2")).
+            Encrypt()
+        assertError(cypt.Error(), name + "-Encode")
+
+        // deterministic
+        cypt2 := c.FromString(data).
+            SIV(macKey, []byte("ad1"), []byte("ad2")).
+            Encrypt()
+        assert(cypt.ToHexString(), cypt2.ToHexString(), name + "-Deterministic")
+
+        cyptde := c.FromBytes(cypt.ToBytes()).
+            SIV(macKey, []byte("ad1"), []byte("ad2")).
+            Decrypt()
+        assertError(cyptde.Error(), name + "-Decode")
+        assert(data, cyptde.ToString(), name)
+
+        cyptde2 := c.FromBytes(cypt.ToBytes()).
+            ModeBy(SIV, map[string]any{
+                "siv_key":    macKey,
+                "additional": [][]byte{[]byte("ad1"), []byte("ad2")},
+            }).
+            Decrypt()
+        assertError(cyptde2.Error(), name + "-ModeBy-Decode")
+        assert(data, cyptde2.ToString(), name + "-ModeBy")
+    }
+}
+
+func Test_AesGCMSIV(t *testing.T) {
+    assert := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    // RFC 8452 C.1
+    key, _ := hex.DecodeString("01000000000000000000000000000000")
+    nonce, _ := hex.DecodeString("030000000000000000000000")
+    plain, _ := hex.DecodeString("0100000000000000")
+    check := "b5d839330ac7b786578782fff6013b815b287c22493a364c"
+
+    cypt := FromBytes(plain).
+        WithKey(key).
+        Aes().
+        GCMSIV(string(nonce)).
+        Encrypt()
+    assertError(cypt.Error(), "AesGCMSIV-Encode")
+    assert(check, cypt.ToHexString(), "AesGCMSIV-Encode")
+
+    cyptde := FromHexString(check).
+        WithKey(key).
+        Aes().
+        GCMSIV(string(nonce)).
+        Decrypt()
+    assertError(cyptde.Error(), "AesGCMSIV-Decode")
+    assert(plain, cyptde.ToBytes(), "AesGCMSIV-Decode")
+}
+
+func Test_GCMSIV(t *testing.T) {
+    assert := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    key := "dfertf12dfertf12"
+    nonce := "nonce1234567"
+    data := "test-pass"
+
+    cases := []Cryptobin{
+        New().SetKey(key).Aes(),
+        New().SetKey(key).SM4(),
+        New().SetKey(key).Aria(),
+        New().SetKey(key).Camellia(),
+    }
+
+    for _, c := range cases {
+        name := c.GetMultiple().String() + "-GCMSIV"
+
+        cypt := c.FromString(data).
+            GCMSIV(nonce, "additional").
+            Encrypt()
+        assertError(cypt.Error(), name + "-Encode")
+
+        cyptde := c.FromBytes(cypt.ToBytes()).
+            ModeBy(GCMSIV, map[string]any{
+                "nonce":      []byte(nonce),
+                "additional": []byte("additional"),
+            }).
+            Decrypt()
+        assertError(cyptde.Error(), name + "-Decode")
+        assert(data, cyptde.ToString(), name)
+
+        cyptde2 := c.FromBytes(cypt.ToBytes()).
+            GCMSIV(nonce, "wrong").
+            Decrypt()
+        if cyptde2.Error() == nil {
+            t.Error(name + "-Decode should fail with wrong additional")
+        }
+    }
+}
+
+func Test_AesKW(t *testing.T) {
+    assert := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    // RFC 3394 4.1
+    kek, _ := hex.DecodeString("000102030405060708090A0B0C0D0E0F")
+    key, _ := hex.DecodeString("00112233445566778899AABBCCDDEEFF")
+    check := "1fa68b0a8112b447aef34bd8fb5a7b829d3e862371d2cfe5"
+
+    cypt := FromBytes(key).
+        WithKey(kek).
+        Aes().
+        KW().
+        Encrypt()
+    assertError(cypt.Error(), "AesKW-Encode")
+    assert(check, cypt.ToHexString(), "AesKW-Encode")
+
+    cyptde := FromHexString(check).
+        WithKey(kek).
+        Aes().
+        ModeBy(KW).
+        Decrypt()
+    assertError(cyptde.Error(), "AesKW-Decode")
+    assert(key, cyptde.ToBytes(), "AesKW-Decode")
+}
+
+func Test_AesKWP(t *testing.T) {
+    assert := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    // RFC 5649 Section 6
+    kek, _ := hex.DecodeString("5840df6e29b02af1ab493b705bf16ea1ae8338f4dcc176a8")
+    key, _ := hex.DecodeString("466f7250617369")
+    check := "afbeb0f07dfbf5419200f2ccb50bb24f"
+
+    cypt := FromBytes(key).
+        WithKey(kek).
+        Aes().
+        KWP().
+        Encrypt()
+    assertError(cypt.Error(), "AesKWP-Encode")
+    assert(check, cypt.ToHexString(), "AesKWP-Encode")
+
+    cyptde := FromHexString(check).
+        WithKey(kek).
+        Aes().
+        KWP().
+        Decrypt()
+    assertError(cyptde.Error(), "AesKWP-Decode")
+    assert(key, cyptde.ToBytes(), "AesKWP-Decode")
+}
+
+func Test_SM4KW(t *testing.T) {
+    assert := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    kek := "dfertf12dfertf12"
+    data := "key-data-1234567"
+
+    for _, mode := range []Mode{KW, KWP} {
+        name := "SM4-" + mode.String()
+
+        cypt := FromString(data).
+            SetKey(kek).
+            SM4().
+            ModeBy(mode).
+            Encrypt()
+        assertError(cypt.Error(), name + "-Encode")
+
+        cyptde := FromBytes(cypt.ToBytes()).
+            SetKey(kek).
+            SM4().
+            ModeBy(mode).
+            Decrypt()
+        assertError(cyptde.Error(), name + "-Decode")
+        assert(data, cyptde.ToString(), name)
+
+        cyptde2 := FromBytes(cypt.ToBytes()).
+            SetKey("dfertf12dfertf13").
+            SM4().
+            ModeBy(mode).
+            Decrypt()
+        if cyptde2.Error() == nil {
+            t.Error(name + "-Decode should fail with wrong kek")
+        }
+    }
+}
+
+func Test_AesFPE(t *testing.T) {
+    assert := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    // NIST SP 800-38G FF1 Sample 2
+    key, _ := hex.DecodeString("2B7E151628AED2A6ABF7158809CF4F3C")
+    tweak, _ := hex.DecodeString("39383736353433323130")
+
+    cypt := FromString("0123456789").
+        WithKey(key).
+        Aes().
+        FPE("0123456789", tweak).
+        Encrypt()
+    assertError(cypt.Error(), "AesFPE-Encode")
+    assert("6124200773", cypt.ToString(), "AesFPE-Encode")
+
+    cyptde := FromString("6124200773").
+        WithKey(key).
+        Aes().
+        FPE("0123456789", tweak).
+        Decrypt()
+    assertError(cyptde.Error(), "AesFPE-Decode")
+    assert("0123456789", cyptde.ToString(), "AesFPE-Decode")
+}
+
+func Test_FPE(t *testing.T) {
+    assert := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    key := "dfertf12dfertf12"
+    alphabet := "0123456789X"
+    tweak := []byte("tweak12")
+    data := "11010519491231002X"
+
+    for _, mode := range []Mode{FF1, FF31} {
+        cases := []Cryptobin{
+            New().SetKey(key).Aes(),
+            New().SetKey(key).SM4(),
+            New().SetKey(key).Aria(),
+        }
+
+        for _, c := range cases {
+            name := c.GetMultiple().String() + "-" + mode.String()
+
+            cfg := map[string]any{
+                "alphabet": alphabet,
+                "tweak":    tweak,
+            }
+
+            cypt := c.FromString(data).
+                ModeBy(mode, cfg).
+                Encrypt()
+            assertError(cypt.Error(), name + "-Encode")
+            assert(len(data), len(cypt.ToString()), name + "-Len")
+
+            cyptde := c.FromBytes(cypt.ToBytes()).
+                ModeBy(mode, cfg).
+                Decrypt()
+            assertError(cyptde.Error(), name + "-Decode")
+            assert(data, cyptde.ToString(), name)
+        }
+    }
+
+    cypt := FromString("abc-123").
+        SetKey(key).
+        SM4().
+        FPE(alphabet, tweak).
+        Encrypt()
+    if cypt.Error() == nil {
+        t.Error("FPE should fail with characters not in alphabet")
+    }
+}
+
+func Test_AesXTS(t *testing.T) {
+    assert := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    // IEEE 1619 Vector 15
+    key1, _ := hex.DecodeString("fffefdfcfbfaf9f8f7f6f5f4f3f2f1f0")
+    key2, _ := hex.DecodeString("bfbebdbcbbbab9b8b7b6b5b4b3b2b1b0")
+    plain, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f10")
+    check := "6c1625db4671522d3d7599601de7ca09ed"
+
+    cypt := FromBytes(plain).
+        WithKey(key1).
+        Aes().
+        XTS(key2, 0x123456789a).
+        Encrypt()
+    assertError(cypt.Error(), "AesXTS-Encode")
+    assert(check, cypt.ToHexString(), "AesXTS-Encode")
+
+    cyptde := FromHexString(check).
+        WithKey(key1).
+        Aes().
+        XTS(key2, 0x123456789a).
+        Decrypt()
+    assertError(cyptde.Error(), "AesXTS-Decode")
+    assert(plain, cyptde.ToBytes(), "AesXTS-Decode")
+}
+
+func Test_XTSSectors(t *testing.T) {
+    assert := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    key := "dfertf12dfertf12"
+    tweakKey := []byte("11injkijkol22plo")
+
+    data := make([]byte, 512*3 + 100)
+    for i := range data {
+        data[i] = byte(i)
+    }
+
+    cases := []Cryptobin{
+        New().SetKey(key).SM4(),
+        New().SetKey(key).Camellia(),
+        New().SetKey(key).Serpent(),
+    }
+
+    for _, c := range cases {
+        name := c.GetMultiple().String() + "-XTS"
+
+        cypt := c.FromBytes(data).
+            XTS(tweakKey, 10, 512).
+            Encrypt()
+        assertError(cypt.Error(), name + "-Encode")
+        assert(len(data), len(cypt.ToBytes()), name + "-Len")
+
+        // 每个扇区可以单独解密
+        sector := c.FromBytes(cypt.ToBytes()[512:1024]).
+            XTS(tweakKey, 11).
+            Decrypt()
+        assertError(sector.Error(), name + "-Sector")
+        assert(data[512:1024], sector.ToBytes(), name + "-Sector")
+
+        cyptde := c.FromBytes(cypt.ToBytes()).
+            ModeBy(XTS, map[string]any{
+                "tweak_key":   tweakKey,
+                "sector_num":  uint64(10),
+                "sector_size": 512,
+            }).
+            Decrypt()
+        assertError(cyptde.Error(), name + "-Decode")
+        assert(data, cyptde.ToBytes(), name)
+    }
+}
+
+func Test_AesLRW(t *testing.T) {
+    assert := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    // IEEE P1619 LRW-32-AES 1
+    key1, _ := hex.DecodeString("4562ac25f828176d4c268414b5680185")
+    key2, _ := hex.DecodeString("258e2a05e73e9d03ee5a830ccc094c87")
+    index, _ := hex.DecodeString("00000000000000000000000000000001")
+    check := "f1b273cd65a3df5fe95d489254634eb8"
+
+    cypt := FromString("0123456789ABCDEF").
+        WithKey(key1).
+        Aes().
+        LRW(key2, index).
+        Encrypt()
+    assertError(cypt.Error(), "AesLRW-Encode")
+    assert(check, cypt.ToHexString(), "AesLRW-Encode")
+
+    cyptde := FromHexString(check).
+        WithKey(key1).
+        Aes().
+        LRW(key2, index).
+        Decrypt()
+    assertError(cyptde.Error(), "AesLRW-Decode")
+    assert("0123456789ABCDEF", cyptde.ToString(), "AesLRW-Decode")
+}
+
+func Test_HCTR2(t *testing.T) {
+    assert := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    key := "dfertf12dfertf12"
+    tweak := []byte("kkinjkijeel2pass")
+    data := "test-pass-test-pass-test-pass"
+
+    cases := []Cryptobin{
+        New().SetKey(key).Aes(),
+        New().SetKey(key).SM4(),
+        New().SetKey(key).Camellia(),
+    }
+
+    for _, c := range cases {
+        name := c.GetMultiple().String() + "-HCTR2"
+
+        cypt := c.FromString(data).
+            HCTR2(tweak).
+            Encrypt()
+        assertError(cypt.Error(), name + "-Encode")
+        assert(len(data), len(cypt.ToBytes()), name + "-Len")
+
+        cyptde := c.FromBytes(cypt.ToBytes()).
+            HCTR2(tweak).
+            Decrypt()
+        assertError(cyptde.Error(), name + "-Decode")
+        assert(data, cyptde.ToString(), name)
+    }
+}
+
 func Test_PresentPKCS7Padding(t *testing.T) {
     assert := cryptobin_test.AssertEqualT(t)
     assertError := cryptobin_test.AssertErrorT(t)
@@ -1,6 +1,7 @@
 package crypto
 
 import (
+    "io"
     "fmt"
 )
 
@@ -73,3 +74,39 @@ func (this Cryptobin) FuncEncrypt(f func(Cryptobin) Cryptobin) Cryptobin {
 func (this Cryptobin) FuncDecrypt(f func(Cryptobin) Cryptobin) Cryptobin {
     return f(this).triggerError()
 }
+
+// ====================
+
+// 流式加密
+// 从 r 读取数据, 加密后写入 w
+func (this Cryptobin) EncryptTo(w io.Writer, r io.Reader) Cryptobin {
+    // 加密解密
+    newEncrypt, err := getEncrypt(this.multiple)
+    if err != nil {
+        return this.AppendError(err).triggerError()
+    }
+
+    err = StreamEncrypt(newEncrypt, w, r, NewConfig(this))
+    if err != nil {
+        return this.AppendError(err).triggerError()
+    }
+
+    return this.triggerError()
+}
+
+// 流式解密
+// 从 r 读取数据, 解密后写入 w
+func (this Cryptobin) DecryptFrom(w io.Writer, r io.Reader) Cryptobin {
+    // 加密解密
+    newEncrypt, err := getEncrypt(this.multiple)
+    if err != nil {
+        return this.AppendError(err).triggerError()
+    }
+
+    err = StreamDecrypt(newEncrypt, w, r, NewConfig(this))
+    if err != nil {
+        return this.AppendError(err).triggerError()
+    }
+
+    return this.triggerError()
+}
@@ -9,7 +9,14 @@ import (
     "github.com/deatil/go-cryptobin/cipher/ocb"
     "github.com/deatil/go-cryptobin/cipher/eax"
     "github.com/deatil/go-cryptobin/cipher/ccm"
+    "github.com/deatil/go-cryptobin/cipher/fpe"
+    "github.com/deatil/go-cryptobin/cipher/lrw"
+    "github.com/deatil/go-cryptobin/cipher/siv"
+    "github.com/deatil/go-cryptobin/cipher/xts"
     "github.com/deatil/go-cryptobin/cipher/hctr"
+    "github.com/deatil/go-cryptobin/cipher/hctr2"
+    "github.com/deatil/go-cryptobin/cipher/gcmsiv"
+    "github.com/deatil/go-cryptobin/cipher/keywrap"
     cryptobin_cipher "github.com/deatil/go-cryptobin/cipher"
 )
 
@@ -31,6 +38,16 @@ func (this ModeECB) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byt
     return dst, nil
 }
 
+// 流式加密
+func (this ModeECB) EncryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    return cryptobin_cipher.NewECBEncrypter(block), nil
+}
+
+// 流式解密
+func (this ModeECB) DecryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    return cryptobin_cipher.NewECBDecrypter(block), nil
+}
+
 // ===================
 
 type ModeCBC struct {}
@@ -57,6 +74,22 @@ func (this ModeCBC) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byt
     return dst, nil
 }
 
+// 流式加密
+func (this ModeCBC) EncryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return cipher.NewCBCEncrypter(block, iv), nil
+}
+
+// 流式解密
+func (this ModeCBC) DecryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return cipher.NewCBCDecrypter(block, iv), nil
+}
+
 // ===================
 
 type ModePCBC struct {}
@@ -83,6 +116,22 @@ func (this ModePCBC) Decrypt(data []byte, block cipher.Block, opt IOption) ([]by
     return dst, nil
 }
 
+// 流式加密
+func (this ModePCBC) EncryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return cryptobin_cipher.NewPCBCEncrypter(block, iv), nil
+}
+
+// 流式解密
+func (this ModePCBC) DecryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return cryptobin_cipher.NewPCBCDecrypter(block, iv), nil
+}
+
 // ===================
 
 type ModeCFB struct {}
@@ -109,6 +158,22 @@ func (this ModeCFB) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byt
     return dst, nil
 }
 
+// 流式加密
+func (this ModeCFB) EncryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cipher.NewCFBEncrypter(block, iv)), nil
+}
+
+// 流式解密
+func (this ModeCFB) DecryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cipher.NewCFBDecrypter(block, iv)), nil
+}
+
 // ===================
 
 type ModeCFB1 struct {}
@@ -135,6 +200,22 @@ func (this ModeCFB1) Decrypt(data []byte, block cipher.Block, opt IOption) ([]by
     return dst, nil
 }
 
+// 流式加密
+func (this ModeCFB1) EncryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cryptobin_cipher.NewCFB1Encrypter(block, iv)), nil
+}
+
+// 流式解密
+func (this ModeCFB1) DecryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cryptobin_cipher.NewCFB1Decrypter(block, iv)), nil
+}
+
 // ===================
 
 type ModeCFB8 struct {}
@@ -161,6 +242,22 @@ func (this ModeCFB8) Decrypt(data []byte, block cipher.Block, opt IOption) ([]by
     return dst, nil
 }
 
+// 流式加密
+func (this ModeCFB8) EncryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cryptobin_cipher.NewCFB8Encrypter(block, iv)), nil
+}
+
+// 流式解密
+func (this ModeCFB8) DecryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cryptobin_cipher.NewCFB8Decrypter(block, iv)), nil
+}
+
 // ===================
 
 type ModeCFB16 struct {}
@@ -187,6 +284,22 @@ func (this ModeCFB16) Decrypt(data []byte, block cipher.Block, opt IOption) ([]b
     return dst, nil
 }
 
+// 流式加密
+func (this ModeCFB16) EncryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cryptobin_cipher.NewCFB16Encrypter(block, iv)), nil
+}
+
+// 流式解密
+func (this ModeCFB16) DecryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cryptobin_cipher.NewCFB16Decrypter(block, iv)), nil
+}
+
 // ===================
 
 type ModeCFB32 struct {}
@@ -213,6 +326,22 @@ func (this ModeCFB32) Decrypt(data []byte, block cipher.Block, opt IOption) ([]b
     return dst, nil
 }
 
+// 流式加密
+func (this ModeCFB32) EncryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cryptobin_cipher.NewCFB32Encrypter(block, iv)), nil
+}
+
+// 流式解密
+func (this ModeCFB32) DecryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cryptobin_cipher.NewCFB32Decrypter(block, iv)), nil
+}
+
 // ===================
 
 type ModeCFB64 struct {}
@@ -239,6 +368,22 @@ func (this ModeCFB64) Decrypt(data []byte, block cipher.Block, opt IOption) ([]b
     return dst, nil
 }
 
+// 流式加密
+func (this ModeCFB64) EncryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cryptobin_cipher.NewCFB64Encrypter(block, iv)), nil
+}
+
+// 流式解密
+func (this ModeCFB64) DecryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cryptobin_cipher.NewCFB64Decrypter(block, iv)), nil
+}
+
 // ===================
 
 type ModeOFB struct {}
@@ -265,6 +410,22 @@ func (this ModeOFB) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byt
     return dst, nil
 }
 
+// 流式加密
+func (this ModeOFB) EncryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cipher.NewOFB(block, iv)), nil
+}
+
+// 流式解密
+func (this ModeOFB) DecryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cipher.NewOFB(block, iv)), nil
+}
+
 // ===================
 
 type ModeOFB8 struct {}
@@ -291,6 +452,22 @@ func (this ModeOFB8) Decrypt(data []byte, block cipher.Block, opt IOption) ([]by
     return dst, nil
 }
 
+// 流式加密
+func (this ModeOFB8) EncryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cryptobin_cipher.NewOFB8(block, iv)), nil
+}
+
+// 流式解密
+func (this ModeOFB8) DecryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cryptobin_cipher.NewOFB8(block, iv)), nil
+}
+
 // ===================
 
 type ModeCTR struct {}
@@ -317,10 +494,47 @@ func (this ModeCTR) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byt
     return dst, nil
 }
 
+// 流式加密
+func (this ModeCTR) EncryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cipher.NewCTR(block, iv)), nil
+}
+
+// 流式解密
+func (this ModeCTR) DecryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cipher.NewCTR(block, iv)), nil
+}
+
 // ===================
 
 type ModeGCM struct {}
 
+// AEAD
+// 没有设置 nonce 时使用默认 nonce 长度
+func (this ModeGCM) AEAD(block cipher.Block, opt IOption) (cipher.AEAD, error) {
+    var aead cipher.AEAD
+    var err error
+
+    nonceBytes := opt.Config().GetBytes("nonce")
+    if nonceBytes == nil {
+        aead, err = cipher.NewGCM(block)
+    } else {
+        aead, err = cipher.NewGCMWithNonceSize(block, len(nonceBytes))
+    }
+
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return aead, nil
+}
+
 // 加密
 func (this ModeGCM) Encrypt(plain []byte, block cipher.Block, opt IOption) ([]byte, error) {
     nonceBytes := opt.Config().GetBytes("nonce")
@@ -329,9 +543,8 @@ func (this ModeGCM) Encrypt(plain []byte, block cipher.Block, opt IOption) ([]by
         return nil, err
     }
 
-    aead, err := cipher.NewGCMWithNonceSize(block, len(nonceBytes))
+    aead, err := this.AEAD(block, opt)
     if err != nil {
-        err = fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -350,9 +563,8 @@ func (this ModeGCM) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byt
         return nil, err
     }
 
-    aead, err := cipher.NewGCMWithNonceSize(block, len(nonceBytes))
+    aead, err := this.AEAD(block, opt)
     if err != nil {
-        err = fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -367,6 +579,27 @@ func (this ModeGCM) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byt
 
 type ModeCCM struct {}
 
+// AEAD
+// 没有设置 nonce 时使用默认 nonce 长度
+func (this ModeCCM) AEAD(block cipher.Block, opt IOption) (cipher.AEAD, error) {
+    var aead cipher.AEAD
+    var err error
+
+    nonceBytes := opt.Config().GetBytes("nonce")
+    if nonceBytes == nil {
+        aead, err = ccm.NewCCM(block)
+    } else {
+        aead, err = ccm.NewCCMWithNonceSize(block, len(nonceBytes))
+    }
+
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return aead, nil
+}
+
 // 加密
 func (this ModeCCM) Encrypt(plain []byte, block cipher.Block, opt IOption) ([]byte, error) {
     nonceBytes := opt.Config().GetBytes("nonce")
@@ -375,9 +608,8 @@ func (this ModeCCM) Encrypt(plain []byte, block cipher.Block, opt IOption) ([]by
         return nil, err
     }
 
-    aead, err := ccm.NewCCMWithNonceSize(block, len(nonceBytes))
+    aead, err := this.AEAD(block, opt)
     if err != nil {
-        err = fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -397,9 +629,8 @@ func (this ModeCCM) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byt
         return nil, err
     }
 
-    aead, err := ccm.NewCCMWithNonceSize(block, len(nonceBytes))
+    aead, err := this.AEAD(block, opt)
     if err != nil {
-        err = fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -526,6 +757,27 @@ func init() {
 
 type ModeOCB struct {}
 
+// AEAD
+// 没有设置 nonce 时使用默认 nonce 长度
+func (this ModeOCB) AEAD(block cipher.Block, opt IOption) (cipher.AEAD, error) {
+    var aead cipher.AEAD
+    var err error
+
+    nonceBytes := opt.Config().GetBytes("nonce")
+    if nonceBytes == nil {
+        aead, err = ocb.NewOCB(block)
+    } else {
+        aead, err = ocb.NewOCBWithNonceSize(block, len(nonceBytes))
+    }
+
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return aead, nil
+}
+
 // 加密
 func (this ModeOCB) Encrypt(plain []byte, block cipher.Block, opt IOption) ([]byte, error) {
     nonceBytes := opt.Config().GetBytes("nonce")
@@ -534,9 +786,8 @@ func (this ModeOCB) Encrypt(plain []byte, block cipher.Block, opt IOption) ([]by
         return nil, err
     }
 
-    aead, err := ocb.NewOCBWithNonceSize(block, len(nonceBytes))
+    aead, err := this.AEAD(block, opt)
     if err != nil {
-        err = fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -556,9 +807,8 @@ func (this ModeOCB) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byt
         return nil, err
     }
 
-    aead, err := ocb.NewOCBWithNonceSize(block, len(nonceBytes))
+    aead, err := this.AEAD(block, opt)
     if err != nil {
-        err = fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -579,6 +829,27 @@ func init() {
 
 type ModeEAX struct {}
 
+// AEAD
+// 没有设置 nonce 时使用默认 nonce 长度
+func (this ModeEAX) AEAD(block cipher.Block, opt IOption) (cipher.AEAD, error) {
+    var aead cipher.AEAD
+    var err error
+
+    nonceBytes := opt.Config().GetBytes("nonce")
+    if nonceBytes == nil {
+        aead, err = eax.NewEAX(block)
+    } else {
+        aead, err = eax.NewEAXWithNonceSize(block, len(nonceBytes))
+    }
+
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return aead, nil
+}
+
 // 加密
 func (this ModeEAX) Encrypt(plain []byte, block cipher.Block, opt IOption) ([]byte, error) {
     nonceBytes := opt.Config().GetBytes("nonce")
@@ -587,9 +858,8 @@ func (this ModeEAX) Encrypt(plain []byte, block cipher.Block, opt IOption) ([]by
         return nil, err
     }
 
-    aead, err := eax.NewEAXWithNonceSize(block, len(nonceBytes))
+    aead, err := this.AEAD(block, opt)
     if err != nil {
-        err = fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -609,9 +879,8 @@ func (this ModeEAX) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byt
         return nil, err
     }
 
-    aead, err := eax.NewEAXWithNonceSize(block, len(nonceBytes))
+    aead, err := this.AEAD(block, opt)
     if err != nil {
-        err = fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -624,7 +893,7 @@ func (this ModeEAX) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byt
 
 func init() {
     UseMode.Add(EAX, func() IMode {
-        return ModeOCB{}
+        return ModeEAX{}
     })
 }
 
@@ -654,6 +923,22 @@ func (this ModeNCFB) Decrypt(data []byte, block cipher.Block, opt IOption) ([]by
     return dst, nil
 }
 
+// 流式加密
+func (this ModeNCFB) EncryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cryptobin_cipher.NewNCFBEncrypter(block, iv)), nil
+}
+
+// 流式解密
+func (this ModeNCFB) DecryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cryptobin_cipher.NewNCFBDecrypter(block, iv)), nil
+}
+
 func init() {
     UseMode.Add(NCFB, func() IMode {
         return ModeNCFB{}
@@ -686,6 +971,22 @@ func (this ModeNOFB) Decrypt(data []byte, block cipher.Block, opt IOption) ([]by
     return dst, nil
 }
 
+// 流式加密
+func (this ModeNOFB) EncryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cryptobin_cipher.NewNOFB(block, iv)), nil
+}
+
+// 流式解密
+func (this ModeNOFB) DecryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return newStreamMode(cryptobin_cipher.NewNOFB(block, iv)), nil
+}
+
 func init() {
     UseMode.Add(NOFB, func() IMode {
         return ModeNOFB{}
@@ -718,6 +1019,22 @@ func (this ModeBC) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byte
     return dst, nil
 }
 
+// 流式加密
+func (this ModeBC) EncryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return cryptobin_cipher.NewBCEncrypter(block, iv), nil
+}
+
+// 流式解密
+func (this ModeBC) DecryptStream(block cipher.Block, opt IOption) (cipher.BlockMode, error) {
+    // 向量
+    iv := opt.Iv()
+
+    return cryptobin_cipher.NewBCDecrypter(block, iv), nil
+}
+
 func init() {
     UseMode.Add(BC, func() IMode {
         return ModeBC{}
@@ -755,3 +1072,520 @@ func init() {
         return ModeHCTR{}
     })
 }
+
+// ===================
+
+type ModeSIV struct {}
+
+// SIV
+// 密钥为 CTR 密钥 K2, 配置 siv_key 为 S2V 密钥 K1
+func (this ModeSIV) SIV(block cipher.Block, opt IOption) (*siv.SIV, error) {
+    macKey := opt.Config().GetBytes("siv_key")
+    if macKey == nil {
+        err := fmt.Errorf("Cryptobin: siv key is empty.")
+        return nil, err
+    }
+
+    macBlock, err := getBlockWithKey(opt, macKey)
+    if err != nil {
+        return nil, err
+    }
+
+    s, err := siv.NewSIV(macBlock, block)
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return s, nil
+}
+
+// 加密
+func (this ModeSIV) Encrypt(plain []byte, block cipher.Block, opt IOption) ([]byte, error) {
+    s, err := this.SIV(block, opt)
+    if err != nil {
+        return nil, err
+    }
+
+    return s.Seal(nil, plain, this.additional(opt)...)
+}
+
+// 解密
+func (this ModeSIV) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byte, error) {
+    s, err := this.SIV(block, opt)
+    if err != nil {
+        return nil, err
+    }
+
+    return s.Open(nil, data, this.additional(opt)...)
+}
+
+// 附加数据, 可以为 []byte 或者 [][]byte
+func (this ModeSIV) additional(opt IOption) [][]byte {
+    switch ad := opt.Config().Get("additional").(type) {
+        case []byte:
+            return [][]byte{ad}
+        case [][]byte:
+            return ad
+    }
+
+    return nil
+}
+
+func init() {
+    UseMode.Add(SIV, func() IMode {
+        return ModeSIV{}
+    })
+}
+
+// ===================
+
+type ModeGCMSIV struct {}
+
+// AEAD
+// 派生的加密密钥使用当前加密类型生成分组
+func (this ModeGCMSIV) AEAD(block cipher.Block, opt IOption) (cipher.AEAD, error) {
+    newCipher := func(key []byte) (cipher.Block, error) {
+        return getBlockWithKey(opt, key)
+    }
+
+    aead, err := gcmsiv.NewGCMSIVWithBlock(block, len(opt.Key()), newCipher)
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return aead, nil
+}
+
+// 加密
+func (this ModeGCMSIV) Encrypt(plain []byte, block cipher.Block, opt IOption) ([]byte, error) {
+    nonceBytes := opt.Config().GetBytes("nonce")
+    if len(nonceBytes) != gcmsiv.NonceSize {
+        err := fmt.Errorf("Cryptobin: nonce size must be %d bytes.", gcmsiv.NonceSize)
+        return nil, err
+    }
+
+    aead, err := this.AEAD(block, opt)
+    if err != nil {
+        return nil, err
+    }
+
+    additionalBytes := opt.Config().GetBytes("additional")
+
+    cryptText := aead.Seal(nil, nonceBytes, plain, additionalBytes)
+
+    return cryptText, nil
+}
+
+// 解密
+func (this ModeGCMSIV) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byte, error) {
+    nonceBytes := opt.Config().GetBytes("nonce")
+    if len(nonceBytes) != gcmsiv.NonceSize {
+        err := fmt.Errorf("Cryptobin: nonce size must be %d bytes.", gcmsiv.NonceSize)
+        return nil, err
+    }
+
+    aead, err := this.AEAD(block, opt)
+    if err != nil {
+        return nil, err
+    }
+
+    additionalBytes := opt.Config().GetBytes("additional")
+
+    dst, err := aead.Open(nil, nonceBytes, data, additionalBytes)
+
+    return dst, err
+}
+
+func init() {
+    UseMode.Add(GCMSIV, func() IMode {
+        return ModeGCMSIV{}
+    })
+}
+
+// ===================
+
+// 密钥包装, 设置向量时使用设置的 IV
+type ModeKW struct {}
+
+// 加密
+func (this ModeKW) Encrypt(plain []byte, block cipher.Block, opt IOption) ([]byte, error) {
+    cryptText, err := keywrap.Wrap(block, getKeyWrapIV(opt), plain)
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return cryptText, nil
+}
+
+// 解密
+func (this ModeKW) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byte, error) {
+    dst, err := keywrap.Unwrap(block, getKeyWrapIV(opt), data)
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return dst, nil
+}
+
+func init() {
+    UseMode.Add(KW, func() IMode {
+        return ModeKW{}
+    })
+}
+
+// ===================
+
+// 带填充的密钥包装, 设置向量时使用设置的 AIV 前缀
+type ModeKWP struct {}
+
+// 加密
+func (this ModeKWP) Encrypt(plain []byte, block cipher.Block, opt IOption) ([]byte, error) {
+    cryptText, err := keywrap.WrapPad(block, getKeyWrapIV(opt), plain)
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return cryptText, nil
+}
+
+// 解密
+func (this ModeKWP) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byte, error) {
+    dst, err := keywrap.UnwrapPad(block, getKeyWrapIV(opt), data)
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return dst, nil
+}
+
+func init() {
+    UseMode.Add(KWP, func() IMode {
+        return ModeKWP{}
+    })
+}
+
+// 密钥包装向量, 没有设置时使用默认值
+func getKeyWrapIV(opt IOption) []byte {
+    iv := opt.Iv()
+    if len(iv) == 0 {
+        return nil
+    }
+
+    return iv
+}
+
+// ===================
+
+// 格式保留加密 FF1
+// 配置 alphabet 为字符集, tweak 为调整值
+type ModeFF1 struct {}
+
+// FF1
+func (this ModeFF1) FF1(block cipher.Block, opt IOption) (*fpe.FF1, error) {
+    alphabet := opt.Config().GetString("alphabet")
+    tweak := opt.Config().GetBytes("tweak")
+
+    f, err := fpe.NewFF1(block, alphabet, tweak)
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return f, nil
+}
+
+// 加密
+func (this ModeFF1) Encrypt(plain []byte, block cipher.Block, opt IOption) ([]byte, error) {
+    f, err := this.FF1(block, opt)
+    if err != nil {
+        return nil, err
+    }
+
+    cryptText, err := f.Encrypt(string(plain))
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return []byte(cryptText), nil
+}
+
+// 解密
+func (this ModeFF1) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byte, error) {
+    f, err := this.FF1(block, opt)
+    if err != nil {
+        return nil, err
+    }
+
+    dst, err := f.Decrypt(string(data))
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return []byte(dst), nil
+}
+
+func init() {
+    UseMode.Add(FF1, func() IMode {
+        return ModeFF1{}
+    })
+}
+
+// ===================
+
+// 格式保留加密 FF3-1
+// 配置 alphabet 为字符集, tweak 为 7 字节调整值
+type ModeFF31 struct {}
+
+// FF3-1, 使用反序的密钥重新生成分组
+func (this ModeFF31) FF31(opt IOption) (*fpe.FF31, error) {
+    alphabet := opt.Config().GetString("alphabet")
+    tweak := opt.Config().GetBytes("tweak")
+
+    newCipher := func(key []byte) (cipher.Block, error) {
+        return getBlockWithKey(opt, key)
+    }
+
+    f, err := fpe.NewFF31(opt.Key(), newCipher, alphabet, tweak)
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return f, nil
+}
+
+// 加密
+func (this ModeFF31) Encrypt(plain []byte, block cipher.Block, opt IOption) ([]byte, error) {
+    f, err := this.FF31(opt)
+    if err != nil {
+        return nil, err
+    }
+
+    cryptText, err := f.Encrypt(string(plain))
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return []byte(cryptText), nil
+}
+
+// 解密
+func (this ModeFF31) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byte, error) {
+    f, err := this.FF31(opt)
+    if err != nil {
+        return nil, err
+    }
+
+    dst, err := f.Decrypt(string(data))
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return []byte(dst), nil
+}
+
+func init() {
+    UseMode.Add(FF31, func() IMode {
+        return ModeFF31{}
+    })
+}
+
+// ===================
+
+// XTS 模式, 设置的密钥为数据密钥 K1
+// 配置 tweak_key 为调整值密钥 K2, sector_num 为起始扇区号,
+// sector_size 为扇区大小, 为 0 时整个数据作为一个扇区
+type ModeXTS struct {}
+
+// XTS
+func (this ModeXTS) XTS(block cipher.Block, opt IOption) (*xts.XTS, error) {
+    tweakKey := opt.Config().GetBytes("tweak_key")
+    if tweakKey == nil {
+        err := fmt.Errorf("Cryptobin: tweak key is empty.")
+        return nil, err
+    }
+
+    tweak, err := getBlockWithKey(opt, tweakKey)
+    if err != nil {
+        return nil, err
+    }
+
+    x, err := xts.NewXTS(block, tweak)
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return x, nil
+}
+
+// 加密
+func (this ModeXTS) Encrypt(plain []byte, block cipher.Block, opt IOption) ([]byte, error) {
+    x, err := this.XTS(block, opt)
+    if err != nil {
+        return nil, err
+    }
+
+    sectorNum := opt.Config().GetUint64("sector_num")
+    sectorSize := opt.Config().GetInt("sector_size")
+
+    cryptText := make([]byte, len(plain))
+    err = x.EncryptSectors(cryptText, plain, sectorNum, sectorSize)
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return cryptText, nil
+}
+
+// 解密
+func (this ModeXTS) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byte, error) {
+    x, err := this.XTS(block, opt)
+    if err != nil {
+        return nil, err
+    }
+
+    sectorNum := opt.Config().GetUint64("sector_num")
+    sectorSize := opt.Config().GetInt("sector_size")
+
+    dst := make([]byte, len(data))
+    err = x.DecryptSectors(dst, data, sectorNum, sectorSize)
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return dst, nil
+}
+
+func init() {
+    UseMode.Add(XTS, func() IMode {
+        return ModeXTS{}
+    })
+}
+
+// ===================
+
+// LRW 模式
+// 配置 tweak_key 为 16 字节调整值密钥 K2, index 为 16 字节起始分组序号
+type ModeLRW struct {}
+
+// LRW
+func (this ModeLRW) LRW(block cipher.Block, opt IOption) (*lrw.LRW, error) {
+    tweakKey := opt.Config().GetBytes("tweak_key")
+
+    l, err := lrw.NewLRW(block, tweakKey)
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return l, nil
+}
+
+// 加密
+func (this ModeLRW) Encrypt(plain []byte, block cipher.Block, opt IOption) ([]byte, error) {
+    l, err := this.LRW(block, opt)
+    if err != nil {
+        return nil, err
+    }
+
+    index := opt.Config().GetBytes("index")
+
+    cryptText := make([]byte, len(plain))
+    err = l.Encrypt(cryptText, plain, index)
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return cryptText, nil
+}
+
+// 解密
+func (this ModeLRW) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byte, error) {
+    l, err := this.LRW(block, opt)
+    if err != nil {
+        return nil, err
+    }
+
+    index := opt.Config().GetBytes("index")
+
+    dst := make([]byte, len(data))
+    err = l.Decrypt(dst, data, index)
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return dst, nil
+}
+
+func init() {
+    UseMode.Add(LRW, func() IMode {
+        return ModeLRW{}
+    })
+}
+
+// ===================
+
+// HCTR2 宽分组模式
+// 配置 tweak 为调整值
+type ModeHCTR2 struct {}
+
+// 加密
+func (this ModeHCTR2) Encrypt(plain []byte, block cipher.Block, opt IOption) ([]byte, error) {
+    c, err := hctr2.NewHCTR2(block)
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    tweak := opt.Config().GetBytes("tweak")
+
+    cryptText := make([]byte, len(plain))
+    err = c.Encrypt(cryptText, plain, tweak)
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return cryptText, nil
+}
+
+// 解密
+func (this ModeHCTR2) Decrypt(data []byte, block cipher.Block, opt IOption) ([]byte, error) {
+    c, err := hctr2.NewHCTR2(block)
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    tweak := opt.Config().GetBytes("tweak")
+
+    dst := make([]byte, len(data))
+    err = c.Decrypt(dst, data, tweak)
+    if err != nil {
+        err = fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return dst, nil
+}
+
+func init() {
+    UseMode.Add(HCTR2, func() IMode {
+        return ModeHCTR2{}
+    })
+}
@@ -74,6 +74,22 @@ func getMode(opt IOption) (IMode, error) {
     return newMode(), nil
 }
 
+// 使用指定密钥获取加密类型的分组
+func getBlockWithKey(opt IOption, key []byte) (cipher.Block, error) {
+    newEncrypt, err := getEncrypt(opt.Multiple())
+    if err != nil {
+        return nil, err
+    }
+
+    encrypt, ok := newEncrypt.(IEncryptBlock)
+    if !ok {
+        err := fmt.Errorf("Cryptobin: the multiple %s is not a block cipher.", opt.Multiple())
+        return nil, err
+    }
+
+    return encrypt.Block(keyOption{opt, key})
+}
+
 // 获取补码方式
 func getPadding(opt IOption) (IPadding, error) {
     padding := opt.Padding()
@@ -163,9 +179,19 @@ func BlockDecrypt(block cipher.Block, data []byte, opt IOption) ([]byte, error)
 // AES-128, AES-192, or AES-256.
 type EncryptAes struct {}
 
+// 分组
+func (this EncryptAes) Block(opt IOption) (cipher.Block, error) {
+    block, err := aes.NewCipher(opt.Key())
+    if err != nil {
+        return nil, err
+    }
+
+    return block, nil
+}
+
 // 加密
 func (this EncryptAes) Encrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := aes.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -175,7 +201,7 @@ func (this EncryptAes) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptAes) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := aes.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -187,9 +213,19 @@ func (this EncryptAes) Decrypt(data []byte, opt IOption) ([]byte, error) {
 
 type EncryptDes struct {}
 
+// 分组
+func (this EncryptDes) Block(opt IOption) (cipher.Block, error) {
+    block, err := des.NewCipher(opt.Key())
+    if err != nil {
+        return nil, err
+    }
+
+    return block, nil
+}
+
 // 加密
 func (this EncryptDes) Encrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := des.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -199,7 +235,7 @@ func (this EncryptDes) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptDes) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := des.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -211,9 +247,19 @@ func (this EncryptDes) Decrypt(data []byte, opt IOption) ([]byte, error) {
 
 type EncryptTwoDes struct {}
 
+// 分组
+func (this EncryptTwoDes) Block(opt IOption) (cipher.Block, error) {
+    block, err := cryptobin_des.NewTwoDESCipher(opt.Key())
+    if err != nil {
+        return nil, err
+    }
+
+    return block, nil
+}
+
 // 加密
 func (this EncryptTwoDes) Encrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_des.NewTwoDESCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -223,7 +269,7 @@ func (this EncryptTwoDes) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptTwoDes) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_des.NewTwoDESCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -235,9 +281,19 @@ func (this EncryptTwoDes) Decrypt(data []byte, opt IOption) ([]byte, error) {
 
 type EncryptTripleDes struct {}
 
+// 分组
+func (this EncryptTripleDes) Block(opt IOption) (cipher.Block, error) {
+    block, err := des.NewTripleDESCipher(opt.Key())
+    if err != nil {
+        return nil, err
+    }
+
+    return block, nil
+}
+
 // 加密
 func (this EncryptTripleDes) Encrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := des.NewTripleDESCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -247,7 +303,7 @@ func (this EncryptTripleDes) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptTripleDes) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := des.NewTripleDESCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -261,9 +317,19 @@ func (this EncryptTripleDes) Decrypt(data []byte, opt IOption) ([]byte, error) {
 // 16, 24 or 32 bytes.
 type EncryptTwofish struct {}
 
+// 分组
+func (this EncryptTwofish) Block(opt IOption) (cipher.Block, error) {
+    block, err := twofish.NewCipher(opt.Key())
+    if err != nil {
+        return nil, err
+    }
+
+    return block, nil
+}
+
 // 加密
 func (this EncryptTwofish) Encrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := twofish.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -273,7 +339,7 @@ func (this EncryptTwofish) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptTwofish) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := twofish.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -285,8 +351,8 @@ func (this EncryptTwofish) Decrypt(data []byte, opt IOption) ([]byte, error) {
 
 type EncryptBlowfish struct {}
 
-// 加密
-func (this EncryptBlowfish) getBlock(opt IOption) (cipher.Block, error) {
+// 分组
+func (this EncryptBlowfish) Block(opt IOption) (cipher.Block, error) {
     if opt.Config().Has("salt") {
         return blowfish.NewSaltedCipher(opt.Key(), opt.Config().GetBytes("salt"))
     }
@@ -296,7 +362,7 @@ func (this EncryptBlowfish) getBlock(opt IOption) (cipher.Block, error) {
 
 // 加密
 func (this EncryptBlowfish) Encrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := this.getBlock(opt)
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -306,7 +372,7 @@ func (this EncryptBlowfish) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptBlowfish) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := this.getBlock(opt)
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -318,8 +384,8 @@ func (this EncryptBlowfish) Decrypt(data []byte, opt IOption) ([]byte, error) {
 
 type EncryptTea struct {}
 
-// 加密
-func (this EncryptTea) getBlock(opt IOption) (cipher.Block, error) {
+// 分组
+func (this EncryptTea) Block(opt IOption) (cipher.Block, error) {
     // key is 16 bytes
     if opt.Config().Has("rounds") {
         return tea.NewCipherWithRounds(opt.Key(), opt.Config().GetInt("rounds"))
@@ -330,7 +396,7 @@ func (this EncryptTea) getBlock(opt IOption) (cipher.Block, error) {
 
 // 加密
 func (this EncryptTea) Encrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := this.getBlock(opt)
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -340,7 +406,7 @@ func (this EncryptTea) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptTea) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := this.getBlock(opt)
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -352,15 +418,15 @@ func (this EncryptTea) Decrypt(data []byte, opt IOption) ([]byte, error) {
 
 type EncryptXtea struct {}
 
-// 加密
-func (this EncryptXtea) getBlock(opt IOption) (cipher.Block, error) {
+// 分组
+func (this EncryptXtea) Block(opt IOption) (cipher.Block, error) {
     // XTEA only supports 128 bit (16 byte) keys.
     return xtea.NewCipher(opt.Key())
 }
 
 // 加密
 func (this EncryptXtea) Encrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := this.getBlock(opt)
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -370,7 +436,7 @@ func (this EncryptXtea) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptXtea) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := this.getBlock(opt)
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -382,15 +448,15 @@ func (this EncryptXtea) Decrypt(data []byte, opt IOption) ([]byte, error) {
 
 type EncryptCast5 struct {}
 
-// 加密
-func (this EncryptCast5) getBlock(opt IOption) (cipher.Block, error) {
+// 分组
+func (this EncryptCast5) Block(opt IOption) (cipher.Block, error) {
     // Cast5 only supports 128 bit (16 byte) keys.
     return cast5.NewCipher(opt.Key())
 }
 
 // 加密
 func (this EncryptCast5) Encrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := this.getBlock(opt)
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -400,7 +466,7 @@ func (this EncryptCast5) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptCast5) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := this.getBlock(opt)
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -412,8 +478,8 @@ func (this EncryptCast5) Decrypt(data []byte, opt IOption) ([]byte, error) {
 
 type EncryptRC2 struct {}
 
-// 加密
-func (this EncryptRC2) getBlock(opt IOption) (cipher.Block, error) {
+// 分组
+func (this EncryptRC2) Block(opt IOption) (cipher.Block, error) {
     // RC2 key, at least 1 byte and at most 128 bytes.
     key := opt.Key()
 
@@ -422,7 +488,7 @@ func (this EncryptRC2) getBlock(opt IOption) (cipher.Block, error) {
 
 // 加密
 func (this EncryptRC2) Encrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := this.getBlock(opt)
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -432,7 +498,7 @@ func (this EncryptRC2) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptRC2) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := this.getBlock(opt)
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -444,8 +510,8 @@ func (this EncryptRC2) Decrypt(data []byte, opt IOption) ([]byte, error) {
 
 type EncryptRC5 struct {}
 
-// 加密
-func (this EncryptRC5) getBlock(opt IOption) (cipher.Block, error) {
+// 分组
+func (this EncryptRC5) Block(opt IOption) (cipher.Block, error) {
     // wordSize is 16, 32 or 64
     wordSize := uint(32)
     if opt.Config().Has("word_size") {
@@ -467,7 +533,7 @@ func (this EncryptRC5) getBlock(opt IOption) (cipher.Block, error) {
 
 // 加密
 func (this EncryptRC5) Encrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := this.getBlock(opt)
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -477,7 +543,7 @@ func (this EncryptRC5) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptRC5) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := this.getBlock(opt)
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -489,20 +555,30 @@ func (this EncryptRC5) Decrypt(data []byte, opt IOption) ([]byte, error) {
 
 type EncryptRC6 struct {}
 
-// 加密
-func (this EncryptRC6) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptRC6) Block(opt IOption) (cipher.Block, error) {
     // RC6 key is 16 bytes.
     block, err := cryptobin_rc6.NewCipher(opt.Key())
     if err != nil {
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptRC6) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptRC6) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_rc6.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -514,15 +590,15 @@ func (this EncryptRC6) Decrypt(data []byte, opt IOption) ([]byte, error) {
 
 type EncryptIdea struct {}
 
-// 加密
-func (this EncryptIdea) getBlock(opt IOption) (cipher.Block, error) {
+// 分组
+func (this EncryptIdea) Block(opt IOption) (cipher.Block, error) {
     // Idea only supports 128 bit (16 byte) keys.
     return cryptobin_idea.NewCipher(opt.Key())
 }
 
 // 加密
 func (this EncryptIdea) Encrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := this.getBlock(opt)
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -532,7 +608,7 @@ func (this EncryptIdea) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptIdea) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := this.getBlock(opt)
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -544,15 +620,15 @@ func (this EncryptIdea) Decrypt(data []byte, opt IOption) ([]byte, error) {
 
 type EncryptSM4 struct {}
 
-// 加密
-func (this EncryptSM4) getBlock(opt IOption) (cipher.Block, error) {
+// 分组
+func (this EncryptSM4) Block(opt IOption) (cipher.Block, error) {
     // 国密 sm4 加密
     return cryptobin_sm4.NewCipher(opt.Key())
 }
 
 // 加密
 func (this EncryptSM4) Encrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := this.getBlock(opt)
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -562,7 +638,7 @@ func (this EncryptSM4) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptSM4) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := this.getBlock(opt)
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -575,8 +651,8 @@ func (this EncryptSM4) Decrypt(data []byte, opt IOption) ([]byte, error) {
 // 32 bytes key and a 12 or 24 bytes nonce
 type EncryptChacha20 struct {}
 
-// 加密
-func (this EncryptChacha20) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 加密流
+func (this EncryptChacha20) EncryptStream(opt IOption) (cipher.Stream, error) {
     if !opt.Config().Has("nonce") {
         err := fmt.Errorf("Cryptobin: nonce is empty.")
         return nil, err
@@ -594,6 +670,21 @@ func (this EncryptChacha20) Encrypt(data []byte, opt IOption) ([]byte, error) {
         chacha.SetCounter(opt.Config().GetUint32("counter"))
     }
 
+    return chacha, nil
+}
+
+// 解密流
+func (this EncryptChacha20) DecryptStream(opt IOption) (cipher.Stream, error) {
+    return this.EncryptStream(opt)
+}
+
+// 加密
+func (this EncryptChacha20) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    chacha, err := this.EncryptStream(opt)
+    if err != nil {
+        return nil, err
+    }
+
     dst := make([]byte, len(data))
 
     chacha.XORKeyStream(dst, data)
@@ -603,23 +694,11 @@ func (this EncryptChacha20) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptChacha20) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    if !opt.Config().Has("nonce") {
-        err := fmt.Errorf("Cryptobin: nonce is empty")
-        return nil, err
-    }
-
-    nonce := opt.Config().GetBytes("nonce")
-
-    chacha, err := chacha20.NewUnauthenticatedCipher(opt.Key(), nonce)
+    chacha, err := this.DecryptStream(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
-    if opt.Config().Has("counter") {
-        chacha.SetCounter(opt.Config().GetUint32("counter"))
-    }
-
     dst := make([]byte, len(data))
 
     chacha.XORKeyStream(dst, data)
@@ -632,14 +711,24 @@ func (this EncryptChacha20) Decrypt(data []byte, opt IOption) ([]byte, error) {
 // 32 bytes key
 type EncryptChacha20poly1305 struct {}
 
-// 加密
-func (this EncryptChacha20poly1305) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// AEAD
+func (this EncryptChacha20poly1305) AEAD(opt IOption) (cipher.AEAD, error) {
     aead, err := chacha20poly1305.New(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return aead, nil
+}
+
+// 加密
+func (this EncryptChacha20poly1305) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    aead, err := this.AEAD(opt)
+    if err != nil {
+        return nil, err
+    }
+
     if !opt.Config().Has("nonce") {
         err := fmt.Errorf("Cryptobin: nonce is empty.")
         return nil, err
@@ -655,9 +744,8 @@ func (this EncryptChacha20poly1305) Encrypt(data []byte, opt IOption) ([]byte, e
 
 // 解密
 func (this EncryptChacha20poly1305) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    chacha, err := chacha20poly1305.New(opt.Key())
+    chacha, err := this.AEAD(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -677,14 +765,24 @@ func (this EncryptChacha20poly1305) Decrypt(data []byte, opt IOption) ([]byte, e
 // 32 bytes key
 type EncryptChacha20poly1305X struct {}
 
-// 加密
-func (this EncryptChacha20poly1305X) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// AEAD
+func (this EncryptChacha20poly1305X) AEAD(opt IOption) (cipher.AEAD, error) {
     aead, err := chacha20poly1305.NewX(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return aead, nil
+}
+
+// 加密
+func (this EncryptChacha20poly1305X) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    aead, err := this.AEAD(opt)
+    if err != nil {
+        return nil, err
+    }
+
     if !opt.Config().Has("nonce") {
         err := fmt.Errorf("Cryptobin: nonce is empty.")
         return nil, err
@@ -700,9 +798,8 @@ func (this EncryptChacha20poly1305X) Encrypt(data []byte, opt IOption) ([]byte,
 
 // 解密
 func (this EncryptChacha20poly1305X) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    chacha, err := chacha20poly1305.NewX(opt.Key())
+    chacha, err := this.AEAD(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -722,14 +819,29 @@ func (this EncryptChacha20poly1305X) Decrypt(data []byte, opt IOption) ([]byte,
 // RC4 key, at least 1 byte and at most 256 bytes.
 type EncryptRC4 struct {}
 
-// 加密
-func (this EncryptRC4) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 加密流
+func (this EncryptRC4) EncryptStream(opt IOption) (cipher.Stream, error) {
     rc, err := rc4.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return rc, nil
+}
+
+// 解密流
+func (this EncryptRC4) DecryptStream(opt IOption) (cipher.Stream, error) {
+    return this.EncryptStream(opt)
+}
+
+// 加密
+func (this EncryptRC4) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    rc, err := this.EncryptStream(opt)
+    if err != nil {
+        return nil, err
+    }
+
     dst := make([]byte, len(data))
 
     rc.XORKeyStream(dst, data)
@@ -739,9 +851,8 @@ func (this EncryptRC4) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptRC4) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    rc, err := rc4.NewCipher(opt.Key())
+    rc, err := this.DecryptStream(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -766,14 +877,29 @@ func (this EncryptRC4MD5) getCipher(opt IOption) (cipher.Stream, error) {
     return rc4.NewCipher(h.Sum(nil))
 }
 
-// 加密
-func (this EncryptRC4MD5) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 加密流
+func (this EncryptRC4MD5) EncryptStream(opt IOption) (cipher.Stream, error) {
     rc, err := this.getCipher(opt)
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return rc, nil
+}
+
+// 解密流
+func (this EncryptRC4MD5) DecryptStream(opt IOption) (cipher.Stream, error) {
+    return this.EncryptStream(opt)
+}
+
+// 加密
+func (this EncryptRC4MD5) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    rc, err := this.EncryptStream(opt)
+    if err != nil {
+        return nil, err
+    }
+
     dst := make([]byte, len(data))
 
     rc.XORKeyStream(dst, data)
@@ -783,9 +909,8 @@ func (this EncryptRC4MD5) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptRC4MD5) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    rc, err := this.getCipher(opt)
+    rc, err := this.DecryptStream(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -891,8 +1016,8 @@ func (this EncryptXts) Decrypt(data []byte, opt IOption) ([]byte, error) {
 // nonce is 16 bytes.
 type EncryptSalsa20 struct {}
 
-// 加密
-func (this EncryptSalsa20) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 加密流
+func (this EncryptSalsa20) EncryptStream(opt IOption) (cipher.Stream, error) {
     nonce := opt.Config().GetBytes("nonce")
 
     c, err := cryptobin_salsa20.NewCipher(opt.Key(), nonce)
@@ -901,6 +1026,21 @@ func (this EncryptSalsa20) Encrypt(data []byte, opt IOption) ([]byte, error) {
         return nil, err
     }
 
+    return c, nil
+}
+
+// 解密流
+func (this EncryptSalsa20) DecryptStream(opt IOption) (cipher.Stream, error) {
+    return this.EncryptStream(opt)
+}
+
+// 加密
+func (this EncryptSalsa20) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    c, err := this.EncryptStream(opt)
+    if err != nil {
+        return nil, err
+    }
+
     dst := make([]byte, len(data))
 
     c.XORKeyStream(dst, data)
@@ -910,11 +1050,8 @@ func (this EncryptSalsa20) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptSalsa20) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    nonce := opt.Config().GetBytes("nonce")
-
-    c, err := cryptobin_salsa20.NewCipher(opt.Key(), nonce)
+    c, err := this.DecryptStream(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -930,22 +1067,31 @@ func (this EncryptSalsa20) Decrypt(data []byte, opt IOption) ([]byte, error) {
 // Seed key is 16 bytes.
 type EncryptSeed struct {}
 
-// 加密
-func (this EncryptSeed) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptSeed) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_seed.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptSeed) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptSeed) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_seed.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -957,22 +1103,31 @@ func (this EncryptSeed) Decrypt(data []byte, opt IOption) ([]byte, error) {
 // Aria key is 16, 24, or 32 bytes.
 type EncryptAria struct {}
 
-// 加密
-func (this EncryptAria) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptAria) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_aria.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptAria) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptAria) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_aria.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -984,22 +1139,31 @@ func (this EncryptAria) Decrypt(data []byte, opt IOption) ([]byte, error) {
 // Camellia key is 16, 24, or 32 bytes.
 type EncryptCamellia struct {}
 
-// 加密
-func (this EncryptCamellia) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptCamellia) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_camellia.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptCamellia) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptCamellia) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_camellia.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1011,8 +1175,8 @@ func (this EncryptCamellia) Decrypt(data []byte, opt IOption) ([]byte, error) {
 // Gost key is 32 bytes.
 type EncryptGost struct {}
 
-// 加密
-func (this EncryptGost) getCipher(opt IOption) (cipher.Block, error) {
+// 分组
+func (this EncryptGost) Block(opt IOption) (cipher.Block, error) {
     s := opt.Config().Get("sbox")
 
     var sbox [][]byte
@@ -1051,7 +1215,7 @@ func (this EncryptGost) getCipher(opt IOption) (cipher.Block, error) {
 
 // 加密
 func (this EncryptGost) Encrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := this.getCipher(opt)
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -1061,7 +1225,7 @@ func (this EncryptGost) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptGost) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := this.getCipher(opt)
+    block, err := this.Block(opt)
     if err != nil {
         return nil, err
     }
@@ -1074,22 +1238,31 @@ func (this EncryptGost) Decrypt(data []byte, opt IOption) ([]byte, error) {
 // Kuznyechik key is 32 bytes.
 type EncryptKuznyechik struct {}
 
-// 加密
-func (this EncryptKuznyechik) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptKuznyechik) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_kuznyechik.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptKuznyechik) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptKuznyechik) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_kuznyechik.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1101,22 +1274,31 @@ func (this EncryptKuznyechik) Decrypt(data []byte, opt IOption) ([]byte, error)
 // Skipjack key is 10 bytes.
 type EncryptSkipjack struct {}
 
-// 加密
-func (this EncryptSkipjack) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptSkipjack) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_skipjack.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptSkipjack) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptSkipjack) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_skipjack.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1128,22 +1310,31 @@ func (this EncryptSkipjack) Decrypt(data []byte, opt IOption) ([]byte, error) {
 // Serpent key is 16, 24, 32 bytes.
 type EncryptSerpent struct {}
 
-// 加密
-func (this EncryptSerpent) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptSerpent) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_serpent.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptSerpent) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptSerpent) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_serpent.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1244,22 +1435,31 @@ func init() {
 // Loki97 key is 16, 24, 32 bytes.
 type EncryptLoki97 struct {}
 
-// 加密
-func (this EncryptLoki97) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptLoki97) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_loki97.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
-    return BlockEncrypt(block, data, opt)
+    return block, nil
 }
 
-// 解密
-func (this EncryptLoki97) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_loki97.NewCipher(opt.Key())
-    if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
+// 加密
+func (this EncryptLoki97) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
+    return BlockEncrypt(block, data, opt)
+}
+
+// 解密
+func (this EncryptLoki97) Decrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
         return nil, err
     }
 
@@ -1277,22 +1477,31 @@ func init() {
 // Saferplus key is 8, 16 bytes.
 type EncryptSaferplus struct {}
 
-// 加密
-func (this EncryptSaferplus) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptSaferplus) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_saferplus.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptSaferplus) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptSaferplus) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_saferplus.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1310,22 +1519,31 @@ func init() {
 // Mars key is 16, 24, 32 bytes.
 type EncryptMars struct {}
 
-// 加密
-func (this EncryptMars) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptMars) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_mars.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptMars) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptMars) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_mars.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1343,22 +1561,31 @@ func init() {
 // Mars key is 16, 24, 32 bytes.
 type EncryptMars2 struct {}
 
-// 加密
-func (this EncryptMars2) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptMars2) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_mars2.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptMars2) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptMars2) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_mars2.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1376,32 +1603,52 @@ func init() {
 // Wake key is 16 bytes.
 type EncryptWake struct {}
 
-// 加密
-func (this EncryptWake) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 加密流
+func (this EncryptWake) EncryptStream(opt IOption) (cipher.Stream, error) {
     c, err := cryptobin_wake.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return streamFunc(c.Encrypt), nil
+}
+
+// 解密流
+func (this EncryptWake) DecryptStream(opt IOption) (cipher.Stream, error) {
+    c, err := cryptobin_wake.NewCipher(opt.Key())
+    if err != nil {
+        err := fmt.Errorf("Cryptobin: %w", err)
+        return nil, err
+    }
+
+    return streamFunc(c.Decrypt), nil
+}
+
+// 加密
+func (this EncryptWake) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    c, err := this.EncryptStream(opt)
+    if err != nil {
+        return nil, err
+    }
+
     dst := make([]byte, len(data))
 
-    c.Encrypt(dst, data)
+    c.XORKeyStream(dst, data)
 
     return dst, nil
 }
 
 // 解密
 func (this EncryptWake) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    c, err := cryptobin_wake.NewCipher(opt.Key())
+    c, err := this.DecryptStream(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
     dst := make([]byte, len(data))
 
-    c.Decrypt(dst, data)
+    c.XORKeyStream(dst, data)
 
     return dst, nil
 }
@@ -1417,14 +1664,29 @@ func init() {
 // Enigma key is 13 bytes.
 type EncryptEnigma struct {}
 
-// 加密
-func (this EncryptEnigma) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 加密流
+func (this EncryptEnigma) EncryptStream(opt IOption) (cipher.Stream, error) {
     c, err := cryptobin_enigma.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return c, nil
+}
+
+// 解密流
+func (this EncryptEnigma) DecryptStream(opt IOption) (cipher.Stream, error) {
+    return this.EncryptStream(opt)
+}
+
+// 加密
+func (this EncryptEnigma) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    c, err := this.EncryptStream(opt)
+    if err != nil {
+        return nil, err
+    }
+
     dst := make([]byte, len(data))
 
     c.XORKeyStream(dst, data)
@@ -1434,9 +1696,8 @@ func (this EncryptEnigma) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptEnigma) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    c, err := cryptobin_enigma.NewCipher(opt.Key())
+    c, err := this.DecryptStream(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1458,22 +1719,31 @@ func init() {
 // Cast256 key is 32 bytes.
 type EncryptCast256 struct {}
 
-// 加密
-func (this EncryptCast256) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptCast256) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_cast256.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptCast256) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptCast256) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_cast256.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1491,22 +1761,31 @@ func init() {
 // The key argument should be 16 bytes.
 type EncryptHight struct {}
 
-// 加密
-func (this EncryptHight) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptHight) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_hight.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptHight) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptHight) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_hight.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1524,22 +1803,31 @@ func init() {
 // The key argument should be 16, 24, 32 bytes.
 type EncryptLea struct {}
 
-// 加密
-func (this EncryptLea) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptLea) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_lea.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptLea) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptLea) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_lea.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1557,14 +1845,29 @@ func init() {
 // The key argument should be 32 bytes.
 type EncryptPanama struct {}
 
-// 加密
-func (this EncryptPanama) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 加密流
+func (this EncryptPanama) EncryptStream(opt IOption) (cipher.Stream, error) {
     c, err := cryptobin_panama.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return c, nil
+}
+
+// 解密流
+func (this EncryptPanama) DecryptStream(opt IOption) (cipher.Stream, error) {
+    return this.EncryptStream(opt)
+}
+
+// 加密
+func (this EncryptPanama) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    c, err := this.EncryptStream(opt)
+    if err != nil {
+        return nil, err
+    }
+
     dst := make([]byte, len(data))
 
     c.XORKeyStream(dst, data)
@@ -1574,9 +1877,8 @@ func (this EncryptPanama) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptPanama) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    c, err := cryptobin_panama.NewCipher(opt.Key())
+    c, err := this.DecryptStream(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1598,22 +1900,31 @@ func init() {
 // The key argument should be 32 bytes.
 type EncryptSquare struct {}
 
-// 加密
-func (this EncryptSquare) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptSquare) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_square.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptSquare) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptSquare) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_square.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1631,22 +1942,31 @@ func init() {
 // The key argument should be 16, 24, 32 bytes.
 type EncryptMagenta struct {}
 
-// 加密
-func (this EncryptMagenta) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptMagenta) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_magenta.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptMagenta) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptMagenta) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_magenta.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1664,22 +1984,31 @@ func init() {
 // The key argument should be 16 bytes.
 type EncryptKasumi struct {}
 
-// 加密
-func (this EncryptKasumi) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptKasumi) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_kasumi.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptKasumi) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptKasumi) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_kasumi.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1697,22 +2026,31 @@ func init() {
 // The key argument should be 16, 24, 32 bytes.
 type EncryptE2 struct {}
 
-// 加密
-func (this EncryptE2) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptE2) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_e2.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptE2) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptE2) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_e2.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1730,22 +2068,31 @@ func init() {
 // The key argument should be 16, 24, 32 bytes.
 type EncryptCrypton1 struct {}
 
-// 加密
-func (this EncryptCrypton1) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptCrypton1) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_crypton1.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptCrypton1) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptCrypton1) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_crypton1.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1763,22 +2110,31 @@ func init() {
 // The key argument should be 16, 24, 32 bytes.
 type EncryptClefia struct {}
 
-// 加密
-func (this EncryptClefia) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptClefia) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_clefia.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptClefia) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptClefia) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_clefia.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1796,8 +2152,8 @@ func init() {
 // The key argument should be 16, 24, 32 bytes.
 type EncryptSafer struct {}
 
-// 加密
-func (this EncryptSafer) getBlock(opt IOption) (cipher.Block, error) {
+// 分组
+func (this EncryptSafer) Block(opt IOption) (cipher.Block, error) {
     typ := opt.Config().GetString("type")
     rounds := opt.Config().GetInt32("rounds")
 
@@ -1810,7 +2166,7 @@ func (this EncryptSafer) getBlock(opt IOption) (cipher.Block, error) {
 
 // 加密
 func (this EncryptSafer) Encrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := this.getBlock(opt)
+    block, err := this.Block(opt)
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
@@ -1821,7 +2177,7 @@ func (this EncryptSafer) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptSafer) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := this.getBlock(opt)
+    block, err := this.Block(opt)
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
@@ -1841,22 +2197,31 @@ func init() {
 // The key argument should be 16 bytes.
 type EncryptNoekeon struct {}
 
-// 加密
-func (this EncryptNoekeon) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptNoekeon) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_noekeon.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptNoekeon) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptNoekeon) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_noekeon.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1874,8 +2239,8 @@ func init() {
 // The key argument should be 40 bytes.
 type EncryptMulti2 struct {}
 
-// 加密
-func (this EncryptMulti2) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptMulti2) Block(opt IOption) (cipher.Block, error) {
     rounds := opt.Config().GetInt32("rounds")
 
     block, err := cryptobin_multi2.NewCipher(opt.Key(), rounds)
@@ -1884,16 +2249,23 @@ func (this EncryptMulti2) Encrypt(data []byte, opt IOption) ([]byte, error) {
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptMulti2) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptMulti2) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    rounds := opt.Config().GetInt32("rounds")
-
-    block, err := cryptobin_multi2.NewCipher(opt.Key(), rounds)
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1911,22 +2283,31 @@ func init() {
 // The key argument should be 16 bytes.
 type EncryptKseed struct {}
 
-// 加密
-func (this EncryptKseed) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptKseed) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_kseed.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptKseed) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptKseed) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_kseed.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1944,22 +2325,31 @@ func init() {
 // The key argument should be 16 bytes.
 type EncryptKhazad struct {}
 
-// 加密
-func (this EncryptKhazad) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptKhazad) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_khazad.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptKhazad) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptKhazad) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_khazad.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -1977,22 +2367,31 @@ func init() {
 // The key argument should be 16, 20, 24, 28, 32, 36, and 40 bytes.
 type EncryptAnubis struct {}
 
-// 加密
-func (this EncryptAnubis) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptAnubis) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_anubis.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptAnubis) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptAnubis) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_anubis.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -2010,22 +2409,31 @@ func init() {
 // The key argument should be 16, 20, 24, 28, 32, 36, and 40 bytes.
 type EncryptPresent struct {}
 
-// 加密
-func (this EncryptPresent) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptPresent) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_present.NewCipher(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptPresent) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptPresent) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_present.NewCipher(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -2043,14 +2451,29 @@ func init() {
 // The key argument should be 10 bytes.
 type EncryptTrivium struct {}
 
-// 加密
-func (this EncryptTrivium) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 加密流
+func (this EncryptTrivium) EncryptStream(opt IOption) (cipher.Stream, error) {
     c, err := cryptobin_trivium.NewCipher(opt.Key(), opt.Iv())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return c, nil
+}
+
+// 解密流
+func (this EncryptTrivium) DecryptStream(opt IOption) (cipher.Stream, error) {
+    return this.EncryptStream(opt)
+}
+
+// 加密
+func (this EncryptTrivium) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    c, err := this.EncryptStream(opt)
+    if err != nil {
+        return nil, err
+    }
+
     dst := make([]byte, len(data))
 
     c.XORKeyStream(dst, data)
@@ -2060,9 +2483,8 @@ func (this EncryptTrivium) Encrypt(data []byte, opt IOption) ([]byte, error) {
 
 // 解密
 func (this EncryptTrivium) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    c, err := cryptobin_trivium.NewCipher(opt.Key(), opt.Iv())
+    c, err := this.DecryptStream(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -2084,8 +2506,8 @@ func init() {
 // The key argument should be 16, 24 or 32 bytes.
 type EncryptRijndael struct {}
 
-// 加密
-func (this EncryptRijndael) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptRijndael) Block(opt IOption) (cipher.Block, error) {
     blockSize := opt.Config().GetInt("block_size")
 
     block, err := cryptobin_rijndael.NewCipher(opt.Key(), blockSize)
@@ -2094,16 +2516,23 @@ func (this EncryptRijndael) Encrypt(data []byte, opt IOption) ([]byte, error) {
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptRijndael) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptRijndael) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    blockSize := opt.Config().GetInt("block_size")
-
-    block, err := cryptobin_rijndael.NewCipher(opt.Key(), blockSize)
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -2121,22 +2550,31 @@ func init() {
 // The key argument should be 16, 24 or 32 bytes.
 type EncryptRijndael128 struct {}
 
-// 加密
-func (this EncryptRijndael128) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptRijndael128) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_rijndael.NewCipher128(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptRijndael128) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptRijndael128) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_rijndael.NewCipher128(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -2154,22 +2592,31 @@ func init() {
 // The key argument should be 16, 24 or 32 bytes.
 type EncryptRijndael192 struct {}
 
-// 加密
-func (this EncryptRijndael192) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptRijndael192) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_rijndael.NewCipher192(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptRijndael192) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptRijndael192) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_rijndael.NewCipher192(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
@@ -2187,22 +2634,31 @@ func init() {
 // The key argument should be 16, 24 or 32 bytes.
 type EncryptRijndael256 struct {}
 
-// 加密
-func (this EncryptRijndael256) Encrypt(data []byte, opt IOption) ([]byte, error) {
+// 分组
+func (this EncryptRijndael256) Block(opt IOption) (cipher.Block, error) {
     block, err := cryptobin_rijndael.NewCipher256(opt.Key())
     if err != nil {
         err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
+    return block, nil
+}
+
+// 加密
+func (this EncryptRijndael256) Encrypt(data []byte, opt IOption) ([]byte, error) {
+    block, err := this.Block(opt)
+    if err != nil {
+        return nil, err
+    }
+
     return BlockEncrypt(block, data, opt)
 }
 
 // 解密
 func (this EncryptRijndael256) Decrypt(data []byte, opt IOption) ([]byte, error) {
-    block, err := cryptobin_rijndael.NewCipher256(opt.Key())
+    block, err := this.Block(opt)
     if err != nil {
-        err := fmt.Errorf("Cryptobin: %w", err)
         return nil, err
     }
 
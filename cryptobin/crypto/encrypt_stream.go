@@ -0,0 +1,309 @@
+package crypto
+
+import (
+    "io"
+    "fmt"
+    "crypto/cipher"
+)
+
+// 流式处理每次读取的数据长度
+const streamBufferSize = 32 * 1024
+
+// 方法转换为流
+type streamFunc func(dst, src []byte)
+
+func (f streamFunc) XORKeyStream(dst, src []byte) {
+    f(dst, src)
+}
+
+// 流模式转换为分组模式
+type streamMode struct {
+    stream cipher.Stream
+}
+
+func newStreamMode(stream cipher.Stream) cipher.BlockMode {
+    return streamMode{
+        stream: stream,
+    }
+}
+
+func (this streamMode) BlockSize() int {
+    return 1
+}
+
+func (this streamMode) CryptBlocks(dst, src []byte) {
+    this.stream.XORKeyStream(dst, src)
+}
+
+// 获取流式模式
+func getModeStream(opt IOption) (IModeStream, error) {
+    newMode, err := getMode(opt)
+    if err != nil {
+        return nil, err
+    }
+
+    mode, ok := newMode.(IModeStream)
+    if !ok {
+        err := fmt.Errorf("Cryptobin: the mode %s can not stream.", opt.Mode())
+        return nil, err
+    }
+
+    return mode, nil
+}
+
+// 补码转换为流式补码, 只处理最后一块数据
+type streamPadding struct {
+    padding IPadding
+}
+
+func (this streamPadding) StreamPadding(final []byte, blockSize int, opt IOption) []byte {
+    return this.padding.Padding(final, blockSize, opt)
+}
+
+func (this streamPadding) StreamUnPadding(final []byte, blockSize int, opt IOption) ([]byte, error) {
+    return this.padding.UnPadding(final, opt)
+}
+
+// 获取流式补码
+// 补码没有实现 IPaddingStream 时使用 Padding 及 UnPadding 处理最后一块数据
+func getPaddingStream(opt IOption) (IPaddingStream, error) {
+    newPadding, err := getPadding(opt)
+    if err != nil {
+        return nil, err
+    }
+
+    if padding, ok := newPadding.(IPaddingStream); ok {
+        return padding, nil
+    }
+
+    return streamPadding{newPadding}, nil
+}
+
+// 最后保留的数据长度
+// 有数据时保留 (0, blockSize] 长度数据, 用于补码
+func streamTailSize(n, blockSize int) int {
+    if n == 0 {
+        return 0
+    }
+
+    return (n-1) % blockSize + 1
+}
+
+// 流式加密
+func StreamEncrypt(encrypt IEncrypt, w io.Writer, r io.Reader, opt IOption) error {
+    // AEAD 使用分段格式
+    s, err := getStreamAEAD(encrypt, opt)
+    if err != nil {
+        return err
+    }
+
+    if s != nil {
+        return AEADEncryptStream(s, w, r, opt)
+    }
+
+    switch e := encrypt.(type) {
+        case IEncryptBlock:
+            block, err := e.Block(opt)
+            if err != nil {
+                return err
+            }
+
+            return BlockEncryptStream(block, w, r, opt)
+        case IEncryptStream:
+            stream, err := e.EncryptStream(opt)
+            if err != nil {
+                return err
+            }
+
+            return CipherStream(stream, w, r)
+    }
+
+    return fmt.Errorf("Cryptobin: Multiple [%s] can not stream.", opt.Multiple())
+}
+
+// 流式解密
+func StreamDecrypt(encrypt IEncrypt, w io.Writer, r io.Reader, opt IOption) error {
+    // AEAD 使用分段格式
+    s, err := getStreamAEAD(encrypt, opt)
+    if err != nil {
+        return err
+    }
+
+    if s != nil {
+        return AEADDecryptStream(s, w, r, opt)
+    }
+
+    switch e := encrypt.(type) {
+        case IEncryptBlock:
+            block, err := e.Block(opt)
+            if err != nil {
+                return err
+            }
+
+            return BlockDecryptStream(block, w, r, opt)
+        case IEncryptStream:
+            stream, err := e.DecryptStream(opt)
+            if err != nil {
+                return err
+            }
+
+            return CipherStream(stream, w, r)
+    }
+
+    return fmt.Errorf("Cryptobin: Multiple [%s] can not stream.", opt.Multiple())
+}
+
+// 流密码处理数据
+func CipherStream(stream cipher.Stream, w io.Writer, r io.Reader) error {
+    buf := make([]byte, streamBufferSize)
+
+    for {
+        n, err := r.Read(buf)
+        if n > 0 {
+            stream.XORKeyStream(buf[:n], buf[:n])
+
+            if _, werr := w.Write(buf[:n]); werr != nil {
+                return werr
+            }
+        }
+
+        if err == io.EOF {
+            return nil
+        }
+
+        if err != nil {
+            return err
+        }
+    }
+}
+
+// 分组流式加密
+// 补码只在最后一块数据处理
+func BlockEncryptStream(block cipher.Block, w io.Writer, r io.Reader, opt IOption) error {
+    bs := block.BlockSize()
+
+    // 补码
+    newPadding, err := getPaddingStream(opt)
+    if err != nil {
+        return err
+    }
+
+    // 模式
+    newMode, err := getModeStream(opt)
+    if err != nil {
+        return err
+    }
+
+    mode, err := newMode.EncryptStream(block, opt)
+    if err != nil {
+        return err
+    }
+
+    final, err := blockStream(mode, bs, w, r)
+    if err != nil {
+        return err
+    }
+
+    // 补码最后一块数据
+    final = newPadding.StreamPadding(final, bs, opt)
+
+    // 补码后需要验证
+    if len(final)%mode.BlockSize() != 0 {
+        err := fmt.Errorf("Cryptobin: the length of the completed data must be an integer multiple of the block, the completed data size is %d, block size is %d", len(final), mode.BlockSize())
+        return err
+    }
+
+    dst := make([]byte, len(final))
+    mode.CryptBlocks(dst, final)
+
+    _, err = w.Write(dst)
+
+    return err
+}
+
+// 分组流式解密
+// 去除补码只在最后一块数据处理
+func BlockDecryptStream(block cipher.Block, w io.Writer, r io.Reader, opt IOption) error {
+    bs := block.BlockSize()
+
+    // 补码
+    newPadding, err := getPaddingStream(opt)
+    if err != nil {
+        return err
+    }
+
+    // 模式
+    newMode, err := getModeStream(opt)
+    if err != nil {
+        return err
+    }
+
+    mode, err := newMode.DecryptStream(block, opt)
+    if err != nil {
+        return err
+    }
+
+    final, err := blockStream(mode, bs, w, r)
+    if err != nil {
+        return err
+    }
+
+    // 补码后需要验证
+    if opt.Padding() != NoPadding {
+        if len(final)%bs != 0 {
+            err := fmt.Errorf("Cryptobin: improper decrypt type, block size is %d", bs)
+            return err
+        }
+    }
+
+    if len(final)%mode.BlockSize() != 0 {
+        err := fmt.Errorf("Cryptobin: input not full blocks, block size is %d", mode.BlockSize())
+        return err
+    }
+
+    dst := make([]byte, len(final))
+    mode.CryptBlocks(dst, final)
+
+    // 去除补码数据
+    dst, err = newPadding.StreamUnPadding(dst, bs, opt)
+    if err != nil {
+        return err
+    }
+
+    _, err = w.Write(dst)
+
+    return err
+}
+
+// 分组处理数据, 返回最后保留的未处理数据
+func blockStream(mode cipher.BlockMode, blockSize int, w io.Writer, r io.Reader) ([]byte, error) {
+    buf := make([]byte, 0, streamBufferSize + blockSize)
+    dst := make([]byte, streamBufferSize + blockSize)
+    chunk := make([]byte, streamBufferSize)
+
+    for {
+        n, err := r.Read(chunk)
+        if n > 0 {
+            buf = append(buf, chunk[:n]...)
+
+            m := len(buf) - streamTailSize(len(buf), blockSize)
+            if m > 0 {
+                mode.CryptBlocks(dst[:m], buf[:m])
+
+                if _, werr := w.Write(dst[:m]); werr != nil {
+                    return nil, werr
+                }
+
+                buf = append(buf[:0], buf[m:]...)
+            }
+        }
+
+        if err == io.EOF {
+            return buf, nil
+        }
+
+        if err != nil {
+            return nil, err
+        }
+    }
+}
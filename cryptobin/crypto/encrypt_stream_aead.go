@@ -0,0 +1,123 @@
+package crypto
+
+import (
+    "io"
+    "fmt"
+    "crypto/cipher"
+
+    "github.com/deatil/go-cryptobin/cipher/streamaead"
+)
+
+// 分段 AEAD 模式标识
+const (
+    streamAEADGCM byte = 1 + iota
+    streamAEADCCM
+    streamAEADOCB
+    streamAEADEAX
+    streamAEADGCMSIV
+)
+
+// 获取分段 AEAD 算法标识, 写入数据头部
+// 高字节为加密类型, 低字节为模式, 加密类型本身为 AEAD 时模式为 0
+func getStreamAEADID(opt IOption, isBlock bool) (uint16, error) {
+    multiple := opt.Multiple()
+    if multiple > 0xff {
+        err := fmt.Errorf("Cryptobin: Multiple [%s] can not stream.", multiple)
+        return 0, err
+    }
+
+    var mode byte
+    if isBlock {
+        switch opt.Mode() {
+            case GCM:
+                mode = streamAEADGCM
+            case CCM:
+                mode = streamAEADCCM
+            case OCB:
+                mode = streamAEADOCB
+            case EAX:
+                mode = streamAEADEAX
+            case GCMSIV:
+                mode = streamAEADGCMSIV
+        }
+    }
+
+    return uint16(multiple) << 8 | uint16(mode), nil
+}
+
+// 获取分段 AEAD
+// 加密类型及模式不是 AEAD 时返回 nil
+// 每段 nonce 由随机前缀及分段序号组成, 设置的 nonce 只用于确定 nonce 长度
+func getStreamAEAD(encrypt IEncrypt, opt IOption) (*streamaead.StreamAEAD, error) {
+    var newAEAD streamaead.AEADFunc
+    var isBlock bool
+
+    switch e := encrypt.(type) {
+        case IEncryptAEAD:
+            newAEAD = func(key []byte) (cipher.AEAD, error) {
+                return e.AEAD(keyOption{opt, key})
+            }
+        case IEncryptBlock:
+            newMode, err := getMode(opt)
+            if err != nil {
+                return nil, err
+            }
+
+            mode, ok := newMode.(IModeAEAD)
+            if !ok {
+                return nil, nil
+            }
+
+            newAEAD = func(key []byte) (cipher.AEAD, error) {
+                block, err := e.Block(keyOption{opt, key})
+                if err != nil {
+                    return nil, err
+                }
+
+                return mode.AEAD(block, opt)
+            }
+
+            isBlock = true
+        default:
+            return nil, nil
+    }
+
+    id, err := getStreamAEADID(opt, isBlock)
+    if err != nil {
+        return nil, err
+    }
+
+    segmentSize := opt.Config().GetInt("segment_size")
+
+    return streamaead.New(id, opt.Key(), segmentSize, newAEAD)
+}
+
+// 分段 AEAD 流式加密
+func AEADEncryptStream(s *streamaead.StreamAEAD, w io.Writer, r io.Reader, opt IOption) error {
+    additional := opt.Config().GetBytes("additional")
+
+    ew, err := s.NewEncryptingWriter(w, additional)
+    if err != nil {
+        return err
+    }
+
+    if _, err := io.Copy(ew, r); err != nil {
+        return err
+    }
+
+    return ew.Close()
+}
+
+// 分段 AEAD 流式解密
+func AEADDecryptStream(s *streamaead.StreamAEAD, w io.Writer, r io.Reader, opt IOption) error {
+    additional := opt.Config().GetBytes("additional")
+
+    dr, err := s.NewDecryptingReader(r, additional)
+    if err != nil {
+        return err
+    }
+
+    _, err = io.Copy(w, dr)
+
+    return err
+}
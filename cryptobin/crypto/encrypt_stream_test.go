@@ -0,0 +1,244 @@
+package crypto
+
+import (
+    "bytes"
+    "errors"
+    "testing"
+    "crypto/rand"
+    "testing/iotest"
+
+    "github.com/deatil/go-cryptobin/cipher/streamaead"
+
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+func Test_EncryptTo(t *testing.T) {
+    assert := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    data := make([]byte, 100*1024+7)
+    rand.Read(data)
+
+    key := []byte("dfertf12dfertf12")
+    iv := []byte("dfertf12dfertf12")
+
+    modes := []Mode{ECB, CBC, PCBC, CFB, CFB1, CFB8, CFB16, CFB32, CFB64, OFB, OFB8, CTR, NCFB, NOFB, BC}
+    paddings := []Padding{PKCS7Padding, ZeroPadding, X923Padding, ISO7816_4Padding}
+
+    for _, mode := range modes {
+        for _, padding := range paddings {
+            name := mode.String() + "-" + padding.String()
+
+            c := New().
+                WithKey(key).
+                WithIv(iv).
+                Aes().
+                WithMode(mode).
+                WithPadding(padding)
+
+            // CFB1 is slow
+            src := data
+            if mode == CFB1 {
+                src = data[:1024+3]
+            }
+
+            cypt := c.FromBytes(src).Encrypt()
+            assertError(cypt.Error(), name + "-Encrypt")
+
+            var enc bytes.Buffer
+            cyptTo := c.EncryptTo(&enc, iotest.HalfReader(bytes.NewReader(src)))
+            assertError(cyptTo.Error(), name + "-EncryptTo")
+
+            assert(cypt.ToBytes(), enc.Bytes(), name + "-EncryptTo-res")
+
+            var dec bytes.Buffer
+            cyptFrom := c.DecryptFrom(&dec, iotest.OneByteReader(bytes.NewReader(enc.Bytes())))
+            assertError(cyptFrom.Error(), name + "-DecryptFrom")
+
+            assert(src, dec.Bytes(), name + "-DecryptFrom-res")
+        }
+    }
+}
+
+func Test_EncryptTo_Stream(t *testing.T) {
+    assert := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    data := make([]byte, 50*1024+3)
+    rand.Read(data)
+
+    cases := []Cryptobin{
+        New().SetKey("dfertf12dfertf12").RC4(),
+        New().SetKey("dfertf12dfertf12dfertf12dfertf12").Chacha20("dfertf12dfer"),
+        New().SetKey("dfertf12dfertf12dfertf12dfertf12").Salsa20("dfertf12dfertf12"),
+        New().SetKey("dfertf12dfertf12").Wake(),
+        New().SetKey("dfertf12dfertf12dfertf12dfertf12").Panama(),
+    }
+
+    for _, c := range cases {
+        name := c.GetMultiple().String()
+
+        cypt := c.FromBytes(data).Encrypt()
+        assertError(cypt.Error(), name + "-Encrypt")
+
+        var enc bytes.Buffer
+        cyptTo := c.EncryptTo(&enc, iotest.HalfReader(bytes.NewReader(data)))
+        assertError(cyptTo.Error(), name + "-EncryptTo")
+
+        assert(cypt.ToBytes(), enc.Bytes(), name + "-EncryptTo-res")
+
+        var dec bytes.Buffer
+        cyptFrom := c.DecryptFrom(&dec, iotest.HalfReader(bytes.NewReader(enc.Bytes())))
+        assertError(cyptFrom.Error(), name + "-DecryptFrom")
+
+        assert(data, dec.Bytes(), name + "-DecryptFrom-res")
+    }
+}
+
+func Test_EncryptTo_Empty(t *testing.T) {
+    assert := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    c := New().
+        SetKey("dfertf12dfertf12").
+        SetIv("dfertf12dfertf12").
+        Aes().
+        CBC().
+        PKCS7Padding()
+
+    var enc bytes.Buffer
+    cyptTo := c.EncryptTo(&enc, bytes.NewReader(nil))
+    assertError(cyptTo.Error(), "EncryptTo_Empty")
+
+    assert(len(c.FromBytes(nil).Encrypt().ToBytes()), enc.Len(), "EncryptTo_Empty-res")
+}
+
+func Test_EncryptTo_Error(t *testing.T) {
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    data := []byte("test-pass")
+
+    var buf bytes.Buffer
+
+    cypt := New().
+        SetKey("dfertf12dfertf12").
+        Aes().
+        HCTR([]byte("kkinjkijeel2pass"), []byte("11injkijkol22plo")).
+        EncryptTo(&buf, bytes.NewReader(data))
+    assertNotErrorNil(cypt.Error(), "EncryptTo_Error-HCTR")
+
+    cypt = New().
+        SetKey("dfertf12dfertf12").
+        Xts("Aes", 0x3333333333).
+        EncryptTo(&buf, bytes.NewReader(data))
+    assertNotErrorNil(cypt.Error(), "EncryptTo_Error-Xts")
+
+    cypt = New().
+        SetKey("dfertf12dfertf12").
+        SetIv("dfertf12dfertf12").
+        Aes().
+        CBC().
+        NoPadding().
+        EncryptTo(&buf, bytes.NewReader(data))
+    assertNotErrorNil(cypt.Error(), "EncryptTo_Error-NoPadding")
+
+    cypt = New().
+        SetKey("dfertf12dfertf12").
+        SetIv("dfertf12dfertf12").
+        Aes().
+        CBC().
+        PKCS7Padding().
+        DecryptFrom(&buf, bytes.NewReader(data))
+    assertNotErrorNil(cypt.Error(), "DecryptFrom_Error-Size")
+}
+
+func Test_EncryptTo_AEAD(t *testing.T) {
+    assert := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    data := make([]byte, 20*1024+5)
+    rand.Read(data)
+
+    key16 := "dfertf12dfertf12"
+    key32 := "dfertf12dfertf12dfertf12dfertf12"
+
+    cases := []Cryptobin{
+        New().SetKey(key16).Aes().GCM("nonce1234567", "additional"),
+        New().SetKey(key16).Aes().CCM("nonce1234567"),
+        New().SetKey(key16).Aes().OCB("nonce1234567"),
+        New().SetKey(key16).Aes().EAX("nonce1234567"),
+        New().SetKey(key16).SM4().GCM("nonce1234567"),
+        New().SetKey(key16).Aria().GCM("nonce1234567"),
+        New().SetKey(key16).Camellia().GCM("nonce1234567"),
+        New().SetKey(key16).SM4().GCMSIV("nonce1234567", "additional"),
+        New().SetKey(key32).Kuznyechik().GCM("nonce1234567"),
+        New().SetKey(key32).Chacha20poly1305("nonce1234567", "additional"),
+        New().SetKey(key32).Chacha20poly1305X("nonce1234567nonce1234567", "additional"),
+    }
+
+    for _, c := range cases {
+        c = c.SegmentSize(1024)
+
+        name := c.GetMultiple().String() + "-" + c.GetMode().String()
+
+        var enc bytes.Buffer
+        cyptTo := c.EncryptTo(&enc, iotest.HalfReader(bytes.NewReader(data)))
+        assertError(cyptTo.Error(), name + "-EncryptTo")
+
+        var dec bytes.Buffer
+        cyptFrom := c.DecryptFrom(&dec, iotest.HalfReader(bytes.NewReader(enc.Bytes())))
+        assertError(cyptFrom.Error(), name + "-DecryptFrom")
+
+        assert(data, dec.Bytes(), name + "-DecryptFrom-res")
+    }
+}
+
+func Test_EncryptTo_AEADCheck(t *testing.T) {
+    data := make([]byte, 4*1024)
+    rand.Read(data)
+
+    c := New().
+        SetKey("dfertf12dfertf12").
+        SM4().
+        GCM("nonce1234567").
+        SegmentSize(1024)
+
+    var enc bytes.Buffer
+    cyptTo := c.EncryptTo(&enc, bytes.NewReader(data))
+    if err := cyptTo.Error(); err != nil {
+        t.Fatal(err)
+    }
+
+    encrypted := enc.Bytes()
+
+    // 头部长度: 1 + 2 + 16 + 7
+    headerSize := 26
+
+    check := func(t *testing.T, c Cryptobin, data []byte, want error) {
+        var dec bytes.Buffer
+        err := c.DecryptFrom(&dec, bytes.NewReader(data)).Error()
+        if !errors.Is(err, want) {
+            t.Errorf("DecryptFrom got %v, want %v", err, want)
+        }
+    }
+
+    t.Run("Truncate", func(t *testing.T) {
+        check(t, c, encrypted[:len(encrypted)-1024], streamaead.ErrOpen)
+    })
+
+    t.Run("Reorder", func(t *testing.T) {
+        reorder := append([]byte(nil), encrypted...)
+        copy(reorder[headerSize:], encrypted[headerSize+1024:headerSize+2048])
+        copy(reorder[headerSize+1024:], encrypted[headerSize:headerSize+1024])
+
+        check(t, c, reorder, streamaead.ErrOpen)
+    })
+
+    t.Run("Multiple", func(t *testing.T) {
+        check(t, c.Aes(), encrypted, streamaead.ErrInvalidID)
+    })
+
+    t.Run("Mode", func(t *testing.T) {
+        check(t, c.EAX("nonce1234567"), encrypted, streamaead.ErrInvalidID)
+    })
+}
@@ -53,3 +53,53 @@ type IPadding interface {
     // 解密
     UnPadding([]byte, IOption) ([]byte, error)
 }
+
+// 分组接口
+// 用于流式加密获取分组
+type IEncryptBlock interface {
+    // 分组
+    Block(IOption) (cipher.Block, error)
+}
+
+// 流密码接口
+// 用于流式加密获取流密码
+type IEncryptStream interface {
+    // 加密流
+    EncryptStream(IOption) (cipher.Stream, error)
+
+    // 解密流
+    DecryptStream(IOption) (cipher.Stream, error)
+}
+
+// 流式模式接口
+type IModeStream interface {
+    // 加密
+    EncryptStream(cipher.Block, IOption) (cipher.BlockMode, error)
+
+    // 解密
+    DecryptStream(cipher.Block, IOption) (cipher.BlockMode, error)
+}
+
+// 流式补码接口
+// 补码没有实现时使用 Padding 及 UnPadding 处理最后一块数据
+type IPaddingStream interface {
+    // 补码
+    StreamPadding([]byte, int, IOption) []byte
+
+    // 解密
+    StreamUnPadding([]byte, int, IOption) ([]byte, error)
+}
+
+// AEAD 模式接口
+// 用于分段 AEAD 流式加密
+type IModeAEAD interface {
+    // AEAD
+    AEAD(cipher.Block, IOption) (cipher.AEAD, error)
+}
+
+// AEAD 加密接口
+// 用于分段 AEAD 流式加密
+type IEncryptAEAD interface {
+    // AEAD
+    AEAD(IOption) (cipher.AEAD, error)
+}
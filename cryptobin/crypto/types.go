@@ -287,6 +287,24 @@ func (this Mode) String() string {
             return "BC"
         case HCTR:
             return "HCTR"
+        case SIV:
+            return "SIV"
+        case GCMSIV:
+            return "GCMSIV"
+        case KW:
+            return "KW"
+        case KWP:
+            return "KWP"
+        case FF1:
+            return "FF1"
+        case FF31:
+            return "FF31"
+        case XTS:
+            return "XTS"
+        case LRW:
+            return "LRW"
+        case HCTR2:
+            return "HCTR2"
         default:
             if TypeMode.Names().Has(this) {
                 return (TypeMode.Names().Get(this))()
@@ -319,6 +337,15 @@ const (
     EAX
     BC
     HCTR
+    SIV
+    GCMSIV
+    KW
+    KWP
+    FF1
+    FF31
+    XTS
+    LRW
+    HCTR2
     maxMode
 )
 
@@ -168,6 +168,7 @@ func (this Cryptobin) RC6() Cryptobin {
 
 // Xts
 // cipher 可用 [ Aes | Des | TripleDes | Tea | Xtea | Twofish | Blowfish | Cast5 | SM4]
+// 可以使用 XTS 模式代替, 支持任意 16 字节分组的加密类型
 func (this Cryptobin) Xts(cipher string, sectorNum uint64) Cryptobin {
     this.multiple = Xts
 
@@ -676,6 +677,114 @@ func (this Cryptobin) HCTR(tweak, hkey []byte) Cryptobin {
     return this
 }
 
+// SIV
+// 设置的密钥为 CTR 密钥, macKey 为 S2V 密钥
+// 多个附加数据依次作为 S2V 的输入
+func (this Cryptobin) SIV(macKey []byte, additional ...[]byte) Cryptobin {
+    this.mode = SIV
+
+    this.config.Set("siv_key", macKey)
+
+    if len(additional) > 0 {
+        this.config.Set("additional", additional)
+    }
+
+    return this
+}
+
+// GCMSIV
+// nonce 长度为 12 字节
+func (this Cryptobin) GCMSIV(nonce string, additional ...string) Cryptobin {
+    this.mode = GCMSIV
+
+    this.config.Set("nonce", []byte(nonce))
+
+    if len(additional) > 0 {
+        this.config.Set("additional", []byte(additional[0]))
+    }
+
+    return this
+}
+
+// KW 密钥包装 (RFC 3394)
+func (this Cryptobin) KW() Cryptobin {
+    this.mode = KW
+
+    return this
+}
+
+// KWP 带填充的密钥包装 (RFC 5649)
+func (this Cryptobin) KWP() Cryptobin {
+    this.mode = KWP
+
+    return this
+}
+
+// 格式保留加密 FF1
+// 加密结果的长度及字符集和原数据相同
+func (this Cryptobin) FPE(alphabet string, tweak []byte) Cryptobin {
+    this.mode = FF1
+
+    this.config.Set("alphabet", alphabet)
+    this.config.Set("tweak", tweak)
+
+    return this
+}
+
+// 格式保留加密 FF3-1
+// tweak 长度为 7 字节
+func (this Cryptobin) FF31(alphabet string, tweak []byte) Cryptobin {
+    this.mode = FF31
+
+    this.config.Set("alphabet", alphabet)
+    this.config.Set("tweak", tweak)
+
+    return this
+}
+
+// XTS 模式 (IEEE 1619)
+// 设置的密钥为数据密钥, tweakKey 为调整值密钥, sectorSize 为扇区大小,
+// 不设置扇区大小时整个数据作为一个扇区
+func (this Cryptobin) XTS(tweakKey []byte, sectorNum uint64, sectorSize ...int) Cryptobin {
+    this.mode = XTS
+
+    this.config.Set("tweak_key", tweakKey)
+    this.config.Set("sector_num", sectorNum)
+
+    if len(sectorSize) > 0 {
+        this.config.Set("sector_size", sectorSize[0])
+    }
+
+    return this
+}
+
+// LRW 模式
+// tweakKey 及 index 长度为 16 字节
+func (this Cryptobin) LRW(tweakKey, index []byte) Cryptobin {
+    this.mode = LRW
+
+    this.config.Set("tweak_key", tweakKey)
+    this.config.Set("index", index)
+
+    return this
+}
+
+// HCTR2 宽分组模式
+func (this Cryptobin) HCTR2(tweak []byte) Cryptobin {
+    this.mode = HCTR2
+
+    this.config.Set("tweak", tweak)
+
+    return this
+}
+
+// AEAD 模式流式加密时的分段长度
+func (this Cryptobin) SegmentSize(size int) Cryptobin {
+    this.config.Set("segment_size", size)
+
+    return this
+}
+
 // 使用模式
 func (this Cryptobin) ModeBy(mode Mode, cfg ...map[string]any) Cryptobin {
     this.mode = mode
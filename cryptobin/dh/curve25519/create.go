@@ -5,6 +5,8 @@ import (
     "crypto/rand"
     "encoding/pem"
 
+    "github.com/deatil/go-cryptobin/ecdh"
+    "github.com/deatil/go-cryptobin/jose"
     "github.com/deatil/go-cryptobin/dh/curve25519"
     cryptobin_pkcs8 "github.com/deatil/go-cryptobin/pkcs8"
 )
@@ -124,3 +126,49 @@ func (this Curve25519) CreateSecretKey() Curve25519 {
 
     return this
 }
+
+// ====================
+
+// 生成私钥 JWK 数据
+func (this Curve25519) CreateJWKPrivateKey() Curve25519 {
+    if this.privateKey == nil {
+        err := errors.New("Curve25519: privateKey error.")
+        return this.AppendError(err)
+    }
+
+    privateKey, err := ecdh.X25519().NewPrivateKey(this.privateKey.X)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    jwkPrivateKey, err := jose.MarshalJWK(privateKey)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.keyData = jwkPrivateKey
+
+    return this
+}
+
+// 生成公钥 JWK 数据
+func (this Curve25519) CreateJWKPublicKey() Curve25519 {
+    if this.publicKey == nil {
+        err := errors.New("Curve25519: publicKey error.")
+        return this.AppendError(err)
+    }
+
+    publicKey, err := ecdh.X25519().NewPublicKey(this.publicKey.Y)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    jwkPublicKey, err := jose.MarshalJWK(publicKey)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.keyData = jwkPublicKey
+
+    return this
+}
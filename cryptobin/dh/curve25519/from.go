@@ -5,6 +5,8 @@ import (
     "crypto/rand"
 
     "github.com/deatil/go-cryptobin/tool"
+    "github.com/deatil/go-cryptobin/ecdh"
+    "github.com/deatil/go-cryptobin/jose"
     "github.com/deatil/go-cryptobin/dh/curve25519"
 )
 
@@ -180,3 +182,45 @@ func (this Curve25519) FromPublicKeyDer(der []byte) Curve25519 {
 
     return this
 }
+
+// ==========
+
+// JWK 私钥或者公钥
+func (this Curve25519) FromJWK(key []byte) Curve25519 {
+    parsedKey, err := jose.ParseJWK(key)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    switch k := parsedKey.Key.(type) {
+        case *ecdh.PrivateKey:
+            if k.Curve() != ecdh.X25519() {
+                return this.AppendError(ErrNotPrivateKey)
+            }
+
+            priv := &curve25519.PrivateKey{}
+            priv.X = k.Bytes()
+            priv.PublicKey.Y = k.PublicKey().Bytes()
+
+            this.privateKey = priv
+            this.publicKey  = &priv.PublicKey
+        case *ecdh.PublicKey:
+            if k.Curve() != ecdh.X25519() {
+                return this.AppendError(ErrNotPublicKey)
+            }
+
+            pub := &curve25519.PublicKey{}
+            pub.Y = k.Bytes()
+
+            this.publicKey = pub
+        default:
+            return this.AppendError(ErrNotPublicKey)
+    }
+
+    return this
+}
+
+// JWK 私钥或者公钥
+func FromJWK(key []byte) Curve25519 {
+    return defaultCurve25519.FromJWK(key)
+}
@@ -35,3 +35,24 @@ func (this Curve25519) ToBase64String() string {
 func (this Curve25519) ToHexString() string {
     return cryptobin_tool.NewEncoding().HexEncode(this.secretData)
 }
+
+// ==========
+
+// 输出 JWK 数据, 有私钥时输出私钥 JWK, 否则输出公钥 JWK
+// 返回的错误只包含生成 JWK 时的错误
+func (this Curve25519) ToJWK() (string, error) {
+    this.keyData = nil
+    this.Errors = nil
+
+    if this.privateKey != nil {
+        this = this.CreateJWKPrivateKey()
+    } else {
+        this = this.CreateJWKPublicKey()
+    }
+
+    if err := this.Error(); err != nil {
+        return "", err
+    }
+
+    return string(this.keyData), nil
+}
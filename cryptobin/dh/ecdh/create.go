@@ -2,9 +2,13 @@ package ecdh
 
 import (
     "errors"
+    "math/big"
     "crypto/rand"
+    "crypto/ecdsa"
+    "crypto/elliptic"
     "encoding/pem"
 
+    "github.com/deatil/go-cryptobin/jose"
     "github.com/deatil/go-cryptobin/dh/ecdh"
     cryptobin_pkcs8 "github.com/deatil/go-cryptobin/pkcs8"
 )
@@ -124,3 +128,70 @@ func (this ECDH) CreateSecretKey() ECDH {
 
     return this
 }
+
+// ====================
+
+// 生成私钥 JWK 数据
+func (this ECDH) CreateJWKPrivateKey() ECDH {
+    if this.privateKey == nil {
+        err := errors.New("ecdh: privateKey error.")
+        return this.AppendError(err)
+    }
+
+    curve := this.privateKey.Curve
+
+    x, y := elliptic.Unmarshal(curve, this.privateKey.Y)
+    if x == nil {
+        err := errors.New("ecdh: privateKey error.")
+        return this.AppendError(err)
+    }
+
+    privateKey := &ecdsa.PrivateKey{
+        PublicKey: ecdsa.PublicKey{
+            Curve: curve,
+            X:     x,
+            Y:     y,
+        },
+        D: new(big.Int).SetBytes(this.privateKey.X),
+    }
+
+    jwkPrivateKey, err := jose.MarshalJWK(privateKey)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.keyData = jwkPrivateKey
+
+    return this
+}
+
+// 生成公钥 JWK 数据
+func (this ECDH) CreateJWKPublicKey() ECDH {
+    if this.publicKey == nil {
+        err := errors.New("ecdh: publicKey error.")
+        return this.AppendError(err)
+    }
+
+    curve := this.publicKey.Curve
+
+    x, y := elliptic.Unmarshal(curve, this.publicKey.Y)
+    if x == nil {
+        err := errors.New("ecdh: publicKey error.")
+        return this.AppendError(err)
+    }
+
+    publicKey := &ecdsa.PublicKey{
+        Curve: curve,
+        X:     x,
+        Y:     y,
+    }
+
+    jwkPublicKey, err := jose.MarshalJWK(publicKey)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.keyData = jwkPublicKey
+
+    return this
+}
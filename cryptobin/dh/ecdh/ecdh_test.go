@@ -94,3 +94,45 @@ func test_CreateSecretKeyWithPassword(t *testing.T) {
 
     assert(objSecret1.ToHexString(), objSecret2.ToHexString(), "CreateSecretKey-Equal")
 }
+
+func Test_JWK(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertJWKEqual := cryptobin_test.AssertJWKEqualT(t)
+
+    pri := FromJWK([]byte(cryptobin_test.JWKP256AlicePrivateKey))
+    assertError(pri.Error(), "Test_JWK-pri")
+
+    pub := FromJWK([]byte(cryptobin_test.JWKP256BobPublicKey))
+    assertError(pub.Error(), "Test_JWK-pub")
+
+    bob := FromJWK([]byte(cryptobin_test.JWKP256BobPrivateKey))
+    assertError(bob.Error(), "Test_JWK-bob")
+
+    secret1 := pri.WithPublicKey(bob.GetPublicKey()).CreateSecretKey()
+    assertError(secret1.Error(), "Test_JWK-CreateSecretKey")
+
+    secret2 := bob.WithPublicKey(pri.GetPublicKey()).CreateSecretKey()
+    assertError(secret2.Error(), "Test_JWK-CreateSecretKey")
+
+    assertEqual(secret1.ToHexString(), secret2.ToHexString(), "Test_JWK-CreateSecretKey")
+
+    assertJWKEqual(pri.CreateJWKPrivateKey().ToKeyBytes(), cryptobin_test.JWKP256AlicePrivateKey, "Test_JWK-CreateJWKPrivateKey")
+    assertJWKEqual(pub.CreateJWKPublicKey().ToKeyBytes(), cryptobin_test.JWKP256BobPublicKey, "Test_JWK-CreateJWKPublicKey")
+
+    priJWK, err := pri.ToJWK()
+    assertError(err, "Test_JWK-ToJWK-pri")
+    assertEqual(priJWK, pri.CreateJWKPrivateKey().ToKeyString(), "Test_JWK-ToJWK-pri")
+
+    pubJWK, err := pub.ToJWK()
+    assertError(err, "Test_JWK-ToJWK-pub")
+    assertEqual(pubJWK, pub.CreateJWKPublicKey().ToKeyString(), "Test_JWK-ToJWK-pub")
+
+    _, err = ECDH{}.ToJWK()
+    assertNotErrorNil(err, "Test_JWK-ToJWK-empty")
+
+    // X25519 密钥
+    res := FromJWK([]byte(cryptobin_test.JWKX25519PublicKey))
+    assertNotErrorNil(res.Error(), "Test_JWK-other")
+}
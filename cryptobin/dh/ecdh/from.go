@@ -3,8 +3,11 @@ package ecdh
 import (
     "io"
     "crypto/rand"
+    "crypto/ecdsa"
+    "crypto/elliptic"
 
     "github.com/deatil/go-cryptobin/tool"
+    "github.com/deatil/go-cryptobin/jose"
     "github.com/deatil/go-cryptobin/dh/ecdh"
 )
 
@@ -185,3 +188,43 @@ func (this ECDH) FromPublicKeyDer(der []byte) ECDH {
 
     return this
 }
+
+// ==========
+
+// JWK 私钥或者公钥
+func (this ECDH) FromJWK(key []byte) ECDH {
+    parsedKey, err := jose.ParseJWK(key)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    switch k := parsedKey.Key.(type) {
+        case *ecdsa.PrivateKey:
+            size := (k.Curve.Params().BitSize + 7) / 8
+
+            priv := &ecdh.PrivateKey{}
+            priv.X = k.D.FillBytes(make([]byte, size))
+            priv.PublicKey.Y = elliptic.Marshal(k.Curve, k.X, k.Y)
+            priv.PublicKey.Curve = k.Curve
+
+            this.privateKey = priv
+            this.publicKey  = &priv.PublicKey
+            this.curve      = k.Curve
+        case *ecdsa.PublicKey:
+            pub := &ecdh.PublicKey{}
+            pub.Y = elliptic.Marshal(k.Curve, k.X, k.Y)
+            pub.Curve = k.Curve
+
+            this.publicKey = pub
+            this.curve     = k.Curve
+        default:
+            return this.AppendError(ErrNotPublicKey)
+    }
+
+    return this
+}
+
+// JWK 私钥或者公钥
+func FromJWK(key []byte) ECDH {
+    return defaultECDH.FromJWK(key)
+}
@@ -6,6 +6,7 @@ import (
     "crypto/x509"
     "encoding/pem"
 
+    "github.com/deatil/go-cryptobin/jose"
     cryptobin_ecdh "github.com/deatil/go-cryptobin/ecdh"
     cryptobin_ecdh_key "github.com/deatil/go-cryptobin/ecdh/key"
     cryptobin_pkcs8 "github.com/deatil/go-cryptobin/pkcs8"
@@ -229,3 +230,39 @@ func (this ECDH) CreateSecretKey() ECDH {
 
     return this
 }
+
+// ====================
+
+// 生成私钥 JWK 数据
+func (this ECDH) CreateJWKPrivateKey() ECDH {
+    if this.privateKey == nil {
+        err := errors.New("ecdh: privateKey error.")
+        return this.AppendError(err)
+    }
+
+    jwkPrivateKey, err := jose.MarshalJWK(this.privateKey)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.keyData = jwkPrivateKey
+
+    return this
+}
+
+// 生成公钥 JWK 数据
+func (this ECDH) CreateJWKPublicKey() ECDH {
+    if this.publicKey == nil {
+        err := errors.New("ecdh: publicKey error.")
+        return this.AppendError(err)
+    }
+
+    jwkPublicKey, err := jose.MarshalJWK(this.publicKey)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.keyData = jwkPublicKey
+
+    return this
+}
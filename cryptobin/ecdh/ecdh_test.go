@@ -178,3 +178,39 @@ func test_CreateSecretKeyWithPassword(t *testing.T, name string) {
 
     assert(objSecret1.ToHexString(), objSecret2.ToHexString(), "CreateSecretKey-Equal")
 }
+
+func Test_JWK(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertJWKEqual := cryptobin_test.AssertJWKEqualT(t)
+
+    pri := FromJWK([]byte(cryptobin_test.JWKX25519PrivateKey))
+    assertError(pri.Error(), "Test_JWK-pri")
+
+    pub := FromJWK([]byte(cryptobin_test.JWKX25519PublicKey))
+    assertError(pub.Error(), "Test_JWK-pub")
+
+    // RFC 7748 6.1
+    secret := pri.WithPublicKey(pub.GetPublicKey()).CreateSecretKey()
+    assertError(secret.Error(), "Test_JWK-CreateSecretKey")
+    assertEqual(secret.ToHexString(), "4a5d9d5ba4ce2de1728e3bf480350f25e07e21c947d19e3376f09b3c1e161742", "Test_JWK-CreateSecretKey")
+
+    assertJWKEqual(pri.CreateJWKPrivateKey().ToKeyBytes(), cryptobin_test.JWKX25519PrivateKey, "Test_JWK-CreateJWKPrivateKey")
+    assertJWKEqual(pub.CreateJWKPublicKey().ToKeyBytes(), cryptobin_test.JWKX25519PublicKey, "Test_JWK-CreateJWKPublicKey")
+
+    priJWK, err := pri.ToJWK()
+    assertError(err, "Test_JWK-ToJWK-pri")
+    assertEqual(priJWK, pri.CreateJWKPrivateKey().ToKeyString(), "Test_JWK-ToJWK-pri")
+
+    pubJWK, err := pub.ToJWK()
+    assertError(err, "Test_JWK-ToJWK-pub")
+    assertEqual(pubJWK, pub.CreateJWKPublicKey().ToKeyString(), "Test_JWK-ToJWK-pub")
+
+    _, err = ECDH{}.ToJWK()
+    assertNotErrorNil(err, "Test_JWK-ToJWK-empty")
+
+    // SM2 密钥
+    res := FromJWK([]byte(cryptobin_test.JWKSM2PublicKey))
+    assertNotErrorNil(res.Error(), "Test_JWK-other")
+}
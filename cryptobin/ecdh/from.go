@@ -4,7 +4,10 @@ import (
     "io"
     "crypto/rand"
     "crypto/ecdh"
+    "crypto/ecdsa"
 
+    "github.com/deatil/go-cryptobin/jose"
+    cryptobin_ecdh "github.com/deatil/go-cryptobin/ecdh"
     cryptobin_tool "github.com/deatil/go-cryptobin/tool"
 )
 
@@ -201,3 +204,59 @@ func (this ECDH) FromECDHPublicKeyDer(der []byte) ECDH {
 
     return this
 }
+
+// ==========
+
+// JWK 私钥或者公钥
+func (this ECDH) FromJWK(key []byte) ECDH {
+    parsedKey, err := jose.ParseJWK(key)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    switch k := parsedKey.Key.(type) {
+        case *ecdsa.PrivateKey:
+            privateKey, err := k.ECDH()
+            if err != nil {
+                return this.AppendError(err)
+            }
+
+            this.privateKey = privateKey
+            this.publicKey  = privateKey.PublicKey()
+            this.curve      = privateKey.Curve()
+        case *ecdsa.PublicKey:
+            publicKey, err := k.ECDH()
+            if err != nil {
+                return this.AppendError(err)
+            }
+
+            this.publicKey = publicKey
+            this.curve     = publicKey.Curve()
+        case *cryptobin_ecdh.PrivateKey:
+            privateKey, err := cryptobin_ecdh.ToPrivateKey(k)
+            if err != nil {
+                return this.AppendError(err)
+            }
+
+            this.privateKey = privateKey
+            this.publicKey  = privateKey.PublicKey()
+            this.curve      = privateKey.Curve()
+        case *cryptobin_ecdh.PublicKey:
+            publicKey, err := cryptobin_ecdh.ToPublicKey(k)
+            if err != nil {
+                return this.AppendError(err)
+            }
+
+            this.publicKey = publicKey
+            this.curve     = publicKey.Curve()
+        default:
+            return this.AppendError(ErrNotPublicKey)
+    }
+
+    return this
+}
+
+// JWK 私钥或者公钥
+func FromJWK(key []byte) ECDH {
+    return defaultECDH.FromJWK(key)
+}
@@ -6,6 +6,7 @@ import (
     "crypto/x509"
     "encoding/pem"
 
+    "github.com/deatil/go-cryptobin/jose"
     cryptobin_pkcs1 "github.com/deatil/go-cryptobin/pkcs1"
     cryptobin_pkcs8 "github.com/deatil/go-cryptobin/pkcs8"
 )
@@ -190,3 +191,39 @@ func (this ECDSA) CreatePublicKey() ECDSA {
 
     return this
 }
+
+// ====================
+
+// 生成私钥 JWK 数据
+func (this ECDSA) CreateJWKPrivateKey() ECDSA {
+    if this.privateKey == nil {
+        err := errors.New("ecdsa: privateKey error.")
+        return this.AppendError(err)
+    }
+
+    jwkPrivateKey, err := jose.MarshalJWK(this.privateKey)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.keyData = jwkPrivateKey
+
+    return this
+}
+
+// 生成公钥 JWK 数据
+func (this ECDSA) CreateJWKPublicKey() ECDSA {
+    if this.publicKey == nil {
+        err := errors.New("ecdsa: publicKey error.")
+        return this.AppendError(err)
+    }
+
+    jwkPublicKey, err := jose.MarshalJWK(this.publicKey)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.keyData = jwkPublicKey
+
+    return this
+}
@@ -3,6 +3,7 @@ package ecdsa
 import (
     "testing"
     "crypto/rand"
+    "crypto/elliptic"
 
     cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
 )
@@ -453,3 +454,37 @@ func Test_PublicKey_Der(t *testing.T) {
 
     assertEqual(res.GetPublicKey(), obj.GetPublicKey(), "PublicKey_Der-res")
 }
+
+func Test_JWK(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertJWKEqual := cryptobin_test.AssertJWKEqualT(t)
+    assertTrue := cryptobin_test.AssertTrueT(t)
+
+    pri := FromJWK([]byte(cryptobin_test.JWKP256BobPrivateKey))
+    assertError(pri.Error(), "Test_JWK-pri")
+
+    pub := FromJWK([]byte(cryptobin_test.JWKP256BobPublicKey))
+    assertError(pub.Error(), "Test_JWK-pub")
+    assertTrue(pub.GetPublicKey().Equal(pri.GetPublicKey()), "Test_JWK-pub")
+    assertEqual(pri.GetPrivateKey().Curve, elliptic.P256(), "Test_JWK-pri-Curve")
+
+    assertJWKEqual(pri.CreateJWKPrivateKey().ToKeyBytes(), cryptobin_test.JWKP256BobPrivateKey, "Test_JWK-CreateJWKPrivateKey")
+    assertJWKEqual(pub.CreateJWKPublicKey().ToKeyBytes(), cryptobin_test.JWKP256BobPublicKey, "Test_JWK-CreateJWKPublicKey")
+
+    priJWK, err := pri.ToJWK()
+    assertError(err, "Test_JWK-ToJWK-pri")
+    assertEqual(priJWK, pri.CreateJWKPrivateKey().ToKeyString(), "Test_JWK-ToJWK-pri")
+
+    pubJWK, err := pub.ToJWK()
+    assertError(err, "Test_JWK-ToJWK-pub")
+    assertEqual(pubJWK, pub.CreateJWKPublicKey().ToKeyString(), "Test_JWK-ToJWK-pub")
+
+    _, err = ECDSA{}.ToJWK()
+    assertNotErrorNil(err, "Test_JWK-ToJWK-empty")
+
+    // Ed25519 密钥
+    res := FromJWK([]byte(cryptobin_test.JWKEd25519PublicKey))
+    assertNotErrorNil(res.Error(), "Test_JWK-other")
+}
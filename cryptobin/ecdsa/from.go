@@ -8,6 +8,7 @@ import (
     "crypto/ecdsa"
     "crypto/elliptic"
 
+    "github.com/deatil/go-cryptobin/jose"
     cryptobin_tool "github.com/deatil/go-cryptobin/tool"
 )
 
@@ -412,3 +413,32 @@ func (this ECDSA) FromHexString(data string) ECDSA {
 func FromHexString(data string) ECDSA {
     return defaultECDSA.FromHexString(data)
 }
+
+// ==========
+
+// JWK 私钥或者公钥
+func (this ECDSA) FromJWK(key []byte) ECDSA {
+    parsedKey, err := jose.ParseJWK(key)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    switch k := parsedKey.Key.(type) {
+        case *ecdsa.PrivateKey:
+            this.privateKey = k
+            this.publicKey  = &k.PublicKey
+            this.curve      = k.Curve
+        case *ecdsa.PublicKey:
+            this.publicKey = k
+            this.curve     = k.Curve
+        default:
+            return this.AppendError(ErrNotECPublicKey)
+    }
+
+    return this
+}
+
+// JWK 私钥或者公钥
+func FromJWK(key []byte) ECDSA {
+    return defaultECDSA.FromJWK(key)
+}
@@ -5,6 +5,7 @@ import (
     "crypto/rand"
     "encoding/pem"
 
+    "github.com/deatil/go-cryptobin/jose"
     "github.com/deatil/go-cryptobin/pkcs8"
     "github.com/deatil/go-cryptobin/ed448"
 )
@@ -104,3 +105,39 @@ func (this ED448) CreatePublicKey() ED448 {
 
     return this
 }
+
+// ====================
+
+// 生成私钥 JWK 数据
+func (this ED448) CreateJWKPrivateKey() ED448 {
+    if this.privateKey == nil {
+        err := errors.New("ED448: privateKey error.")
+        return this.AppendError(err)
+    }
+
+    jwkPrivateKey, err := jose.MarshalJWK(this.privateKey)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.keyData = jwkPrivateKey
+
+    return this
+}
+
+// 生成公钥 JWK 数据
+func (this ED448) CreateJWKPublicKey() ED448 {
+    if this.publicKey == nil {
+        err := errors.New("ED448: publicKey error.")
+        return this.AppendError(err)
+    }
+
+    jwkPublicKey, err := jose.MarshalJWK(this.publicKey)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.keyData = jwkPublicKey
+
+    return this
+}
@@ -161,3 +161,36 @@ func Test_MakePublicKey(t *testing.T) {
     assertError(ed.Error(), "MakePublicKey")
     assertEqual(newPubkey, testPubkey, "MakePublicKey")
 }
+
+func Test_JWK(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertJWKEqual := cryptobin_test.AssertJWKEqualT(t)
+    assertTrue := cryptobin_test.AssertTrueT(t)
+
+    pri := FromJWK([]byte(cryptobin_test.JWKEd448PrivateKey))
+    assertError(pri.Error(), "Test_JWK-pri")
+
+    pub := FromJWK([]byte(cryptobin_test.JWKEd448PublicKey))
+    assertError(pub.Error(), "Test_JWK-pub")
+    assertTrue(pub.GetPublicKey().Equal(pri.GetPublicKey()), "Test_JWK-pub")
+
+    assertJWKEqual(pri.CreateJWKPrivateKey().ToKeyBytes(), cryptobin_test.JWKEd448PrivateKey, "Test_JWK-CreateJWKPrivateKey")
+    assertJWKEqual(pub.CreateJWKPublicKey().ToKeyBytes(), cryptobin_test.JWKEd448PublicKey, "Test_JWK-CreateJWKPublicKey")
+
+    priJWK, err := pri.ToJWK()
+    assertError(err, "Test_JWK-ToJWK-pri")
+    assertEqual(priJWK, pri.CreateJWKPrivateKey().ToKeyString(), "Test_JWK-ToJWK-pri")
+
+    pubJWK, err := pub.ToJWK()
+    assertError(err, "Test_JWK-ToJWK-pub")
+    assertEqual(pubJWK, pub.CreateJWKPublicKey().ToKeyString(), "Test_JWK-ToJWK-pub")
+
+    _, err = ED448{}.ToJWK()
+    assertNotErrorNil(err, "Test_JWK-ToJWK-empty")
+
+    // Ed25519 密钥
+    res := FromJWK([]byte(cryptobin_test.JWKEd25519PublicKey))
+    assertNotErrorNil(res.Error(), "Test_JWK-other")
+}
@@ -4,6 +4,7 @@ import (
     "io"
     "crypto/rand"
 
+    "github.com/deatil/go-cryptobin/jose"
     "github.com/deatil/go-cryptobin/tool"
     "github.com/deatil/go-cryptobin/ed448"
 )
@@ -186,3 +187,30 @@ func (this ED448) FromHexString(data string) ED448 {
 func FromHexString(data string) ED448 {
     return defaultED448.FromHexString(data)
 }
+
+// ==========
+
+// JWK 私钥或者公钥
+func (this ED448) FromJWK(key []byte) ED448 {
+    parsedKey, err := jose.ParseJWK(key)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    switch k := parsedKey.Key.(type) {
+        case ed448.PrivateKey:
+            this.privateKey = k
+            this.publicKey  = k.Public().(ed448.PublicKey)
+        case ed448.PublicKey:
+            this.publicKey = k
+        default:
+            return this.AppendError(ErrNotEdPublicKey)
+    }
+
+    return this
+}
+
+// JWK 私钥或者公钥
+func FromJWK(key []byte) ED448 {
+    return defaultED448.FromJWK(key)
+}
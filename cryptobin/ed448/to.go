@@ -51,3 +51,24 @@ func (this ED448) ToVerifyInt() int {
 
     return 0
 }
+
+// ==========
+
+// 输出 JWK 数据, 有私钥时输出私钥 JWK, 否则输出公钥 JWK
+// 返回的错误只包含生成 JWK 时的错误
+func (this ED448) ToJWK() (string, error) {
+    this.keyData = nil
+    this.Errors = nil
+
+    if this.privateKey != nil {
+        this = this.CreateJWKPrivateKey()
+    } else {
+        this = this.CreateJWKPublicKey()
+    }
+
+    if err := this.Error(); err != nil {
+        return "", err
+    }
+
+    return string(this.keyData), nil
+}
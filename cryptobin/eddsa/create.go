@@ -6,6 +6,7 @@ import (
     "crypto/x509"
     "encoding/pem"
 
+    "github.com/deatil/go-cryptobin/jose"
     cryptobin_pkcs8 "github.com/deatil/go-cryptobin/pkcs8"
 )
 
@@ -107,3 +108,39 @@ func (this EdDSA) CreatePublicKey() EdDSA {
 
     return this
 }
+
+// ====================
+
+// 生成私钥 JWK 数据
+func (this EdDSA) CreateJWKPrivateKey() EdDSA {
+    if this.privateKey == nil {
+        err := errors.New("EdDSA: privateKey error.")
+        return this.AppendError(err)
+    }
+
+    jwkPrivateKey, err := jose.MarshalJWK(this.privateKey)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.keyData = jwkPrivateKey
+
+    return this
+}
+
+// 生成公钥 JWK 数据
+func (this EdDSA) CreateJWKPublicKey() EdDSA {
+    if this.publicKey == nil {
+        err := errors.New("EdDSA: publicKey error.")
+        return this.AppendError(err)
+    }
+
+    jwkPublicKey, err := jose.MarshalJWK(this.publicKey)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.keyData = jwkPublicKey
+
+    return this
+}
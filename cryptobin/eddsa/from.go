@@ -5,6 +5,7 @@ import (
     "crypto/rand"
     "crypto/ed25519"
 
+    "github.com/deatil/go-cryptobin/jose"
     cryptobin_tool "github.com/deatil/go-cryptobin/tool"
 )
 
@@ -186,3 +187,30 @@ func (this EdDSA) FromHexString(data string) EdDSA {
 func FromHexString(data string) EdDSA {
     return defaultEdDSA.FromHexString(data)
 }
+
+// ==========
+
+// JWK 私钥或者公钥
+func (this EdDSA) FromJWK(key []byte) EdDSA {
+    parsedKey, err := jose.ParseJWK(key)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    switch k := parsedKey.Key.(type) {
+        case ed25519.PrivateKey:
+            this.privateKey = k
+            this.publicKey  = k.Public().(ed25519.PublicKey)
+        case ed25519.PublicKey:
+            this.publicKey = k
+        default:
+            return this.AppendError(ErrNotEdPublicKey)
+    }
+
+    return this
+}
+
+// JWK 私钥或者公钥
+func FromJWK(key []byte) EdDSA {
+    return defaultEdDSA.FromJWK(key)
+}
@@ -3,6 +3,7 @@ package eddsa
 import (
     "crypto"
     "testing"
+    "encoding/hex"
 
     cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
 )
@@ -70,3 +71,37 @@ func Test_EdDSASign(t *testing.T) {
         useEdDSASign(t, opts)
     }
 }
+
+func Test_JWK(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertJWKEqual := cryptobin_test.AssertJWKEqualT(t)
+    assertTrue := cryptobin_test.AssertTrueT(t)
+
+    pri := FromJWK([]byte(cryptobin_test.JWKEd25519PrivateKey))
+    assertError(pri.Error(), "Test_JWK-pri")
+
+    pub := FromJWK([]byte(cryptobin_test.JWKEd25519PublicKey))
+    assertError(pub.Error(), "Test_JWK-pub")
+    assertTrue(pub.GetPublicKey().Equal(pri.GetPublicKey()), "Test_JWK-pub")
+    assertEqual(hex.EncodeToString(pri.GetPrivateKey().Seed()), "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60", "Test_JWK-pri-Seed")
+
+    assertJWKEqual(pri.CreateJWKPrivateKey().ToKeyBytes(), cryptobin_test.JWKEd25519PrivateKey, "Test_JWK-CreateJWKPrivateKey")
+    assertJWKEqual(pub.CreateJWKPublicKey().ToKeyBytes(), cryptobin_test.JWKEd25519PublicKey, "Test_JWK-CreateJWKPublicKey")
+
+    priJWK, err := pri.ToJWK()
+    assertError(err, "Test_JWK-ToJWK-pri")
+    assertEqual(priJWK, pri.CreateJWKPrivateKey().ToKeyString(), "Test_JWK-ToJWK-pri")
+
+    pubJWK, err := pub.ToJWK()
+    assertError(err, "Test_JWK-ToJWK-pub")
+    assertEqual(pubJWK, pub.CreateJWKPublicKey().ToKeyString(), "Test_JWK-ToJWK-pub")
+
+    _, err = EdDSA{}.ToJWK()
+    assertNotErrorNil(err, "Test_JWK-ToJWK-empty")
+
+    // X25519 密钥
+    res := FromJWK([]byte(cryptobin_test.JWKX25519PublicKey))
+    assertNotErrorNil(res.Error(), "Test_JWK-other")
+}
@@ -6,6 +6,7 @@ import (
     "crypto/x509"
     "encoding/pem"
 
+    "github.com/deatil/go-cryptobin/jose"
     cryptobin_rsa "github.com/deatil/go-cryptobin/rsa"
     cryptobin_pkcs1 "github.com/deatil/go-cryptobin/pkcs1"
     cryptobin_pkcs8 "github.com/deatil/go-cryptobin/pkcs8"
@@ -243,3 +244,39 @@ func (this RSA) CreateXMLPublicKey() RSA {
 
     return this
 }
+
+// ====================
+
+// 生成私钥 JWK 数据
+func (this RSA) CreateJWKPrivateKey() RSA {
+    if this.privateKey == nil {
+        err := errors.New("rsa: privateKey error.")
+        return this.AppendError(err)
+    }
+
+    jwkPrivateKey, err := jose.MarshalJWK(this.privateKey)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.keyData = jwkPrivateKey
+
+    return this
+}
+
+// 生成公钥 JWK 数据
+func (this RSA) CreateJWKPublicKey() RSA {
+    if this.publicKey == nil {
+        err := errors.New("rsa: publicKey error.")
+        return this.AppendError(err)
+    }
+
+    jwkPublicKey, err := jose.MarshalJWK(this.publicKey)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.keyData = jwkPublicKey
+
+    return this
+}
@@ -7,6 +7,7 @@ import (
     "crypto/rsa"
     "crypto/rand"
 
+    "github.com/deatil/go-cryptobin/jose"
     cryptobin_tool "github.com/deatil/go-cryptobin/tool"
 )
 
@@ -381,6 +382,33 @@ func FromXMLPublicKey(key []byte) RSA {
 
 // ==========
 
+// JWK 私钥或者公钥
+func (this RSA) FromJWK(key []byte) RSA {
+    parsedKey, err := jose.ParseJWK(key)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    switch k := parsedKey.Key.(type) {
+        case *rsa.PrivateKey:
+            this.privateKey = k
+            this.publicKey  = &k.PublicKey
+        case *rsa.PublicKey:
+            this.publicKey = k
+        default:
+            return this.AppendError(ErrNotRSAPublicKey)
+    }
+
+    return this
+}
+
+// JWK 私钥或者公钥
+func FromJWK(key []byte) RSA {
+    return defaultRSA.FromJWK(key)
+}
+
+// ==========
+
 // Pkcs12 Cert
 func (this RSA) FromPKCS12Cert(key []byte) RSA {
     privateKey, err := this.ParsePKCS12CertFromPEMWithPassword(key, "")
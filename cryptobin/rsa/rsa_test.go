@@ -235,3 +235,38 @@ func test_CreatePKCS1PrivateKeyWithPassword(t *testing.T, cipher string) {
         assertEqual(newPrikey, prikey, "Test_CreatePKCS1PrivateKeyWithPassword")
     })
 }
+
+func Test_JWK(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertJWKEqual := cryptobin_test.AssertJWKEqualT(t)
+    assertTrue := cryptobin_test.AssertTrueT(t)
+
+    pri := FromJWK([]byte(cryptobin_test.JWKRSAPrivateKey))
+    assertError(pri.Error(), "Test_JWK-pri")
+
+    pub := FromJWK([]byte(cryptobin_test.JWKRSAPublicKey))
+    assertError(pub.Error(), "Test_JWK-pub")
+    assertTrue(pub.GetPublicKey().Equal(pri.GetPublicKey()), "Test_JWK-pub")
+    assertEqual(pri.GetPrivateKey().E, 65537, "Test_JWK-pri-E")
+    assertEqual(pri.GetPrivateKey().N.BitLen(), 2048, "Test_JWK-pri-N")
+
+    assertJWKEqual(pri.CreateJWKPrivateKey().ToKeyBytes(), cryptobin_test.JWKRSAPrivateKey, "Test_JWK-CreateJWKPrivateKey")
+    assertJWKEqual(pub.CreateJWKPublicKey().ToKeyBytes(), cryptobin_test.JWKRSAPublicKey, "Test_JWK-CreateJWKPublicKey")
+
+    priJWK, err := pri.ToJWK()
+    assertError(err, "Test_JWK-ToJWK-pri")
+    assertEqual(priJWK, pri.CreateJWKPrivateKey().ToKeyString(), "Test_JWK-ToJWK-pri")
+
+    pubJWK, err := pub.ToJWK()
+    assertError(err, "Test_JWK-ToJWK-pub")
+    assertEqual(pubJWK, pub.CreateJWKPublicKey().ToKeyString(), "Test_JWK-ToJWK-pub")
+
+    _, err = RSA{}.ToJWK()
+    assertNotErrorNil(err, "Test_JWK-ToJWK-empty")
+
+    // Ed25519 密钥
+    res := FromJWK([]byte(cryptobin_test.JWKEd25519PublicKey))
+    assertNotErrorNil(res.Error(), "Test_JWK-other")
+}
@@ -5,6 +5,7 @@ import (
     "crypto/rand"
     "encoding/pem"
 
+    "github.com/deatil/go-cryptobin/jose"
     cryptobin_sm2 "github.com/deatil/go-cryptobin/gm/sm2"
     cryptobin_pkcs1 "github.com/deatil/go-cryptobin/pkcs1"
     cryptobin_pkcs8 "github.com/deatil/go-cryptobin/pkcs8"
@@ -189,3 +190,39 @@ func (this SM2) CreatePublicKey() SM2 {
 
     return this
 }
+
+// ====================
+
+// 生成私钥 JWK 数据
+func (this SM2) CreateJWKPrivateKey() SM2 {
+    if this.privateKey == nil {
+        err := errors.New("SM2: privateKey error.")
+        return this.AppendError(err)
+    }
+
+    jwkPrivateKey, err := jose.MarshalJWK(this.privateKey)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.keyData = jwkPrivateKey
+
+    return this
+}
+
+// 生成公钥 JWK 数据
+func (this SM2) CreateJWKPublicKey() SM2 {
+    if this.publicKey == nil {
+        err := errors.New("SM2: publicKey error.")
+        return this.AppendError(err)
+    }
+
+    jwkPublicKey, err := jose.MarshalJWK(this.publicKey)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.keyData = jwkPublicKey
+
+    return this
+}
@@ -0,0 +1,50 @@
+package sm2
+
+import (
+    "errors"
+    "crypto/rand"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+)
+
+// 生成 GM/T 0009 数字信封, 使用签名公钥及 SM4 封装加密密钥对
+// 公钥为签名公钥, 返回数据使用 ToBytes 等获取
+func (this SM2) CreateEnvelopedKey(key *sm2.PrivateKey) SM2 {
+    if this.publicKey == nil {
+        err := errors.New("SM2: publicKey error.")
+        return this.AppendError(err)
+    }
+
+    if key == nil {
+        err := errors.New("SM2: enveloped key error.")
+        return this.AppendError(err)
+    }
+
+    parsedData, err := sm2.MarshalEnvelopedPrivateKey(rand.Reader, this.publicKey, key)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.parsedData = parsedData
+
+    return this
+}
+
+// 使用签名私钥解开 GM/T 0009 数字信封
+// 返回的对象私钥及公钥为解出的加密密钥对
+func (this SM2) OpenEnvelopedKey() SM2 {
+    if this.privateKey == nil {
+        err := errors.New("SM2: privateKey error.")
+        return this.AppendError(err)
+    }
+
+    key, err := sm2.ParseEnvelopedPrivateKey(this.privateKey, this.data)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.privateKey = key
+    this.publicKey = &key.PublicKey
+
+    return this
+}
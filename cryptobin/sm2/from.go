@@ -7,6 +7,7 @@ import (
     "math/big"
     "crypto/rand"
 
+    "github.com/deatil/go-cryptobin/jose"
     "github.com/deatil/go-cryptobin/gm/sm2"
     cryptobin_tool "github.com/deatil/go-cryptobin/tool"
 )
@@ -417,3 +418,30 @@ func (this SM2) FromHexString(data string) SM2 {
 func FromHexString(data string) SM2 {
     return defaultSM2.FromHexString(data)
 }
+
+// ==========
+
+// JWK 私钥或者公钥
+func (this SM2) FromJWK(key []byte) SM2 {
+    parsedKey, err := jose.ParseJWK(key)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    switch k := parsedKey.Key.(type) {
+        case *sm2.PrivateKey:
+            this.privateKey = k
+            this.publicKey  = &k.PublicKey
+        case *sm2.PublicKey:
+            this.publicKey = k
+        default:
+            return this.AppendError(ErrNotECPublicKey)
+    }
+
+    return this
+}
+
+// JWK 私钥或者公钥
+func FromJWK(key []byte) SM2 {
+    return defaultSM2.FromJWK(key)
+}
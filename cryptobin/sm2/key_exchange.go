@@ -0,0 +1,146 @@
+package sm2
+
+import (
+    "errors"
+    "crypto/rand"
+    "crypto/subtle"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+)
+
+// 默认用户 id
+var defaultUID = []byte("1234567812345678")
+
+// 密钥交换, 交换数据使用未压缩公钥字节格式
+// 发起方: NewKeyExchange -> Init -> 发送 rA -> ConfirmResponder -> 发送 sA
+// 响应方: NewKeyExchange -> 接收 rA -> Respond -> 发送 rB, sB -> ConfirmInitiator
+type KeyExchange struct {
+    // 自己私钥
+    privateKey *sm2.PrivateKey
+
+    // 对方公钥
+    peerPublicKey *sm2.PublicKey
+
+    // 双方 id
+    uid, peerUID []byte
+
+    // 协商密钥长度
+    keyLen int
+
+    // 临时私钥
+    ephemeral *sm2.PrivateKey
+
+    // 响应方协商结果
+    key, s2 []byte
+}
+
+// 生成密钥交换, 私钥为自己私钥, 公钥为对方公钥
+// uid 为自己 id, peerUID 为对方 id, 为空时使用默认 id
+func (this SM2) NewKeyExchange(uid, peerUID []byte, keyLen int) (*KeyExchange, error) {
+    if this.privateKey == nil {
+        return nil, errors.New("SM2: privateKey error.")
+    }
+
+    if this.publicKey == nil {
+        return nil, errors.New("SM2: publicKey error.")
+    }
+
+    if keyLen <= 0 {
+        return nil, errors.New("SM2: keyLen error.")
+    }
+
+    if len(uid) == 0 {
+        uid = defaultUID
+    }
+
+    if len(peerUID) == 0 {
+        peerUID = defaultUID
+    }
+
+    return &KeyExchange{
+        privateKey:    this.privateKey,
+        peerPublicKey: this.publicKey,
+        uid:           uid,
+        peerUID:       peerUID,
+        keyLen:        keyLen,
+    }, nil
+}
+
+// 发起方生成临时公钥 rA
+func (this *KeyExchange) Init() ([]byte, error) {
+    ephemeral, err := sm2.GenerateKey(rand.Reader)
+    if err != nil {
+        return nil, err
+    }
+
+    this.ephemeral = ephemeral
+
+    return sm2.ToPublicKey(&ephemeral.PublicKey), nil
+}
+
+// 响应方根据 rA 生成临时公钥 rB 及验证值 sB
+func (this *KeyExchange) Respond(rA []byte) (rB []byte, sB []byte, err error) {
+    ra, err := sm2.NewPublicKey(rA)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    ephemeral, err := sm2.GenerateKey(rand.Reader)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    key, s1, s2, err := sm2.KeyExchangeB(this.keyLen, this.peerUID, this.uid, this.privateKey, this.peerPublicKey, ephemeral, ra)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    this.ephemeral = ephemeral
+    this.key = key
+    this.s2 = s2
+
+    return sm2.ToPublicKey(&ephemeral.PublicKey), s1, nil
+}
+
+// 发起方验证响应方数据, 返回共享密钥及验证值 sA
+func (this *KeyExchange) ConfirmResponder(rB, sB []byte) (key []byte, sA []byte, err error) {
+    if this.ephemeral == nil {
+        return nil, nil, errors.New("SM2: key exchange not init.")
+    }
+
+    rb, err := sm2.NewPublicKey(rB)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    key, s1, s2, err := sm2.KeyExchangeA(this.keyLen, this.uid, this.peerUID, this.privateKey, this.peerPublicKey, this.ephemeral, rb)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    if subtle.ConstantTimeCompare(s1, sB) != 1 {
+        return nil, nil, errors.New("SM2: key exchange confirm responder fail.")
+    }
+
+    return key, s2, nil
+}
+
+// 响应方验证发起方验证值 sA, 返回共享密钥
+func (this *KeyExchange) ConfirmInitiator(sA []byte) ([]byte, error) {
+    if this.key == nil {
+        return nil, errors.New("SM2: key exchange not respond.")
+    }
+
+    if subtle.ConstantTimeCompare(this.s2, sA) != 1 {
+        return nil, errors.New("SM2: key exchange confirm initiator fail.")
+    }
+
+    return this.key, nil
+}
+
+// 清除内部数据
+func (this *KeyExchange) Reset() {
+    this.ephemeral = nil
+    this.key = nil
+    this.s2 = nil
+}
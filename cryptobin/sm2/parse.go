@@ -12,6 +12,7 @@ import (
 var (
     ErrKeyMustBePEMEncoded = errors.New("invalid key: Key must be a PEM encoded PKCS1 or PKCS8 key")
     ErrNotECPrivateKey     = errors.New("key is not a valid SM2 private key")
+    ErrNotECPublicKey      = errors.New("key is not a valid SM2 public key")
 )
 
 // 解析私钥，默认为 PKCS8
@@ -721,3 +721,117 @@ func Test_DecryptWithBCJavaEndata(t *testing.T) {
 
     assertEqual(deData, check, "DecryptWithBCJavaEndata-Dedata")
 }
+
+func Test_KeyExchange(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    alice := GenerateKey()
+    bob := GenerateKey()
+
+    alicePub := alice.CreatePublicKey().ToKeyString()
+    bobPub := bob.CreatePublicKey().ToKeyString()
+
+    uidA := []byte("Alice")
+    uidB := []byte("Bob")
+
+    initiator, err := alice.FromPublicKey([]byte(bobPub)).NewKeyExchange(uidA, uidB, 16)
+    assertError(err, "KeyExchange-initiator")
+
+    responder, err := bob.FromPublicKey([]byte(alicePub)).NewKeyExchange(uidB, uidA, 16)
+    assertError(err, "KeyExchange-responder")
+
+    rA, err := initiator.Init()
+    assertError(err, "KeyExchange-Init")
+
+    rB, sB, err := responder.Respond(rA)
+    assertError(err, "KeyExchange-Respond")
+
+    key1, sA, err := initiator.ConfirmResponder(rB, sB)
+    assertError(err, "KeyExchange-ConfirmResponder")
+
+    key2, err := responder.ConfirmInitiator(sA)
+    assertError(err, "KeyExchange-ConfirmInitiator")
+
+    assertEqual(len(key1), 16, "KeyExchange-len")
+    assertEqual(key1, key2, "KeyExchange")
+
+    // 错误验证值
+    _, err = responder.ConfirmInitiator(sB)
+    assertNotErrorNil(err, "KeyExchange-ConfirmInitiator-bad")
+
+    // 错误 id
+    responder2, _ := bob.FromPublicKey([]byte(alicePub)).NewKeyExchange(uidB, []byte("Eve"), 16)
+    rB2, sB2, err := responder2.Respond(rA)
+    assertError(err, "KeyExchange-Respond-2")
+
+    _, _, err = initiator.ConfirmResponder(rB2, sB2)
+    assertNotErrorNil(err, "KeyExchange-ConfirmResponder-bad-id")
+}
+
+func Test_EnvelopedKey(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotEmpty := cryptobin_test.AssertNotEmptyT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    signKey := GenerateKey()
+    encKey := GenerateKey()
+
+    signPub := signKey.CreatePublicKey().ToKeyString()
+    encPri := encKey.CreatePrivateKey().ToKeyString()
+
+    enveloped := New().
+        FromPublicKey([]byte(signPub)).
+        CreateEnvelopedKey(encKey.GetPrivateKey())
+    assertError(enveloped.Error(), "EnvelopedKey-Create")
+    assertNotEmpty(enveloped.ToBase64String(), "EnvelopedKey-Create")
+
+    opened := New().
+        FromBase64String(enveloped.ToBase64String()).
+        WithPrivateKey(signKey.GetPrivateKey()).
+        OpenEnvelopedKey()
+    assertError(opened.Error(), "EnvelopedKey-Open")
+    assertEqual(opened.CreatePrivateKey().ToKeyString(), encPri, "EnvelopedKey-Open")
+
+    opened2 := New().
+        FromBytes(enveloped.ToBytes()).
+        WithPrivateKey(encKey.GetPrivateKey()).
+        OpenEnvelopedKey()
+    assertNotErrorNil(opened2.Error(), "EnvelopedKey-Open-bad-key")
+}
+
+func Test_JWK(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertJWKEqual := cryptobin_test.AssertJWKEqualT(t)
+    assertTrue := cryptobin_test.AssertTrueT(t)
+
+    pri := FromJWK([]byte(cryptobin_test.JWKSM2PrivateKey))
+    assertError(pri.Error(), "Test_JWK-pri")
+
+    pub := FromJWK([]byte(cryptobin_test.JWKSM2PublicKey))
+    assertError(pub.Error(), "Test_JWK-pub")
+    assertTrue(pub.GetPublicKey().Equal(pri.GetPublicKey()), "Test_JWK-pub")
+    assertEqual(pri.GetPrivateKey().D.Text(16), "3945208f7b2144b13f36e38ac6d39f95889393692860b51a42fb81ef4df7c5b8", "Test_JWK-pri-D")
+
+    assertJWKEqual(pri.CreateJWKPrivateKey().ToKeyBytes(), cryptobin_test.JWKSM2PrivateKey, "Test_JWK-CreateJWKPrivateKey")
+    assertJWKEqual(pub.CreateJWKPublicKey().ToKeyBytes(), cryptobin_test.JWKSM2PublicKey, "Test_JWK-CreateJWKPublicKey")
+
+    priJWK, err := pri.ToJWK()
+    assertError(err, "Test_JWK-ToJWK-pri")
+    assertEqual(priJWK, pri.CreateJWKPrivateKey().ToKeyString(), "Test_JWK-ToJWK-pri")
+
+    pubJWK, err := pub.ToJWK()
+    assertError(err, "Test_JWK-ToJWK-pub")
+    assertEqual(pubJWK, pub.CreateJWKPublicKey().ToKeyString(), "Test_JWK-ToJWK-pub")
+
+    _, err = SM2{}.ToJWK()
+    assertNotErrorNil(err, "Test_JWK-ToJWK-empty")
+
+    // P-256 密钥
+    res := FromJWK([]byte(cryptobin_test.JWKP256BobPublicKey))
+    assertNotErrorNil(res.Error(), "Test_JWK-other")
+}
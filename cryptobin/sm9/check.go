@@ -0,0 +1,49 @@
+package sm9
+
+import (
+    "bytes"
+)
+
+// 检测主私钥或者用户私钥和主公钥是否匹配
+// 曲线点坐标可能未归一化, 这里比较编码后的数据
+func (this SM9) CheckKeyPair() bool {
+    checked := false
+
+    if this.signMasterPublicKey != nil {
+        if this.signMasterPrivateKey != nil {
+            if !bytes.Equal(this.signMasterPrivateKey.PublicKey().Marshal(), this.signMasterPublicKey.Marshal()) {
+                return false
+            }
+
+            checked = true
+        }
+
+        if this.signPrivateKey != nil {
+            if !bytes.Equal(this.signPrivateKey.PublicKey().Marshal(), this.signMasterPublicKey.Marshal()) {
+                return false
+            }
+
+            checked = true
+        }
+    }
+
+    if this.encryptMasterPublicKey != nil {
+        if this.encryptMasterPrivateKey != nil {
+            if !bytes.Equal(this.encryptMasterPrivateKey.PublicKey().Marshal(), this.encryptMasterPublicKey.Marshal()) {
+                return false
+            }
+
+            checked = true
+        }
+
+        if this.encryptPrivateKey != nil {
+            if !bytes.Equal(this.encryptPrivateKey.PublicKey().Marshal(), this.encryptMasterPublicKey.Marshal()) {
+                return false
+            }
+
+            checked = true
+        }
+    }
+
+    return checked
+}
@@ -0,0 +1,195 @@
+package sm9
+
+import (
+    "errors"
+    "crypto/rand"
+    "encoding/pem"
+
+    "github.com/deatil/go-cryptobin/pkcs8"
+    "github.com/deatil/go-cryptobin/gm/sm9"
+)
+
+type (
+    // 配置
+    Opts       = pkcs8.Opts
+    // PBKDF2 配置
+    PBKDF2Opts = pkcs8.PBKDF2Opts
+    // Scrypt 配置
+    ScryptOpts = pkcs8.ScryptOpts
+)
+
+var (
+    // 获取 Cipher 类型
+    GetCipherFromName = pkcs8.GetCipherFromName
+    // 获取 hash 类型
+    GetHashFromName   = pkcs8.GetHashFromName
+)
+
+// 生成签名主私钥 pem 数据
+func (this SM9) CreateSignMasterPrivateKey() SM9 {
+    if this.signMasterPrivateKey == nil {
+        err := errors.New("SM9: signMasterPrivateKey error.")
+        return this.AppendError(err)
+    }
+
+    return this.createPrivateKey(this.signMasterPrivateKey)
+}
+
+// 生成签名主私钥带密码 pem 数据
+// CreateSignMasterPrivateKeyWithPassword("123", "AES256CBC", "SHA256")
+func (this SM9) CreateSignMasterPrivateKeyWithPassword(password string, opts ...any) SM9 {
+    if this.signMasterPrivateKey == nil {
+        err := errors.New("SM9: signMasterPrivateKey error.")
+        return this.AppendError(err)
+    }
+
+    return this.createPrivateKeyWithPassword(this.signMasterPrivateKey, password, opts...)
+}
+
+// 生成签名主公钥 pem 数据
+func (this SM9) CreateSignMasterPublicKey() SM9 {
+    if this.signMasterPublicKey == nil {
+        err := errors.New("SM9: signMasterPublicKey error.")
+        return this.AppendError(err)
+    }
+
+    return this.createPublicKey(this.signMasterPublicKey)
+}
+
+// 生成签名用户私钥 pem 数据
+func (this SM9) CreateSignPrivateKey() SM9 {
+    if this.signPrivateKey == nil {
+        err := errors.New("SM9: signPrivateKey error.")
+        return this.AppendError(err)
+    }
+
+    return this.createPrivateKey(this.signPrivateKey)
+}
+
+// 生成签名用户私钥带密码 pem 数据
+func (this SM9) CreateSignPrivateKeyWithPassword(password string, opts ...any) SM9 {
+    if this.signPrivateKey == nil {
+        err := errors.New("SM9: signPrivateKey error.")
+        return this.AppendError(err)
+    }
+
+    return this.createPrivateKeyWithPassword(this.signPrivateKey, password, opts...)
+}
+
+// ==========
+
+// 生成加密主私钥 pem 数据
+func (this SM9) CreateEncryptMasterPrivateKey() SM9 {
+    if this.encryptMasterPrivateKey == nil {
+        err := errors.New("SM9: encryptMasterPrivateKey error.")
+        return this.AppendError(err)
+    }
+
+    return this.createPrivateKey(this.encryptMasterPrivateKey)
+}
+
+// 生成加密主私钥带密码 pem 数据
+func (this SM9) CreateEncryptMasterPrivateKeyWithPassword(password string, opts ...any) SM9 {
+    if this.encryptMasterPrivateKey == nil {
+        err := errors.New("SM9: encryptMasterPrivateKey error.")
+        return this.AppendError(err)
+    }
+
+    return this.createPrivateKeyWithPassword(this.encryptMasterPrivateKey, password, opts...)
+}
+
+// 生成加密主公钥 pem 数据
+func (this SM9) CreateEncryptMasterPublicKey() SM9 {
+    if this.encryptMasterPublicKey == nil {
+        err := errors.New("SM9: encryptMasterPublicKey error.")
+        return this.AppendError(err)
+    }
+
+    return this.createPublicKey(this.encryptMasterPublicKey)
+}
+
+// 生成加密用户私钥 pem 数据
+func (this SM9) CreateEncryptPrivateKey() SM9 {
+    if this.encryptPrivateKey == nil {
+        err := errors.New("SM9: encryptPrivateKey error.")
+        return this.AppendError(err)
+    }
+
+    return this.createPrivateKey(this.encryptPrivateKey)
+}
+
+// 生成加密用户私钥带密码 pem 数据
+func (this SM9) CreateEncryptPrivateKeyWithPassword(password string, opts ...any) SM9 {
+    if this.encryptPrivateKey == nil {
+        err := errors.New("SM9: encryptPrivateKey error.")
+        return this.AppendError(err)
+    }
+
+    return this.createPrivateKeyWithPassword(this.encryptPrivateKey, password, opts...)
+}
+
+// ==========
+
+// 生成私钥 pem 数据
+func (this SM9) createPrivateKey(key any) SM9 {
+    x509PrivateKey, err := sm9.MarshalPrivateKey(key)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    privateBlock := &pem.Block{
+        Type:  "PRIVATE KEY",
+        Bytes: x509PrivateKey,
+    }
+
+    this.keyData = pem.EncodeToMemory(privateBlock)
+
+    return this
+}
+
+// 生成 PKCS8 私钥带密码 pem 数据
+func (this SM9) createPrivateKeyWithPassword(key any, password string, opts ...any) SM9 {
+    opt, err := pkcs8.ParseOpts(opts...)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    // 生成私钥
+    x509PrivateKey, err := sm9.MarshalPrivateKey(key)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    // 生成加密数据
+    privateBlock, err := pkcs8.EncryptPEMBlock(
+        rand.Reader,
+        "ENCRYPTED PRIVATE KEY",
+        x509PrivateKey,
+        []byte(password),
+        opt,
+    )
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.keyData = pem.EncodeToMemory(privateBlock)
+
+    return this
+}
+
+// 生成公钥 pem 数据
+func (this SM9) createPublicKey(key any) SM9 {
+    x509PublicKey, err := sm9.MarshalPublicKey(key)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    publicBlock := &pem.Block{
+        Type:  "PUBLIC KEY",
+        Bytes: x509PublicKey,
+    }
+
+    this.keyData = pem.EncodeToMemory(publicBlock)
+
+    return this
+}
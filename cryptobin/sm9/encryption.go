@@ -0,0 +1,88 @@
+package sm9
+
+import (
+    "errors"
+    "crypto/rand"
+
+    "github.com/deatil/go-cryptobin/gm/sm9"
+)
+
+// 加密主公钥加密, 需要设置接收方的 id 及 hid
+// 返回 C1 || C3 || C2 格式的密文内容
+func (this SM9) Encrypt() SM9 {
+    if this.encryptMasterPublicKey == nil {
+        err := errors.New("SM9: encryptMasterPublicKey error.")
+        return this.AppendError(err)
+    }
+
+    parsedData, err := sm9.Encrypt(rand.Reader, this.encryptMasterPublicKey, this.id, this.getHid(EncryptHid), this.data, this.getOpts())
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.parsedData = parsedData
+
+    return this
+}
+
+// 加密用户私钥解密, 需要设置接收方的 id
+func (this SM9) Decrypt() SM9 {
+    if this.encryptPrivateKey == nil {
+        err := errors.New("SM9: encryptPrivateKey error.")
+        return this.AppendError(err)
+    }
+
+    parsedData, err := sm9.Decrypt(this.encryptPrivateKey, this.id, this.data, this.getOpts())
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.parsedData = parsedData
+
+    return this
+}
+
+// ================
+
+// 加密主公钥加密，返回 asn.1 编码格式的密文内容
+func (this SM9) EncryptASN1() SM9 {
+    if this.encryptMasterPublicKey == nil {
+        err := errors.New("SM9: encryptMasterPublicKey error.")
+        return this.AppendError(err)
+    }
+
+    parsedData, err := sm9.EncryptASN1(rand.Reader, this.encryptMasterPublicKey, this.id, this.getHid(EncryptHid), this.data, this.getOpts())
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.parsedData = parsedData
+
+    return this
+}
+
+// 加密用户私钥解密，解析 asn.1 编码格式的密文内容
+// 加密方式使用密文中记录的类型
+func (this SM9) DecryptASN1() SM9 {
+    if this.encryptPrivateKey == nil {
+        err := errors.New("SM9: encryptPrivateKey error.")
+        return this.AppendError(err)
+    }
+
+    parsedData, err := sm9.DecryptASN1(this.encryptPrivateKey, this.id, this.data, this.getOpts())
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.parsedData = parsedData
+
+    return this
+}
+
+// 加密设置
+func (this SM9) getOpts() *sm9.Opts {
+    return &sm9.Opts{
+        Encrypt: this.encrypt,
+        Hash:    this.hash,
+    }
+}
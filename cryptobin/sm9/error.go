@@ -0,0 +1,17 @@
+package sm9
+
+import (
+    "github.com/deatil/go-cryptobin/tool"
+)
+
+// 添加错误
+func (this SM9) AppendError(err ...error) SM9 {
+    this.Errors = append(this.Errors, err...)
+
+    return this
+}
+
+// 获取错误
+func (this SM9) Error() error {
+    return tool.NewError(this.Errors...)
+}
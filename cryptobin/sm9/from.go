@@ -0,0 +1,263 @@
+package sm9
+
+import (
+    "io"
+    "errors"
+    "crypto"
+    "crypto/rand"
+
+    "github.com/deatil/go-cryptobin/tool"
+    "github.com/deatil/go-cryptobin/gm/sm9"
+)
+
+// 生成签名主密钥
+func (this SM9) GenerateSignMasterKeyWithSeed(reader io.Reader) SM9 {
+    masterKey, err := sm9.GenerateSignMasterKey(reader)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.signMasterPrivateKey = masterKey
+    this.signMasterPublicKey  = masterKey.PublicKey()
+
+    return this
+}
+
+// 生成签名主密钥
+func GenerateSignMasterKeyWithSeed(reader io.Reader) SM9 {
+    return defaultSM9.GenerateSignMasterKeyWithSeed(reader)
+}
+
+// 生成签名主密钥
+func (this SM9) GenerateSignMasterKey() SM9 {
+    return this.GenerateSignMasterKeyWithSeed(rand.Reader)
+}
+
+// 生成签名主密钥
+func GenerateSignMasterKey() SM9 {
+    return defaultSM9.GenerateSignMasterKey()
+}
+
+// 生成加密主密钥
+func (this SM9) GenerateEncryptMasterKeyWithSeed(reader io.Reader) SM9 {
+    masterKey, err := sm9.GenerateEncryptMasterKey(reader)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.encryptMasterPrivateKey = masterKey
+    this.encryptMasterPublicKey  = masterKey.PublicKey()
+
+    return this
+}
+
+// 生成加密主密钥
+func GenerateEncryptMasterKeyWithSeed(reader io.Reader) SM9 {
+    return defaultSM9.GenerateEncryptMasterKeyWithSeed(reader)
+}
+
+// 生成加密主密钥
+func (this SM9) GenerateEncryptMasterKey() SM9 {
+    return this.GenerateEncryptMasterKeyWithSeed(rand.Reader)
+}
+
+// 生成加密主密钥
+func GenerateEncryptMasterKey() SM9 {
+    return defaultSM9.GenerateEncryptMasterKey()
+}
+
+// ==========
+
+// 使用签名主私钥, 根据 id 及 hid 生成签名用户私钥
+func (this SM9) GenerateSignUserKey() SM9 {
+    if this.signMasterPrivateKey == nil {
+        err := errors.New("SM9: signMasterPrivateKey error.")
+        return this.AppendError(err)
+    }
+
+    userKey, err := sm9.GenerateSignUserKey(this.signMasterPrivateKey, this.id, this.getHid(SignHid))
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.signPrivateKey = userKey
+
+    return this
+}
+
+// 使用加密主私钥, 根据 id 及 hid 生成加密用户私钥
+func (this SM9) GenerateEncryptUserKey() SM9 {
+    if this.encryptMasterPrivateKey == nil {
+        err := errors.New("SM9: encryptMasterPrivateKey error.")
+        return this.AppendError(err)
+    }
+
+    userKey, err := sm9.GenerateEncryptUserKey(this.encryptMasterPrivateKey, this.id, this.getHid(EncryptHid))
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.encryptPrivateKey = userKey
+
+    return this
+}
+
+// ==========
+
+// 私钥, 可为 [签名主私钥 | 签名用户私钥 | 加密主私钥 | 加密用户私钥]
+func (this SM9) FromPrivateKey(key []byte) SM9 {
+    parsedKey, err := this.ParsePrivateKeyFromPEM(key)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    return this.withParsedPrivateKey(parsedKey)
+}
+
+// 私钥
+func FromPrivateKey(key []byte) SM9 {
+    return defaultSM9.FromPrivateKey(key)
+}
+
+// 私钥带密码
+func (this SM9) FromPrivateKeyWithPassword(key []byte, password string) SM9 {
+    parsedKey, err := this.ParsePrivateKeyFromPEMWithPassword(key, password)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    return this.withParsedPrivateKey(parsedKey)
+}
+
+// 私钥带密码
+func FromPrivateKeyWithPassword(key []byte, password string) SM9 {
+    return defaultSM9.FromPrivateKeyWithPassword(key, password)
+}
+
+// 公钥, 可为 [签名主公钥 | 加密主公钥]
+func (this SM9) FromPublicKey(key []byte) SM9 {
+    parsedKey, err := this.ParsePublicKeyFromPEM(key)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    return this.withParsedPublicKey(parsedKey)
+}
+
+// 公钥
+func FromPublicKey(key []byte) SM9 {
+    return defaultSM9.FromPublicKey(key)
+}
+
+// ==========
+
+// DER 私钥
+func (this SM9) FromPrivateKeyDer(der []byte) SM9 {
+    key := tool.EncodeDerToPem(der, "PRIVATE KEY")
+
+    return this.FromPrivateKey(key)
+}
+
+// DER 公钥
+func (this SM9) FromPublicKeyDer(der []byte) SM9 {
+    key := tool.EncodeDerToPem(der, "PUBLIC KEY")
+
+    return this.FromPublicKey(key)
+}
+
+// ==========
+
+// 字节
+func (this SM9) FromBytes(data []byte) SM9 {
+    this.data = data
+
+    return this
+}
+
+// 字节
+func FromBytes(data []byte) SM9 {
+    return defaultSM9.FromBytes(data)
+}
+
+// 字符
+func (this SM9) FromString(data string) SM9 {
+    this.data = []byte(data)
+
+    return this
+}
+
+// 字符
+func FromString(data string) SM9 {
+    return defaultSM9.FromString(data)
+}
+
+// Base64
+func (this SM9) FromBase64String(data string) SM9 {
+    newData, err := tool.Base64Decode(data)
+
+    this.data = newData
+
+    return this.AppendError(err)
+}
+
+// Base64
+func FromBase64String(data string) SM9 {
+    return defaultSM9.FromBase64String(data)
+}
+
+// Hex
+func (this SM9) FromHexString(data string) SM9 {
+    newData, err := tool.HexDecode(data)
+
+    this.data = newData
+
+    return this.AppendError(err)
+}
+
+// Hex
+func FromHexString(data string) SM9 {
+    return defaultSM9.FromHexString(data)
+}
+
+// ==========
+
+// 根据类型设置私钥
+func (this SM9) withParsedPrivateKey(key crypto.PrivateKey) SM9 {
+    switch k := key.(type) {
+        case *sm9.SignMasterPrivateKey:
+            this.signMasterPrivateKey = k
+        case *sm9.SignPrivateKey:
+            this.signPrivateKey = k
+        case *sm9.EncryptMasterPrivateKey:
+            this.encryptMasterPrivateKey = k
+        case *sm9.EncryptPrivateKey:
+            this.encryptPrivateKey = k
+        default:
+            return this.AppendError(ErrNotSM9PrivateKey)
+    }
+
+    return this
+}
+
+// 根据类型设置公钥
+func (this SM9) withParsedPublicKey(key crypto.PublicKey) SM9 {
+    switch k := key.(type) {
+        case *sm9.SignMasterPublicKey:
+            this.signMasterPublicKey = k
+        case *sm9.EncryptMasterPublicKey:
+            this.encryptMasterPublicKey = k
+        default:
+            return this.AppendError(ErrNotSM9PublicKey)
+    }
+
+    return this
+}
+
+// 获取 hid, 未设置时使用默认值
+func (this SM9) getHid(defaultHid byte) byte {
+    if this.hid == 0 {
+        return defaultHid
+    }
+
+    return this.hid
+}
@@ -0,0 +1,80 @@
+package sm9
+
+import (
+    "github.com/deatil/go-cryptobin/gm/sm9"
+)
+
+// 获取签名主私钥
+func (this SM9) GetSignMasterPrivateKey() *sm9.SignMasterPrivateKey {
+    return this.signMasterPrivateKey
+}
+
+// 获取签名主公钥
+func (this SM9) GetSignMasterPublicKey() *sm9.SignMasterPublicKey {
+    return this.signMasterPublicKey
+}
+
+// 获取签名用户私钥
+func (this SM9) GetSignPrivateKey() *sm9.SignPrivateKey {
+    return this.signPrivateKey
+}
+
+// 获取加密主私钥
+func (this SM9) GetEncryptMasterPrivateKey() *sm9.EncryptMasterPrivateKey {
+    return this.encryptMasterPrivateKey
+}
+
+// 获取加密主公钥
+func (this SM9) GetEncryptMasterPublicKey() *sm9.EncryptMasterPublicKey {
+    return this.encryptMasterPublicKey
+}
+
+// 获取加密用户私钥
+func (this SM9) GetEncryptPrivateKey() *sm9.EncryptPrivateKey {
+    return this.encryptPrivateKey
+}
+
+// 获取用户标识
+func (this SM9) GetID() []byte {
+    return this.id
+}
+
+// 获取 hid
+func (this SM9) GetHid() byte {
+    return this.hid
+}
+
+// 获取加密方式
+func (this SM9) GetEncrypt() IEncrypt {
+    return this.encrypt
+}
+
+// 获取加密 Hash 方式
+func (this SM9) GetHash() IHash {
+    return this.hash
+}
+
+// 获取 keyData
+func (this SM9) GetKeyData() []byte {
+    return this.keyData
+}
+
+// 获取 data
+func (this SM9) GetData() []byte {
+    return this.data
+}
+
+// 获取 parsedData
+func (this SM9) GetParedData() []byte {
+    return this.parsedData
+}
+
+// 获取验证后情况
+func (this SM9) GetVerify() bool {
+    return this.verify
+}
+
+// 获取错误
+func (this SM9) GetErrors() []error {
+    return this.Errors
+}
@@ -0,0 +1,96 @@
+package sm9
+
+import (
+    "errors"
+    "crypto/rand"
+
+    "github.com/deatil/go-cryptobin/gm/sm9"
+    "github.com/deatil/go-cryptobin/gm/sm9/sm9curve"
+)
+
+// 密钥交换, 交换数据使用字节格式
+// 发起方: NewKeyExchange -> Init -> 发送 rA -> ConfirmResponder -> 发送 sigA
+// 响应方: NewKeyExchange -> 接收 rA -> Respond -> 发送 rB, sigB -> ConfirmInitiator
+type KeyExchange struct {
+    ke  *sm9.KeyExchange
+    hid byte
+}
+
+// 生成密钥交换, 使用加密用户私钥及 id, peerID 为对方 id
+// 双方用户私钥需要使用相同的 hid 生成, 默认为 KeyExchangeHid
+func (this SM9) NewKeyExchange(peerID []byte, keyLen int, genSignature bool) (*KeyExchange, error) {
+    if this.encryptPrivateKey == nil {
+        return nil, errors.New("SM9: encryptPrivateKey error.")
+    }
+
+    ke := sm9.NewKeyExchange(this.encryptPrivateKey, this.id, peerID, keyLen, genSignature)
+
+    return &KeyExchange{
+        ke:  ke,
+        hid: this.getHid(KeyExchangeHid),
+    }, nil
+}
+
+// 发起方生成 rA
+func (this *KeyExchange) Init() ([]byte, error) {
+    rA, err := this.ke.InitKeyExchange(rand.Reader, this.hid)
+    if err != nil {
+        return nil, err
+    }
+
+    return rA.MarshalUncompressed(), nil
+}
+
+// 响应方根据 rA 生成 rB 及签名 sigB
+func (this *KeyExchange) Respond(rA []byte) (rB []byte, sigB []byte, err error) {
+    ra, err := unmarshalG1(rA)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    rb, sigB, err := this.ke.RepondKeyExchange(rand.Reader, this.hid, ra)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    return rb.MarshalUncompressed(), sigB, nil
+}
+
+// 发起方验证响应方数据, 返回共享密钥及签名 sigA
+func (this *KeyExchange) ConfirmResponder(rB, sigB []byte) (key []byte, sigA []byte, err error) {
+    rb, err := unmarshalG1(rB)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    return this.ke.ConfirmResponder(rb, sigB)
+}
+
+// 响应方验证发起方签名, 返回共享密钥
+func (this *KeyExchange) ConfirmInitiator(sigA []byte) ([]byte, error) {
+    if len(sigA) == 0 {
+        sigA = nil
+    }
+
+    return this.ke.ConfirmInitiator(sigA)
+}
+
+// 清除内部数据
+func (this *KeyExchange) Reset() {
+    this.ke.Reset()
+}
+
+func unmarshalG1(data []byte) (*sm9curve.G1, error) {
+    p := new(sm9curve.G1)
+
+    rest, err := p.UnmarshalUncompressed(data)
+    if err != nil {
+        return nil, err
+    }
+
+    if len(rest) > 0 {
+        return nil, errors.New("SM9: invalid key exchange data.")
+    }
+
+    return p, nil
+}
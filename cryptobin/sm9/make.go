@@ -0,0 +1,46 @@
+package sm9
+
+import (
+    "errors"
+    "encoding/pem"
+)
+
+// 使用主私钥或者用户私钥生成主公钥
+func (this SM9) MakePublicKey() SM9 {
+    if this.signMasterPrivateKey == nil &&
+        this.signPrivateKey == nil &&
+        this.encryptMasterPrivateKey == nil &&
+        this.encryptPrivateKey == nil {
+        err := errors.New("SM9: privateKey error.")
+        return this.AppendError(err)
+    }
+
+    // 签名主公钥
+    if this.signMasterPrivateKey != nil {
+        this.signMasterPublicKey = this.signMasterPrivateKey.PublicKey()
+    } else if this.signPrivateKey != nil {
+        this.signMasterPublicKey = this.signPrivateKey.PublicKey()
+    }
+
+    // 加密主公钥
+    if this.encryptMasterPrivateKey != nil {
+        this.encryptMasterPublicKey = this.encryptMasterPrivateKey.PublicKey()
+    } else if this.encryptPrivateKey != nil {
+        this.encryptMasterPublicKey = this.encryptPrivateKey.PublicKey()
+    }
+
+    return this
+}
+
+// 生成密钥 der 数据
+func (this SM9) MakeKeyDer() SM9 {
+    var block *pem.Block
+    if block, _ = pem.Decode(this.keyData); block == nil {
+        err := errors.New("SM9: keyData error.")
+        return this.AppendError(err)
+    }
+
+    this.keyData = block.Bytes
+
+    return this
+}
@@ -0,0 +1,13 @@
+package sm9
+
+type (
+    // 错误方法
+    SM9ErrorFunc = func([]error)
+)
+
+// 引出错误信息
+func (this SM9) OnError(fn SM9ErrorFunc) SM9 {
+    fn(this.Errors)
+
+    return this
+}
@@ -0,0 +1,87 @@
+package sm9
+
+import (
+    "errors"
+    "crypto"
+    "encoding/pem"
+
+    "github.com/deatil/go-cryptobin/gm/sm9"
+    cryptobin_pkcs8 "github.com/deatil/go-cryptobin/pkcs8"
+)
+
+var (
+    ErrKeyMustBePEMEncoded = errors.New("invalid key: Key must be a PEM encoded PKCS8 key")
+    ErrNotSM9PrivateKey    = errors.New("key is not a valid SM9 private key")
+    ErrNotSM9PublicKey     = errors.New("key is not a valid SM9 public key")
+)
+
+// 解析私钥
+func (this SM9) ParsePrivateKeyFromPEM(key []byte) (crypto.PrivateKey, error) {
+    // Parse PEM block
+    var block *pem.Block
+    if block, _ = pem.Decode(key); block == nil {
+        return nil, ErrKeyMustBePEMEncoded
+    }
+
+    return this.parsePrivateKey(block.Bytes)
+}
+
+// 解析私钥带密码
+func (this SM9) ParsePrivateKeyFromPEMWithPassword(key []byte, password string) (crypto.PrivateKey, error) {
+    var err error
+
+    // Parse PEM block
+    var block *pem.Block
+    if block, _ = pem.Decode(key); block == nil {
+        return nil, ErrKeyMustBePEMEncoded
+    }
+
+    var blockDecrypted []byte
+    if blockDecrypted, err = cryptobin_pkcs8.DecryptPEMBlock(block, []byte(password)); err != nil {
+        return nil, err
+    }
+
+    return this.parsePrivateKey(blockDecrypted)
+}
+
+// 解析公钥
+func (this SM9) ParsePublicKeyFromPEM(key []byte) (crypto.PublicKey, error) {
+    var err error
+
+    // Parse PEM block
+    var block *pem.Block
+    if block, _ = pem.Decode(key); block == nil {
+        return nil, ErrKeyMustBePEMEncoded
+    }
+
+    // Parse the key
+    var parsedKey any
+    if parsedKey, err = sm9.ParsePublicKey(block.Bytes); err != nil {
+        return nil, err
+    }
+
+    switch parsedKey.(type) {
+        case *sm9.SignMasterPublicKey,
+            *sm9.EncryptMasterPublicKey:
+            return parsedKey, nil
+    }
+
+    return nil, ErrNotSM9PublicKey
+}
+
+func (this SM9) parsePrivateKey(der []byte) (crypto.PrivateKey, error) {
+    parsedKey, err := sm9.ParsePrivateKey(der)
+    if err != nil {
+        return nil, err
+    }
+
+    switch parsedKey.(type) {
+        case *sm9.SignMasterPrivateKey,
+            *sm9.SignPrivateKey,
+            *sm9.EncryptMasterPrivateKey,
+            *sm9.EncryptPrivateKey:
+            return parsedKey, nil
+    }
+
+    return nil, ErrNotSM9PrivateKey
+}
@@ -0,0 +1,37 @@
+package sm9
+
+import (
+    "errors"
+    "crypto/rand"
+
+    "github.com/deatil/go-cryptobin/gm/sm9"
+)
+
+// 签名用户私钥签名
+func (this SM9) Sign() SM9 {
+    if this.signPrivateKey == nil {
+        err := errors.New("SM9: signPrivateKey error.")
+        return this.AppendError(err)
+    }
+
+    parsedData, err := sm9.SignASN1(rand.Reader, this.signPrivateKey, this.data)
+    if err != nil {
+        return this.AppendError(err)
+    }
+
+    this.parsedData = parsedData
+
+    return this
+}
+
+// 签名主公钥验证, 需要设置签名用户的 id 及 hid
+func (this SM9) Verify(data []byte) SM9 {
+    if this.signMasterPublicKey == nil {
+        err := errors.New("SM9: signMasterPublicKey error.")
+        return this.AppendError(err)
+    }
+
+    this.verify = sm9.VerifyASN1(this.signMasterPublicKey, this.id, this.getHid(SignHid), data, this.data)
+
+    return this
+}
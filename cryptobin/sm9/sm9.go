@@ -0,0 +1,94 @@
+package sm9
+
+import (
+    "github.com/deatil/go-cryptobin/gm/sm9"
+)
+
+const (
+    // 默认签名 hid
+    SignHid        = sm9.DefaultSignHid
+    // 默认加密 hid
+    EncryptHid     = sm9.DefaultEncryptHid
+    // 默认密钥交换 hid
+    KeyExchangeHid = byte(0x02)
+)
+
+type (
+    // 加密方式
+    IEncrypt = sm9.IEncrypt
+    // 加密 Hash 方式
+    IHash    = sm9.IHash
+)
+
+/**
+ * 国密 SM9 标识密码
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type SM9 struct {
+    // 签名主私钥
+    signMasterPrivateKey *sm9.SignMasterPrivateKey
+
+    // 签名主公钥
+    signMasterPublicKey *sm9.SignMasterPublicKey
+
+    // 签名用户私钥
+    signPrivateKey *sm9.SignPrivateKey
+
+    // 加密主私钥
+    encryptMasterPrivateKey *sm9.EncryptMasterPrivateKey
+
+    // 加密主公钥
+    encryptMasterPublicKey *sm9.EncryptMasterPublicKey
+
+    // 加密用户私钥
+    encryptPrivateKey *sm9.EncryptPrivateKey
+
+    // 用户标识
+    id []byte
+
+    // hid, 为 0 时使用对应操作的默认值
+    hid byte
+
+    // 加密方式
+    encrypt IEncrypt
+
+    // 加密 Hash 方式
+    hash IHash
+
+    // [私钥/公钥]数据
+    keyData []byte
+
+    // 传入数据
+    data []byte
+
+    // 解析后的数据
+    parsedData []byte
+
+    // 验证结果
+    verify bool
+
+    // 错误
+    Errors []error
+}
+
+// 构造函数
+func NewSM9() SM9 {
+    return SM9{
+        encrypt: sm9.DefaultEncrypt,
+        hash:    sm9.DefaultHash,
+        verify:  false,
+        Errors:  make([]error, 0),
+    }
+}
+
+// 构造函数
+func New() SM9 {
+    return NewSM9()
+}
+
+var (
+    // 默认
+    defaultSM9 = NewSM9()
+)
@@ -0,0 +1,247 @@
+package sm9
+
+import (
+    "testing"
+
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+func Test_SignMasterKey(t *testing.T) {
+    assertBool := cryptobin_test.AssertBoolT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotEmpty := cryptobin_test.AssertNotEmptyT(t)
+
+    gen := GenerateSignMasterKey()
+    assertError(gen.Error(), "Test_SignMasterKey-Generate")
+
+    priKey := gen.CreateSignMasterPrivateKey().ToKeyString()
+    priKeyEn := gen.CreateSignMasterPrivateKeyWithPassword("123", "AES256CBC", "SHA256").ToKeyString()
+    pubKey := gen.CreateSignMasterPublicKey().ToKeyString()
+
+    assertNotEmpty(priKey, "Test_SignMasterKey-priKey")
+    assertNotEmpty(priKeyEn, "Test_SignMasterKey-priKeyEn")
+    assertNotEmpty(pubKey, "Test_SignMasterKey-pubKey")
+
+    pri := FromPrivateKey([]byte(priKey))
+    assertError(pri.Error(), "Test_SignMasterKey-FromPrivateKey")
+    assertEqual(pri.CreateSignMasterPrivateKey().ToKeyString(), priKey, "Test_SignMasterKey-FromPrivateKey")
+
+    priEn := FromPrivateKeyWithPassword([]byte(priKeyEn), "123")
+    assertError(priEn.Error(), "Test_SignMasterKey-FromPrivateKeyWithPassword")
+    assertEqual(priEn.CreateSignMasterPrivateKey().ToKeyString(), priKey, "Test_SignMasterKey-FromPrivateKeyWithPassword")
+
+    pub := FromPublicKey([]byte(pubKey))
+    assertError(pub.Error(), "Test_SignMasterKey-FromPublicKey")
+    assertEqual(pub.CreateSignMasterPublicKey().ToKeyString(), pubKey, "Test_SignMasterKey-FromPublicKey")
+
+    pubKey2 := pri.MakePublicKey().CreateSignMasterPublicKey().ToKeyString()
+    assertEqual(pubKey2, pubKey, "Test_SignMasterKey-MakePublicKey")
+
+    check := pri.FromPublicKey([]byte(pubKey)).CheckKeyPair()
+    assertBool(check, "Test_SignMasterKey-CheckKeyPair")
+
+    assertBool(gen.CheckKeyPair(), "Test_SignMasterKey-CheckKeyPair-generated")
+    assertBool(!FromPrivateKey([]byte(priKey)).CheckKeyPair(), "Test_SignMasterKey-CheckKeyPair-no-public")
+
+    other := GenerateSignMasterKey().CreateSignMasterPublicKey().ToKeyString()
+    assertBool(!pri.FromPublicKey([]byte(other)).CheckKeyPair(), "Test_SignMasterKey-CheckKeyPair-bad")
+
+    der := gen.CreateSignMasterPrivateKey().MakeKeyDer().ToKeyBytes()
+    priDer := New().FromPrivateKeyDer(der)
+    assertError(priDer.Error(), "Test_SignMasterKey-FromPrivateKeyDer")
+    assertEqual(priDer.CreateSignMasterPrivateKey().ToKeyString(), priKey, "Test_SignMasterKey-FromPrivateKeyDer")
+}
+
+func Test_SignAndVerify(t *testing.T) {
+    assertBool := cryptobin_test.AssertBoolT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotEmpty := cryptobin_test.AssertNotEmptyT(t)
+
+    data := "test-pass"
+    id := "Alice"
+
+    master := GenerateSignMasterKey()
+
+    userKey := master.
+        SetID(id).
+        GenerateSignUserKey().
+        CreateSignPrivateKeyWithPassword("123").
+        ToKeyString()
+    assertNotEmpty(userKey, "Test_SignAndVerify-userKey")
+
+    masterPubKey := master.CreateSignMasterPublicKey().ToKeyString()
+
+    // 签名
+    objSign := New().
+        FromString(data).
+        FromPrivateKeyWithPassword([]byte(userKey), "123").
+        Sign()
+    signed := objSign.ToBase64String()
+
+    assertError(objSign.Error(), "Test_SignAndVerify-Sign")
+    assertNotEmpty(signed, "Test_SignAndVerify-Sign")
+
+    // 验证
+    objVerify := New().
+        FromBase64String(signed).
+        FromPublicKey([]byte(masterPubKey)).
+        SetID(id).
+        Verify([]byte(data))
+
+    assertError(objVerify.Error(), "Test_SignAndVerify-Verify")
+    assertBool(objVerify.ToVerify(), "Test_SignAndVerify-Verify")
+
+    // 错误 id
+    objVerify2 := New().
+        FromBase64String(signed).
+        FromPublicKey([]byte(masterPubKey)).
+        SetID("Bob").
+        Verify([]byte(data))
+
+    assertBool(!objVerify2.ToVerify(), "Test_SignAndVerify-Verify-bad-id")
+
+    // 用户私钥导出主公钥
+    objVerify3 := New().
+        FromBase64String(signed).
+        FromPrivateKeyWithPassword([]byte(userKey), "123").
+        MakePublicKey().
+        SetID(id).
+        Verify([]byte(data))
+
+    assertError(objVerify3.Error(), "Test_SignAndVerify-Verify-MakePublicKey")
+    assertBool(objVerify3.ToVerify(), "Test_SignAndVerify-Verify-MakePublicKey")
+}
+
+func Test_EncryptAndDecrypt(t *testing.T) {
+    encrypts := []string{"SM4ECB", "SM4CBC", "SM4CFB", "SM4OFB", "XOR"}
+    hashes := []string{"HmacSM3", "HmacSHA256", "SM3", "SHA256"}
+
+    data := "test-pass"
+    id := "Bob"
+
+    master := GenerateEncryptMasterKey().SetID(id)
+
+    masterPubKey := master.CreateEncryptMasterPublicKey().ToKeyString()
+    userKey := master.GenerateEncryptUserKey().CreateEncryptPrivateKey().ToKeyString()
+
+    for _, enc := range encrypts {
+        for _, hash := range hashes {
+            t.Run(enc + "-" + hash, func(t *testing.T) {
+                assertEqual := cryptobin_test.AssertEqualT(t)
+                assertError := cryptobin_test.AssertErrorT(t)
+                assertNotEmpty := cryptobin_test.AssertNotEmptyT(t)
+
+                en := New().
+                    FromString(data).
+                    FromPublicKey([]byte(masterPubKey)).
+                    SetID(id).
+                    SetEncrypt(enc).
+                    SetHash(hash).
+                    Encrypt()
+                assertError(en.Error(), "Encrypt")
+                assertNotEmpty(en.ToBytes(), "Encrypt")
+
+                de := New().
+                    FromBytes(en.ToBytes()).
+                    FromPrivateKey([]byte(userKey)).
+                    SetID(id).
+                    SetEncrypt(enc).
+                    SetHash(hash).
+                    Decrypt()
+                assertError(de.Error(), "Decrypt")
+                assertEqual(de.ToString(), data, "Decrypt")
+
+                // ASN.1 密文记录加密方式
+                enASN1 := New().
+                    FromString(data).
+                    FromPublicKey([]byte(masterPubKey)).
+                    SetID(id).
+                    SetEncrypt(enc).
+                    SetHash(hash).
+                    EncryptASN1()
+                assertError(enASN1.Error(), "EncryptASN1")
+
+                deASN1 := New().
+                    FromBytes(enASN1.ToBytes()).
+                    FromPrivateKey([]byte(userKey)).
+                    SetID(id).
+                    SetHash(hash).
+                    DecryptASN1()
+                assertError(deASN1.Error(), "DecryptASN1")
+                assertEqual(deASN1.ToString(), data, "DecryptASN1")
+            })
+        }
+    }
+}
+
+func Test_DecryptWithBadID(t *testing.T) {
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    master := GenerateEncryptMasterKey().SetID("Bob")
+    user := master.GenerateEncryptUserKey()
+
+    en := master.FromString("test-pass").Encrypt()
+
+    de := user.
+        FromBytes(en.ToBytes()).
+        SetID("Alice").
+        Decrypt()
+    assertNotErrorNil(de.Error(), "Test_DecryptWithBadID")
+}
+
+func Test_KeyExchange(t *testing.T) {
+    for _, genSignature := range []bool{true, false} {
+        assertEqual := cryptobin_test.AssertEqualT(t)
+        assertError := cryptobin_test.AssertErrorT(t)
+
+        master := GenerateEncryptMasterKey().WithHid(KeyExchangeHid)
+
+        alice := master.SetID("Alice").GenerateEncryptUserKey()
+        bob := master.SetID("Bob").GenerateEncryptUserKey()
+
+        // 私钥导出导入
+        aliceKey := alice.CreateEncryptPrivateKey().ToKeyString()
+        alice = New().FromPrivateKey([]byte(aliceKey)).SetID("Alice")
+
+        initiator, err := alice.NewKeyExchange([]byte("Bob"), 16, genSignature)
+        assertError(err, "Test_KeyExchange-initiator")
+
+        responder, err := bob.NewKeyExchange([]byte("Alice"), 16, genSignature)
+        assertError(err, "Test_KeyExchange-responder")
+
+        rA, err := initiator.Init()
+        assertError(err, "Test_KeyExchange-Init")
+
+        rB, sigB, err := responder.Respond(rA)
+        assertError(err, "Test_KeyExchange-Respond")
+
+        key1, sigA, err := initiator.ConfirmResponder(rB, sigB)
+        assertError(err, "Test_KeyExchange-ConfirmResponder")
+
+        key2, err := responder.ConfirmInitiator(sigA)
+        assertError(err, "Test_KeyExchange-ConfirmInitiator")
+
+        assertEqual(len(key1), 16, "Test_KeyExchange-len")
+        assertEqual(key1, key2, "Test_KeyExchange")
+
+        initiator.Reset()
+        responder.Reset()
+    }
+}
+
+func Test_NoKeyError(t *testing.T) {
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    obj := New().FromString("test-pass")
+
+    assertNotErrorNil(obj.Sign().Error(), "Test_NoKeyError-Sign")
+    assertNotErrorNil(obj.Verify([]byte("test")).Error(), "Test_NoKeyError-Verify")
+    assertNotErrorNil(obj.Encrypt().Error(), "Test_NoKeyError-Encrypt")
+    assertNotErrorNil(obj.Decrypt().Error(), "Test_NoKeyError-Decrypt")
+    assertNotErrorNil(obj.GenerateSignUserKey().Error(), "Test_NoKeyError-GenerateSignUserKey")
+    assertNotErrorNil(obj.CreateEncryptPrivateKey().Error(), "Test_NoKeyError-CreateEncryptPrivateKey")
+
+    _, err := obj.NewKeyExchange([]byte("Bob"), 16, true)
+    assertNotErrorNil(err, "Test_NoKeyError-NewKeyExchange")
+}
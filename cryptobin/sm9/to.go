@@ -0,0 +1,53 @@
+package sm9
+
+import (
+    "github.com/deatil/go-cryptobin/tool"
+)
+
+// 私钥/公钥
+func (this SM9) ToKeyBytes() []byte {
+    return this.keyData
+}
+
+// 私钥/公钥
+func (this SM9) ToKeyString() string {
+    return string(this.keyData)
+}
+
+// ==========
+
+// 输出字节
+func (this SM9) ToBytes() []byte {
+    return this.parsedData
+}
+
+// 输出字符
+func (this SM9) ToString() string {
+    return string(this.parsedData)
+}
+
+// 输出Base64
+func (this SM9) ToBase64String() string {
+    return tool.Base64Encode(this.parsedData)
+}
+
+// 输出Hex
+func (this SM9) ToHexString() string {
+    return tool.HexEncode(this.parsedData)
+}
+
+// ==========
+
+// 验证结果
+func (this SM9) ToVerify() bool {
+    return this.verify
+}
+
+// 验证结果，返回 int 类型
+func (this SM9) ToVerifyInt() int {
+    if this.verify {
+        return 1
+    }
+
+    return 0
+}
@@ -0,0 +1,146 @@
+package sm9
+
+import (
+    "github.com/deatil/go-cryptobin/gm/sm9"
+)
+
+// 设置签名主私钥
+func (this SM9) WithSignMasterPrivateKey(data *sm9.SignMasterPrivateKey) SM9 {
+    this.signMasterPrivateKey = data
+
+    return this
+}
+
+// 设置签名主公钥
+func (this SM9) WithSignMasterPublicKey(data *sm9.SignMasterPublicKey) SM9 {
+    this.signMasterPublicKey = data
+
+    return this
+}
+
+// 设置签名用户私钥
+func (this SM9) WithSignPrivateKey(data *sm9.SignPrivateKey) SM9 {
+    this.signPrivateKey = data
+
+    return this
+}
+
+// 设置加密主私钥
+func (this SM9) WithEncryptMasterPrivateKey(data *sm9.EncryptMasterPrivateKey) SM9 {
+    this.encryptMasterPrivateKey = data
+
+    return this
+}
+
+// 设置加密主公钥
+func (this SM9) WithEncryptMasterPublicKey(data *sm9.EncryptMasterPublicKey) SM9 {
+    this.encryptMasterPublicKey = data
+
+    return this
+}
+
+// 设置加密用户私钥
+func (this SM9) WithEncryptPrivateKey(data *sm9.EncryptPrivateKey) SM9 {
+    this.encryptPrivateKey = data
+
+    return this
+}
+
+// 设置用户标识
+func (this SM9) WithID(data []byte) SM9 {
+    this.id = data
+
+    return this
+}
+
+// 设置用户标识
+func (this SM9) SetID(data string) SM9 {
+    this.id = []byte(data)
+
+    return this
+}
+
+// 设置 hid
+func (this SM9) WithHid(data byte) SM9 {
+    this.hid = data
+
+    return this
+}
+
+// 设置加密方式
+func (this SM9) WithEncrypt(data IEncrypt) SM9 {
+    this.encrypt = data
+
+    return this
+}
+
+// 设置加密方式
+// 可用参数 [SM4ECB | SM4CBC | SM4CFB | SM4OFB | XOR]
+func (this SM9) SetEncrypt(data string) SM9 {
+    switch data {
+        case "SM4ECB":
+            this.encrypt = sm9.SM4ECBEncrypt
+        case "SM4CBC":
+            this.encrypt = sm9.SM4CBCEncrypt
+        case "SM4CFB":
+            this.encrypt = sm9.SM4CFBEncrypt
+        case "SM4OFB":
+            this.encrypt = sm9.SM4OFBEncrypt
+        case "XOR":
+            this.encrypt = sm9.XorEncrypt
+    }
+
+    return this
+}
+
+// 设置加密 Hash 方式
+func (this SM9) WithHash(data IHash) SM9 {
+    this.hash = data
+
+    return this
+}
+
+// 设置加密 Hash 方式
+// 可用参数 [HmacSM3 | HmacSHA256 | SM3 | SHA256]
+func (this SM9) SetHash(data string) SM9 {
+    switch data {
+        case "HmacSM3":
+            this.hash = sm9.HmacSM3Hash
+        case "HmacSHA256":
+            this.hash = sm9.HmacSHA256Hash
+        case "SM3":
+            this.hash = sm9.SM3Hash
+        case "SHA256":
+            this.hash = sm9.SHA256Hash
+    }
+
+    return this
+}
+
+// 设置 data
+func (this SM9) WithData(data []byte) SM9 {
+    this.data = data
+
+    return this
+}
+
+// 设置 parsedData
+func (this SM9) WithParedData(data []byte) SM9 {
+    this.parsedData = data
+
+    return this
+}
+
+// 设置 verify
+func (this SM9) WithVerify(data bool) SM9 {
+    this.verify = data
+
+    return this
+}
+
+// 设置错误
+func (this SM9) WithErrors(errs []error) SM9 {
+    this.Errors = errs
+
+    return this
+}
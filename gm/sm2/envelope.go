@@ -0,0 +1,164 @@
+package sm2
+
+import (
+    "io"
+    "errors"
+    "math/big"
+    "encoding/asn1"
+    "crypto/subtle"
+    "crypto/x509/pkix"
+
+    "github.com/deatil/go-cryptobin/cipher/sm4"
+    cryptobin_cipher "github.com/deatil/go-cryptobin/cipher"
+    "github.com/deatil/go-cryptobin/gm/sm2/sm2curve"
+)
+
+var (
+    oidSM4    = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 104}
+    oidSM4ECB = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 104, 1}
+)
+
+// GM/T 0009-2012 数字信封
+// SM2EnvelopedKey ::= SEQUENCE {
+//     symAlgID               AlgorithmIdentifier,
+//     symEncryptedKey        SM2Cipher,
+//     sm2PublicKey           SM2PublicKey,
+//     sm2EncryptedPrivateKey BIT STRING }
+type sm2EnvelopedKey struct {
+    SymAlgID               pkix.AlgorithmIdentifier
+    SymEncryptedKey        asn1.RawValue
+    Sm2PublicKey           asn1.BitString
+    Sm2EncryptedPrivateKey asn1.BitString
+}
+
+// 使用签名公钥封装加密密钥对, 私钥使用随机 SM4 密钥 ECB 模式加密,
+// SM4 密钥使用 pub 加密
+// Marshal SM2EnvelopedKey, protect the key pair with pub and SM4
+func MarshalEnvelopedPrivateKey(random io.Reader, pub *PublicKey, key *PrivateKey) ([]byte, error) {
+    if pub == nil || key == nil {
+        return nil, errors.New("cryptobin/sm2: invalid enveloped key params")
+    }
+
+    byteLen := (key.Curve.Params().BitSize + 7) / 8
+    if key.D.BitLen() > byteLen*8 {
+        return nil, errors.New("cryptobin/sm2: invalid private key")
+    }
+
+    symKey := make([]byte, sm4.BlockSize)
+    if _, err := io.ReadFull(random, symKey); err != nil {
+        return nil, err
+    }
+
+    block, err := sm4.NewCipher(symKey)
+    if err != nil {
+        return nil, err
+    }
+
+    d := make([]byte, byteLen)
+    key.D.FillBytes(d)
+
+    encryptedPrivateKey := make([]byte, byteLen)
+    cryptobin_cipher.NewECBEncrypter(block).CryptBlocks(encryptedPrivateKey, d)
+
+    encryptedKey, err := EncryptASN1(random, pub, symKey, C1C3C2)
+    if err != nil {
+        return nil, err
+    }
+
+    publicKey := sm2curve.Marshal(key.Curve, key.X, key.Y)
+
+    return asn1.Marshal(sm2EnvelopedKey{
+        SymAlgID: pkix.AlgorithmIdentifier{
+            Algorithm: oidSM4ECB,
+        },
+        SymEncryptedKey: asn1.RawValue{
+            FullBytes: encryptedKey,
+        },
+        Sm2PublicKey: asn1.BitString{
+            Bytes:     publicKey,
+            BitLength: 8 * len(publicKey),
+        },
+        Sm2EncryptedPrivateKey: asn1.BitString{
+            Bytes:     encryptedPrivateKey,
+            BitLength: 8 * len(encryptedPrivateKey),
+        },
+    })
+}
+
+// 使用签名私钥解开数字信封, 返回加密密钥对
+// Parse SM2EnvelopedKey with priv
+func ParseEnvelopedPrivateKey(priv *PrivateKey, data []byte) (*PrivateKey, error) {
+    if priv == nil {
+        return nil, errors.New("cryptobin/sm2: invalid private key")
+    }
+
+    var envelopedKey sm2EnvelopedKey
+    rest, err := asn1.Unmarshal(data, &envelopedKey)
+    if err != nil {
+        return nil, err
+    } else if len(rest) > 0 {
+        return nil, errors.New("cryptobin/sm2: trailing data after enveloped key")
+    }
+
+    algo := envelopedKey.SymAlgID.Algorithm
+    if !algo.Equal(oidSM4ECB) && !algo.Equal(oidSM4) {
+        return nil, errors.New("cryptobin/sm2: unsupported enveloped key algorithm " + algo.String())
+    }
+
+    symKey, err := DecryptASN1(priv, envelopedKey.SymEncryptedKey.FullBytes, C1C3C2)
+    if err != nil {
+        return nil, err
+    }
+
+    block, err := sm4.NewCipher(symKey)
+    if err != nil {
+        return nil, err
+    }
+
+    curve := P256()
+
+    x, y := sm2curve.Unmarshal(curve, envelopedKey.Sm2PublicKey.RightAlign())
+    if x == nil || y == nil {
+        return nil, errors.New("cryptobin/sm2: invalid enveloped public key")
+    }
+
+    encryptedPrivateKey := envelopedKey.Sm2EncryptedPrivateKey.RightAlign()
+    if len(encryptedPrivateKey) == 0 || len(encryptedPrivateKey)%sm4.BlockSize != 0 {
+        return nil, errors.New("cryptobin/sm2: invalid enveloped private key")
+    }
+
+    d := make([]byte, len(encryptedPrivateKey))
+    cryptobin_cipher.NewECBDecrypter(block).CryptBlocks(d, encryptedPrivateKey)
+
+    // 部分实现使用 64 字节存储私钥, 高位补 0
+    byteLen := (curve.Params().BitSize + 7) / 8
+    if len(d) > byteLen {
+        for _, b := range d[:len(d)-byteLen] {
+            if b != 0 {
+                return nil, errors.New("cryptobin/sm2: invalid enveloped private key")
+            }
+        }
+
+        d = d[len(d)-byteLen:]
+    }
+
+    k := new(big.Int).SetBytes(d)
+    if k.Sign() == 0 || k.Cmp(curve.Params().N) >= 0 {
+        return nil, errors.New("cryptobin/sm2: invalid enveloped private key")
+    }
+
+    key, err := NewPrivateKey(d)
+    if err != nil {
+        return nil, err
+    }
+
+    // 校验私钥和公钥是否匹配
+    if subtle.ConstantTimeCompare(
+        sm2curve.Marshal(curve, key.X, key.Y),
+        sm2curve.Marshal(curve, x, y),
+    ) != 1 {
+        return nil, errors.New("cryptobin/sm2: enveloped private key not match public key")
+    }
+
+    return key, nil
+}
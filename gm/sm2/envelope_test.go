@@ -0,0 +1,91 @@
+package sm2_test
+
+import (
+    "testing"
+    "crypto/rand"
+    "encoding/asn1"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+)
+
+func Test_EnvelopedPrivateKey(t *testing.T) {
+    signKey, err := sm2.GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    encKey, err := sm2.GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    enveloped, err := sm2.MarshalEnvelopedPrivateKey(rand.Reader, &signKey.PublicKey, encKey)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    key, err := sm2.ParseEnvelopedPrivateKey(signKey, enveloped)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if !key.Equal(encKey) {
+        t.Error("ParseEnvelopedPrivateKey fail")
+    }
+
+    // 错误的签名私钥
+    otherKey, err := sm2.GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if _, err = sm2.ParseEnvelopedPrivateKey(otherKey, enveloped); err == nil {
+        t.Error("ParseEnvelopedPrivateKey should fail with other key")
+    }
+}
+
+func Test_EnvelopedPrivateKey_Check(t *testing.T) {
+    signKey, _ := sm2.GenerateKey(rand.Reader)
+    encKey, _ := sm2.GenerateKey(rand.Reader)
+    otherKey, _ := sm2.GenerateKey(rand.Reader)
+
+    enveloped, err := sm2.MarshalEnvelopedPrivateKey(rand.Reader, &signKey.PublicKey, encKey)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    var data struct {
+        SymAlgID               asn1.RawValue
+        SymEncryptedKey        asn1.RawValue
+        Sm2PublicKey           asn1.BitString
+        Sm2EncryptedPrivateKey asn1.BitString
+    }
+
+    if _, err = asn1.Unmarshal(enveloped, &data); err != nil {
+        t.Fatal(err)
+    }
+
+    if len(data.Sm2PublicKey.Bytes) != 65 || data.Sm2PublicKey.Bytes[0] != 0x04 {
+        t.Errorf("Sm2PublicKey got %x", data.Sm2PublicKey.Bytes)
+    }
+
+    if len(data.Sm2EncryptedPrivateKey.Bytes) != 32 {
+        t.Errorf("Sm2EncryptedPrivateKey len got %d", len(data.Sm2EncryptedPrivateKey.Bytes))
+    }
+
+    // 替换公钥后校验失败
+    pub := sm2.ToPublicKey(&otherKey.PublicKey)
+    data.Sm2PublicKey = asn1.BitString{
+        Bytes:     pub,
+        BitLength: 8 * len(pub),
+    }
+
+    changed, err := asn1.Marshal(data)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if _, err = sm2.ParseEnvelopedPrivateKey(signKey, changed); err == nil {
+        t.Error("ParseEnvelopedPrivateKey should fail with changed public key")
+    }
+}
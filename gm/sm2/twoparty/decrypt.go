@@ -0,0 +1,147 @@
+package twoparty
+
+import (
+    "errors"
+    "math/big"
+    "crypto/subtle"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+    "github.com/deatil/go-cryptobin/hash/sm3"
+    "github.com/deatil/go-cryptobin/kdf/smkdf"
+)
+
+var errDecrypt = errors.New("cryptobin/sm2/twoparty: failed to decrypt")
+
+// 解密发起方会话
+// decrypt session of the initiator
+type DecryptSession struct {
+    priv   *PrivateKey
+    c1x    *big.Int
+    c1y    *big.Int
+    c2, c3 []byte
+}
+
+// 发起解密, 密文为 sm2.Encrypt 生成的字节拼接格式
+// start decrypt with bytes marshal data
+func (priv *PrivateKey) DecryptStart(data []byte, mode sm2.Mode) (*DecryptSession, *DecryptRequest, error) {
+    curve := priv.Curve
+    N := curve.Params().N
+
+    byteLen := (curve.Params().BitSize + 7) / 8
+    if len(data) < 1+2*byteLen+sm3.Size {
+        return nil, nil, ErrDataTooShort
+    }
+
+    c1x, c1y, err := unmarshalPoint(curve, data[:1+2*byteLen])
+    if err != nil {
+        return nil, nil, err
+    }
+
+    data = data[1+2*byteLen:]
+
+    // C1C3C2 密文结构: x + y + hash + CipherText
+    // C1C2C3 密文结构: x + y + CipherText + hash
+    var c2, c3 []byte
+    switch mode {
+        case sm2.C1C2C3:
+            c2 = data[:len(data)-sm3.Size]
+            c3 = data[len(data)-sm3.Size:]
+        default:
+            c3 = data[:sm3.Size]
+            c2 = data[sm3.Size:]
+    }
+
+    // T1 = d1^-1 * C1
+    d1Inv := new(big.Int).ModInverse(priv.D, N)
+    t1x, t1y := curve.ScalarMult(c1x, c1y, d1Inv.Bytes())
+
+    session := &DecryptSession{
+        priv: priv,
+        c1x:  c1x,
+        c1y:  c1y,
+        c2:   append([]byte(nil), c2...),
+        c3:   append([]byte(nil), c3...),
+    }
+
+    req := &DecryptRequest{
+        T1X: t1x,
+        T1Y: t1y,
+    }
+
+    return session, req, nil
+}
+
+// 协助方解密, T2 = d2^-1 * T1
+func (priv *PrivateKey) DecryptAssist(req *DecryptRequest) (*DecryptResponse, error) {
+    curve := priv.Curve
+
+    if req == nil || !isOnCurve(curve, req.T1X, req.T1Y) {
+        return nil, ErrInvalidPoint
+    }
+
+    d2Inv := new(big.Int).ModInverse(priv.D, curve.Params().N)
+    t2x, t2y := curve.ScalarMult(req.T1X, req.T1Y, d2Inv.Bytes())
+
+    return &DecryptResponse{
+        T2X: t2x,
+        T2Y: t2y,
+    }, nil
+}
+
+// 完成解密, (x2, y2) = T2 - C1 = d * C1
+// finish decrypt
+func (this *DecryptSession) Finish(resp *DecryptResponse) ([]byte, error) {
+    curve := this.priv.Curve
+
+    if resp == nil || !isOnCurve(curve, resp.T2X, resp.T2Y) {
+        return nil, ErrInvalidPoint
+    }
+
+    x2, y2 := curve.Add(resp.T2X, resp.T2Y, this.c1x, negY(curve, this.c1y))
+    if !isOnCurve(curve, x2, y2) {
+        return nil, errDecrypt
+    }
+
+    byteLen := (curve.Params().BitSize + 7) / 8
+
+    x2Buf := make([]byte, byteLen)
+    y2Buf := make([]byte, byteLen)
+    x2.FillBytes(x2Buf)
+    y2.FillBytes(y2Buf)
+
+    z := make([]byte, 0, 2*byteLen)
+    z = append(z, x2Buf...)
+    z = append(z, y2Buf...)
+
+    length := len(this.c2)
+
+    // 生成密钥 / make key
+    c := smkdf.Key(sm3.New, z, length)
+    if length > 0 && allZero(c) {
+        return nil, errDecrypt
+    }
+
+    // 解密密文 / decrypt data
+    subtle.XORBytes(c, c, this.c2)
+
+    md := sm3.New()
+    md.Write(x2Buf)
+    md.Write(c)
+    md.Write(y2Buf)
+
+    if subtle.ConstantTimeCompare(md.Sum(nil), this.c3) != 1 {
+        return nil, errDecrypt
+    }
+
+    return c, nil
+}
+
+func allZero(b []byte) bool {
+    for _, v := range b {
+        if v != 0 {
+            return false
+        }
+    }
+
+    return true
+}
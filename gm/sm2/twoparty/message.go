@@ -0,0 +1,140 @@
+package twoparty
+
+import (
+    "errors"
+    "math/big"
+    "encoding/asn1"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+)
+
+var errTrailingData = errors.New("cryptobin/sm2/twoparty: trailing data after message")
+
+// 公钥分片, 编码为未压缩点
+// key share message
+type KeyShare struct {
+    X, Y *big.Int
+}
+
+func (this *KeyShare) Marshal() ([]byte, error) {
+    if !isOnCurve(sm2.P256(), this.X, this.Y) {
+        return nil, ErrInvalidPoint
+    }
+
+    return marshalPoint(sm2.P256(), this.X, this.Y), nil
+}
+
+func (this *KeyShare) Unmarshal(data []byte) (err error) {
+    this.X, this.Y, err = unmarshalPoint(sm2.P256(), data)
+    return
+}
+
+// 签名请求
+// SignRequest ::= SEQUENCE {
+//     q1 OCTET STRING, -- k1 * G
+//     e  OCTET STRING }
+type SignRequest struct {
+    Q1X, Q1Y *big.Int
+    E        []byte
+}
+
+type signRequestASN1 struct {
+    Q1 []byte
+    E  []byte
+}
+
+func (this *SignRequest) Marshal() ([]byte, error) {
+    if !isOnCurve(sm2.P256(), this.Q1X, this.Q1Y) {
+        return nil, ErrInvalidPoint
+    }
+
+    return asn1.Marshal(signRequestASN1{
+        Q1: marshalPoint(sm2.P256(), this.Q1X, this.Q1Y),
+        E:  this.E,
+    })
+}
+
+func (this *SignRequest) Unmarshal(data []byte) (err error) {
+    var req signRequestASN1
+    rest, err := asn1.Unmarshal(data, &req)
+    if err != nil {
+        return err
+    } else if len(rest) > 0 {
+        return errTrailingData
+    }
+
+    this.Q1X, this.Q1Y, err = unmarshalPoint(sm2.P256(), req.Q1)
+    if err != nil {
+        return err
+    }
+
+    this.E = req.E
+
+    return nil
+}
+
+// 签名响应
+// SignResponse ::= SEQUENCE {
+//     r  INTEGER,
+//     s2 INTEGER, -- d2 * k3
+//     s3 INTEGER  -- d2 * (r + k2) }
+type SignResponse struct {
+    R, S2, S3 *big.Int
+}
+
+func (this *SignResponse) Marshal() ([]byte, error) {
+    if this.R == nil || this.S2 == nil || this.S3 == nil {
+        return nil, ErrInvalidResponse
+    }
+
+    return asn1.Marshal(*this)
+}
+
+func (this *SignResponse) Unmarshal(data []byte) error {
+    rest, err := asn1.Unmarshal(data, this)
+    if err != nil {
+        return err
+    } else if len(rest) > 0 {
+        return errTrailingData
+    }
+
+    return nil
+}
+
+// 解密请求, 编码为未压缩点 d1^-1 * C1
+// decrypt request message
+type DecryptRequest struct {
+    T1X, T1Y *big.Int
+}
+
+func (this *DecryptRequest) Marshal() ([]byte, error) {
+    if !isOnCurve(sm2.P256(), this.T1X, this.T1Y) {
+        return nil, ErrInvalidPoint
+    }
+
+    return marshalPoint(sm2.P256(), this.T1X, this.T1Y), nil
+}
+
+func (this *DecryptRequest) Unmarshal(data []byte) (err error) {
+    this.T1X, this.T1Y, err = unmarshalPoint(sm2.P256(), data)
+    return
+}
+
+// 解密响应, 编码为未压缩点 d2^-1 * T1
+// decrypt response message
+type DecryptResponse struct {
+    T2X, T2Y *big.Int
+}
+
+func (this *DecryptResponse) Marshal() ([]byte, error) {
+    if !isOnCurve(sm2.P256(), this.T2X, this.T2Y) {
+        return nil, ErrInvalidPoint
+    }
+
+    return marshalPoint(sm2.P256(), this.T2X, this.T2Y), nil
+}
+
+func (this *DecryptResponse) Unmarshal(data []byte) (err error) {
+    this.T2X, this.T2Y, err = unmarshalPoint(sm2.P256(), data)
+    return
+}
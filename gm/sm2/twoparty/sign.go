@@ -0,0 +1,169 @@
+package twoparty
+
+import (
+    "io"
+    "errors"
+    "math/big"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+)
+
+var errRetrySign = errors.New("cryptobin/sm2/twoparty: invalid signature, please retry")
+
+// 签名发起方会话
+// sign session of the initiator
+type SignSession struct {
+    priv *PrivateKey
+    k1   *big.Int
+    e    []byte
+}
+
+// 发起签名, 使用 uid 计算 ZA 后签名消息
+// start sign with msg and uid, uid is empty will use default uid
+func (priv *PrivateKey) SignStart(random io.Reader, msg, uid []byte) (*SignSession, *SignRequest, error) {
+    if err := priv.checkPublicKey(); err != nil {
+        return nil, nil, err
+    }
+
+    hash, err := sm2.CalculateSM2Hash(&priv.PublicKey, msg, uid)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    return priv.SignHashStart(random, hash)
+}
+
+// 发起签名, 签名已经计算好的摘要 e
+// start sign with hash e
+func (priv *PrivateKey) SignHashStart(random io.Reader, hash []byte) (*SignSession, *SignRequest, error) {
+    if err := priv.checkPublicKey(); err != nil {
+        return nil, nil, err
+    }
+
+    curve := priv.Curve
+
+    k1, err := randFieldElement(curve, random)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    x, y := curve.ScalarBaseMult(k1.Bytes())
+
+    session := &SignSession{
+        priv: priv,
+        k1:   k1,
+        e:    append([]byte(nil), hash...),
+    }
+
+    req := &SignRequest{
+        Q1X: x,
+        Q1Y: y,
+        E:   session.e,
+    }
+
+    return session, req, nil
+}
+
+// 协助方签名
+// r = x1 + e, (x1, y1) = k3 * Q1 + k2 * G
+// s2 = d2 * k3, s3 = d2 * (r + k2)
+func (priv *PrivateKey) SignAssist(random io.Reader, req *SignRequest) (*SignResponse, error) {
+    curve := priv.Curve
+    N := curve.Params().N
+
+    if req == nil || !isOnCurve(curve, req.Q1X, req.Q1Y) {
+        return nil, ErrInvalidPoint
+    }
+
+    e := new(big.Int).SetBytes(req.E)
+
+    for {
+        k2, err := randFieldElement(curve, random)
+        if err != nil {
+            return nil, err
+        }
+
+        k3, err := randFieldElement(curve, random)
+        if err != nil {
+            return nil, err
+        }
+
+        x2, y2 := curve.ScalarBaseMult(k2.Bytes())
+        x3, y3 := curve.ScalarMult(req.Q1X, req.Q1Y, k3.Bytes())
+        x1, _ := curve.Add(x3, y3, x2, y2)
+
+        r := new(big.Int).Add(x1, e)
+        r.Mod(r, N)
+        if r.Sign() == 0 {
+            continue
+        }
+
+        s2 := new(big.Int).Mul(priv.D, k3)
+        s2.Mod(s2, N)
+
+        s3 := new(big.Int).Add(r, k2)
+        s3.Mul(s3, priv.D)
+        s3.Mod(s3, N)
+
+        return &SignResponse{
+            R:  r,
+            S2: s2,
+            S3: s3,
+        }, nil
+    }
+}
+
+// 完成签名, 返回可以使用 sm2.Verify 验证的签名
+// s = d1 * k1 * s2 + d1 * s3 - r
+func (this *SignSession) Finish(resp *SignResponse) (r, s *big.Int, err error) {
+    curve := this.priv.Curve
+    N := curve.Params().N
+
+    if resp == nil || resp.R == nil || resp.S2 == nil || resp.S3 == nil {
+        return nil, nil, ErrInvalidResponse
+    }
+
+    if resp.R.Sign() <= 0 || resp.R.Cmp(N) >= 0 ||
+        resp.S2.Sign() <= 0 || resp.S2.Cmp(N) >= 0 ||
+        resp.S3.Sign() < 0 || resp.S3.Cmp(N) >= 0 {
+        return nil, nil, ErrInvalidResponse
+    }
+
+    d1 := this.priv.D
+
+    s = new(big.Int).Mul(d1, this.k1)
+    s.Mul(s, resp.S2)
+
+    t := new(big.Int).Mul(d1, resp.S3)
+    s.Add(s, t)
+    s.Sub(s, resp.R)
+    s.Mod(s, N)
+
+    if s.Sign() == 0 {
+        return nil, nil, errRetrySign
+    }
+
+    if t := new(big.Int).Add(s, resp.R); t.Cmp(N) == 0 {
+        return nil, nil, errRetrySign
+    }
+
+    r = new(big.Int).Set(resp.R)
+
+    // 校验签名, 防止协助方返回错误数据
+    if !sm2.Verify(&this.priv.PublicKey, this.e, r, s) {
+        return nil, nil, ErrInvalidResponse
+    }
+
+    return r, s, nil
+}
+
+// 完成签名, 返回 asn.1 编码的签名
+// finish sign and return asn.1 marshal data
+func (this *SignSession) FinishASN1(resp *SignResponse) ([]byte, error) {
+    r, s, err := this.Finish(resp)
+    if err != nil {
+        return nil, err
+    }
+
+    return sm2.MarshalSignatureASN1(r, s)
+}
@@ -0,0 +1,197 @@
+package twoparty
+
+import (
+    "io"
+    "errors"
+    "math/big"
+    "crypto/rand"
+    "crypto/elliptic"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+    "github.com/deatil/go-cryptobin/gm/sm2/sm2curve"
+)
+
+var one = new(big.Int).SetInt64(1)
+
+var (
+    ErrInvalidPoint    = errors.New("cryptobin/sm2/twoparty: invalid point")
+    ErrInvalidShare    = errors.New("cryptobin/sm2/twoparty: invalid private key share")
+    ErrNoPublicKey     = errors.New("cryptobin/sm2/twoparty: joint public key not computed")
+    ErrInvalidResponse = errors.New("cryptobin/sm2/twoparty: invalid response")
+    ErrDataTooShort    = errors.New("cryptobin/sm2/twoparty: encrypt data is too short")
+)
+
+/**
+ * SM2 双方协同签名及解密
+ *
+ * 私钥 d 满足 (1 + d)^-1 = d1 * d2 mod n, d1 和 d2 分别由双方持有,
+ * 任何一方都无法单独得到完整私钥
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type PrivateKey struct {
+    // 联合公钥
+    sm2.PublicKey
+
+    // 私钥分片
+    D *big.Int
+}
+
+// 生成私钥分片, 联合公钥需要和对方交换 KeyShare 后计算
+// generate a private key share
+func GenerateKey(random io.Reader) (*PrivateKey, error) {
+    curve := sm2.P256()
+
+    d, err := randFieldElement(curve, random)
+    if err != nil {
+        return nil, err
+    }
+
+    priv := new(PrivateKey)
+    priv.PublicKey.Curve = curve
+    priv.D = d
+
+    return priv, nil
+}
+
+// 根据私钥分片及联合公钥初始化, 用于恢复已保存的分片
+// New a PrivateKey from share data and joint public key
+func NewPrivateKey(d []byte, pub *sm2.PublicKey) (*PrivateKey, error) {
+    curve := sm2.P256()
+
+    k := new(big.Int).SetBytes(d)
+    if k.Sign() == 0 || k.Cmp(curve.Params().N) >= 0 {
+        return nil, ErrInvalidShare
+    }
+
+    priv := new(PrivateKey)
+    priv.PublicKey.Curve = curve
+    priv.D = k
+
+    if pub != nil {
+        if pub.X == nil || pub.Y == nil || !curve.IsOnCurve(pub.X, pub.Y) {
+            return nil, ErrInvalidPoint
+        }
+
+        priv.PublicKey.X = pub.X
+        priv.PublicKey.Y = pub.Y
+    }
+
+    return priv, nil
+}
+
+// 输出私钥分片明文
+// output share data
+func ToPrivateKey(priv *PrivateKey) []byte {
+    return priv.D.Bytes()
+}
+
+// 获取联合公钥
+// get joint public key
+func (priv *PrivateKey) Public() *sm2.PublicKey {
+    return &priv.PublicKey
+}
+
+// 生成发送给对方的公钥分片 d^-1 * G
+// make key share for peer
+func (priv *PrivateKey) KeyShare() *KeyShare {
+    curve := priv.Curve
+
+    dInv := new(big.Int).ModInverse(priv.D, curve.Params().N)
+    x, y := curve.ScalarBaseMult(dInv.Bytes())
+
+    return &KeyShare{
+        X: x,
+        Y: y,
+    }
+}
+
+// 使用对方公钥分片计算联合公钥 P = d^-1 * P' - G
+// compute joint public key with peer key share
+func (priv *PrivateKey) Combine(peer *KeyShare) error {
+    curve := priv.Curve
+
+    if peer == nil || !isOnCurve(curve, peer.X, peer.Y) {
+        return ErrInvalidPoint
+    }
+
+    dInv := new(big.Int).ModInverse(priv.D, curve.Params().N)
+    x, y := curve.ScalarMult(peer.X, peer.Y, dInv.Bytes())
+
+    params := curve.Params()
+    x, y = curve.Add(x, y, params.Gx, negY(curve, params.Gy))
+
+    if !isOnCurve(curve, x, y) {
+        return ErrInvalidPoint
+    }
+
+    priv.PublicKey.X = x
+    priv.PublicKey.Y = y
+
+    return nil
+}
+
+func (priv *PrivateKey) checkPublicKey() error {
+    if priv.PublicKey.X == nil || priv.PublicKey.Y == nil {
+        return ErrNoPublicKey
+    }
+
+    return nil
+}
+
+// 取负值 -y mod p
+func negY(curve elliptic.Curve, y *big.Int) *big.Int {
+    p := curve.Params().P
+
+    ny := new(big.Int).Sub(p, y)
+    return ny.Mod(ny, p)
+}
+
+func isOnCurve(curve elliptic.Curve, x, y *big.Int) bool {
+    if x == nil || y == nil {
+        return false
+    }
+
+    if x.Sign() == 0 && y.Sign() == 0 {
+        return false
+    }
+
+    return curve.IsOnCurve(x, y)
+}
+
+func marshalPoint(curve elliptic.Curve, x, y *big.Int) []byte {
+    return sm2curve.Marshal(curve, x, y)
+}
+
+func unmarshalPoint(curve elliptic.Curve, data []byte) (x, y *big.Int, err error) {
+    x, y = sm2curve.Unmarshal(curve, data)
+    if x == nil || y == nil {
+        return nil, nil, ErrInvalidPoint
+    }
+
+    return x, y, nil
+}
+
+func randFieldElement(curve elliptic.Curve, random io.Reader) (k *big.Int, err error) {
+    if random == nil {
+        random = rand.Reader
+    }
+
+    params := curve.Params()
+
+    b := make([]byte, params.BitSize/8+8)
+
+    _, err = io.ReadFull(random, b)
+    if err != nil {
+        return
+    }
+
+    k = new(big.Int).SetBytes(b)
+    n := new(big.Int).Sub(params.N, one)
+
+    k.Mod(k, n)
+    k.Add(k, one)
+
+    return
+}
@@ -0,0 +1,259 @@
+package twoparty
+
+import (
+    "bytes"
+    "testing"
+    "math/big"
+    "crypto/rand"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+)
+
+func newTestKeys(t *testing.T) (*PrivateKey, *PrivateKey) {
+    p1, err := GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    p2, err := GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // 交换公钥分片, 经过编码传输
+    share1, err := p1.KeyShare().Marshal()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    share2, err := p2.KeyShare().Marshal()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    var ks1, ks2 KeyShare
+    if err = ks1.Unmarshal(share1); err != nil {
+        t.Fatal(err)
+    }
+    if err = ks2.Unmarshal(share2); err != nil {
+        t.Fatal(err)
+    }
+
+    if err = p1.Combine(&ks2); err != nil {
+        t.Fatal(err)
+    }
+    if err = p2.Combine(&ks1); err != nil {
+        t.Fatal(err)
+    }
+
+    return p1, p2
+}
+
+func Test_KeyGen(t *testing.T) {
+    p1, p2 := newTestKeys(t)
+
+    if !p1.Public().Equal(p2.Public()) {
+        t.Fatal("joint public key not equal")
+    }
+
+    // d = (d1 * d2)^-1 - 1
+    N := sm2.P256().Params().N
+    d := new(big.Int).Mul(p1.D, p2.D)
+    d.ModInverse(d, N)
+    d.Sub(d, one)
+    d.Mod(d, N)
+
+    priv, err := sm2.NewPrivateKey(d.Bytes())
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if !priv.PublicKey.Equal(p1.Public()) {
+        t.Error("joint public key not match private key")
+    }
+
+    p3, err := NewPrivateKey(ToPrivateKey(p1), p1.Public())
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if p3.D.Cmp(p1.D) != 0 || !p3.Public().Equal(p1.Public()) {
+        t.Error("NewPrivateKey fail")
+    }
+}
+
+func Test_Sign(t *testing.T) {
+    p1, p2 := newTestKeys(t)
+
+    msg := []byte("test-data")
+    uid := []byte("test-uid")
+
+    // 双方都可以作为发起方
+    for i, parties := range [][2]*PrivateKey{{p1, p2}, {p2, p1}} {
+        initiator, assistant := parties[0], parties[1]
+
+        session, req, err := initiator.SignStart(rand.Reader, msg, uid)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        reqBytes, err := req.Marshal()
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        var req2 SignRequest
+        if err = req2.Unmarshal(reqBytes); err != nil {
+            t.Fatal(err)
+        }
+
+        resp, err := assistant.SignAssist(rand.Reader, &req2)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        respBytes, err := resp.Marshal()
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        var resp2 SignResponse
+        if err = resp2.Unmarshal(respBytes); err != nil {
+            t.Fatal(err)
+        }
+
+        r, s, err := session.Finish(&resp2)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if !sm2.VerifyWithSM2(initiator.Public(), msg, uid, r, s) {
+            t.Errorf("[%d] VerifyWithSM2 fail", i)
+        }
+
+        sig, err := session.FinishASN1(&resp2)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if !initiator.Public().Verify(msg, sig, sm2.SignerOpts{Uid: uid}) {
+            t.Errorf("[%d] PublicKey.Verify fail", i)
+        }
+
+        if initiator.Public().Verify([]byte("bad-data"), sig, sm2.SignerOpts{Uid: uid}) {
+            t.Errorf("[%d] PublicKey.Verify should fail", i)
+        }
+    }
+}
+
+func Test_SignBadResponse(t *testing.T) {
+    p1, p2 := newTestKeys(t)
+
+    session, req, err := p1.SignStart(rand.Reader, []byte("test-data"), nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    resp, err := p2.SignAssist(rand.Reader, req)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    resp.S3 = new(big.Int).Add(resp.S3, one)
+
+    if _, _, err = session.Finish(resp); err == nil {
+        t.Error("should return error")
+    }
+}
+
+func Test_Decrypt(t *testing.T) {
+    p1, p2 := newTestKeys(t)
+
+    msg := []byte("test-data test-data test-data test-data")
+
+    for _, mode := range []sm2.Mode{sm2.C1C3C2, sm2.C1C2C3} {
+        ct, err := sm2.Encrypt(rand.Reader, p1.Public(), msg, mode)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        session, req, err := p1.DecryptStart(ct, mode)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        reqBytes, err := req.Marshal()
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        var req2 DecryptRequest
+        if err = req2.Unmarshal(reqBytes); err != nil {
+            t.Fatal(err)
+        }
+
+        resp, err := p2.DecryptAssist(&req2)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        respBytes, err := resp.Marshal()
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        var resp2 DecryptResponse
+        if err = resp2.Unmarshal(respBytes); err != nil {
+            t.Fatal(err)
+        }
+
+        plain, err := session.Finish(&resp2)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if !bytes.Equal(plain, msg) {
+            t.Errorf("mode %d: got %x, want %x", mode, plain, msg)
+        }
+
+        // 错误的协助方
+        p3, _ := newTestKeys(t)
+
+        resp3, err := p3.DecryptAssist(req)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if _, err = session.Finish(resp3); err == nil {
+            t.Error("should return error")
+        }
+    }
+}
+
+func Test_Errors(t *testing.T) {
+    p1, err := GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if _, _, err = p1.SignStart(rand.Reader, []byte("test"), nil); err != ErrNoPublicKey {
+        t.Errorf("got %v, want ErrNoPublicKey", err)
+    }
+
+    if err = p1.Combine(&KeyShare{X: big.NewInt(1), Y: big.NewInt(2)}); err != ErrInvalidPoint {
+        t.Errorf("got %v, want ErrInvalidPoint", err)
+    }
+
+    var ks KeyShare
+    if err = ks.Unmarshal([]byte{0x04, 0x01}); err == nil {
+        t.Error("should return error")
+    }
+
+    if _, err = NewPrivateKey(make([]byte, 32), nil); err != ErrInvalidShare {
+        t.Errorf("got %v, want ErrInvalidShare", err)
+    }
+
+    if _, _, err = p1.DecryptStart(make([]byte, 64), sm2.C1C3C2); err != ErrDataTooShort {
+        t.Errorf("got %v, want ErrDataTooShort", err)
+    }
+}
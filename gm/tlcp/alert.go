@@ -0,0 +1,83 @@
+package tlcp
+
+import "strconv"
+
+type alert uint8
+
+const (
+    alertLevelWarning = 1
+    alertLevelError   = 2
+)
+
+const (
+    alertCloseNotify            alert = 0
+    alertUnexpectedMessage      alert = 10
+    alertBadRecordMAC           alert = 20
+    alertRecordOverflow         alert = 22
+    alertDecompressionFailure   alert = 30
+    alertHandshakeFailure       alert = 40
+    alertBadCertificate         alert = 42
+    alertUnsupportedCertificate alert = 43
+    alertCertificateRevoked     alert = 44
+    alertCertificateExpired     alert = 45
+    alertCertificateUnknown     alert = 46
+    alertIllegalParameter       alert = 47
+    alertUnknownCA              alert = 48
+    alertAccessDenied           alert = 49
+    alertDecodeError            alert = 50
+    alertDecryptError           alert = 51
+    alertProtocolVersion        alert = 70
+    alertInsufficientSecurity   alert = 71
+    alertInternalError          alert = 80
+    alertUserCanceled           alert = 90
+    alertNoRenegotiation        alert = 100
+    alertUnsupportedSite2Site   alert = 200
+    alertNoArea                 alert = 201
+    alertUnsupportedAreaType    alert = 202
+    alertBadIBCParam            alert = 203
+    alertUnsupportedIBCParam    alert = 204
+    alertIdentityNeed           alert = 205
+)
+
+var alertText = map[alert]string{
+    alertCloseNotify:            "close notify",
+    alertUnexpectedMessage:      "unexpected message",
+    alertBadRecordMAC:           "bad record MAC",
+    alertRecordOverflow:         "record overflow",
+    alertDecompressionFailure:   "decompression failure",
+    alertHandshakeFailure:       "handshake failure",
+    alertBadCertificate:         "bad certificate",
+    alertUnsupportedCertificate: "unsupported certificate",
+    alertCertificateRevoked:     "revoked certificate",
+    alertCertificateExpired:     "expired certificate",
+    alertCertificateUnknown:     "unknown certificate",
+    alertIllegalParameter:       "illegal parameter",
+    alertUnknownCA:              "unknown certificate authority",
+    alertAccessDenied:           "access denied",
+    alertDecodeError:            "error decoding message",
+    alertDecryptError:           "error decrypting message",
+    alertProtocolVersion:        "protocol version not supported",
+    alertInsufficientSecurity:   "insufficient security level",
+    alertInternalError:          "internal error",
+    alertUserCanceled:           "user canceled",
+    alertNoRenegotiation:        "no renegotiation",
+    alertUnsupportedSite2Site:   "unsupported site2site",
+    alertNoArea:                 "no area",
+    alertUnsupportedAreaType:    "unsupported area type",
+    alertBadIBCParam:            "bad ibc param",
+    alertUnsupportedIBCParam:    "unsupported ibc param",
+    alertIdentityNeed:           "identity need",
+}
+
+func (e alert) String() string {
+    s, ok := alertText[e]
+    if ok {
+        return "tlcp: " + s
+    }
+
+    return "tlcp: alert(" + strconv.Itoa(int(e)) + ")"
+}
+
+func (e alert) Error() string {
+    return e.String()
+}
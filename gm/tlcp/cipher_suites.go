@@ -0,0 +1,152 @@
+package tlcp
+
+import (
+    "hash"
+    "crypto/hmac"
+    "crypto/cipher"
+
+    "github.com/deatil/go-cryptobin/hash/sm3"
+    "github.com/deatil/go-cryptobin/cipher/sm4"
+)
+
+// 密码套件
+const (
+    ECDHE_SM4_CBC_SM3 uint16 = 0xe011
+    ECDHE_SM4_GCM_SM3 uint16 = 0xe051
+    ECC_SM4_CBC_SM3   uint16 = 0xe013
+    ECC_SM4_GCM_SM3   uint16 = 0xe053
+)
+
+var defaultCipherSuites = []uint16{
+    ECC_SM4_GCM_SM3,
+    ECC_SM4_CBC_SM3,
+    ECDHE_SM4_GCM_SM3,
+    ECDHE_SM4_CBC_SM3,
+}
+
+const (
+    aeadNonceLength   = 12
+    aeadExplicitNonce = 8
+)
+
+type cipherSuite struct {
+    id     uint16
+    name   string
+    keyLen int
+    macLen int
+    ivLen  int
+    ecdhe  bool
+    aead   bool
+}
+
+var cipherSuites = []*cipherSuite{
+    {ECC_SM4_CBC_SM3, "ECC_SM4_CBC_SM3", 16, 32, 16, false, false},
+    {ECC_SM4_GCM_SM3, "ECC_SM4_GCM_SM3", 16, 0, 4, false, true},
+    {ECDHE_SM4_CBC_SM3, "ECDHE_SM4_CBC_SM3", 16, 32, 16, true, false},
+    {ECDHE_SM4_GCM_SM3, "ECDHE_SM4_GCM_SM3", 16, 0, 4, true, true},
+}
+
+// 密码套件名称
+func CipherSuiteName(id uint16) string {
+    if suite := cipherSuiteByID(id); suite != nil {
+        return suite.name
+    }
+
+    return "unknown"
+}
+
+func cipherSuiteByID(id uint16) *cipherSuite {
+    for _, suite := range cipherSuites {
+        if suite.id == id {
+            return suite
+        }
+    }
+
+    return nil
+}
+
+// 选择密码套件, 使用 have 中的顺序
+func selectCipherSuite(have, want []uint16) *cipherSuite {
+    for _, id := range have {
+        for _, w := range want {
+            if id == w {
+                if suite := cipherSuiteByID(id); suite != nil {
+                    return suite
+                }
+            }
+        }
+    }
+
+    return nil
+}
+
+func (s *cipherSuite) keyAgreement() keyAgreement {
+    if s.ecdhe {
+        return &ecdheKeyAgreement{}
+    }
+
+    return &eccKeyAgreement{}
+}
+
+// 生成记录层加密
+func (s *cipherSuite) cipher(key, iv []byte) (any, error) {
+    block, err := sm4.NewCipher(key)
+    if err != nil {
+        return nil, err
+    }
+
+    if s.aead {
+        aead, err := cipher.NewGCM(block)
+        if err != nil {
+            return nil, err
+        }
+
+        return &prefixNonceAEAD{
+            fixed: append([]byte(nil), iv...),
+            aead:  aead,
+        }, nil
+    }
+
+    return &cbcCipher{
+        block: block,
+    }, nil
+}
+
+func (s *cipherSuite) mac(key []byte) hash.Hash {
+    if s.aead {
+        return nil
+    }
+
+    return hmac.New(sm3.New, key)
+}
+
+// CBC 模式, 每条记录使用显式 IV
+type cbcCipher struct {
+    block cipher.Block
+}
+
+// GCM 模式, nonce 为 4 字节固定部分及 8 字节显式部分
+type prefixNonceAEAD struct {
+    fixed []byte
+    aead  cipher.AEAD
+}
+
+func (f *prefixNonceAEAD) Overhead() int {
+    return f.aead.Overhead()
+}
+
+func (f *prefixNonceAEAD) Seal(out, explicitNonce, plaintext, additionalData []byte) []byte {
+    nonce := make([]byte, 0, aeadNonceLength)
+    nonce = append(nonce, f.fixed...)
+    nonce = append(nonce, explicitNonce...)
+
+    return f.aead.Seal(out, nonce, plaintext, additionalData)
+}
+
+func (f *prefixNonceAEAD) Open(out, explicitNonce, ciphertext, additionalData []byte) ([]byte, error) {
+    nonce := make([]byte, 0, aeadNonceLength)
+    nonce = append(nonce, f.fixed...)
+    nonce = append(nonce, explicitNonce...)
+
+    return f.aead.Open(out, nonce, ciphertext, additionalData)
+}
@@ -0,0 +1,294 @@
+package tlcp
+
+import (
+    "io"
+    "sync"
+    "time"
+    "errors"
+    "crypto"
+    "crypto/rand"
+    "container/list"
+
+    "github.com/deatil/go-cryptobin/gm/x509"
+)
+
+/**
+ * TLCP 传输层密码协议 (GB/T 38636-2020)
+ *
+ * 使用签名证书及加密证书的双证书握手, 支持 ECC 及 ECDHE 密钥交换,
+ * 对称加密使用 SM4 (CBC 或者 GCM), 摘要使用 SM3
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+
+// 协议版本
+const VersionTLCP = 0x0101
+
+const (
+    recordHeaderLen = 5
+    maxPlaintext    = 16384
+    maxCiphertext   = 16384 + 2048
+    maxHandshake    = 65536
+)
+
+// 记录类型
+type recordType uint8
+
+const (
+    recordTypeChangeCipherSpec recordType = 20
+    recordTypeAlert            recordType = 21
+    recordTypeHandshake        recordType = 22
+    recordTypeApplicationData  recordType = 23
+)
+
+// 握手消息类型
+const (
+    typeClientHello        uint8 = 1
+    typeServerHello        uint8 = 2
+    typeCertificate        uint8 = 11
+    typeServerKeyExchange  uint8 = 12
+    typeCertificateRequest uint8 = 13
+    typeServerHelloDone    uint8 = 14
+    typeCertificateVerify  uint8 = 15
+    typeClientKeyExchange  uint8 = 16
+    typeFinished           uint8 = 20
+)
+
+// 证书类型
+const (
+    certTypeECDSASign uint8 = 64
+    certTypeIBCParams uint8 = 80
+)
+
+// 曲线
+const (
+    curveTypeNamedCurve uint8  = 3
+    curveSM2            uint16 = 41
+)
+
+// 客户端证书验证方式
+type ClientAuthType int
+
+const (
+    // 不请求客户端证书
+    NoClientCert ClientAuthType = iota
+    // 请求客户端证书, 不要求客户端发送
+    RequestClientCert
+    // 要求客户端发送证书, 不验证
+    RequireAnyClientCert
+    // 客户端发送证书时验证
+    VerifyClientCertIfGiven
+    // 要求客户端发送证书并验证
+    RequireAndVerifyClientCert
+)
+
+// 密钥对, Certificate 为 DER 编码的证书链
+type Certificate struct {
+    Certificate [][]byte
+
+    // 私钥, 签名证书需实现 crypto.Signer, 加密证书需为 *sm2.PrivateKey
+    PrivateKey crypto.PrivateKey
+
+    // 解析后的证书, 为空时自动解析
+    Leaf *x509.Certificate
+}
+
+// 握手完成后的连接状态
+type ConnectionState struct {
+    Version           uint16
+    HandshakeComplete bool
+    DidResume         bool
+    CipherSuite       uint16
+    ServerName        string
+
+    // 对方的证书, 第一个为签名证书, 第二个为加密证书
+    PeerCertificates []*x509.Certificate
+
+    // 验证后的签名证书链
+    VerifiedChains [][]*x509.Certificate
+}
+
+// 配置
+type Config struct {
+    // 随机数, 为空时使用 crypto/rand
+    Rand io.Reader
+
+    // 当前时间, 为空时使用 time.Now
+    Time func() time.Time
+
+    // 证书, 第一个为签名证书, 第二个为加密证书
+    // 服务端必须设置, 客户端在服务端请求证书或者使用 ECDHE 时设置
+    Certificates []Certificate
+
+    // 客户端验证服务端证书使用的根证书
+    RootCAs *x509.CertPool
+
+    // 服务端名称, 用于验证服务端证书
+    ServerName string
+
+    // 服务端验证客户端证书的方式
+    ClientAuth ClientAuthType
+
+    // 服务端验证客户端证书使用的根证书
+    ClientCAs *x509.CertPool
+
+    // 不验证对方证书
+    InsecureSkipVerify bool
+
+    // 支持的密码套件, 为空时使用全部密码套件
+    CipherSuites []uint16
+
+    // 会话缓存, 设置后可以恢复会话
+    SessionCache SessionCache
+
+    // 证书验证后的额外验证
+    VerifyPeerCertificate func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error
+}
+
+func (c *Config) rand() io.Reader {
+    if c.Rand == nil {
+        return rand.Reader
+    }
+
+    return c.Rand
+}
+
+func (c *Config) time() time.Time {
+    if c.Time == nil {
+        return time.Now()
+    }
+
+    return c.Time()
+}
+
+func (c *Config) cipherSuites() []uint16 {
+    if c.CipherSuites == nil {
+        return defaultCipherSuites
+    }
+
+    return c.CipherSuites
+}
+
+// 签名证书及加密证书
+func (c *Config) signAndEncCertificate() (*Certificate, *Certificate, error) {
+    if len(c.Certificates) < 2 {
+        return nil, nil, errors.New("tlcp: sign and enc certificates are required")
+    }
+
+    return &c.Certificates[0], &c.Certificates[1], nil
+}
+
+// 复制配置
+func (c *Config) Clone() *Config {
+    if c == nil {
+        return nil
+    }
+
+    return &Config{
+        Rand:                  c.Rand,
+        Time:                  c.Time,
+        Certificates:          c.Certificates,
+        RootCAs:               c.RootCAs,
+        ServerName:            c.ServerName,
+        ClientAuth:            c.ClientAuth,
+        ClientCAs:             c.ClientCAs,
+        InsecureSkipVerify:    c.InsecureSkipVerify,
+        CipherSuites:          c.CipherSuites,
+        SessionCache:          c.SessionCache,
+        VerifyPeerCertificate: c.VerifyPeerCertificate,
+    }
+}
+
+// 会话状态
+type SessionState struct {
+    sessionID        []byte
+    version          uint16
+    cipherSuite      uint16
+    masterSecret     []byte
+    peerCertificates [][]byte
+    createdAt        time.Time
+}
+
+// 会话缓存, 客户端使用服务端名称作为 key, 服务端使用会话 ID 作为 key
+type SessionCache interface {
+    Get(key string) (*SessionState, bool)
+    Put(key string, session *SessionState)
+}
+
+type lruSessionCache struct {
+    sync.Mutex
+
+    m        map[string]*list.Element
+    q        *list.List
+    capacity int
+}
+
+type lruSessionCacheEntry struct {
+    key   string
+    state *SessionState
+}
+
+// 使用 LRU 策略的会话缓存, capacity 小于 1 时默认为 64
+func NewLRUSessionCache(capacity int) SessionCache {
+    const defaultSessionCacheCapacity = 64
+
+    if capacity < 1 {
+        capacity = defaultSessionCacheCapacity
+    }
+
+    return &lruSessionCache{
+        m:        make(map[string]*list.Element),
+        q:        list.New(),
+        capacity: capacity,
+    }
+}
+
+func (c *lruSessionCache) Put(key string, session *SessionState) {
+    c.Lock()
+    defer c.Unlock()
+
+    if elem, ok := c.m[key]; ok {
+        if session == nil {
+            c.q.Remove(elem)
+            delete(c.m, key)
+        } else {
+            entry := elem.Value.(*lruSessionCacheEntry)
+            entry.state = session
+            c.q.MoveToFront(elem)
+        }
+
+        return
+    }
+
+    if session == nil {
+        return
+    }
+
+    if c.q.Len() < c.capacity {
+        entry := &lruSessionCacheEntry{key, session}
+        c.m[key] = c.q.PushFront(entry)
+        return
+    }
+
+    elem := c.q.Back()
+    entry := elem.Value.(*lruSessionCacheEntry)
+    delete(c.m, entry.key)
+
+    entry.key = key
+    entry.state = session
+    c.q.MoveToFront(elem)
+    c.m[key] = elem
+}
+
+func (c *lruSessionCache) Get(key string) (*SessionState, bool) {
+    c.Lock()
+    defer c.Unlock()
+
+    if elem, ok := c.m[key]; ok {
+        c.q.MoveToFront(elem)
+        return elem.Value.(*lruSessionCacheEntry).state, true
+    }
+
+    return nil, false
+}
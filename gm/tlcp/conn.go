@@ -0,0 +1,774 @@
+package tlcp
+
+import (
+    "io"
+    "fmt"
+    "net"
+    "hash"
+    "sync"
+    "time"
+    "bytes"
+    "errors"
+    "crypto/hmac"
+    "crypto/cipher"
+    "crypto/subtle"
+    "sync/atomic"
+
+    "github.com/deatil/go-cryptobin/gm/x509"
+)
+
+// TLCP 连接, 实现 net.Conn 接口
+type Conn struct {
+    conn     net.Conn
+    isClient bool
+    config   *Config
+
+    // 握手
+    handshakeMutex    sync.Mutex
+    handshakeErr      error
+    handshakeComplete atomic.Bool
+
+    vers             uint16
+    didResume        bool
+    cipherSuite      uint16
+    serverName       string
+    peerCertificates []*x509.Certificate
+    verifiedChains   [][]*x509.Certificate
+
+    in, out halfConn
+
+    // 原始数据
+    rawInput bytes.Buffer
+    // 应用数据
+    input bytes.Reader
+    // 握手数据
+    hand bytes.Buffer
+
+    // 握手时缓存写入的数据
+    buffering bool
+    sendBuf   []byte
+
+    closeNotifySent bool
+}
+
+// 单方向的加密状态
+type halfConn struct {
+    sync.Mutex
+
+    err    error
+    cipher any
+    mac    hash.Hash
+    seq    [8]byte
+
+    nextCipher any
+    nextMac    hash.Hash
+}
+
+func (hc *halfConn) setErrorLocked(err error) error {
+    hc.err = err
+    return err
+}
+
+// 设置下一个加密状态, 收到或者发送 ChangeCipherSpec 后生效
+func (hc *halfConn) prepareCipherSpec(cipher any, mac hash.Hash) {
+    hc.nextCipher = cipher
+    hc.nextMac = mac
+}
+
+func (hc *halfConn) changeCipherSpec() error {
+    if hc.nextCipher == nil {
+        return alertInternalError
+    }
+
+    hc.cipher = hc.nextCipher
+    hc.mac = hc.nextMac
+    hc.nextCipher = nil
+    hc.nextMac = nil
+
+    for i := range hc.seq {
+        hc.seq[i] = 0
+    }
+
+    return nil
+}
+
+func (hc *halfConn) incSeq() {
+    for i := 7; i >= 0; i-- {
+        hc.seq[i]++
+        if hc.seq[i] != 0 {
+            return
+        }
+    }
+
+    // 序号不能溢出
+    panic("tlcp: sequence number wraparound")
+}
+
+// 附加数据, 为序号及记录头
+func (hc *halfConn) additionalData(typ recordType, n int) []byte {
+    ad := make([]byte, 0, 13)
+    ad = append(ad, hc.seq[:]...)
+    ad = append(ad, byte(typ), byte(VersionTLCP >> 8), byte(VersionTLCP & 0xff))
+    ad = append(ad, byte(n >> 8), byte(n))
+
+    return ad
+}
+
+// 解密记录, 返回明文及记录类型
+func (hc *halfConn) decrypt(record []byte) ([]byte, recordType, error) {
+    typ := recordType(record[0])
+    payload := record[recordHeaderLen:]
+
+    if hc.cipher == nil {
+        return payload, typ, nil
+    }
+
+    var plaintext []byte
+
+    switch c := hc.cipher.(type) {
+        case *prefixNonceAEAD:
+            if len(payload) < aeadExplicitNonce + c.Overhead() {
+                return nil, 0, alertBadRecordMAC
+            }
+
+            nonce := payload[:aeadExplicitNonce]
+            ciphertext := payload[aeadExplicitNonce:]
+
+            n := len(ciphertext) - c.Overhead()
+            ad := hc.additionalData(typ, n)
+
+            var err error
+            plaintext, err = c.Open(ciphertext[:0], nonce, ciphertext, ad)
+            if err != nil {
+                return nil, 0, alertBadRecordMAC
+            }
+
+        case *cbcCipher:
+            blockSize := c.block.BlockSize()
+            macSize := hc.mac.Size()
+
+            if len(payload) % blockSize != 0 ||
+                len(payload) < blockSize + roundUp(macSize + 1, blockSize) {
+                return nil, 0, alertBadRecordMAC
+            }
+
+            iv := payload[:blockSize]
+            ciphertext := payload[blockSize:]
+
+            cipher.NewCBCDecrypter(c.block, iv).CryptBlocks(ciphertext, ciphertext)
+
+            n, good := extractPadding(ciphertext, macSize)
+
+            data := ciphertext[:n]
+            remoteMAC := ciphertext[n:n + macSize]
+
+            hc.mac.Reset()
+            hc.mac.Write(hc.additionalData(typ, n))
+            hc.mac.Write(data)
+            localMAC := hc.mac.Sum(nil)
+
+            if subtle.ConstantTimeCompare(localMAC, remoteMAC) & int(good) != 1 {
+                return nil, 0, alertBadRecordMAC
+            }
+
+            plaintext = data
+
+        default:
+            return nil, 0, alertInternalError
+    }
+
+    hc.incSeq()
+
+    return plaintext, typ, nil
+}
+
+// 加密记录, record 为记录头
+func (hc *halfConn) encrypt(record, payload []byte, rand io.Reader) ([]byte, error) {
+    if hc.cipher == nil {
+        return append(record, payload...), nil
+    }
+
+    typ := recordType(record[0])
+
+    switch c := hc.cipher.(type) {
+        case *prefixNonceAEAD:
+            nonce := hc.seq[:]
+            record = append(record, nonce...)
+            record = c.Seal(record, nonce, payload, hc.additionalData(typ, len(payload)))
+
+        case *cbcCipher:
+            blockSize := c.block.BlockSize()
+
+            hc.mac.Reset()
+            hc.mac.Write(hc.additionalData(typ, len(payload)))
+            hc.mac.Write(payload)
+            mac := hc.mac.Sum(nil)
+
+            iv := make([]byte, blockSize)
+            if _, err := io.ReadFull(rand, iv); err != nil {
+                return nil, err
+            }
+
+            paddingLen := blockSize - (len(payload) + len(mac)) % blockSize
+
+            data := make([]byte, 0, len(payload) + len(mac) + paddingLen)
+            data = append(data, payload...)
+            data = append(data, mac...)
+            for i := 0; i < paddingLen; i++ {
+                data = append(data, byte(paddingLen - 1))
+            }
+
+            cipher.NewCBCEncrypter(c.block, iv).CryptBlocks(data, data)
+
+            record = append(record, iv...)
+            record = append(record, data...)
+
+        default:
+            return nil, alertInternalError
+    }
+
+    // 更新记录长度
+    n := len(record) - recordHeaderLen
+    record[3] = byte(n >> 8)
+    record[4] = byte(n)
+
+    hc.incSeq()
+
+    return record, nil
+}
+
+// 检查并去除 CBC 填充, 返回数据长度及填充是否正确
+func extractPadding(payload []byte, macSize int) (toRemove int, good byte) {
+    paddingLen := int(payload[len(payload) - 1])
+    t := uint(len(payload) - macSize - 1) - uint(paddingLen)
+    good = byte(int32(^t) >> 31)
+
+    toCheck := 256
+    if toCheck > len(payload) {
+        toCheck = len(payload)
+    }
+
+    for i := 0; i < toCheck; i++ {
+        t := uint(paddingLen) - uint(i)
+        mask := byte(int32(^t) >> 31)
+        b := payload[len(payload) - 1 - i]
+        good &^= mask & byte(paddingLen) ^ mask & b
+    }
+
+    good &= good << 4
+    good &= good << 2
+    good &= good << 1
+    good = uint8(int8(good) >> 7)
+
+    // 填充错误时按无填充处理
+    paddingLen &= int(good)
+
+    toRemove = len(payload) - macSize - paddingLen - 1
+    return
+}
+
+func roundUp(a, b int) int {
+    return a + (b - a % b) % b
+}
+
+// 至少读取 N 个字节
+type atLeastReader struct {
+    R io.Reader
+    N int64
+}
+
+func (r *atLeastReader) Read(p []byte) (int, error) {
+    if r.N <= 0 {
+        return 0, io.EOF
+    }
+
+    n, err := r.R.Read(p)
+    r.N -= int64(n)
+
+    if r.N > 0 && err == io.EOF {
+        return n, io.ErrUnexpectedEOF
+    }
+
+    if r.N <= 0 && err == nil {
+        return n, io.EOF
+    }
+
+    return n, err
+}
+
+func (c *Conn) readFromUntil(r io.Reader, n int) error {
+    if c.rawInput.Len() >= n {
+        return nil
+    }
+
+    needs := n - c.rawInput.Len()
+    c.rawInput.Grow(needs + bytes.MinRead)
+
+    _, err := c.rawInput.ReadFrom(&atLeastReader{r, int64(needs)})
+    return err
+}
+
+func (c *Conn) readRecord() error {
+    return c.readRecordOrCCS(false)
+}
+
+func (c *Conn) readChangeCipherSpec() error {
+    return c.readRecordOrCCS(true)
+}
+
+// 读取一条记录
+func (c *Conn) readRecordOrCCS(expectChangeCipherSpec bool) error {
+    if c.in.err != nil {
+        return c.in.err
+    }
+
+    handshakeComplete := c.handshakeComplete.Load()
+
+    if c.input.Len() != 0 {
+        return c.in.setErrorLocked(errors.New("tlcp: internal error: attempted to read record with pending application data"))
+    }
+
+    c.input.Reset(nil)
+
+    if err := c.readFromUntil(c.conn, recordHeaderLen); err != nil {
+        if err == io.ErrUnexpectedEOF && c.rawInput.Len() == 0 {
+            err = io.EOF
+        }
+
+        if e, ok := err.(net.Error); !ok || !e.Timeout() {
+            c.in.setErrorLocked(err)
+        }
+
+        return err
+    }
+
+    hdr := c.rawInput.Bytes()[:recordHeaderLen]
+    typ := recordType(hdr[0])
+    vers := uint16(hdr[1]) << 8 | uint16(hdr[2])
+    n := int(hdr[3]) << 8 | int(hdr[4])
+
+    if vers != VersionTLCP {
+        c.sendAlert(alertProtocolVersion)
+        return c.in.setErrorLocked(fmt.Errorf("tlcp: received record with version %x", vers))
+    }
+
+    if n > maxCiphertext {
+        c.sendAlert(alertRecordOverflow)
+        return c.in.setErrorLocked(errors.New("tlcp: oversized record received"))
+    }
+
+    if err := c.readFromUntil(c.conn, recordHeaderLen + n); err != nil {
+        if e, ok := err.(net.Error); !ok || !e.Timeout() {
+            c.in.setErrorLocked(err)
+        }
+
+        return err
+    }
+
+    record := c.rawInput.Next(recordHeaderLen + n)
+    data, typ, err := c.in.decrypt(record)
+    if err != nil {
+        return c.in.setErrorLocked(c.sendAlert(err.(alert)))
+    }
+
+    if len(data) > maxPlaintext {
+        return c.in.setErrorLocked(c.sendAlert(alertRecordOverflow))
+    }
+
+    if c.in.cipher == nil && typ == recordTypeApplicationData {
+        return c.in.setErrorLocked(c.sendAlert(alertUnexpectedMessage))
+    }
+
+    // 期望 ChangeCipherSpec 时只接受 ChangeCipherSpec 及告警
+    if expectChangeCipherSpec && typ != recordTypeChangeCipherSpec && typ != recordTypeAlert {
+        return c.in.setErrorLocked(c.sendAlert(alertUnexpectedMessage))
+    }
+
+    switch typ {
+        case recordTypeAlert:
+            if len(data) != 2 {
+                return c.in.setErrorLocked(c.sendAlert(alertUnexpectedMessage))
+            }
+
+            if alert(data[1]) == alertCloseNotify {
+                return c.in.setErrorLocked(io.EOF)
+            }
+
+            switch data[0] {
+                case alertLevelWarning:
+                    return c.retryReadRecord(expectChangeCipherSpec)
+                case alertLevelError:
+                    return c.in.setErrorLocked(&net.OpError{Op: "remote error", Err: alert(data[1])})
+                default:
+                    return c.in.setErrorLocked(c.sendAlert(alertUnexpectedMessage))
+            }
+
+        case recordTypeChangeCipherSpec:
+            if len(data) != 1 || data[0] != 1 {
+                return c.in.setErrorLocked(c.sendAlert(alertDecodeError))
+            }
+
+            if !expectChangeCipherSpec || c.hand.Len() > 0 {
+                return c.in.setErrorLocked(c.sendAlert(alertUnexpectedMessage))
+            }
+
+            if err := c.in.changeCipherSpec(); err != nil {
+                return c.in.setErrorLocked(c.sendAlert(err.(alert)))
+            }
+
+        case recordTypeApplicationData:
+            if !handshakeComplete {
+                return c.in.setErrorLocked(c.sendAlert(alertUnexpectedMessage))
+            }
+
+            // 忽略空记录
+            if len(data) == 0 {
+                return c.retryReadRecord(expectChangeCipherSpec)
+            }
+
+            c.input.Reset(data)
+
+        case recordTypeHandshake:
+            if len(data) == 0 || handshakeComplete {
+                // 不支持重新协商
+                return c.in.setErrorLocked(c.sendAlert(alertNoRenegotiation))
+            }
+
+            c.hand.Write(data)
+
+        default:
+            return c.in.setErrorLocked(c.sendAlert(alertUnexpectedMessage))
+    }
+
+    return nil
+}
+
+func (c *Conn) retryReadRecord(expectChangeCipherSpec bool) error {
+    return c.readRecordOrCCS(expectChangeCipherSpec)
+}
+
+// 发送告警
+func (c *Conn) sendAlertLocked(err alert) error {
+    level := byte(alertLevelError)
+    if err == alertCloseNotify || err == alertNoRenegotiation {
+        level = alertLevelWarning
+    }
+
+    _, writeErr := c.writeRecordLocked(recordTypeAlert, []byte{level, byte(err)})
+    if err == alertCloseNotify {
+        return writeErr
+    }
+
+    return c.out.setErrorLocked(&net.OpError{Op: "local error", Err: err})
+}
+
+func (c *Conn) sendAlert(err alert) error {
+    c.out.Lock()
+    defer c.out.Unlock()
+
+    return c.sendAlertLocked(err)
+}
+
+// 写入记录, 超过最大长度时分多条记录写入
+func (c *Conn) writeRecordLocked(typ recordType, data []byte) (int, error) {
+    var n int
+    for len(data) > 0 || n == 0 {
+        m := len(data)
+        if m > maxPlaintext {
+            m = maxPlaintext
+        }
+
+        record := make([]byte, recordHeaderLen, recordHeaderLen + m + 2 * 16 + 32)
+        record[0] = byte(typ)
+        record[1] = byte(VersionTLCP >> 8)
+        record[2] = byte(VersionTLCP & 0xff)
+        record[3] = byte(m >> 8)
+        record[4] = byte(m)
+
+        var err error
+        record, err = c.out.encrypt(record, data[:m], c.config.rand())
+        if err != nil {
+            return n, err
+        }
+
+        if _, err := c.write(record); err != nil {
+            return n, err
+        }
+
+        n += m
+        data = data[m:]
+
+        if m == 0 {
+            break
+        }
+    }
+
+    if typ == recordTypeChangeCipherSpec {
+        if err := c.out.changeCipherSpec(); err != nil {
+            return n, c.sendAlertLocked(err.(alert))
+        }
+    }
+
+    return n, nil
+}
+
+func (c *Conn) write(data []byte) (int, error) {
+    if c.buffering {
+        c.sendBuf = append(c.sendBuf, data...)
+        return len(data), nil
+    }
+
+    n, err := c.conn.Write(data)
+    return n, err
+}
+
+func (c *Conn) flush() (int, error) {
+    if len(c.sendBuf) == 0 {
+        return 0, nil
+    }
+
+    n, err := c.conn.Write(c.sendBuf)
+    c.sendBuf = nil
+    c.buffering = false
+
+    return n, err
+}
+
+// 写入握手消息
+func (c *Conn) writeHandshakeRecord(msg handshakeMessage, transcript io.Writer) (int, error) {
+    c.out.Lock()
+    defer c.out.Unlock()
+
+    data, err := msg.marshal()
+    if err != nil {
+        return 0, err
+    }
+
+    if transcript != nil {
+        transcript.Write(data)
+    }
+
+    return c.writeRecordLocked(recordTypeHandshake, data)
+}
+
+func (c *Conn) writeChangeCipherRecord() error {
+    c.out.Lock()
+    defer c.out.Unlock()
+
+    _, err := c.writeRecordLocked(recordTypeChangeCipherSpec, []byte{1})
+    return err
+}
+
+// 读取握手消息
+func (c *Conn) readHandshake(transcript io.Writer) (any, error) {
+    for c.hand.Len() < 4 {
+        if err := c.readRecord(); err != nil {
+            return nil, err
+        }
+    }
+
+    data := c.hand.Bytes()
+    n := int(data[1]) << 16 | int(data[2]) << 8 | int(data[3])
+    if n > maxHandshake {
+        c.sendAlert(alertInternalError)
+        return nil, c.in.setErrorLocked(errors.New("tlcp: handshake message too large"))
+    }
+
+    for c.hand.Len() < 4 + n {
+        if err := c.readRecord(); err != nil {
+            return nil, err
+        }
+    }
+
+    data = append([]byte(nil), c.hand.Next(4 + n)...)
+
+    var m handshakeMessage
+    switch data[0] {
+        case typeClientHello:
+            m = new(clientHelloMsg)
+        case typeServerHello:
+            m = new(serverHelloMsg)
+        case typeCertificate:
+            m = new(certificateMsg)
+        case typeServerKeyExchange:
+            m = new(serverKeyExchangeMsg)
+        case typeCertificateRequest:
+            m = new(certificateRequestMsg)
+        case typeServerHelloDone:
+            m = new(serverHelloDoneMsg)
+        case typeClientKeyExchange:
+            m = new(clientKeyExchangeMsg)
+        case typeCertificateVerify:
+            m = new(certificateVerifyMsg)
+        case typeFinished:
+            m = new(finishedMsg)
+        default:
+            return nil, c.in.setErrorLocked(c.sendAlert(alertUnexpectedMessage))
+    }
+
+    if !m.unmarshal(data) {
+        return nil, c.in.setErrorLocked(c.sendAlert(alertDecodeError))
+    }
+
+    if transcript != nil {
+        transcript.Write(data)
+    }
+
+    return m, nil
+}
+
+// 执行握手, 读写数据时会自动执行
+func (c *Conn) Handshake() error {
+    c.handshakeMutex.Lock()
+    defer c.handshakeMutex.Unlock()
+
+    if c.handshakeComplete.Load() {
+        return nil
+    }
+
+    if c.handshakeErr != nil {
+        return c.handshakeErr
+    }
+
+    c.in.Lock()
+    defer c.in.Unlock()
+
+    if c.isClient {
+        c.handshakeErr = c.clientHandshake()
+    } else {
+        c.handshakeErr = c.serverHandshake()
+    }
+
+    if c.handshakeErr == nil {
+        c.handshakeComplete.Store(true)
+    } else {
+        // 握手失败时丢弃缓存的数据
+        c.buffering = false
+        c.sendBuf = nil
+    }
+
+    return c.handshakeErr
+}
+
+// 读取数据
+func (c *Conn) Read(b []byte) (int, error) {
+    if err := c.Handshake(); err != nil {
+        return 0, err
+    }
+
+    if len(b) == 0 {
+        return 0, nil
+    }
+
+    c.in.Lock()
+    defer c.in.Unlock()
+
+    for c.input.Len() == 0 {
+        if err := c.readRecord(); err != nil {
+            return 0, err
+        }
+    }
+
+    return c.input.Read(b)
+}
+
+// 写入数据
+func (c *Conn) Write(b []byte) (int, error) {
+    if err := c.Handshake(); err != nil {
+        return 0, err
+    }
+
+    c.out.Lock()
+    defer c.out.Unlock()
+
+    if c.out.err != nil {
+        return 0, c.out.err
+    }
+
+    if c.closeNotifySent {
+        return 0, errors.New("tlcp: use of closed connection")
+    }
+
+    return c.writeRecordLocked(recordTypeApplicationData, b)
+}
+
+// 关闭连接, 握手完成时先发送 close_notify 告警
+func (c *Conn) Close() error {
+    var alertErr error
+    if c.handshakeComplete.Load() {
+        alertErr = c.closeNotify()
+    }
+
+    if err := c.conn.Close(); err != nil {
+        return err
+    }
+
+    return alertErr
+}
+
+func (c *Conn) closeNotify() error {
+    c.out.Lock()
+    defer c.out.Unlock()
+
+    if c.closeNotifySent {
+        return nil
+    }
+
+    // 避免对方不读取时阻塞
+    c.SetWriteDeadline(time.Now().Add(5 * time.Second))
+    err := c.sendAlertLocked(alertCloseNotify)
+    c.SetWriteDeadline(time.Time{})
+
+    c.closeNotifySent = true
+
+    return err
+}
+
+// 连接状态
+func (c *Conn) ConnectionState() ConnectionState {
+    c.handshakeMutex.Lock()
+    defer c.handshakeMutex.Unlock()
+
+    var state ConnectionState
+    state.HandshakeComplete = c.handshakeComplete.Load()
+    if state.HandshakeComplete {
+        state.Version = c.vers
+        state.DidResume = c.didResume
+        state.CipherSuite = c.cipherSuite
+        state.ServerName = c.serverName
+        state.PeerCertificates = c.peerCertificates
+        state.VerifiedChains = c.verifiedChains
+    }
+
+    return state
+}
+
+// 底层连接
+func (c *Conn) NetConn() net.Conn {
+    return c.conn
+}
+
+func (c *Conn) LocalAddr() net.Addr {
+    return c.conn.LocalAddr()
+}
+
+func (c *Conn) RemoteAddr() net.Addr {
+    return c.conn.RemoteAddr()
+}
+
+func (c *Conn) SetDeadline(t time.Time) error {
+    return c.conn.SetDeadline(t)
+}
+
+func (c *Conn) SetReadDeadline(t time.Time) error {
+    return c.conn.SetReadDeadline(t)
+}
+
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+    return c.conn.SetWriteDeadline(t)
+}
+
+// 比较 Finished 消息
+func verifyDataEqual(a, b []byte) bool {
+    return hmac.Equal(a, b)
+}
@@ -0,0 +1,504 @@
+package tlcp
+
+import (
+    "io"
+    "fmt"
+    "errors"
+    "crypto"
+    "encoding/binary"
+
+    "github.com/deatil/go-cryptobin/gm/x509"
+)
+
+type clientHandshakeState struct {
+    c            *Conn
+    hello        *clientHelloMsg
+    serverHello  *serverHelloMsg
+    suite        *cipherSuite
+    finishedHash finishedHash
+    masterSecret []byte
+    session      *SessionState
+}
+
+func (c *Conn) clientHandshake() error {
+    if c.config == nil {
+        c.config = &Config{}
+    }
+
+    hello, err := c.makeClientHello()
+    if err != nil {
+        return err
+    }
+
+    cacheKey := c.clientSessionCacheKey()
+
+    var session *SessionState
+    if c.config.SessionCache != nil && cacheKey != "" {
+        if s, ok := c.config.SessionCache.Get(cacheKey); ok && s != nil &&
+            s.version == VersionTLCP &&
+            selectCipherSuite([]uint16{s.cipherSuite}, hello.cipherSuites) != nil {
+            hello.sessionId = s.sessionID
+            session = s
+        }
+    }
+
+    if _, err := c.writeHandshakeRecord(hello, nil); err != nil {
+        return err
+    }
+
+    msg, err := c.readHandshake(nil)
+    if err != nil {
+        return err
+    }
+
+    serverHello, ok := msg.(*serverHelloMsg)
+    if !ok {
+        c.sendAlert(alertUnexpectedMessage)
+        return unexpectedMessageError(serverHello, msg)
+    }
+
+    if serverHello.vers != VersionTLCP {
+        c.sendAlert(alertProtocolVersion)
+        return errors.New("tlcp: server selected unsupported protocol version")
+    }
+
+    if serverHello.compressionMethod != 0 {
+        c.sendAlert(alertUnexpectedMessage)
+        return errors.New("tlcp: server selected unsupported compression format")
+    }
+
+    suite := selectCipherSuite([]uint16{serverHello.cipherSuite}, hello.cipherSuites)
+    if suite == nil {
+        c.sendAlert(alertHandshakeFailure)
+        return errors.New("tlcp: server chose an unconfigured cipher suite")
+    }
+
+    c.vers = VersionTLCP
+    c.cipherSuite = suite.id
+
+    hs := &clientHandshakeState{
+        c:            c,
+        hello:        hello,
+        serverHello:  serverHello,
+        suite:        suite,
+        finishedHash: newFinishedHash(),
+    }
+
+    hs.finishedHash.Write(hello.raw)
+    hs.finishedHash.Write(serverHello.raw)
+
+    if session != nil && len(serverHello.sessionId) > 0 &&
+        string(serverHello.sessionId) == string(session.sessionID) {
+        if session.cipherSuite != serverHello.cipherSuite {
+            c.sendAlert(alertHandshakeFailure)
+            return errors.New("tlcp: server resumed a session with a different cipher suite")
+        }
+
+        hs.session = session
+        if err := hs.resumeHandshake(); err != nil {
+            return err
+        }
+
+        return nil
+    }
+
+    if err := hs.doFullHandshake(); err != nil {
+        return err
+    }
+
+    if c.config.SessionCache != nil && cacheKey != "" && len(serverHello.sessionId) > 0 {
+        c.config.SessionCache.Put(cacheKey, &SessionState{
+            sessionID:        serverHello.sessionId,
+            version:          c.vers,
+            cipherSuite:      suite.id,
+            masterSecret:     hs.masterSecret,
+            peerCertificates: rawCertificates(c.peerCertificates),
+            createdAt:        c.config.time(),
+        })
+    }
+
+    return nil
+}
+
+func (c *Conn) makeClientHello() (*clientHelloMsg, error) {
+    config := c.config
+
+    hello := &clientHelloMsg{
+        vers:               VersionTLCP,
+        random:             make([]byte, 32),
+        cipherSuites:       config.cipherSuites(),
+        compressionMethods: []uint8{0},
+    }
+
+    if err := makeRandom(config, hello.random); err != nil {
+        return nil, errors.New("tlcp: short read from Rand: " + err.Error())
+    }
+
+    return hello, nil
+}
+
+// 随机数, 前 4 字节为时间
+func makeRandom(config *Config, random []byte) error {
+    binary.BigEndian.PutUint32(random, uint32(config.time().Unix()))
+
+    _, err := io.ReadFull(config.rand(), random[4:])
+    return err
+}
+
+// 会话缓存使用的 key
+func (c *Conn) clientSessionCacheKey() string {
+    if len(c.config.ServerName) > 0 {
+        return c.config.ServerName
+    }
+
+    if c.conn != nil && c.conn.RemoteAddr() != nil {
+        return c.conn.RemoteAddr().String()
+    }
+
+    return ""
+}
+
+// 恢复会话
+func (hs *clientHandshakeState) resumeHandshake() error {
+    c := hs.c
+
+    certs, err := parseCertificates(hs.session.peerCertificates)
+    if err != nil {
+        c.sendAlert(alertBadCertificate)
+        return err
+    }
+
+    c.peerCertificates = certs
+    c.didResume = true
+    c.serverName = c.config.ServerName
+
+    hs.masterSecret = hs.session.masterSecret
+    if err := hs.establishKeys(); err != nil {
+        return err
+    }
+
+    if err := hs.readFinished(); err != nil {
+        return err
+    }
+
+    c.buffering = true
+    if err := hs.sendFinished(); err != nil {
+        return err
+    }
+
+    if _, err := c.flush(); err != nil {
+        return err
+    }
+
+    return nil
+}
+
+// 完整握手
+func (hs *clientHandshakeState) doFullHandshake() error {
+    c := hs.c
+    config := c.config
+
+    msg, err := c.readHandshake(&hs.finishedHash)
+    if err != nil {
+        return err
+    }
+
+    certMsg, ok := msg.(*certificateMsg)
+    if !ok || len(certMsg.certificates) < 2 {
+        c.sendAlert(alertUnexpectedMessage)
+        return unexpectedMessageError(certMsg, msg)
+    }
+
+    if err := c.verifyServerCertificate(certMsg.certificates); err != nil {
+        return err
+    }
+
+    msg, err = c.readHandshake(&hs.finishedHash)
+    if err != nil {
+        return err
+    }
+
+    skx, ok := msg.(*serverKeyExchangeMsg)
+    if !ok {
+        c.sendAlert(alertUnexpectedMessage)
+        return unexpectedMessageError(skx, msg)
+    }
+
+    signCert, encCert := c.peerCertificates[0], c.peerCertificates[1]
+
+    ka := hs.suite.keyAgreement()
+    err = ka.processServerKeyExchange(config, hs.hello, hs.serverHello, signCert, encCert, skx)
+    if err != nil {
+        c.sendAlert(alertUnexpectedMessage)
+        return err
+    }
+
+    msg, err = c.readHandshake(&hs.finishedHash)
+    if err != nil {
+        return err
+    }
+
+    var certRequested bool
+    if _, ok := msg.(*certificateRequestMsg); ok {
+        certRequested = true
+
+        msg, err = c.readHandshake(&hs.finishedHash)
+        if err != nil {
+            return err
+        }
+    }
+
+    shd, ok := msg.(*serverHelloDoneMsg)
+    if !ok {
+        c.sendAlert(alertUnexpectedMessage)
+        return unexpectedMessageError(shd, msg)
+    }
+
+    c.buffering = true
+
+    var ownSign, ownEnc *Certificate
+    if certRequested {
+        certMsg := new(certificateMsg)
+
+        if len(config.Certificates) >= 2 {
+            ownSign, ownEnc, _ = config.signAndEncCertificate()
+
+            certMsg.certificates = makeCertificateChain(ownSign, ownEnc)
+        }
+
+        if _, err := c.writeHandshakeRecord(certMsg, &hs.finishedHash); err != nil {
+            return err
+        }
+    }
+
+    preMasterSecret, ckx, err := ka.generateClientKeyExchange(config, hs.hello, encCert, ownEnc)
+    if err != nil {
+        c.sendAlert(alertInternalError)
+        return err
+    }
+
+    if _, err := c.writeHandshakeRecord(ckx, &hs.finishedHash); err != nil {
+        return err
+    }
+
+    if ownSign != nil {
+        signer, ok := ownSign.PrivateKey.(crypto.Signer)
+        if !ok {
+            c.sendAlert(alertInternalError)
+            return errors.New("tlcp: client certificate private key does not implement crypto.Signer")
+        }
+
+        sig, err := signer.Sign(config.rand(), hs.finishedHash.Sum(), nil)
+        if err != nil {
+            c.sendAlert(alertInternalError)
+            return err
+        }
+
+        certVerify := &certificateVerifyMsg{
+            signature: sig,
+        }
+
+        if _, err := c.writeHandshakeRecord(certVerify, &hs.finishedHash); err != nil {
+            return err
+        }
+    }
+
+    hs.masterSecret = masterFromPreMasterSecret(preMasterSecret, hs.hello.random, hs.serverHello.random)
+    if err := hs.establishKeys(); err != nil {
+        return err
+    }
+
+    if err := hs.sendFinished(); err != nil {
+        return err
+    }
+
+    if _, err := c.flush(); err != nil {
+        return err
+    }
+
+    if err := hs.readFinished(); err != nil {
+        return err
+    }
+
+    c.serverName = config.ServerName
+
+    return nil
+}
+
+func (hs *clientHandshakeState) establishKeys() error {
+    c := hs.c
+    suite := hs.suite
+
+    clientMAC, serverMAC, clientKey, serverKey, clientIV, serverIV :=
+        keysFromMasterSecret(hs.masterSecret, hs.hello.random, hs.serverHello.random, suite.macLen, suite.keyLen, suite.ivLen)
+
+    clientCipher, err := suite.cipher(clientKey, clientIV)
+    if err != nil {
+        return err
+    }
+
+    serverCipher, err := suite.cipher(serverKey, serverIV)
+    if err != nil {
+        return err
+    }
+
+    c.in.prepareCipherSpec(serverCipher, suite.mac(serverMAC))
+    c.out.prepareCipherSpec(clientCipher, suite.mac(clientMAC))
+
+    return nil
+}
+
+func (hs *clientHandshakeState) readFinished() error {
+    c := hs.c
+
+    if err := c.readChangeCipherSpec(); err != nil {
+        return err
+    }
+
+    msg, err := c.readHandshake(nil)
+    if err != nil {
+        return err
+    }
+
+    serverFinished, ok := msg.(*finishedMsg)
+    if !ok {
+        c.sendAlert(alertUnexpectedMessage)
+        return unexpectedMessageError(serverFinished, msg)
+    }
+
+    verify := hs.finishedHash.serverSum(hs.masterSecret)
+    if !verifyDataEqual(verify, serverFinished.verifyData) {
+        c.sendAlert(alertHandshakeFailure)
+        return errors.New("tlcp: server's Finished message was incorrect")
+    }
+
+    hs.finishedHash.Write(serverFinished.raw)
+
+    return nil
+}
+
+func (hs *clientHandshakeState) sendFinished() error {
+    c := hs.c
+
+    if err := c.writeChangeCipherRecord(); err != nil {
+        return err
+    }
+
+    finished := &finishedMsg{
+        verifyData: hs.finishedHash.clientSum(hs.masterSecret),
+    }
+
+    if _, err := c.writeHandshakeRecord(finished, &hs.finishedHash); err != nil {
+        return err
+    }
+
+    return nil
+}
+
+// 验证服务端证书, 第一个为签名证书, 第二个为加密证书, 其余为中间证书
+func (c *Conn) verifyServerCertificate(certificates [][]byte) error {
+    config := c.config
+
+    certs, err := parseCertificates(certificates)
+    if err != nil {
+        c.sendAlert(alertBadCertificate)
+        return err
+    }
+
+    for _, cert := range certs[:2] {
+        if _, ok := sm2PublicKey(cert.PublicKey); !ok {
+            c.sendAlert(alertUnsupportedCertificate)
+            return errUnsupportedKey
+        }
+    }
+
+    if !config.InsecureSkipVerify {
+        chains, err := verifyCertificates(config, certs, config.RootCAs, config.ServerName, x509.ExtKeyUsageServerAuth)
+        if err != nil {
+            c.sendAlert(alertBadCertificate)
+            return err
+        }
+
+        c.verifiedChains = chains
+    }
+
+    if config.VerifyPeerCertificate != nil {
+        if err := config.VerifyPeerCertificate(certificates, c.verifiedChains); err != nil {
+            c.sendAlert(alertBadCertificate)
+            return err
+        }
+    }
+
+    c.peerCertificates = certs
+
+    return nil
+}
+
+// 验证签名证书及加密证书, 返回签名证书的证书链
+func verifyCertificates(config *Config, certs []*x509.Certificate, roots *x509.CertPool, dnsName string, usage x509.ExtKeyUsage) ([][]*x509.Certificate, error) {
+    intermediates := x509.NewCertPool()
+    for _, cert := range certs[2:] {
+        intermediates.AddCert(cert)
+    }
+
+    opts := x509.VerifyOptions{
+        Roots:         roots,
+        CurrentTime:   config.time(),
+        DNSName:       dnsName,
+        Intermediates: intermediates,
+        KeyUsages:     []x509.ExtKeyUsage{usage},
+    }
+
+    chains, err := certs[0].Verify(opts)
+    if err != nil {
+        return nil, err
+    }
+
+    // 加密证书不验证名称
+    opts.DNSName = ""
+    if _, err := certs[1].Verify(opts); err != nil {
+        return nil, err
+    }
+
+    return chains, nil
+}
+
+// 证书链, 签名证书, 加密证书及签名证书的中间证书
+func makeCertificateChain(signCert, encCert *Certificate) [][]byte {
+    if len(signCert.Certificate) == 0 || len(encCert.Certificate) == 0 {
+        return nil
+    }
+
+    chain := make([][]byte, 0, len(signCert.Certificate) + 1)
+    chain = append(chain, signCert.Certificate[0], encCert.Certificate[0])
+    chain = append(chain, signCert.Certificate[1:]...)
+
+    return chain
+}
+
+func parseCertificates(certificates [][]byte) ([]*x509.Certificate, error) {
+    certs := make([]*x509.Certificate, len(certificates))
+    for i, asn1Data := range certificates {
+        cert, err := x509.ParseCertificate(asn1Data)
+        if err != nil {
+            return nil, errors.New("tlcp: failed to parse certificate: " + err.Error())
+        }
+
+        certs[i] = cert
+    }
+
+    return certs, nil
+}
+
+func rawCertificates(certs []*x509.Certificate) [][]byte {
+    raws := make([][]byte, len(certs))
+    for i, cert := range certs {
+        raws[i] = cert.Raw
+    }
+
+    return raws
+}
+
+func unexpectedMessageError(wanted, got any) error {
+    return fmt.Errorf("tlcp: received unexpected handshake message of type %T when waiting for %T", got, wanted)
+}
@@ -0,0 +1,419 @@
+package tlcp
+
+import (
+    "golang.org/x/crypto/cryptobyte"
+)
+
+// 握手消息
+type handshakeMessage interface {
+    marshal() ([]byte, error)
+    unmarshal([]byte) bool
+}
+
+// 添加握手消息头部
+func marshalHandshake(typ uint8, f func(b *cryptobyte.Builder)) ([]byte, error) {
+    var b cryptobyte.Builder
+    b.AddUint8(typ)
+    b.AddUint24LengthPrefixed(f)
+
+    return b.Bytes()
+}
+
+// 读取握手消息头部
+func readHandshakeBody(data []byte, typ uint8) (cryptobyte.String, bool) {
+    s := cryptobyte.String(data)
+
+    var t uint8
+    var body cryptobyte.String
+    if !s.ReadUint8(&t) || t != typ ||
+        !s.ReadUint24LengthPrefixed(&body) || !s.Empty() {
+        return nil, false
+    }
+
+    return body, true
+}
+
+type clientHelloMsg struct {
+    raw                []byte
+    vers               uint16
+    random             []byte
+    sessionId          []byte
+    cipherSuites       []uint16
+    compressionMethods []uint8
+}
+
+func (m *clientHelloMsg) marshal() ([]byte, error) {
+    if m.raw != nil {
+        return m.raw, nil
+    }
+
+    raw, err := marshalHandshake(typeClientHello, func(b *cryptobyte.Builder) {
+        b.AddUint16(m.vers)
+        b.AddBytes(m.random)
+        b.AddUint8LengthPrefixed(func(b *cryptobyte.Builder) {
+            b.AddBytes(m.sessionId)
+        })
+        b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+            for _, suite := range m.cipherSuites {
+                b.AddUint16(suite)
+            }
+        })
+        b.AddUint8LengthPrefixed(func(b *cryptobyte.Builder) {
+            b.AddBytes(m.compressionMethods)
+        })
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    m.raw = raw
+    return raw, nil
+}
+
+func (m *clientHelloMsg) unmarshal(data []byte) bool {
+    s, ok := readHandshakeBody(data, typeClientHello)
+    if !ok {
+        return false
+    }
+
+    *m = clientHelloMsg{raw: data}
+
+    var sessionId, suites, compression cryptobyte.String
+    if !s.ReadUint16(&m.vers) ||
+        !s.ReadBytes(&m.random, 32) ||
+        !s.ReadUint8LengthPrefixed(&sessionId) || len(sessionId) > 32 ||
+        !s.ReadUint16LengthPrefixed(&suites) ||
+        !s.ReadUint8LengthPrefixed(&compression) {
+        return false
+    }
+
+    m.sessionId = []byte(sessionId)
+    m.compressionMethods = []byte(compression)
+
+    for !suites.Empty() {
+        var suite uint16
+        if !suites.ReadUint16(&suite) {
+            return false
+        }
+
+        m.cipherSuites = append(m.cipherSuites, suite)
+    }
+
+    // 忽略扩展
+    return true
+}
+
+type serverHelloMsg struct {
+    raw               []byte
+    vers              uint16
+    random            []byte
+    sessionId         []byte
+    cipherSuite       uint16
+    compressionMethod uint8
+}
+
+func (m *serverHelloMsg) marshal() ([]byte, error) {
+    if m.raw != nil {
+        return m.raw, nil
+    }
+
+    raw, err := marshalHandshake(typeServerHello, func(b *cryptobyte.Builder) {
+        b.AddUint16(m.vers)
+        b.AddBytes(m.random)
+        b.AddUint8LengthPrefixed(func(b *cryptobyte.Builder) {
+            b.AddBytes(m.sessionId)
+        })
+        b.AddUint16(m.cipherSuite)
+        b.AddUint8(m.compressionMethod)
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    m.raw = raw
+    return raw, nil
+}
+
+func (m *serverHelloMsg) unmarshal(data []byte) bool {
+    s, ok := readHandshakeBody(data, typeServerHello)
+    if !ok {
+        return false
+    }
+
+    *m = serverHelloMsg{raw: data}
+
+    var sessionId cryptobyte.String
+    if !s.ReadUint16(&m.vers) ||
+        !s.ReadBytes(&m.random, 32) ||
+        !s.ReadUint8LengthPrefixed(&sessionId) || len(sessionId) > 32 ||
+        !s.ReadUint16(&m.cipherSuite) ||
+        !s.ReadUint8(&m.compressionMethod) {
+        return false
+    }
+
+    m.sessionId = []byte(sessionId)
+
+    return true
+}
+
+// 证书消息, 第一个为签名证书, 第二个为加密证书
+type certificateMsg struct {
+    raw          []byte
+    certificates [][]byte
+}
+
+func (m *certificateMsg) marshal() ([]byte, error) {
+    if m.raw != nil {
+        return m.raw, nil
+    }
+
+    raw, err := marshalHandshake(typeCertificate, func(b *cryptobyte.Builder) {
+        b.AddUint24LengthPrefixed(func(b *cryptobyte.Builder) {
+            for _, cert := range m.certificates {
+                b.AddUint24LengthPrefixed(func(b *cryptobyte.Builder) {
+                    b.AddBytes(cert)
+                })
+            }
+        })
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    m.raw = raw
+    return raw, nil
+}
+
+func (m *certificateMsg) unmarshal(data []byte) bool {
+    s, ok := readHandshakeBody(data, typeCertificate)
+    if !ok {
+        return false
+    }
+
+    *m = certificateMsg{raw: data}
+
+    var certs cryptobyte.String
+    if !s.ReadUint24LengthPrefixed(&certs) || !s.Empty() {
+        return false
+    }
+
+    for !certs.Empty() {
+        var cert cryptobyte.String
+        if !certs.ReadUint24LengthPrefixed(&cert) {
+            return false
+        }
+
+        m.certificates = append(m.certificates, []byte(cert))
+    }
+
+    return true
+}
+
+type serverKeyExchangeMsg struct {
+    raw []byte
+    key []byte
+}
+
+func (m *serverKeyExchangeMsg) marshal() ([]byte, error) {
+    if m.raw != nil {
+        return m.raw, nil
+    }
+
+    raw, err := marshalHandshake(typeServerKeyExchange, func(b *cryptobyte.Builder) {
+        b.AddBytes(m.key)
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    m.raw = raw
+    return raw, nil
+}
+
+func (m *serverKeyExchangeMsg) unmarshal(data []byte) bool {
+    s, ok := readHandshakeBody(data, typeServerKeyExchange)
+    if !ok {
+        return false
+    }
+
+    m.raw = data
+    m.key = []byte(s)
+
+    return true
+}
+
+type certificateRequestMsg struct {
+    raw                    []byte
+    certificateTypes       []byte
+    certificateAuthorities [][]byte
+}
+
+func (m *certificateRequestMsg) marshal() ([]byte, error) {
+    if m.raw != nil {
+        return m.raw, nil
+    }
+
+    raw, err := marshalHandshake(typeCertificateRequest, func(b *cryptobyte.Builder) {
+        b.AddUint8LengthPrefixed(func(b *cryptobyte.Builder) {
+            b.AddBytes(m.certificateTypes)
+        })
+        b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+            for _, ca := range m.certificateAuthorities {
+                b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+                    b.AddBytes(ca)
+                })
+            }
+        })
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    m.raw = raw
+    return raw, nil
+}
+
+func (m *certificateRequestMsg) unmarshal(data []byte) bool {
+    s, ok := readHandshakeBody(data, typeCertificateRequest)
+    if !ok {
+        return false
+    }
+
+    *m = certificateRequestMsg{raw: data}
+
+    var types, cas cryptobyte.String
+    if !s.ReadUint8LengthPrefixed(&types) ||
+        !s.ReadUint16LengthPrefixed(&cas) || !s.Empty() {
+        return false
+    }
+
+    m.certificateTypes = []byte(types)
+
+    for !cas.Empty() {
+        var ca cryptobyte.String
+        if !cas.ReadUint16LengthPrefixed(&ca) {
+            return false
+        }
+
+        m.certificateAuthorities = append(m.certificateAuthorities, []byte(ca))
+    }
+
+    return true
+}
+
+type serverHelloDoneMsg struct{}
+
+func (m *serverHelloDoneMsg) marshal() ([]byte, error) {
+    return []byte{typeServerHelloDone, 0, 0, 0}, nil
+}
+
+func (m *serverHelloDoneMsg) unmarshal(data []byte) bool {
+    return len(data) == 4 && data[0] == typeServerHelloDone
+}
+
+type clientKeyExchangeMsg struct {
+    raw        []byte
+    ciphertext []byte
+}
+
+func (m *clientKeyExchangeMsg) marshal() ([]byte, error) {
+    if m.raw != nil {
+        return m.raw, nil
+    }
+
+    raw, err := marshalHandshake(typeClientKeyExchange, func(b *cryptobyte.Builder) {
+        b.AddBytes(m.ciphertext)
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    m.raw = raw
+    return raw, nil
+}
+
+func (m *clientKeyExchangeMsg) unmarshal(data []byte) bool {
+    s, ok := readHandshakeBody(data, typeClientKeyExchange)
+    if !ok {
+        return false
+    }
+
+    m.raw = data
+    m.ciphertext = []byte(s)
+
+    return true
+}
+
+type certificateVerifyMsg struct {
+    raw       []byte
+    signature []byte
+}
+
+func (m *certificateVerifyMsg) marshal() ([]byte, error) {
+    if m.raw != nil {
+        return m.raw, nil
+    }
+
+    raw, err := marshalHandshake(typeCertificateVerify, func(b *cryptobyte.Builder) {
+        b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+            b.AddBytes(m.signature)
+        })
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    m.raw = raw
+    return raw, nil
+}
+
+func (m *certificateVerifyMsg) unmarshal(data []byte) bool {
+    s, ok := readHandshakeBody(data, typeCertificateVerify)
+    if !ok {
+        return false
+    }
+
+    m.raw = data
+
+    var sig cryptobyte.String
+    if !s.ReadUint16LengthPrefixed(&sig) || !s.Empty() {
+        return false
+    }
+
+    m.signature = []byte(sig)
+
+    return true
+}
+
+type finishedMsg struct {
+    raw        []byte
+    verifyData []byte
+}
+
+func (m *finishedMsg) marshal() ([]byte, error) {
+    if m.raw != nil {
+        return m.raw, nil
+    }
+
+    raw, err := marshalHandshake(typeFinished, func(b *cryptobyte.Builder) {
+        b.AddBytes(m.verifyData)
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    m.raw = raw
+    return raw, nil
+}
+
+func (m *finishedMsg) unmarshal(data []byte) bool {
+    s, ok := readHandshakeBody(data, typeFinished)
+    if !ok {
+        return false
+    }
+
+    m.raw = data
+    m.verifyData = []byte(s)
+
+    return len(m.verifyData) == finishedVerifyLength
+}
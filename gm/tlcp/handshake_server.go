@@ -0,0 +1,476 @@
+package tlcp
+
+import (
+    "io"
+    "time"
+    "errors"
+
+    "github.com/deatil/go-cryptobin/gm/x509"
+)
+
+// 会话有效期
+const sessionLifetime = 24 * time.Hour
+
+type serverHandshakeState struct {
+    c            *Conn
+    clientHello  *clientHelloMsg
+    hello        *serverHelloMsg
+    suite        *cipherSuite
+    finishedHash finishedHash
+    masterSecret []byte
+    session      *SessionState
+    signCert     *Certificate
+    encCert      *Certificate
+}
+
+func (c *Conn) serverHandshake() error {
+    if c.config == nil {
+        return errors.New("tlcp: server config is required")
+    }
+
+    msg, err := c.readHandshake(nil)
+    if err != nil {
+        return err
+    }
+
+    clientHello, ok := msg.(*clientHelloMsg)
+    if !ok {
+        c.sendAlert(alertUnexpectedMessage)
+        return unexpectedMessageError(clientHello, msg)
+    }
+
+    hs := &serverHandshakeState{
+        c:            c,
+        clientHello:  clientHello,
+        finishedHash: newFinishedHash(),
+    }
+
+    if err := hs.processClientHello(); err != nil {
+        return err
+    }
+
+    hs.finishedHash.Write(clientHello.raw)
+
+    if hs.checkForResumption() {
+        return hs.doResumeHandshake()
+    }
+
+    if err := hs.doFullHandshake(); err != nil {
+        return err
+    }
+
+    if c.config.SessionCache != nil && len(hs.hello.sessionId) > 0 {
+        c.config.SessionCache.Put(string(hs.hello.sessionId), &SessionState{
+            sessionID:        hs.hello.sessionId,
+            version:          c.vers,
+            cipherSuite:      hs.suite.id,
+            masterSecret:     hs.masterSecret,
+            peerCertificates: rawCertificates(c.peerCertificates),
+            createdAt:        c.config.time(),
+        })
+    }
+
+    return nil
+}
+
+func (hs *serverHandshakeState) processClientHello() error {
+    c := hs.c
+    config := c.config
+
+    if hs.clientHello.vers != VersionTLCP {
+        c.sendAlert(alertProtocolVersion)
+        return errors.New("tlcp: client offered unsupported protocol version")
+    }
+
+    var hasNoCompression bool
+    for _, method := range hs.clientHello.compressionMethods {
+        if method == 0 {
+            hasNoCompression = true
+            break
+        }
+    }
+
+    if !hasNoCompression {
+        c.sendAlert(alertHandshakeFailure)
+        return errors.New("tlcp: client does not support uncompressed connections")
+    }
+
+    hs.suite = selectCipherSuite(config.cipherSuites(), hs.clientHello.cipherSuites)
+    if hs.suite == nil {
+        c.sendAlert(alertHandshakeFailure)
+        return errors.New("tlcp: no cipher suite supported by both client and server")
+    }
+
+    var err error
+    hs.signCert, hs.encCert, err = config.signAndEncCertificate()
+    if err != nil {
+        c.sendAlert(alertInternalError)
+        return err
+    }
+
+    hs.hello = &serverHelloMsg{
+        vers:        VersionTLCP,
+        random:      make([]byte, 32),
+        cipherSuite: hs.suite.id,
+    }
+
+    if err := makeRandom(config, hs.hello.random); err != nil {
+        c.sendAlert(alertInternalError)
+        return err
+    }
+
+    c.vers = VersionTLCP
+    c.cipherSuite = hs.suite.id
+
+    return nil
+}
+
+// 检查是否可以恢复会话
+func (hs *serverHandshakeState) checkForResumption() bool {
+    c := hs.c
+    config := c.config
+
+    if config.SessionCache == nil || len(hs.clientHello.sessionId) == 0 {
+        return false
+    }
+
+    session, ok := config.SessionCache.Get(string(hs.clientHello.sessionId))
+    if !ok || session == nil || session.version != VersionTLCP {
+        return false
+    }
+
+    if config.time().Sub(session.createdAt) > sessionLifetime {
+        return false
+    }
+
+    // 密码套件需要双方都支持
+    suite := selectCipherSuite([]uint16{session.cipherSuite}, hs.clientHello.cipherSuites)
+    if suite == nil || selectCipherSuite([]uint16{session.cipherSuite}, config.cipherSuites()) == nil {
+        return false
+    }
+
+    // 要求客户端证书时, 会话中需要有客户端证书
+    needClientCerts := config.ClientAuth == RequireAnyClientCert ||
+        config.ClientAuth == RequireAndVerifyClientCert
+    if needClientCerts && len(session.peerCertificates) == 0 {
+        return false
+    }
+
+    hs.session = session
+    hs.suite = suite
+    hs.hello.cipherSuite = suite.id
+    c.cipherSuite = suite.id
+
+    return true
+}
+
+func (hs *serverHandshakeState) doResumeHandshake() error {
+    c := hs.c
+
+    hs.hello.sessionId = hs.clientHello.sessionId
+
+    certs, err := parseCertificates(hs.session.peerCertificates)
+    if err != nil {
+        c.sendAlert(alertInternalError)
+        return err
+    }
+
+    c.peerCertificates = certs
+    c.didResume = true
+
+    c.buffering = true
+    if _, err := c.writeHandshakeRecord(hs.hello, &hs.finishedHash); err != nil {
+        return err
+    }
+
+    hs.masterSecret = hs.session.masterSecret
+    if err := hs.establishKeys(); err != nil {
+        return err
+    }
+
+    if err := hs.sendFinished(); err != nil {
+        return err
+    }
+
+    if _, err := c.flush(); err != nil {
+        return err
+    }
+
+    return hs.readFinished()
+}
+
+func (hs *serverHandshakeState) doFullHandshake() error {
+    c := hs.c
+    config := c.config
+
+    if config.SessionCache != nil {
+        hs.hello.sessionId = make([]byte, 32)
+        if _, err := io.ReadFull(config.rand(), hs.hello.sessionId); err != nil {
+            c.sendAlert(alertInternalError)
+            return err
+        }
+    }
+
+    c.buffering = true
+    if _, err := c.writeHandshakeRecord(hs.hello, &hs.finishedHash); err != nil {
+        return err
+    }
+
+    certMsg := &certificateMsg{
+        certificates: makeCertificateChain(hs.signCert, hs.encCert),
+    }
+    if len(certMsg.certificates) < 2 {
+        c.sendAlert(alertInternalError)
+        return errors.New("tlcp: sign and enc certificates are required")
+    }
+
+    if _, err := c.writeHandshakeRecord(certMsg, &hs.finishedHash); err != nil {
+        return err
+    }
+
+    ka := hs.suite.keyAgreement()
+    skx, err := ka.generateServerKeyExchange(config, hs.signCert, hs.encCert, hs.clientHello, hs.hello)
+    if err != nil {
+        c.sendAlert(alertHandshakeFailure)
+        return err
+    }
+
+    if _, err := c.writeHandshakeRecord(skx, &hs.finishedHash); err != nil {
+        return err
+    }
+
+    // ECDHE 需要客户端的加密证书
+    certRequested := config.ClientAuth >= RequestClientCert || hs.suite.ecdhe
+    if certRequested {
+        certReq := &certificateRequestMsg{
+            certificateTypes: []byte{certTypeECDSASign},
+        }
+
+        if config.ClientCAs != nil {
+            certReq.certificateAuthorities = config.ClientCAs.Subjects()
+        }
+
+        if _, err := c.writeHandshakeRecord(certReq, &hs.finishedHash); err != nil {
+            return err
+        }
+    }
+
+    if _, err := c.writeHandshakeRecord(&serverHelloDoneMsg{}, &hs.finishedHash); err != nil {
+        return err
+    }
+
+    if _, err := c.flush(); err != nil {
+        return err
+    }
+
+    var peerEncCert *x509.Certificate
+    if certRequested {
+        msg, err := c.readHandshake(&hs.finishedHash)
+        if err != nil {
+            return err
+        }
+
+        certMsg, ok := msg.(*certificateMsg)
+        if !ok {
+            c.sendAlert(alertUnexpectedMessage)
+            return unexpectedMessageError(certMsg, msg)
+        }
+
+        if err := hs.processCertsFromClient(certMsg.certificates); err != nil {
+            return err
+        }
+
+        if len(c.peerCertificates) > 1 {
+            peerEncCert = c.peerCertificates[1]
+        }
+    }
+
+    msg, err := c.readHandshake(&hs.finishedHash)
+    if err != nil {
+        return err
+    }
+
+    ckx, ok := msg.(*clientKeyExchangeMsg)
+    if !ok {
+        c.sendAlert(alertUnexpectedMessage)
+        return unexpectedMessageError(ckx, msg)
+    }
+
+    preMasterSecret, err := ka.processClientKeyExchange(config, hs.encCert, ckx, peerEncCert)
+    if err != nil {
+        c.sendAlert(alertHandshakeFailure)
+        return err
+    }
+
+    // 客户端发送证书时需要验证签名
+    if len(c.peerCertificates) > 0 {
+        msg, err := c.readHandshake(nil)
+        if err != nil {
+            return err
+        }
+
+        certVerify, ok := msg.(*certificateVerifyMsg)
+        if !ok {
+            c.sendAlert(alertUnexpectedMessage)
+            return unexpectedMessageError(certVerify, msg)
+        }
+
+        pub, _ := sm2PublicKey(c.peerCertificates[0].PublicKey)
+        if !pub.Verify(hs.finishedHash.Sum(), certVerify.signature, nil) {
+            c.sendAlert(alertDecryptError)
+            return errors.New("tlcp: invalid signature by the client certificate")
+        }
+
+        hs.finishedHash.Write(certVerify.raw)
+    }
+
+    hs.masterSecret = masterFromPreMasterSecret(preMasterSecret, hs.clientHello.random, hs.hello.random)
+    if err := hs.establishKeys(); err != nil {
+        return err
+    }
+
+    if err := hs.readFinished(); err != nil {
+        return err
+    }
+
+    c.buffering = true
+    if err := hs.sendFinished(); err != nil {
+        return err
+    }
+
+    if _, err := c.flush(); err != nil {
+        return err
+    }
+
+    return nil
+}
+
+// 处理客户端证书, 第一个为签名证书, 第二个为加密证书
+func (hs *serverHandshakeState) processCertsFromClient(certificates [][]byte) error {
+    c := hs.c
+    config := c.config
+
+    if len(certificates) == 0 {
+        if hs.suite.ecdhe {
+            c.sendAlert(alertHandshakeFailure)
+            return errors.New("tlcp: client didn't provide an enc certificate for ECDHE")
+        }
+
+        if config.ClientAuth == RequireAnyClientCert ||
+            config.ClientAuth == RequireAndVerifyClientCert {
+            c.sendAlert(alertBadCertificate)
+            return errors.New("tlcp: client didn't provide a certificate")
+        }
+
+        return nil
+    }
+
+    if len(certificates) < 2 {
+        c.sendAlert(alertBadCertificate)
+        return errors.New("tlcp: client didn't provide both sign and enc certificates")
+    }
+
+    certs, err := parseCertificates(certificates)
+    if err != nil {
+        c.sendAlert(alertBadCertificate)
+        return err
+    }
+
+    for _, cert := range certs[:2] {
+        if _, ok := sm2PublicKey(cert.PublicKey); !ok {
+            c.sendAlert(alertUnsupportedCertificate)
+            return errUnsupportedKey
+        }
+    }
+
+    if config.ClientAuth >= VerifyClientCertIfGiven {
+        chains, err := verifyCertificates(config, certs, config.ClientCAs, "", x509.ExtKeyUsageClientAuth)
+        if err != nil {
+            c.sendAlert(alertBadCertificate)
+            return errors.New("tlcp: failed to verify client certificate: " + err.Error())
+        }
+
+        c.verifiedChains = chains
+    }
+
+    if config.VerifyPeerCertificate != nil {
+        if err := config.VerifyPeerCertificate(certificates, c.verifiedChains); err != nil {
+            c.sendAlert(alertBadCertificate)
+            return err
+        }
+    }
+
+    c.peerCertificates = certs
+
+    return nil
+}
+
+func (hs *serverHandshakeState) establishKeys() error {
+    c := hs.c
+    suite := hs.suite
+
+    clientMAC, serverMAC, clientKey, serverKey, clientIV, serverIV :=
+        keysFromMasterSecret(hs.masterSecret, hs.clientHello.random, hs.hello.random, suite.macLen, suite.keyLen, suite.ivLen)
+
+    clientCipher, err := suite.cipher(clientKey, clientIV)
+    if err != nil {
+        return err
+    }
+
+    serverCipher, err := suite.cipher(serverKey, serverIV)
+    if err != nil {
+        return err
+    }
+
+    c.in.prepareCipherSpec(clientCipher, suite.mac(clientMAC))
+    c.out.prepareCipherSpec(serverCipher, suite.mac(serverMAC))
+
+    return nil
+}
+
+func (hs *serverHandshakeState) readFinished() error {
+    c := hs.c
+
+    if err := c.readChangeCipherSpec(); err != nil {
+        return err
+    }
+
+    msg, err := c.readHandshake(nil)
+    if err != nil {
+        return err
+    }
+
+    clientFinished, ok := msg.(*finishedMsg)
+    if !ok {
+        c.sendAlert(alertUnexpectedMessage)
+        return unexpectedMessageError(clientFinished, msg)
+    }
+
+    verify := hs.finishedHash.clientSum(hs.masterSecret)
+    if !verifyDataEqual(verify, clientFinished.verifyData) {
+        c.sendAlert(alertHandshakeFailure)
+        return errors.New("tlcp: client's Finished message is incorrect")
+    }
+
+    hs.finishedHash.Write(clientFinished.raw)
+
+    return nil
+}
+
+func (hs *serverHandshakeState) sendFinished() error {
+    c := hs.c
+
+    if err := c.writeChangeCipherRecord(); err != nil {
+        return err
+    }
+
+    finished := &finishedMsg{
+        verifyData: hs.finishedHash.serverSum(hs.masterSecret),
+    }
+
+    if _, err := c.writeHandshakeRecord(finished, &hs.finishedHash); err != nil {
+        return err
+    }
+
+    return nil
+}
@@ -0,0 +1,381 @@
+package tlcp
+
+import (
+    "io"
+    "errors"
+    "crypto"
+    "crypto/ecdsa"
+
+    "golang.org/x/crypto/cryptobyte"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+    "github.com/deatil/go-cryptobin/gm/x509"
+)
+
+// 预主密钥长度
+const preMasterSecretLength = 48
+
+// SM2 密钥交换使用的默认用户 ID
+var defaultUID = []byte("1234567812345678")
+
+var (
+    errServerKeyExchange = errors.New("tlcp: invalid ServerKeyExchange message")
+    errClientKeyExchange = errors.New("tlcp: invalid ClientKeyExchange message")
+    errUnsupportedKey    = errors.New("tlcp: unsupported key type, require SM2 key")
+)
+
+// 密钥交换
+type keyAgreement interface {
+    // 服务端
+    generateServerKeyExchange(config *Config, signCert, encCert *Certificate, clientHello *clientHelloMsg, serverHello *serverHelloMsg) (*serverKeyExchangeMsg, error)
+    processClientKeyExchange(config *Config, encCert *Certificate, ckx *clientKeyExchangeMsg, peerEncCert *x509.Certificate) ([]byte, error)
+
+    // 客户端
+    processServerKeyExchange(config *Config, clientHello *clientHelloMsg, serverHello *serverHelloMsg, signCert, encCert *x509.Certificate, skx *serverKeyExchangeMsg) error
+    generateClientKeyExchange(config *Config, clientHello *clientHelloMsg, encCert *x509.Certificate, ownEncCert *Certificate) ([]byte, *clientKeyExchangeMsg, error)
+}
+
+// 使用签名证书私钥签名, 返回带长度的签名
+func signParams(config *Config, cert *Certificate, clientHello *clientHelloMsg, serverHello *serverHelloMsg, params []byte) ([]byte, error) {
+    signer, ok := cert.PrivateKey.(crypto.Signer)
+    if !ok {
+        return nil, errors.New("tlcp: certificate private key does not implement crypto.Signer")
+    }
+
+    msg := make([]byte, 0, 64 + len(params))
+    msg = append(msg, clientHello.random...)
+    msg = append(msg, serverHello.random...)
+    msg = append(msg, params...)
+
+    sig, err := signer.Sign(config.rand(), msg, nil)
+    if err != nil {
+        return nil, err
+    }
+
+    var b cryptobyte.Builder
+    b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+        b.AddBytes(sig)
+    })
+
+    return b.Bytes()
+}
+
+// 使用签名证书公钥验证签名
+func verifyParams(clientHello *clientHelloMsg, serverHello *serverHelloMsg, signCert *x509.Certificate, params, sig []byte) error {
+    pub, ok := sm2PublicKey(signCert.PublicKey)
+    if !ok {
+        return errUnsupportedKey
+    }
+
+    msg := make([]byte, 0, 64 + len(params))
+    msg = append(msg, clientHello.random...)
+    msg = append(msg, serverHello.random...)
+    msg = append(msg, params...)
+
+    if !pub.Verify(msg, sig, nil) {
+        return errors.New("tlcp: invalid signature in ServerKeyExchange message")
+    }
+
+    return nil
+}
+
+// 加密证书编码, 作为 ECC 密钥交换的签名数据
+func encCertParams(raw []byte) []byte {
+    params := make([]byte, 0, 3 + len(raw))
+    params = append(params, byte(len(raw) >> 16), byte(len(raw) >> 8), byte(len(raw)))
+    params = append(params, raw...)
+
+    return params
+}
+
+// 证书公钥, 解析后的 SM2 公钥为 ecdsa.PublicKey
+func sm2PublicKey(pub crypto.PublicKey) (*sm2.PublicKey, bool) {
+    switch k := pub.(type) {
+        case *sm2.PublicKey:
+            return k, true
+        case *ecdsa.PublicKey:
+            if k.Curve != sm2.P256() {
+                return nil, false
+            }
+
+            return &sm2.PublicKey{
+                Curve: k.Curve,
+                X:     k.X,
+                Y:     k.Y,
+            }, true
+    }
+
+    return nil, false
+}
+
+func sm2PrivateKey(cert *Certificate) (*sm2.PrivateKey, error) {
+    if cert == nil {
+        return nil, errors.New("tlcp: enc certificate is required")
+    }
+
+    priv, ok := cert.PrivateKey.(*sm2.PrivateKey)
+    if !ok {
+        return nil, errUnsupportedKey
+    }
+
+    return priv, nil
+}
+
+// ECC 密钥交换, 客户端使用服务端加密证书公钥加密预主密钥
+type eccKeyAgreement struct {
+    encPub *sm2.PublicKey
+}
+
+func (ka *eccKeyAgreement) generateServerKeyExchange(config *Config, signCert, encCert *Certificate, clientHello *clientHelloMsg, serverHello *serverHelloMsg) (*serverKeyExchangeMsg, error) {
+    if len(encCert.Certificate) == 0 {
+        return nil, errors.New("tlcp: enc certificate is required")
+    }
+
+    // 签名数据为加密证书, 只发送签名
+    key, err := signParams(config, signCert, clientHello, serverHello, encCertParams(encCert.Certificate[0]))
+    if err != nil {
+        return nil, err
+    }
+
+    return &serverKeyExchangeMsg{key: key}, nil
+}
+
+func (ka *eccKeyAgreement) processClientKeyExchange(config *Config, encCert *Certificate, ckx *clientKeyExchangeMsg, peerEncCert *x509.Certificate) ([]byte, error) {
+    priv, err := sm2PrivateKey(encCert)
+    if err != nil {
+        return nil, err
+    }
+
+    s := cryptobyte.String(ckx.ciphertext)
+
+    var ciphertext cryptobyte.String
+    if !s.ReadUint16LengthPrefixed(&ciphertext) || !s.Empty() {
+        return nil, errClientKeyExchange
+    }
+
+    preMasterSecret, err := priv.DecryptASN1(ciphertext, nil)
+    if err != nil {
+        return nil, err
+    }
+
+    if len(preMasterSecret) != preMasterSecretLength {
+        return nil, errClientKeyExchange
+    }
+
+    // 前两个字节为客户端版本
+    if uint16(preMasterSecret[0]) << 8 | uint16(preMasterSecret[1]) != VersionTLCP {
+        return nil, errClientKeyExchange
+    }
+
+    return preMasterSecret, nil
+}
+
+func (ka *eccKeyAgreement) processServerKeyExchange(config *Config, clientHello *clientHelloMsg, serverHello *serverHelloMsg, signCert, encCert *x509.Certificate, skx *serverKeyExchangeMsg) error {
+    s := cryptobyte.String(skx.key)
+
+    var sig cryptobyte.String
+    if !s.ReadUint16LengthPrefixed(&sig) || !s.Empty() {
+        return errServerKeyExchange
+    }
+
+    err := verifyParams(clientHello, serverHello, signCert, encCertParams(encCert.Raw), sig)
+    if err != nil {
+        return err
+    }
+
+    pub, ok := sm2PublicKey(encCert.PublicKey)
+    if !ok {
+        return errUnsupportedKey
+    }
+
+    ka.encPub = pub
+
+    return nil
+}
+
+func (ka *eccKeyAgreement) generateClientKeyExchange(config *Config, clientHello *clientHelloMsg, encCert *x509.Certificate, ownEncCert *Certificate) ([]byte, *clientKeyExchangeMsg, error) {
+    if ka.encPub == nil {
+        return nil, nil, errServerKeyExchange
+    }
+
+    preMasterSecret := make([]byte, preMasterSecretLength)
+    preMasterSecret[0] = byte(clientHello.vers >> 8)
+    preMasterSecret[1] = byte(clientHello.vers)
+
+    if _, err := io.ReadFull(config.rand(), preMasterSecret[2:]); err != nil {
+        return nil, nil, err
+    }
+
+    ciphertext, err := sm2.EncryptASN1(config.rand(), ka.encPub, preMasterSecret, sm2.C1C3C2)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    var b cryptobyte.Builder
+    b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+        b.AddBytes(ciphertext)
+    })
+
+    data, err := b.Bytes()
+    if err != nil {
+        return nil, nil, err
+    }
+
+    return preMasterSecret, &clientKeyExchangeMsg{ciphertext: data}, nil
+}
+
+// ECDHE 密钥交换, 使用 SM2 密钥交换协议, 双方都需要加密证书
+type ecdheKeyAgreement struct {
+    // 服务端临时密钥
+    priv *sm2.PrivateKey
+
+    // 服务端临时公钥及加密证书公钥
+    peerPub    *sm2.PublicKey
+    peerEncPub *sm2.PublicKey
+}
+
+// 编码临时公钥参数
+func marshalECDHEParams(pub *sm2.PublicKey) ([]byte, error) {
+    point := sm2.ToPublicKey(pub)
+
+    var b cryptobyte.Builder
+    b.AddUint8(curveTypeNamedCurve)
+    b.AddUint16(curveSM2)
+    b.AddUint8LengthPrefixed(func(b *cryptobyte.Builder) {
+        b.AddBytes(point)
+    })
+
+    return b.Bytes()
+}
+
+// 解析临时公钥参数, 返回公钥及剩余数据
+func unmarshalECDHEParams(data []byte) (*sm2.PublicKey, []byte, bool) {
+    s := cryptobyte.String(data)
+
+    var curveType uint8
+    var curve uint16
+    var point cryptobyte.String
+    if !s.ReadUint8(&curveType) || curveType != curveTypeNamedCurve ||
+        !s.ReadUint16(&curve) || curve != curveSM2 ||
+        !s.ReadUint8LengthPrefixed(&point) {
+        return nil, nil, false
+    }
+
+    pub, err := sm2.NewPublicKey(point)
+    if err != nil {
+        return nil, nil, false
+    }
+
+    return pub, []byte(s), true
+}
+
+func (ka *ecdheKeyAgreement) generateServerKeyExchange(config *Config, signCert, encCert *Certificate, clientHello *clientHelloMsg, serverHello *serverHelloMsg) (*serverKeyExchangeMsg, error) {
+    priv, err := sm2.GenerateKey(config.rand())
+    if err != nil {
+        return nil, err
+    }
+
+    ka.priv = priv
+
+    params, err := marshalECDHEParams(&priv.PublicKey)
+    if err != nil {
+        return nil, err
+    }
+
+    sig, err := signParams(config, signCert, clientHello, serverHello, params)
+    if err != nil {
+        return nil, err
+    }
+
+    return &serverKeyExchangeMsg{key: append(params, sig...)}, nil
+}
+
+func (ka *ecdheKeyAgreement) processClientKeyExchange(config *Config, encCert *Certificate, ckx *clientKeyExchangeMsg, peerEncCert *x509.Certificate) ([]byte, error) {
+    if ka.priv == nil {
+        return nil, errClientKeyExchange
+    }
+
+    encPriv, err := sm2PrivateKey(encCert)
+    if err != nil {
+        return nil, err
+    }
+
+    if peerEncCert == nil {
+        return nil, errors.New("tlcp: client enc certificate is required for ECDHE")
+    }
+
+    peerEncPub, ok := sm2PublicKey(peerEncCert.PublicKey)
+    if !ok {
+        return nil, errUnsupportedKey
+    }
+
+    peerPub, rest, ok := unmarshalECDHEParams(ckx.ciphertext)
+    if !ok || len(rest) != 0 {
+        return nil, errClientKeyExchange
+    }
+
+    preMasterSecret, _, _, err := sm2.KeyExchangeB(preMasterSecretLength, defaultUID, defaultUID, encPriv, peerEncPub, ka.priv, peerPub)
+    if err != nil {
+        return nil, err
+    }
+
+    return preMasterSecret, nil
+}
+
+func (ka *ecdheKeyAgreement) processServerKeyExchange(config *Config, clientHello *clientHelloMsg, serverHello *serverHelloMsg, signCert, encCert *x509.Certificate, skx *serverKeyExchangeMsg) error {
+    peerPub, rest, ok := unmarshalECDHEParams(skx.key)
+    if !ok {
+        return errServerKeyExchange
+    }
+
+    params := skx.key[:len(skx.key) - len(rest)]
+
+    s := cryptobyte.String(rest)
+
+    var sig cryptobyte.String
+    if !s.ReadUint16LengthPrefixed(&sig) || !s.Empty() {
+        return errServerKeyExchange
+    }
+
+    if err := verifyParams(clientHello, serverHello, signCert, params, sig); err != nil {
+        return err
+    }
+
+    peerEncPub, ok := sm2PublicKey(encCert.PublicKey)
+    if !ok {
+        return errUnsupportedKey
+    }
+
+    ka.peerPub = peerPub
+    ka.peerEncPub = peerEncPub
+
+    return nil
+}
+
+func (ka *ecdheKeyAgreement) generateClientKeyExchange(config *Config, clientHello *clientHelloMsg, encCert *x509.Certificate, ownEncCert *Certificate) ([]byte, *clientKeyExchangeMsg, error) {
+    if ka.peerPub == nil {
+        return nil, nil, errServerKeyExchange
+    }
+
+    encPriv, err := sm2PrivateKey(ownEncCert)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    priv, err := sm2.GenerateKey(config.rand())
+    if err != nil {
+        return nil, nil, err
+    }
+
+    preMasterSecret, _, _, err := sm2.KeyExchangeA(preMasterSecretLength, defaultUID, defaultUID, encPriv, ka.peerEncPub, priv, ka.peerPub)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    params, err := marshalECDHEParams(&priv.PublicKey)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    return preMasterSecret, &clientKeyExchangeMsg{ciphertext: params}, nil
+}
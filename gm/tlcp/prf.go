@@ -0,0 +1,121 @@
+package tlcp
+
+import (
+    "hash"
+    "crypto/hmac"
+
+    "github.com/deatil/go-cryptobin/hash/sm3"
+)
+
+const (
+    masterSecretLength   = 48
+    finishedVerifyLength = 12
+)
+
+var (
+    masterSecretLabel   = []byte("master secret")
+    keyExpansionLabel   = []byte("key expansion")
+    clientFinishedLabel = []byte("client finished")
+    serverFinishedLabel = []byte("server finished")
+)
+
+// P_SM3
+func pHash(result, secret, seed []byte) {
+    h := hmac.New(sm3.New, secret)
+    h.Write(seed)
+    a := h.Sum(nil)
+
+    j := 0
+    for j < len(result) {
+        h.Reset()
+        h.Write(a)
+        h.Write(seed)
+        b := h.Sum(nil)
+        copy(result[j:], b)
+        j += len(b)
+
+        h.Reset()
+        h.Write(a)
+        a = h.Sum(nil)
+    }
+}
+
+// PRF(secret, label, seed) = P_SM3(secret, label + seed)
+func prf(result, secret, label, seed []byte) {
+    labelAndSeed := make([]byte, len(label) + len(seed))
+    copy(labelAndSeed, label)
+    copy(labelAndSeed[len(label):], seed)
+
+    pHash(result, secret, labelAndSeed)
+}
+
+// 主密钥
+func masterFromPreMasterSecret(preMasterSecret, clientRandom, serverRandom []byte) []byte {
+    seed := make([]byte, 0, len(clientRandom) + len(serverRandom))
+    seed = append(seed, clientRandom...)
+    seed = append(seed, serverRandom...)
+
+    masterSecret := make([]byte, masterSecretLength)
+    prf(masterSecret, preMasterSecret, masterSecretLabel, seed)
+
+    return masterSecret
+}
+
+// 工作密钥
+func keysFromMasterSecret(masterSecret, clientRandom, serverRandom []byte, macLen, keyLen, ivLen int) (clientMAC, serverMAC, clientKey, serverKey, clientIV, serverIV []byte) {
+    seed := make([]byte, 0, len(serverRandom) + len(clientRandom))
+    seed = append(seed, serverRandom...)
+    seed = append(seed, clientRandom...)
+
+    n := 2*macLen + 2*keyLen + 2*ivLen
+    keyMaterial := make([]byte, n)
+    prf(keyMaterial, masterSecret, keyExpansionLabel, seed)
+
+    clientMAC = keyMaterial[:macLen]
+    keyMaterial = keyMaterial[macLen:]
+    serverMAC = keyMaterial[:macLen]
+    keyMaterial = keyMaterial[macLen:]
+    clientKey = keyMaterial[:keyLen]
+    keyMaterial = keyMaterial[keyLen:]
+    serverKey = keyMaterial[:keyLen]
+    keyMaterial = keyMaterial[keyLen:]
+    clientIV = keyMaterial[:ivLen]
+    keyMaterial = keyMaterial[ivLen:]
+    serverIV = keyMaterial[:ivLen]
+
+    return
+}
+
+// 握手消息摘要
+type finishedHash struct {
+    h hash.Hash
+}
+
+func newFinishedHash() finishedHash {
+    return finishedHash{
+        h: sm3.New(),
+    }
+}
+
+func (h finishedHash) Write(msg []byte) (int, error) {
+    return h.h.Write(msg)
+}
+
+// 当前握手消息的摘要
+func (h finishedHash) Sum() []byte {
+    return h.h.Sum(nil)
+}
+
+func (h finishedHash) clientSum(masterSecret []byte) []byte {
+    out := make([]byte, finishedVerifyLength)
+    prf(out, masterSecret, clientFinishedLabel, h.Sum())
+
+    return out
+}
+
+func (h finishedHash) serverSum(masterSecret []byte) []byte {
+    out := make([]byte, finishedVerifyLength)
+    prf(out, masterSecret, serverFinishedLabel, h.Sum())
+
+    return out
+}
@@ -0,0 +1,204 @@
+package tlcp
+
+import (
+    "os"
+    "net"
+    "time"
+    "errors"
+    "context"
+    "encoding/pem"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+    "github.com/deatil/go-cryptobin/gm/x509"
+)
+
+// 服务端连接, config 需要设置签名证书及加密证书
+func Server(conn net.Conn, config *Config) *Conn {
+    return &Conn{
+        conn:   conn,
+        config: config,
+    }
+}
+
+// 客户端连接, config 需要设置 ServerName 或者 InsecureSkipVerify
+func Client(conn net.Conn, config *Config) *Conn {
+    return &Conn{
+        conn:     conn,
+        config:   config,
+        isClient: true,
+    }
+}
+
+type listener struct {
+    net.Listener
+    config *Config
+}
+
+// 接收连接, 返回的连接在第一次读写时握手
+func (l *listener) Accept() (net.Conn, error) {
+    c, err := l.Listener.Accept()
+    if err != nil {
+        return nil, err
+    }
+
+    return Server(c, l.config), nil
+}
+
+// 使用已有的 Listener 创建 TLCP Listener
+func NewListener(inner net.Listener, config *Config) net.Listener {
+    l := new(listener)
+    l.Listener = inner
+    l.config = config
+
+    return l
+}
+
+// 监听地址
+func Listen(network, laddr string, config *Config) (net.Listener, error) {
+    if config == nil || len(config.Certificates) < 2 {
+        return nil, errors.New("tlcp: neither Certificates nor enc Certificate set in Config")
+    }
+
+    l, err := net.Listen(network, laddr)
+    if err != nil {
+        return nil, err
+    }
+
+    return NewListener(l, config), nil
+}
+
+// 使用 Dialer 连接并握手
+func DialWithDialer(dialer *net.Dialer, network, addr string, config *Config) (*Conn, error) {
+    return dial(context.Background(), dialer, network, addr, config)
+}
+
+// 连接并握手
+func Dial(network, addr string, config *Config) (*Conn, error) {
+    return DialWithDialer(new(net.Dialer), network, addr, config)
+}
+
+func dial(ctx context.Context, netDialer *net.Dialer, network, addr string, config *Config) (*Conn, error) {
+    if netDialer.Timeout != 0 {
+        var cancel context.CancelFunc
+        ctx, cancel = context.WithTimeout(ctx, netDialer.Timeout)
+        defer cancel()
+    }
+
+    rawConn, err := netDialer.DialContext(ctx, network, addr)
+    if err != nil {
+        return nil, err
+    }
+
+    if config == nil {
+        config = &Config{}
+    }
+
+    // 没有设置 ServerName 时使用地址中的主机名
+    if config.ServerName == "" {
+        hostname := addr
+        if host, _, err := net.SplitHostPort(addr); err == nil {
+            hostname = host
+        }
+
+        c := config.Clone()
+        c.ServerName = hostname
+        config = c
+    }
+
+    conn := Client(rawConn, config)
+
+    if deadline, ok := ctx.Deadline(); ok {
+        conn.SetDeadline(deadline)
+        defer conn.SetDeadline(time.Time{})
+    }
+
+    if err := conn.Handshake(); err != nil {
+        rawConn.Close()
+        return nil, err
+    }
+
+    return conn, nil
+}
+
+// 读取 PEM 编码的证书及私钥文件
+func LoadX509KeyPair(certFile, keyFile string) (Certificate, error) {
+    certPEMBlock, err := os.ReadFile(certFile)
+    if err != nil {
+        return Certificate{}, err
+    }
+
+    keyPEMBlock, err := os.ReadFile(keyFile)
+    if err != nil {
+        return Certificate{}, err
+    }
+
+    return X509KeyPair(certPEMBlock, keyPEMBlock)
+}
+
+// 解析 PEM 编码的证书及私钥
+func X509KeyPair(certPEMBlock, keyPEMBlock []byte) (Certificate, error) {
+    var cert Certificate
+
+    for {
+        var certDERBlock *pem.Block
+        certDERBlock, certPEMBlock = pem.Decode(certPEMBlock)
+        if certDERBlock == nil {
+            break
+        }
+
+        if certDERBlock.Type == "CERTIFICATE" {
+            cert.Certificate = append(cert.Certificate, certDERBlock.Bytes)
+        }
+    }
+
+    if len(cert.Certificate) == 0 {
+        return Certificate{}, errors.New("tlcp: failed to find any PEM data in certificate input")
+    }
+
+    var keyDERBlock *pem.Block
+    for {
+        keyDERBlock, keyPEMBlock = pem.Decode(keyPEMBlock)
+        if keyDERBlock == nil {
+            return Certificate{}, errors.New("tlcp: failed to find PEM block with type ending in \"PRIVATE KEY\" in key input")
+        }
+
+        if keyDERBlock.Type == "PRIVATE KEY" ||
+            keyDERBlock.Type == "SM2 PRIVATE KEY" ||
+            keyDERBlock.Type == "EC PRIVATE KEY" {
+            break
+        }
+    }
+
+    leaf, err := x509.ParseCertificate(cert.Certificate[0])
+    if err != nil {
+        return Certificate{}, err
+    }
+
+    cert.Leaf = leaf
+
+    key, err := parsePrivateKey(keyDERBlock.Bytes)
+    if err != nil {
+        return Certificate{}, err
+    }
+
+    pub, ok := sm2PublicKey(leaf.PublicKey)
+    if !ok || !pub.Equal(&key.PublicKey) {
+        return Certificate{}, errors.New("tlcp: private key does not match public key")
+    }
+
+    cert.PrivateKey = key
+
+    return cert, nil
+}
+
+func parsePrivateKey(der []byte) (*sm2.PrivateKey, error) {
+    if key, err := sm2.ParsePrivateKey(der); err == nil {
+        return key, nil
+    }
+
+    if key, err := sm2.ParseSM2PrivateKey(der); err == nil {
+        return key, nil
+    }
+
+    return nil, errors.New("tlcp: failed to parse private key")
+}
@@ -0,0 +1,387 @@
+package tlcp
+
+import (
+    "io"
+    "net"
+    "time"
+    "bytes"
+    "testing"
+    "math/big"
+    "crypto/rand"
+    "crypto/x509/pkix"
+    "encoding/pem"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+    "github.com/deatil/go-cryptobin/gm/x509"
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+type testPKI struct {
+    root       *x509.Certificate
+    rootKey    *sm2.PrivateKey
+    serverSign Certificate
+    serverEnc  Certificate
+    clientSign Certificate
+    clientEnc  Certificate
+}
+
+var testSerial int64
+
+func createTestCert(t *testing.T, template, parent *x509.Certificate, pub *sm2.PublicKey, signer *sm2.PrivateKey) *x509.Certificate {
+    testSerial++
+
+    template.SerialNumber = big.NewInt(testSerial)
+    template.SignatureAlgorithm = x509.SM2WithSM3
+
+    der, err := x509.CreateCertificate(template, parent, pub, signer)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    cert, err := x509.ParseCertificate(der)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return cert
+}
+
+func createTestLeaf(t *testing.T, root *x509.Certificate, rootKey *sm2.PrivateKey, name string, keyUsage x509.KeyUsage, extKeyUsage x509.ExtKeyUsage) Certificate {
+    key, err := sm2.GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    template := &x509.Certificate{
+        Subject:     pkix.Name{CommonName: name},
+        NotBefore:   time.Now().Add(-time.Hour),
+        NotAfter:    time.Now().Add(time.Hour),
+        KeyUsage:    keyUsage,
+        ExtKeyUsage: []x509.ExtKeyUsage{extKeyUsage},
+        DNSNames:    []string{name},
+    }
+
+    cert := createTestCert(t, template, root, &key.PublicKey, rootKey)
+
+    return Certificate{
+        Certificate: [][]byte{cert.Raw},
+        PrivateKey:  key,
+        Leaf:        cert,
+    }
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+    rootKey, err := sm2.GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    rootTemplate := &x509.Certificate{
+        Subject:               pkix.Name{CommonName: "Test Root CA"},
+        NotBefore:             time.Now().Add(-time.Hour),
+        NotAfter:              time.Now().Add(time.Hour),
+        KeyUsage:              x509.KeyUsageCertSign,
+        BasicConstraintsValid: true,
+        IsCA:                  true,
+    }
+
+    root := createTestCert(t, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
+
+    signUsage := x509.KeyUsageDigitalSignature
+    encUsage := x509.KeyUsageKeyEncipherment | x509.KeyUsageDataEncipherment | x509.KeyUsageKeyAgreement
+
+    return &testPKI{
+        root:       root,
+        rootKey:    rootKey,
+        serverSign: createTestLeaf(t, root, rootKey, "server.example.com", signUsage, x509.ExtKeyUsageServerAuth),
+        serverEnc:  createTestLeaf(t, root, rootKey, "server.example.com", encUsage, x509.ExtKeyUsageServerAuth),
+        clientSign: createTestLeaf(t, root, rootKey, "client.example.com", signUsage, x509.ExtKeyUsageClientAuth),
+        clientEnc:  createTestLeaf(t, root, rootKey, "client.example.com", encUsage, x509.ExtKeyUsageClientAuth),
+    }
+}
+
+func (p *testPKI) pool() *x509.CertPool {
+    pool := x509.NewCertPool()
+    pool.AddCert(p.root)
+
+    return pool
+}
+
+func (p *testPKI) serverConfig() *Config {
+    return &Config{
+        Certificates: []Certificate{p.serverSign, p.serverEnc},
+        ClientCAs:    p.pool(),
+    }
+}
+
+func (p *testPKI) clientConfig() *Config {
+    return &Config{
+        Certificates: []Certificate{p.clientSign, p.clientEnc},
+        RootCAs:      p.pool(),
+        ServerName:   "server.example.com",
+    }
+}
+
+// 在 net.Pipe 上握手并交换数据
+func runPipe(t *testing.T, clientConfig, serverConfig *Config) (ConnectionState, ConnectionState, error, error) {
+    c, s := net.Pipe()
+
+    client := Client(c, clientConfig)
+    server := Server(s, serverConfig)
+
+    var serverState ConnectionState
+    serverErr := make(chan error, 1)
+
+    go func() {
+        defer server.Close()
+
+        if err := server.Handshake(); err != nil {
+            serverErr <- err
+            s.Close()
+            return
+        }
+
+        serverState = server.ConnectionState()
+
+        buf := make([]byte, 1024)
+        n, err := server.Read(buf)
+        if err != nil {
+            serverErr <- err
+            return
+        }
+
+        if _, err := server.Write(bytes.ToUpper(buf[:n])); err != nil {
+            serverErr <- err
+            return
+        }
+
+        // 等待客户端关闭
+        _, err = server.Read(buf)
+        if err != io.EOF {
+            serverErr <- err
+            return
+        }
+
+        serverErr <- nil
+    }()
+
+    clientErr := func() error {
+        defer client.Close()
+
+        if err := client.Handshake(); err != nil {
+            c.Close()
+            return err
+        }
+
+        msg := []byte("hello tlcp")
+        if _, err := client.Write(msg); err != nil {
+            return err
+        }
+
+        buf := make([]byte, len(msg))
+        if _, err := io.ReadFull(client, buf); err != nil {
+            return err
+        }
+
+        if !bytes.Equal(buf, bytes.ToUpper(msg)) {
+            t.Errorf("got %q, want %q", buf, bytes.ToUpper(msg))
+        }
+
+        return nil
+    }()
+
+    return client.ConnectionState(), serverState, clientErr, <-serverErr
+}
+
+func Test_CipherSuites(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    pki := newTestPKI(t)
+
+    for _, suite := range defaultCipherSuites {
+        name := CipherSuiteName(suite)
+
+        clientConfig := pki.clientConfig()
+        clientConfig.CipherSuites = []uint16{suite}
+
+        cs, ss, clientErr, serverErr := runPipe(t, clientConfig, pki.serverConfig())
+        assertError(clientErr, name + "-client")
+        assertError(serverErr, name + "-server")
+
+        assertEqual(cs.HandshakeComplete, true, name + "-HandshakeComplete")
+        assertEqual(cs.Version, uint16(VersionTLCP), name + "-Version")
+        assertEqual(cs.CipherSuite, suite, name + "-client CipherSuite")
+        assertEqual(ss.CipherSuite, suite, name + "-server CipherSuite")
+        assertEqual(len(cs.PeerCertificates), 2, name + "-PeerCertificates")
+        assertEqual(len(cs.VerifiedChains), 1, name + "-VerifiedChains")
+    }
+}
+
+func Test_ClientAuth(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    pki := newTestPKI(t)
+
+    serverConfig := pki.serverConfig()
+    serverConfig.ClientAuth = RequireAndVerifyClientCert
+
+    _, ss, clientErr, serverErr := runPipe(t, pki.clientConfig(), serverConfig)
+    assertError(clientErr, "client")
+    assertError(serverErr, "server")
+    assertEqual(len(ss.PeerCertificates), 2, "PeerCertificates")
+    assertEqual(ss.PeerCertificates[0].Subject.CommonName, "client.example.com", "CommonName")
+
+    // 客户端没有证书
+    clientConfig := pki.clientConfig()
+    clientConfig.Certificates = nil
+    clientConfig.CipherSuites = []uint16{ECC_SM4_GCM_SM3}
+
+    _, _, clientErr, serverErr = runPipe(t, clientConfig, serverConfig)
+    assertNotErrorNil(clientErr, "no cert client")
+    assertNotErrorNil(serverErr, "no cert server")
+}
+
+func Test_SessionResumption(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    pki := newTestPKI(t)
+
+    for _, suite := range []uint16{ECC_SM4_CBC_SM3, ECDHE_SM4_GCM_SM3} {
+        name := CipherSuiteName(suite)
+
+        serverConfig := pki.serverConfig()
+        serverConfig.SessionCache = NewLRUSessionCache(0)
+
+        clientConfig := pki.clientConfig()
+        clientConfig.CipherSuites = []uint16{suite}
+        clientConfig.SessionCache = NewLRUSessionCache(0)
+
+        cs, ss, clientErr, serverErr := runPipe(t, clientConfig, serverConfig)
+        assertError(clientErr, name + "-first client")
+        assertError(serverErr, name + "-first server")
+        assertEqual(cs.DidResume, false, name + "-first client DidResume")
+        assertEqual(ss.DidResume, false, name + "-first server DidResume")
+
+        cs, ss, clientErr, serverErr = runPipe(t, clientConfig, serverConfig)
+        assertError(clientErr, name + "-second client")
+        assertError(serverErr, name + "-second server")
+        assertEqual(cs.DidResume, true, name + "-second client DidResume")
+        assertEqual(ss.DidResume, true, name + "-second server DidResume")
+        assertEqual(cs.CipherSuite, suite, name + "-second CipherSuite")
+        assertEqual(len(cs.PeerCertificates), 2, name + "-second PeerCertificates")
+    }
+}
+
+func Test_BadCertificate(t *testing.T) {
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    pki := newTestPKI(t)
+    other := newTestPKI(t)
+
+    // 不信任的根证书
+    clientConfig := pki.clientConfig()
+    clientConfig.RootCAs = other.pool()
+
+    _, _, clientErr, serverErr := runPipe(t, clientConfig, pki.serverConfig())
+    assertNotErrorNil(clientErr, "untrusted client")
+    assertNotErrorNil(serverErr, "untrusted server")
+
+    // 名称不匹配
+    clientConfig = pki.clientConfig()
+    clientConfig.ServerName = "other.example.com"
+
+    _, _, clientErr, serverErr = runPipe(t, clientConfig, pki.serverConfig())
+    assertNotErrorNil(clientErr, "name client")
+    assertNotErrorNil(serverErr, "name server")
+
+    // 跳过验证
+    clientConfig.InsecureSkipVerify = true
+
+    _, _, clientErr, serverErr = runPipe(t, clientConfig, pki.serverConfig())
+    if clientErr != nil || serverErr != nil {
+        t.Fatalf("InsecureSkipVerify: %v, %v", clientErr, serverErr)
+    }
+}
+
+func Test_NoCommonCipherSuite(t *testing.T) {
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    pki := newTestPKI(t)
+
+    clientConfig := pki.clientConfig()
+    clientConfig.CipherSuites = []uint16{ECC_SM4_CBC_SM3}
+
+    serverConfig := pki.serverConfig()
+    serverConfig.CipherSuites = []uint16{ECC_SM4_GCM_SM3}
+
+    _, _, clientErr, serverErr := runPipe(t, clientConfig, serverConfig)
+    assertNotErrorNil(clientErr, "client")
+    assertNotErrorNil(serverErr, "server")
+}
+
+func Test_LargeData(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+
+    pki := newTestPKI(t)
+
+    for _, suite := range []uint16{ECC_SM4_CBC_SM3, ECC_SM4_GCM_SM3} {
+        c, s := net.Pipe()
+
+        clientConfig := pki.clientConfig()
+        clientConfig.CipherSuites = []uint16{suite}
+
+        client := Client(c, clientConfig)
+        server := Server(s, pki.serverConfig())
+
+        data := make([]byte, 3 * maxPlaintext + 100)
+        rand.Read(data)
+
+        done := make(chan []byte, 1)
+        go func() {
+            defer server.Close()
+
+            got, _ := io.ReadAll(server)
+            done <- got
+        }()
+
+        _, err := client.Write(data)
+        assertError(err, "Write")
+        client.Close()
+
+        assertEqual(<-done, data, CipherSuiteName(suite))
+    }
+}
+
+func Test_X509KeyPair(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    pki := newTestPKI(t)
+
+    keyDER, err := sm2.MarshalPrivateKey(pki.serverSign.PrivateKey.(*sm2.PrivateKey))
+    assertError(err, "MarshalPrivateKey")
+
+    certPEM := pemEncode("CERTIFICATE", pki.serverSign.Certificate[0])
+    keyPEM := pemEncode("PRIVATE KEY", keyDER)
+
+    cert, err := X509KeyPair(certPEM, keyPEM)
+    assertError(err, "X509KeyPair")
+
+    if cert.Leaf == nil || len(cert.Certificate) != 1 {
+        t.Error("X509KeyPair: bad certificate")
+    }
+
+    otherDER, _ := sm2.MarshalPrivateKey(pki.serverEnc.PrivateKey.(*sm2.PrivateKey))
+
+    _, err = X509KeyPair(certPEM, pemEncode("PRIVATE KEY", otherDER))
+    assertNotErrorNil(err, "X509KeyPair mismatch")
+}
+
+func pemEncode(typ string, der []byte) []byte {
+    return pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+}
@@ -1533,6 +1533,7 @@ var (
     oidExtensionNameConstraints       = []int{2, 5, 29, 30}
     oidExtensionCRLDistributionPoints = []int{2, 5, 29, 31}
     oidExtensionAuthorityInfoAccess   = []int{1, 3, 6, 1, 5, 5, 7, 1, 1}
+    oidExtensionCRLNumber             = []int{2, 5, 29, 20}
 )
 
 var (
@@ -1949,6 +1950,150 @@ func (c *Certificate) CreateCRL(rand io.Reader, priv interface{}, revokedCerts [
     })
 }
 
+// RevocationList contains the fields used to create an X.509 v2 Certificate
+// Revocation list with CreateRevocationList.
+type RevocationList struct {
+    // SignatureAlgorithm is used to determine the signature algorithm to be
+    // used when signing the CRL. If 0 the default algorithm for the signing
+    // key will be used.
+    SignatureAlgorithm SignatureAlgorithm
+
+    // RevokedCertificates is used to populate the revokedCertificates
+    // sequence in the CRL, it may be empty. RevokedCertificates may be nil,
+    // in which case an empty CRL will be created.
+    RevokedCertificates []pkix.RevokedCertificate
+
+    // Number is used to populate the X.509 v2 cRLNumber extension in the CRL,
+    // which should be a monotonically increasing sequence number for a given
+    // CRL scope and CRL issuer.
+    Number *big.Int
+
+    // ThisUpdate is used to populate the thisUpdate field in the CRL, which
+    // indicates the issuance date of the CRL.
+    ThisUpdate time.Time
+    // NextUpdate is used to populate the nextUpdate field in the CRL, which
+    // indicates the date by which the next CRL will be issued. NextUpdate
+    // must be greater than ThisUpdate.
+    NextUpdate time.Time
+
+    // ExtraExtensions contains any additional extensions to add directly to
+    // the CRL, such as the delta CRL indicator.
+    ExtraExtensions []pkix.Extension
+}
+
+// CreateRevocationList creates a new X.509 v2 Certificate Revocation List,
+// according to RFC 5280, based on template.
+//
+// The CRL is signed by priv which should be the private key associated with
+// the public key in the issuer certificate. If the issuer has a key usage
+// extension, it must have the crlSign bit set.
+func CreateRevocationList(rand io.Reader, template *RevocationList, issuer *Certificate, priv crypto.Signer) ([]byte, error) {
+    if template == nil {
+        return nil, errors.New("x509: template can not be nil")
+    }
+    if issuer == nil {
+        return nil, errors.New("x509: issuer can not be nil")
+    }
+    if issuer.KeyUsage != 0 && (issuer.KeyUsage&KeyUsageCRLSign) == 0 {
+        return nil, errors.New("x509: issuer must have the crlSign key usage bit set")
+    }
+    if template.NextUpdate.Before(template.ThisUpdate) {
+        return nil, errors.New("x509: template.ThisUpdate is after template.NextUpdate")
+    }
+    if template.Number == nil {
+        return nil, errors.New("x509: template contains nil Number field")
+    }
+    // RFC 5280 Section 5.2.3: CRL numbers are at most 20 octets.
+    if len(template.Number.Bytes()) > 20 {
+        return nil, errors.New("x509: CRL number exceeds 20 octets")
+    }
+
+    hashFunc, signatureAlgorithm, err := signingParamsForPublicKey(priv.Public(), template.SignatureAlgorithm)
+    if err != nil {
+        return nil, err
+    }
+
+    // Force revocation times to UTC per RFC 5280.
+    revokedCertsUTC := make([]pkix.RevokedCertificate, len(template.RevokedCertificates))
+    for i, rc := range template.RevokedCertificates {
+        rc.RevocationTime = rc.RevocationTime.UTC()
+        revokedCertsUTC[i] = rc
+    }
+
+    crlNum, err := asn1.Marshal(template.Number)
+    if err != nil {
+        return nil, err
+    }
+
+    var issuerName pkix.RDNSequence
+    if _, err := asn1.Unmarshal(issuer.RawSubject, &issuerName); err != nil {
+        return nil, err
+    }
+
+    tbsCertList := pkix.TBSCertificateList{
+        Version:    1, // v2
+        Signature:  signatureAlgorithm,
+        Issuer:     issuerName,
+        ThisUpdate: template.ThisUpdate.UTC(),
+        NextUpdate: template.NextUpdate.UTC(),
+    }
+
+    // Authority Key Id
+    if len(issuer.SubjectKeyId) > 0 {
+        aki, err := asn1.Marshal(authKeyId{Id: issuer.SubjectKeyId})
+        if err != nil {
+            return nil, err
+        }
+
+        tbsCertList.Extensions = append(tbsCertList.Extensions, pkix.Extension{
+            Id:    oidExtensionAuthorityKeyId,
+            Value: aki,
+        })
+    }
+
+    tbsCertList.Extensions = append(tbsCertList.Extensions, pkix.Extension{
+        Id:    oidExtensionCRLNumber,
+        Value: crlNum,
+    })
+
+    if len(revokedCertsUTC) > 0 {
+        tbsCertList.RevokedCertificates = revokedCertsUTC
+    }
+    if len(template.ExtraExtensions) > 0 {
+        tbsCertList.Extensions = append(tbsCertList.Extensions, template.ExtraExtensions...)
+    }
+
+    tbsCertListContents, err := asn1.Marshal(tbsCertList)
+    if err != nil {
+        return nil, err
+    }
+
+    // Optimization to only marshal this struct once, when signing and
+    // then embedding in certificateList below.
+    tbsCertList.Raw = tbsCertListContents
+
+    digest := tbsCertListContents
+    switch hashFunc {
+    case SM3:
+        break
+    default:
+        h := hashFunc.New()
+        h.Write(tbsCertListContents)
+        digest = h.Sum(nil)
+    }
+
+    signature, err := priv.Sign(rand, digest, hashFunc)
+    if err != nil {
+        return nil, err
+    }
+
+    return asn1.Marshal(pkix.CertificateList{
+        TBSCertList:        tbsCertList,
+        SignatureAlgorithm: signatureAlgorithm,
+        SignatureValue:     asn1.BitString{Bytes: signature, BitLength: len(signature) * 8},
+    })
+}
+
 // CertificateRequest represents a PKCS #10, certificate signature request.
 type CertificateRequest struct {
     Raw                      []byte // Complete ASN.1 DER content (CSR, signature algorithm and signature).
@@ -0,0 +1,117 @@
+package polyval
+
+import (
+    "errors"
+    "encoding/binary"
+)
+
+/**
+ * POLYVAL (RFC 8452)
+ * 域为 GF(2^128), 多项式为 x^128 + x^127 + x^126 + x^121 + 1, 数据为小端序
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+
+const (
+    // 分组长度
+    BlockSize = 16
+
+    // 密钥长度
+    KeySize = 16
+)
+
+var ErrKeySize = errors.New("cryptobin/polyval: key size must be 16 bytes")
+
+type fieldElement struct {
+    lo, hi uint64
+}
+
+func loadElement(b []byte) fieldElement {
+    return fieldElement{
+        lo: binary.LittleEndian.Uint64(b[:8]),
+        hi: binary.LittleEndian.Uint64(b[8:]),
+    }
+}
+
+// dot(a, b) = a * b * x^-128
+func dot(a, b fieldElement) fieldElement {
+    var r fieldElement
+
+    for i := 0; i < 128; i++ {
+        var bit uint64
+        if i < 64 {
+            bit = (b.lo >> uint(i)) & 1
+        } else {
+            bit = (b.hi >> uint(i - 64)) & 1
+        }
+
+        mask := -bit
+        r.lo ^= a.lo & mask
+        r.hi ^= a.hi & mask
+
+        // r = r * x^-1
+        carry := -(r.lo & 1)
+        r.lo = r.lo >> 1 | r.hi << 63
+        r.hi = r.hi >> 1
+        r.hi ^= 0xe100000000000000 & carry
+    }
+
+    return r
+}
+
+type Polyval struct {
+    h fieldElement
+    s fieldElement
+}
+
+// 使用密钥 H 生成
+func New(key []byte) (*Polyval, error) {
+    if len(key) != KeySize {
+        return nil, ErrKeySize
+    }
+
+    p := &Polyval{
+        h: loadElement(key),
+    }
+
+    return p, nil
+}
+
+// 写入数据, 长度需为 16 的倍数
+func (this *Polyval) Update(blocks []byte) {
+    if len(blocks) % BlockSize != 0 {
+        panic("cryptobin/polyval: input not multiple of 16 bytes")
+    }
+
+    this.UpdatePadded(blocks)
+}
+
+// 写入数据, 不足 16 字节时补 0
+func (this *Polyval) UpdatePadded(data []byte) {
+    for len(data) > 0 {
+        var block [BlockSize]byte
+        n := copy(block[:], data)
+        data = data[n:]
+
+        x := loadElement(block[:])
+        this.s.lo ^= x.lo
+        this.s.hi ^= x.hi
+
+        this.s = dot(this.s, this.h)
+    }
+}
+
+// 把结果添加到 b 后面
+func (this *Polyval) Sum(b []byte) []byte {
+    var out [BlockSize]byte
+    binary.LittleEndian.PutUint64(out[:8], this.s.lo)
+    binary.LittleEndian.PutUint64(out[8:], this.s.hi)
+
+    return append(b, out[:]...)
+}
+
+// 重置
+func (this *Polyval) Reset() {
+    this.s = fieldElement{}
+}
@@ -0,0 +1,57 @@
+package polyval
+
+import (
+    "testing"
+    "encoding/hex"
+)
+
+func fromHex(s string) []byte {
+    h, _ := hex.DecodeString(s)
+    return h
+}
+
+// RFC 8452 Appendix A
+func Test_Polyval(t *testing.T) {
+    h := fromHex("25629347589242761d31f826ba4b757b")
+    x1 := fromHex("4f4f95668c83dfb6401762bb2d01a262")
+    x2 := fromHex("d1a24ddd2721d006bbe45f20d3c9f362")
+
+    p, err := New(h)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    p.Update(x1)
+    p.Update(x2)
+
+    want := "f7a3b47b846119fae5b7866cf5e5b77e"
+
+    sum := p.Sum(nil)
+    if hex.EncodeToString(sum) != want {
+        t.Errorf("POLYVAL got %x, want %s", sum, want)
+    }
+
+    p.Reset()
+    p.UpdatePadded(append(x1, x2...))
+
+    sum = p.Sum(nil)
+    if hex.EncodeToString(sum) != want {
+        t.Errorf("POLYVAL after Reset got %x, want %s", sum, want)
+    }
+
+    p.Reset()
+    p.UpdatePadded(x1[:5])
+
+    p2, _ := New(h)
+    p2.Update(append(x1[:5], make([]byte, 11)...))
+
+    if hex.EncodeToString(p.Sum(nil)) != hex.EncodeToString(p2.Sum(nil)) {
+        t.Error("UpdatePadded should zero pad")
+    }
+}
+
+func Test_KeySize(t *testing.T) {
+    if _, err := New(make([]byte, 15)); err != ErrKeySize {
+        t.Errorf("New got %v", err)
+    }
+}
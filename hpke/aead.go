@@ -0,0 +1,115 @@
+package hpke
+
+import (
+    "errors"
+    "crypto/aes"
+    "crypto/cipher"
+
+    "golang.org/x/crypto/chacha20poly1305"
+
+    "github.com/deatil/go-cryptobin/cipher/sm4"
+)
+
+// AEAD 标识
+type AEAD uint16
+
+const (
+    AEAD_AES128GCM        AEAD = 0x0001
+    AEAD_AES256GCM        AEAD = 0x0002
+    AEAD_ChaCha20Poly1305 AEAD = 0x0003
+
+    // 只使用导出接口
+    AEAD_ExportOnly       AEAD = 0xFFFF
+)
+
+// SM4-GCM 标识, 为 0 时不启用, 使用 SetSMSuite 设置
+var aeadSM4GCM AEAD
+
+var errUnknownAEAD = errors.New("hpke: unknown aead")
+
+// 是否支持
+func (aead AEAD) IsValid() bool {
+    return aead.isRegistered() || aead.isSM4GCM()
+}
+
+// 是否为已支持的 IANA 注册标识
+func (aead AEAD) isRegistered() bool {
+    switch aead {
+        case AEAD_AES128GCM,
+            AEAD_AES256GCM,
+            AEAD_ChaCha20Poly1305,
+            AEAD_ExportOnly:
+            return true
+    }
+
+    return false
+}
+
+// 是否为 SM4-GCM
+func (aead AEAD) isSM4GCM() bool {
+    return aeadSM4GCM != 0 && aead == aeadSM4GCM
+}
+
+// Nk, 密钥长度
+func (aead AEAD) KeySize() int {
+    if aead.isSM4GCM() {
+        return 16
+    }
+
+    switch aead {
+        case AEAD_AES128GCM:
+            return 16
+        case AEAD_AES256GCM, AEAD_ChaCha20Poly1305:
+            return 32
+        case AEAD_ExportOnly:
+            return 0
+    }
+
+    panic(errUnknownAEAD)
+}
+
+// Nn, nonce 长度
+func (aead AEAD) NonceSize() int {
+    if aead.isSM4GCM() {
+        return 12
+    }
+
+    switch aead {
+        case AEAD_AES128GCM,
+            AEAD_AES256GCM,
+            AEAD_ChaCha20Poly1305:
+            return 12
+        case AEAD_ExportOnly:
+            return 0
+    }
+
+    panic(errUnknownAEAD)
+}
+
+// 生成 AEAD
+func (aead AEAD) New(key []byte) (cipher.AEAD, error) {
+    if aead.isSM4GCM() {
+        block, err := sm4.NewCipher(key)
+        if err != nil {
+            return nil, err
+        }
+
+        return cipher.NewGCM(block)
+    }
+
+    switch aead {
+        case AEAD_AES128GCM, AEAD_AES256GCM:
+            block, err := aes.NewCipher(key)
+            if err != nil {
+                return nil, err
+            }
+
+            return cipher.NewGCM(block)
+        case AEAD_ChaCha20Poly1305:
+            return chacha20poly1305.New(key)
+        case AEAD_ExportOnly:
+            return nil, errors.New("hpke: export-only aead")
+    }
+
+    return nil, errUnknownAEAD
+}
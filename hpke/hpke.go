@@ -0,0 +1,381 @@
+package hpke
+
+import (
+    "io"
+    "errors"
+    "crypto/cipher"
+    "encoding/binary"
+
+    "github.com/deatil/go-cryptobin/ecdh"
+)
+
+// 版本标识
+const versionLabel = "HPKE-v1"
+
+// 模式
+type Mode byte
+
+const (
+    ModeBase    Mode = 0x00
+    ModePSK     Mode = 0x01
+    ModeAuth    Mode = 0x02
+    ModeAuthPSK Mode = 0x03
+)
+
+var (
+    ErrInvalidSuite    = errors.New("hpke: invalid suite")
+    ErrInvalidPSK      = errors.New("hpke: invalid psk inputs")
+    ErrExportOnly      = errors.New("hpke: export-only aead can not seal or open")
+    ErrMessageLimit    = errors.New("hpke: message limit reached")
+    ErrOpen            = errors.New("hpke: open fail")
+    ErrInvalidSMSuite  = errors.New("hpke: invalid sm suite id")
+)
+
+// 启用 SM2/SM3/SM4 组合.
+// SM2/SM3/SM4 没有 IANA 注册的标识, 默认不启用, 标识需要通信双方约定,
+// 不能为 0 或者和已支持的注册标识重复. 需要在使用前设置
+func SetSMSuite(kem KEM, kdf KDF, aead AEAD) error {
+    if kem == 0 || kdf == 0 || aead == 0 {
+        return ErrInvalidSMSuite
+    }
+
+    if kem.isRegistered() || kdf.isRegistered() || aead.isRegistered() {
+        return ErrInvalidSMSuite
+    }
+
+    kemSM2 = kem
+    kdfSM3 = kdf
+    aeadSM4GCM = aead
+
+    return nil
+}
+
+/**
+ * HPKE (RFC 9180) 混合公钥加密
+ *
+ * 支持 base, psk, auth 及 auth_psk 模式和导出接口,
+ * SM2/SM3/SM4 组合未在 IANA 注册, 需要使用 SetSMSuite 设置双方约定的标识
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type Suite struct {
+    KEM  KEM
+    KDF  KDF
+    AEAD AEAD
+}
+
+// 构造函数
+func NewSuite(kem KEM, kdf KDF, aead AEAD) Suite {
+    return Suite{
+        KEM:  kem,
+        KDF:  kdf,
+        AEAD: aead,
+    }
+}
+
+// 是否支持
+func (suite Suite) IsValid() bool {
+    return suite.KEM.IsValid() &&
+        suite.KDF.IsValid() &&
+        suite.AEAD.IsValid()
+}
+
+// suite_id = concat("HPKE", I2OSP(kem_id, 2), I2OSP(kdf_id, 2), I2OSP(aead_id, 2))
+func (suite Suite) suiteID() []byte {
+    id := make([]byte, 10)
+    copy(id, "HPKE")
+    binary.BigEndian.PutUint16(id[4:], uint16(suite.KEM))
+    binary.BigEndian.PutUint16(id[6:], uint16(suite.KDF))
+    binary.BigEndian.PutUint16(id[8:], uint16(suite.AEAD))
+
+    return id
+}
+
+// 发送方 base 模式
+func (suite Suite) SetupBaseS(random io.Reader, pkR *ecdh.PublicKey, info []byte) ([]byte, *Sender, error) {
+    return suite.setupS(random, ModeBase, pkR, info, nil, nil, nil)
+}
+
+// 接收方 base 模式
+func (suite Suite) SetupBaseR(skR *ecdh.PrivateKey, enc, info []byte) (*Receiver, error) {
+    return suite.setupR(ModeBase, skR, enc, info, nil, nil, nil)
+}
+
+// 发送方 psk 模式
+func (suite Suite) SetupPSKS(random io.Reader, pkR *ecdh.PublicKey, info, psk, pskID []byte) ([]byte, *Sender, error) {
+    return suite.setupS(random, ModePSK, pkR, info, psk, pskID, nil)
+}
+
+// 接收方 psk 模式
+func (suite Suite) SetupPSKR(skR *ecdh.PrivateKey, enc, info, psk, pskID []byte) (*Receiver, error) {
+    return suite.setupR(ModePSK, skR, enc, info, psk, pskID, nil)
+}
+
+// 发送方 auth 模式, skS 为发送方私钥
+func (suite Suite) SetupAuthS(random io.Reader, pkR *ecdh.PublicKey, info []byte, skS *ecdh.PrivateKey) ([]byte, *Sender, error) {
+    if skS == nil {
+        return nil, nil, errInvalidKEMKey
+    }
+
+    return suite.setupS(random, ModeAuth, pkR, info, nil, nil, skS)
+}
+
+// 接收方 auth 模式, pkS 为发送方公钥
+func (suite Suite) SetupAuthR(skR *ecdh.PrivateKey, enc, info []byte, pkS *ecdh.PublicKey) (*Receiver, error) {
+    if pkS == nil {
+        return nil, errInvalidKEMKey
+    }
+
+    return suite.setupR(ModeAuth, skR, enc, info, nil, nil, pkS)
+}
+
+// 发送方 auth_psk 模式
+func (suite Suite) SetupAuthPSKS(random io.Reader, pkR *ecdh.PublicKey, info, psk, pskID []byte, skS *ecdh.PrivateKey) ([]byte, *Sender, error) {
+    if skS == nil {
+        return nil, nil, errInvalidKEMKey
+    }
+
+    return suite.setupS(random, ModeAuthPSK, pkR, info, psk, pskID, skS)
+}
+
+// 接收方 auth_psk 模式
+func (suite Suite) SetupAuthPSKR(skR *ecdh.PrivateKey, enc, info, psk, pskID []byte, pkS *ecdh.PublicKey) (*Receiver, error) {
+    if pkS == nil {
+        return nil, errInvalidKEMKey
+    }
+
+    return suite.setupR(ModeAuthPSK, skR, enc, info, psk, pskID, pkS)
+}
+
+// 单次加密, base 模式
+// single-shot seal
+func (suite Suite) Seal(random io.Reader, pkR *ecdh.PublicKey, info, aad, plaintext []byte) (enc, ciphertext []byte, err error) {
+    enc, sender, err := suite.SetupBaseS(random, pkR, info)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    ciphertext, err = sender.Seal(aad, plaintext)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    return enc, ciphertext, nil
+}
+
+// 单次解密, base 模式
+// single-shot open
+func (suite Suite) Open(skR *ecdh.PrivateKey, enc, info, aad, ciphertext []byte) ([]byte, error) {
+    receiver, err := suite.SetupBaseR(skR, enc, info)
+    if err != nil {
+        return nil, err
+    }
+
+    return receiver.Open(aad, ciphertext)
+}
+
+func (suite Suite) setupS(random io.Reader, mode Mode, pkR *ecdh.PublicKey, info, psk, pskID []byte, skS *ecdh.PrivateKey) ([]byte, *Sender, error) {
+    if !suite.IsValid() {
+        return nil, nil, ErrInvalidSuite
+    }
+
+    sharedSecret, enc, err := suite.KEM.encap(random, pkR, skS)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    ctx, err := suite.keySchedule(mode, sharedSecret, info, psk, pskID)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    return enc, &Sender{ctx}, nil
+}
+
+func (suite Suite) setupR(mode Mode, skR *ecdh.PrivateKey, enc, info, psk, pskID []byte, pkS *ecdh.PublicKey) (*Receiver, error) {
+    if !suite.IsValid() {
+        return nil, ErrInvalidSuite
+    }
+
+    sharedSecret, err := suite.KEM.decap(enc, skR, pkS)
+    if err != nil {
+        return nil, err
+    }
+
+    ctx, err := suite.keySchedule(mode, sharedSecret, info, psk, pskID)
+    if err != nil {
+        return nil, err
+    }
+
+    return &Receiver{ctx}, nil
+}
+
+// VerifyPSKInputs
+func verifyPSKInputs(mode Mode, psk, pskID []byte) error {
+    gotPSK := len(psk) > 0
+    gotPSKID := len(pskID) > 0
+
+    if gotPSK != gotPSKID {
+        return ErrInvalidPSK
+    }
+
+    switch mode {
+        case ModePSK, ModeAuthPSK:
+            if !gotPSK {
+                return ErrInvalidPSK
+            }
+        default:
+            if gotPSK {
+                return ErrInvalidPSK
+            }
+    }
+
+    return nil
+}
+
+// KeySchedule
+func (suite Suite) keySchedule(mode Mode, sharedSecret, info, psk, pskID []byte) (*context, error) {
+    if err := verifyPSKInputs(mode, psk, pskID); err != nil {
+        return nil, err
+    }
+
+    kdf := suite.KDF
+    suiteID := suite.suiteID()
+
+    pskIDHash := kdf.labeledExtract(suiteID, nil, "psk_id_hash", pskID)
+    infoHash := kdf.labeledExtract(suiteID, nil, "info_hash", info)
+
+    keyScheduleContext := make([]byte, 0, 1+len(pskIDHash)+len(infoHash))
+    keyScheduleContext = append(keyScheduleContext, byte(mode))
+    keyScheduleContext = append(keyScheduleContext, pskIDHash...)
+    keyScheduleContext = append(keyScheduleContext, infoHash...)
+
+    secret := kdf.labeledExtract(suiteID, sharedSecret, "secret", psk)
+
+    exporterSecret, err := kdf.labeledExpand(suiteID, secret, "exp", keyScheduleContext, kdf.ExtractSize())
+    if err != nil {
+        return nil, err
+    }
+
+    ctx := &context{
+        suite:          suite,
+        suiteID:        suiteID,
+        exporterSecret: exporterSecret,
+    }
+
+    if suite.AEAD == AEAD_ExportOnly {
+        return ctx, nil
+    }
+
+    key, err := kdf.labeledExpand(suiteID, secret, "key", keyScheduleContext, suite.AEAD.KeySize())
+    if err != nil {
+        return nil, err
+    }
+
+    baseNonce, err := kdf.labeledExpand(suiteID, secret, "base_nonce", keyScheduleContext, suite.AEAD.NonceSize())
+    if err != nil {
+        return nil, err
+    }
+
+    ctx.aead, err = suite.AEAD.New(key)
+    if err != nil {
+        return nil, err
+    }
+
+    ctx.key = key
+    ctx.baseNonce = baseNonce
+
+    return ctx, nil
+}
+
+// 加密上下文
+type context struct {
+    suite          Suite
+    suiteID        []byte
+    aead           cipher.AEAD
+    key            []byte
+    baseNonce      []byte
+    exporterSecret []byte
+    seq            uint64
+}
+
+// ComputeNonce(seq) = base_nonce XOR I2OSP(seq, Nn)
+func (ctx *context) computeNonce() []byte {
+    nonce := make([]byte, len(ctx.baseNonce))
+    binary.BigEndian.PutUint64(nonce[len(nonce)-8:], ctx.seq)
+
+    for i := range nonce {
+        nonce[i] ^= ctx.baseNonce[i]
+    }
+
+    return nonce
+}
+
+// IncrementSeq
+func (ctx *context) incrementSeq() error {
+    if ctx.seq == ^uint64(0) {
+        return ErrMessageLimit
+    }
+
+    ctx.seq++
+
+    return nil
+}
+
+// Export(exporter_context, L)
+func (ctx *context) Export(exporterContext []byte, length int) ([]byte, error) {
+    if length < 0 || length > 255*ctx.suite.KDF.ExtractSize() {
+        return nil, errors.New("hpke: invalid export length")
+    }
+
+    return ctx.suite.KDF.labeledExpand(ctx.suiteID, ctx.exporterSecret, "sec", exporterContext, length)
+}
+
+// 当前序号
+func (ctx *context) Seq() uint64 {
+    return ctx.seq
+}
+
+// 发送方
+type Sender struct {
+    *context
+}
+
+// 加密
+func (s *Sender) Seal(aad, plaintext []byte) ([]byte, error) {
+    if s.aead == nil {
+        return nil, ErrExportOnly
+    }
+
+    ciphertext := s.aead.Seal(nil, s.computeNonce(), plaintext, aad)
+
+    if err := s.incrementSeq(); err != nil {
+        return nil, err
+    }
+
+    return ciphertext, nil
+}
+
+// 接收方
+type Receiver struct {
+    *context
+}
+
+// 解密
+func (r *Receiver) Open(aad, ciphertext []byte) ([]byte, error) {
+    if r.aead == nil {
+        return nil, ErrExportOnly
+    }
+
+    plaintext, err := r.aead.Open(nil, r.computeNonce(), ciphertext, aad)
+    if err != nil {
+        return nil, ErrOpen
+    }
+
+    if err := r.incrementSeq(); err != nil {
+        return nil, err
+    }
+
+    return plaintext, nil
+}
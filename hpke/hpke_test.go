@@ -0,0 +1,434 @@
+package hpke
+
+import (
+    "os"
+    "bytes"
+    "testing"
+    "crypto/rand"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+
+    "github.com/deatil/go-cryptobin/ecdh"
+)
+
+type hexBytes []byte
+
+func (h *hexBytes) UnmarshalJSON(data []byte) error {
+    var s string
+    if err := json.Unmarshal(data, &s); err != nil {
+        return err
+    }
+
+    b, err := hex.DecodeString(s)
+    if err != nil {
+        return err
+    }
+
+    *h = b
+    return nil
+}
+
+// RFC 9180 测试向量, 每个组合只保留部分加密数据
+type testVector struct {
+    Mode               Mode     `json:"mode"`
+    KEM                KEM      `json:"kem_id"`
+    KDF                KDF      `json:"kdf_id"`
+    AEAD               AEAD     `json:"aead_id"`
+    Info               hexBytes `json:"info"`
+    IkmE               hexBytes `json:"ikmE"`
+    IkmR               hexBytes `json:"ikmR"`
+    IkmS               hexBytes `json:"ikmS"`
+    SkEm               hexBytes `json:"skEm"`
+    SkRm               hexBytes `json:"skRm"`
+    SkSm               hexBytes `json:"skSm"`
+    PkEm               hexBytes `json:"pkEm"`
+    PkRm               hexBytes `json:"pkRm"`
+    PkSm               hexBytes `json:"pkSm"`
+    Psk                hexBytes `json:"psk"`
+    PskID              hexBytes `json:"psk_id"`
+    Enc                hexBytes `json:"enc"`
+    SharedSecret       hexBytes `json:"shared_secret"`
+    KeyScheduleContext hexBytes `json:"key_schedule_context"`
+    Secret             hexBytes `json:"secret"`
+    Key                hexBytes `json:"key"`
+    BaseNonce          hexBytes `json:"base_nonce"`
+    ExporterSecret     hexBytes `json:"exporter_secret"`
+    Encryptions        []struct {
+        Seq   uint64   `json:"seq"`
+        Aad   hexBytes `json:"aad"`
+        Ct    hexBytes `json:"ct"`
+        Nonce hexBytes `json:"nonce"`
+        Pt    hexBytes `json:"pt"`
+    } `json:"encryptions"`
+    Exports []struct {
+        Context hexBytes `json:"exporter_context"`
+        L       int      `json:"L"`
+        Value   hexBytes `json:"exported_value"`
+    } `json:"exports"`
+}
+
+func Test_RFC9180Vectors(t *testing.T) {
+    testVectorFile(t, "testdata/rfc9180.json")
+}
+
+// DHKEM(P-384) 不在 RFC 9180 的测试向量中,
+// 数据为本包生成, 只用于检测结果不会改变
+func Test_DHKEMP384SelfGenerated(t *testing.T) {
+    testVectorFile(t, "testdata/dhkem_p384_selfgen.json")
+}
+
+func testVectorFile(t *testing.T, file string) {
+    data, err := os.ReadFile(file)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    var vectors []testVector
+    if err = json.Unmarshal(data, &vectors); err != nil {
+        t.Fatal(err)
+    }
+
+    for _, v := range vectors {
+        name := fmt.Sprintf("mode %d kem %04x kdf %04x aead %04x", v.Mode, v.KEM, v.KDF, v.AEAD)
+
+        t.Run(name, func(t *testing.T) {
+            testVectorCase(t, v)
+        })
+    }
+}
+
+func testVectorCase(t *testing.T, v testVector) {
+    suite := NewSuite(v.KEM, v.KDF, v.AEAD)
+    if !suite.IsValid() {
+        t.Fatal("suite is not valid")
+    }
+
+    skR, err := suite.KEM.DeriveKeyPair(v.IkmR)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if !bytes.Equal(skR.Bytes(), v.SkRm) {
+        t.Errorf("skR got %x, want %x", skR.Bytes(), v.SkRm)
+    }
+    if !bytes.Equal(skR.PublicKey().Bytes(), v.PkRm) {
+        t.Errorf("pkR got %x, want %x", skR.PublicKey().Bytes(), v.PkRm)
+    }
+
+    skE, err := suite.KEM.DeriveKeyPair(v.IkmE)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if !bytes.Equal(skE.Bytes(), v.SkEm) {
+        t.Errorf("skE got %x, want %x", skE.Bytes(), v.SkEm)
+    }
+
+    pkR, err := suite.KEM.NewPublicKey(v.PkRm)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    var skS *ecdh.PrivateKey
+    var pkS *ecdh.PublicKey
+    if v.Mode == ModeAuth || v.Mode == ModeAuthPSK {
+        skS, err = suite.KEM.DeriveKeyPair(v.IkmS)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if !bytes.Equal(skS.Bytes(), v.SkSm) {
+            t.Errorf("skS got %x, want %x", skS.Bytes(), v.SkSm)
+        }
+
+        pkS, err = suite.KEM.NewPublicKey(v.PkSm)
+        if err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    // 使用 ikmE 作为随机数据, 生成确定的临时密钥
+    random := bytes.NewReader(v.IkmE)
+
+    var enc []byte
+    var sender *Sender
+    var receiver *Receiver
+
+    switch v.Mode {
+        case ModeBase:
+            enc, sender, err = suite.SetupBaseS(random, pkR, v.Info)
+            if err == nil {
+                receiver, err = suite.SetupBaseR(skR, enc, v.Info)
+            }
+        case ModePSK:
+            enc, sender, err = suite.SetupPSKS(random, pkR, v.Info, v.Psk, v.PskID)
+            if err == nil {
+                receiver, err = suite.SetupPSKR(skR, enc, v.Info, v.Psk, v.PskID)
+            }
+        case ModeAuth:
+            enc, sender, err = suite.SetupAuthS(random, pkR, v.Info, skS)
+            if err == nil {
+                receiver, err = suite.SetupAuthR(skR, enc, v.Info, pkS)
+            }
+        case ModeAuthPSK:
+            enc, sender, err = suite.SetupAuthPSKS(random, pkR, v.Info, v.Psk, v.PskID, skS)
+            if err == nil {
+                receiver, err = suite.SetupAuthPSKR(skR, enc, v.Info, v.Psk, v.PskID, pkS)
+            }
+    }
+
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if !bytes.Equal(enc, v.Enc) {
+        t.Errorf("enc got %x, want %x", enc, v.Enc)
+    }
+
+    for _, ctx := range []*context{sender.context, receiver.context} {
+        if !bytes.Equal(ctx.exporterSecret, v.ExporterSecret) {
+            t.Errorf("exporter_secret got %x, want %x", ctx.exporterSecret, v.ExporterSecret)
+        }
+
+        if v.AEAD != AEAD_ExportOnly {
+            if !bytes.Equal(ctx.key, v.Key) {
+                t.Errorf("key got %x, want %x", ctx.key, v.Key)
+            }
+            if !bytes.Equal(ctx.baseNonce, v.BaseNonce) {
+                t.Errorf("base_nonce got %x, want %x", ctx.baseNonce, v.BaseNonce)
+            }
+        }
+    }
+
+    if v.AEAD == AEAD_ExportOnly {
+        if _, err = sender.Seal(nil, nil); err != ErrExportOnly {
+            t.Errorf("Seal got %v, want ErrExportOnly", err)
+        }
+        if _, err = receiver.Open(nil, nil); err != ErrExportOnly {
+            t.Errorf("Open got %v, want ErrExportOnly", err)
+        }
+    }
+
+    for _, e := range v.Encryptions {
+        sender.seq = e.Seq
+        receiver.seq = e.Seq
+
+        if !bytes.Equal(sender.computeNonce(), e.Nonce) {
+            t.Errorf("[%d] nonce got %x, want %x", e.Seq, sender.computeNonce(), e.Nonce)
+        }
+
+        ct, err := sender.Seal(e.Aad, e.Pt)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if !bytes.Equal(ct, e.Ct) {
+            t.Errorf("[%d] ct got %x, want %x", e.Seq, ct, e.Ct)
+        }
+
+        pt, err := receiver.Open(e.Aad, e.Ct)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if !bytes.Equal(pt, e.Pt) {
+            t.Errorf("[%d] pt got %x, want %x", e.Seq, pt, e.Pt)
+        }
+    }
+
+    for _, e := range v.Exports {
+        for _, ctx := range []*context{sender.context, receiver.context} {
+            got, err := ctx.Export(e.Context, e.L)
+            if err != nil {
+                t.Fatal(err)
+            }
+
+            if !bytes.Equal(got, e.Value) {
+                t.Errorf("export got %x, want %x", got, e.Value)
+            }
+        }
+    }
+}
+
+func Test_SealOpen(t *testing.T) {
+    suites := []Suite{
+        NewSuite(KEM_P256_HKDF_SHA256, KDF_HKDF_SHA256, AEAD_AES128GCM),
+        NewSuite(KEM_P384_HKDF_SHA384, KDF_HKDF_SHA384, AEAD_AES256GCM),
+        NewSuite(KEM_P521_HKDF_SHA512, KDF_HKDF_SHA512, AEAD_ChaCha20Poly1305),
+        NewSuite(KEM_X25519_HKDF_SHA256, KDF_HKDF_SHA256, AEAD_ChaCha20Poly1305),
+        NewSuite(KEM_X448_HKDF_SHA512, KDF_HKDF_SHA512, AEAD_AES256GCM),
+    }
+
+    for _, suite := range suites {
+        t.Run(fmt.Sprintf("%04x-%04x-%04x", suite.KEM, suite.KDF, suite.AEAD), func(t *testing.T) {
+            testSealOpen(t, suite)
+        })
+    }
+}
+
+func testSealOpen(t *testing.T, suite Suite) {
+    info := []byte("test-info")
+    aad := []byte("test-aad")
+    msg := []byte("test-data")
+    psk := []byte("0123456789abcdef0123456789abcdef")
+    pskID := []byte("test-psk-id")
+
+    skR, err := suite.KEM.GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    skS, err := suite.KEM.GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    pkR := skR.PublicKey()
+    pkS := skS.PublicKey()
+
+    // single-shot
+    enc, ct, err := suite.Seal(rand.Reader, pkR, info, aad, msg)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    pt, err := suite.Open(skR, enc, info, aad, ct)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if !bytes.Equal(pt, msg) {
+        t.Errorf("Open got %x, want %x", pt, msg)
+    }
+
+    if _, err = suite.Open(skR, enc, []byte("bad-info"), aad, ct); err == nil {
+        t.Error("Open with bad info should fail")
+    }
+
+    // auth_psk
+    enc, sender, err := suite.SetupAuthPSKS(rand.Reader, pkR, info, psk, pskID, skS)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    receiver, err := suite.SetupAuthPSKR(skR, enc, info, psk, pskID, pkS)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    for i := 0; i < 3; i++ {
+        ct, err := sender.Seal(aad, msg)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        pt, err := receiver.Open(aad, ct)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if !bytes.Equal(pt, msg) {
+            t.Errorf("[%d] Open got %x, want %x", i, pt, msg)
+        }
+    }
+
+    exp1, err := sender.Export([]byte("ctx"), 32)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    exp2, err := receiver.Export([]byte("ctx"), 32)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if !bytes.Equal(exp1, exp2) {
+        t.Error("Export not equal")
+    }
+
+    // 错误的发送方公钥
+    receiver2, err := suite.SetupAuthPSKR(skR, enc, info, psk, pskID, pkR)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    ct, err = sender.Seal(aad, msg)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if _, err = receiver2.Open(aad, ct); err != ErrOpen {
+        t.Errorf("Open got %v, want ErrOpen", err)
+    }
+}
+
+func Test_SMSuite(t *testing.T) {
+    kem, kdf, aead := KEM(0xFF01), KDF(0xFF01), AEAD(0xFF01)
+
+    // 默认不启用
+    if kem.IsValid() || kdf.IsValid() || aead.IsValid() {
+        t.Fatal("sm suite should be disabled by default")
+    }
+
+    // 标识为 0 或者和已支持的注册标识重复
+    invalids := []Suite{
+        NewSuite(0, kdf, aead),
+        NewSuite(KEM_P256_HKDF_SHA256, kdf, aead),
+        NewSuite(kem, KDF_HKDF_SHA256, aead),
+        NewSuite(kem, kdf, AEAD_AES128GCM),
+        NewSuite(kem, kdf, AEAD_ExportOnly),
+    }
+
+    for i, v := range invalids {
+        if err := SetSMSuite(v.KEM, v.KDF, v.AEAD); err != ErrInvalidSMSuite {
+            t.Errorf("[%d] SetSMSuite got %v, want ErrInvalidSMSuite", i, err)
+        }
+    }
+
+    if err := SetSMSuite(kem, kdf, aead); err != nil {
+        t.Fatal(err)
+    }
+
+    suite := NewSuite(kem, kdf, aead)
+    if !suite.IsValid() {
+        t.Fatal("sm suite should be valid")
+    }
+
+    testSealOpen(t, suite)
+}
+
+func Test_Errors(t *testing.T) {
+    suite := NewSuite(KEM_X25519_HKDF_SHA256, KDF_HKDF_SHA256, AEAD_AES128GCM)
+
+    skR, err := suite.KEM.GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if _, _, err = suite.SetupPSKS(rand.Reader, skR.PublicKey(), nil, []byte("psk"), nil); err != ErrInvalidPSK {
+        t.Errorf("got %v, want ErrInvalidPSK", err)
+    }
+
+    if _, _, err = suite.SetupPSKS(rand.Reader, skR.PublicKey(), nil, nil, nil); err != ErrInvalidPSK {
+        t.Errorf("got %v, want ErrInvalidPSK", err)
+    }
+
+    // 曲线不匹配
+    p256Key, err := KEM_P256_HKDF_SHA256.GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if _, _, err = suite.SetupBaseS(rand.Reader, p256Key.PublicKey(), nil); err == nil {
+        t.Error("SetupBaseS with other curve key should fail")
+    }
+
+    if _, err = suite.SetupBaseR(skR, []byte("bad-enc"), nil); err == nil {
+        t.Error("SetupBaseR with bad enc should fail")
+    }
+
+    if _, _, err = NewSuite(0x9999, KDF_HKDF_SHA256, AEAD_AES128GCM).SetupBaseS(rand.Reader, skR.PublicKey(), nil); err != ErrInvalidSuite {
+        t.Errorf("got %v, want ErrInvalidSuite", err)
+    }
+}
@@ -0,0 +1,122 @@
+package hpke
+
+import (
+    "hash"
+    "errors"
+    "crypto/sha256"
+    "crypto/sha512"
+    "encoding/binary"
+
+    "golang.org/x/crypto/hkdf"
+
+    "github.com/deatil/go-cryptobin/hash/sm3"
+)
+
+// KDF 标识
+type KDF uint16
+
+const (
+    KDF_HKDF_SHA256 KDF = 0x0001
+    KDF_HKDF_SHA384 KDF = 0x0002
+    KDF_HKDF_SHA512 KDF = 0x0003
+)
+
+// HKDF-SM3 标识, 为 0 时不启用, 使用 SetSMSuite 设置
+var kdfSM3 KDF
+
+var errUnknownKDF = errors.New("hpke: unknown kdf")
+
+// 是否支持
+func (kdf KDF) IsValid() bool {
+    return kdf.isRegistered() || kdf.isSM3()
+}
+
+// 是否为已支持的 IANA 注册标识
+func (kdf KDF) isRegistered() bool {
+    switch kdf {
+        case KDF_HKDF_SHA256,
+            KDF_HKDF_SHA384,
+            KDF_HKDF_SHA512:
+            return true
+    }
+
+    return false
+}
+
+// 是否为 HKDF-SM3
+func (kdf KDF) isSM3() bool {
+    return kdfSM3 != 0 && kdf == kdfSM3
+}
+
+// 摘要方法
+func (kdf KDF) Hash() func() hash.Hash {
+    if kdf.isSM3() {
+        return sm3.New
+    }
+
+    switch kdf {
+        case KDF_HKDF_SHA256:
+            return sha256.New
+        case KDF_HKDF_SHA384:
+            return sha512.New384
+        case KDF_HKDF_SHA512:
+            return sha512.New
+    }
+
+    panic(errUnknownKDF)
+}
+
+// Nh, 摘要长度
+func (kdf KDF) ExtractSize() int {
+    return kdf.Hash()().Size()
+}
+
+// Extract(salt, ikm)
+func (kdf KDF) Extract(secret, salt []byte) []byte {
+    return hkdf.Extract(kdf.Hash(), secret, salt)
+}
+
+// Expand(prk, info, L)
+func (kdf KDF) Expand(prk, info []byte, length int) ([]byte, error) {
+    if length > 255*kdf.ExtractSize() {
+        return nil, errors.New("hpke: expand length too large")
+    }
+
+    out := make([]byte, length)
+
+    _, err := hkdf.Expand(kdf.Hash(), prk, info).Read(out)
+    if err != nil {
+        return nil, err
+    }
+
+    return out, nil
+}
+
+// LabeledExtract(salt, label, ikm)
+//     = Extract(salt, "HPKE-v1" || suite_id || label || ikm)
+func (kdf KDF) labeledExtract(suiteID []byte, salt []byte, label string, ikm []byte) []byte {
+    labeledIKM := make([]byte, 0, len(versionLabel)+len(suiteID)+len(label)+len(ikm))
+    labeledIKM = append(labeledIKM, versionLabel...)
+    labeledIKM = append(labeledIKM, suiteID...)
+    labeledIKM = append(labeledIKM, label...)
+    labeledIKM = append(labeledIKM, ikm...)
+
+    return kdf.Extract(labeledIKM, salt)
+}
+
+// LabeledExpand(prk, label, info, L)
+//     = Expand(prk, I2OSP(L, 2) || "HPKE-v1" || suite_id || label || info, L)
+func (kdf KDF) labeledExpand(suiteID []byte, prk []byte, label string, info []byte, length int) ([]byte, error) {
+    if length > 0xFFFF {
+        return nil, errors.New("hpke: expand length too large")
+    }
+
+    labeledInfo := make([]byte, 2, 2+len(versionLabel)+len(suiteID)+len(label)+len(info))
+    binary.BigEndian.PutUint16(labeledInfo, uint16(length))
+    labeledInfo = append(labeledInfo, versionLabel...)
+    labeledInfo = append(labeledInfo, suiteID...)
+    labeledInfo = append(labeledInfo, label...)
+    labeledInfo = append(labeledInfo, info...)
+
+    return kdf.Expand(prk, labeledInfo, length)
+}
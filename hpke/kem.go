@@ -0,0 +1,362 @@
+package hpke
+
+import (
+    "io"
+    "errors"
+    "math/big"
+    "crypto/rand"
+    "crypto/elliptic"
+    "encoding/binary"
+
+    "github.com/deatil/go-cryptobin/ecdh"
+    "github.com/deatil/go-cryptobin/gm/sm2"
+)
+
+// KEM 标识
+type KEM uint16
+
+const (
+    KEM_P256_HKDF_SHA256   KEM = 0x0010
+    KEM_P384_HKDF_SHA384   KEM = 0x0011
+    KEM_P521_HKDF_SHA512   KEM = 0x0012
+    KEM_X25519_HKDF_SHA256 KEM = 0x0020
+    KEM_X448_HKDF_SHA512   KEM = 0x0021
+)
+
+// SM2 KEM 标识, 为 0 时不启用, 使用 SetSMSuite 设置
+var kemSM2 KEM
+
+var (
+    errUnknownKEM      = errors.New("hpke: unknown kem")
+    errInvalidKEMKey   = errors.New("hpke: key is not for the kem curve")
+    errDeriveKeyPair   = errors.New("hpke: derive key pair fail")
+)
+
+// 是否支持
+func (kem KEM) IsValid() bool {
+    return kem.isRegistered() || kem.isSM2()
+}
+
+// 是否为已支持的 IANA 注册标识
+func (kem KEM) isRegistered() bool {
+    switch kem {
+        case KEM_P256_HKDF_SHA256,
+            KEM_P384_HKDF_SHA384,
+            KEM_P521_HKDF_SHA512,
+            KEM_X25519_HKDF_SHA256,
+            KEM_X448_HKDF_SHA512:
+            return true
+    }
+
+    return false
+}
+
+// 是否为 SM2 KEM
+func (kem KEM) isSM2() bool {
+    return kemSM2 != 0 && kem == kemSM2
+}
+
+// 使用的曲线
+func (kem KEM) Curve() ecdh.Curve {
+    if kem.isSM2() {
+        return ecdh.GmSM2()
+    }
+
+    switch kem {
+        case KEM_P256_HKDF_SHA256:
+            return ecdh.P256()
+        case KEM_P384_HKDF_SHA384:
+            return ecdh.P384()
+        case KEM_P521_HKDF_SHA512:
+            return ecdh.P521()
+        case KEM_X25519_HKDF_SHA256:
+            return ecdh.X25519()
+        case KEM_X448_HKDF_SHA512:
+            return ecdh.X448()
+    }
+
+    panic(errUnknownKEM)
+}
+
+// KEM 内部使用的 KDF
+func (kem KEM) kdf() KDF {
+    if kem.isSM2() {
+        return kdfSM3
+    }
+
+    switch kem {
+        case KEM_P256_HKDF_SHA256, KEM_X25519_HKDF_SHA256:
+            return KDF_HKDF_SHA256
+        case KEM_P384_HKDF_SHA384:
+            return KDF_HKDF_SHA384
+        case KEM_P521_HKDF_SHA512, KEM_X448_HKDF_SHA512:
+            return KDF_HKDF_SHA512
+    }
+
+    panic(errUnknownKEM)
+}
+
+// Nsk, 私钥长度
+func (kem KEM) PrivateKeySize() int {
+    if kem.isSM2() {
+        return 32
+    }
+
+    switch kem {
+        case KEM_P256_HKDF_SHA256, KEM_X25519_HKDF_SHA256:
+            return 32
+        case KEM_P384_HKDF_SHA384:
+            return 48
+        case KEM_P521_HKDF_SHA512:
+            return 66
+        case KEM_X448_HKDF_SHA512:
+            return 56
+    }
+
+    panic(errUnknownKEM)
+}
+
+// Npk, 公钥长度, 和 Nenc 相同
+func (kem KEM) PublicKeySize() int {
+    if kem.isSM2() {
+        return 65
+    }
+
+    switch kem {
+        case KEM_P256_HKDF_SHA256:
+            return 65
+        case KEM_P384_HKDF_SHA384:
+            return 97
+        case KEM_P521_HKDF_SHA512:
+            return 133
+        case KEM_X25519_HKDF_SHA256:
+            return 32
+        case KEM_X448_HKDF_SHA512:
+            return 56
+    }
+
+    panic(errUnknownKEM)
+}
+
+// Nsecret, 共享密钥长度
+func (kem KEM) SecretSize() int {
+    return kem.kdf().ExtractSize()
+}
+
+// 椭圆曲线参数, X25519 及 X448 返回 nil
+func (kem KEM) ellipticCurve() (elliptic.Curve, byte) {
+    if kem.isSM2() {
+        return sm2.P256(), 0xFF
+    }
+
+    switch kem {
+        case KEM_P256_HKDF_SHA256:
+            return elliptic.P256(), 0xFF
+        case KEM_P384_HKDF_SHA384:
+            return elliptic.P384(), 0xFF
+        case KEM_P521_HKDF_SHA512:
+            return elliptic.P521(), 0x01
+    }
+
+    return nil, 0
+}
+
+// suite_id = concat("KEM", I2OSP(kem_id, 2))
+func (kem KEM) suiteID() []byte {
+    id := make([]byte, 5)
+    copy(id, "KEM")
+    binary.BigEndian.PutUint16(id[3:], uint16(kem))
+
+    return id
+}
+
+// 生成私钥
+// GenerateKeyPair = DeriveKeyPair(random(Nsk))
+func (kem KEM) GenerateKey(random io.Reader) (*ecdh.PrivateKey, error) {
+    if random == nil {
+        random = rand.Reader
+    }
+
+    ikm := make([]byte, kem.PrivateKeySize())
+    if _, err := io.ReadFull(random, ikm); err != nil {
+        return nil, err
+    }
+
+    return kem.DeriveKeyPair(ikm)
+}
+
+// 根据 ikm 生成私钥
+// derive private key from ikm
+func (kem KEM) DeriveKeyPair(ikm []byte) (*ecdh.PrivateKey, error) {
+    if !kem.IsValid() {
+        return nil, errUnknownKEM
+    }
+
+    kdf := kem.kdf()
+    suiteID := kem.suiteID()
+    nsk := kem.PrivateKeySize()
+
+    dkpPRK := kdf.labeledExtract(suiteID, nil, "dkp_prk", ikm)
+
+    curve, bitmask := kem.ellipticCurve()
+    if curve == nil {
+        sk, err := kdf.labeledExpand(suiteID, dkpPRK, "sk", nil, nsk)
+        if err != nil {
+            return nil, err
+        }
+
+        return kem.Curve().NewPrivateKey(sk)
+    }
+
+    N := curve.Params().N
+
+    for counter := 0; counter < 256; counter++ {
+        sk, err := kdf.labeledExpand(suiteID, dkpPRK, "candidate", []byte{byte(counter)}, nsk)
+        if err != nil {
+            return nil, err
+        }
+
+        sk[0] &= bitmask
+
+        k := new(big.Int).SetBytes(sk)
+        if k.Sign() != 0 && k.Cmp(N) < 0 {
+            return kem.Curve().NewPrivateKey(sk)
+        }
+    }
+
+    return nil, errDeriveKeyPair
+}
+
+// 解析公钥
+// DeserializePublicKey
+func (kem KEM) NewPublicKey(pk []byte) (*ecdh.PublicKey, error) {
+    if len(pk) != kem.PublicKeySize() {
+        return nil, errors.New("hpke: invalid public key size")
+    }
+
+    return kem.Curve().NewPublicKey(pk)
+}
+
+// 解析私钥
+// DeserializePrivateKey
+func (kem KEM) NewPrivateKey(sk []byte) (*ecdh.PrivateKey, error) {
+    if len(sk) != kem.PrivateKeySize() {
+        return nil, errors.New("hpke: invalid private key size")
+    }
+
+    if curve, _ := kem.ellipticCurve(); curve != nil {
+        k := new(big.Int).SetBytes(sk)
+        if k.Sign() == 0 || k.Cmp(curve.Params().N) >= 0 {
+            return nil, errors.New("hpke: invalid private key")
+        }
+    }
+
+    return kem.Curve().NewPrivateKey(sk)
+}
+
+func (kem KEM) checkPublicKey(pub *ecdh.PublicKey) error {
+    if pub == nil || pub.Curve() != kem.Curve() {
+        return errInvalidKEMKey
+    }
+
+    return nil
+}
+
+func (kem KEM) checkPrivateKey(priv *ecdh.PrivateKey) error {
+    if priv == nil || priv.Curve() != kem.Curve() {
+        return errInvalidKEMKey
+    }
+
+    return nil
+}
+
+// ExtractAndExpand(dh, kem_context)
+func (kem KEM) extractAndExpand(dh, kemContext []byte) ([]byte, error) {
+    kdf := kem.kdf()
+    suiteID := kem.suiteID()
+
+    eaePRK := kdf.labeledExtract(suiteID, nil, "eae_prk", dh)
+
+    return kdf.labeledExpand(suiteID, eaePRK, "shared_secret", kemContext, kem.SecretSize())
+}
+
+// Encap(pkR) 及 AuthEncap(pkR, skS), skS 为 nil 时为 Encap
+func (kem KEM) encap(random io.Reader, pkR *ecdh.PublicKey, skS *ecdh.PrivateKey) (sharedSecret []byte, enc []byte, err error) {
+    if err = kem.checkPublicKey(pkR); err != nil {
+        return nil, nil, err
+    }
+
+    if skS != nil {
+        if err = kem.checkPrivateKey(skS); err != nil {
+            return nil, nil, err
+        }
+    }
+
+    skE, err := kem.GenerateKey(random)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    dh, err := skE.ECDH(pkR)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    enc = skE.PublicKey().Bytes()
+
+    kemContext := append(append([]byte{}, enc...), pkR.Bytes()...)
+
+    if skS != nil {
+        dhS, err := skS.ECDH(pkR)
+        if err != nil {
+            return nil, nil, err
+        }
+
+        dh = append(dh, dhS...)
+        kemContext = append(kemContext, skS.PublicKey().Bytes()...)
+    }
+
+    sharedSecret, err = kem.extractAndExpand(dh, kemContext)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    return sharedSecret, enc, nil
+}
+
+// Decap(enc, skR) 及 AuthDecap(enc, skR, pkS), pkS 为 nil 时为 Decap
+func (kem KEM) decap(enc []byte, skR *ecdh.PrivateKey, pkS *ecdh.PublicKey) ([]byte, error) {
+    if err := kem.checkPrivateKey(skR); err != nil {
+        return nil, err
+    }
+
+    if pkS != nil {
+        if err := kem.checkPublicKey(pkS); err != nil {
+            return nil, err
+        }
+    }
+
+    pkE, err := kem.NewPublicKey(enc)
+    if err != nil {
+        return nil, err
+    }
+
+    dh, err := skR.ECDH(pkE)
+    if err != nil {
+        return nil, err
+    }
+
+    kemContext := append(append([]byte{}, enc...), skR.PublicKey().Bytes()...)
+
+    if pkS != nil {
+        dhS, err := skR.ECDH(pkS)
+        if err != nil {
+            return nil, err
+        }
+
+        dh = append(dh, dhS...)
+        kemContext = append(kemContext, pkS.Bytes()...)
+    }
+
+    return kem.extractAndExpand(dh, kemContext)
+}
@@ -0,0 +1,152 @@
+package jose
+
+import (
+    "hash"
+    "errors"
+    "crypto/aes"
+    "crypto/hmac"
+    "crypto/cipher"
+    "crypto/subtle"
+    "crypto/sha256"
+    "crypto/sha512"
+    "encoding/binary"
+)
+
+// AES_CBC_HMAC_SHA2 (RFC 7518 5.2)
+type cbcHMAC struct {
+    block   cipher.Block
+    macKey  []byte
+    hash    func() hash.Hash
+    tagSize int
+}
+
+func newCBCHMAC(key []byte) (cipher.AEAD, error) {
+    var h func() hash.Hash
+
+    switch len(key) {
+        case 32:
+            h = sha256.New
+        case 48:
+            h = sha512.New384
+        case 64:
+            h = sha512.New
+        default:
+            return nil, errors.New("jose: invalid aes-cbc-hmac key size")
+    }
+
+    keySize := len(key) / 2
+
+    block, err := aes.NewCipher(key[keySize:])
+    if err != nil {
+        return nil, err
+    }
+
+    return &cbcHMAC{
+        block:   block,
+        macKey:  key[:keySize],
+        hash:    h,
+        tagSize: keySize,
+    }, nil
+}
+
+func (c *cbcHMAC) NonceSize() int {
+    return aes.BlockSize
+}
+
+func (c *cbcHMAC) Overhead() int {
+    return aes.BlockSize + c.tagSize
+}
+
+func (c *cbcHMAC) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+    padding := aes.BlockSize - len(plaintext) % aes.BlockSize
+
+    ciphertext := make([]byte, len(plaintext) + padding)
+    copy(ciphertext, plaintext)
+    for i := len(plaintext); i < len(ciphertext); i++ {
+        ciphertext[i] = byte(padding)
+    }
+
+    cipher.NewCBCEncrypter(c.block, nonce).CryptBlocks(ciphertext, ciphertext)
+
+    tag := c.computeTag(additionalData, nonce, ciphertext)
+
+    ret, out := sliceForAppend(dst, len(ciphertext) + len(tag))
+    copy(out, ciphertext)
+    copy(out[len(ciphertext):], tag)
+
+    return ret
+}
+
+func (c *cbcHMAC) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+    if len(ciphertext) < c.tagSize {
+        return nil, ErrDecrypt
+    }
+
+    tag := ciphertext[len(ciphertext) - c.tagSize:]
+    ciphertext = ciphertext[:len(ciphertext) - c.tagSize]
+
+    expectedTag := c.computeTag(additionalData, nonce, ciphertext)
+    if subtle.ConstantTimeCompare(tag, expectedTag) != 1 {
+        return nil, ErrDecrypt
+    }
+
+    if len(ciphertext) == 0 || len(ciphertext) % aes.BlockSize != 0 {
+        return nil, ErrDecrypt
+    }
+
+    plaintext := make([]byte, len(ciphertext))
+    cipher.NewCBCDecrypter(c.block, nonce).CryptBlocks(plaintext, ciphertext)
+
+    padding := int(plaintext[len(plaintext) - 1])
+    if padding == 0 || padding > aes.BlockSize {
+        return nil, ErrDecrypt
+    }
+
+    for _, b := range plaintext[len(plaintext) - padding:] {
+        if int(b) != padding {
+            return nil, ErrDecrypt
+        }
+    }
+
+    plaintext = plaintext[:len(plaintext) - padding]
+
+    ret, out := sliceForAppend(dst, len(plaintext))
+    copy(out, plaintext)
+
+    return ret, nil
+}
+
+// M = HMAC(MAC_KEY, A || IV || E || AL)
+func (c *cbcHMAC) computeTag(aad, iv, ciphertext []byte) []byte {
+    al := make([]byte, 8)
+    binary.BigEndian.PutUint64(al, uint64(len(aad)) * 8)
+
+    mac := hmac.New(c.hash, c.macKey)
+    mac.Write(aad)
+    mac.Write(iv)
+    mac.Write(ciphertext)
+    mac.Write(al)
+
+    return mac.Sum(nil)[:c.tagSize]
+}
+
+// 认证标签长度
+func aeadTagSize(aead cipher.AEAD) int {
+    if c, ok := aead.(*cbcHMAC); ok {
+        return c.tagSize
+    }
+
+    return aead.Overhead()
+}
+
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+    if total := len(in) + n; cap(in) >= total {
+        head = in[:total]
+    } else {
+        head = make([]byte, total)
+        copy(head, in)
+    }
+
+    tail = head[len(in):]
+    return
+}
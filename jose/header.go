@@ -0,0 +1,203 @@
+package jose
+
+import (
+    "encoding/json"
+)
+
+// 头信息中已经处理的字段
+var headerFields = []string{
+    "alg", "enc", "kid", "typ", "cty", "jwk", "crit",
+    "epk", "apu", "apv", "zip",
+}
+
+// JOSE 头信息
+type Header struct {
+    // 算法
+    Algorithm string
+
+    // 内容加密算法, 只用于 JWE
+    Encryption string
+
+    // 密钥 ID
+    KeyID string
+
+    // 类型
+    Type string
+
+    // 内容类型
+    ContentType string
+
+    // 公钥
+    JWK *JWK
+
+    // 必须理解的字段
+    Critical []string
+
+    // ECDH-ES 临时公钥
+    EphemeralKey *JWK
+
+    // ECDH-ES 双方信息
+    AgreementPartyUInfo []byte
+    AgreementPartyVInfo []byte
+
+    // 压缩方式
+    Zip string
+
+    // 其他字段
+    Extra map[string]any
+}
+
+type rawHeader struct {
+    Alg  string     `json:"alg,omitempty"`
+    Enc  string     `json:"enc,omitempty"`
+    Kid  string     `json:"kid,omitempty"`
+    Typ  string     `json:"typ,omitempty"`
+    Cty  string     `json:"cty,omitempty"`
+    JWK  *JWK       `json:"jwk,omitempty"`
+    Crit []string   `json:"crit,omitempty"`
+    Epk  *JWK       `json:"epk,omitempty"`
+    Apu  byteBuffer `json:"apu,omitempty"`
+    Apv  byteBuffer `json:"apv,omitempty"`
+    Zip  string     `json:"zip,omitempty"`
+}
+
+// 是否为空
+func (h Header) isEmpty() bool {
+    return h.Algorithm == "" &&
+        h.Encryption == "" &&
+        h.KeyID == "" &&
+        h.Type == "" &&
+        h.ContentType == "" &&
+        h.JWK == nil &&
+        len(h.Critical) == 0 &&
+        h.EphemeralKey == nil &&
+        len(h.AgreementPartyUInfo) == 0 &&
+        len(h.AgreementPartyVInfo) == 0 &&
+        h.Zip == "" &&
+        len(h.Extra) == 0
+}
+
+// 编码为 JSON
+func (h Header) MarshalJSON() ([]byte, error) {
+    raw := rawHeader{
+        Alg:  h.Algorithm,
+        Enc:  h.Encryption,
+        Kid:  h.KeyID,
+        Typ:  h.Type,
+        Cty:  h.ContentType,
+        JWK:  h.JWK,
+        Crit: h.Critical,
+        Epk:  h.EphemeralKey,
+        Apu:  h.AgreementPartyUInfo,
+        Apv:  h.AgreementPartyVInfo,
+        Zip:  h.Zip,
+    }
+
+    if len(h.Extra) == 0 {
+        return json.Marshal(raw)
+    }
+
+    data, err := json.Marshal(raw)
+    if err != nil {
+        return nil, err
+    }
+
+    fields := make(map[string]any)
+    for k, v := range h.Extra {
+        fields[k] = v
+    }
+
+    var known map[string]json.RawMessage
+    if err = json.Unmarshal(data, &known); err != nil {
+        return nil, err
+    }
+
+    for k, v := range known {
+        fields[k] = v
+    }
+
+    return json.Marshal(fields)
+}
+
+// 从 JSON 解析
+func (h *Header) UnmarshalJSON(data []byte) error {
+    var raw rawHeader
+    if err := json.Unmarshal(data, &raw); err != nil {
+        return err
+    }
+
+    var extra map[string]any
+    if err := json.Unmarshal(data, &extra); err != nil {
+        return err
+    }
+
+    for _, name := range headerFields {
+        delete(extra, name)
+    }
+
+    if len(extra) == 0 {
+        extra = nil
+    }
+
+    *h = Header{
+        Algorithm:           raw.Alg,
+        Encryption:          raw.Enc,
+        KeyID:               raw.Kid,
+        Type:                raw.Typ,
+        ContentType:         raw.Cty,
+        JWK:                 raw.JWK,
+        Critical:            raw.Crit,
+        EphemeralKey:        raw.Epk,
+        AgreementPartyUInfo: raw.Apu,
+        AgreementPartyVInfo: raw.Apv,
+        Zip:                 raw.Zip,
+        Extra:               extra,
+    }
+
+    return nil
+}
+
+// 合并头信息, h 中的字段优先
+func (h Header) merge(other Header) Header {
+    if h.Algorithm == "" {
+        h.Algorithm = other.Algorithm
+    }
+    if h.Encryption == "" {
+        h.Encryption = other.Encryption
+    }
+    if h.KeyID == "" {
+        h.KeyID = other.KeyID
+    }
+    if h.Type == "" {
+        h.Type = other.Type
+    }
+    if h.ContentType == "" {
+        h.ContentType = other.ContentType
+    }
+    if h.JWK == nil {
+        h.JWK = other.JWK
+    }
+    if h.EphemeralKey == nil {
+        h.EphemeralKey = other.EphemeralKey
+    }
+    if h.AgreementPartyUInfo == nil {
+        h.AgreementPartyUInfo = other.AgreementPartyUInfo
+    }
+    if h.AgreementPartyVInfo == nil {
+        h.AgreementPartyVInfo = other.AgreementPartyVInfo
+    }
+    if h.Zip == "" {
+        h.Zip = other.Zip
+    }
+
+    return h
+}
+
+// 检测 crit 字段, 当前不支持任何扩展字段
+func (h Header) checkCritical() error {
+    if h.Critical != nil {
+        return ErrCritical
+    }
+
+    return nil
+}
@@ -0,0 +1,57 @@
+package jose
+
+import (
+    "errors"
+    "strings"
+    "math/big"
+    "encoding/base64"
+)
+
+var (
+    ErrUnsupportedKeyType   = errors.New("jose: unsupported key type")
+    ErrUnsupportedAlgorithm = errors.New("jose: unsupported algorithm")
+    ErrUnsupportedCurve     = errors.New("jose: unsupported curve")
+    ErrInvalidKey           = errors.New("jose: invalid key")
+    ErrInvalidKeyType       = errors.New("jose: key type is not match the algorithm")
+    ErrMalformed            = errors.New("jose: malformed data")
+    ErrCritical             = errors.New("jose: unsupported critical header")
+    ErrVerify               = errors.New("jose: verify signature fail")
+    ErrDecrypt              = errors.New("jose: decrypt fail")
+)
+
+// base64url 编码, 不带填充
+func encodeSegment(data []byte) string {
+    return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// base64url 解码, 兼容带填充的数据
+func decodeSegment(data string) ([]byte, error) {
+    return base64.RawURLEncoding.DecodeString(strings.TrimRight(data, "="))
+}
+
+// 大数转为固定长度字节
+func fillBytes(n *big.Int, size int) []byte {
+    buf := make([]byte, size)
+    return n.FillBytes(buf)
+}
+
+// base64url 编码的字节数据
+type byteBuffer []byte
+
+func (b byteBuffer) MarshalText() ([]byte, error) {
+    return []byte(encodeSegment(b)), nil
+}
+
+func (b *byteBuffer) UnmarshalText(data []byte) error {
+    decoded, err := decodeSegment(string(data))
+    if err != nil {
+        return ErrMalformed
+    }
+
+    *b = decoded
+    return nil
+}
+
+func (b byteBuffer) bigInt() *big.Int {
+    return new(big.Int).SetBytes(b)
+}
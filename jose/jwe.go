@@ -0,0 +1,204 @@
+package jose
+
+import (
+    "strings"
+    "encoding/json"
+)
+
+/**
+ * JWE (RFC 7516)
+ *
+ * 支持 compact 格式
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type JWE struct {
+    // 受保护的头信息
+    Header Header
+
+    // 加密后的 CEK
+    EncryptedKey []byte
+
+    // 初始向量
+    IV []byte
+
+    // 密文
+    Ciphertext []byte
+
+    // 认证标签
+    Tag []byte
+
+    // 原始的受保护头信息
+    protected string
+}
+
+// 加密, key 可为 *JWK 或者具体密钥
+// header 为额外的受保护头信息, 可为 nil
+func Encrypt(plaintext []byte, alg KeyAlgorithm, enc ContentEncryption, key any, header *Header) (*JWE, error) {
+    key, kid := unwrapJWK(key)
+
+    var protected Header
+    if header != nil {
+        protected = *header
+    }
+
+    if protected.Zip != "" {
+        return nil, ErrUnsupportedAlgorithm
+    }
+
+    protected.Algorithm = string(alg)
+    protected.Encryption = string(enc)
+    if protected.KeyID == "" {
+        protected.KeyID = kid
+    }
+
+    cek, encryptedKey, err := alg.encryptKey(key, enc, &protected)
+    if err != nil {
+        return nil, err
+    }
+
+    aead, err := enc.aead(cek)
+    if err != nil {
+        return nil, err
+    }
+
+    iv, err := randomBytes(aead.NonceSize())
+    if err != nil {
+        return nil, err
+    }
+
+    headerData, err := json.Marshal(protected)
+    if err != nil {
+        return nil, err
+    }
+
+    encodedHeader := encodeSegment(headerData)
+
+    sealed := aead.Seal(nil, iv, plaintext, []byte(encodedHeader))
+
+    tagSize := aeadTagSize(aead)
+
+    return &JWE{
+        Header:       protected,
+        EncryptedKey: encryptedKey,
+        IV:           iv,
+        Ciphertext:   sealed[:len(sealed) - tagSize],
+        Tag:          sealed[len(sealed) - tagSize:],
+        protected:    encodedHeader,
+    }, nil
+}
+
+// 解密, key 可为 *JWK, *JWKSet 或者具体密钥
+func (jwe *JWE) Decrypt(key any) ([]byte, error) {
+    header := jwe.Header
+
+    if err := header.checkCritical(); err != nil {
+        return nil, err
+    }
+
+    if header.Zip != "" {
+        return nil, ErrUnsupportedAlgorithm
+    }
+
+    alg := KeyAlgorithm(header.Algorithm)
+    enc := ContentEncryption(header.Encryption)
+
+    keys := selectKeys(key, header.KeyID)
+    if len(keys) == 0 {
+        return nil, ErrInvalidKey
+    }
+
+    for _, k := range keys {
+        cek, err := alg.decryptKey(k, enc, header, jwe.EncryptedKey)
+        if err != nil {
+            continue
+        }
+
+        aead, err := enc.aead(cek)
+        if err != nil {
+            continue
+        }
+
+        if len(jwe.IV) != aead.NonceSize() {
+            return nil, ErrMalformed
+        }
+
+        sealed := make([]byte, 0, len(jwe.Ciphertext) + len(jwe.Tag))
+        sealed = append(sealed, jwe.Ciphertext...)
+        sealed = append(sealed, jwe.Tag...)
+
+        plaintext, err := aead.Open(nil, jwe.IV, sealed, []byte(jwe.protected))
+        if err == nil {
+            return plaintext, nil
+        }
+    }
+
+    return nil, ErrDecrypt
+}
+
+// compact 格式
+func (jwe *JWE) CompactSerialize() string {
+    return strings.Join([]string{
+        jwe.protected,
+        encodeSegment(jwe.EncryptedKey),
+        encodeSegment(jwe.IV),
+        encodeSegment(jwe.Ciphertext),
+        encodeSegment(jwe.Tag),
+    }, ".")
+}
+
+// 解析 compact 格式的 JWE
+func ParseJWE(data string) (*JWE, error) {
+    parts := strings.Split(strings.TrimSpace(data), ".")
+    if len(parts) != 5 {
+        return nil, ErrMalformed
+    }
+
+    headerData, err := decodeSegment(parts[0])
+    if err != nil {
+        return nil, ErrMalformed
+    }
+
+    var header Header
+    if err = json.Unmarshal(headerData, &header); err != nil {
+        return nil, err
+    }
+
+    decoded := make([][]byte, 4)
+    for i := range decoded {
+        decoded[i], err = decodeSegment(parts[i+1])
+        if err != nil {
+            return nil, ErrMalformed
+        }
+    }
+
+    return &JWE{
+        Header:       header,
+        EncryptedKey: decoded[0],
+        IV:           decoded[1],
+        Ciphertext:   decoded[2],
+        Tag:          decoded[3],
+        protected:    parts[0],
+    }, nil
+}
+
+// 加密为 compact 格式
+func EncryptCompact(plaintext []byte, alg KeyAlgorithm, enc ContentEncryption, key any, header *Header) (string, error) {
+    jwe, err := Encrypt(plaintext, alg, enc, key, header)
+    if err != nil {
+        return "", err
+    }
+
+    return jwe.CompactSerialize(), nil
+}
+
+// 解密 compact 格式的 JWE
+func Decrypt(data string, key any) ([]byte, error) {
+    jwe, err := ParseJWE(data)
+    if err != nil {
+        return nil, err
+    }
+
+    return jwe.Decrypt(key)
+}
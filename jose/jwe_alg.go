@@ -0,0 +1,490 @@
+package jose
+
+import (
+    "io"
+    "hash"
+    "errors"
+    "math/big"
+    "crypto/aes"
+    "crypto/rsa"
+    "crypto/rand"
+    "crypto/ecdsa"
+    "crypto/cipher"
+    "crypto/sha1"
+    "crypto/sha256"
+    "crypto/elliptic"
+    "encoding/binary"
+    crypto_ecdh "crypto/ecdh"
+
+    "github.com/deatil/go-cryptobin/ecdh"
+    "github.com/deatil/go-cryptobin/gm/sm2"
+    "github.com/deatil/go-cryptobin/cipher/keywrap"
+)
+
+// 密钥管理算法
+type KeyAlgorithm string
+
+const (
+    RSA_OAEP       KeyAlgorithm = "RSA-OAEP"
+    RSA_OAEP_256   KeyAlgorithm = "RSA-OAEP-256"
+    ECDH_ES        KeyAlgorithm = "ECDH-ES"
+    ECDH_ES_A128KW KeyAlgorithm = "ECDH-ES+A128KW"
+    ECDH_ES_A192KW KeyAlgorithm = "ECDH-ES+A192KW"
+    ECDH_ES_A256KW KeyAlgorithm = "ECDH-ES+A256KW"
+    A128KW         KeyAlgorithm = "A128KW"
+    A192KW         KeyAlgorithm = "A192KW"
+    A256KW         KeyAlgorithm = "A256KW"
+    DIRECT         KeyAlgorithm = "dir"
+)
+
+// 内容加密算法
+type ContentEncryption string
+
+const (
+    A128GCM       ContentEncryption = "A128GCM"
+    A192GCM       ContentEncryption = "A192GCM"
+    A256GCM       ContentEncryption = "A256GCM"
+    A128CBC_HS256 ContentEncryption = "A128CBC-HS256"
+    A192CBC_HS384 ContentEncryption = "A192CBC-HS384"
+    A256CBC_HS512 ContentEncryption = "A256CBC-HS512"
+)
+
+// CEK 长度
+func (enc ContentEncryption) keySize() int {
+    switch enc {
+        case A128GCM:
+            return 16
+        case A192GCM:
+            return 24
+        case A256GCM, A128CBC_HS256:
+            return 32
+        case A192CBC_HS384:
+            return 48
+        case A256CBC_HS512:
+            return 64
+    }
+
+    return 0
+}
+
+// 生成 AEAD
+func (enc ContentEncryption) aead(cek []byte) (cipher.AEAD, error) {
+    if len(cek) != enc.keySize() {
+        return nil, ErrInvalidKey
+    }
+
+    switch enc {
+        case A128GCM, A192GCM, A256GCM:
+            block, err := aes.NewCipher(cek)
+            if err != nil {
+                return nil, err
+            }
+
+            return cipher.NewGCM(block)
+        case A128CBC_HS256, A192CBC_HS384, A256CBC_HS512:
+            return newCBCHMAC(cek)
+    }
+
+    return nil, ErrUnsupportedAlgorithm
+}
+
+// 包装密钥的长度
+func (alg KeyAlgorithm) kwKeySize() int {
+    switch alg {
+        case A128KW, ECDH_ES_A128KW:
+            return 16
+        case A192KW, ECDH_ES_A192KW:
+            return 24
+        case A256KW, ECDH_ES_A256KW:
+            return 32
+    }
+
+    return 0
+}
+
+// 生成 CEK 并使用接收方密钥加密
+func (alg KeyAlgorithm) encryptKey(key any, enc ContentEncryption, header *Header) (cek, encryptedKey []byte, err error) {
+    size := enc.keySize()
+    if size == 0 {
+        return nil, nil, ErrUnsupportedAlgorithm
+    }
+
+    switch alg {
+        case DIRECT:
+            k, ok := key.([]byte)
+            if !ok {
+                return nil, nil, ErrInvalidKeyType
+            }
+
+            if len(k) != size {
+                return nil, nil, ErrInvalidKey
+            }
+
+            return k, nil, nil
+
+        case ECDH_ES:
+            cek, err = ecdhESEncrypt(key, string(enc), size, header)
+            if err != nil {
+                return nil, nil, err
+            }
+
+            return cek, nil, nil
+    }
+
+    cek, err = randomBytes(size)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    switch alg {
+        case RSA_OAEP, RSA_OAEP_256:
+            k, ok := key.(*rsa.PublicKey)
+            if !ok {
+                return nil, nil, ErrInvalidKeyType
+            }
+
+            encryptedKey, err = rsa.EncryptOAEP(alg.oaepHash(), rand.Reader, k, cek, nil)
+            if err != nil {
+                return nil, nil, err
+            }
+
+            return cek, encryptedKey, nil
+
+        case A128KW, A192KW, A256KW:
+            k, ok := key.([]byte)
+            if !ok {
+                return nil, nil, ErrInvalidKeyType
+            }
+
+            encryptedKey, err = aesKeyWrap(k, alg.kwKeySize(), cek)
+            if err != nil {
+                return nil, nil, err
+            }
+
+            return cek, encryptedKey, nil
+
+        case ECDH_ES_A128KW, ECDH_ES_A192KW, ECDH_ES_A256KW:
+            kek, err := ecdhESEncrypt(key, string(alg), alg.kwKeySize(), header)
+            if err != nil {
+                return nil, nil, err
+            }
+
+            encryptedKey, err = aesKeyWrap(kek, alg.kwKeySize(), cek)
+            if err != nil {
+                return nil, nil, err
+            }
+
+            return cek, encryptedKey, nil
+    }
+
+    return nil, nil, ErrUnsupportedAlgorithm
+}
+
+// 解密 CEK
+func (alg KeyAlgorithm) decryptKey(key any, enc ContentEncryption, header Header, encryptedKey []byte) ([]byte, error) {
+    size := enc.keySize()
+    if size == 0 {
+        return nil, ErrUnsupportedAlgorithm
+    }
+
+    switch alg {
+        case DIRECT:
+            k, ok := key.([]byte)
+            if !ok {
+                return nil, ErrInvalidKeyType
+            }
+
+            if len(encryptedKey) != 0 || len(k) != size {
+                return nil, ErrInvalidKey
+            }
+
+            return k, nil
+
+        case ECDH_ES:
+            if len(encryptedKey) != 0 {
+                return nil, ErrMalformed
+            }
+
+            return ecdhESDecrypt(key, string(enc), size, header)
+
+        case RSA_OAEP, RSA_OAEP_256:
+            k, ok := key.(*rsa.PrivateKey)
+            if !ok {
+                return nil, ErrInvalidKeyType
+            }
+
+            cek, err := rsa.DecryptOAEP(alg.oaepHash(), nil, k, encryptedKey, nil)
+            if err != nil {
+                return nil, ErrDecrypt
+            }
+
+            return cek, nil
+
+        case A128KW, A192KW, A256KW:
+            k, ok := key.([]byte)
+            if !ok {
+                return nil, ErrInvalidKeyType
+            }
+
+            return aesKeyUnwrap(k, alg.kwKeySize(), encryptedKey)
+
+        case ECDH_ES_A128KW, ECDH_ES_A192KW, ECDH_ES_A256KW:
+            kek, err := ecdhESDecrypt(key, string(alg), alg.kwKeySize(), header)
+            if err != nil {
+                return nil, err
+            }
+
+            return aesKeyUnwrap(kek, alg.kwKeySize(), encryptedKey)
+    }
+
+    return nil, ErrUnsupportedAlgorithm
+}
+
+func (alg KeyAlgorithm) oaepHash() hash.Hash {
+    if alg == RSA_OAEP_256 {
+        return sha256.New()
+    }
+
+    return sha1.New()
+}
+
+func aesKeyWrap(kek []byte, size int, cek []byte) ([]byte, error) {
+    if len(kek) != size {
+        return nil, ErrInvalidKey
+    }
+
+    block, err := aes.NewCipher(kek)
+    if err != nil {
+        return nil, err
+    }
+
+    return keywrap.Wrap(block, nil, cek)
+}
+
+func aesKeyUnwrap(kek []byte, size int, encryptedKey []byte) ([]byte, error) {
+    if len(kek) != size {
+        return nil, ErrInvalidKey
+    }
+
+    block, err := aes.NewCipher(kek)
+    if err != nil {
+        return nil, err
+    }
+
+    cek, err := keywrap.Unwrap(block, nil, encryptedKey)
+    if err != nil {
+        return nil, ErrDecrypt
+    }
+
+    return cek, nil
+}
+
+func randomBytes(size int) ([]byte, error) {
+    buf := make([]byte, size)
+    if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+        return nil, err
+    }
+
+    return buf, nil
+}
+
+// ==========
+
+// ECDH-ES 发送方, 生成临时密钥并写入头信息
+func ecdhESEncrypt(key any, algID string, size int, header *Header) ([]byte, error) {
+    pub, err := normalizeAgreementPublicKey(key)
+    if err != nil {
+        return nil, err
+    }
+
+    var z []byte
+
+    switch pub := pub.(type) {
+        case *ecdsa.PublicKey:
+            eph, err := ecdsa.GenerateKey(pub.Curve, rand.Reader)
+            if err != nil {
+                return nil, err
+            }
+
+            z, err = ecAgreement(eph, pub)
+            if err != nil {
+                return nil, err
+            }
+
+            if pub.Curve == sm2.P256() {
+                header.EphemeralKey = NewJWK(&sm2.PublicKey{
+                    Curve: eph.Curve,
+                    X:     eph.X,
+                    Y:     eph.Y,
+                })
+            } else {
+                header.EphemeralKey = NewJWK(&eph.PublicKey)
+            }
+
+        case *ecdh.PublicKey:
+            eph, err := pub.Curve().GenerateKey(rand.Reader)
+            if err != nil {
+                return nil, err
+            }
+
+            z, err = eph.ECDH(pub)
+            if err != nil {
+                return nil, err
+            }
+
+            header.EphemeralKey = NewJWK(eph.PublicKey())
+    }
+
+    return concatKDF(z, algID, header.AgreementPartyUInfo, header.AgreementPartyVInfo, size), nil
+}
+
+// ECDH-ES 接收方
+func ecdhESDecrypt(key any, algID string, size int, header Header) ([]byte, error) {
+    if header.EphemeralKey == nil {
+        return nil, ErrMalformed
+    }
+
+    priv, err := normalizeAgreementPrivateKey(key)
+    if err != nil {
+        return nil, err
+    }
+
+    epk, err := normalizeAgreementPublicKey(header.EphemeralKey.Key)
+    if err != nil {
+        return nil, err
+    }
+
+    var z []byte
+
+    switch priv := priv.(type) {
+        case *ecdsa.PrivateKey:
+            pub, ok := epk.(*ecdsa.PublicKey)
+            if !ok || pub.Curve != priv.Curve {
+                return nil, ErrInvalidKey
+            }
+
+            z, err = ecAgreement(priv, pub)
+        case *ecdh.PrivateKey:
+            pub, ok := epk.(*ecdh.PublicKey)
+            if !ok || pub.Curve() != priv.Curve() {
+                return nil, ErrInvalidKey
+            }
+
+            z, err = priv.ECDH(pub)
+    }
+
+    if err != nil {
+        return nil, err
+    }
+
+    return concatKDF(z, algID, header.AgreementPartyUInfo, header.AgreementPartyVInfo, size), nil
+}
+
+// EC 公钥转为 *ecdsa.PublicKey, X25519 及 X448 公钥转为 *ecdh.PublicKey
+func normalizeAgreementPublicKey(key any) (any, error) {
+    switch k := key.(type) {
+        case *ecdsa.PublicKey:
+            return k, nil
+        case *sm2.PublicKey:
+            return &ecdsa.PublicKey{Curve: k.Curve, X: k.X, Y: k.Y}, nil
+        case *crypto_ecdh.PublicKey:
+            pub, err := ecdh.FromPublicKey(k)
+            if err != nil {
+                return nil, err
+            }
+
+            return normalizeAgreementPublicKey(pub)
+        case *ecdh.PublicKey:
+            curve := ecdhEllipticCurve(k.Curve())
+            if curve == nil {
+                return k, nil
+            }
+
+            x, y := elliptic.Unmarshal(curve, k.Bytes())
+            if x == nil {
+                return nil, ErrInvalidKey
+            }
+
+            return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+    }
+
+    return nil, ErrInvalidKeyType
+}
+
+// EC 私钥转为 *ecdsa.PrivateKey, X25519 及 X448 私钥转为 *ecdh.PrivateKey
+func normalizeAgreementPrivateKey(key any) (any, error) {
+    switch k := key.(type) {
+        case *ecdsa.PrivateKey:
+            return k, nil
+        case *sm2.PrivateKey:
+            return &ecdsa.PrivateKey{
+                PublicKey: ecdsa.PublicKey{Curve: k.Curve, X: k.X, Y: k.Y},
+                D:         k.D,
+            }, nil
+        case *crypto_ecdh.PrivateKey:
+            priv, err := ecdh.FromPrivateKey(k)
+            if err != nil {
+                return nil, err
+            }
+
+            return normalizeAgreementPrivateKey(priv)
+        case *ecdh.PrivateKey:
+            curve := ecdhEllipticCurve(k.Curve())
+            if curve == nil {
+                return k, nil
+            }
+
+            pub, err := normalizeAgreementPublicKey(k.PublicKey())
+            if err != nil {
+                return nil, err
+            }
+
+            return &ecdsa.PrivateKey{
+                PublicKey: *pub.(*ecdsa.PublicKey),
+                D:         new(big.Int).SetBytes(k.Bytes()),
+            }, nil
+    }
+
+    return nil, ErrInvalidKeyType
+}
+
+// 椭圆曲线 DH, 返回 x 坐标
+func ecAgreement(priv *ecdsa.PrivateKey, pub *ecdsa.PublicKey) ([]byte, error) {
+    curve := priv.Curve
+    if !curve.IsOnCurve(pub.X, pub.Y) {
+        return nil, ErrInvalidKey
+    }
+
+    x, y := curve.ScalarMult(pub.X, pub.Y, priv.D.Bytes())
+    if x.Sign() == 0 && y.Sign() == 0 {
+        return nil, errors.New("jose: ecdh result is the point at infinity")
+    }
+
+    return fillBytes(x, curveSize(curve)), nil
+}
+
+// Concat KDF (NIST SP 800-56A), 使用 SHA-256
+func concatKDF(z []byte, algID string, apu, apv []byte, size int) []byte {
+    otherInfo := lengthPrefixed([]byte(algID))
+    otherInfo = append(otherInfo, lengthPrefixed(apu)...)
+    otherInfo = append(otherInfo, lengthPrefixed(apv)...)
+    otherInfo = binary.BigEndian.AppendUint32(otherInfo, uint32(size) * 8)
+
+    out := make([]byte, 0, size + sha256.Size)
+
+    for counter := uint32(1); len(out) < size; counter++ {
+        h := sha256.New()
+        binary.Write(h, binary.BigEndian, counter)
+        h.Write(z)
+        h.Write(otherInfo)
+
+        out = h.Sum(out)
+    }
+
+    return out[:size]
+}
+
+func lengthPrefixed(data []byte) []byte {
+    out := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+    return append(out, data...)
+}
@@ -0,0 +1,229 @@
+package jose
+
+import (
+    "bytes"
+    "testing"
+    "math/big"
+    "crypto/rsa"
+    "crypto/rand"
+    "crypto/ecdsa"
+    "crypto/elliptic"
+    "encoding/base64"
+
+    "github.com/deatil/go-cryptobin/ecdh"
+    "github.com/deatil/go-cryptobin/gm/sm2"
+    "github.com/deatil/go-cryptobin/elliptic/secp256k1"
+    "github.com/deatil/go-cryptobin/elliptic/brainpool"
+)
+
+func fromBase64Int(data string) *big.Int {
+    val, err := base64.RawURLEncoding.DecodeString(data)
+    if err != nil {
+        panic(err)
+    }
+
+    return new(big.Int).SetBytes(val)
+}
+
+// RFC 7518 Appendix C
+func Test_ECDHESVector(t *testing.T) {
+    bobKey := &ecdsa.PrivateKey{
+        PublicKey: ecdsa.PublicKey{
+            Curve: elliptic.P256(),
+            X:     fromBase64Int("weNJy2HscCSM6AEDTDg04biOvhFhyyWvOHQfeF_PxMQ"),
+            Y:     fromBase64Int("e8lnCO-AlStT-NJVX-crhB7QRYhiix03illJOVAOyck"),
+        },
+        D: fromBase64Int("VEmDZpDXXK8p8N0Cndsxs924q6nS1RXFASRl6BfUqdw"),
+    }
+
+    epk := &ecdsa.PublicKey{
+        Curve: elliptic.P256(),
+        X:     fromBase64Int("gI0GAILBdu7T53akrFmMyGcsF3n5dO7MmwNBHKW5SV0"),
+        Y:     fromBase64Int("SLW_xSffzlPWrHEVI30DHM_4egVwt3NQqeUD7nMFpps"),
+    }
+
+    header := Header{
+        EphemeralKey:        NewJWK(epk),
+        AgreementPartyUInfo: []byte("Alice"),
+        AgreementPartyVInfo: []byte("Bob"),
+    }
+
+    key, err := ecdhESDecrypt(bobKey, "A128GCM", 16, header)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if encodeSegment(key) != "VqqN6vgjbSBcIijNcacQGg" {
+        t.Errorf("derived key got %s", encodeSegment(key))
+    }
+}
+
+// RFC 7516 Appendix B
+func Test_CBCHMACVector(t *testing.T) {
+    key := []byte{
+        4, 211, 31, 197, 84, 157, 252, 254, 11, 100, 157, 250, 63, 170, 106, 206,
+        107, 124, 212, 45, 111, 107, 9, 219, 200, 177, 0, 240, 143, 156, 44, 207,
+    }
+    iv := []byte{3, 22, 60, 12, 43, 67, 104, 105, 108, 108, 105, 99, 111, 116, 104, 101}
+    plaintext := []byte("Live long and prosper.")
+    aad := []byte("eyJhbGciOiJSU0ExXzUiLCJlbmMiOiJBMTI4Q0JDLUhTMjU2In0")
+
+    wantCiphertext := []byte{
+        40, 57, 83, 181, 119, 33, 133, 148, 198, 185, 243, 24, 152, 230, 6,
+        75, 129, 223, 127, 19, 210, 82, 183, 230, 168, 33, 215, 104, 143,
+        112, 56, 102,
+    }
+    wantTag := []byte{246, 17, 244, 190, 4, 95, 98, 3, 231, 0, 115, 157, 242, 203, 100, 191}
+
+    aead, err := A128CBC_HS256.aead(key)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    sealed := aead.Seal(nil, iv, plaintext, aad)
+
+    if !bytes.Equal(sealed[:len(sealed)-16], wantCiphertext) {
+        t.Errorf("ciphertext got %v", sealed[:len(sealed)-16])
+    }
+
+    if !bytes.Equal(sealed[len(sealed)-16:], wantTag) {
+        t.Errorf("tag got %v", sealed[len(sealed)-16:])
+    }
+
+    opened, err := aead.Open(nil, iv, sealed, aad)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if !bytes.Equal(opened, plaintext) {
+        t.Errorf("plaintext got %s", opened)
+    }
+
+    sealed[0] ^= 1
+    if _, err = aead.Open(nil, iv, sealed, aad); err == nil {
+        t.Error("Open modified data should fail")
+    }
+}
+
+func Test_JWEEncryptAndDecrypt(t *testing.T) {
+    rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+    p256Key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    p521Key, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+    k256Key, _ := ecdsa.GenerateKey(secp256k1.Curve(), rand.Reader)
+    bp256Key, _ := ecdsa.GenerateKey(brainpool.P256r1(), rand.Reader)
+    sm2Key, _ := sm2.GenerateKey(rand.Reader)
+    x25519Key, _ := ecdh.X25519().GenerateKey(rand.Reader)
+    x448Key, _ := ecdh.X448().GenerateKey(rand.Reader)
+    ecdhP384Key, _ := ecdh.P384().GenerateKey(rand.Reader)
+
+    encs := []ContentEncryption{
+        A128GCM, A192GCM, A256GCM,
+        A128CBC_HS256, A192CBC_HS384, A256CBC_HS512,
+    }
+
+    type recipient struct {
+        alg  KeyAlgorithm
+        priv any
+        pub  any
+    }
+
+    recipients := func(enc ContentEncryption) []recipient {
+        direct := make([]byte, enc.keySize())
+        rand.Read(direct)
+
+        return []recipient{
+            {RSA_OAEP, rsaKey, &rsaKey.PublicKey},
+            {RSA_OAEP_256, rsaKey, &rsaKey.PublicKey},
+            {A128KW, []byte("0123456789abcdef"), []byte("0123456789abcdef")},
+            {A192KW, []byte("0123456789abcdef01234567"), []byte("0123456789abcdef01234567")},
+            {A256KW, []byte("0123456789abcdef0123456789abcdef"), []byte("0123456789abcdef0123456789abcdef")},
+            {DIRECT, direct, direct},
+            {ECDH_ES, p256Key, &p256Key.PublicKey},
+            {ECDH_ES, k256Key, &k256Key.PublicKey},
+            {ECDH_ES, bp256Key, &bp256Key.PublicKey},
+            {ECDH_ES, sm2Key, &sm2Key.PublicKey},
+            {ECDH_ES, x25519Key, x25519Key.PublicKey()},
+            {ECDH_ES, x448Key, x448Key.PublicKey()},
+            {ECDH_ES, ecdhP384Key, ecdhP384Key.PublicKey()},
+            {ECDH_ES_A128KW, p521Key, &p521Key.PublicKey},
+            {ECDH_ES_A192KW, x25519Key, x25519Key.PublicKey()},
+            {ECDH_ES_A256KW, sm2Key, &sm2Key.PublicKey},
+        }
+    }
+
+    plaintext := []byte("test-plaintext")
+
+    for _, enc := range encs {
+        for _, r := range recipients(enc) {
+            token, err := EncryptCompact(plaintext, r.alg, enc, r.pub, &Header{
+                AgreementPartyUInfo: []byte("Alice"),
+                AgreementPartyVInfo: []byte("Bob"),
+            })
+            if err != nil {
+                t.Fatalf("%s %s %T: %v", r.alg, enc, r.pub, err)
+            }
+
+            got, err := Decrypt(token, r.priv)
+            if err != nil {
+                t.Fatalf("%s %s %T: %v", r.alg, enc, r.priv, err)
+            }
+
+            if !bytes.Equal(got, plaintext) {
+                t.Errorf("%s %s: plaintext got %s", r.alg, enc, got)
+            }
+
+            jwe, _ := ParseJWE(token)
+            jwe.Ciphertext[0] ^= 1
+            if _, err = jwe.Decrypt(r.priv); err == nil {
+                t.Errorf("%s %s: Decrypt modified data should fail", r.alg, enc)
+            }
+        }
+    }
+}
+
+func Test_JWEWrongKey(t *testing.T) {
+    key1, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    key2, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    key384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+
+    token, err := EncryptCompact([]byte("test"), ECDH_ES_A128KW, A128GCM, &JWK{Key: &key1.PublicKey, KeyID: "k1"}, nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if _, err = Decrypt(token, key2); err == nil {
+        t.Error("Decrypt with wrong key should fail")
+    }
+
+    if _, err = Decrypt(token, key384); err == nil {
+        t.Error("Decrypt with other curve key should fail")
+    }
+
+    set := &JWKSet{
+        Keys: []JWK{
+            {Key: key2, KeyID: "k2"},
+            {Key: key1, KeyID: "k1"},
+        },
+    }
+
+    got, err := Decrypt(token, set)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if string(got) != "test" {
+        t.Errorf("plaintext got %s", got)
+    }
+
+    if _, err = EncryptCompact([]byte("test"), A128KW, A128GCM, []byte("short"), nil); err == nil {
+        t.Error("A128KW with short key should fail")
+    }
+
+    if _, err = EncryptCompact([]byte("test"), DIRECT, A256GCM, []byte("0123456789abcdef"), nil); err == nil {
+        t.Error("dir with wrong key size should fail")
+    }
+
+    if _, err = EncryptCompact([]byte("test"), "RSA1_5", A128GCM, &key1.PublicKey, nil); err == nil {
+        t.Error("unsupported alg should fail")
+    }
+}
@@ -0,0 +1,643 @@
+package jose
+
+import (
+    "fmt"
+    "errors"
+    "crypto"
+    "math/big"
+    "crypto/rsa"
+    "crypto/ecdsa"
+    "crypto/ed25519"
+    "crypto/elliptic"
+    "encoding/json"
+    crypto_ecdh "crypto/ecdh"
+
+    "github.com/deatil/go-cryptobin/ecdh"
+    "github.com/deatil/go-cryptobin/ed448"
+    "github.com/deatil/go-cryptobin/gm/sm2"
+    "github.com/deatil/go-cryptobin/elliptic/secp256k1"
+    "github.com/deatil/go-cryptobin/elliptic/brainpool"
+)
+
+// 密钥类型
+const (
+    KeyTypeRSA = "RSA"
+    KeyTypeEC  = "EC"
+    KeyTypeOKP = "OKP"
+    KeyTypeOct = "oct"
+)
+
+// 曲线名称
+const (
+    CurveP256      = "P-256"
+    CurveP384      = "P-384"
+    CurveP521      = "P-521"
+    CurveSecp256k1 = "secp256k1"
+    CurveBP256     = "BP-256"
+    CurveBP384     = "BP-384"
+    CurveBP512     = "BP-512"
+    CurveSM2       = "SM2"
+
+    CurveEd25519   = "Ed25519"
+    CurveEd448     = "Ed448"
+    CurveX25519    = "X25519"
+    CurveX448      = "X448"
+)
+
+// EC 曲线, Brainpool 使用 r1 曲线
+func ecCurveByName(name string) (elliptic.Curve, error) {
+    switch name {
+        case CurveP256:
+            return elliptic.P256(), nil
+        case CurveP384:
+            return elliptic.P384(), nil
+        case CurveP521:
+            return elliptic.P521(), nil
+        case CurveSecp256k1:
+            return secp256k1.Curve(), nil
+        case CurveBP256:
+            return brainpool.P256r1(), nil
+        case CurveBP384:
+            return brainpool.P384r1(), nil
+        case CurveBP512:
+            return brainpool.P512r1(), nil
+        case CurveSM2:
+            return sm2.P256(), nil
+    }
+
+    return nil, ErrUnsupportedCurve
+}
+
+// EC 曲线名称
+func ecCurveName(curve elliptic.Curve) (string, error) {
+    switch curve {
+        case elliptic.P256():
+            return CurveP256, nil
+        case elliptic.P384():
+            return CurveP384, nil
+        case elliptic.P521():
+            return CurveP521, nil
+        case secp256k1.Curve():
+            return CurveSecp256k1, nil
+        case brainpool.P256r1():
+            return CurveBP256, nil
+        case brainpool.P384r1():
+            return CurveBP384, nil
+        case brainpool.P512r1():
+            return CurveBP512, nil
+        case sm2.P256():
+            return CurveSM2, nil
+    }
+
+    return "", ErrUnsupportedCurve
+}
+
+// 坐标长度
+func curveSize(curve elliptic.Curve) int {
+    return (curve.Params().BitSize + 7) / 8
+}
+
+/**
+ * JWK (RFC 7517)
+ *
+ * 支持 RSA, EC (含 secp256k1, Brainpool 及 SM2),
+ * OKP (Ed25519, Ed448, X25519, X448) 及 oct 类型的密钥
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type JWK struct {
+    // 密钥, 可为:
+    // *rsa.PrivateKey, *rsa.PublicKey,
+    // *ecdsa.PrivateKey, *ecdsa.PublicKey,
+    // *sm2.PrivateKey, *sm2.PublicKey,
+    // ed25519.PrivateKey, ed25519.PublicKey,
+    // ed448.PrivateKey, ed448.PublicKey,
+    // *ecdh.PrivateKey, *ecdh.PublicKey,
+    // []byte
+    Key any
+
+    // 密钥 ID
+    KeyID string
+
+    // 使用的算法
+    Algorithm string
+
+    // 用途, sig 或 enc
+    Use string
+
+    // 允许的操作
+    KeyOps []string
+}
+
+// JWK 的 JSON 结构
+type rawJWK struct {
+    Kty    string     `json:"kty"`
+    Kid    string     `json:"kid,omitempty"`
+    Alg    string     `json:"alg,omitempty"`
+    Use    string     `json:"use,omitempty"`
+    KeyOps []string   `json:"key_ops,omitempty"`
+    Crv    string     `json:"crv,omitempty"`
+
+    // RSA
+    N      byteBuffer `json:"n,omitempty"`
+    E      byteBuffer `json:"e,omitempty"`
+    P      byteBuffer `json:"p,omitempty"`
+    Q      byteBuffer `json:"q,omitempty"`
+    Dp     byteBuffer `json:"dp,omitempty"`
+    Dq     byteBuffer `json:"dq,omitempty"`
+    Qi     byteBuffer `json:"qi,omitempty"`
+    Oth    []any      `json:"oth,omitempty"`
+
+    // EC 及 OKP
+    X      byteBuffer `json:"x,omitempty"`
+    Y      byteBuffer `json:"y,omitempty"`
+
+    // 私钥数据
+    D      byteBuffer `json:"d,omitempty"`
+
+    // oct
+    K      byteBuffer `json:"k,omitempty"`
+}
+
+// 生成 JWK
+func NewJWK(key any) *JWK {
+    return &JWK{
+        Key: key,
+    }
+}
+
+// 是否为公钥
+func (k JWK) IsPublic() bool {
+    switch k.Key.(type) {
+        case *rsa.PublicKey,
+            *ecdsa.PublicKey,
+            *sm2.PublicKey,
+            ed25519.PublicKey,
+            ed448.PublicKey,
+            *ecdh.PublicKey:
+            return true
+    }
+
+    return false
+}
+
+// 返回公钥 JWK
+func (k JWK) Public() (JWK, error) {
+    if k.IsPublic() {
+        return k, nil
+    }
+
+    switch key := k.Key.(type) {
+        case *rsa.PrivateKey:
+            k.Key = &key.PublicKey
+        case *ecdsa.PrivateKey:
+            k.Key = &key.PublicKey
+        case *sm2.PrivateKey:
+            k.Key = &key.PublicKey
+        case ed25519.PrivateKey:
+            k.Key = key.Public()
+        case ed448.PrivateKey:
+            k.Key = key.Public()
+        case *ecdh.PrivateKey:
+            k.Key = key.PublicKey()
+        default:
+            return JWK{}, ErrUnsupportedKeyType
+    }
+
+    return k, nil
+}
+
+// 编码为 JSON
+func (k JWK) MarshalJSON() ([]byte, error) {
+    raw, err := marshalJWKKey(k.Key)
+    if err != nil {
+        return nil, err
+    }
+
+    raw.Kid = k.KeyID
+    raw.Alg = k.Algorithm
+    raw.Use = k.Use
+    raw.KeyOps = k.KeyOps
+
+    return json.Marshal(raw)
+}
+
+// 从 JSON 解析
+func (k *JWK) UnmarshalJSON(data []byte) error {
+    var raw rawJWK
+    if err := json.Unmarshal(data, &raw); err != nil {
+        return err
+    }
+
+    key, err := raw.key()
+    if err != nil {
+        return err
+    }
+
+    *k = JWK{
+        Key:       key,
+        KeyID:     raw.Kid,
+        Algorithm: raw.Alg,
+        Use:       raw.Use,
+        KeyOps:    raw.KeyOps,
+    }
+
+    return nil
+}
+
+// RFC 7638 指纹
+func (k JWK) Thumbprint(hash crypto.Hash) ([]byte, error) {
+    raw, err := marshalJWKKey(k.Key)
+    if err != nil {
+        return nil, err
+    }
+
+    var input string
+    switch raw.Kty {
+        case KeyTypeRSA:
+            input = fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`,
+                encodeSegment(raw.E), encodeSegment(raw.N))
+        case KeyTypeEC:
+            input = fmt.Sprintf(`{"crv":"%s","kty":"EC","x":"%s","y":"%s"}`,
+                raw.Crv, encodeSegment(raw.X), encodeSegment(raw.Y))
+        case KeyTypeOKP:
+            input = fmt.Sprintf(`{"crv":"%s","kty":"OKP","x":"%s"}`,
+                raw.Crv, encodeSegment(raw.X))
+        case KeyTypeOct:
+            input = fmt.Sprintf(`{"k":"%s","kty":"oct"}`,
+                encodeSegment(raw.K))
+    }
+
+    if !hash.Available() {
+        return nil, errors.New("jose: hash function is not available")
+    }
+
+    h := hash.New()
+    h.Write([]byte(input))
+
+    return h.Sum(nil), nil
+}
+
+// 编码 JWK
+func MarshalJWK(key any) ([]byte, error) {
+    return json.Marshal(JWK{Key: key})
+}
+
+// 解析 JWK
+func ParseJWK(data []byte) (*JWK, error) {
+    var k JWK
+    if err := json.Unmarshal(data, &k); err != nil {
+        return nil, err
+    }
+
+    return &k, nil
+}
+
+// 密钥转为 JWK 结构
+func marshalJWKKey(key any) (*rawJWK, error) {
+    switch k := key.(type) {
+        case *rsa.PublicKey:
+            return marshalRSAPublicKey(k), nil
+        case *rsa.PrivateKey:
+            return marshalRSAPrivateKey(k)
+        case *ecdsa.PublicKey:
+            return marshalECPublicKey(k.Curve, k.X, k.Y)
+        case *ecdsa.PrivateKey:
+            return marshalECPrivateKey(k.Curve, k.X, k.Y, k.D)
+        case *sm2.PublicKey:
+            return marshalECPublicKey(k.Curve, k.X, k.Y)
+        case *sm2.PrivateKey:
+            return marshalECPrivateKey(k.Curve, k.X, k.Y, k.D)
+        case ed25519.PublicKey:
+            return &rawJWK{Kty: KeyTypeOKP, Crv: CurveEd25519, X: byteBuffer(k)}, nil
+        case ed25519.PrivateKey:
+            return &rawJWK{
+                Kty: KeyTypeOKP,
+                Crv: CurveEd25519,
+                X:   byteBuffer(k.Public().(ed25519.PublicKey)),
+                D:   byteBuffer(k.Seed()),
+            }, nil
+        case ed448.PublicKey:
+            return &rawJWK{Kty: KeyTypeOKP, Crv: CurveEd448, X: byteBuffer(k)}, nil
+        case ed448.PrivateKey:
+            return &rawJWK{
+                Kty: KeyTypeOKP,
+                Crv: CurveEd448,
+                X:   byteBuffer(k.Public().(ed448.PublicKey)),
+                D:   byteBuffer(k.Seed()),
+            }, nil
+        case *ecdh.PublicKey:
+            return marshalECDHPublicKey(k)
+        case *ecdh.PrivateKey:
+            return marshalECDHPrivateKey(k)
+        case *crypto_ecdh.PublicKey:
+            pub, err := ecdh.FromPublicKey(k)
+            if err != nil {
+                return nil, err
+            }
+
+            return marshalECDHPublicKey(pub)
+        case *crypto_ecdh.PrivateKey:
+            priv, err := ecdh.FromPrivateKey(k)
+            if err != nil {
+                return nil, err
+            }
+
+            return marshalECDHPrivateKey(priv)
+        case []byte:
+            if len(k) == 0 {
+                return nil, ErrInvalidKey
+            }
+
+            return &rawJWK{Kty: KeyTypeOct, K: byteBuffer(k)}, nil
+    }
+
+    return nil, ErrUnsupportedKeyType
+}
+
+func marshalRSAPublicKey(pub *rsa.PublicKey) *rawJWK {
+    return &rawJWK{
+        Kty: KeyTypeRSA,
+        N:   pub.N.Bytes(),
+        E:   big.NewInt(int64(pub.E)).Bytes(),
+    }
+}
+
+func marshalRSAPrivateKey(priv *rsa.PrivateKey) (*rawJWK, error) {
+    if len(priv.Primes) != 2 {
+        return nil, errors.New("jose: multi-prime rsa key is not supported")
+    }
+
+    raw := marshalRSAPublicKey(&priv.PublicKey)
+    raw.D = priv.D.Bytes()
+    raw.P = priv.Primes[0].Bytes()
+    raw.Q = priv.Primes[1].Bytes()
+
+    if priv.Precomputed.Dp == nil {
+        priv.Precompute()
+    }
+
+    raw.Dp = priv.Precomputed.Dp.Bytes()
+    raw.Dq = priv.Precomputed.Dq.Bytes()
+    raw.Qi = priv.Precomputed.Qinv.Bytes()
+
+    return raw, nil
+}
+
+func marshalECPublicKey(curve elliptic.Curve, x, y *big.Int) (*rawJWK, error) {
+    name, err := ecCurveName(curve)
+    if err != nil {
+        return nil, err
+    }
+
+    size := curveSize(curve)
+
+    return &rawJWK{
+        Kty: KeyTypeEC,
+        Crv: name,
+        X:   fillBytes(x, size),
+        Y:   fillBytes(y, size),
+    }, nil
+}
+
+func marshalECPrivateKey(curve elliptic.Curve, x, y, d *big.Int) (*rawJWK, error) {
+    raw, err := marshalECPublicKey(curve, x, y)
+    if err != nil {
+        return nil, err
+    }
+
+    // d 长度和曲线阶的长度相同
+    raw.D = fillBytes(d, (curve.Params().N.BitLen() + 7) / 8)
+
+    return raw, nil
+}
+
+// ecdh 曲线对应的椭圆曲线
+func ecdhEllipticCurve(curve ecdh.Curve) elliptic.Curve {
+    switch curve {
+        case ecdh.P256():
+            return elliptic.P256()
+        case ecdh.P384():
+            return elliptic.P384()
+        case ecdh.P521():
+            return elliptic.P521()
+        case ecdh.GmSM2():
+            return sm2.P256()
+    }
+
+    return nil
+}
+
+func marshalECDHPublicKey(pub *ecdh.PublicKey) (*rawJWK, error) {
+    switch pub.Curve() {
+        case ecdh.X25519():
+            return &rawJWK{Kty: KeyTypeOKP, Crv: CurveX25519, X: pub.Bytes()}, nil
+        case ecdh.X448():
+            return &rawJWK{Kty: KeyTypeOKP, Crv: CurveX448, X: pub.Bytes()}, nil
+    }
+
+    curve := ecdhEllipticCurve(pub.Curve())
+    if curve == nil {
+        return nil, ErrUnsupportedCurve
+    }
+
+    x, y := elliptic.Unmarshal(curve, pub.Bytes())
+    if x == nil {
+        return nil, ErrInvalidKey
+    }
+
+    return marshalECPublicKey(curve, x, y)
+}
+
+func marshalECDHPrivateKey(priv *ecdh.PrivateKey) (*rawJWK, error) {
+    raw, err := marshalECDHPublicKey(priv.PublicKey())
+    if err != nil {
+        return nil, err
+    }
+
+    raw.D = priv.Bytes()
+
+    return raw, nil
+}
+
+// 解析密钥
+func (raw *rawJWK) key() (any, error) {
+    switch raw.Kty {
+        case KeyTypeRSA:
+            return raw.rsaKey()
+        case KeyTypeEC:
+            return raw.ecKey()
+        case KeyTypeOKP:
+            return raw.okpKey()
+        case KeyTypeOct:
+            if len(raw.K) == 0 {
+                return nil, ErrInvalidKey
+            }
+
+            return []byte(raw.K), nil
+    }
+
+    return nil, ErrUnsupportedKeyType
+}
+
+func (raw *rawJWK) rsaKey() (any, error) {
+    if len(raw.N) == 0 || len(raw.E) == 0 || len(raw.E) > 4 {
+        return nil, ErrInvalidKey
+    }
+
+    pub := rsa.PublicKey{
+        N: raw.N.bigInt(),
+        E: int(raw.E.bigInt().Int64()),
+    }
+
+    if len(raw.D) == 0 {
+        return &pub, nil
+    }
+
+    if len(raw.Oth) > 0 {
+        return nil, errors.New("jose: multi-prime rsa key is not supported")
+    }
+
+    if len(raw.P) == 0 || len(raw.Q) == 0 {
+        return nil, ErrInvalidKey
+    }
+
+    priv := &rsa.PrivateKey{
+        PublicKey: pub,
+        D:         raw.D.bigInt(),
+        Primes:    []*big.Int{
+            raw.P.bigInt(),
+            raw.Q.bigInt(),
+        },
+    }
+
+    if err := priv.Validate(); err != nil {
+        return nil, err
+    }
+
+    priv.Precompute()
+
+    return priv, nil
+}
+
+func (raw *rawJWK) ecKey() (any, error) {
+    curve, err := ecCurveByName(raw.Crv)
+    if err != nil {
+        return nil, err
+    }
+
+    size := curveSize(curve)
+    if len(raw.X) != size || len(raw.Y) != size {
+        return nil, ErrInvalidKey
+    }
+
+    x, y := raw.X.bigInt(), raw.Y.bigInt()
+    if !curve.IsOnCurve(x, y) {
+        return nil, ErrInvalidKey
+    }
+
+    var d *big.Int
+    if len(raw.D) > 0 {
+        if len(raw.D) != (curve.Params().N.BitLen() + 7) / 8 {
+            return nil, ErrInvalidKey
+        }
+
+        d = raw.D.bigInt()
+        if d.Sign() == 0 || d.Cmp(curve.Params().N) >= 0 {
+            return nil, ErrInvalidKey
+        }
+
+        px, py := curve.ScalarBaseMult(fillBytes(d, size))
+        if px.Cmp(x) != 0 || py.Cmp(y) != 0 {
+            return nil, ErrInvalidKey
+        }
+    }
+
+    if raw.Crv == CurveSM2 {
+        pub := sm2.PublicKey{Curve: curve, X: x, Y: y}
+        if d == nil {
+            return &pub, nil
+        }
+
+        return &sm2.PrivateKey{PublicKey: pub, D: d}, nil
+    }
+
+    pub := ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+    if d == nil {
+        return &pub, nil
+    }
+
+    return &ecdsa.PrivateKey{PublicKey: pub, D: d}, nil
+}
+
+func (raw *rawJWK) okpKey() (any, error) {
+    switch raw.Crv {
+        case CurveEd25519:
+            if len(raw.X) != ed25519.PublicKeySize {
+                return nil, ErrInvalidKey
+            }
+
+            if len(raw.D) == 0 {
+                return ed25519.PublicKey(raw.X), nil
+            }
+
+            if len(raw.D) != ed25519.SeedSize {
+                return nil, ErrInvalidKey
+            }
+
+            priv := ed25519.NewKeyFromSeed(raw.D)
+            if !priv.Public().(ed25519.PublicKey).Equal(ed25519.PublicKey(raw.X)) {
+                return nil, ErrInvalidKey
+            }
+
+            return priv, nil
+        case CurveEd448:
+            if len(raw.X) != ed448.PublicKeySize {
+                return nil, ErrInvalidKey
+            }
+
+            if len(raw.D) == 0 {
+                return ed448.PublicKey(raw.X), nil
+            }
+
+            if len(raw.D) != ed448.SeedSize {
+                return nil, ErrInvalidKey
+            }
+
+            priv := ed448.NewKeyFromSeed(raw.D)
+            if !priv.Public().(ed448.PublicKey).Equal(ed448.PublicKey(raw.X)) {
+                return nil, ErrInvalidKey
+            }
+
+            return priv, nil
+        case CurveX25519:
+            return raw.ecdhKey(ecdh.X25519())
+        case CurveX448:
+            return raw.ecdhKey(ecdh.X448())
+    }
+
+    return nil, ErrUnsupportedCurve
+}
+
+func (raw *rawJWK) ecdhKey(curve ecdh.Curve) (any, error) {
+    pub, err := curve.NewPublicKey(raw.X)
+    if err != nil {
+        return nil, ErrInvalidKey
+    }
+
+    if len(raw.D) == 0 {
+        return pub, nil
+    }
+
+    priv, err := curve.NewPrivateKey(raw.D)
+    if err != nil {
+        return nil, ErrInvalidKey
+    }
+
+    if !priv.PublicKey().Equal(pub) {
+        return nil, ErrInvalidKey
+    }
+
+    return priv, nil
+}
@@ -0,0 +1,350 @@
+package jose
+
+import (
+    "bytes"
+    "errors"
+    "testing"
+    "crypto"
+    "crypto/rsa"
+    "crypto/rand"
+    "crypto/ecdsa"
+    "crypto/ed25519"
+    "crypto/elliptic"
+    "encoding/hex"
+    "encoding/json"
+    crypto_ecdh "crypto/ecdh"
+
+    "github.com/deatil/go-cryptobin/ecdh"
+    "github.com/deatil/go-cryptobin/ed448"
+    "github.com/deatil/go-cryptobin/gm/sm2"
+    "github.com/deatil/go-cryptobin/elliptic/secp256k1"
+    "github.com/deatil/go-cryptobin/elliptic/brainpool"
+)
+
+// RFC 7520 测试密钥
+var cookbookJWKs = []string{
+    `{"kty":"EC","kid":"bilbo.baggins@hobbiton.example","use":"sig","crv":"P-521","x":"AHKZLLOsCOzz5cY97ewNUajB957y-C-U88c3v13nmGZx6sYl_oJXu9A5RkTKqjqvjyekWF-7ytDyRXYgCF5cj0Kt","y":"AdymlHvOiLxXkEhayXQnNCvDX4h9htZaCJN34kfmC6pV5OhQHiraVySsUdaQkAgDPrwQrJmbnX9cwlGfP-HqHZR1"}`,
+    `{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo","d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A"}`,
+    `{"kty":"EC","kid":"bilbo.baggins@hobbiton.example","use":"sig","crv":"P-521","x":"AHKZLLOsCOzz5cY97ewNUajB957y-C-U88c3v13nmGZx6sYl_oJXu9A5RkTKqjqvjyekWF-7ytDyRXYgCF5cj0Kt","y":"AdymlHvOiLxXkEhayXQnNCvDX4h9htZaCJN34kfmC6pV5OhQHiraVySsUdaQkAgDPrwQrJmbnX9cwlGfP-HqHZR1","d":"AAhRON2r9cqXX1hg-RoI6R1tX5p2rUAYdmpHZoC1XNM56KtscrX6zbKipQrCW9CGZH3T4ubpnoTKLDYJ_fF3_rJt"}`,
+    `{"kty":"RSA","kid":"bilbo.baggins@hobbiton.example","use":"sig","n":"n4EPtAOCc9AlkeQHPzHStgAbgs7bTZLwUBZdR8_KuKPEHLd4rHVTeT-O-XV2jRojdNhxJWTDvNd7nqQ0VEiZQHz_AJmSCpMaJMRBSFKrKb2wqVwGU_NsYOYL-QtiWN2lbzcEe6XC0dApr5ydQLrHqkHHig3RBordaZ6Aj-oBHqFEHYpPe7Tpe-OfVfHd1E6cS6M1FZcD1NNLYD5lFHpPI9bTwJlsde3uhGqC0ZCuEHg8lhzwOHrtIQbS0FVbb9k3-tVTU4fg_3L_vniUFAKwuCLqKnS2BYwdq_mzSnbLY7h_qixoR7jig3__kRhuaxwUkRz5iaiQkqgc5gHdrNP5zw","e":"AQAB"}`,
+    `{"kty":"RSA","kid":"juliet@capulet.lit","use":"enc","n":"t6Q8PWSi1dkJj9hTP8hNYFlvadM7DflW9mWepOJhJ66w7nyoK1gPNqFMSQRyO125Gp-TEkodhWr0iujjHVx7BcV0llS4w5ACGgPrcAd6ZcSR0-Iqom-QFcNP8Sjg086MwoqQU_LYywlAGZ21WSdS_PERyGFiNnj3QQlO8Yns5jCtLCRwLHL0Pb1fEv45AuRIuUfVcPySBWYnDyGxvjYGDSM-AqWS9zIQ2ZilgT-GqUmipg0XOC0Cc20rgLe2ymLHjpHciCKVAbY5-L32-lSeZO-Os6U15_aXrk9Gw8cPUaX1_I8sLGuSiVdt3C_Fn2PZ3Z8i744FPFGGcG1qs2Wz-Q","e":"AQAB","d":"GRtbIQmhOZtyszfgKdg4u_N-R_mZGU_9k7JQ_jn1DnfTuMdSNprTeaSTyWfSNkuaAwnOEbIQVy1IQbWVV25NY3ybc_IhUJtfri7bAXYEReWaCl3hdlPKXy9UvqPYGR0kIXTQRqns-dVJ7jahlI7LyckrpTmrM8dWBo4_PMaenNnPiQgO0xnuToxutRZJfJvG4Ox4ka3GORQd9CsCZ2vsUDmsXOfUENOyMqADC6p1M3h33tsurY15k9qMSpG9OX_IJAXmxzAh_tWiZOwk2K4yxH9tS3Lq1yX8C1EWmeRDkK2ahecG85-oLKQt5VEpWHKmjOi_gJSdSgqcN96X52esAQ","p":"2rnSOV4hKSN8sS4CgcQHFbs08XboFDqKum3sc4h3GRxrTmQdl1ZK9uw-PIHfQP0FkxXVrx-WE-ZEbrqivH_2iCLUS7wAl6XvARt1KkIaUxPPSYB9yk31s0Q8UK96E3_OrADAYtAJs-M3JxCLfNgqh56HDnETTQhH3rCT5T3yJws","q":"1u_RiFDP7LBYh3N4GXLT9OpSKYP0uQZyiaZwBtOCBNJgQxaj10RWjsZu0c6Iedis4S7B_coSKB0Kj9PaPaBzg-IySRvvcQuPamQu66riMhjVtG6TlV8CLCYKrYl52ziqK0E_ym2QnkwsUX7eYTB7LbAHRK9GqocDE5B0f808I4s","dp":"KkMTWqBUefVwZ2_Dbj1pPQqyHSHjj90L5x_MOzqYAJMcLMZtbUtwKqvVDq3tbEo3ZIcohbDtt6SbfmWzggabpQxNxuBpoOOf_a_HgMXK_lhqigI4y_kqS1wY52IwjUn5rgRrJ-yYo1h41KR-vz2pYhEAeYrhttWtxVqLCRViD6c","dq":"AvfS0-gRxvn0bwJoMSnFxYcK1WnuEjQFluMGfwGitQBWtfZ1Er7t1xDkbN9GQTB9yqpDoYaN06H7CFtrkxhJIBQaj6nkF5KKS3TQtQ5qCzkOkmxIe3KRbBymXxkb5qwUpX5ELD5xFc6FeiafWYY63TmmEAu_lRFCOJ3xDea-ots","qi":"lSQi-w9CpyUReMErP1RsBLk7wNtOvs5EQpPqmuMvqW57NBUczScEoPwmUqqabu9V0-Py4dQ57_bapoKRu1R90bvuFnU63SHWEFglZQvJDMeAvmj4sm-Fp0oYu_neotgQ0hzbI5gry7ajdYy9-2lNx_76aBZoOUu9HCJ-UsfSOI8"}`,
+}
+
+// SHA-256 指纹
+var cookbookJWKThumbprints = []string{
+    "747ae2dd2003664aeeb21e4753fe7402846170a16bc8df8f23a8cf06d3cbe793",
+    "90facafea9b1556698540f70c0117a22ea37bd5cf3ed3c47093c1707282b4b89",
+    "747ae2dd2003664aeeb21e4753fe7402846170a16bc8df8f23a8cf06d3cbe793",
+    "f63838e96077ad1fc01c3f8405774dedc0641f558ebb4b40dccf5f9b6d66a932",
+    "0fc478f8579325fcee0d4cbc6d9d1ce21730a6e97e435d6008fb379b0ebe47d4",
+}
+
+func Test_JWKThumbprint(t *testing.T) {
+    for i, data := range cookbookJWKs {
+        key, err := ParseJWK([]byte(data))
+        if err != nil {
+            t.Fatalf("[%d] %v", i, err)
+        }
+
+        tp, err := key.Thumbprint(crypto.SHA256)
+        if err != nil {
+            t.Fatalf("[%d] %v", i, err)
+        }
+
+        if hex.EncodeToString(tp) != cookbookJWKThumbprints[i] {
+            t.Errorf("[%d] Thumbprint got %x, want %s", i, tp, cookbookJWKThumbprints[i])
+        }
+    }
+}
+
+func Test_JWKParseCookbook(t *testing.T) {
+    key, err := ParseJWK([]byte(cookbookJWKs[4]))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if _, ok := key.Key.(*rsa.PrivateKey); !ok {
+        t.Fatalf("got %T, want *rsa.PrivateKey", key.Key)
+    }
+
+    if key.KeyID != "juliet@capulet.lit" || key.Use != "enc" {
+        t.Errorf("got kid %q use %q", key.KeyID, key.Use)
+    }
+
+    // 重新编码后应与原数据一致
+    data, err := json.Marshal(key)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    var got, want map[string]any
+    json.Unmarshal(data, &got)
+    json.Unmarshal([]byte(cookbookJWKs[4]), &want)
+
+    for k, v := range want {
+        if got[k] != v {
+            t.Errorf("field %s got %v, want %v", k, got[k], v)
+        }
+    }
+
+    ec, err := ParseJWK([]byte(cookbookJWKs[2]))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    priv, ok := ec.Key.(*ecdsa.PrivateKey)
+    if !ok || priv.Curve != elliptic.P521() {
+        t.Fatalf("got %T, want P-521 *ecdsa.PrivateKey", ec.Key)
+    }
+}
+
+func testJWKKeys(t *testing.T) []any {
+    rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    keys := []any{rsaKey}
+
+    curves := []elliptic.Curve{
+        elliptic.P256(),
+        elliptic.P384(),
+        elliptic.P521(),
+        secp256k1.Curve(),
+        brainpool.P256r1(),
+        brainpool.P384r1(),
+        brainpool.P512r1(),
+    }
+
+    for _, curve := range curves {
+        k, err := ecdsa.GenerateKey(curve, rand.Reader)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        keys = append(keys, k)
+    }
+
+    sm2Key, err := sm2.GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    _, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    _, ed448Key, err := ed448.GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    x25519Key, err := ecdh.X25519().GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    x448Key, err := ecdh.X448().GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return append(keys, sm2Key, ed25519Key, ed448Key, x25519Key, x448Key, []byte("0123456789abcdef"))
+}
+
+func Test_JWKRoundtrip(t *testing.T) {
+    for i, key := range testJWKKeys(t) {
+        jwk := JWK{
+            Key:   key,
+            KeyID: "test-kid",
+        }
+
+        data, err := json.Marshal(jwk)
+        if err != nil {
+            t.Fatalf("[%d] %T: %v", i, key, err)
+        }
+
+        parsed, err := ParseJWK(data)
+        if err != nil {
+            t.Fatalf("[%d] %T: %v", i, key, err)
+        }
+
+        if parsed.KeyID != "test-kid" {
+            t.Errorf("[%d] kid got %q", i, parsed.KeyID)
+        }
+
+        if k, ok := key.([]byte); ok {
+            if !bytes.Equal(parsed.Key.([]byte), k) {
+                t.Errorf("[%d] oct key not equal", i)
+            }
+
+            continue
+        }
+
+        type equaler interface {
+            Equal(crypto.PrivateKey) bool
+        }
+
+        if !key.(equaler).Equal(parsed.Key) {
+            t.Errorf("[%d] %T key not equal", i, key)
+        }
+
+        pub, err := jwk.Public()
+        if err != nil {
+            t.Fatalf("[%d] %T: %v", i, key, err)
+        }
+
+        if !pub.IsPublic() {
+            t.Errorf("[%d] %T Public() is not public", i, key)
+        }
+
+        pubData, err := json.Marshal(pub)
+        if err != nil {
+            t.Fatalf("[%d] %T: %v", i, key, err)
+        }
+
+        var fields map[string]any
+        json.Unmarshal(pubData, &fields)
+
+        if _, ok := fields["d"]; ok {
+            t.Errorf("[%d] %T public jwk has private field", i, key)
+        }
+
+        parsedPub, err := ParseJWK(pubData)
+        if err != nil {
+            t.Fatalf("[%d] %T: %v", i, key, err)
+        }
+
+        if !parsedPub.IsPublic() {
+            t.Errorf("[%d] %T parsed public key is not public", i, key)
+        }
+
+        type publicer interface {
+            Public() crypto.PublicKey
+        }
+        type publicKeyEqualer interface {
+            Equal(crypto.PublicKey) bool
+        }
+
+        origPub := key.(publicer).Public()
+        if !origPub.(publicKeyEqualer).Equal(parsedPub.Key) {
+            t.Errorf("[%d] %T public key not equal", i, key)
+        }
+    }
+}
+
+func Test_JWKCryptoECDH(t *testing.T) {
+    priv, err := crypto_ecdh.X25519().GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    data, err := MarshalJWK(priv)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    parsed, err := ParseJWK(data)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    got, ok := parsed.Key.(*ecdh.PrivateKey)
+    if !ok || got.Curve() != ecdh.X25519() {
+        t.Fatalf("got %T, want X25519 *ecdh.PrivateKey", parsed.Key)
+    }
+
+    if !bytes.Equal(got.Bytes(), priv.Bytes()) {
+        t.Error("X25519 key not equal")
+    }
+
+    p256, err := crypto_ecdh.P256().GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    data, err = MarshalJWK(p256.PublicKey())
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    parsed, err = ParseJWK(data)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if _, ok := parsed.Key.(*ecdsa.PublicKey); !ok {
+        t.Fatalf("got %T, want *ecdsa.PublicKey", parsed.Key)
+    }
+}
+
+func Test_JWKInvalid(t *testing.T) {
+    tests := []string{
+        // 点不在曲线上
+        `{"kty":"EC","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyQ"}`,
+        // 私钥和公钥不匹配
+        `{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo","d":"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"}`,
+        // 长度错误
+        `{"kty":"OKP","crv":"X25519","x":"AAAA"}`,
+        // 未知曲线
+        `{"kty":"EC","crv":"P-192","x":"AA","y":"AA"}`,
+        // 未知类型
+        `{"kty":"foo"}`,
+    }
+
+    for i, data := range tests {
+        if _, err := ParseJWK([]byte(data)); err == nil {
+            t.Errorf("[%d] ParseJWK should fail", i)
+        }
+    }
+
+    if _, err := MarshalJWK(struct{}{}); !errors.Is(err, ErrUnsupportedKeyType) {
+        t.Errorf("got %v, want ErrUnsupportedKeyType", err)
+    }
+
+    p224, _ := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+    if _, err := MarshalJWK(p224); !errors.Is(err, ErrUnsupportedCurve) {
+        t.Errorf("got %v, want ErrUnsupportedCurve", err)
+    }
+}
+
+func Test_JWKSet(t *testing.T) {
+    data := `{"keys":[` + cookbookJWKs[0] + `,{"kty":"foo","kid":"unknown"},` + cookbookJWKs[1] + `]}`
+
+    set, err := ParseJWKSet([]byte(data))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if len(set.Keys) != 2 {
+        t.Fatalf("got %d keys, want 2", len(set.Keys))
+    }
+
+    keys := set.Key("bilbo.baggins@hobbiton.example")
+    if len(keys) != 1 {
+        t.Fatalf("got %d keys, want 1", len(keys))
+    }
+
+    if _, ok := keys[0].Key.(*ecdsa.PublicKey); !ok {
+        t.Errorf("got %T, want *ecdsa.PublicKey", keys[0].Key)
+    }
+
+    out, err := MarshalJWKSet(set.Keys[0], &set.Keys[1])
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    set2, err := ParseJWKSet(out)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if len(set2.Keys) != 2 || set2.Keys[0].KeyID != "bilbo.baggins@hobbiton.example" {
+        t.Error("JWKSet roundtrip fail")
+    }
+
+    if _, err = ParseJWKSet([]byte(`{"foo":[]}`)); err == nil {
+        t.Error("ParseJWKSet without keys should fail")
+    }
+}
@@ -0,0 +1,87 @@
+package jose
+
+import (
+    "errors"
+    "encoding/json"
+)
+
+// JWK Set
+type JWKSet struct {
+    Keys []JWK `json:"keys"`
+}
+
+// 根据 kid 获取密钥
+func (s JWKSet) Key(kid string) []JWK {
+    var keys []JWK
+    for _, key := range s.Keys {
+        if key.KeyID == kid {
+            keys = append(keys, key)
+        }
+    }
+
+    return keys
+}
+
+// 从 JSON 解析, 不支持的密钥类型会被忽略
+func (s *JWKSet) UnmarshalJSON(data []byte) error {
+    var raw struct {
+        Keys []json.RawMessage `json:"keys"`
+    }
+
+    if err := json.Unmarshal(data, &raw); err != nil {
+        return err
+    }
+
+    if raw.Keys == nil {
+        return ErrMalformed
+    }
+
+    keys := make([]JWK, 0, len(raw.Keys))
+    for _, rawKey := range raw.Keys {
+        var key JWK
+        if err := json.Unmarshal(rawKey, &key); err != nil {
+            if errors.Is(err, ErrUnsupportedKeyType) ||
+                errors.Is(err, ErrUnsupportedCurve) {
+                continue
+            }
+
+            return err
+        }
+
+        keys = append(keys, key)
+    }
+
+    s.Keys = keys
+
+    return nil
+}
+
+// 编码 JWK Set
+func MarshalJWKSet(keys ...any) ([]byte, error) {
+    set := JWKSet{
+        Keys: make([]JWK, 0, len(keys)),
+    }
+
+    for _, key := range keys {
+        switch k := key.(type) {
+            case JWK:
+                set.Keys = append(set.Keys, k)
+            case *JWK:
+                set.Keys = append(set.Keys, *k)
+            default:
+                set.Keys = append(set.Keys, JWK{Key: key})
+        }
+    }
+
+    return json.Marshal(set)
+}
+
+// 解析 JWK Set
+func ParseJWKSet(data []byte) (*JWKSet, error) {
+    var set JWKSet
+    if err := json.Unmarshal(data, &set); err != nil {
+        return nil, err
+    }
+
+    return &set, nil
+}
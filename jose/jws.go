@@ -0,0 +1,375 @@
+package jose
+
+import (
+    "bytes"
+    "strings"
+    "encoding/json"
+)
+
+// JWS 签名
+type Signature struct {
+    // 受保护的头信息
+    Protected Header
+
+    // 不受保护的头信息
+    Header Header
+
+    // 签名数据
+    Signature []byte
+
+    // 原始的受保护头信息
+    protected string
+}
+
+// 合并后的头信息
+func (s Signature) mergedHeader() Header {
+    return s.Protected.merge(s.Header)
+}
+
+/**
+ * JWS (RFC 7515)
+ *
+ * 支持 compact 及 JSON 格式
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+type JWS struct {
+    // 载荷
+    Payload []byte
+
+    // 签名列表
+    Signatures []Signature
+}
+
+// 生成 JWS
+func NewJWS(payload []byte) *JWS {
+    return &JWS{
+        Payload: payload,
+    }
+}
+
+// 签名, key 可为 *JWK 或者具体密钥
+// header 为额外的受保护头信息, 可为 nil
+func (jws *JWS) Sign(alg SignatureAlgorithm, key any, header *Header) error {
+    key, kid := unwrapJWK(key)
+
+    var protected Header
+    if header != nil {
+        protected = *header
+    }
+
+    protected.Algorithm = string(alg)
+    if protected.KeyID == "" {
+        protected.KeyID = kid
+    }
+
+    headerData, err := json.Marshal(protected)
+    if err != nil {
+        return err
+    }
+
+    encodedHeader := encodeSegment(headerData)
+
+    sig, err := alg.sign(key, signingInput(encodedHeader, jws.Payload))
+    if err != nil {
+        return err
+    }
+
+    jws.Signatures = append(jws.Signatures, Signature{
+        Protected: protected,
+        Signature: sig,
+        protected: encodedHeader,
+    })
+
+    return nil
+}
+
+// 验证, 有一个签名验证通过即返回 nil
+// key 可为 *JWK, *JWKSet 或者具体密钥
+func (jws *JWS) Verify(key any) error {
+    if len(jws.Signatures) == 0 {
+        return ErrVerify
+    }
+
+    for _, sig := range jws.Signatures {
+        if err := jws.verifySignature(sig, key); err == nil {
+            return nil
+        }
+    }
+
+    return ErrVerify
+}
+
+func (jws *JWS) verifySignature(sig Signature, key any) error {
+    header := sig.mergedHeader()
+
+    if err := sig.Protected.checkCritical(); err != nil {
+        return err
+    }
+
+    keys := selectKeys(key, header.KeyID)
+    if len(keys) == 0 {
+        return ErrInvalidKey
+    }
+
+    alg := SignatureAlgorithm(header.Algorithm)
+    input := signingInput(sig.protected, jws.Payload)
+
+    for _, k := range keys {
+        if alg.verify(publicKeyOf(k), input, sig.Signature) == nil {
+            return nil
+        }
+    }
+
+    return ErrVerify
+}
+
+// compact 格式, 只能有一个签名且没有不受保护的头信息
+func (jws *JWS) CompactSerialize() (string, error) {
+    if len(jws.Signatures) != 1 || !jws.Signatures[0].Header.isEmpty() {
+        return "", ErrMalformed
+    }
+
+    sig := jws.Signatures[0]
+
+    return sig.protected + "." +
+        encodeSegment(jws.Payload) + "." +
+        encodeSegment(sig.Signature), nil
+}
+
+type rawSignature struct {
+    Protected string     `json:"protected,omitempty"`
+    Header    *Header    `json:"header,omitempty"`
+    Signature byteBuffer `json:"signature"`
+}
+
+type rawJWS struct {
+    Payload    *string         `json:"payload"`
+    Signatures []rawSignature  `json:"signatures,omitempty"`
+
+    // flattened 格式
+    Protected  string          `json:"protected,omitempty"`
+    Header     *Header         `json:"header,omitempty"`
+    Signature  byteBuffer      `json:"signature,omitempty"`
+}
+
+func (jws *JWS) rawSignatures() []rawSignature {
+    sigs := make([]rawSignature, 0, len(jws.Signatures))
+    for _, sig := range jws.Signatures {
+        raw := rawSignature{
+            Protected: sig.protected,
+            Signature: sig.Signature,
+        }
+
+        if !sig.Header.isEmpty() {
+            header := sig.Header
+            raw.Header = &header
+        }
+
+        sigs = append(sigs, raw)
+    }
+
+    return sigs
+}
+
+// JSON 格式
+func (jws *JWS) JSONSerialize() ([]byte, error) {
+    if len(jws.Signatures) == 0 {
+        return nil, ErrMalformed
+    }
+
+    payload := encodeSegment(jws.Payload)
+
+    return json.Marshal(rawJWS{
+        Payload:    &payload,
+        Signatures: jws.rawSignatures(),
+    })
+}
+
+// flattened JSON 格式, 只能有一个签名
+func (jws *JWS) FlattenedSerialize() ([]byte, error) {
+    if len(jws.Signatures) != 1 {
+        return nil, ErrMalformed
+    }
+
+    payload := encodeSegment(jws.Payload)
+    sig := jws.rawSignatures()[0]
+
+    return json.Marshal(rawJWS{
+        Payload:   &payload,
+        Protected: sig.Protected,
+        Header:    sig.Header,
+        Signature: sig.Signature,
+    })
+}
+
+// 解析 JWS, 支持 compact 及 JSON 格式
+func ParseJWS(data []byte) (*JWS, error) {
+    data = bytes.TrimSpace(data)
+    if len(data) > 0 && data[0] == '{' {
+        return parseJWSJSON(data)
+    }
+
+    return parseJWSCompact(string(data))
+}
+
+func parseJWSCompact(data string) (*JWS, error) {
+    parts := strings.Split(data, ".")
+    if len(parts) != 3 {
+        return nil, ErrMalformed
+    }
+
+    payload, err := decodeSegment(parts[1])
+    if err != nil {
+        return nil, ErrMalformed
+    }
+
+    sig, err := parseSignature(parts[0], nil, parts[2])
+    if err != nil {
+        return nil, err
+    }
+
+    return &JWS{
+        Payload:    payload,
+        Signatures: []Signature{sig},
+    }, nil
+}
+
+func parseJWSJSON(data []byte) (*JWS, error) {
+    var raw rawJWS
+    if err := json.Unmarshal(data, &raw); err != nil {
+        return nil, err
+    }
+
+    if raw.Payload == nil {
+        return nil, ErrMalformed
+    }
+
+    payload, err := decodeSegment(*raw.Payload)
+    if err != nil {
+        return nil, ErrMalformed
+    }
+
+    sigs := raw.Signatures
+    if sigs == nil {
+        sigs = []rawSignature{
+            {
+                Protected: raw.Protected,
+                Header:    raw.Header,
+                Signature: raw.Signature,
+            },
+        }
+    } else if raw.Protected != "" || raw.Header != nil || raw.Signature != nil {
+        return nil, ErrMalformed
+    }
+
+    jws := &JWS{
+        Payload: payload,
+    }
+
+    for _, s := range sigs {
+        sig, err := parseSignature(s.Protected, s.Header, encodeSegment(s.Signature))
+        if err != nil {
+            return nil, err
+        }
+
+        jws.Signatures = append(jws.Signatures, sig)
+    }
+
+    return jws, nil
+}
+
+func parseSignature(protected string, header *Header, signature string) (Signature, error) {
+    var sig Signature
+
+    if protected != "" {
+        headerData, err := decodeSegment(protected)
+        if err != nil {
+            return sig, ErrMalformed
+        }
+
+        if err = json.Unmarshal(headerData, &sig.Protected); err != nil {
+            return sig, err
+        }
+    }
+
+    if header != nil {
+        sig.Header = *header
+    }
+
+    sigData, err := decodeSegment(signature)
+    if err != nil {
+        return sig, ErrMalformed
+    }
+
+    sig.Signature = sigData
+    sig.protected = protected
+
+    return sig, nil
+}
+
+// 签名数据
+func signingInput(protected string, payload []byte) []byte {
+    return []byte(protected + "." + encodeSegment(payload))
+}
+
+// 解析 JWK 包装的密钥
+func unwrapJWK(key any) (any, string) {
+    switch k := key.(type) {
+        case *JWK:
+            return k.Key, k.KeyID
+        case JWK:
+            return k.Key, k.KeyID
+    }
+
+    return key, ""
+}
+
+// 根据 kid 选择密钥
+func selectKeys(key any, kid string) []any {
+    var set []JWK
+
+    switch k := key.(type) {
+        case *JWKSet:
+            set = k.Keys
+        case JWKSet:
+            set = k.Keys
+        default:
+            k, _ = unwrapJWK(key)
+            return []any{k}
+    }
+
+    var keys []any
+    for _, k := range set {
+        if kid == "" || k.KeyID == kid {
+            keys = append(keys, k.Key)
+        }
+    }
+
+    return keys
+}
+
+// compact 格式签名
+func SignCompact(payload []byte, alg SignatureAlgorithm, key any, header *Header) (string, error) {
+    jws := NewJWS(payload)
+    if err := jws.Sign(alg, key, header); err != nil {
+        return "", err
+    }
+
+    return jws.CompactSerialize()
+}
+
+// 验证 compact 或 JSON 格式并返回载荷
+func Verify(data []byte, key any) ([]byte, error) {
+    jws, err := ParseJWS(data)
+    if err != nil {
+        return nil, err
+    }
+
+    if err = jws.Verify(key); err != nil {
+        return nil, err
+    }
+
+    return jws.Payload, nil
+}
@@ -0,0 +1,273 @@
+package jose
+
+import (
+    "crypto"
+    "math/big"
+    "crypto/rsa"
+    "crypto/hmac"
+    "crypto/rand"
+    "crypto/ecdsa"
+    "crypto/ed25519"
+    "crypto/elliptic"
+    _ "crypto/sha256"
+    _ "crypto/sha512"
+
+    "github.com/deatil/go-cryptobin/ed448"
+    "github.com/deatil/go-cryptobin/gm/sm2"
+    "github.com/deatil/go-cryptobin/elliptic/secp256k1"
+)
+
+// 签名算法
+type SignatureAlgorithm string
+
+const (
+    HS256   SignatureAlgorithm = "HS256"
+    HS384   SignatureAlgorithm = "HS384"
+    HS512   SignatureAlgorithm = "HS512"
+    RS256   SignatureAlgorithm = "RS256"
+    RS384   SignatureAlgorithm = "RS384"
+    RS512   SignatureAlgorithm = "RS512"
+    PS256   SignatureAlgorithm = "PS256"
+    PS384   SignatureAlgorithm = "PS384"
+    PS512   SignatureAlgorithm = "PS512"
+    ES256   SignatureAlgorithm = "ES256"
+    ES384   SignatureAlgorithm = "ES384"
+    ES512   SignatureAlgorithm = "ES512"
+    ES256K  SignatureAlgorithm = "ES256K"
+    EdDSA   SignatureAlgorithm = "EdDSA"
+
+    // 未在 IANA 注册, 签名为 r||s, 使用默认 uid
+    SM2_SM3 SignatureAlgorithm = "SM2-SM3"
+)
+
+// 摘要方式
+func (alg SignatureAlgorithm) hash() crypto.Hash {
+    switch alg {
+        case HS256, RS256, PS256, ES256, ES256K:
+            return crypto.SHA256
+        case HS384, RS384, PS384, ES384:
+            return crypto.SHA384
+        case HS512, RS512, PS512, ES512:
+            return crypto.SHA512
+    }
+
+    return 0
+}
+
+// ES 系列算法对应的曲线
+func (alg SignatureAlgorithm) curve() elliptic.Curve {
+    switch alg {
+        case ES256:
+            return elliptic.P256()
+        case ES384:
+            return elliptic.P384()
+        case ES512:
+            return elliptic.P521()
+        case ES256K:
+            return secp256k1.Curve()
+    }
+
+    return nil
+}
+
+// 摘要数据
+func (alg SignatureAlgorithm) digest(data []byte) []byte {
+    h := alg.hash().New()
+    h.Write(data)
+
+    return h.Sum(nil)
+}
+
+// 签名
+func (alg SignatureAlgorithm) sign(key any, data []byte) ([]byte, error) {
+    switch alg {
+        case HS256, HS384, HS512:
+            k, ok := key.([]byte)
+            if !ok {
+                return nil, ErrInvalidKeyType
+            }
+
+            if len(k) == 0 {
+                return nil, ErrInvalidKey
+            }
+
+            mac := hmac.New(alg.hash().New, k)
+            mac.Write(data)
+
+            return mac.Sum(nil), nil
+
+        case RS256, RS384, RS512:
+            k, ok := key.(*rsa.PrivateKey)
+            if !ok {
+                return nil, ErrInvalidKeyType
+            }
+
+            return rsa.SignPKCS1v15(rand.Reader, k, alg.hash(), alg.digest(data))
+
+        case PS256, PS384, PS512:
+            k, ok := key.(*rsa.PrivateKey)
+            if !ok {
+                return nil, ErrInvalidKeyType
+            }
+
+            return rsa.SignPSS(rand.Reader, k, alg.hash(), alg.digest(data), &rsa.PSSOptions{
+                SaltLength: rsa.PSSSaltLengthEqualsHash,
+            })
+
+        case ES256, ES384, ES512, ES256K:
+            k, ok := key.(*ecdsa.PrivateKey)
+            if !ok {
+                return nil, ErrInvalidKeyType
+            }
+
+            if k.Curve != alg.curve() {
+                return nil, ErrInvalidKeyType
+            }
+
+            r, s, err := ecdsa.Sign(rand.Reader, k, alg.digest(data))
+            if err != nil {
+                return nil, err
+            }
+
+            size := curveSize(k.Curve)
+
+            return append(fillBytes(r, size), fillBytes(s, size)...), nil
+
+        case EdDSA:
+            switch k := key.(type) {
+                case ed25519.PrivateKey:
+                    return ed25519.Sign(k, data), nil
+                case ed448.PrivateKey:
+                    return ed448.Sign(k, data), nil
+            }
+
+            return nil, ErrInvalidKeyType
+
+        case SM2_SM3:
+            k, ok := key.(*sm2.PrivateKey)
+            if !ok {
+                return nil, ErrInvalidKeyType
+            }
+
+            return k.SignBytes(rand.Reader, data, nil)
+    }
+
+    return nil, ErrUnsupportedAlgorithm
+}
+
+// 验证
+func (alg SignatureAlgorithm) verify(key any, data, sig []byte) error {
+    switch alg {
+        case HS256, HS384, HS512:
+            expected, err := alg.sign(key, data)
+            if err != nil {
+                return err
+            }
+
+            if !hmac.Equal(expected, sig) {
+                return ErrVerify
+            }
+
+            return nil
+
+        case RS256, RS384, RS512:
+            k, ok := key.(*rsa.PublicKey)
+            if !ok {
+                return ErrInvalidKeyType
+            }
+
+            if rsa.VerifyPKCS1v15(k, alg.hash(), alg.digest(data), sig) != nil {
+                return ErrVerify
+            }
+
+            return nil
+
+        case PS256, PS384, PS512:
+            k, ok := key.(*rsa.PublicKey)
+            if !ok {
+                return ErrInvalidKeyType
+            }
+
+            err := rsa.VerifyPSS(k, alg.hash(), alg.digest(data), sig, &rsa.PSSOptions{
+                SaltLength: rsa.PSSSaltLengthAuto,
+            })
+            if err != nil {
+                return ErrVerify
+            }
+
+            return nil
+
+        case ES256, ES384, ES512, ES256K:
+            k, ok := key.(*ecdsa.PublicKey)
+            if !ok {
+                return ErrInvalidKeyType
+            }
+
+            if k.Curve != alg.curve() {
+                return ErrInvalidKeyType
+            }
+
+            size := curveSize(k.Curve)
+            if len(sig) != 2*size {
+                return ErrVerify
+            }
+
+            r := new(big.Int).SetBytes(sig[:size])
+            s := new(big.Int).SetBytes(sig[size:])
+
+            if !ecdsa.Verify(k, alg.digest(data), r, s) {
+                return ErrVerify
+            }
+
+            return nil
+
+        case EdDSA:
+            var ok bool
+            switch k := key.(type) {
+                case ed25519.PublicKey:
+                    ok = ed25519.Verify(k, data, sig)
+                case ed448.PublicKey:
+                    ok = ed448.Verify(k, data, sig)
+                default:
+                    return ErrInvalidKeyType
+            }
+
+            if !ok {
+                return ErrVerify
+            }
+
+            return nil
+
+        case SM2_SM3:
+            k, ok := key.(*sm2.PublicKey)
+            if !ok {
+                return ErrInvalidKeyType
+            }
+
+            if !k.VerifyBytes(data, sig, nil) {
+                return ErrVerify
+            }
+
+            return nil
+    }
+
+    return ErrUnsupportedAlgorithm
+}
+
+// 私钥转为公钥, 用于使用私钥验证
+func publicKeyOf(key any) any {
+    switch k := key.(type) {
+        case *rsa.PrivateKey:
+            return &k.PublicKey
+        case *ecdsa.PrivateKey:
+            return &k.PublicKey
+        case *sm2.PrivateKey:
+            return &k.PublicKey
+        case ed25519.PrivateKey:
+            return k.Public()
+        case ed448.PrivateKey:
+            return k.Public()
+    }
+
+    return key
+}
@@ -0,0 +1,249 @@
+package jose
+
+import (
+    "bytes"
+    "errors"
+    "testing"
+    "crypto/rsa"
+    "crypto/rand"
+    "crypto/ecdsa"
+    "crypto/ed25519"
+    "crypto/elliptic"
+    "encoding/json"
+
+    "github.com/deatil/go-cryptobin/ed448"
+    "github.com/deatil/go-cryptobin/gm/sm2"
+    "github.com/deatil/go-cryptobin/elliptic/secp256k1"
+)
+
+// RFC 7515 A.1
+func Test_JWSVectorHS256(t *testing.T) {
+    jwk, err := ParseJWK([]byte(`{"kty":"oct","k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow"}`))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    token := "eyJ0eXAiOiJKV1QiLA0KICJhbGciOiJIUzI1NiJ9." +
+        "eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ." +
+        "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+    payload, err := Verify([]byte(token), jwk)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    want := "{\"iss\":\"joe\",\r\n \"exp\":1300819380,\r\n \"http://example.com/is_root\":true}"
+    if string(payload) != want {
+        t.Errorf("payload got %q, want %q", payload, want)
+    }
+
+    // 已经过期
+    if _, err = ParseJWT(token, jwk); err != ErrTokenExpired {
+        t.Errorf("ParseJWT got %v, want ErrTokenExpired", err)
+    }
+
+    if _, err = Verify([]byte(token), []byte("bad-key")); err == nil {
+        t.Error("Verify with bad key should fail")
+    }
+}
+
+// RFC 8037 A.4
+func Test_JWSVectorEd25519(t *testing.T) {
+    jwk, err := ParseJWK([]byte(cookbookJWKs[1]))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    token, err := SignCompact([]byte("Example of Ed25519 signing"), EdDSA, jwk.Key, nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    want := "eyJhbGciOiJFZERTQSJ9." +
+        "RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc." +
+        "hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg"
+
+    if token != want {
+        t.Errorf("token got %s, want %s", token, want)
+    }
+
+    pub, _ := jwk.Public()
+    if _, err = Verify([]byte(want), pub); err != nil {
+        t.Error(err)
+    }
+}
+
+func Test_JWSSignAndVerify(t *testing.T) {
+    rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+    p256Key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    p384Key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+    p521Key, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+    k256Key, _ := ecdsa.GenerateKey(secp256k1.Curve(), rand.Reader)
+    _, ed25519Key, _ := ed25519.GenerateKey(rand.Reader)
+    _, ed448Key, _ := ed448.GenerateKey(rand.Reader)
+    sm2Key, _ := sm2.GenerateKey(rand.Reader)
+    hmacKey := []byte("0123456789abcdef0123456789abcdef")
+
+    tests := []struct {
+        alg SignatureAlgorithm
+        key any
+    }{
+        {HS256, hmacKey},
+        {HS384, hmacKey},
+        {HS512, hmacKey},
+        {RS256, rsaKey},
+        {RS384, rsaKey},
+        {RS512, rsaKey},
+        {PS256, rsaKey},
+        {PS384, rsaKey},
+        {PS512, rsaKey},
+        {ES256, p256Key},
+        {ES384, p384Key},
+        {ES512, p521Key},
+        {ES256K, k256Key},
+        {EdDSA, ed25519Key},
+        {EdDSA, ed448Key},
+        {SM2_SM3, sm2Key},
+    }
+
+    payload := []byte("test-payload")
+
+    for _, tt := range tests {
+        token, err := SignCompact(payload, tt.alg, tt.key, &Header{KeyID: "kid-1"})
+        if err != nil {
+            t.Fatalf("%s: %v", tt.alg, err)
+        }
+
+        // 公钥验证
+        got, err := Verify([]byte(token), publicKeyOf(tt.key))
+        if err != nil {
+            t.Fatalf("%s: %v", tt.alg, err)
+        }
+
+        if !bytes.Equal(got, payload) {
+            t.Errorf("%s: payload got %s", tt.alg, got)
+        }
+
+        // 修改载荷
+        jws, _ := ParseJWS([]byte(token))
+        jws.Payload = []byte("bad-payload")
+        if err = jws.Verify(publicKeyOf(tt.key)); err == nil {
+            t.Errorf("%s: Verify modified payload should fail", tt.alg)
+        }
+    }
+
+    // 密钥和算法不匹配
+    if _, err := SignCompact(payload, ES256, p384Key, nil); !errors.Is(err, ErrInvalidKeyType) {
+        t.Errorf("ES256 with P-384 key got %v", err)
+    }
+
+    if _, err := SignCompact(payload, HS256, rsaKey, nil); !errors.Is(err, ErrInvalidKeyType) {
+        t.Errorf("HS256 with rsa key got %v", err)
+    }
+
+    if _, err := SignCompact(payload, "none", hmacKey, nil); !errors.Is(err, ErrUnsupportedAlgorithm) {
+        t.Errorf("none got %v", err)
+    }
+}
+
+func Test_JWSJSONSerialize(t *testing.T) {
+    rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+    ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+    jws := NewJWS([]byte("test-payload"))
+
+    if err := jws.Sign(RS256, &JWK{Key: rsaKey, KeyID: "rsa"}, nil); err != nil {
+        t.Fatal(err)
+    }
+
+    if err := jws.Sign(ES256, &JWK{Key: ecKey, KeyID: "ec"}, nil); err != nil {
+        t.Fatal(err)
+    }
+
+    if _, err := jws.CompactSerialize(); err == nil {
+        t.Error("CompactSerialize with two signatures should fail")
+    }
+
+    data, err := jws.JSONSerialize()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // 使用 JWK Set 验证
+    set := &JWKSet{
+        Keys: []JWK{
+            {Key: &rsaKey.PublicKey, KeyID: "rsa"},
+            {Key: &ecKey.PublicKey, KeyID: "ec"},
+        },
+    }
+
+    payload, err := Verify(data, set)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if string(payload) != "test-payload" {
+        t.Errorf("payload got %s", payload)
+    }
+
+    if _, err = Verify(data, &ecKey.PublicKey); err != nil {
+        t.Error(err)
+    }
+
+    parsed, err := ParseJWS(data)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if len(parsed.Signatures) != 2 || parsed.Signatures[1].Protected.KeyID != "ec" {
+        t.Error("parsed signatures not match")
+    }
+
+    // flattened
+    single := NewJWS([]byte("test-payload"))
+    if err = single.Sign(ES256, ecKey, nil); err != nil {
+        t.Fatal(err)
+    }
+
+    single.Signatures[0].Header = Header{KeyID: "ec", Extra: map[string]any{"x-test": "v"}}
+
+    data, err = single.FlattenedSerialize()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    var fields map[string]json.RawMessage
+    json.Unmarshal(data, &fields)
+    if _, ok := fields["signatures"]; ok {
+        t.Error("flattened data should not have signatures")
+    }
+
+    parsed, err = ParseJWS(data)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if parsed.Signatures[0].Header.KeyID != "ec" || parsed.Signatures[0].Header.Extra["x-test"] != "v" {
+        t.Error("unprotected header not match")
+    }
+
+    if _, err = Verify(data, set); err != nil {
+        t.Error(err)
+    }
+}
+
+func Test_JWSCritical(t *testing.T) {
+    key := []byte("0123456789abcdef0123456789abcdef")
+
+    token, err := SignCompact([]byte("test"), HS256, key, &Header{
+        Critical: []string{"exp"},
+        Extra:    map[string]any{"exp": 1},
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if _, err = Verify([]byte(token), key); err == nil {
+        t.Error("Verify with unknown crit should fail")
+    }
+}
@@ -0,0 +1,261 @@
+package jose
+
+import (
+    "math"
+    "time"
+    "bytes"
+    "errors"
+    "encoding/json"
+)
+
+var (
+    ErrTokenExpired     = errors.New("jose: token is expired")
+    ErrTokenNotValidYet = errors.New("jose: token is not valid yet")
+    ErrTokenUsedBefore  = errors.New("jose: token used before issued")
+    ErrInvalidClaims    = errors.New("jose: invalid claims")
+    ErrInvalidTimeClaim = errors.New("jose: time claim is not a NumericDate")
+)
+
+// JWT 声明 (RFC 7519)
+type Claims map[string]any
+
+// 字符串字段
+func (c Claims) stringField(name string) string {
+    if v, ok := c[name].(string); ok {
+        return v
+    }
+
+    return ""
+}
+
+// 时间字段, 字段存在但不是 NumericDate 时返回错误
+func (c Claims) numericDate(name string) (time.Time, bool, error) {
+    value, ok := c[name]
+    if !ok {
+        return time.Time{}, false, nil
+    }
+
+    var seconds float64
+
+    switch v := value.(type) {
+        case json.Number:
+            f, err := v.Float64()
+            if err != nil {
+                return time.Time{}, false, ErrInvalidTimeClaim
+            }
+
+            seconds = f
+        case float64:
+            seconds = v
+        case int64:
+            seconds = float64(v)
+        case int:
+            seconds = float64(v)
+        case time.Time:
+            return v, true, nil
+        default:
+            return time.Time{}, false, ErrInvalidTimeClaim
+    }
+
+    if math.IsNaN(seconds) || seconds < math.MinInt64 || seconds >= math.MaxInt64 {
+        return time.Time{}, false, ErrInvalidTimeClaim
+    }
+
+    return time.Unix(int64(seconds), 0), true, nil
+}
+
+// 时间字段
+func (c Claims) timeField(name string) (time.Time, bool) {
+    t, ok, err := c.numericDate(name)
+    if err != nil {
+        return time.Time{}, false
+    }
+
+    return t, ok
+}
+
+// 签发者 iss
+func (c Claims) Issuer() string {
+    return c.stringField("iss")
+}
+
+// 主题 sub
+func (c Claims) Subject() string {
+    return c.stringField("sub")
+}
+
+// ID jti
+func (c Claims) ID() string {
+    return c.stringField("jti")
+}
+
+// 接收方 aud, 可为字符串或者字符串数组
+func (c Claims) Audience() []string {
+    switch v := c["aud"].(type) {
+        case string:
+            return []string{v}
+        case []string:
+            return v
+        case []any:
+            aud := make([]string, 0, len(v))
+            for _, a := range v {
+                if s, ok := a.(string); ok {
+                    aud = append(aud, s)
+                }
+            }
+
+            return aud
+    }
+
+    return nil
+}
+
+// 过期时间 exp
+func (c Claims) ExpiresAt() (time.Time, bool) {
+    return c.timeField("exp")
+}
+
+// 生效时间 nbf
+func (c Claims) NotBefore() (time.Time, bool) {
+    return c.timeField("nbf")
+}
+
+// 签发时间 iat
+func (c Claims) IssuedAt() (time.Time, bool) {
+    return c.timeField("iat")
+}
+
+// 是否包含接收方
+func (c Claims) HasAudience(aud string) bool {
+    for _, a := range c.Audience() {
+        if a == aud {
+            return true
+        }
+    }
+
+    return false
+}
+
+// 验证时间字段, 时间字段存在但不是 NumericDate 时返回 ErrInvalidTimeClaim
+func (c Claims) Validate(now time.Time, leeway time.Duration) error {
+    exp, hasExp, err := c.numericDate("exp")
+    if err != nil {
+        return err
+    }
+
+    nbf, hasNbf, err := c.numericDate("nbf")
+    if err != nil {
+        return err
+    }
+
+    iat, hasIat, err := c.numericDate("iat")
+    if err != nil {
+        return err
+    }
+
+    if hasExp && !now.Add(-leeway).Before(exp) {
+        return ErrTokenExpired
+    }
+
+    if hasNbf && now.Add(leeway).Before(nbf) {
+        return ErrTokenNotValidYet
+    }
+
+    if hasIat && now.Add(leeway).Before(iat) {
+        return ErrTokenUsedBefore
+    }
+
+    return nil
+}
+
+// 编码, time.Time 类型的字段转为时间戳
+func (c Claims) marshal() ([]byte, error) {
+    claims := make(map[string]any, len(c))
+    for k, v := range c {
+        if t, ok := v.(time.Time); ok {
+            v = t.Unix()
+        }
+
+        claims[k] = v
+    }
+
+    return json.Marshal(claims)
+}
+
+func parseClaims(data []byte) (Claims, error) {
+    decoder := json.NewDecoder(bytes.NewReader(data))
+    decoder.UseNumber()
+
+    var claims Claims
+    if err := decoder.Decode(&claims); err != nil || claims == nil {
+        return nil, ErrInvalidClaims
+    }
+
+    return claims, nil
+}
+
+// 签名生成 JWT
+func SignJWT(claims Claims, alg SignatureAlgorithm, key any) (string, error) {
+    payload, err := claims.marshal()
+    if err != nil {
+        return "", err
+    }
+
+    return SignCompact(payload, alg, key, &Header{
+        Type: "JWT",
+    })
+}
+
+// 验证 JWT 签名及时间字段
+func ParseJWT(token string, key any) (Claims, error) {
+    jws, err := parseJWSCompact(token)
+    if err != nil {
+        return nil, err
+    }
+
+    if err = jws.Verify(key); err != nil {
+        return nil, err
+    }
+
+    claims, err := parseClaims(jws.Payload)
+    if err != nil {
+        return nil, err
+    }
+
+    if err = claims.Validate(time.Now(), 0); err != nil {
+        return nil, err
+    }
+
+    return claims, nil
+}
+
+// 加密生成 JWT
+func EncryptJWT(claims Claims, alg KeyAlgorithm, enc ContentEncryption, key any) (string, error) {
+    payload, err := claims.marshal()
+    if err != nil {
+        return "", err
+    }
+
+    return EncryptCompact(payload, alg, enc, key, &Header{
+        Type: "JWT",
+    })
+}
+
+// 解密 JWT 并验证时间字段
+func DecryptJWT(token string, key any) (Claims, error) {
+    payload, err := Decrypt(token, key)
+    if err != nil {
+        return nil, err
+    }
+
+    claims, err := parseClaims(payload)
+    if err != nil {
+        return nil, err
+    }
+
+    if err = claims.Validate(time.Now(), 0); err != nil {
+        return nil, err
+    }
+
+    return claims, nil
+}
@@ -0,0 +1,116 @@
+package jose
+
+import (
+    "time"
+    "testing"
+    "crypto/rand"
+    "crypto/ecdsa"
+    "crypto/elliptic"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+)
+
+func Test_JWT(t *testing.T) {
+    key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+    now := time.Now()
+
+    claims := Claims{
+        "iss": "issuer",
+        "sub": "subject",
+        "aud": []string{"aud1", "aud2"},
+        "exp": now.Add(time.Hour),
+        "nbf": now.Add(-time.Minute).Unix(),
+        "iat": now.Unix(),
+        "foo": "bar",
+    }
+
+    token, err := SignJWT(claims, ES256, key)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    got, err := ParseJWT(token, &key.PublicKey)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if got.Issuer() != "issuer" || got.Subject() != "subject" || got["foo"] != "bar" {
+        t.Errorf("claims got %v", got)
+    }
+
+    if !got.HasAudience("aud2") || got.HasAudience("aud3") {
+        t.Errorf("aud got %v", got.Audience())
+    }
+
+    exp, ok := got.ExpiresAt()
+    if !ok || exp.Unix() != now.Add(time.Hour).Unix() {
+        t.Errorf("exp got %v", exp)
+    }
+
+    jws, _ := ParseJWS([]byte(token))
+    if jws.Signatures[0].Protected.Type != "JWT" {
+        t.Errorf("typ got %s", jws.Signatures[0].Protected.Type)
+    }
+
+    // 过期及未生效
+    expired, _ := SignJWT(Claims{"exp": now.Add(-time.Minute)}, ES256, key)
+    if _, err = ParseJWT(expired, &key.PublicKey); err != ErrTokenExpired {
+        t.Errorf("got %v, want ErrTokenExpired", err)
+    }
+
+    notYet, _ := SignJWT(Claims{"nbf": now.Add(time.Hour)}, ES256, key)
+    if _, err = ParseJWT(notYet, &key.PublicKey); err != ErrTokenNotValidYet {
+        t.Errorf("got %v, want ErrTokenNotValidYet", err)
+    }
+
+    if err = (Claims{"exp": now.Add(-time.Minute)}).Validate(now, 2*time.Minute); err != nil {
+        t.Errorf("Validate with leeway got %v", err)
+    }
+}
+
+func Test_JWTInvalidTimeClaim(t *testing.T) {
+    key := []byte("0123456789abcdef0123456789abcdef")
+
+    tests := []string{
+        `{"exp":"0"}`,
+        `{"exp":null}`,
+        `{"nbf":true}`,
+        `{"iat":[1]}`,
+        `{"exp":1e400}`,
+        `{"exp":1e300}`,
+    }
+
+    for _, claims := range tests {
+        token, err := SignCompact([]byte(claims), HS256, key, &Header{Type: "JWT"})
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if _, err = ParseJWT(token, key); err != ErrInvalidTimeClaim {
+            t.Errorf("%s: got %v, want ErrInvalidTimeClaim", claims, err)
+        }
+    }
+
+    if err := (Claims{"exp": "0"}).Validate(time.Now(), 0); err != ErrInvalidTimeClaim {
+        t.Errorf("Validate got %v, want ErrInvalidTimeClaim", err)
+    }
+}
+
+func Test_EncryptJWT(t *testing.T) {
+    key, _ := sm2.GenerateKey(rand.Reader)
+
+    token, err := EncryptJWT(Claims{"sub": "subject"}, ECDH_ES, A256GCM, &key.PublicKey)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    claims, err := DecryptJWT(token, key)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if claims.Subject() != "subject" {
+        t.Errorf("sub got %s", claims.Subject())
+    }
+}
@@ -0,0 +1,146 @@
+package ocsp
+
+import (
+    "bytes"
+    "errors"
+    "math/big"
+    "crypto"
+    "crypto/x509"
+    "encoding/asn1"
+    "crypto/x509/pkix"
+
+    sm2X509 "github.com/deatil/go-cryptobin/gm/x509"
+)
+
+var errCertificateType = errors.New("cryptobin/ocsp: certificate type must be *x509.Certificate or *sm2X509.Certificate")
+
+// 证书数据
+type certificate struct {
+    raw                     []byte
+    rawSubject              []byte
+    rawSubjectPublicKeyInfo []byte
+    serialNumber            *big.Int
+    publicKey               crypto.PublicKey
+}
+
+// 解析证书
+// 可用 [*x509.Certificate | *sm2X509.Certificate]
+func parseCert(cert any) (*certificate, error) {
+    switch c := cert.(type) {
+        case *x509.Certificate:
+            if c == nil {
+                break
+            }
+
+            return &certificate{
+                raw:                     c.Raw,
+                rawSubject:              c.RawSubject,
+                rawSubjectPublicKeyInfo: c.RawSubjectPublicKeyInfo,
+                serialNumber:            c.SerialNumber,
+                publicKey:               c.PublicKey,
+            }, nil
+        case *sm2X509.Certificate:
+            if c == nil {
+                break
+            }
+
+            return &certificate{
+                raw:                     c.Raw,
+                rawSubject:              c.RawSubject,
+                rawSubjectPublicKeyInfo: c.RawSubjectPublicKeyInfo,
+                serialNumber:            c.SerialNumber,
+                publicKey:               c.PublicKey,
+            }, nil
+    }
+
+    return nil, errCertificateType
+}
+
+// 公钥数据
+func (this *certificate) publicKeyBytes() ([]byte, error) {
+    var publicKeyInfo struct {
+        Algorithm pkix.AlgorithmIdentifier
+        PublicKey asn1.BitString
+    }
+
+    if _, err := asn1.Unmarshal(this.rawSubjectPublicKeyInfo, &publicKeyInfo); err != nil {
+        return nil, err
+    }
+
+    return publicKeyInfo.PublicKey.RightAlign(), nil
+}
+
+// 颁发者名称及公钥摘要
+func (this *certificate) issuerHashes(h Hash) (nameHash, keyHash []byte, err error) {
+    publicKey, err := this.publicKeyBytes()
+    if err != nil {
+        return nil, nil, err
+    }
+
+    return h.sum(this.rawSubject), h.sum(publicKey), nil
+}
+
+// 解析响应中的证书, 使用和颁发者证书相同的类型,
+// 没有颁发者证书时先使用 crypto/x509 解析
+func parseCertificateLike(issuer any, der []byte) (any, error) {
+    switch issuer.(type) {
+        case *x509.Certificate:
+            return x509.ParseCertificate(der)
+        case *sm2X509.Certificate:
+            return sm2X509.ParseCertificate(der)
+    }
+
+    if cert, err := x509.ParseCertificate(der); err == nil {
+        return cert, nil
+    }
+
+    return sm2X509.ParseCertificate(der)
+}
+
+// 检查响应者证书由颁发者签发, 并且可用于 OCSP 签名
+func checkResponderCert(responder, issuer any) error {
+    switch r := responder.(type) {
+        case *x509.Certificate:
+            i, ok := issuer.(*x509.Certificate)
+            if !ok {
+                return errCertificateType
+            }
+
+            if bytes.Equal(r.Raw, i.Raw) {
+                return nil
+            }
+
+            if err := r.CheckSignatureFrom(i); err != nil {
+                return errors.New("cryptobin/ocsp: bad signature on embedded certificate: " + err.Error())
+            }
+
+            for _, usage := range r.ExtKeyUsage {
+                if usage == x509.ExtKeyUsageOCSPSigning {
+                    return nil
+                }
+            }
+        case *sm2X509.Certificate:
+            i, ok := issuer.(*sm2X509.Certificate)
+            if !ok {
+                return errCertificateType
+            }
+
+            if bytes.Equal(r.Raw, i.Raw) {
+                return nil
+            }
+
+            if err := r.CheckSignatureFrom(i); err != nil {
+                return errors.New("cryptobin/ocsp: bad signature on embedded certificate: " + err.Error())
+            }
+
+            for _, usage := range r.ExtKeyUsage {
+                if usage == sm2X509.ExtKeyUsageOCSPSigning {
+                    return nil
+                }
+            }
+        default:
+            return errCertificateType
+    }
+
+    return errors.New("cryptobin/ocsp: responder certificate is not authorized for OCSP signing")
+}
@@ -0,0 +1,262 @@
+package ocsp
+
+import (
+    "hash"
+    "time"
+    "errors"
+    "strconv"
+    "math/big"
+    "crypto/sha1"
+    "crypto/sha256"
+    "crypto/sha512"
+    "encoding/asn1"
+    "crypto/x509/pkix"
+
+    "github.com/deatil/go-cryptobin/hash/sm3"
+)
+
+/**
+ * OCSP 在线证书状态协议 (RFC 6960)
+ *
+ * 证书可用 [*x509.Certificate | *sm2X509.Certificate]
+ * 响应签名支持 RSA, ECDSA, EdDSA 及 SM2 密钥
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+
+var (
+    oidPKIXOCSPBasic = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 1}
+    oidPKIXOCSPNonce = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 2}
+
+    oidSHA1   = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+    oidSHA256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+    oidSHA384 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+    oidSHA512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+    oidSM3    = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 401}
+)
+
+var (
+    ErrParse       = errors.New("cryptobin/ocsp: parse error")
+    ErrNoResponse  = errors.New("cryptobin/ocsp: no response for certificate")
+    ErrIssuerMatch = errors.New("cryptobin/ocsp: response is not for the issuer")
+)
+
+// 响应状态
+type ResponseStatus int
+
+const (
+    Success           ResponseStatus = 0
+    Malformed         ResponseStatus = 1
+    InternalError     ResponseStatus = 2
+    TryLater          ResponseStatus = 3
+    // 4 未使用
+    SignatureRequired ResponseStatus = 5
+    Unauthorized      ResponseStatus = 6
+)
+
+func (r ResponseStatus) String() string {
+    switch r {
+        case Success:
+            return "success"
+        case Malformed:
+            return "malformed"
+        case InternalError:
+            return "internal error"
+        case TryLater:
+            return "try later"
+        case SignatureRequired:
+            return "signature required"
+        case Unauthorized:
+            return "unauthorized"
+        default:
+            return "unknown OCSP status: " + strconv.Itoa(int(r))
+    }
+}
+
+// 响应状态不是成功时返回的错误
+type ResponseError struct {
+    Status ResponseStatus
+}
+
+func (r ResponseError) Error() string {
+    return "cryptobin/ocsp: error from server: " + r.Status.String()
+}
+
+// 证书状态
+const (
+    Good = iota
+    Revoked
+    Unknown
+    // 不是 OCSP 定义的状态, 用于表示服务端出错
+    ServerFailed
+)
+
+// 吊销原因
+const (
+    Unspecified          = 0
+    KeyCompromise        = 1
+    CACompromise         = 2
+    AffiliationChanged   = 3
+    Superseded           = 4
+    CessationOfOperation = 5
+    CertificateHold      = 6
+
+    RemoveFromCRL      = 8
+    PrivilegeWithdrawn = 9
+    AACompromise       = 10
+)
+
+// 错误响应
+var (
+    MalformedRequestErrorResponse = []byte{0x30, 0x03, 0x0A, 0x01, 0x01}
+    InternalErrorErrorResponse    = []byte{0x30, 0x03, 0x0A, 0x01, 0x02}
+    TryLaterErrorResponse         = []byte{0x30, 0x03, 0x0A, 0x01, 0x03}
+    SigRequredErrorResponse       = []byte{0x30, 0x03, 0x0A, 0x01, 0x05}
+    UnauthorizedErrorResponse     = []byte{0x30, 0x03, 0x0A, 0x01, 0x06}
+)
+
+// 证书 ID 使用的摘要
+type Hash uint
+
+const (
+    SHA1 Hash = 1 + iota
+    SHA256
+    SHA384
+    SHA512
+    SM3
+)
+
+var hashOIDs = map[Hash]asn1.ObjectIdentifier{
+    SHA1:   oidSHA1,
+    SHA256: oidSHA256,
+    SHA384: oidSHA384,
+    SHA512: oidSHA512,
+    SM3:    oidSM3,
+}
+
+var hashNames = map[Hash]string{
+    SHA1:   "SHA1",
+    SHA256: "SHA256",
+    SHA384: "SHA384",
+    SHA512: "SHA512",
+    SM3:    "SM3",
+}
+
+func (h Hash) String() string {
+    if name, ok := hashNames[h]; ok {
+        return name
+    }
+
+    return "unknown hash: " + strconv.Itoa(int(h))
+}
+
+// 是否可用
+func (h Hash) Available() bool {
+    _, ok := hashOIDs[h]
+    return ok
+}
+
+func (h Hash) New() hash.Hash {
+    switch h {
+        case SHA1:
+            return sha1.New()
+        case SHA256:
+            return sha256.New()
+        case SHA384:
+            return sha512.New384()
+        case SHA512:
+            return sha512.New()
+        case SM3:
+            return sm3.New()
+    }
+
+    panic("cryptobin/ocsp: requested hash function is unavailable")
+}
+
+func (h Hash) OID() asn1.ObjectIdentifier {
+    return hashOIDs[h]
+}
+
+func (h Hash) sum(data []byte) []byte {
+    hh := h.New()
+    hh.Write(data)
+
+    return hh.Sum(nil)
+}
+
+func hashFromOID(oid asn1.ObjectIdentifier) Hash {
+    for h, o := range hashOIDs {
+        if o.Equal(oid) {
+            return h
+        }
+    }
+
+    return 0
+}
+
+// ASN.1 结构
+
+type certID struct {
+    HashAlgorithm pkix.AlgorithmIdentifier
+    NameHash      []byte
+    IssuerKeyHash []byte
+    SerialNumber  *big.Int
+}
+
+type ocspRequest struct {
+    TBSRequest tbsRequest
+}
+
+type tbsRequest struct {
+    Version           int              `asn1:"explicit,tag:0,default:0,optional"`
+    RequestorName     pkix.RDNSequence `asn1:"explicit,tag:1,optional"`
+    RequestList       []request
+    RequestExtensions []pkix.Extension `asn1:"explicit,tag:2,optional"`
+}
+
+type request struct {
+    Cert                    certID
+    SingleRequestExtensions []pkix.Extension `asn1:"explicit,tag:0,optional"`
+}
+
+type responseASN1 struct {
+    Status   asn1.Enumerated
+    Response responseBytes `asn1:"explicit,tag:0,optional"`
+}
+
+type responseBytes struct {
+    ResponseType asn1.ObjectIdentifier
+    Response     []byte
+}
+
+type basicResponse struct {
+    TBSResponseData    responseData
+    SignatureAlgorithm pkix.AlgorithmIdentifier
+    Signature          asn1.BitString
+    Certificates       []asn1.RawValue `asn1:"explicit,tag:0,optional"`
+}
+
+type responseData struct {
+    Raw                asn1.RawContent
+    Version            int `asn1:"optional,default:0,explicit,tag:0"`
+    RawResponderID     asn1.RawValue
+    ProducedAt         time.Time `asn1:"generalized"`
+    Responses          []singleResponse
+    ResponseExtensions []pkix.Extension `asn1:"explicit,tag:1,optional"`
+}
+
+type singleResponse struct {
+    CertID           certID
+    Good             asn1.Flag        `asn1:"tag:0,optional"`
+    Revoked          revokedInfo      `asn1:"tag:1,optional"`
+    Unknown          asn1.Flag        `asn1:"tag:2,optional"`
+    ThisUpdate       time.Time        `asn1:"generalized"`
+    NextUpdate       time.Time        `asn1:"generalized,explicit,tag:0,optional"`
+    SingleExtensions []pkix.Extension `asn1:"explicit,tag:1,optional"`
+}
+
+type revokedInfo struct {
+    RevocationTime time.Time       `asn1:"generalized"`
+    Reason         asn1.Enumerated `asn1:"explicit,tag:0,optional"`
+}
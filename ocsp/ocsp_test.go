@@ -0,0 +1,292 @@
+package ocsp
+
+import (
+    "time"
+    "bytes"
+    "testing"
+    "math/big"
+    "crypto"
+    "crypto/rsa"
+    "crypto/rand"
+    "crypto/x509"
+    "crypto/ecdsa"
+    "crypto/ed25519"
+    "crypto/elliptic"
+    "crypto/x509/pkix"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+    sm2X509 "github.com/deatil/go-cryptobin/gm/x509"
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+func newStdCert(t *testing.T, serial int64, name string, pub crypto.PublicKey, parent *x509.Certificate, signer crypto.Signer, isCA bool, usage []x509.ExtKeyUsage) *x509.Certificate {
+    template := &x509.Certificate{
+        SerialNumber:          big.NewInt(serial),
+        Subject:               pkix.Name{CommonName: name},
+        NotBefore:             time.Now().Add(-time.Hour),
+        NotAfter:              time.Now().Add(time.Hour),
+        BasicConstraintsValid: true,
+        IsCA:                  isCA,
+        ExtKeyUsage:           usage,
+    }
+
+    if isCA {
+        template.KeyUsage = x509.KeyUsageCertSign
+    }
+
+    if parent == nil {
+        parent = template
+    }
+
+    der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, signer)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    cert, err := x509.ParseCertificate(der)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return cert
+}
+
+func newSM2Cert(t *testing.T, serial int64, name string, pub *sm2.PublicKey, parent *sm2X509.Certificate, signer crypto.Signer, isCA bool, usage []sm2X509.ExtKeyUsage) *sm2X509.Certificate {
+    template := &sm2X509.Certificate{
+        SerialNumber:          big.NewInt(serial),
+        Subject:               pkix.Name{CommonName: name},
+        NotBefore:             time.Now().Add(-time.Hour),
+        NotAfter:              time.Now().Add(time.Hour),
+        BasicConstraintsValid: true,
+        IsCA:                  isCA,
+        ExtKeyUsage:           usage,
+        SignatureAlgorithm:    sm2X509.SM2WithSM3,
+    }
+
+    if isCA {
+        template.KeyUsage = sm2X509.KeyUsageCertSign
+    }
+
+    if parent == nil {
+        parent = template
+    }
+
+    der, err := sm2X509.CreateCertificate(template, parent, pub, signer)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    cert, err := sm2X509.ParseCertificate(der)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return cert
+}
+
+func Test_Request(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    key, _ := rsa.GenerateKey(rand.Reader, 2048)
+    issuer := newStdCert(t, 1, "issuer", &key.PublicKey, nil, key, true, nil)
+    leaf := newStdCert(t, 1234, "leaf", &key.PublicKey, issuer, key, false, nil)
+
+    for _, h := range []Hash{SHA1, SHA256, SHA384, SHA512, SM3} {
+        der, err := CreateRequest(leaf, issuer, &RequestOptions{
+            Hash:  h,
+            Nonce: []byte("nonce"),
+        })
+        assertError(err, h.String() + "-CreateRequest")
+
+        req, err := ParseRequest(der)
+        assertError(err, h.String() + "-ParseRequest")
+
+        nameHash, keyHash, _ := (&certificate{
+            rawSubject:              issuer.RawSubject,
+            rawSubjectPublicKeyInfo: issuer.RawSubjectPublicKeyInfo,
+        }).issuerHashes(h)
+
+        assertEqual(req.HashAlgorithm, h, h.String() + "-HashAlgorithm")
+        assertEqual(req.SerialNumber.Int64(), int64(1234), h.String() + "-SerialNumber")
+        assertEqual(req.IssuerNameHash, nameHash, h.String() + "-IssuerNameHash")
+        assertEqual(req.IssuerKeyHash, keyHash, h.String() + "-IssuerKeyHash")
+        assertEqual(req.Nonce, []byte("nonce"), h.String() + "-Nonce")
+
+        der2, err := req.Marshal()
+        assertError(err, h.String() + "-Marshal")
+        assertEqual(der2, der, h.String() + "-Marshal")
+    }
+}
+
+func Test_Response(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+    ecKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+    _, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+    issuer := newStdCert(t, 1, "issuer", &rsaKey.PublicKey, nil, rsaKey, true, nil)
+    leaf := newStdCert(t, 100, "leaf", &ecKey.PublicKey, issuer, rsaKey, false, nil)
+
+    ocspUsage := []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning}
+
+    signers := []struct {
+        name      string
+        responder *x509.Certificate
+        key       crypto.Signer
+        algo      SignatureAlgorithm
+    }{
+        {"RSA", issuer, rsaKey, SHA256WithRSA},
+        {"ECDSA", newStdCert(t, 2, "responder", &ecKey.PublicKey, issuer, rsaKey, false, ocspUsage), ecKey, ECDSAWithSHA384},
+        {"Ed25519", newStdCert(t, 3, "responder", edKey.Public(), issuer, rsaKey, false, ocspUsage), edKey, PureEd25519},
+    }
+
+    thisUpdate := time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)
+    revokedAt := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+
+    for _, s := range signers {
+        for _, status := range []int{Good, Revoked, Unknown} {
+            template := Response{
+                Status:           status,
+                SerialNumber:     leaf.SerialNumber,
+                ThisUpdate:       thisUpdate,
+                NextUpdate:       thisUpdate.Add(time.Hour),
+                RevokedAt:        revokedAt,
+                RevocationReason: KeyCompromise,
+                IssuerHash:       SHA256,
+            }
+
+            der, err := CreateResponse(issuer, s.responder, template, s.key)
+            assertError(err, s.name + "-CreateResponse")
+
+            resp, err := ParseResponseForCert(der, leaf, issuer)
+            assertError(err, s.name + "-ParseResponseForCert")
+
+            assertEqual(resp.Status, status, s.name + "-Status")
+            assertEqual(resp.SerialNumber.Int64(), int64(100), s.name + "-SerialNumber")
+            assertEqual(resp.ThisUpdate.Equal(thisUpdate), true, s.name + "-ThisUpdate")
+            assertEqual(resp.NextUpdate.Equal(thisUpdate.Add(time.Hour)), true, s.name + "-NextUpdate")
+            assertEqual(resp.SignatureAlgorithm, s.algo, s.name + "-SignatureAlgorithm")
+            assertEqual(resp.IssuerHash, SHA256, s.name + "-IssuerHash")
+            assertEqual(resp.RawResponderName, s.responder.RawSubject, s.name + "-RawResponderName")
+
+            if status == Revoked {
+                assertEqual(resp.RevokedAt.Equal(revokedAt), true, s.name + "-RevokedAt")
+                assertEqual(resp.RevocationReason, KeyCompromise, s.name + "-RevocationReason")
+            }
+
+            if s.responder == issuer {
+                assertEqual(resp.Certificate, nil, s.name + "-Certificate")
+            } else {
+                assertEqual(resp.Certificate.(*x509.Certificate).Raw, s.responder.Raw, s.name + "-Certificate")
+            }
+        }
+    }
+}
+
+func Test_ResponseSM2(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    caKey, _ := sm2.GenerateKey(rand.Reader)
+    leafKey, _ := sm2.GenerateKey(rand.Reader)
+    responderKey, _ := sm2.GenerateKey(rand.Reader)
+
+    issuer := newSM2Cert(t, 1, "sm2 issuer", &caKey.PublicKey, nil, caKey, true, nil)
+    leaf := newSM2Cert(t, 200, "sm2 leaf", &leafKey.PublicKey, issuer, caKey, false, nil)
+    responder := newSM2Cert(t, 2, "sm2 responder", &responderKey.PublicKey, issuer, caKey, false, []sm2X509.ExtKeyUsage{sm2X509.ExtKeyUsageOCSPSigning})
+
+    der, err := CreateRequest(leaf, issuer, &RequestOptions{Hash: SM3})
+    assertError(err, "CreateRequest")
+
+    req, err := ParseRequest(der)
+    assertError(err, "ParseRequest")
+    assertEqual(req.HashAlgorithm, SM3, "HashAlgorithm")
+
+    for _, r := range []struct {
+        name string
+        cert *sm2X509.Certificate
+        key  *sm2.PrivateKey
+    }{
+        {"issuer", issuer, caKey},
+        {"responder", responder, responderKey},
+    } {
+        template := Response{
+            Status:       Good,
+            SerialNumber: req.SerialNumber,
+            ThisUpdate:   time.Now(),
+            IssuerHash:   req.HashAlgorithm,
+        }
+
+        respDER, err := CreateResponse(issuer, r.cert, template, r.key)
+        assertError(err, r.name + "-CreateResponse")
+
+        resp, err := ParseResponse(respDER, issuer)
+        assertError(err, r.name + "-ParseResponse")
+
+        assertEqual(resp.Status, Good, r.name + "-Status")
+        assertEqual(resp.SignatureAlgorithm, SM2WithSM3, r.name + "-SignatureAlgorithm")
+        assertEqual(resp.IssuerHash, SM3, r.name + "-IssuerHash")
+        assertEqual(resp.SerialNumber.Int64(), int64(200), r.name + "-SerialNumber")
+    }
+}
+
+func Test_ResponseErrors(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+    issuer := newStdCert(t, 1, "issuer", &key.PublicKey, nil, key, true, nil)
+    other := newStdCert(t, 1, "other", &otherKey.PublicKey, nil, otherKey, true, nil)
+    leaf := newStdCert(t, 300, "leaf", &key.PublicKey, issuer, key, false, nil)
+
+    // 没有 OCSP 签名用途的响应者证书
+    noUsage := newStdCert(t, 4, "responder", &otherKey.PublicKey, issuer, key, false, nil)
+
+    template := Response{
+        Status:       Good,
+        SerialNumber: leaf.SerialNumber,
+        ThisUpdate:   time.Now(),
+    }
+
+    der, err := CreateResponse(issuer, issuer, template, key)
+    assertError(err, "CreateResponse")
+
+    _, err = ParseResponse(der, issuer)
+    assertError(err, "ParseResponse")
+
+    // 不需要验证
+    _, err = ParseResponse(der, nil)
+    assertError(err, "ParseResponse nil issuer")
+
+    _, err = ParseResponse(der, other)
+    assertNotErrorNil(err, "ParseResponse other issuer")
+
+    _, err = ParseResponseForCert(der, other, issuer)
+    assertEqual(err, ErrNoResponse, "ParseResponseForCert other cert")
+
+    tampered := bytes.Clone(der)
+    tampered[len(tampered) - 1] ^= 0xff
+
+    _, err = ParseResponse(tampered, issuer)
+    assertNotErrorNil(err, "ParseResponse tampered")
+
+    der, err = CreateResponse(issuer, noUsage, template, otherKey)
+    assertError(err, "CreateResponse noUsage")
+
+    _, err = ParseResponse(der, issuer)
+    assertNotErrorNil(err, "ParseResponse noUsage")
+
+    _, err = ParseResponse(TryLaterErrorResponse, issuer)
+    assertEqual(err, ResponseError{TryLater}, "TryLater")
+
+    // 签名算法和密钥不匹配
+    template.SignatureAlgorithm = SHA256WithRSA
+    _, err = CreateResponse(issuer, issuer, template, key)
+    assertNotErrorNil(err, "SignatureAlgorithm mismatch")
+}
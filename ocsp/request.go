@@ -0,0 +1,155 @@
+package ocsp
+
+import (
+    "errors"
+    "math/big"
+    "encoding/asn1"
+    "crypto/x509/pkix"
+)
+
+// OCSP 请求
+type Request struct {
+    HashAlgorithm  Hash
+    IssuerNameHash []byte
+    IssuerKeyHash  []byte
+    SerialNumber   *big.Int
+
+    // 随机数扩展, 为空时不使用
+    Nonce []byte
+}
+
+// 请求设置
+type RequestOptions struct {
+    // 摘要, 默认为 SHA1
+    Hash Hash
+
+    // 随机数扩展
+    Nonce []byte
+}
+
+func (opts *RequestOptions) hash() Hash {
+    if opts == nil || opts.Hash == 0 {
+        return SHA1
+    }
+
+    return opts.Hash
+}
+
+// 编码请求
+func (req *Request) Marshal() ([]byte, error) {
+    hashAlg := req.HashAlgorithm.OID()
+    if hashAlg == nil {
+        return nil, errors.New("cryptobin/ocsp: unknown hash function")
+    }
+
+    var extensions []pkix.Extension
+    if len(req.Nonce) > 0 {
+        nonce, err := asn1.Marshal(req.Nonce)
+        if err != nil {
+            return nil, err
+        }
+
+        extensions = append(extensions, pkix.Extension{
+            Id:    oidPKIXOCSPNonce,
+            Value: nonce,
+        })
+    }
+
+    return asn1.Marshal(ocspRequest{
+        tbsRequest{
+            Version: 0,
+            RequestList: []request{
+                {
+                    Cert: certID{
+                        HashAlgorithm: pkix.AlgorithmIdentifier{
+                            Algorithm:  hashAlg,
+                            Parameters: asn1.RawValue{Tag: asn1.TagNull},
+                        },
+                        NameHash:      req.IssuerNameHash,
+                        IssuerKeyHash: req.IssuerKeyHash,
+                        SerialNumber:  req.SerialNumber,
+                    },
+                },
+            },
+            RequestExtensions: extensions,
+        },
+    })
+}
+
+// 生成请求
+// cert 及 issuer 可用 [*x509.Certificate | *sm2X509.Certificate]
+func CreateRequest(cert, issuer any, opts *RequestOptions) ([]byte, error) {
+    hashFunc := opts.hash()
+    if !hashFunc.Available() {
+        return nil, errors.New("cryptobin/ocsp: requested hash function is unavailable")
+    }
+
+    c, err := parseCert(cert)
+    if err != nil {
+        return nil, err
+    }
+
+    i, err := parseCert(issuer)
+    if err != nil {
+        return nil, err
+    }
+
+    nameHash, keyHash, err := i.issuerHashes(hashFunc)
+    if err != nil {
+        return nil, err
+    }
+
+    req := &Request{
+        HashAlgorithm:  hashFunc,
+        IssuerNameHash: nameHash,
+        IssuerKeyHash:  keyHash,
+        SerialNumber:   c.serialNumber,
+    }
+
+    if opts != nil {
+        req.Nonce = opts.Nonce
+    }
+
+    return req.Marshal()
+}
+
+// 解析请求, 只支持单个证书的请求
+func ParseRequest(bytes []byte) (*Request, error) {
+    var req ocspRequest
+    rest, err := asn1.Unmarshal(bytes, &req)
+    if err != nil {
+        return nil, err
+    }
+
+    if len(rest) > 0 {
+        return nil, errors.New("cryptobin/ocsp: trailing data in OCSP request")
+    }
+
+    if len(req.TBSRequest.RequestList) == 0 {
+        return nil, errors.New("cryptobin/ocsp: OCSP request contains no request body")
+    }
+
+    innerRequest := req.TBSRequest.RequestList[0]
+
+    hashFunc := hashFromOID(innerRequest.Cert.HashAlgorithm.Algorithm)
+    if hashFunc == 0 {
+        return nil, errors.New("cryptobin/ocsp: OCSP request uses unknown hash function")
+    }
+
+    ret := &Request{
+        HashAlgorithm:  hashFunc,
+        IssuerNameHash: innerRequest.Cert.NameHash,
+        IssuerKeyHash:  innerRequest.Cert.IssuerKeyHash,
+        SerialNumber:   innerRequest.Cert.SerialNumber,
+    }
+
+    for _, ext := range req.TBSRequest.RequestExtensions {
+        if ext.Id.Equal(oidPKIXOCSPNonce) {
+            if _, err := asn1.Unmarshal(ext.Value, &ret.Nonce); err != nil {
+                return nil, errors.New("cryptobin/ocsp: invalid nonce extension")
+            }
+        }
+    }
+
+    return ret, nil
+}
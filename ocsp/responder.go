@@ -0,0 +1,226 @@
+package ocsp
+
+import (
+    "io"
+    "time"
+    "sync"
+    "bytes"
+    "errors"
+    "strings"
+    "math/big"
+    "net/url"
+    "net/http"
+    "crypto"
+    "encoding/base64"
+)
+
+// 请求最大长度
+const maxRequestSize = 1 << 16
+
+var errMalformedGet = errors.New("cryptobin/ocsp: malformed GET request")
+
+// 吊销信息
+type revokedEntry struct {
+    revokedAt time.Time
+    reason    int
+}
+
+// 使用内存吊销列表的 OCSP 响应服务
+type Responder struct {
+    issuer    any
+    responder any
+    signer    crypto.Signer
+    hash      Hash
+
+    issuerNameHash map[Hash][]byte
+    issuerKeyHash  map[Hash][]byte
+
+    mu      sync.RWMutex
+    revoked map[string]revokedEntry
+
+    // 响应有效期, 默认为 1 小时
+    Validity time.Duration
+
+    // 当前时间, 为空时使用 time.Now
+    Now func() time.Time
+}
+
+// 响应服务
+// issuer 为颁发者证书, responderCert 为签名使用的证书, 可以和 issuer 相同
+// 证书可用 [*x509.Certificate | *sm2X509.Certificate]
+func NewResponder(issuer, responderCert any, signer crypto.Signer) (*Responder, error) {
+    i, err := parseCert(issuer)
+    if err != nil {
+        return nil, err
+    }
+
+    if _, err := parseCert(responderCert); err != nil {
+        return nil, err
+    }
+
+    r := &Responder{
+        issuer:         issuer,
+        responder:      responderCert,
+        signer:         signer,
+        issuerNameHash: make(map[Hash][]byte),
+        issuerKeyHash:  make(map[Hash][]byte),
+        revoked:        make(map[string]revokedEntry),
+        Validity:       time.Hour,
+    }
+
+    for h := range hashOIDs {
+        nameHash, keyHash, err := i.issuerHashes(h)
+        if err != nil {
+            return nil, err
+        }
+
+        r.issuerNameHash[h] = nameHash
+        r.issuerKeyHash[h] = keyHash
+    }
+
+    return r, nil
+}
+
+func (r *Responder) now() time.Time {
+    if r.Now == nil {
+        return time.Now()
+    }
+
+    return r.Now()
+}
+
+// 吊销证书
+func (r *Responder) Revoke(serial *big.Int, revokedAt time.Time, reason int) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    r.revoked[serial.String()] = revokedEntry{
+        revokedAt: revokedAt,
+        reason:    reason,
+    }
+}
+
+// 取消吊销, 用于撤销 CertificateHold
+func (r *Responder) Unrevoke(serial *big.Int) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    delete(r.revoked, serial.String())
+}
+
+// 处理 DER 编码的请求, 返回 DER 编码的响应
+func (r *Responder) Respond(reqBytes []byte) ([]byte, error) {
+    req, err := ParseRequest(reqBytes)
+    if err != nil {
+        return MalformedRequestErrorResponse, err
+    }
+
+    now := r.now()
+
+    template := Response{
+        Status:       Good,
+        SerialNumber: req.SerialNumber,
+        IssuerHash:   req.HashAlgorithm,
+        ProducedAt:   now,
+        ThisUpdate:   now,
+        NextUpdate:   now.Add(r.Validity),
+        Nonce:        req.Nonce,
+    }
+
+    // 不是该颁发者的证书时返回未知状态
+    if !bytes.Equal(req.IssuerNameHash, r.issuerNameHash[req.HashAlgorithm]) ||
+        !bytes.Equal(req.IssuerKeyHash, r.issuerKeyHash[req.HashAlgorithm]) {
+        template.Status = Unknown
+    } else {
+        r.mu.RLock()
+        entry, ok := r.revoked[req.SerialNumber.String()]
+        r.mu.RUnlock()
+
+        if ok {
+            template.Status = Revoked
+            template.RevokedAt = entry.revokedAt
+            template.RevocationReason = entry.reason
+        }
+    }
+
+    resp, err := CreateResponse(r.issuer, r.responder, template, r.signer)
+    if err != nil {
+        return InternalErrorErrorResponse, err
+    }
+
+    return resp, nil
+}
+
+// http 服务, 支持 GET 及 POST 请求 (RFC 6960 附录 A)
+func (r *Responder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+    var reqBytes []byte
+    var err error
+
+    switch req.Method {
+        case http.MethodGet:
+            reqBytes, err = decodeGetRequest(req.URL.EscapedPath())
+        case http.MethodPost:
+            if req.Header.Get("Content-Type") != "application/ocsp-request" {
+                http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+                return
+            }
+
+            reqBytes, err = io.ReadAll(io.LimitReader(req.Body, maxRequestSize))
+        default:
+            w.Header().Set("Allow", "GET, POST")
+            http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+            return
+    }
+
+    var resp []byte
+    if err != nil {
+        resp = MalformedRequestErrorResponse
+    } else {
+        resp, _ = r.Respond(reqBytes)
+    }
+
+    w.Header().Set("Content-Type", "application/ocsp-response")
+    w.WriteHeader(http.StatusOK)
+    w.Write(resp)
+}
+
+// GET 请求路径为 url 编码的 base64 编码请求,
+// 请求中的 "/" 没有编码时, 依次尝试各段路径
+func decodeGetRequest(escapedPath string) ([]byte, error) {
+    if i := strings.LastIndex(escapedPath, "/"); i >= 0 {
+        if data, err := decodeBase64Path(escapedPath[i+1:]); err == nil {
+            return data, nil
+        }
+    }
+
+    path := escapedPath
+    for {
+        i := strings.Index(path, "/")
+        if i < 0 {
+            return nil, errMalformedGet
+        }
+
+        path = path[i+1:]
+        if data, err := decodeBase64Path(path); err == nil {
+            return data, nil
+        }
+    }
+}
+
+func decodeBase64Path(path string) ([]byte, error) {
+    path, err := url.PathUnescape(path)
+    if err != nil {
+        return nil, err
+    }
+
+    data, err := base64.StdEncoding.DecodeString(path)
+    if err != nil {
+        return nil, err
+    }
+
+    if _, err := ParseRequest(data); err != nil {
+        return nil, err
+    }
+
+    return data, nil
+}
@@ -0,0 +1,93 @@
+package ocsp
+
+import (
+    "io"
+    "time"
+    "bytes"
+    "testing"
+    "net/url"
+    "net/http"
+    "net/http/httptest"
+    "crypto/rand"
+    "encoding/base64"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+    sm2X509 "github.com/deatil/go-cryptobin/gm/x509"
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+func Test_Responder(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    caKey, _ := sm2.GenerateKey(rand.Reader)
+    responderKey, _ := sm2.GenerateKey(rand.Reader)
+
+    issuer := newSM2Cert(t, 1, "sm2 issuer", &caKey.PublicKey, nil, caKey, true, nil)
+    responderCert := newSM2Cert(t, 2, "sm2 responder", &responderKey.PublicKey, issuer, caKey, false, []sm2X509.ExtKeyUsage{sm2X509.ExtKeyUsageOCSPSigning})
+    good := newSM2Cert(t, 10, "good", &caKey.PublicKey, issuer, caKey, false, nil)
+    revoked := newSM2Cert(t, 11, "revoked", &caKey.PublicKey, issuer, caKey, false, nil)
+
+    otherKey, _ := sm2.GenerateKey(rand.Reader)
+    other := newSM2Cert(t, 1, "other issuer", &otherKey.PublicKey, nil, otherKey, true, nil)
+
+    responder, err := NewResponder(issuer, responderCert, responderKey)
+    assertError(err, "NewResponder")
+
+    revokedAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+    responder.Revoke(revoked.SerialNumber, revokedAt, KeyCompromise)
+
+    server := httptest.NewServer(responder)
+    defer server.Close()
+
+    post := func(reqDER []byte) []byte {
+        resp, err := http.Post(server.URL, "application/ocsp-request", bytes.NewReader(reqDER))
+        assertError(err, "Post")
+        defer resp.Body.Close()
+
+        assertEqual(resp.Header.Get("Content-Type"), "application/ocsp-response", "Content-Type")
+
+        data, _ := io.ReadAll(resp.Body)
+        return data
+    }
+
+    get := func(reqDER []byte) []byte {
+        resp, err := http.Get(server.URL + "/ocsp/" + url.PathEscape(base64.StdEncoding.EncodeToString(reqDER)))
+        assertError(err, "Get")
+        defer resp.Body.Close()
+
+        data, _ := io.ReadAll(resp.Body)
+        return data
+    }
+
+    for name, fetch := range map[string]func([]byte) []byte{"POST": post, "GET": get} {
+        reqDER, _ := CreateRequest(good, issuer, &RequestOptions{Nonce: []byte("nonce-" + name)})
+        resp, err := ParseResponseForCert(fetch(reqDER), good, issuer)
+        assertError(err, name + "-good")
+        assertEqual(resp.Status, Good, name + "-good Status")
+        assertEqual(resp.Nonce, []byte("nonce-" + name), name + "-good Nonce")
+
+        reqDER, _ = CreateRequest(revoked, issuer, &RequestOptions{Hash: SM3})
+        resp, err = ParseResponseForCert(fetch(reqDER), revoked, issuer)
+        assertError(err, name + "-revoked")
+        assertEqual(resp.Status, Revoked, name + "-revoked Status")
+        assertEqual(resp.RevokedAt.Equal(revokedAt), true, name + "-revoked RevokedAt")
+        assertEqual(resp.RevocationReason, KeyCompromise, name + "-revoked RevocationReason")
+
+        // 其他颁发者的证书
+        reqDER, _ = CreateRequest(good, other, nil)
+        resp, err = ParseResponse(fetch(reqDER), nil)
+        assertError(err, name + "-unknown")
+        assertEqual(resp.Status, Unknown, name + "-unknown Status")
+
+        _, err = ParseResponse(fetch([]byte("bad request")), issuer)
+        assertEqual(err, ResponseError{Malformed}, name + "-malformed")
+    }
+
+    responder.Unrevoke(revoked.SerialNumber)
+
+    reqDER, _ := CreateRequest(revoked, issuer, nil)
+    resp, err := ParseResponse(post(reqDER), issuer)
+    assertError(err, "unrevoked")
+    assertEqual(resp.Status, Good, "unrevoked Status")
+}
@@ -0,0 +1,397 @@
+package ocsp
+
+import (
+    "time"
+    "bytes"
+    "errors"
+    "math/big"
+    "crypto"
+    "crypto/rand"
+    "encoding/asn1"
+    "crypto/x509/pkix"
+)
+
+// OCSP 响应
+type Response struct {
+    Raw []byte
+
+    // 证书状态 [Good | Revoked | Unknown]
+    Status       int
+    SerialNumber *big.Int
+
+    ProducedAt time.Time
+    ThisUpdate time.Time
+    NextUpdate time.Time
+    RevokedAt  time.Time
+
+    // 吊销原因
+    RevocationReason int
+
+    // 响应中的响应者证书
+    // 可用 [*x509.Certificate | *sm2X509.Certificate]
+    Certificate any
+
+    // 签名数据
+    TBSResponseData    []byte
+    Signature          []byte
+    SignatureAlgorithm SignatureAlgorithm
+
+    // 证书 ID 使用的摘要
+    IssuerHash Hash
+
+    // 响应者 ID, 名称或者公钥的 SHA1 摘要
+    RawResponderName []byte
+    ResponderKeyHash []byte
+
+    // 解析出的响应扩展
+    Extensions []pkix.Extension
+
+    // 生成响应时添加的扩展
+    ExtraExtensions []pkix.Extension
+
+    // 随机数扩展
+    Nonce []byte
+
+    issuerNameHash []byte
+    issuerKeyHash  []byte
+}
+
+// 使用颁发者证书或者响应者证书验证签名
+// 可用 [*x509.Certificate | *sm2X509.Certificate]
+func (resp *Response) CheckSignatureFrom(issuer any) error {
+    i, err := parseCert(issuer)
+    if err != nil {
+        return err
+    }
+
+    return checkSignature(resp.SignatureAlgorithm, resp.TBSResponseData, resp.Signature, i.publicKey)
+}
+
+// 解析响应, 响应中只能有一个证书状态
+// issuer 不为空时验证签名
+func ParseResponse(bytes []byte, issuer any) (*Response, error) {
+    return ParseResponseForCert(bytes, nil, issuer)
+}
+
+// 解析响应中 cert 的状态, cert 为空时响应中只能有一个证书状态
+// issuer 不为空时验证签名及颁发者
+// cert 及 issuer 可用 [*x509.Certificate | *sm2X509.Certificate]
+func ParseResponseForCert(bytes []byte, cert, issuer any) (*Response, error) {
+    var resp responseASN1
+    rest, err := asn1.Unmarshal(bytes, &resp)
+    if err != nil {
+        return nil, err
+    }
+
+    if len(rest) > 0 {
+        return nil, errors.New("cryptobin/ocsp: trailing data in OCSP response")
+    }
+
+    if status := ResponseStatus(resp.Status); status != Success {
+        return nil, ResponseError{status}
+    }
+
+    if !resp.Response.ResponseType.Equal(oidPKIXOCSPBasic) {
+        return nil, errors.New("cryptobin/ocsp: bad OCSP response type")
+    }
+
+    var basicResp basicResponse
+    rest, err = asn1.Unmarshal(resp.Response.Response, &basicResp)
+    if err != nil {
+        return nil, err
+    }
+
+    if len(rest) > 0 {
+        return nil, errors.New("cryptobin/ocsp: trailing data in OCSP response")
+    }
+
+    responses := basicResp.TBSResponseData.Responses
+    if len(responses) == 0 {
+        return nil, ErrNoResponse
+    }
+
+    var singleResp singleResponse
+    if cert == nil {
+        if len(responses) > 1 {
+            return nil, errors.New("cryptobin/ocsp: OCSP response contains multiple certificates")
+        }
+
+        singleResp = responses[0]
+    } else {
+        c, err := parseCert(cert)
+        if err != nil {
+            return nil, err
+        }
+
+        match := false
+        for _, r := range responses {
+            if r.CertID.SerialNumber.Cmp(c.serialNumber) == 0 {
+                singleResp = r
+                match = true
+                break
+            }
+        }
+
+        if !match {
+            return nil, ErrNoResponse
+        }
+    }
+
+    ret := &Response{
+        Raw:                bytes,
+        TBSResponseData:    basicResp.TBSResponseData.Raw,
+        Signature:          basicResp.Signature.RightAlign(),
+        SignatureAlgorithm: signatureAlgorithmFromAI(basicResp.SignatureAlgorithm),
+        Extensions:         singleResp.SingleExtensions,
+        SerialNumber:       singleResp.CertID.SerialNumber,
+        ProducedAt:         basicResp.TBSResponseData.ProducedAt,
+        ThisUpdate:         singleResp.ThisUpdate,
+        NextUpdate:         singleResp.NextUpdate,
+        IssuerHash:         hashFromOID(singleResp.CertID.HashAlgorithm.Algorithm),
+
+        issuerNameHash: singleResp.CertID.NameHash,
+        issuerKeyHash:  singleResp.CertID.IssuerKeyHash,
+    }
+
+    if err := parseResponderID(ret, basicResp.TBSResponseData.RawResponderID); err != nil {
+        return nil, err
+    }
+
+    for _, ext := range basicResp.TBSResponseData.ResponseExtensions {
+        ret.Extensions = append(ret.Extensions, ext)
+
+        if ext.Id.Equal(oidPKIXOCSPNonce) {
+            if _, err := asn1.Unmarshal(ext.Value, &ret.Nonce); err != nil {
+                return nil, errors.New("cryptobin/ocsp: invalid nonce extension")
+            }
+        }
+    }
+
+    for _, ext := range singleResp.SingleExtensions {
+        if ext.Critical {
+            return nil, errors.New("cryptobin/ocsp: unsupported critical extension")
+        }
+    }
+
+    switch {
+        case bool(singleResp.Good):
+            ret.Status = Good
+        case bool(singleResp.Unknown):
+            ret.Status = Unknown
+        default:
+            ret.Status = Revoked
+            ret.RevokedAt = singleResp.Revoked.RevocationTime
+            ret.RevocationReason = int(singleResp.Revoked.Reason)
+    }
+
+    if len(basicResp.Certificates) > 0 {
+        ret.Certificate, err = parseCertificateLike(issuer, basicResp.Certificates[0].FullBytes)
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    if issuer != nil {
+        if err := ret.checkIssuer(issuer); err != nil {
+            return nil, err
+        }
+
+        // 有响应者证书时, 响应者证书需要由颁发者签发
+        signer := issuer
+        if ret.Certificate != nil {
+            if err := checkResponderCert(ret.Certificate, issuer); err != nil {
+                return nil, err
+            }
+
+            signer = ret.Certificate
+        }
+
+        if err := ret.CheckSignatureFrom(signer); err != nil {
+            return nil, err
+        }
+    }
+
+    return ret, nil
+}
+
+// 检查证书 ID 中的颁发者摘要
+func (resp *Response) checkIssuer(issuer any) error {
+    i, err := parseCert(issuer)
+    if err != nil {
+        return err
+    }
+
+    if !resp.IssuerHash.Available() {
+        return errors.New("cryptobin/ocsp: OCSP response uses unknown hash function")
+    }
+
+    nameHash, keyHash, err := i.issuerHashes(resp.IssuerHash)
+    if err != nil {
+        return err
+    }
+
+    if !bytes.Equal(nameHash, resp.issuerNameHash) ||
+        !bytes.Equal(keyHash, resp.issuerKeyHash) {
+        return ErrIssuerMatch
+    }
+
+    return nil
+}
+
+func parseResponderID(resp *Response, rawResponderID asn1.RawValue) error {
+    switch rawResponderID.Tag {
+        case 1:
+            // byName
+            var rdn pkix.RDNSequence
+            if rest, err := asn1.Unmarshal(rawResponderID.Bytes, &rdn); err != nil || len(rest) != 0 {
+                return errors.New("cryptobin/ocsp: invalid responder name")
+            }
+
+            resp.RawResponderName = rawResponderID.Bytes
+        case 2:
+            // byKey
+            if rest, err := asn1.Unmarshal(rawResponderID.Bytes, &resp.ResponderKeyHash); err != nil || len(rest) != 0 {
+                return errors.New("cryptobin/ocsp: invalid responder key hash")
+            }
+        default:
+            return errors.New("cryptobin/ocsp: invalid responder id tag")
+    }
+
+    return nil
+}
+
+// 生成响应
+// issuer 为颁发者证书, responderCert 为响应者证书, 和颁发者证书不同时添加到响应中
+// template 中的 Status, SerialNumber, ProducedAt, ThisUpdate, NextUpdate, RevokedAt, RevocationReason,
+// IssuerHash, SignatureAlgorithm, Nonce 及 ExtraExtensions 会被使用
+// 证书可用 [*x509.Certificate | *sm2X509.Certificate]
+func CreateResponse(issuer, responderCert any, template Response, priv crypto.Signer) ([]byte, error) {
+    i, err := parseCert(issuer)
+    if err != nil {
+        return nil, err
+    }
+
+    r, err := parseCert(responderCert)
+    if err != nil {
+        return nil, err
+    }
+
+    if template.IssuerHash == 0 {
+        template.IssuerHash = SHA1
+    }
+
+    if !template.IssuerHash.Available() {
+        return nil, errors.New("cryptobin/ocsp: unsupported issuer hash algorithm")
+    }
+
+    nameHash, keyHash, err := i.issuerHashes(template.IssuerHash)
+    if err != nil {
+        return nil, err
+    }
+
+    innerResponse := singleResponse{
+        CertID: certID{
+            HashAlgorithm: pkix.AlgorithmIdentifier{
+                Algorithm:  template.IssuerHash.OID(),
+                Parameters: asn1.RawValue{Tag: asn1.TagNull},
+            },
+            NameHash:      nameHash,
+            IssuerKeyHash: keyHash,
+            SerialNumber:  template.SerialNumber,
+        },
+        ThisUpdate:       template.ThisUpdate.UTC(),
+        NextUpdate:       template.NextUpdate.UTC(),
+        SingleExtensions: template.ExtraExtensions,
+    }
+
+    switch template.Status {
+        case Good:
+            innerResponse.Good = true
+        case Unknown:
+            innerResponse.Unknown = true
+        case Revoked:
+            innerResponse.Revoked = revokedInfo{
+                RevocationTime: template.RevokedAt.UTC(),
+                Reason:         asn1.Enumerated(template.RevocationReason),
+            }
+        default:
+            return nil, errors.New("cryptobin/ocsp: unsupported certificate status")
+    }
+
+    // 使用响应者名称作为响应者 ID
+    rawResponderID := asn1.RawValue{
+        Class:      asn1.ClassContextSpecific,
+        Tag:        1,
+        IsCompound: true,
+        Bytes:      r.rawSubject,
+    }
+
+    var responseExtensions []pkix.Extension
+    if len(template.Nonce) > 0 {
+        nonce, err := asn1.Marshal(template.Nonce)
+        if err != nil {
+            return nil, err
+        }
+
+        responseExtensions = append(responseExtensions, pkix.Extension{
+            Id:    oidPKIXOCSPNonce,
+            Value: nonce,
+        })
+    }
+
+    producedAt := template.ProducedAt
+    if producedAt.IsZero() {
+        producedAt = time.Now()
+    }
+
+    tbsResponseData := responseData{
+        Version:            0,
+        RawResponderID:     rawResponderID,
+        ProducedAt:         producedAt.Truncate(time.Minute).UTC(),
+        Responses:          []singleResponse{innerResponse},
+        ResponseExtensions: responseExtensions,
+    }
+
+    tbsResponseDataDER, err := asn1.Marshal(tbsResponseData)
+    if err != nil {
+        return nil, err
+    }
+
+    _, hashFunc, signatureAlgorithm, err := signingParamsForPublicKey(priv.Public(), template.SignatureAlgorithm)
+    if err != nil {
+        return nil, err
+    }
+
+    signature, err := signData(rand.Reader, priv, hashFunc, tbsResponseDataDER)
+    if err != nil {
+        return nil, err
+    }
+
+    response := basicResponse{
+        TBSResponseData:    tbsResponseData,
+        SignatureAlgorithm: signatureAlgorithm,
+        Signature: asn1.BitString{
+            Bytes:     signature,
+            BitLength: 8 * len(signature),
+        },
+    }
+
+    if !bytes.Equal(r.raw, i.raw) {
+        response.Certificates = []asn1.RawValue{
+            {FullBytes: r.raw},
+        }
+    }
+
+    responseDER, err := asn1.Marshal(response)
+    if err != nil {
+        return nil, err
+    }
+
+    return asn1.Marshal(responseASN1{
+        Status: asn1.Enumerated(Success),
+        Response: responseBytes{
+            ResponseType: oidPKIXOCSPBasic,
+            Response:     responseDER,
+        },
+    })
+}
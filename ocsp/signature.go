@@ -0,0 +1,218 @@
+package ocsp
+
+import (
+    "io"
+    "errors"
+    "crypto"
+    "crypto/rsa"
+    "crypto/ecdsa"
+    "crypto/ed25519"
+    "crypto/elliptic"
+    "encoding/asn1"
+    "crypto/x509/pkix"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+)
+
+// 签名算法
+type SignatureAlgorithm int
+
+const (
+    UnknownSignatureAlgorithm SignatureAlgorithm = iota
+
+    SHA1WithRSA
+    SHA256WithRSA
+    SHA384WithRSA
+    SHA512WithRSA
+    ECDSAWithSHA1
+    ECDSAWithSHA256
+    ECDSAWithSHA384
+    ECDSAWithSHA512
+    PureEd25519
+    SM2WithSM3
+)
+
+const (
+    keyRSA = 1 + iota
+    keyECDSA
+    keyEd25519
+    keySM2
+)
+
+var signatureAlgorithmDetails = []struct {
+    algo       SignatureAlgorithm
+    name       string
+    oid        asn1.ObjectIdentifier
+    keyType    int
+    hash       crypto.Hash
+    nullParams bool
+}{
+    {SHA1WithRSA, "SHA1-RSA", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 5}, keyRSA, crypto.SHA1, true},
+    {SHA256WithRSA, "SHA256-RSA", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}, keyRSA, crypto.SHA256, true},
+    {SHA384WithRSA, "SHA384-RSA", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 12}, keyRSA, crypto.SHA384, true},
+    {SHA512WithRSA, "SHA512-RSA", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 13}, keyRSA, crypto.SHA512, true},
+    {ECDSAWithSHA1, "ECDSA-SHA1", asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 1}, keyECDSA, crypto.SHA1, false},
+    {ECDSAWithSHA256, "ECDSA-SHA256", asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}, keyECDSA, crypto.SHA256, false},
+    {ECDSAWithSHA384, "ECDSA-SHA384", asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 3}, keyECDSA, crypto.SHA384, false},
+    {ECDSAWithSHA512, "ECDSA-SHA512", asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 4}, keyECDSA, crypto.SHA512, false},
+    {PureEd25519, "Ed25519", asn1.ObjectIdentifier{1, 3, 101, 112}, keyEd25519, crypto.Hash(0), false},
+    {SM2WithSM3, "SM2-SM3", asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 501}, keySM2, crypto.Hash(0), false},
+}
+
+func (algo SignatureAlgorithm) String() string {
+    for _, details := range signatureAlgorithmDetails {
+        if details.algo == algo {
+            return details.name
+        }
+    }
+
+    return "unknown signature algorithm"
+}
+
+func signatureAlgorithmFromAI(ai pkix.AlgorithmIdentifier) SignatureAlgorithm {
+    for _, details := range signatureAlgorithmDetails {
+        if ai.Algorithm.Equal(details.oid) {
+            return details.algo
+        }
+    }
+
+    return UnknownSignatureAlgorithm
+}
+
+// 公钥类型, SM2 曲线的 ecdsa 公钥作为 SM2 公钥
+func publicKeyType(pub crypto.PublicKey) (int, crypto.PublicKey) {
+    switch k := pub.(type) {
+        case *rsa.PublicKey:
+            return keyRSA, k
+        case *ecdsa.PublicKey:
+            if k.Curve == sm2.P256() {
+                return keySM2, &sm2.PublicKey{
+                    Curve: k.Curve,
+                    X:     k.X,
+                    Y:     k.Y,
+                }
+            }
+
+            return keyECDSA, k
+        case ed25519.PublicKey:
+            return keyEd25519, k
+        case *sm2.PublicKey:
+            return keySM2, k
+    }
+
+    return 0, nil
+}
+
+// 签名参数, requested 为 0 时根据公钥选择
+func signingParamsForPublicKey(pub crypto.PublicKey, requested SignatureAlgorithm) (SignatureAlgorithm, crypto.Hash, pkix.AlgorithmIdentifier, error) {
+    var ai pkix.AlgorithmIdentifier
+
+    keyType, key := publicKeyType(pub)
+
+    algo := requested
+    if algo == UnknownSignatureAlgorithm {
+        switch keyType {
+            case keyRSA:
+                algo = SHA256WithRSA
+            case keyECDSA:
+                switch key.(*ecdsa.PublicKey).Curve {
+                    case elliptic.P384():
+                        algo = ECDSAWithSHA384
+                    case elliptic.P521():
+                        algo = ECDSAWithSHA512
+                    default:
+                        algo = ECDSAWithSHA256
+                }
+            case keyEd25519:
+                algo = PureEd25519
+            case keySM2:
+                algo = SM2WithSM3
+            default:
+                return 0, 0, ai, errors.New("cryptobin/ocsp: only RSA, ECDSA, Ed25519 and SM2 keys supported")
+        }
+    }
+
+    for _, details := range signatureAlgorithmDetails {
+        if details.algo != algo {
+            continue
+        }
+
+        if details.keyType != keyType {
+            return 0, 0, ai, errors.New("cryptobin/ocsp: requested SignatureAlgorithm does not match private key type")
+        }
+
+        ai.Algorithm = details.oid
+        if details.nullParams {
+            ai.Parameters = asn1.NullRawValue
+        }
+
+        return algo, details.hash, ai, nil
+    }
+
+    return 0, 0, ai, errors.New("cryptobin/ocsp: unknown SignatureAlgorithm")
+}
+
+// 签名
+func signData(rand io.Reader, signer crypto.Signer, hashFunc crypto.Hash, data []byte) ([]byte, error) {
+    if hashFunc == crypto.Hash(0) {
+        // Ed25519 签名原始数据, SM2 使用默认 uid 签名
+        switch signer.Public().(type) {
+            case *sm2.PublicKey:
+                return signer.Sign(rand, data, nil)
+        }
+
+        return signer.Sign(rand, data, hashFunc)
+    }
+
+    h := hashFunc.New()
+    h.Write(data)
+
+    return signer.Sign(rand, h.Sum(nil), hashFunc)
+}
+
+// 验证签名
+func checkSignature(algo SignatureAlgorithm, signed, signature []byte, pub crypto.PublicKey) error {
+    keyType, key := publicKeyType(pub)
+
+    for _, details := range signatureAlgorithmDetails {
+        if details.algo != algo {
+            continue
+        }
+
+        if details.keyType != keyType {
+            return errors.New("cryptobin/ocsp: signature algorithm does not match public key type")
+        }
+
+        digest := signed
+        if details.hash != crypto.Hash(0) {
+            if !details.hash.Available() {
+                return errors.New("cryptobin/ocsp: hash function is unavailable")
+            }
+
+            h := details.hash.New()
+            h.Write(signed)
+            digest = h.Sum(nil)
+        }
+
+        switch k := key.(type) {
+            case *rsa.PublicKey:
+                return rsa.VerifyPKCS1v15(k, details.hash, digest, signature)
+            case *ecdsa.PublicKey:
+                if !ecdsa.VerifyASN1(k, digest, signature) {
+                    return errors.New("cryptobin/ocsp: ECDSA verification failure")
+                }
+            case ed25519.PublicKey:
+                if !ed25519.Verify(k, digest, signature) {
+                    return errors.New("cryptobin/ocsp: Ed25519 verification failure")
+                }
+            case *sm2.PublicKey:
+                if !k.Verify(digest, signature, nil) {
+                    return errors.New("cryptobin/ocsp: SM2 verification failure")
+                }
+        }
+
+        return nil
+    }
+
+    return errors.New("cryptobin/ocsp: unsupported signature algorithm")
+}
@@ -0,0 +1,212 @@
+package pbes2
+
+import (
+    "io"
+    "errors"
+    "crypto/cipher"
+    "encoding/asn1"
+
+    "github.com/deatil/go-cryptobin/cipher/gost"
+)
+
+// CTR-ACPKM 模式加密参数
+// Gost3412-15-Encryption-Parameters ::= SEQUENCE {
+//     ukm OCTET STRING }
+type ctrACPKMParams struct {
+    Ukm []byte
+}
+
+// CTR-ACPKM 模式加密
+// 分组长度一半的 ukm 作为 iv, 每 sectionSize 字节更换一次密钥
+type CipherCTRACPKM struct {
+    cipherFunc   func(key []byte) (cipher.Block, error)
+    keySize      int
+    blockSize    int
+    sectionSize  int
+    identifier   asn1.ObjectIdentifier
+    hasKeyLength bool
+    needPassBmp  bool
+}
+
+// 值大小
+func (this CipherCTRACPKM) KeySize() int {
+    return this.keySize
+}
+
+// oid
+func (this CipherCTRACPKM) OID() asn1.ObjectIdentifier {
+    return this.identifier
+}
+
+// 是否有 KeyLength
+func (this CipherCTRACPKM) HasKeyLength() bool {
+    return this.hasKeyLength
+}
+
+// 密码是否需要 Bmp 处理
+func (this CipherCTRACPKM) NeedPasswordBmpString() bool {
+    return this.needPassBmp
+}
+
+// 设置分段大小
+func (this CipherCTRACPKM) WithSectionSize(sectionSize int) CipherCTRACPKM {
+    this.sectionSize = sectionSize
+
+    return this
+}
+
+// 加密
+func (this CipherCTRACPKM) Encrypt(rand io.Reader, key, plaintext []byte) ([]byte, []byte, error) {
+    // 随机生成 ukm
+    ukm := make([]byte, this.blockSize/2)
+    if _, err := io.ReadFull(rand, ukm); err != nil {
+        return nil, nil, errors.New("pkcs/cipher: failed to generate ukm: " + err.Error())
+    }
+
+    encrypted, err := this.xorKeyStream(key, ukm, plaintext)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    // 编码参数
+    paramBytes, err := asn1.Marshal(ctrACPKMParams{
+        Ukm: ukm,
+    })
+    if err != nil {
+        return nil, nil, err
+    }
+
+    return encrypted, paramBytes, nil
+}
+
+// 解密
+func (this CipherCTRACPKM) Decrypt(key, params, ciphertext []byte) ([]byte, error) {
+    var param ctrACPKMParams
+    if _, err := asn1.Unmarshal(params, &param); err != nil {
+        return nil, errors.New("pkcs/cipher: invalid ukm parameters")
+    }
+
+    if len(param.Ukm) < this.blockSize/2 {
+        return nil, errors.New("pkcs/cipher: invalid ukm size")
+    }
+
+    return this.xorKeyStream(key, param.Ukm[:this.blockSize/2], ciphertext)
+}
+
+// R 1323565.1.017 / RFC 8645 CTR-ACPKM
+func (this CipherCTRACPKM) xorKeyStream(key, iv, src []byte) ([]byte, error) {
+    block, err := this.cipherFunc(key)
+    if err != nil {
+        return nil, errors.New("pkcs/cipher: failed to create cipher: " + err.Error())
+    }
+
+    bs := this.blockSize
+    if this.sectionSize <= 0 || this.sectionSize%bs != 0 {
+        return nil, errors.New("pkcs/cipher: invalid section size")
+    }
+
+    // 计数器为 iv || 0, 低半部分递增
+    ctr := make([]byte, bs)
+    copy(ctr, iv)
+
+    stream := make([]byte, bs)
+    dst := make([]byte, len(src))
+
+    for i := 0; i < len(src); i += bs {
+        if i > 0 && i%this.sectionSize == 0 {
+            block, err = this.cipherFunc(acpkmKey(block, len(key)))
+            if err != nil {
+                return nil, errors.New("pkcs/cipher: failed to create cipher: " + err.Error())
+            }
+        }
+
+        block.Encrypt(stream, ctr)
+
+        end := i + bs
+        if end > len(src) {
+            end = len(src)
+        }
+
+        for j := i; j < end; j++ {
+            dst[j] = src[j] ^ stream[j-i]
+        }
+
+        for j := bs - 1; j >= bs/2; j-- {
+            ctr[j]++
+            if ctr[j] != 0 {
+                break
+            }
+        }
+    }
+
+    return dst, nil
+}
+
+// ACPKM 密钥变换, 加密常量 D = 0x80 | 0x81 | ... | 0x9F
+func acpkmKey(block cipher.Block, keySize int) []byte {
+    bs := block.BlockSize()
+
+    d := make([]byte, keySize)
+    for i := range d {
+        d[i] = byte(0x80 + i)
+    }
+
+    key := make([]byte, keySize)
+    for i := 0; i < keySize; i += bs {
+        block.Encrypt(key[i:i+bs], d[i:i+bs])
+    }
+
+    return key
+}
+
+// Magma 使用 GOST 28147-89 TC26 sbox, 密钥及数据为大端字节序
+type magmaCipher struct {
+    block cipher.Block
+}
+
+func newMagmaCipher(key []byte) (cipher.Block, error) {
+    if len(key) != 32 {
+        return nil, errors.New("pkcs/cipher: invalid magma key size")
+    }
+
+    k := make([]byte, 32)
+    for i := 0; i < 32; i += 4 {
+        k[i], k[i+1], k[i+2], k[i+3] = key[i+3], key[i+2], key[i+1], key[i]
+    }
+
+    block, err := gost.NewCipher(k, gost.TC26Sbox)
+    if err != nil {
+        return nil, err
+    }
+
+    return &magmaCipher{block}, nil
+}
+
+func (this *magmaCipher) BlockSize() int {
+    return gost.BlockSize
+}
+
+func (this *magmaCipher) Encrypt(dst, src []byte) {
+    var b [gost.BlockSize]byte
+    reverseBlock(b[:], src)
+
+    this.block.Encrypt(b[:], b[:])
+    reverseBlock(dst, b[:])
+}
+
+func (this *magmaCipher) Decrypt(dst, src []byte) {
+    var b [gost.BlockSize]byte
+    reverseBlock(b[:], src)
+
+    this.block.Decrypt(b[:], b[:])
+    reverseBlock(dst, b[:])
+}
+
+func reverseBlock(dst, src []byte) {
+    var b [gost.BlockSize]byte
+    for i := 0; i < gost.BlockSize; i++ {
+        b[i] = src[gost.BlockSize-1-i]
+    }
+
+    copy(dst, b[:])
+}
@@ -7,6 +7,10 @@ import (
     "encoding/asn1"
 
     "github.com/deatil/go-cryptobin/cipher/sm4"
+    "github.com/deatil/go-cryptobin/cipher/aria"
+    "github.com/deatil/go-cryptobin/cipher/gost"
+    "github.com/deatil/go-cryptobin/cipher/camellia"
+    "github.com/deatil/go-cryptobin/cipher/kuznyechik"
     cryptobin_rc2 "github.com/deatil/go-cryptobin/cipher/rc2"
     cryptobin_rc5 "github.com/deatil/go-cryptobin/cipher/rc5"
 )
@@ -48,6 +52,45 @@ var (
     oidSM4CFB8    = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 104, 6}
     oidSM4GCM     = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 104, 8}
     oidSM4CCM     = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 104, 9}
+
+    oidAria128ECB = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 1}
+    oidAria128CBC = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 2}
+    oidAria128OFB = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 4}
+    oidAria128CFB = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 3}
+    oidAria128GCM = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 34}
+    oidAria128CCM = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 37}
+
+    oidAria192ECB = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 6}
+    oidAria192CBC = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 7}
+    oidAria192OFB = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 9}
+    oidAria192CFB = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 8}
+    oidAria192GCM = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 35}
+    oidAria192CCM = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 38}
+
+    oidAria256ECB = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 11}
+    oidAria256CBC = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 12}
+    oidAria256OFB = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 14}
+    oidAria256CFB = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 13}
+    oidAria256GCM = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 36}
+    oidAria256CCM = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 39}
+
+    oidCamellia128ECB = asn1.ObjectIdentifier{0, 3, 4401, 5, 3, 1, 9, 1}
+    oidCamellia128CBC = asn1.ObjectIdentifier{1, 2, 392, 200011, 61, 1, 1, 1, 2}
+    oidCamellia128OFB = asn1.ObjectIdentifier{0, 3, 4401, 5, 3, 1, 9, 3}
+    oidCamellia128CFB = asn1.ObjectIdentifier{0, 3, 4401, 5, 3, 1, 9, 4}
+
+    oidCamellia192ECB = asn1.ObjectIdentifier{0, 3, 4401, 5, 3, 1, 9, 21}
+    oidCamellia192CBC = asn1.ObjectIdentifier{1, 2, 392, 200011, 61, 1, 1, 1, 3}
+    oidCamellia192OFB = asn1.ObjectIdentifier{0, 3, 4401, 5, 3, 1, 9, 23}
+    oidCamellia192CFB = asn1.ObjectIdentifier{0, 3, 4401, 5, 3, 1, 9, 24}
+
+    oidCamellia256ECB = asn1.ObjectIdentifier{0, 3, 4401, 5, 3, 1, 9, 41}
+    oidCamellia256CBC = asn1.ObjectIdentifier{1, 2, 392, 200011, 61, 1, 1, 1, 4}
+    oidCamellia256OFB = asn1.ObjectIdentifier{0, 3, 4401, 5, 3, 1, 9, 43}
+    oidCamellia256CFB = asn1.ObjectIdentifier{0, 3, 4401, 5, 3, 1, 9, 44}
+
+    oidGostKuznyechikCTRACPKM = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 5, 2, 1}
+    oidGostMagmaCTRACPKM      = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 5, 1, 1}
 )
 
 var (
@@ -386,6 +429,333 @@ var SM4CCMIv = CipherCCMIv{
     hasKeyLength: false,
 }
 
+// ==========
+
+// Aria128ECB is the 128-bit key Aria cipher in ECB mode.
+var Aria128ECB = CipherECB{
+    cipherFunc:   aria.NewCipher,
+    keySize:      16,
+    blockSize:    aria.BlockSize,
+    identifier:   oidAria128ECB,
+    hasKeyLength: false,
+}
+// Aria128CBC is the 128-bit key Aria cipher in CBC mode.
+var Aria128CBC = CipherCBC{
+    cipherFunc:   aria.NewCipher,
+    keySize:      16,
+    blockSize:    aria.BlockSize,
+    identifier:   oidAria128CBC,
+    hasKeyLength: false,
+}
+// Aria128OFB is the 128-bit key Aria cipher in OFB mode.
+var Aria128OFB = CipherOFB{
+    cipherFunc:   aria.NewCipher,
+    keySize:      16,
+    blockSize:    aria.BlockSize,
+    identifier:   oidAria128OFB,
+    hasKeyLength: false,
+}
+// Aria128CFB is the 128-bit key Aria cipher in CFB mode.
+var Aria128CFB = CipherCFB{
+    cipherFunc:   aria.NewCipher,
+    keySize:      16,
+    blockSize:    aria.BlockSize,
+    identifier:   oidAria128CFB,
+    hasKeyLength: false,
+}
+// Aria128GCM is the 128-bit key Aria cipher in GCM mode.
+var Aria128GCM = CipherGCM{
+    cipherFunc:   aria.NewCipher,
+    keySize:      16,
+    nonceSize:    12,
+    identifier:   oidAria128GCM,
+    hasKeyLength: false,
+}
+// Aria128GCMIv is the 128-bit key Aria cipher in GCM mode.
+var Aria128GCMIv = CipherGCMIv{
+    cipherFunc:   aria.NewCipher,
+    keySize:      16,
+    nonceSize:    12,
+    identifier:   oidAria128GCM,
+    hasKeyLength: false,
+}
+// Aria128CCM is the 128-bit key Aria cipher in CCM mode.
+var Aria128CCM = CipherCCM{
+    cipherFunc:   aria.NewCipher,
+    keySize:      16,
+    nonceSize:    12,
+    identifier:   oidAria128CCM,
+    hasKeyLength: false,
+}
+// Aria128CCMIv is the 128-bit key Aria cipher in CCM mode.
+var Aria128CCMIv = CipherCCMIv{
+    cipherFunc:   aria.NewCipher,
+    keySize:      16,
+    nonceSize:    12,
+    identifier:   oidAria128CCM,
+    hasKeyLength: false,
+}
+
+// ==========
+
+// Aria192ECB is the 192-bit key Aria cipher in ECB mode.
+var Aria192ECB = CipherECB{
+    cipherFunc:   aria.NewCipher,
+    keySize:      24,
+    blockSize:    aria.BlockSize,
+    identifier:   oidAria192ECB,
+    hasKeyLength: false,
+}
+// Aria192CBC is the 192-bit key Aria cipher in CBC mode.
+var Aria192CBC = CipherCBC{
+    cipherFunc:   aria.NewCipher,
+    keySize:      24,
+    blockSize:    aria.BlockSize,
+    identifier:   oidAria192CBC,
+    hasKeyLength: false,
+}
+// Aria192OFB is the 192-bit key Aria cipher in OFB mode.
+var Aria192OFB = CipherOFB{
+    cipherFunc:   aria.NewCipher,
+    keySize:      24,
+    blockSize:    aria.BlockSize,
+    identifier:   oidAria192OFB,
+    hasKeyLength: false,
+}
+// Aria192CFB is the 192-bit key Aria cipher in CFB mode.
+var Aria192CFB = CipherCFB{
+    cipherFunc:   aria.NewCipher,
+    keySize:      24,
+    blockSize:    aria.BlockSize,
+    identifier:   oidAria192CFB,
+    hasKeyLength: false,
+}
+// Aria192GCM is the 192-bit key Aria cipher in GCM mode.
+var Aria192GCM = CipherGCM{
+    cipherFunc:   aria.NewCipher,
+    keySize:      24,
+    nonceSize:    12,
+    identifier:   oidAria192GCM,
+    hasKeyLength: false,
+}
+// Aria192GCMIv is the 192-bit key Aria cipher in GCM mode.
+var Aria192GCMIv = CipherGCMIv{
+    cipherFunc:   aria.NewCipher,
+    keySize:      24,
+    nonceSize:    12,
+    identifier:   oidAria192GCM,
+    hasKeyLength: false,
+}
+// Aria192CCM is the 192-bit key Aria cipher in CCM mode.
+var Aria192CCM = CipherCCM{
+    cipherFunc:   aria.NewCipher,
+    keySize:      24,
+    nonceSize:    12,
+    identifier:   oidAria192CCM,
+    hasKeyLength: false,
+}
+// Aria192CCMIv is the 192-bit key Aria cipher in CCM mode.
+var Aria192CCMIv = CipherCCMIv{
+    cipherFunc:   aria.NewCipher,
+    keySize:      24,
+    nonceSize:    12,
+    identifier:   oidAria192CCM,
+    hasKeyLength: false,
+}
+
+// ==========
+
+// Aria256ECB is the 256-bit key Aria cipher in ECB mode.
+var Aria256ECB = CipherECB{
+    cipherFunc:   aria.NewCipher,
+    keySize:      32,
+    blockSize:    aria.BlockSize,
+    identifier:   oidAria256ECB,
+    hasKeyLength: false,
+}
+// Aria256CBC is the 256-bit key Aria cipher in CBC mode.
+var Aria256CBC = CipherCBC{
+    cipherFunc:   aria.NewCipher,
+    keySize:      32,
+    blockSize:    aria.BlockSize,
+    identifier:   oidAria256CBC,
+    hasKeyLength: false,
+}
+// Aria256OFB is the 256-bit key Aria cipher in OFB mode.
+var Aria256OFB = CipherOFB{
+    cipherFunc:   aria.NewCipher,
+    keySize:      32,
+    blockSize:    aria.BlockSize,
+    identifier:   oidAria256OFB,
+    hasKeyLength: false,
+}
+// Aria256CFB is the 256-bit key Aria cipher in CFB mode.
+var Aria256CFB = CipherCFB{
+    cipherFunc:   aria.NewCipher,
+    keySize:      32,
+    blockSize:    aria.BlockSize,
+    identifier:   oidAria256CFB,
+    hasKeyLength: false,
+}
+// Aria256GCM is the 256-bit key Aria cipher in GCM mode.
+var Aria256GCM = CipherGCM{
+    cipherFunc:   aria.NewCipher,
+    keySize:      32,
+    nonceSize:    12,
+    identifier:   oidAria256GCM,
+    hasKeyLength: false,
+}
+// Aria256GCMIv is the 256-bit key Aria cipher in GCM mode.
+var Aria256GCMIv = CipherGCMIv{
+    cipherFunc:   aria.NewCipher,
+    keySize:      32,
+    nonceSize:    12,
+    identifier:   oidAria256GCM,
+    hasKeyLength: false,
+}
+// Aria256CCM is the 256-bit key Aria cipher in CCM mode.
+var Aria256CCM = CipherCCM{
+    cipherFunc:   aria.NewCipher,
+    keySize:      32,
+    nonceSize:    12,
+    identifier:   oidAria256CCM,
+    hasKeyLength: false,
+}
+// Aria256CCMIv is the 256-bit key Aria cipher in CCM mode.
+var Aria256CCMIv = CipherCCMIv{
+    cipherFunc:   aria.NewCipher,
+    keySize:      32,
+    nonceSize:    12,
+    identifier:   oidAria256CCM,
+    hasKeyLength: false,
+}
+
+// ==========
+
+// Camellia128ECB is the 128-bit key Camellia cipher in ECB mode.
+var Camellia128ECB = CipherECB{
+    cipherFunc:   camellia.NewCipher,
+    keySize:      16,
+    blockSize:    camellia.BlockSize,
+    identifier:   oidCamellia128ECB,
+    hasKeyLength: false,
+}
+// Camellia128CBC is the 128-bit key Camellia cipher in CBC mode.
+var Camellia128CBC = CipherCBC{
+    cipherFunc:   camellia.NewCipher,
+    keySize:      16,
+    blockSize:    camellia.BlockSize,
+    identifier:   oidCamellia128CBC,
+    hasKeyLength: false,
+}
+// Camellia128OFB is the 128-bit key Camellia cipher in OFB mode.
+var Camellia128OFB = CipherOFB{
+    cipherFunc:   camellia.NewCipher,
+    keySize:      16,
+    blockSize:    camellia.BlockSize,
+    identifier:   oidCamellia128OFB,
+    hasKeyLength: false,
+}
+// Camellia128CFB is the 128-bit key Camellia cipher in CFB mode.
+var Camellia128CFB = CipherCFB{
+    cipherFunc:   camellia.NewCipher,
+    keySize:      16,
+    blockSize:    camellia.BlockSize,
+    identifier:   oidCamellia128CFB,
+    hasKeyLength: false,
+}
+
+// ==========
+
+// Camellia192ECB is the 192-bit key Camellia cipher in ECB mode.
+var Camellia192ECB = CipherECB{
+    cipherFunc:   camellia.NewCipher,
+    keySize:      24,
+    blockSize:    camellia.BlockSize,
+    identifier:   oidCamellia192ECB,
+    hasKeyLength: false,
+}
+// Camellia192CBC is the 192-bit key Camellia cipher in CBC mode.
+var Camellia192CBC = CipherCBC{
+    cipherFunc:   camellia.NewCipher,
+    keySize:      24,
+    blockSize:    camellia.BlockSize,
+    identifier:   oidCamellia192CBC,
+    hasKeyLength: false,
+}
+// Camellia192OFB is the 192-bit key Camellia cipher in OFB mode.
+var Camellia192OFB = CipherOFB{
+    cipherFunc:   camellia.NewCipher,
+    keySize:      24,
+    blockSize:    camellia.BlockSize,
+    identifier:   oidCamellia192OFB,
+    hasKeyLength: false,
+}
+// Camellia192CFB is the 192-bit key Camellia cipher in CFB mode.
+var Camellia192CFB = CipherCFB{
+    cipherFunc:   camellia.NewCipher,
+    keySize:      24,
+    blockSize:    camellia.BlockSize,
+    identifier:   oidCamellia192CFB,
+    hasKeyLength: false,
+}
+
+// ==========
+
+// Camellia256ECB is the 256-bit key Camellia cipher in ECB mode.
+var Camellia256ECB = CipherECB{
+    cipherFunc:   camellia.NewCipher,
+    keySize:      32,
+    blockSize:    camellia.BlockSize,
+    identifier:   oidCamellia256ECB,
+    hasKeyLength: false,
+}
+// Camellia256CBC is the 256-bit key Camellia cipher in CBC mode.
+var Camellia256CBC = CipherCBC{
+    cipherFunc:   camellia.NewCipher,
+    keySize:      32,
+    blockSize:    camellia.BlockSize,
+    identifier:   oidCamellia256CBC,
+    hasKeyLength: false,
+}
+// Camellia256OFB is the 256-bit key Camellia cipher in OFB mode.
+var Camellia256OFB = CipherOFB{
+    cipherFunc:   camellia.NewCipher,
+    keySize:      32,
+    blockSize:    camellia.BlockSize,
+    identifier:   oidCamellia256OFB,
+    hasKeyLength: false,
+}
+// Camellia256CFB is the 256-bit key Camellia cipher in CFB mode.
+var Camellia256CFB = CipherCFB{
+    cipherFunc:   camellia.NewCipher,
+    keySize:      32,
+    blockSize:    camellia.BlockSize,
+    identifier:   oidCamellia256CFB,
+    hasKeyLength: false,
+}
+
+// ==========
+
+// GostKuznyechikCTRACPKM is the 256-bit key Kuznyechik cipher in CTR-ACPKM mode.
+var GostKuznyechikCTRACPKM = CipherCTRACPKM{
+    cipherFunc:   kuznyechik.NewCipher,
+    keySize:      32,
+    blockSize:    kuznyechik.BlockSize,
+    sectionSize:  4096,
+    identifier:   oidGostKuznyechikCTRACPKM,
+    hasKeyLength: false,
+}
+// GostMagmaCTRACPKM is the 256-bit key Magma cipher in CTR-ACPKM mode.
+var GostMagmaCTRACPKM = CipherCTRACPKM{
+    cipherFunc:   newMagmaCipher,
+    keySize:      32,
+    blockSize:    gost.BlockSize,
+    sectionSize:  1024,
+    identifier:   oidGostMagmaCTRACPKM,
+    hasKeyLength: false,
+}
+
 func init() {
     // des
     AddCipher(oidDESCBC, func() Cipher {
@@ -486,4 +856,114 @@ func init() {
     AddCipher(oidSM4CCM, func() Cipher {
         return SM4CCM
     })
+
+    // aria128
+    AddCipher(oidAria128ECB, func() Cipher {
+        return Aria128ECB
+    })
+    AddCipher(oidAria128CBC, func() Cipher {
+        return Aria128CBC
+    })
+    AddCipher(oidAria128OFB, func() Cipher {
+        return Aria128OFB
+    })
+    AddCipher(oidAria128CFB, func() Cipher {
+        return Aria128CFB
+    })
+    AddCipher(oidAria128GCM, func() Cipher {
+        return Aria128GCM
+    })
+    AddCipher(oidAria128CCM, func() Cipher {
+        return Aria128CCM
+    })
+
+    // aria192
+    AddCipher(oidAria192ECB, func() Cipher {
+        return Aria192ECB
+    })
+    AddCipher(oidAria192CBC, func() Cipher {
+        return Aria192CBC
+    })
+    AddCipher(oidAria192OFB, func() Cipher {
+        return Aria192OFB
+    })
+    AddCipher(oidAria192CFB, func() Cipher {
+        return Aria192CFB
+    })
+    AddCipher(oidAria192GCM, func() Cipher {
+        return Aria192GCM
+    })
+    AddCipher(oidAria192CCM, func() Cipher {
+        return Aria192CCM
+    })
+
+    // aria256
+    AddCipher(oidAria256ECB, func() Cipher {
+        return Aria256ECB
+    })
+    AddCipher(oidAria256CBC, func() Cipher {
+        return Aria256CBC
+    })
+    AddCipher(oidAria256OFB, func() Cipher {
+        return Aria256OFB
+    })
+    AddCipher(oidAria256CFB, func() Cipher {
+        return Aria256CFB
+    })
+    AddCipher(oidAria256GCM, func() Cipher {
+        return Aria256GCM
+    })
+    AddCipher(oidAria256CCM, func() Cipher {
+        return Aria256CCM
+    })
+
+    // camellia128
+    AddCipher(oidCamellia128ECB, func() Cipher {
+        return Camellia128ECB
+    })
+    AddCipher(oidCamellia128CBC, func() Cipher {
+        return Camellia128CBC
+    })
+    AddCipher(oidCamellia128OFB, func() Cipher {
+        return Camellia128OFB
+    })
+    AddCipher(oidCamellia128CFB, func() Cipher {
+        return Camellia128CFB
+    })
+
+    // camellia192
+    AddCipher(oidCamellia192ECB, func() Cipher {
+        return Camellia192ECB
+    })
+    AddCipher(oidCamellia192CBC, func() Cipher {
+        return Camellia192CBC
+    })
+    AddCipher(oidCamellia192OFB, func() Cipher {
+        return Camellia192OFB
+    })
+    AddCipher(oidCamellia192CFB, func() Cipher {
+        return Camellia192CFB
+    })
+
+    // camellia256
+    AddCipher(oidCamellia256ECB, func() Cipher {
+        return Camellia256ECB
+    })
+    AddCipher(oidCamellia256CBC, func() Cipher {
+        return Camellia256CBC
+    })
+    AddCipher(oidCamellia256OFB, func() Cipher {
+        return Camellia256OFB
+    })
+    AddCipher(oidCamellia256CFB, func() Cipher {
+        return Camellia256CFB
+    })
+
+    // gost
+    AddCipher(oidGostKuznyechikCTRACPKM, func() Cipher {
+        return GostKuznyechikCTRACPKM
+    })
+    AddCipher(oidGostMagmaCTRACPKM, func() Cipher {
+        return GostMagmaCTRACPKM
+    })
 }
@@ -3,6 +3,7 @@ package pbes2
 import (
     "testing"
     "crypto/rand"
+    "encoding/hex"
 
     cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
 )
@@ -66,6 +67,83 @@ func Test_Ciphers(t *testing.T) {
     test_cipher(t, SM4CCM, "SM4CCM", []byte("ghdfrt5tssdfrt5t"))
     test_cipher(t, SM4CCMIv, "SM4CCMIv", []byte("ghdfrt5tssdfrt5t"))
 
+    test_cipher(t, Aria128ECB, "Aria128ECB", []byte("ssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria128CBC, "Aria128CBC", []byte("ssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria128OFB, "Aria128OFB", []byte("ssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria128CFB, "Aria128CFB", []byte("ssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria128GCM, "Aria128GCM", []byte("ssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria128GCMIv, "Aria128GCMIv", []byte("ssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria128CCM, "Aria128CCM", []byte("ssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria128CCMIv, "Aria128CCMIv", []byte("ssdfrt5tssdfrt5t"))
+
+    test_cipher(t, Aria192ECB, "Aria192ECB", []byte("ssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria192CBC, "Aria192CBC", []byte("ssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria192OFB, "Aria192OFB", []byte("ssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria192CFB, "Aria192CFB", []byte("ssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria192GCM, "Aria192GCM", []byte("ssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria192GCMIv, "Aria192GCMIv", []byte("ssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria192CCM, "Aria192CCM", []byte("ssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria192CCMIv, "Aria192CCMIv", []byte("ssdfrt5tssdfrt5tssdfrt5t"))
+
+    test_cipher(t, Aria256ECB, "Aria256ECB", []byte("ghiolkjmssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria256CBC, "Aria256CBC", []byte("ghiolkjmssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria256OFB, "Aria256OFB", []byte("ghiolkjmssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria256CFB, "Aria256CFB", []byte("ghiolkjmssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria256GCM, "Aria256GCM", []byte("ghiolkjmssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria256GCMIv, "Aria256GCMIv", []byte("ghiolkjmssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria256CCM, "Aria256CCM", []byte("ghiolkjmssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, Aria256CCMIv, "Aria256CCMIv", []byte("ghiolkjmssdfrt5tssdfrt5tssdfrt5t"))
+
+    test_cipher(t, Camellia128ECB, "Camellia128ECB", []byte("ssdfrt5tssdfrt5t"))
+    test_cipher(t, Camellia128CBC, "Camellia128CBC", []byte("ssdfrt5tssdfrt5t"))
+    test_cipher(t, Camellia128OFB, "Camellia128OFB", []byte("ssdfrt5tssdfrt5t"))
+    test_cipher(t, Camellia128CFB, "Camellia128CFB", []byte("ssdfrt5tssdfrt5t"))
+
+    test_cipher(t, Camellia192ECB, "Camellia192ECB", []byte("ssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, Camellia192CBC, "Camellia192CBC", []byte("ssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, Camellia192OFB, "Camellia192OFB", []byte("ssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, Camellia192CFB, "Camellia192CFB", []byte("ssdfrt5tssdfrt5tssdfrt5t"))
+
+    test_cipher(t, Camellia256ECB, "Camellia256ECB", []byte("ghiolkjmssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, Camellia256CBC, "Camellia256CBC", []byte("ghiolkjmssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, Camellia256OFB, "Camellia256OFB", []byte("ghiolkjmssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, Camellia256CFB, "Camellia256CFB", []byte("ghiolkjmssdfrt5tssdfrt5tssdfrt5t"))
+
+    test_cipher(t, GostKuznyechikCTRACPKM, "GostKuznyechikCTRACPKM", []byte("ghiolkjmssdfrt5tssdfrt5tssdfrt5t"))
+    test_cipher(t, GostMagmaCTRACPKM, "GostMagmaCTRACPKM", []byte("ghiolkjmssdfrt5tssdfrt5tssdfrt5t"))
 }
 
 
+
+func fromHex(s string) []byte {
+    h, _ := hex.DecodeString(s)
+    return h
+}
+
+// GOST R 34.13-2015 及 RFC 8645 测试数据
+func Test_CTRACPKM(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    key := fromHex("8899aabbccddeeff0011223344556677fedcba98765432100123456789abcdef")
+    iv := fromHex("1234567890abcef0")
+    plaintext := fromHex("1122334455667700ffeeddccbbaa998800112233445566778899aabbcceeff0a112233445566778899aabbcceeff0a002233445566778899aabbcceeff0a0011")
+
+    // 未达到分段大小时和 CTR 模式相同
+    ciphertext, err := GostKuznyechikCTRACPKM.xorKeyStream(key, iv, plaintext)
+    assertError(err, "Test_CTRACPKM-Kuznyechik-CTR")
+    assertEqual(ciphertext, fromHex("f195d8bec10ed1dbd57b5fa240bda1b885eee733f6a13e5df33ce4b33c45dee4a5eae88be6356ed3d5e877f13564a3a5cb91fab1f20cbab6d1c6d15820bdba73"), "Test_CTRACPKM-Kuznyechik-CTR")
+
+    ciphertext, err = GostKuznyechikCTRACPKM.WithSectionSize(32).xorKeyStream(key, iv, plaintext)
+    assertError(err, "Test_CTRACPKM-Kuznyechik-ACPKM")
+    assertEqual(ciphertext[32:48], fromHex("4bceeb8f646f4c55001706275e85e800"), "Test_CTRACPKM-Kuznyechik-ACPKM")
+
+    // Magma
+    key = fromHex("ffeeddccbbaa99887766554433221100f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff")
+    iv = fromHex("12345678")
+    plaintext = fromHex("92def06b3c130a59db54c704f8189d204a98fb2e67a8024c8912409b17b57e41")
+
+    ciphertext, err = GostMagmaCTRACPKM.xorKeyStream(key, iv, plaintext)
+    assertError(err, "Test_CTRACPKM-Magma-CTR")
+    assertEqual(ciphertext, fromHex("4e98110c97b7b93c3e250d93d6e85d69136d868807b2dbef568eb680ab52a12d"), "Test_CTRACPKM-Magma-CTR")
+}
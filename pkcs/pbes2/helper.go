@@ -52,6 +52,51 @@ var CipherMap = map[string]Cipher{
     "SM4GCMIv": SM4GCMIv,
     "SM4CCM":   SM4CCM,
     "SM4CCMIv": SM4CCMIv,
+
+    "Aria128ECB":    Aria128ECB,
+    "Aria128CBC":    Aria128CBC,
+    "Aria128OFB":    Aria128OFB,
+    "Aria128CFB":    Aria128CFB,
+    "Aria128GCM":    Aria128GCM,
+    "Aria128GCMIv":  Aria128GCMIv,
+    "Aria128CCM":    Aria128CCM,
+    "Aria128CCMIv":  Aria128CCMIv,
+
+    "Aria192ECB":    Aria192ECB,
+    "Aria192CBC":    Aria192CBC,
+    "Aria192OFB":    Aria192OFB,
+    "Aria192CFB":    Aria192CFB,
+    "Aria192GCM":    Aria192GCM,
+    "Aria192GCMIv":  Aria192GCMIv,
+    "Aria192CCM":    Aria192CCM,
+    "Aria192CCMIv":  Aria192CCMIv,
+
+    "Aria256ECB":    Aria256ECB,
+    "Aria256CBC":    Aria256CBC,
+    "Aria256OFB":    Aria256OFB,
+    "Aria256CFB":    Aria256CFB,
+    "Aria256GCM":    Aria256GCM,
+    "Aria256GCMIv":  Aria256GCMIv,
+    "Aria256CCM":    Aria256CCM,
+    "Aria256CCMIv":  Aria256CCMIv,
+
+    "Camellia128ECB": Camellia128ECB,
+    "Camellia128CBC": Camellia128CBC,
+    "Camellia128OFB": Camellia128OFB,
+    "Camellia128CFB": Camellia128CFB,
+
+    "Camellia192ECB": Camellia192ECB,
+    "Camellia192CBC": Camellia192CBC,
+    "Camellia192OFB": Camellia192OFB,
+    "Camellia192CFB": Camellia192CFB,
+
+    "Camellia256ECB": Camellia256ECB,
+    "Camellia256CBC": Camellia256CBC,
+    "Camellia256OFB": Camellia256OFB,
+    "Camellia256CFB": Camellia256CFB,
+
+    "GostKuznyechikCTRACPKM": GostKuznyechikCTRACPKM,
+    "GostMagmaCTRACPKM":      GostMagmaCTRACPKM,
 }
 
 // 获取 Cipher 类型
@@ -14,6 +14,8 @@ type (
     PBKDF2Opts = pbes2.PBKDF2Opts
     // Scrypt 配置
     ScryptOpts = pbes2.ScryptOpts
+    // Argon2 配置
+    Argon2Opts = pbes2.Argon2Opts
 
     // KDF 设置接口
     KeyKDFOpts  = pbes2.KDFOpts
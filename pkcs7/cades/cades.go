@@ -0,0 +1,181 @@
+package cades
+
+import (
+    "errors"
+    "strconv"
+    "math/big"
+    "crypto/x509/pkix"
+    "encoding/asn1"
+
+    "github.com/deatil/go-cryptobin/pkcs7/sign"
+)
+
+/**
+ * CAdES 高级电子签名 (ETSI EN 319 122 / TS 101 733)
+ *
+ * CAdES-BES: signingCertificateV2 绑定签名证书
+ * CAdES-T:   添加签名时间戳
+ * CAdES-LT:  添加证书链及 CRL/OCSP 验证数据
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+
+var (
+    oidSigningCertificate      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 12}
+    oidSigningCertificateV2    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 47}
+    oidSignaturePolicyID       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 15}
+    oidSignatureTimeStampToken = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 14}
+    oidCertValues              = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 23}
+    oidRevocationValues        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 24}
+    oidAttributeSigningTime    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+
+    oidSPQetsURI = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 5, 1}
+
+    oidOCSPBasic = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 1}
+)
+
+// 签名级别
+type Level int
+
+const (
+    LevelBES Level = 1 + iota
+    LevelT
+    LevelLT
+)
+
+func (l Level) String() string {
+    switch l {
+        case LevelBES:
+            return "CAdES-BES"
+        case LevelT:
+            return "CAdES-T"
+        case LevelLT:
+            return "CAdES-LT"
+    }
+
+    return "unknown level: " + strconv.Itoa(int(l))
+}
+
+// 签名策略 (CAdES-EPES)
+type Policy struct {
+    // 策略 oid
+    ID asn1.ObjectIdentifier
+
+    // 策略文档摘要方式及摘要值
+    HashAlgorithm asn1.ObjectIdentifier
+    HashValue     []byte
+
+    // 策略文档地址, 可选
+    URI string
+}
+
+// 摘要方式
+var hashs = map[string]sign.SignHash{}
+
+// 添加摘要方式
+func AddHash(hash sign.SignHash) {
+    hashs[hash.OID().String()] = hash
+}
+
+func getHash(oid asn1.ObjectIdentifier) (sign.SignHash, error) {
+    hash, ok := hashs[oid.String()]
+    if !ok {
+        return nil, errors.New("pkcs7/cades: unsupported hash algorithm: " + oid.String())
+    }
+
+    return hash, nil
+}
+
+func init() {
+    AddHash(sign.SignHashWithSHA1)
+    AddHash(sign.SignHashWithSHA224)
+    AddHash(sign.SignHashWithSHA256)
+    AddHash(sign.SignHashWithSHA384)
+    AddHash(sign.SignHashWithSHA512)
+    AddHash(sign.SignHashWithSHA3_256)
+    AddHash(sign.SignHashWithSHA3_384)
+    AddHash(sign.SignHashWithSHA3_512)
+    AddHash(sign.SignHashWithSM3)
+}
+
+// RFC 5035
+// ESSCertIDv2 ::= SEQUENCE {
+//     hashAlgorithm AlgorithmIdentifier DEFAULT {algorithm id-sha256},
+//     certHash Hash,
+//     issuerSerial IssuerSerial OPTIONAL }
+type essCertIDv2 struct {
+    HashAlgorithm pkix.AlgorithmIdentifier `asn1:"optional"`
+    CertHash      []byte
+    IssuerSerial  issuerSerial `asn1:"optional"`
+}
+
+type signingCertificateV2 struct {
+    Certs    []essCertIDv2
+    Policies asn1.RawValue `asn1:"optional"`
+}
+
+// RFC 2634
+// ESSCertID ::= SEQUENCE {
+//     certHash Hash, -- SHA1
+//     issuerSerial IssuerSerial OPTIONAL }
+type essCertID struct {
+    CertHash     []byte
+    IssuerSerial issuerSerial `asn1:"optional"`
+}
+
+type signingCertificate struct {
+    Certs    []essCertID
+    Policies asn1.RawValue `asn1:"optional"`
+}
+
+// IssuerSerial ::= SEQUENCE {
+//     issuer GeneralNames,
+//     serialNumber CertificateSerialNumber }
+type issuerSerial struct {
+    Issuer       asn1.RawValue
+    SerialNumber *big.Int
+}
+
+// SignaturePolicyId ::= SEQUENCE {
+//     sigPolicyId SigPolicyId,
+//     sigPolicyHash SigPolicyHash,
+//     sigPolicyQualifiers SEQUENCE SIZE (1..MAX) OF SigPolicyQualifierInfo OPTIONAL }
+type signaturePolicyID struct {
+    SigPolicyID         asn1.ObjectIdentifier
+    SigPolicyHash       otherHashAlgAndValue
+    SigPolicyQualifiers []sigPolicyQualifierInfo `asn1:"optional,omitempty"`
+}
+
+type otherHashAlgAndValue struct {
+    HashAlgorithm pkix.AlgorithmIdentifier
+    HashValue     []byte
+}
+
+type sigPolicyQualifierInfo struct {
+    SigPolicyQualifierID asn1.ObjectIdentifier
+    SigQualifier         asn1.RawValue
+}
+
+// RevocationValues ::= SEQUENCE {
+//     crlVals [0] SEQUENCE OF CertificateList OPTIONAL,
+//     ocspVals [1] SEQUENCE OF BasicOCSPResponse OPTIONAL,
+//     otherRevVals [2] OtherRevVals OPTIONAL }
+type revocationValues struct {
+    CRLVals      []asn1.RawValue `asn1:"optional,omitempty,explicit,tag:0"`
+    OCSPVals     []asn1.RawValue `asn1:"optional,omitempty,explicit,tag:1"`
+    OtherRevVals asn1.RawValue   `asn1:"optional,explicit,tag:2"`
+}
+
+// OCSPResponse ::= SEQUENCE {
+//     responseStatus OCSPResponseStatus,
+//     responseBytes [0] EXPLICIT ResponseBytes OPTIONAL }
+type ocspResponse struct {
+    Status   asn1.Enumerated
+    Response ocspResponseBytes `asn1:"explicit,tag:0,optional"`
+}
+
+type ocspResponseBytes struct {
+    ResponseType asn1.ObjectIdentifier
+    Response     []byte
+}
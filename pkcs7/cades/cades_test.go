@@ -0,0 +1,509 @@
+package cades
+
+import (
+    "time"
+    "errors"
+    "testing"
+    "math/big"
+    "crypto"
+    "crypto/rand"
+    "crypto/x509"
+    "crypto/ecdsa"
+    "crypto/elliptic"
+    "crypto/x509/pkix"
+    "encoding/asn1"
+
+    "github.com/deatil/go-cryptobin/ocsp"
+    "github.com/deatil/go-cryptobin/pkcs7/sign"
+    "github.com/deatil/go-cryptobin/pkcs7/timestamp"
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+var testPolicy = &Policy{
+    ID:            asn1.ObjectIdentifier{1, 2, 3, 4, 5},
+    HashAlgorithm: sign.SignHashWithSHA256.OID(),
+    HashValue:     sign.SignHashWithSHA256.Sum([]byte("policy document")),
+    URI:           "https://example.com/policy.pdf",
+}
+
+func newTestCert(t *testing.T, serial int64, name string, pub crypto.PublicKey, parent *x509.Certificate, signer crypto.Signer, isCA bool, usage []x509.ExtKeyUsage) *x509.Certificate {
+    template := &x509.Certificate{
+        SerialNumber:          big.NewInt(serial),
+        Subject:               pkix.Name{CommonName: name},
+        NotBefore:             time.Now().Add(-time.Hour),
+        NotAfter:              time.Now().Add(time.Hour),
+        BasicConstraintsValid: true,
+        IsCA:                  isCA,
+        ExtKeyUsage:           usage,
+    }
+
+    if isCA {
+        template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+    }
+
+    if parent == nil {
+        parent = template
+    }
+
+    der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, signer)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    cert, err := x509.ParseCertificate(der)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return cert
+}
+
+type testPKI struct {
+    caKey      *ecdsa.PrivateKey
+    caCert     *x509.Certificate
+    signerKey  *ecdsa.PrivateKey
+    signerCert *x509.Certificate
+    roots      *x509.CertPool
+    tsa        *timestamp.TSA
+}
+
+func newTestPKI(t *testing.T) testPKI {
+    caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    caCert := newTestCert(t, 1, "ca", &caKey.PublicKey, nil, caKey, true, nil)
+
+    tsaKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    tsaCert := newTestCert(t, 2, "tsa", &tsaKey.PublicKey, caCert, caKey, false, []x509.ExtKeyUsage{
+        x509.ExtKeyUsageTimeStamping,
+    })
+
+    signerKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    signerCert := newTestCert(t, 3, "signer", &signerKey.PublicKey, caCert, caKey, false, nil)
+
+    tsa, err := timestamp.NewTSA(tsaCert, tsaKey, sign.KeySignWithEcdsaSHA256, asn1.ObjectIdentifier{1, 2, 3, 4, 1})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    tsa.Parents = []*x509.Certificate{caCert}
+
+    roots := x509.NewCertPool()
+    roots.AddCert(caCert)
+
+    return testPKI{
+        caKey:      caKey,
+        caCert:     caCert,
+        signerKey:  signerKey,
+        signerCert: signerCert,
+        roots:      roots,
+        tsa:        tsa,
+    }
+}
+
+func (this testPKI) token(signature []byte) ([]byte, error) {
+    req, err := timestamp.NewRequest(signature, nil)
+    if err != nil {
+        return nil, err
+    }
+
+    return this.tsa.CreateToken(req)
+}
+
+func (this testPKI) newSignedData(t *testing.T, data []byte, opts SignOpts) *sign.SignedData {
+    signedData, err := sign.NewSignedData(data)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if opts.KeySign == nil {
+        opts.KeySign = sign.KeySignWithEcdsaSHA256
+    }
+
+    err = AddSigner(signedData, this.signerCert, this.signerKey, []*x509.Certificate{this.caCert}, opts)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return signedData
+}
+
+func (this testPKI) crl(t *testing.T, revoked []pkix.RevokedCertificate) []byte {
+    crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+        Number:              big.NewInt(1),
+        ThisUpdate:          time.Now().Add(-time.Minute),
+        NextUpdate:          time.Now().Add(time.Hour),
+        RevokedCertificates: revoked,
+    }, this.caCert, this.caKey)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return crl
+}
+
+func (this testPKI) ocsp(t *testing.T, revoked bool) []byte {
+    responder, err := ocsp.NewResponder(this.caCert, this.caCert, this.caKey)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if revoked {
+        responder.Revoke(this.signerCert.SerialNumber, time.Now().Add(-time.Minute), ocsp.KeyCompromise)
+    }
+
+    req, err := ocsp.CreateRequest(this.signerCert, this.caCert, nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    resp, err := responder.Respond(req)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return resp
+}
+
+func parseSigned(t *testing.T, signedData *sign.SignedData) *sign.PKCS7 {
+    signed, err := signedData.Finish()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    p7, err := sign.Parse(signed)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return p7
+}
+
+func Test_BES(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+
+    pki := newTestPKI(t)
+    data := []byte("cades test data")
+
+    p7 := parseSigned(t, pki.newSignedData(t, data, SignOpts{}))
+
+    results, err := Verify(p7, VerifyOpts{Roots: pki.roots})
+    assertError(err, "Test_BES-Verify")
+    assertEqual(len(results), 1, "Test_BES-results")
+    assertEqual(results[0].Level, LevelBES, "Test_BES-Level")
+    assertEqual(results[0].Level.String(), "CAdES-BES", "Test_BES-Level")
+    assertEqual(results[0].Signer.Raw, pki.signerCert.Raw, "Test_BES-Signer")
+    assertEqual(results[0].Policy == nil, true, "Test_BES-Policy")
+    assertEqual(results[0].SigningTime.IsZero(), false, "Test_BES-SigningTime")
+
+    // 修改内容
+    p7.Content = []byte("cades test datA")
+    _, err = Verify(p7, VerifyOpts{Roots: pki.roots})
+    assertNotErrorNil(err, "Test_BES-Verify-tampered")
+
+    // 不同的签名证书
+    otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    otherCert := newTestCert(t, 3, "other", &otherKey.PublicKey, pki.caCert, pki.caKey, false, nil)
+
+    attr, err := SigningCertificateAttribute(otherCert, sign.SignHashWithSHA256.OID())
+    assertError(err, "Test_BES-SigningCertificateAttribute")
+
+    signedData, _ := sign.NewSignedData(data)
+    signedData.SetDigestAlgorithm(sign.SignHashWithSHA256.OID())
+    signedData.SetEncryptionAlgorithm(sign.KeySignWithEcdsaSHA256.OID())
+
+    err = signedData.AddSignerChain(pki.signerCert, pki.signerKey, []*x509.Certificate{pki.caCert}, sign.SignerInfoConfig{
+        ExtraSignedAttributes: []sign.Attribute{attr},
+    })
+    assertError(err, "Test_BES-AddSignerChain")
+
+    _, err = Verify(parseSigned(t, signedData), VerifyOpts{Roots: pki.roots})
+    assertNotErrorNil(err, "Test_BES-Verify-mismatch")
+}
+
+func Test_NotCAdES(t *testing.T) {
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    pki := newTestPKI(t)
+
+    signedData, _ := sign.NewSignedData([]byte("data"))
+    signedData.SetDigestAlgorithm(sign.SignHashWithSHA256.OID())
+    signedData.SetEncryptionAlgorithm(sign.KeySignWithEcdsaSHA256.OID())
+
+    err := signedData.AddSigner(pki.signerCert, pki.signerKey, sign.SignerInfoConfig{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    _, err = Verify(parseSigned(t, signedData), VerifyOpts{})
+    assertNotErrorNil(err, "Test_NotCAdES")
+}
+
+func Test_EPES(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+
+    pki := newTestPKI(t)
+
+    p7 := parseSigned(t, pki.newSignedData(t, []byte("data"), SignOpts{
+        KeySign: sign.KeySignWithEcdsaSHA384,
+        Policy:  testPolicy,
+    }))
+
+    results, err := Verify(p7, VerifyOpts{
+        Roots:  pki.roots,
+        Policy: testPolicy,
+    })
+    assertError(err, "Test_EPES-Verify")
+    assertEqual(results[0].Level, LevelBES, "Test_EPES-Level")
+    assertEqual(results[0].Policy, testPolicy, "Test_EPES-Policy")
+
+    _, err = Verify(p7, VerifyOpts{
+        Policy: &Policy{
+            ID:            testPolicy.ID,
+            HashAlgorithm: testPolicy.HashAlgorithm,
+            HashValue:     []byte("other"),
+        },
+    })
+    assertNotErrorNil(err, "Test_EPES-Verify-mismatch")
+}
+
+func Test_T(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+
+    pki := newTestPKI(t)
+
+    signedData := pki.newSignedData(t, []byte("data"), SignOpts{})
+
+    err := AddTimestamp(signedData, pki.token)
+    assertError(err, "Test_T-AddTimestamp")
+
+    results, err := Verify(parseSigned(t, signedData), VerifyOpts{
+        Roots:    pki.roots,
+        TSARoots: pki.roots,
+    })
+    assertError(err, "Test_T-Verify")
+    assertEqual(results[0].Level, LevelT, "Test_T-Level")
+    assertEqual(results[0].Timestamp != nil, true, "Test_T-Timestamp")
+}
+
+func Test_LT(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+
+    pki := newTestPKI(t)
+
+    chain := []*x509.Certificate{pki.signerCert, pki.caCert}
+
+    for name, revocation := range map[string][2][][]byte{
+        "crl":  {{pki.crl(t, nil)}, nil},
+        "ocsp": {nil, {pki.ocsp(t, false)}},
+    } {
+        signedData := pki.newSignedData(t, []byte("data"), SignOpts{})
+
+        err := AddTimestamp(signedData, pki.token)
+        assertError(err, "Test_LT-AddTimestamp-" + name)
+
+        err = AddValidationData(signedData, chain, revocation[0], revocation[1])
+        assertError(err, "Test_LT-AddValidationData-" + name)
+
+        results, err := Verify(parseSigned(t, signedData), VerifyOpts{
+            Roots:    pki.roots,
+            TSARoots: pki.roots,
+        })
+        assertError(err, "Test_LT-Verify-" + name)
+        assertEqual(results[0].Level, LevelLT, "Test_LT-Level-" + name)
+        assertEqual(results[0].Level.String(), "CAdES-LT", "Test_LT-Level-" + name)
+    }
+
+    // 已吊销
+    for name, revocation := range map[string][2][][]byte{
+        "crl": {{pki.crl(t, []pkix.RevokedCertificate{
+            {
+                SerialNumber:   pki.signerCert.SerialNumber,
+                RevocationTime: time.Now().Add(-time.Minute),
+            },
+        })}, nil},
+        "ocsp": {nil, {pki.ocsp(t, true)}},
+    } {
+        signedData := pki.newSignedData(t, []byte("data"), SignOpts{})
+
+        err := AddTimestamp(signedData, pki.token)
+        assertError(err, "Test_LT-AddTimestamp-revoked-" + name)
+
+        err = AddValidationData(signedData, chain, revocation[0], revocation[1])
+        assertError(err, "Test_LT-AddValidationData-revoked-" + name)
+
+        _, err = Verify(parseSigned(t, signedData), VerifyOpts{
+            Roots:    pki.roots,
+            TSARoots: pki.roots,
+        })
+        assertNotErrorNil(err, "Test_LT-Verify-revoked-" + name)
+
+        var revErr *RevokedError
+        assertEqual(errors.As(err, &revErr), true, "Test_LT-Verify-revoked-" + name)
+    }
+}
+
+func Test_LTInvalidRevocationData(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    pki := newTestPKI(t)
+    now := time.Now()
+
+    chain := []*x509.Certificate{pki.signerCert, pki.caCert}
+
+    ocspResponse := func(status int, thisUpdate, nextUpdate time.Time, key crypto.Signer) []byte {
+        resp, err := ocsp.CreateResponse(pki.caCert, pki.caCert, ocsp.Response{
+            Status:       status,
+            SerialNumber: pki.signerCert.SerialNumber,
+            ProducedAt:   now,
+            ThisUpdate:   thisUpdate,
+            NextUpdate:   nextUpdate,
+        }, key)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        return resp
+    }
+
+    staleCRL, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+        Number:     big.NewInt(1),
+        ThisUpdate: now.Add(-2 * time.Hour),
+        NextUpdate: now.Add(-time.Hour),
+    }, pki.caCert, pki.caKey)
+    assertError(err, "Test_LTInvalidRevocationData-staleCRL")
+
+    otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+    for name, revocation := range map[string][2][][]byte{
+        "ocsp-unknown": {nil, {ocspResponse(ocsp.Unknown, now, now.Add(time.Hour), pki.caKey)}},
+        "ocsp-stale":   {nil, {ocspResponse(ocsp.Good, now.Add(-2 * time.Hour), now.Add(-time.Hour), pki.caKey)}},
+        "ocsp-badsign": {nil, {ocspResponse(ocsp.Good, now, now.Add(time.Hour), otherKey)}},
+        "crl-stale":    {{staleCRL}, nil},
+    } {
+        signedData := pki.newSignedData(t, []byte("data"), SignOpts{})
+
+        err := AddTimestamp(signedData, pki.token)
+        assertError(err, "Test_LTInvalidRevocationData-AddTimestamp-" + name)
+
+        err = AddValidationData(signedData, chain, revocation[0], revocation[1])
+        assertError(err, "Test_LTInvalidRevocationData-AddValidationData-" + name)
+
+        _, err = Verify(parseSigned(t, signedData), VerifyOpts{
+            Roots:    pki.roots,
+            TSARoots: pki.roots,
+        })
+        assertNotErrorNil(err, "Test_LTInvalidRevocationData-Verify-" + name)
+    }
+}
+
+func Test_LTIntermediate(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+
+    pki := newTestPKI(t)
+    now := time.Now()
+
+    interKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    interCert := newTestCert(t, 10, "intermediate", &interKey.PublicKey, pki.caCert, pki.caKey, true, nil)
+
+    signerKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    signerCert := newTestCert(t, 11, "signer", &signerKey.PublicKey, interCert, interKey, false, nil)
+
+    chain := []*x509.Certificate{signerCert, interCert, pki.caCert}
+
+    makeCRL := func(issuer *x509.Certificate, key crypto.Signer, revoked []pkix.RevokedCertificate) []byte {
+        crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+            Number:              big.NewInt(1),
+            ThisUpdate:          now.Add(-time.Minute),
+            NextUpdate:          now.Add(time.Hour),
+            RevokedCertificates: revoked,
+        }, issuer, key)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        return crl
+    }
+
+    signerCRL := makeCRL(interCert, interKey, nil)
+    interCRL := makeCRL(pki.caCert, pki.caKey, nil)
+    interRevokedCRL := makeCRL(pki.caCert, pki.caKey, []pkix.RevokedCertificate{
+        {
+            SerialNumber:   interCert.SerialNumber,
+            RevocationTime: now.Add(-time.Minute),
+        },
+    })
+
+    verify := func(crls [][]byte) ([]*Result, error) {
+        signedData, err := sign.NewSignedData([]byte("data"))
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        err = AddSigner(signedData, signerCert, signerKey, []*x509.Certificate{interCert, pki.caCert}, SignOpts{
+            KeySign: sign.KeySignWithEcdsaSHA256,
+        })
+        assertError(err, "Test_LTIntermediate-AddSigner")
+
+        err = AddTimestamp(signedData, pki.token)
+        assertError(err, "Test_LTIntermediate-AddTimestamp")
+
+        err = AddValidationData(signedData, chain, crls, nil)
+        assertError(err, "Test_LTIntermediate-AddValidationData")
+
+        return Verify(parseSigned(t, signedData), VerifyOpts{
+            Roots:    pki.roots,
+            TSARoots: pki.roots,
+        })
+    }
+
+    results, err := verify([][]byte{signerCRL, interCRL})
+    assertError(err, "Test_LTIntermediate-Verify")
+    assertEqual(results[0].Level, LevelLT, "Test_LTIntermediate-Level")
+
+    // 中间证书没有吊销数据
+    _, err = verify([][]byte{signerCRL})
+    assertNotErrorNil(err, "Test_LTIntermediate-Verify-no-data")
+
+    // 中间证书已吊销
+    _, err = verify([][]byte{signerCRL, interRevokedCRL})
+    assertNotErrorNil(err, "Test_LTIntermediate-Verify-revoked")
+
+    var revErr *RevokedError
+    assertEqual(errors.As(err, &revErr), true, "Test_LTIntermediate-Verify-revoked")
+    if revErr != nil {
+        assertEqual(revErr.SerialNumber, interCert.SerialNumber, "Test_LTIntermediate-Verify-revoked")
+    }
+}
+
+func Test_Detached(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+
+    pki := newTestPKI(t)
+    data := []byte("detached data")
+
+    signedData := pki.newSignedData(t, data, SignOpts{})
+    signedData.Detach()
+
+    err := AddTimestamp(signedData, pki.token)
+    assertError(err, "Test_Detached-AddTimestamp")
+
+    p7 := parseSigned(t, signedData)
+    p7.Content = data
+
+    results, err := Verify(p7, VerifyOpts{
+        Roots:    pki.roots,
+        TSARoots: pki.roots,
+    })
+    assertError(err, "Test_Detached-Verify")
+    assertEqual(results[0].Level, LevelT, "Test_Detached-Level")
+}
@@ -0,0 +1,264 @@
+package cades
+
+import (
+    "errors"
+    "crypto"
+    "crypto/x509"
+    "crypto/x509/pkix"
+    "encoding/asn1"
+
+    "github.com/deatil/go-cryptobin/pkcs7/sign"
+    "github.com/deatil/go-cryptobin/pkcs7/timestamp"
+)
+
+// 签名配置
+type SignOpts struct {
+    // 签名方式, 默认为 sign.KeySignWithRsaSHA256
+    KeySign sign.KeySign
+
+    // 签名策略, 设置后为 CAdES-EPES
+    Policy *Policy
+
+    // 额外的签名属性
+    ExtraSignedAttributes []sign.Attribute
+}
+
+// 生成 CAdES-BES 签名, 签名属性包含 signingCertificateV2
+// 需要在 sign.NewSignedData 之后调用, 多个签名者时需使用相同的签名方式
+func AddSigner(sd *sign.SignedData, ee *x509.Certificate, pkey crypto.PrivateKey, parents []*x509.Certificate, opts SignOpts) error {
+    keySign := opts.KeySign
+    if keySign == nil {
+        keySign = sign.KeySignWithRsaSHA256
+    }
+
+    signingCert, err := SigningCertificateAttribute(ee, keySign.HashOID())
+    if err != nil {
+        return err
+    }
+
+    attrs := []sign.Attribute{signingCert}
+
+    if opts.Policy != nil {
+        policy, err := SignaturePolicyAttribute(opts.Policy)
+        if err != nil {
+            return err
+        }
+
+        attrs = append(attrs, policy)
+    }
+
+    attrs = append(attrs, opts.ExtraSignedAttributes...)
+
+    sd.SetDigestAlgorithm(keySign.HashOID())
+    sd.SetEncryptionAlgorithm(keySign.OID())
+
+    return sd.AddSignerChain(ee, pkey, parents, sign.SignerInfoConfig{
+        ExtraSignedAttributes: attrs,
+    })
+}
+
+// 添加签名时间戳, 升级到 CAdES-T
+// tokenFunc 为签名值获取时间戳令牌, 可以使用 timestamp.Client.Token
+func AddTimestamp(sd *sign.SignedData, tokenFunc func(signature []byte) ([]byte, error)) error {
+    return timestamp.AttachTimestamp(sd, tokenFunc)
+}
+
+// 添加验证数据, 升级到 CAdES-LT
+// certs 为签名证书链, crls 及 ocsps 为 DER 编码的 CRL 及 OCSP 响应
+func AddValidationData(sd *sign.SignedData, certs []*x509.Certificate, crls [][]byte, ocsps [][]byte) error {
+    signedData := sd.GetSignedData()
+    if len(signedData.SignerInfos) == 0 {
+        return errors.New("pkcs7/cades: signed data has no signers")
+    }
+
+    if len(certs) == 0 {
+        return errors.New("pkcs7/cades: certificate values is empty")
+    }
+
+    if len(crls) == 0 && len(ocsps) == 0 {
+        return errors.New("pkcs7/cades: revocation values is empty")
+    }
+
+    var rawCerts []byte
+    for _, cert := range certs {
+        rawCerts = append(rawCerts, cert.Raw...)
+    }
+
+    certValues, err := asn1.Marshal(asn1.RawValue{
+        Tag:        asn1.TagSequence,
+        IsCompound: true,
+        Bytes:      rawCerts,
+    })
+    if err != nil {
+        return err
+    }
+
+    var revValues revocationValues
+    for _, crl := range crls {
+        revValues.CRLVals = append(revValues.CRLVals, asn1.RawValue{FullBytes: crl})
+    }
+
+    for _, resp := range ocsps {
+        basic, err := parseBasicOCSPResponse(resp)
+        if err != nil {
+            return err
+        }
+
+        revValues.OCSPVals = append(revValues.OCSPVals, asn1.RawValue{FullBytes: basic})
+    }
+
+    revocationBytes, err := asn1.Marshal(revValues)
+    if err != nil {
+        return err
+    }
+
+    for i := range signedData.SignerInfos {
+        signer := &signedData.SignerInfos[i]
+
+        attrs := make([]sign.Attribute, 0, len(signer.UnauthenticatedAttributes)+2)
+        for _, attr := range signer.UnauthenticatedAttributes {
+            if attr.Type.Equal(oidCertValues) || attr.Type.Equal(oidRevocationValues) {
+                continue
+            }
+
+            attrs = append(attrs, sign.Attribute{
+                Type:  attr.Type,
+                Value: asn1.RawValue{FullBytes: attr.Value.Bytes},
+            })
+        }
+
+        attrs = append(attrs, sign.Attribute{
+            Type:  oidCertValues,
+            Value: asn1.RawValue{FullBytes: certValues},
+        }, sign.Attribute{
+            Type:  oidRevocationValues,
+            Value: asn1.RawValue{FullBytes: revocationBytes},
+        })
+
+        if err = signer.SetUnauthenticatedAttributes(attrs); err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+// 生成 signingCertificateV2 属性
+func SigningCertificateAttribute(cert *x509.Certificate, hashOID asn1.ObjectIdentifier) (sign.Attribute, error) {
+    hash, err := getHash(hashOID)
+    if err != nil {
+        return sign.Attribute{}, err
+    }
+
+    issuer, err := marshalIssuerSerialName(cert.RawIssuer)
+    if err != nil {
+        return sign.Attribute{}, err
+    }
+
+    certID := essCertIDv2{
+        CertHash: hash.Sum(cert.Raw),
+        IssuerSerial: issuerSerial{
+            Issuer:       issuer,
+            SerialNumber: cert.SerialNumber,
+        },
+    }
+
+    // 默认为 SHA256, DER 编码时不写入
+    if !hashOID.Equal(sign.SignHashWithSHA256.OID()) {
+        certID.HashAlgorithm = pkix.AlgorithmIdentifier{
+            Algorithm: hashOID,
+        }
+    }
+
+    return sign.Attribute{
+        Type: oidSigningCertificateV2,
+        Value: signingCertificateV2{
+            Certs: []essCertIDv2{certID},
+        },
+    }, nil
+}
+
+// 生成 signaturePolicyIdentifier 属性
+func SignaturePolicyAttribute(policy *Policy) (sign.Attribute, error) {
+    if len(policy.ID) == 0 {
+        return sign.Attribute{}, errors.New("pkcs7/cades: policy id is empty")
+    }
+
+    policyID := signaturePolicyID{
+        SigPolicyID: policy.ID,
+        SigPolicyHash: otherHashAlgAndValue{
+            HashAlgorithm: pkix.AlgorithmIdentifier{
+                Algorithm: policy.HashAlgorithm,
+            },
+            HashValue: policy.HashValue,
+        },
+    }
+
+    if policy.URI != "" {
+        uri, err := asn1.MarshalWithParams(policy.URI, "ia5")
+        if err != nil {
+            return sign.Attribute{}, err
+        }
+
+        policyID.SigPolicyQualifiers = []sigPolicyQualifierInfo{
+            {
+                SigPolicyQualifierID: oidSPQetsURI,
+                SigQualifier:         asn1.RawValue{FullBytes: uri},
+            },
+        }
+    }
+
+    return sign.Attribute{
+        Type:  oidSignaturePolicyID,
+        Value: policyID,
+    }, nil
+}
+
+// GeneralNames 中的 directoryName
+func marshalIssuerSerialName(rawIssuer []byte) (asn1.RawValue, error) {
+    directoryName, err := asn1.Marshal(asn1.RawValue{
+        Class:      asn1.ClassContextSpecific,
+        Tag:        4,
+        IsCompound: true,
+        Bytes:      rawIssuer,
+    })
+    if err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    return asn1.RawValue{
+        Tag:        asn1.TagSequence,
+        IsCompound: true,
+        Bytes:      directoryName,
+    }, nil
+}
+
+// 从 OCSP 响应中取出 BasicOCSPResponse
+func parseBasicOCSPResponse(der []byte) ([]byte, error) {
+    var resp ocspResponse
+    rest, err := asn1.Unmarshal(der, &resp)
+    if err != nil {
+        return nil, err
+    }
+
+    if len(rest) > 0 {
+        return nil, errors.New("pkcs7/cades: trailing data in OCSP response")
+    }
+
+    if resp.Status != 0 || !resp.Response.ResponseType.Equal(oidOCSPBasic) {
+        return nil, errors.New("pkcs7/cades: OCSP response is not a successful basic response")
+    }
+
+    return resp.Response.Response, nil
+}
+
+// 将 BasicOCSPResponse 包装为 OCSP 响应
+func marshalOCSPResponse(basic []byte) ([]byte, error) {
+    return asn1.Marshal(ocspResponse{
+        Status: 0,
+        Response: ocspResponseBytes{
+            ResponseType: oidOCSPBasic,
+            Response:     basic,
+        },
+    })
+}
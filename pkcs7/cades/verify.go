@@ -0,0 +1,445 @@
+package cades
+
+import (
+    "fmt"
+    "time"
+    "bytes"
+    "errors"
+    "math/big"
+    "crypto/x509"
+    "encoding/asn1"
+
+    "github.com/deatil/go-cryptobin/ocsp"
+    "github.com/deatil/go-cryptobin/pkcs7/sign"
+    "github.com/deatil/go-cryptobin/pkcs7/timestamp"
+)
+
+// 验证配置
+type VerifyOpts struct {
+    // 签名证书根证书, 为 nil 时不验证证书链
+    Roots *x509.CertPool
+
+    // 时间戳证书根证书, 签名有时间戳时不能为 nil
+    TSARoots *x509.CertPool
+
+    // 需要匹配的签名策略, 只比较 ID 及摘要值
+    Policy *Policy
+
+    // 没有时间戳时的验证时间, 默认为当前时间
+    CurrentTime time.Time
+}
+
+// 验证结果
+type Result struct {
+    // 达到的签名级别
+    Level Level
+
+    // 签名证书
+    Signer *x509.Certificate
+
+    // signingTime 签名属性
+    SigningTime time.Time
+
+    // 签名策略, 不为 nil 时为 CAdES-EPES
+    Policy *Policy
+
+    // 签名时间戳, CAdES-T 及以上
+    Timestamp *timestamp.Timestamp
+}
+
+// 证书已吊销
+type RevokedError struct {
+    SerialNumber *big.Int
+    RevokedAt    time.Time
+}
+
+func (e *RevokedError) Error() string {
+    return fmt.Sprintf("pkcs7/cades: certificate %s revoked at %s", e.SerialNumber, e.RevokedAt.Format(time.RFC3339))
+}
+
+// 验证 CAdES 签名并返回每个签名者达到的级别
+// 分离签名需要先设置 p7.Content
+func Verify(p7 *sign.PKCS7, opts VerifyOpts) ([]*Result, error) {
+    if len(p7.Signers) == 0 {
+        return nil, errors.New("pkcs7/cades: message has no signers")
+    }
+
+    results := make([]*Result, 0, len(p7.Signers))
+    for i := range p7.Signers {
+        result, err := verifySigner(p7, i, opts)
+        if err != nil {
+            return nil, err
+        }
+
+        results = append(results, result)
+    }
+
+    return results, nil
+}
+
+func verifySigner(p7 *sign.PKCS7, index int, opts VerifyOpts) (*Result, error) {
+    signer := p7.Signers[index]
+
+    signedAttrs := make([]attribute, 0, len(signer.AuthenticatedAttributes))
+    for _, attr := range signer.AuthenticatedAttributes {
+        signedAttrs = append(signedAttrs, attribute{attr.Type, attr.Value})
+    }
+
+    unsignedAttrs := make([]attribute, 0, len(signer.UnauthenticatedAttributes))
+    for _, attr := range signer.UnauthenticatedAttributes {
+        unsignedAttrs = append(unsignedAttrs, attribute{attr.Type, attr.Value})
+    }
+
+    certs := p7.Certificates
+
+    // CAdES-LT 证书链
+    certValues, hasCertValues, err := parseCertValues(unsignedAttrs)
+    if err != nil {
+        return nil, err
+    }
+
+    certs = append(append([]*x509.Certificate{}, certs...), certValues...)
+
+    var ee *x509.Certificate
+    for _, cert := range certs {
+        if cert.SerialNumber.Cmp(signer.IssuerAndSerialNumber.SerialNumber) == 0 &&
+            bytes.Equal(cert.RawIssuer, signer.IssuerAndSerialNumber.IssuerName.FullBytes) {
+            ee = cert
+            break
+        }
+    }
+
+    if ee == nil {
+        return nil, errors.New("pkcs7/cades: no certificate for signer")
+    }
+
+    result := &Result{
+        Level:  LevelBES,
+        Signer: ee,
+    }
+
+    // CAdES-BES
+    if err := checkSigningCertificate(signedAttrs, ee); err != nil {
+        return nil, err
+    }
+
+    var signingTime time.Time
+    if findAttribute(signedAttrs, oidAttributeSigningTime, &signingTime) {
+        result.SigningTime = signingTime
+    }
+
+    // CAdES-EPES
+    policy, err := parseSignaturePolicy(signedAttrs)
+    if err != nil {
+        return nil, err
+    }
+
+    if opts.Policy != nil {
+        if policy == nil {
+            return nil, errors.New("pkcs7/cades: signature policy not found")
+        }
+
+        if !policy.ID.Equal(opts.Policy.ID) ||
+            !policy.HashAlgorithm.Equal(opts.Policy.HashAlgorithm) ||
+            !bytes.Equal(policy.HashValue, opts.Policy.HashValue) {
+            return nil, errors.New("pkcs7/cades: signature policy mismatch")
+        }
+    }
+
+    result.Policy = policy
+
+    validationTime := opts.CurrentTime
+    if validationTime.IsZero() {
+        validationTime = time.Now()
+    }
+
+    // CAdES-T
+    var token asn1.RawValue
+    if findAttribute(unsignedAttrs, oidSignatureTimeStampToken, &token) {
+        ts, err := timestamp.VerifyToken(token.FullBytes, signer.EncryptedDigest, opts.TSARoots)
+        if err != nil {
+            return nil, err
+        }
+
+        result.Level = LevelT
+        result.Timestamp = ts
+
+        validationTime = ts.Time
+    }
+
+    // CAdES-LT
+    revValues, hasRevValues, err := parseRevocationValues(unsignedAttrs)
+    if err != nil {
+        return nil, err
+    }
+
+    if hasCertValues && hasRevValues {
+        if err := checkRevocation(ee, certs, revValues, validationTime); err != nil {
+            return nil, err
+        }
+
+        if result.Level == LevelT {
+            result.Level = LevelLT
+        }
+    }
+
+    // 验证签名及证书链
+    single := *p7
+    single.Certificates = certs
+    single.Signers = p7.Signers[index:index+1]
+
+    if err := single.VerifyWithChainAtTime(opts.Roots, validationTime); err != nil {
+        return nil, err
+    }
+
+    return result, nil
+}
+
+// 检测 signingCertificateV2 或者 signingCertificate 属性
+func checkSigningCertificate(attrs []attribute, ee *x509.Certificate) error {
+    var certV2 signingCertificateV2
+    if findAttribute(attrs, oidSigningCertificateV2, &certV2) {
+        if len(certV2.Certs) == 0 {
+            return errors.New("pkcs7/cades: signing certificate attribute is empty")
+        }
+
+        certID := certV2.Certs[0]
+
+        hashOID := sign.SignHashWithSHA256.OID()
+        if len(certID.HashAlgorithm.Algorithm) > 0 {
+            hashOID = certID.HashAlgorithm.Algorithm
+        }
+
+        return checkESSCertID(ee, hashOID, certID.CertHash, certID.IssuerSerial)
+    }
+
+    var certV1 signingCertificate
+    if findAttribute(attrs, oidSigningCertificate, &certV1) {
+        if len(certV1.Certs) == 0 {
+            return errors.New("pkcs7/cades: signing certificate attribute is empty")
+        }
+
+        certID := certV1.Certs[0]
+
+        return checkESSCertID(ee, sign.SignHashWithSHA1.OID(), certID.CertHash, certID.IssuerSerial)
+    }
+
+    return errors.New("pkcs7/cades: signing certificate attribute not found")
+}
+
+func checkESSCertID(ee *x509.Certificate, hashOID asn1.ObjectIdentifier, certHash []byte, is issuerSerial) error {
+    hash, err := getHash(hashOID)
+    if err != nil {
+        return err
+    }
+
+    if !bytes.Equal(hash.Sum(ee.Raw), certHash) {
+        return errors.New("pkcs7/cades: signing certificate mismatch")
+    }
+
+    if is.SerialNumber != nil {
+        issuer, err := marshalIssuerSerialName(ee.RawIssuer)
+        if err != nil {
+            return err
+        }
+
+        if is.SerialNumber.Cmp(ee.SerialNumber) != 0 || !bytes.Equal(is.Issuer.Bytes, issuer.Bytes) {
+            return errors.New("pkcs7/cades: signing certificate issuer serial mismatch")
+        }
+    }
+
+    return nil
+}
+
+// 解析签名策略
+func parseSignaturePolicy(attrs []attribute) (*Policy, error) {
+    var value asn1.RawValue
+    if !findAttribute(attrs, oidSignaturePolicyID, &value) {
+        return nil, nil
+    }
+
+    // signaturePolicyImplied
+    if value.Tag == asn1.TagNull {
+        return nil, nil
+    }
+
+    var policyID signaturePolicyID
+    if _, err := asn1.Unmarshal(value.FullBytes, &policyID); err != nil {
+        return nil, err
+    }
+
+    policy := &Policy{
+        ID:            policyID.SigPolicyID,
+        HashAlgorithm: policyID.SigPolicyHash.HashAlgorithm.Algorithm,
+        HashValue:     policyID.SigPolicyHash.HashValue,
+    }
+
+    for _, qualifier := range policyID.SigPolicyQualifiers {
+        if qualifier.SigPolicyQualifierID.Equal(oidSPQetsURI) {
+            asn1.Unmarshal(qualifier.SigQualifier.FullBytes, &policy.URI)
+        }
+    }
+
+    return policy, nil
+}
+
+func parseCertValues(attrs []attribute) ([]*x509.Certificate, bool, error) {
+    var value asn1.RawValue
+    if !findAttribute(attrs, oidCertValues, &value) {
+        return nil, false, nil
+    }
+
+    certs, err := x509.ParseCertificates(value.Bytes)
+    if err != nil {
+        return nil, false, err
+    }
+
+    return certs, true, nil
+}
+
+func parseRevocationValues(attrs []attribute) (revocationValues, bool, error) {
+    var value asn1.RawValue
+    if !findAttribute(attrs, oidRevocationValues, &value) {
+        return revocationValues{}, false, nil
+    }
+
+    var revValues revocationValues
+    if _, err := asn1.Unmarshal(value.FullBytes, &revValues); err != nil {
+        return revocationValues{}, false, err
+    }
+
+    return revValues, true, nil
+}
+
+// 使用 CRL 及 OCSP 检测证书链中除根证书外每个证书的吊销状态
+func checkRevocation(ee *x509.Certificate, certs []*x509.Certificate, revValues revocationValues, at time.Time) error {
+    cert := ee
+    for i := 0; i <= len(certs); i++ {
+        // 自签名的根证书
+        if bytes.Equal(cert.RawSubject, cert.RawIssuer) && cert.CheckSignatureFrom(cert) == nil {
+            return nil
+        }
+
+        var issuer *x509.Certificate
+        for _, c := range certs {
+            if bytes.Equal(c.RawSubject, cert.RawIssuer) && cert.CheckSignatureFrom(c) == nil {
+                issuer = c
+                break
+            }
+        }
+
+        if issuer == nil {
+            return errors.New("pkcs7/cades: issuer certificate not found in certificate values")
+        }
+
+        if err := checkCertRevocation(cert, issuer, revValues, at); err != nil {
+            return err
+        }
+
+        cert = issuer
+    }
+
+    return errors.New("pkcs7/cades: certificate chain too long")
+}
+
+// 检测单个证书的吊销状态
+// 只有在验证时间有效的 CRL 及状态为 Good 或者 Revoked 的 OCSP 响应才算有吊销数据
+func checkCertRevocation(cert, issuer *x509.Certificate, revValues revocationValues, at time.Time) error {
+    covered := false
+
+    for _, val := range revValues.CRLVals {
+        crl, err := x509.ParseRevocationList(val.FullBytes)
+        if err != nil {
+            return err
+        }
+
+        if !bytes.Equal(crl.RawIssuer, cert.RawIssuer) {
+            continue
+        }
+
+        if err := crl.CheckSignatureFrom(issuer); err != nil {
+            return fmt.Errorf("pkcs7/cades: invalid CRL signature: %v", err)
+        }
+
+        for _, revoked := range crl.RevokedCertificates {
+            if revoked.SerialNumber.Cmp(cert.SerialNumber) == 0 && !revoked.RevocationTime.After(at) {
+                return &RevokedError{
+                    SerialNumber: cert.SerialNumber,
+                    RevokedAt:    revoked.RevocationTime,
+                }
+            }
+        }
+
+        if isFreshAt(crl.ThisUpdate, crl.NextUpdate, at) {
+            covered = true
+        }
+    }
+
+    for _, val := range revValues.OCSPVals {
+        der, err := marshalOCSPResponse(val.FullBytes)
+        if err != nil {
+            return err
+        }
+
+        resp, err := ocsp.ParseResponseForCert(der, cert, issuer)
+        if err != nil {
+            // 不是该证书的响应
+            if errors.Is(err, ocsp.ErrNoResponse) || errors.Is(err, ocsp.ErrIssuerMatch) {
+                continue
+            }
+
+            return fmt.Errorf("pkcs7/cades: invalid OCSP response: %v", err)
+        }
+
+        switch resp.Status {
+            case ocsp.Revoked:
+                if !resp.RevokedAt.After(at) {
+                    return &RevokedError{
+                        SerialNumber: cert.SerialNumber,
+                        RevokedAt:    resp.RevokedAt,
+                    }
+                }
+
+                if isFreshAt(resp.ThisUpdate, resp.NextUpdate, at) {
+                    covered = true
+                }
+            case ocsp.Good:
+                if isFreshAt(resp.ThisUpdate, resp.NextUpdate, at) {
+                    covered = true
+                }
+        }
+    }
+
+    if !covered {
+        return fmt.Errorf("pkcs7/cades: no valid revocation data for certificate %s", cert.SerialNumber)
+    }
+
+    return nil
+}
+
+// 吊销数据在验证时间是否有效
+// 有 nextUpdate 时验证时间不能晚于 nextUpdate, 没有时 thisUpdate 不能早于验证时间
+func isFreshAt(thisUpdate, nextUpdate, at time.Time) bool {
+    if nextUpdate.IsZero() {
+        return !thisUpdate.Before(at)
+    }
+
+    return !at.After(nextUpdate)
+}
+
+type attribute struct {
+    Type  asn1.ObjectIdentifier
+    Value asn1.RawValue
+}
+
+// 解析属性的第一个值
+func findAttribute(attrs []attribute, attrType asn1.ObjectIdentifier, out any) bool {
+    for _, attr := range attrs {
+        if attr.Type.Equal(attrType) {
+            _, err := asn1.Unmarshal(attr.Value.Bytes, out)
+            return err == nil
+        }
+    }
+
+    return false
+}
@@ -0,0 +1,124 @@
+package encrypt
+
+import (
+    "io"
+    "errors"
+    "crypto/cipher"
+    "encoding/asn1"
+
+    "github.com/deatil/go-cryptobin/cipher/ccm"
+)
+
+// RFC 5084 参数
+// GCMParameters ::= SEQUENCE {
+//     aes-nonce   OCTET STRING, -- recommended size is 12 octets
+//     aes-ICVlen  AES-GCM-ICVlen DEFAULT 12 }
+// CCMParameters ::= SEQUENCE {
+//     aes-nonce   OCTET STRING (SIZE(7..13)),
+//     aes-ICVlen  AES-CCM-ICVlen DEFAULT 12 }
+type authCipherParams struct {
+    Nonce  []byte
+    ICVLen int `asn1:"default:12,optional"`
+}
+
+// 认证加密
+type AuthCipherWithAEAD struct {
+    cipherFunc func(key []byte) (cipher.Block, error)
+    aeadFunc   func(block cipher.Block, nonceSize, tagSize int) (cipher.AEAD, error)
+    keySize    int
+    nonceSize  int
+    tagSize    int
+    identifier asn1.ObjectIdentifier
+}
+
+// oid
+func (this AuthCipherWithAEAD) OID() asn1.ObjectIdentifier {
+    return this.identifier
+}
+
+// 密钥长度
+func (this AuthCipherWithAEAD) KeySize() int {
+    return this.keySize
+}
+
+// 加密
+func (this AuthCipherWithAEAD) Encrypt(rand io.Reader, key, plaintext, additionalData []byte) ([]byte, []byte, []byte, error) {
+    block, err := this.cipherFunc(key)
+    if err != nil {
+        return nil, nil, nil, err
+    }
+
+    aead, err := this.aeadFunc(block, this.nonceSize, this.tagSize)
+    if err != nil {
+        return nil, nil, nil, err
+    }
+
+    nonce := make([]byte, this.nonceSize)
+    if _, err := io.ReadFull(rand, nonce); err != nil {
+        return nil, nil, nil, errors.New("pkcs7: failed to generate nonce: " + err.Error())
+    }
+
+    sealed := aead.Seal(nil, nonce, plaintext, additionalData)
+
+    n := len(sealed) - this.tagSize
+    ciphertext, mac := sealed[:n], sealed[n:]
+
+    paramBytes, err := asn1.Marshal(authCipherParams{
+        Nonce:  nonce,
+        ICVLen: this.tagSize,
+    })
+    if err != nil {
+        return nil, nil, nil, err
+    }
+
+    return ciphertext, mac, paramBytes, nil
+}
+
+// 解密
+func (this AuthCipherWithAEAD) Decrypt(key, params, ciphertext, mac, additionalData []byte) ([]byte, error) {
+    var param authCipherParams
+    if _, err := asn1.Unmarshal(params, &param); err != nil {
+        return nil, errors.New("pkcs7: invalid auth cipher params")
+    }
+
+    if param.ICVLen != len(mac) {
+        return nil, errors.New("pkcs7: invalid mac size")
+    }
+
+    block, err := this.cipherFunc(key)
+    if err != nil {
+        return nil, err
+    }
+
+    aead, err := this.aeadFunc(block, len(param.Nonce), param.ICVLen)
+    if err != nil {
+        return nil, err
+    }
+
+    sealed := make([]byte, 0, len(ciphertext) + len(mac))
+    sealed = append(sealed, ciphertext...)
+    sealed = append(sealed, mac...)
+
+    plaintext, err := aead.Open(nil, param.Nonce, sealed, additionalData)
+    if err != nil {
+        return nil, errors.New("pkcs7: message authentication failed")
+    }
+
+    return plaintext, nil
+}
+
+func newGCM(block cipher.Block, nonceSize, tagSize int) (cipher.AEAD, error) {
+    if tagSize == 16 {
+        return cipher.NewGCMWithNonceSize(block, nonceSize)
+    }
+
+    if nonceSize == 12 {
+        return cipher.NewGCMWithTagSize(block, tagSize)
+    }
+
+    return nil, errors.New("pkcs7: unsupported gcm nonce and tag size")
+}
+
+func newCCM(block cipher.Block, nonceSize, tagSize int) (cipher.AEAD, error) {
+    return ccm.NewCCMWithNonceAndTagSize(block, nonceSize, tagSize)
+}
@@ -0,0 +1,130 @@
+package encrypt
+
+import (
+    "crypto/aes"
+    "encoding/asn1"
+
+    "github.com/deatil/go-cryptobin/cipher/sm4"
+)
+
+var (
+    oidAES128GCM = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 6}
+    oidAES192GCM = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 26}
+    oidAES256GCM = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 46}
+
+    oidAES128CCM = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 7}
+    oidAES192CCM = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 27}
+    oidAES256CCM = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 47}
+
+    oidSM4GCM = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 104, 8}
+    oidSM4CCM = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 104, 9}
+)
+
+// AuthAES128GCM
+var AuthAES128GCM = AuthCipherWithAEAD{
+    cipherFunc: aes.NewCipher,
+    aeadFunc:   newGCM,
+    keySize:    16,
+    nonceSize:  12,
+    tagSize:    16,
+    identifier: oidAES128GCM,
+}
+
+// AuthAES192GCM
+var AuthAES192GCM = AuthCipherWithAEAD{
+    cipherFunc: aes.NewCipher,
+    aeadFunc:   newGCM,
+    keySize:    24,
+    nonceSize:  12,
+    tagSize:    16,
+    identifier: oidAES192GCM,
+}
+
+// AuthAES256GCM
+var AuthAES256GCM = AuthCipherWithAEAD{
+    cipherFunc: aes.NewCipher,
+    aeadFunc:   newGCM,
+    keySize:    32,
+    nonceSize:  12,
+    tagSize:    16,
+    identifier: oidAES256GCM,
+}
+
+// AuthAES128CCM
+var AuthAES128CCM = AuthCipherWithAEAD{
+    cipherFunc: aes.NewCipher,
+    aeadFunc:   newCCM,
+    keySize:    16,
+    nonceSize:  12,
+    tagSize:    16,
+    identifier: oidAES128CCM,
+}
+
+// AuthAES192CCM
+var AuthAES192CCM = AuthCipherWithAEAD{
+    cipherFunc: aes.NewCipher,
+    aeadFunc:   newCCM,
+    keySize:    24,
+    nonceSize:  12,
+    tagSize:    16,
+    identifier: oidAES192CCM,
+}
+
+// AuthAES256CCM
+var AuthAES256CCM = AuthCipherWithAEAD{
+    cipherFunc: aes.NewCipher,
+    aeadFunc:   newCCM,
+    keySize:    32,
+    nonceSize:  12,
+    tagSize:    16,
+    identifier: oidAES256CCM,
+}
+
+// AuthSM4GCM
+var AuthSM4GCM = AuthCipherWithAEAD{
+    cipherFunc: sm4.NewCipher,
+    aeadFunc:   newGCM,
+    keySize:    16,
+    nonceSize:  12,
+    tagSize:    16,
+    identifier: oidSM4GCM,
+}
+
+// AuthSM4CCM
+var AuthSM4CCM = AuthCipherWithAEAD{
+    cipherFunc: sm4.NewCipher,
+    aeadFunc:   newCCM,
+    keySize:    16,
+    nonceSize:  12,
+    tagSize:    16,
+    identifier: oidSM4CCM,
+}
+
+func init() {
+    AddAuthCipher(oidAES128GCM, func() AuthCipher {
+        return AuthAES128GCM
+    })
+    AddAuthCipher(oidAES192GCM, func() AuthCipher {
+        return AuthAES192GCM
+    })
+    AddAuthCipher(oidAES256GCM, func() AuthCipher {
+        return AuthAES256GCM
+    })
+
+    AddAuthCipher(oidAES128CCM, func() AuthCipher {
+        return AuthAES128CCM
+    })
+    AddAuthCipher(oidAES192CCM, func() AuthCipher {
+        return AuthAES192CCM
+    })
+    AddAuthCipher(oidAES256CCM, func() AuthCipher {
+        return AuthAES256CCM
+    })
+
+    AddAuthCipher(oidSM4GCM, func() AuthCipher {
+        return AuthSM4GCM
+    })
+    AddAuthCipher(oidSM4CCM, func() AuthCipher {
+        return AuthSM4CCM
+    })
+}
@@ -0,0 +1,139 @@
+package encrypt
+
+import (
+    "io"
+    "fmt"
+    "errors"
+    "crypto/x509"
+    "crypto/x509/pkix"
+    "encoding/asn1"
+)
+
+var (
+    oidAuthEnvelopedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 23}
+)
+
+// RFC 5083
+// AuthEnvelopedData ::= SEQUENCE {
+//     version CMSVersion,
+//     originatorInfo [0] IMPLICIT OriginatorInfo OPTIONAL,
+//     recipientInfos RecipientInfos,
+//     authEncryptedContentInfo EncryptedContentInfo,
+//     authAttrs [1] IMPLICIT AuthAttributes OPTIONAL,
+//     mac MessageAuthenticationCode,
+//     unauthAttrs [2] IMPLICIT UnauthAttributes OPTIONAL }
+type authEnvelopedData struct {
+    Version                  int
+    OriginatorInfo           asn1.RawValue   `asn1:"optional,tag:0"`
+    RecipientInfos           []asn1.RawValue `asn1:"set"`
+    AuthEncryptedContentInfo encryptedContentInfo
+    AuthAttrs                []attribute     `asn1:"optional,omitempty,tag:1"`
+    MAC                      []byte
+    UnauthAttrs              asn1.RawValue   `asn1:"optional,tag:2"`
+}
+
+// 认证加密, 生成 AuthEnvelopedData
+// opts 只使用接收者相关配置, 忽略 Cipher
+func EncryptAuth(rand io.Reader, content []byte, recipients []*x509.Certificate, cipher AuthCipher, opts ...Opts) ([]byte, error) {
+    opt := &DefaultOpts
+    if len(opts) > 0 {
+        opt = &opts[0]
+    }
+
+    newRecipients, err := makeCertRecipients(recipients, opt)
+    if err != nil {
+        return nil, err
+    }
+
+    return EncryptAuthWithRecipients(rand, content, newRecipients, cipher)
+}
+
+// 使用多种接收者认证加密
+// cipher 可用 [AuthAES128GCM | AuthAES256GCM | AuthAES128CCM | AuthSM4GCM ...]
+func EncryptAuthWithRecipients(rand io.Reader, content []byte, recipients []Recipient, cipher AuthCipher) ([]byte, error) {
+    if cipher == nil {
+        return nil, errors.New("pkcs7: unknown auth cipher")
+    }
+
+    key := make([]byte, cipher.KeySize())
+    if _, err := io.ReadFull(rand, key); err != nil {
+        return nil, errors.New("pkcs7: cannot generate key: " + err.Error())
+    }
+
+    // 没有 authAttrs 时附加数据为空
+    encrypted, mac, paramBytes, err := cipher.Encrypt(rand, key, content, nil)
+    if err != nil {
+        return nil, err
+    }
+
+    recipientInfos, _, err := makeRecipientInfos(rand, key, recipients)
+    if err != nil {
+        return nil, err
+    }
+
+    envelope := authEnvelopedData{
+        Version:        0,
+        RecipientInfos: recipientInfos,
+        AuthEncryptedContentInfo: encryptedContentInfo{
+            ContentType: oidData,
+            ContentEncryptionAlgorithm: pkix.AlgorithmIdentifier{
+                Algorithm: cipher.OID(),
+                Parameters: asn1.RawValue{
+                    FullBytes: paramBytes,
+                },
+            },
+            EncryptedContent: marshalEncryptedContent(encrypted),
+        },
+        MAC: mac,
+    }
+
+    innerContent, err := asn1.Marshal(envelope)
+    if err != nil {
+        return nil, err
+    }
+
+    wrapper := contentInfo{
+        ContentType: oidAuthEnvelopedData,
+        Content:     asn1.RawValue{Class: 2, Tag: 0, IsCompound: true, Bytes: innerContent},
+    }
+
+    return asn1.Marshal(wrapper)
+}
+
+func decryptAuthEnvelopedData(info []byte, key recipientKey) ([]byte, error) {
+    var endata authEnvelopedData
+    if _, err := asn1.Unmarshal(info, &endata); err != nil {
+        return nil, err
+    }
+
+    contentKey, err := decryptRecipientInfos(endata.RecipientInfos, key)
+    if err != nil {
+        return nil, err
+    }
+
+    eci := endata.AuthEncryptedContentInfo
+
+    oid := eci.ContentEncryptionAlgorithm.Algorithm.String()
+
+    fn, ok := authCiphers[oid]
+    if !ok {
+        return nil, fmt.Errorf("pkcs7: unsupported auth cipher (OID: %s)", oid)
+    }
+
+    // 附加数据为 authAttrs 的 DER 编码 (SET OF 标签)
+    var additionalData []byte
+    if len(endata.AuthAttrs) > 0 {
+        additionalData, err = marshalAttributes(endata.AuthAttrs)
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    return fn().Decrypt(
+        contentKey,
+        eci.ContentEncryptionAlgorithm.Parameters.FullBytes,
+        encryptedContentBytes(eci),
+        endata.MAC,
+        additionalData,
+    )
+}
@@ -0,0 +1,198 @@
+package encrypt
+
+import (
+    "bytes"
+    "testing"
+    "crypto/rand"
+    "crypto/x509"
+    "crypto/ecdsa"
+    "crypto/elliptic"
+    "encoding/asn1"
+
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+var (
+    // openssl cms -encrypt -aes-128-gcm -recip ec.crt -keyopt ecdh_kdf_md:sha256
+    testAuthKeyAgreeData = "MIIBGgYLKoZIhvcNAQkQARegggEJMIIBBQIBADGBtaGBsgIBA6BRoU8wCQYHKoZIzj0CAQNCAARm" +
+        "jA3jaHXgpsdInCWtXQAS4LQjC8pgbRZ0zqtY2tYJlhaHVxOWbFd1swlP2fBXE+nuwdmgSo86Zft9" +
+        "01rcGO+dMBUGBiuBBAELATALBglghkgBZQMEAQUwQzBBMCUwDTELMAkGA1UEAwwCZWMCFAWVSUzR" +
+        "Gmaa2RUi09RvmgjYRiJEBBgygAqUT5UwU2OixU7ooiZqhKn8zfHt+WgwNgYJKoZIhvcNAQcBMB4G" +
+        "CWCGSAFlAwQBBjARBAyHBh5WXegBaZaqcFACARCACT/FEmnBf7YZQwQQGfFsbhMHe22Yrcm8yHOr" +
+        "aA=="
+
+    // openssl cms -encrypt -aes-256-gcm -secretkey 000102030405060708090a0b0c0d0e0f -secretkeyid 6b6579
+    testAuthKEKData = "MIGlBgsqhkiG9w0BCRABF6CBlTCBkgIBADFDokECAQQwBQQDa2V5MAsGCWCGSAFlAwQBBQQo5Qjd" +
+        "8/80cbG1hK/Ix0bwDfN5yddSEXvJqunrGjee9a84DQj9gzKG5TA2BgkqhkiG9w0BBwEwHgYJYIZI" +
+        "AWUDBAEuMBEEDNeWYa9xFMhj4+E56QIBEIAJEMe/fcg5oALUBBD6bdR5kHT+hG5kGwvBha+Y"
+)
+
+func Test_DecryptAuthOpenSSL(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+
+    msg := []byte("hello cms")
+
+    cert, err := x509.ParseCertificate(decodeTestPEM(t, testECCert))
+    assertError(err, "Test_DecryptAuthOpenSSL-ParseCertificate")
+
+    key, err := x509.ParseECPrivateKey(decodeTestPEM(t, testECKey))
+    assertError(err, "Test_DecryptAuthOpenSSL-ParseECPrivateKey")
+
+    data := decodeTestBase64(t, testAuthKeyAgreeData)
+
+    res, err := Decrypt(data, cert, key)
+    assertError(err, "Test_DecryptAuthOpenSSL-KeyAgree")
+    assertEqual(res, msg, "Test_DecryptAuthOpenSSL-KeyAgree")
+
+    res, err = DecryptUsingKEK(decodeTestBase64(t, testAuthKEKData), []byte("key"), testKEK)
+    assertError(err, "Test_DecryptAuthOpenSSL-KEK")
+    assertEqual(res, msg, "Test_DecryptAuthOpenSSL-KEK")
+
+    // 修改 mac 最后一个字节
+    data[len(data)-1] ^= 1
+
+    res, err = Decrypt(data, cert, key)
+    assertNotErrorNil(err, "Test_DecryptAuthOpenSSL-Tampered")
+    assertEqual(len(res), 0, "Test_DecryptAuthOpenSSL-Tampered")
+}
+
+func Test_EncryptAuth(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+
+    msg := []byte("test data for auth enveloped")
+
+    ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    ecCert := newTestCert(t, &ecKey.PublicKey, ecKey)
+
+    for name, cipher := range map[string]AuthCipher{
+        "AES128GCM": AuthAES128GCM,
+        "AES192GCM": AuthAES192GCM,
+        "AES256GCM": AuthAES256GCM,
+        "AES128CCM": AuthAES128CCM,
+        "AES192CCM": AuthAES192CCM,
+        "AES256CCM": AuthAES256CCM,
+        "SM4GCM":    AuthSM4GCM,
+        "SM4CCM":    AuthSM4CCM,
+    } {
+        encrypted, err := EncryptAuth(rand.Reader, msg, []*x509.Certificate{ecCert}, cipher, Opts{
+            KeyAgree: KeyAgreeECDHSHA256,
+        })
+        assertError(err, "Test_EncryptAuth-Encrypt-" + name)
+
+        res, err := Decrypt(encrypted, ecCert, ecKey)
+        assertError(err, "Test_EncryptAuth-Decrypt-" + name)
+        assertEqual(res, msg, "Test_EncryptAuth-Decrypt-" + name)
+
+        // 修改密文
+        tampered := tamperEncryptedContent(t, encrypted)
+        _, err = Decrypt(tampered, ecCert, ecKey)
+        assertNotErrorNil(err, "Test_EncryptAuth-Tampered-" + name)
+    }
+}
+
+func Test_EncryptAuthWithRecipients(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+
+    msg := []byte("test data")
+
+    kek := make([]byte, 16)
+    rand.Read(kek)
+
+    encrypted, err := EncryptAuthWithRecipients(rand.Reader, msg, []Recipient{
+        NewKEKRecipient([]byte("kek-id"), kek),
+        NewPasswordRecipient([]byte("123456")),
+    }, AuthSM4GCM)
+    assertError(err, "Test_EncryptAuthWithRecipients-Encrypt")
+
+    res, err := DecryptUsingKEK(encrypted, []byte("kek-id"), kek)
+    assertError(err, "Test_EncryptAuthWithRecipients-DecryptUsingKEK")
+    assertEqual(res, msg, "Test_EncryptAuthWithRecipients-DecryptUsingKEK")
+
+    res, err = DecryptUsingPassword(encrypted, []byte("123456"))
+    assertError(err, "Test_EncryptAuthWithRecipients-DecryptUsingPassword")
+    assertEqual(res, msg, "Test_EncryptAuthWithRecipients-DecryptUsingPassword")
+}
+
+func Test_Authenticate(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+
+    msg := []byte("test data for authenticated")
+
+    ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    ecCert := newTestCert(t, &ecKey.PublicKey, ecKey)
+
+    for name, macHash := range map[string]Hash{
+        "SHA1":   SHA1,
+        "SHA224": SHA224,
+        "SHA256": SHA256,
+        "SHA384": SHA384,
+        "SHA512": SHA512,
+        "SM3":    SM3,
+    } {
+        data, err := Authenticate(rand.Reader, msg, []*x509.Certificate{ecCert}, macHash, Opts{
+            KeyAgree: KeyAgreeECDHSHA256,
+        })
+        assertError(err, "Test_Authenticate-" + name)
+
+        res, err := Decrypt(data, ecCert, ecKey)
+        assertError(err, "Test_Authenticate-Verify-" + name)
+        assertEqual(res, msg, "Test_Authenticate-Verify-" + name)
+
+        // 修改内容
+        idx := bytes.Index(data, msg)
+        if idx < 0 {
+            t.Fatal("content not found")
+        }
+
+        tampered := append([]byte{}, data...)
+        tampered[idx] ^= 1
+
+        res, err = Decrypt(tampered, ecCert, ecKey)
+        assertNotErrorNil(err, "Test_Authenticate-Tampered-" + name)
+        assertEqual(len(res), 0, "Test_Authenticate-Tampered-" + name)
+    }
+
+    data, err := AuthenticateWithRecipients(rand.Reader, msg, []Recipient{
+        NewPasswordRecipient([]byte("123456")),
+    }, SHA256)
+    assertError(err, "Test_Authenticate-Password")
+
+    res, err := DecryptUsingPassword(data, []byte("123456"))
+    assertError(err, "Test_Authenticate-Password-Verify")
+    assertEqual(res, msg, "Test_Authenticate-Password-Verify")
+
+    _, err = DecryptUsingPassword(data, []byte("654321"))
+    assertNotErrorNil(err, "Test_Authenticate-Password-bad")
+}
+
+// 修改 AuthEnvelopedData 中的密文
+func tamperEncryptedContent(t *testing.T, data []byte) []byte {
+    info, _, err := parseData(data)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    var endata authEnvelopedData
+    if _, err := asn1.Unmarshal(info, &endata); err != nil {
+        t.Fatal(err)
+    }
+
+    ciphertext := encryptedContentBytes(endata.AuthEncryptedContentInfo)
+
+    idx := bytes.Index(data, ciphertext)
+    if idx < 0 {
+        t.Fatal("ciphertext not found")
+    }
+
+    tampered := append([]byte{}, data...)
+    tampered[idx] ^= 1
+
+    return tampered
+}
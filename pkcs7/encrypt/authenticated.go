@@ -0,0 +1,291 @@
+package encrypt
+
+import (
+    "io"
+    "fmt"
+    "hash"
+    "errors"
+    "crypto/hmac"
+    "crypto/subtle"
+    "crypto/x509"
+    "crypto/x509/pkix"
+    "encoding/asn1"
+)
+
+var (
+    oidAuthenticatedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 2}
+
+    oidAttributeContentType   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+    oidAttributeMessageDigest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+
+    // RFC 3370 HMAC-SHA1
+    oidHMACSHA1 = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 8, 1, 2}
+
+    oidDigestSHA1   = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+    oidDigestSHA224 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 4}
+    oidDigestSHA256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+    oidDigestSHA384 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+    oidDigestSHA512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+    oidDigestSM3    = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 401}
+)
+
+// 摘要方式
+var digestOIDs = map[Hash]asn1.ObjectIdentifier{
+    SHA1:   oidDigestSHA1,
+    SHA224: oidDigestSHA224,
+    SHA256: oidDigestSHA256,
+    SHA384: oidDigestSHA384,
+    SHA512: oidDigestSHA512,
+    SM3:    oidDigestSM3,
+}
+
+// RFC 5652 9.1
+// AuthenticatedData ::= SEQUENCE {
+//     version CMSVersion,
+//     originatorInfo [0] IMPLICIT OriginatorInfo OPTIONAL,
+//     recipientInfos RecipientInfos,
+//     macAlgorithm MessageAuthenticationCodeAlgorithm,
+//     digestAlgorithm [1] DigestAlgorithmIdentifier OPTIONAL,
+//     encapContentInfo EncapsulatedContentInfo,
+//     authAttrs [2] IMPLICIT AuthAttributes OPTIONAL,
+//     mac MessageAuthenticationCode,
+//     unauthAttrs [3] IMPLICIT UnauthAttributes OPTIONAL }
+type authenticatedData struct {
+    Version          int
+    OriginatorInfo   asn1.RawValue            `asn1:"optional,tag:0"`
+    RecipientInfos   []asn1.RawValue          `asn1:"set"`
+    MACAlgorithm     pkix.AlgorithmIdentifier
+    DigestAlgorithm  pkix.AlgorithmIdentifier `asn1:"optional,tag:1"`
+    EncapContentInfo encapsulatedContentInfo
+    AuthAttrs        []attribute              `asn1:"optional,omitempty,tag:2"`
+    MAC              []byte
+    UnauthAttrs      []attribute              `asn1:"optional,omitempty,tag:3"`
+}
+
+type encapsulatedContentInfo struct {
+    EContentType asn1.ObjectIdentifier
+    EContent     []byte `asn1:"explicit,optional,tag:0"`
+}
+
+type attribute struct {
+    Type  asn1.ObjectIdentifier
+    Value asn1.RawValue `asn1:"set"`
+}
+
+// 生成 AuthenticatedData
+// opts 只使用接收者相关配置, 忽略 Cipher
+func Authenticate(rand io.Reader, content []byte, recipients []*x509.Certificate, macHash Hash, opts ...Opts) ([]byte, error) {
+    opt := &DefaultOpts
+    if len(opts) > 0 {
+        opt = &opts[0]
+    }
+
+    newRecipients, err := makeCertRecipients(recipients, opt)
+    if err != nil {
+        return nil, err
+    }
+
+    return AuthenticateWithRecipients(rand, content, newRecipients, macHash)
+}
+
+// 使用多种接收者生成 AuthenticatedData
+// 使用 HMAC 计算 mac, macHash 可用 [SHA1 | SHA224 | SHA256 | SHA384 | SHA512 | SM3]
+func AuthenticateWithRecipients(rand io.Reader, content []byte, recipients []Recipient, macHash Hash) ([]byte, error) {
+    h, ok := hmacHashs[macHash]
+    if !ok {
+        return nil, errors.New("pkcs7: unsupported mac hash")
+    }
+
+    macOID := h.oid
+    if macHash == SHA1 {
+        macOID = oidHMACSHA1
+    }
+
+    // mac 密钥长度为摘要长度, 对齐到 8 字节用于 key wrap
+    keySize := (h.hash().Size() + 7) / 8 * 8
+
+    key := make([]byte, keySize)
+    if _, err := io.ReadFull(rand, key); err != nil {
+        return nil, errors.New("pkcs7: cannot generate key: " + err.Error())
+    }
+
+    // 认证属性包含内容类型及内容摘要
+    digest := h.hash()
+    digest.Write(content)
+
+    authAttrs, err := makeAuthAttributes(digest.Sum(nil))
+    if err != nil {
+        return nil, err
+    }
+
+    authAttrsBytes, err := marshalAttributes(authAttrs)
+    if err != nil {
+        return nil, err
+    }
+
+    mac := hmac.New(h.hash, key)
+    mac.Write(authAttrsBytes)
+
+    recipientInfos, _, err := makeRecipientInfos(rand, key, recipients)
+    if err != nil {
+        return nil, err
+    }
+
+    ad := authenticatedData{
+        Version:        0,
+        RecipientInfos: recipientInfos,
+        MACAlgorithm: pkix.AlgorithmIdentifier{
+            Algorithm: macOID,
+        },
+        DigestAlgorithm: pkix.AlgorithmIdentifier{
+            Algorithm: digestOIDs[macHash],
+        },
+        EncapContentInfo: encapsulatedContentInfo{
+            EContentType: oidData,
+            EContent:     content,
+        },
+        AuthAttrs: authAttrs,
+        MAC:       mac.Sum(nil),
+    }
+
+    innerContent, err := asn1.Marshal(ad)
+    if err != nil {
+        return nil, err
+    }
+
+    wrapper := contentInfo{
+        ContentType: oidAuthenticatedData,
+        Content:     asn1.RawValue{Class: 2, Tag: 0, IsCompound: true, Bytes: innerContent},
+    }
+
+    return asn1.Marshal(wrapper)
+}
+
+// 验证 mac 并返回内容
+func verifyAuthenticatedData(info []byte, key recipientKey) ([]byte, error) {
+    var ad authenticatedData
+    if _, err := asn1.Unmarshal(info, &ad); err != nil {
+        return nil, err
+    }
+
+    macHashFunc, err := macHashByOID(ad.MACAlgorithm.Algorithm)
+    if err != nil {
+        return nil, err
+    }
+
+    macKey, err := decryptRecipientInfos(ad.RecipientInfos, key)
+    if err != nil {
+        return nil, err
+    }
+
+    content := ad.EncapContentInfo.EContent
+
+    mac := hmac.New(macHashFunc, macKey)
+
+    if len(ad.AuthAttrs) > 0 {
+        digestFunc, err := digestByOID(ad.DigestAlgorithm.Algorithm)
+        if err != nil {
+            return nil, err
+        }
+
+        var digest []byte
+        if err := unmarshalAttribute(ad.AuthAttrs, oidAttributeMessageDigest, &digest); err != nil {
+            return nil, err
+        }
+
+        h := digestFunc()
+        h.Write(content)
+
+        if subtle.ConstantTimeCompare(digest, h.Sum(nil)) != 1 {
+            return nil, errors.New("pkcs7: message digest mismatch")
+        }
+
+        authAttrsBytes, err := marshalAttributes(ad.AuthAttrs)
+        if err != nil {
+            return nil, err
+        }
+
+        mac.Write(authAttrsBytes)
+    } else {
+        mac.Write(content)
+    }
+
+    if !hmac.Equal(mac.Sum(nil), ad.MAC) {
+        return nil, errors.New("pkcs7: message authentication failed")
+    }
+
+    return content, nil
+}
+
+func makeAuthAttributes(digest []byte) ([]attribute, error) {
+    contentType, err := asn1.Marshal(oidData)
+    if err != nil {
+        return nil, err
+    }
+
+    messageDigest, err := asn1.Marshal(digest)
+    if err != nil {
+        return nil, err
+    }
+
+    return []attribute{
+        {
+            Type:  oidAttributeContentType,
+            Value: asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: contentType},
+        },
+        {
+            Type:  oidAttributeMessageDigest,
+            Value: asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: messageDigest},
+        },
+    }, nil
+}
+
+// 编码为 SET OF 用于计算 mac
+func marshalAttributes(attrs []attribute) ([]byte, error) {
+    encodedAttributes, err := asn1.Marshal(struct {
+        A []attribute `asn1:"set"`
+    }{A: attrs})
+    if err != nil {
+        return nil, err
+    }
+
+    // Remove the leading sequence octets
+    var raw asn1.RawValue
+    asn1.Unmarshal(encodedAttributes, &raw)
+    return raw.Bytes, nil
+}
+
+func unmarshalAttribute(attrs []attribute, attributeType asn1.ObjectIdentifier, out any) error {
+    for _, attr := range attrs {
+        if attr.Type.Equal(attributeType) {
+            _, err := asn1.Unmarshal(attr.Value.Bytes, out)
+            return err
+        }
+    }
+
+    return fmt.Errorf("pkcs7: attribute type not in attributes (OID: %s)", attributeType)
+}
+
+func macHashByOID(oid asn1.ObjectIdentifier) (func() hash.Hash, error) {
+    if oid.Equal(oidHMACSHA1) {
+        return hmacHashs[SHA1].hash, nil
+    }
+
+    for _, h := range hmacHashs {
+        if h.oid.Equal(oid) {
+            return h.hash, nil
+        }
+    }
+
+    return nil, fmt.Errorf("pkcs7: unsupported mac algorithm (OID: %s)", oid)
+}
+
+func digestByOID(oid asn1.ObjectIdentifier) (func() hash.Hash, error) {
+    for h, digestOID := range digestOIDs {
+        if digestOID.Equal(oid) {
+            return hmacHashs[h].hash, nil
+        }
+    }
+
+    return nil, fmt.Errorf("pkcs7: unsupported digest algorithm (OID: %s)", oid)
+}
@@ -13,32 +13,53 @@ import (
 )
 
 // 解析
+// 支持 EnvelopedData, AuthEnvelopedData 及 AuthenticatedData
 func Decrypt(data []byte, cert *x509.Certificate, pkey crypto.PrivateKey) ([]byte, error) {
+    return decryptWithRecipientKey(data, certRecipientKey{cert, pkey})
+}
+
+// 使用 SubjectKeyIdentifier 查找密钥协商接收者并解密
+func DecryptWithKeyID(data []byte, keyID []byte, pkey crypto.PrivateKey) ([]byte, error) {
+    return decryptWithRecipientKey(data, keyIDRecipientKey{keyID, pkey})
+}
+
+// 使用预共享密钥 (KEKRecipientInfo) 解密
+func DecryptUsingKEK(data []byte, keyID []byte, kek []byte) ([]byte, error) {
+    return decryptWithRecipientKey(data, kekRecipientKey{keyID, kek})
+}
+
+// 使用密码 (PasswordRecipientInfo) 解密
+func DecryptUsingPassword(data []byte, password []byte) ([]byte, error) {
+    return decryptWithRecipientKey(data, passwordRecipientKey{password})
+}
+
+// 根据内容类型解密
+// AuthenticatedData 验证 mac 后返回内容
+func decryptWithRecipientKey(data []byte, key recipientKey) ([]byte, error) {
     info, contentType, err := parseData(data)
     if err != nil {
         return nil, err
     }
 
-    if !contentType.Equal(oidEnvelopedData) {
-        return nil, errors.New("pkcs7: contentType error")
+    switch {
+        case contentType.Equal(oidEnvelopedData):
+            return decryptEnvelopedData(info, key)
+        case contentType.Equal(oidAuthEnvelopedData):
+            return decryptAuthEnvelopedData(info, key)
+        case contentType.Equal(oidAuthenticatedData):
+            return verifyAuthenticatedData(info, key)
     }
 
+    return nil, errors.New("pkcs7: contentType error")
+}
+
+func decryptEnvelopedData(info []byte, key recipientKey) ([]byte, error) {
     var endata envelopedData
     if _, err := asn1.Unmarshal(info, &endata); err != nil {
         return nil, err
     }
 
-    recipient := selectRecipientForCertificate(endata.RecipientInfos, cert)
-    if recipient.EncryptedKey == nil {
-        return nil, errors.New("pkcs7: no enveloped recipient for provided certificate")
-    }
-
-    keyEncrypt, err := parseKeyEncrypt(recipient.KeyEncryptionAlgorithm)
-    if err != nil {
-        return nil, err
-    }
-
-    contentKey, err := keyEncrypt.Decrypt(recipient.EncryptedKey, pkey)
+    contentKey, err := decryptRecipientInfos(endata.RecipientInfos, key)
     if err != nil {
         return nil, err
     }
@@ -67,9 +88,24 @@ func DecryptUsingPSK(data []byte, key []byte) ([]byte, error) {
 }
 
 func encryptedContentInfoDecrypt(eci encryptedContentInfo, key []byte) ([]byte, error) {
+    cyphertext := encryptedContentBytes(eci)
+
+    cipher, cipherParams, err := parseEncryptionScheme(eci.ContentEncryptionAlgorithm)
+    if err != nil {
+        return nil, err
+    }
+
+    decryptedKey, err := cipher.Decrypt(key, cipherParams, cyphertext)
+    if err != nil {
+        return nil, err
+    }
+
+    return decryptedKey, nil
+}
+
+func encryptedContentBytes(eci encryptedContentInfo) []byte {
     // EncryptedContent can either be constructed of multple OCTET STRINGs
     // or _be_ a tagged OCTET STRING
-    var cyphertext []byte
     if eci.EncryptedContent.IsCompound {
         // Complex case to concat all of the children OCTET STRINGs
         var buf bytes.Buffer
@@ -82,23 +118,12 @@ func encryptedContentInfoDecrypt(eci encryptedContentInfo, key []byte) ([]byte,
                 break
             }
         }
-        cyphertext = buf.Bytes()
-    } else {
-        // Simple case, the bytes _are_ the cyphertext
-        cyphertext = eci.EncryptedContent.Bytes
-    }
 
-    cipher, cipherParams, err := parseEncryptionScheme(eci.ContentEncryptionAlgorithm)
-    if err != nil {
-        return nil, err
+        return buf.Bytes()
     }
 
-    decryptedKey, err := cipher.Decrypt(key, cipherParams, cyphertext)
-    if err != nil {
-        return nil, err
-    }
-
-    return decryptedKey, nil
+    // Simple case, the bytes _are_ the cyphertext
+    return eci.EncryptedContent.Bytes
 }
 
 func parseKeyEncrypt(keyEncrypt pkix.AlgorithmIdentifier) (KeyEncrypt, error) {
@@ -151,16 +176,6 @@ func parseData(data []byte) ([]byte, asn1.ObjectIdentifier, error) {
     return content, contentType, nil
 }
 
-func selectRecipientForCertificate(recipients []recipientInfo, cert *x509.Certificate) recipientInfo {
-    for _, recp := range recipients {
-        if isCertMatchForIssuerAndSerial(cert, recp.IssuerAndSerialNumber) {
-            return recp
-        }
-    }
-
-    return recipientInfo{}
-}
-
 func isCertMatchForIssuerAndSerial(cert *x509.Certificate, ias issuerAndSerial) bool {
     return cert.SerialNumber.Cmp(ias.SerialNumber) == 0 && bytes.Equal(cert.RawIssuer, ias.IssuerName.FullBytes)
 }
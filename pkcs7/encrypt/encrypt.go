@@ -23,7 +23,7 @@ type issuerAndSerial struct {
 
 type envelopedData struct {
     Version              int
-    RecipientInfos       []recipientInfo `asn1:"set"`
+    RecipientInfos       []asn1.RawValue `asn1:"set"`
     EncryptedContentInfo encryptedContentInfo
 }
 
@@ -58,6 +58,12 @@ var ErrPSKNotProvided = errors.New("pkcs7: cannot encrypt content: PSK not provi
 type Opts struct {
     Cipher     Cipher
     KeyEncrypt KeyEncrypt
+
+    // 设置时使用密钥协商 (KeyAgreeRecipientInfo), 忽略 KeyEncrypt
+    KeyAgree KeyAgree
+
+    // 密钥协商使用的 key wrap, 默认为 KeyWrapAES256
+    KeyWrap KeyWrap
 }
 
 // 默认配置
@@ -68,27 +74,46 @@ var DefaultOpts = Opts{
 
 // 加密
 func Encrypt(rand io.Reader, content []byte, recipients []*x509.Certificate, opts ...Opts) ([]byte, error) {
-    var eci *encryptedContentInfo
-    var key []byte
-    var err error
-
     opt := &DefaultOpts
     if len(opts) > 0 {
         opt = &opts[0]
     }
 
-    cipher := opt.Cipher
-    if cipher == nil {
-        return nil, errors.New("pkcs7: failed to encrypt PEM: unknown opts cipher")
+    newRecipients, err := makeCertRecipients(recipients, opt)
+    if err != nil {
+        return nil, err
     }
 
-    keyEncrypt := opt.KeyEncrypt
-    if keyEncrypt == nil {
+    return EncryptWithRecipients(rand, content, newRecipients, opt.Cipher)
+}
+
+// 证书生成接收者
+func makeCertRecipients(certs []*x509.Certificate, opt *Opts) ([]Recipient, error) {
+    if opt.KeyAgree == nil && opt.KeyEncrypt == nil {
         return nil, errors.New("pkcs7: unknown opts keyEncrypt")
     }
 
+    recipients := make([]Recipient, len(certs))
+    for i, cert := range certs {
+        if opt.KeyAgree != nil {
+            recipients[i] = NewKeyAgreeRecipient(cert, opt.KeyAgree, opt.KeyWrap)
+        } else {
+            recipients[i] = NewKeyTransRecipient(cert, opt.KeyEncrypt)
+        }
+    }
+
+    return recipients, nil
+}
+
+// 使用多种接收者加密
+// 接收者可用 [NewKeyTransRecipient | NewKeyAgreeRecipient | NewKEKRecipient | NewPasswordRecipient]
+func EncryptWithRecipients(rand io.Reader, content []byte, recipients []Recipient, cipher Cipher) ([]byte, error) {
+    if cipher == nil {
+        return nil, errors.New("pkcs7: failed to encrypt PEM: unknown opts cipher")
+    }
+
     // 生成密钥
-    key = make([]byte, cipher.KeySize())
+    key := make([]byte, cipher.KeySize())
     if _, err := io.ReadFull(rand, key); err != nil {
         return nil, errors.New("pkcs7: cannot generate key: " + err.Error())
     }
@@ -98,7 +123,7 @@ func Encrypt(rand io.Reader, content []byte, recipients []*x509.Certificate, opt
         return nil, err
     }
 
-    eci = &encryptedContentInfo{
+    eci := encryptedContentInfo{
         ContentType: oidData,
         ContentEncryptionAlgorithm: pkix.AlgorithmIdentifier{
             Algorithm: cipher.OID(),
@@ -110,33 +135,15 @@ func Encrypt(rand io.Reader, content []byte, recipients []*x509.Certificate, opt
     }
 
     // Prepare each recipient's encrypted cipher key
-    recipientInfos := make([]recipientInfo, len(recipients))
-    for i, recipient := range recipients {
-        encrypted, err := keyEncrypt.Encrypt(key, recipient.PublicKey)
-        if err != nil {
-            return nil, err
-        }
-
-        ias, err := cert2issuerAndSerial(recipient)
-        if err != nil {
-            return nil, err
-        }
-
-        info := recipientInfo{
-            Version:               0,
-            IssuerAndSerialNumber: ias,
-            KeyEncryptionAlgorithm: pkix.AlgorithmIdentifier{
-                Algorithm: keyEncrypt.OID(),
-            },
-            EncryptedKey: encrypted,
-        }
-        recipientInfos[i] = info
+    recipientInfos, version, err := makeRecipientInfos(rand, key, recipients)
+    if err != nil {
+        return nil, err
     }
 
     // Prepare envelope content
     envelope := envelopedData{
-        EncryptedContentInfo: *eci,
-        Version:              0,
+        EncryptedContentInfo: eci,
+        Version:              version,
         RecipientInfos:       recipientInfos,
     }
     innerContent, err := asn1.Marshal(envelope)
@@ -1,6 +1,7 @@
 package encrypt
 
 import (
+    "io"
     "crypto"
     "encoding/asn1"
 )
@@ -23,3 +24,63 @@ var keyens = make(map[string]func() KeyEncrypt)
 func AddkeyEncrypt(oid asn1.ObjectIdentifier, fn func() KeyEncrypt) {
     keyens[oid.String()] = fn
 }
+
+// 密钥协商, 用于 KeyAgreeRecipientInfo
+type KeyAgree interface {
+    // oid
+    OID() asn1.ObjectIdentifier
+
+    // 使用共享密钥派生密钥加密密钥
+    DeriveKey(secret, sharedInfo []byte, size int) ([]byte, error)
+}
+
+var keyAgrees = make(map[string]func() KeyAgree)
+
+// 添加密钥协商方式
+func AddKeyAgree(oid asn1.ObjectIdentifier, fn func() KeyAgree) {
+    keyAgrees[oid.String()] = fn
+}
+
+// 密钥包装, 用于 KeyAgreeRecipientInfo 及 KEKRecipientInfo
+type KeyWrap interface {
+    // oid
+    OID() asn1.ObjectIdentifier
+
+    // 密钥长度
+    KeySize() int
+
+    // 包装
+    Wrap(kek, key []byte) ([]byte, error)
+
+    // 解包装
+    Unwrap(kek, wrapped []byte) ([]byte, error)
+}
+
+var keyWraps = make(map[string]func() KeyWrap)
+
+// 添加密钥包装方式
+func AddKeyWrap(oid asn1.ObjectIdentifier, fn func() KeyWrap) {
+    keyWraps[oid.String()] = fn
+}
+
+// 认证加密, 用于 AuthEnvelopedData
+type AuthCipher interface {
+    // oid
+    OID() asn1.ObjectIdentifier
+
+    // 密钥长度
+    KeySize() int
+
+    // 加密, 返回: [加密后数据, mac, 参数, error]
+    Encrypt(rand io.Reader, key, plaintext, additionalData []byte) ([]byte, []byte, []byte, error)
+
+    // 解密, mac 验证失败时返回错误
+    Decrypt(key, params, ciphertext, mac, additionalData []byte) ([]byte, error)
+}
+
+var authCiphers = make(map[string]func() AuthCipher)
+
+// 添加认证加密方式
+func AddAuthCipher(oid asn1.ObjectIdentifier, fn func() AuthCipher) {
+    authCiphers[oid.String()] = fn
+}
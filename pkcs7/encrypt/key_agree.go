@@ -0,0 +1,43 @@
+package encrypt
+
+import (
+    "hash"
+    "errors"
+    "encoding/binary"
+    "encoding/asn1"
+)
+
+// ANSI X9.63 KDF 密钥协商, RFC 5753 及 RFC 8418
+type KeyAgreeWithECDH struct {
+    hashFunc   func() hash.Hash
+    identifier asn1.ObjectIdentifier
+}
+
+// oid
+func (this KeyAgreeWithECDH) OID() asn1.ObjectIdentifier {
+    return this.identifier
+}
+
+// 派生密钥
+func (this KeyAgreeWithECDH) DeriveKey(secret, sharedInfo []byte, size int) ([]byte, error) {
+    if size <= 0 {
+        return nil, errors.New("pkcs7: invalid key size")
+    }
+
+    h := this.hashFunc()
+
+    var counter [4]byte
+    key := make([]byte, 0, size + h.Size())
+
+    for i := uint32(1); len(key) < size; i++ {
+        binary.BigEndian.PutUint32(counter[:], i)
+
+        h.Reset()
+        h.Write(secret)
+        h.Write(counter[:])
+        h.Write(sharedInfo)
+        key = h.Sum(key)
+    }
+
+    return key[:size], nil
+}
@@ -0,0 +1,179 @@
+package encrypt
+
+import (
+    "io"
+    "errors"
+    "math/big"
+    "crypto"
+    "crypto/ecdh"
+    "crypto/ecdsa"
+    "crypto/elliptic"
+    "crypto/x509/pkix"
+    "encoding/asn1"
+
+    "github.com/deatil/go-cryptobin/x448"
+    "github.com/deatil/go-cryptobin/x25519"
+    "github.com/deatil/go-cryptobin/gm/sm2"
+)
+
+var (
+    oidPublicKeyECDSA  = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+    oidPublicKeyX25519 = asn1.ObjectIdentifier{1, 3, 101, 110}
+    oidPublicKeyX448   = asn1.ObjectIdentifier{1, 3, 101, 111}
+)
+
+type originatorPublicKey struct {
+    Algorithm pkix.AlgorithmIdentifier
+    PublicKey asn1.BitString
+}
+
+func newOriginatorPublicKey(oid asn1.ObjectIdentifier, pub []byte) originatorPublicKey {
+    return originatorPublicKey{
+        Algorithm: pkix.AlgorithmIdentifier{
+            Algorithm: oid,
+        },
+        PublicKey: asn1.BitString{
+            Bytes:     pub,
+            BitLength: 8 * len(pub),
+        },
+    }
+}
+
+// 获取椭圆曲线公钥, 支持 NIST, Brainpool 及 SM2 曲线
+func curvePublicKey(pub crypto.PublicKey) (elliptic.Curve, *big.Int, *big.Int, bool) {
+    switch k := pub.(type) {
+        case *ecdsa.PublicKey:
+            return k.Curve, k.X, k.Y, true
+        case *sm2.PublicKey:
+            return k.Curve, k.X, k.Y, true
+    }
+
+    return nil, nil, nil, false
+}
+
+// 获取椭圆曲线私钥
+func curvePrivateKey(priv crypto.PrivateKey) (elliptic.Curve, *big.Int, bool) {
+    switch k := priv.(type) {
+        case *ecdsa.PrivateKey:
+            return k.Curve, k.D, true
+        case *sm2.PrivateKey:
+            return k.Curve, k.D, true
+    }
+
+    return nil, nil, false
+}
+
+// 计算 x 坐标作为共享密钥
+func curveSharedSecret(curve elliptic.Curve, x, y *big.Int, d []byte) ([]byte, error) {
+    if !curve.IsOnCurve(x, y) {
+        return nil, errors.New("pkcs7: invalid public key")
+    }
+
+    sx, _ := curve.ScalarMult(x, y, d)
+    if sx.Sign() == 0 {
+        return nil, errors.New("pkcs7: invalid shared secret")
+    }
+
+    secret := make([]byte, (curve.Params().BitSize + 7) / 8)
+
+    return sx.FillBytes(secret), nil
+}
+
+// 生成临时密钥并计算共享密钥
+func ecdhEphemeral(rand io.Reader, pub crypto.PublicKey) (originatorPublicKey, []byte, error) {
+    if curve, x, y, ok := curvePublicKey(pub); ok {
+        ephemeral, err := ecdsa.GenerateKey(curve, rand)
+        if err != nil {
+            return originatorPublicKey{}, nil, err
+        }
+
+        secret, err := curveSharedSecret(curve, x, y, ephemeral.D.Bytes())
+        if err != nil {
+            return originatorPublicKey{}, nil, err
+        }
+
+        point := elliptic.Marshal(curve, ephemeral.X, ephemeral.Y)
+
+        return newOriginatorPublicKey(oidPublicKeyECDSA, point), secret, nil
+    }
+
+    switch k := pub.(type) {
+        case *ecdh.PublicKey:
+            ephemeral, err := k.Curve().GenerateKey(rand)
+            if err != nil {
+                return originatorPublicKey{}, nil, err
+            }
+
+            secret, err := ephemeral.ECDH(k)
+            if err != nil {
+                return originatorPublicKey{}, nil, err
+            }
+
+            oid := oidPublicKeyECDSA
+            if k.Curve() == ecdh.X25519() {
+                oid = oidPublicKeyX25519
+            }
+
+            return newOriginatorPublicKey(oid, ephemeral.PublicKey().Bytes()), secret, nil
+
+        case x25519.PublicKey:
+            ephemeralPub, ephemeral, err := x25519.GenerateKey(rand)
+            if err != nil {
+                return originatorPublicKey{}, nil, err
+            }
+
+            secret, err := x25519.X25519(ephemeral.Seed(), k)
+            if err != nil {
+                return originatorPublicKey{}, nil, err
+            }
+
+            return newOriginatorPublicKey(oidPublicKeyX25519, ephemeralPub), secret, nil
+
+        case x448.PublicKey:
+            ephemeralPub, ephemeral, err := x448.GenerateKey(rand)
+            if err != nil {
+                return originatorPublicKey{}, nil, err
+            }
+
+            secret, err := x448.X448(ephemeral.Seed(), k)
+            if err != nil {
+                return originatorPublicKey{}, nil, err
+            }
+
+            return newOriginatorPublicKey(oidPublicKeyX448, ephemeralPub), secret, nil
+    }
+
+    return originatorPublicKey{}, nil, errors.New("pkcs7: unsupported key agreement public key")
+}
+
+// 使用接收者私钥计算共享密钥
+func ecdhSharedSecret(priv crypto.PrivateKey, originator originatorPublicKey) ([]byte, error) {
+    pub := originator.PublicKey.RightAlign()
+
+    if curve, d, ok := curvePrivateKey(priv); ok {
+        x, y := elliptic.Unmarshal(curve, pub)
+        if x == nil {
+            return nil, errors.New("pkcs7: invalid originator public key")
+        }
+
+        return curveSharedSecret(curve, x, y, d.Bytes())
+    }
+
+    switch k := priv.(type) {
+        case *ecdh.PrivateKey:
+            remote, err := k.Curve().NewPublicKey(pub)
+            if err != nil {
+                return nil, err
+            }
+
+            return k.ECDH(remote)
+
+        case x25519.PrivateKey:
+            return x25519.X25519(k.Seed(), pub)
+
+        case x448.PrivateKey:
+            return x448.X448(k.Seed(), pub)
+    }
+
+    return nil, errors.New("pkcs7: unsupported key agreement private key")
+}
@@ -0,0 +1,148 @@
+package encrypt
+
+import (
+    "crypto/aes"
+    "crypto/sha1"
+    "crypto/sha256"
+    "crypto/sha512"
+    "encoding/asn1"
+)
+
+var (
+    // RFC 5753 密钥协商
+    oidDhSinglePassStdDHSHA1KDF   = asn1.ObjectIdentifier{1, 3, 133, 16, 840, 63, 0, 2}
+    oidDhSinglePassStdDHSHA224KDF = asn1.ObjectIdentifier{1, 3, 132, 1, 11, 0}
+    oidDhSinglePassStdDHSHA256KDF = asn1.ObjectIdentifier{1, 3, 132, 1, 11, 1}
+    oidDhSinglePassStdDHSHA384KDF = asn1.ObjectIdentifier{1, 3, 132, 1, 11, 2}
+    oidDhSinglePassStdDHSHA512KDF = asn1.ObjectIdentifier{1, 3, 132, 1, 11, 3}
+
+    oidDhSinglePassCofactorDHSHA1KDF   = asn1.ObjectIdentifier{1, 3, 133, 16, 840, 63, 0, 3}
+    oidDhSinglePassCofactorDHSHA224KDF = asn1.ObjectIdentifier{1, 3, 132, 1, 14, 0}
+    oidDhSinglePassCofactorDHSHA256KDF = asn1.ObjectIdentifier{1, 3, 132, 1, 14, 1}
+    oidDhSinglePassCofactorDHSHA384KDF = asn1.ObjectIdentifier{1, 3, 132, 1, 14, 2}
+    oidDhSinglePassCofactorDHSHA512KDF = asn1.ObjectIdentifier{1, 3, 132, 1, 14, 3}
+
+    // 密钥包装
+    oidAES128Wrap = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 5}
+    oidAES192Wrap = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 25}
+    oidAES256Wrap = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 45}
+)
+
+// KeyAgreeECDHSHA1
+var KeyAgreeECDHSHA1 = KeyAgreeWithECDH{
+    hashFunc:   sha1.New,
+    identifier: oidDhSinglePassStdDHSHA1KDF,
+}
+
+// KeyAgreeECDHSHA224
+var KeyAgreeECDHSHA224 = KeyAgreeWithECDH{
+    hashFunc:   sha256.New224,
+    identifier: oidDhSinglePassStdDHSHA224KDF,
+}
+
+// KeyAgreeECDHSHA256
+var KeyAgreeECDHSHA256 = KeyAgreeWithECDH{
+    hashFunc:   sha256.New,
+    identifier: oidDhSinglePassStdDHSHA256KDF,
+}
+
+// KeyAgreeECDHSHA384
+var KeyAgreeECDHSHA384 = KeyAgreeWithECDH{
+    hashFunc:   sha512.New384,
+    identifier: oidDhSinglePassStdDHSHA384KDF,
+}
+
+// KeyAgreeECDHSHA512
+var KeyAgreeECDHSHA512 = KeyAgreeWithECDH{
+    hashFunc:   sha512.New,
+    identifier: oidDhSinglePassStdDHSHA512KDF,
+}
+
+// 支持的曲线余因子都为 1, cofactorDH 和 stdDH 结果相同
+var (
+    KeyAgreeCofactorECDHSHA1 = KeyAgreeWithECDH{
+        hashFunc:   sha1.New,
+        identifier: oidDhSinglePassCofactorDHSHA1KDF,
+    }
+    KeyAgreeCofactorECDHSHA224 = KeyAgreeWithECDH{
+        hashFunc:   sha256.New224,
+        identifier: oidDhSinglePassCofactorDHSHA224KDF,
+    }
+    KeyAgreeCofactorECDHSHA256 = KeyAgreeWithECDH{
+        hashFunc:   sha256.New,
+        identifier: oidDhSinglePassCofactorDHSHA256KDF,
+    }
+    KeyAgreeCofactorECDHSHA384 = KeyAgreeWithECDH{
+        hashFunc:   sha512.New384,
+        identifier: oidDhSinglePassCofactorDHSHA384KDF,
+    }
+    KeyAgreeCofactorECDHSHA512 = KeyAgreeWithECDH{
+        hashFunc:   sha512.New,
+        identifier: oidDhSinglePassCofactorDHSHA512KDF,
+    }
+)
+
+// KeyWrapAES128
+var KeyWrapAES128 = KeyWrapWithFunc{
+    cipherFunc: aes.NewCipher,
+    keySize:    16,
+    identifier: oidAES128Wrap,
+}
+
+// KeyWrapAES192
+var KeyWrapAES192 = KeyWrapWithFunc{
+    cipherFunc: aes.NewCipher,
+    keySize:    24,
+    identifier: oidAES192Wrap,
+}
+
+// KeyWrapAES256
+var KeyWrapAES256 = KeyWrapWithFunc{
+    cipherFunc: aes.NewCipher,
+    keySize:    32,
+    identifier: oidAES256Wrap,
+}
+
+func init() {
+    AddKeyAgree(oidDhSinglePassStdDHSHA1KDF, func() KeyAgree {
+        return KeyAgreeECDHSHA1
+    })
+    AddKeyAgree(oidDhSinglePassStdDHSHA224KDF, func() KeyAgree {
+        return KeyAgreeECDHSHA224
+    })
+    AddKeyAgree(oidDhSinglePassStdDHSHA256KDF, func() KeyAgree {
+        return KeyAgreeECDHSHA256
+    })
+    AddKeyAgree(oidDhSinglePassStdDHSHA384KDF, func() KeyAgree {
+        return KeyAgreeECDHSHA384
+    })
+    AddKeyAgree(oidDhSinglePassStdDHSHA512KDF, func() KeyAgree {
+        return KeyAgreeECDHSHA512
+    })
+
+    AddKeyAgree(oidDhSinglePassCofactorDHSHA1KDF, func() KeyAgree {
+        return KeyAgreeCofactorECDHSHA1
+    })
+    AddKeyAgree(oidDhSinglePassCofactorDHSHA224KDF, func() KeyAgree {
+        return KeyAgreeCofactorECDHSHA224
+    })
+    AddKeyAgree(oidDhSinglePassCofactorDHSHA256KDF, func() KeyAgree {
+        return KeyAgreeCofactorECDHSHA256
+    })
+    AddKeyAgree(oidDhSinglePassCofactorDHSHA384KDF, func() KeyAgree {
+        return KeyAgreeCofactorECDHSHA384
+    })
+    AddKeyAgree(oidDhSinglePassCofactorDHSHA512KDF, func() KeyAgree {
+        return KeyAgreeCofactorECDHSHA512
+    })
+
+    AddKeyWrap(oidAES128Wrap, func() KeyWrap {
+        return KeyWrapAES128
+    })
+    AddKeyWrap(oidAES192Wrap, func() KeyWrap {
+        return KeyWrapAES192
+    })
+    AddKeyWrap(oidAES256Wrap, func() KeyWrap {
+        return KeyWrapAES256
+    })
+}
@@ -0,0 +1,54 @@
+package encrypt
+
+import (
+    "errors"
+    "crypto/cipher"
+    "encoding/asn1"
+
+    "github.com/deatil/go-cryptobin/cipher/keywrap"
+)
+
+// 密钥包装
+type KeyWrapWithFunc struct {
+    cipherFunc func(key []byte) (cipher.Block, error)
+    keySize    int
+    identifier asn1.ObjectIdentifier
+}
+
+// oid
+func (this KeyWrapWithFunc) OID() asn1.ObjectIdentifier {
+    return this.identifier
+}
+
+// 密钥长度
+func (this KeyWrapWithFunc) KeySize() int {
+    return this.keySize
+}
+
+// 包装
+func (this KeyWrapWithFunc) Wrap(kek, key []byte) ([]byte, error) {
+    if len(kek) != this.keySize {
+        return nil, errors.New("pkcs7: invalid key encryption key length")
+    }
+
+    block, err := this.cipherFunc(kek)
+    if err != nil {
+        return nil, err
+    }
+
+    return keywrap.Wrap(block, nil, key)
+}
+
+// 解包装
+func (this KeyWrapWithFunc) Unwrap(kek, wrapped []byte) ([]byte, error) {
+    if len(kek) != this.keySize {
+        return nil, errors.New("pkcs7: invalid key encryption key length")
+    }
+
+    block, err := this.cipherFunc(kek)
+    if err != nil {
+        return nil, err
+    }
+
+    return keywrap.Unwrap(block, nil, wrapped)
+}
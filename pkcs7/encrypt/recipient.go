@@ -0,0 +1,209 @@
+package encrypt
+
+import (
+    "io"
+    "fmt"
+    "bytes"
+    "errors"
+    "crypto"
+    "crypto/x509"
+    "crypto/x509/pkix"
+    "encoding/asn1"
+)
+
+// RecipientInfo 的标签
+const (
+    recipientTagKeyAgree = 1
+    recipientTagKEK      = 2
+    recipientTagPassword = 3
+)
+
+var errNoRecipient = errors.New("pkcs7: no enveloped recipient for provided key")
+
+// 接收者
+type Recipient interface {
+    // 生成 RecipientInfo, key 为内容加密密钥
+    RecipientInfo(rand io.Reader, key []byte) (asn1.RawValue, error)
+}
+
+// 解密使用的密钥
+type recipientKey interface {
+    // 从 RecipientInfo 解出内容加密密钥, 不匹配时返回 nil
+    decryptKey(ri asn1.RawValue) ([]byte, error)
+}
+
+// ==========
+
+// 密钥传输接收者
+type keyTransRecipient struct {
+    cert       *x509.Certificate
+    keyEncrypt KeyEncrypt
+}
+
+// 密钥传输接收者 (KeyTransRecipientInfo)
+func NewKeyTransRecipient(cert *x509.Certificate, keyEncrypt KeyEncrypt) Recipient {
+    return keyTransRecipient{
+        cert:       cert,
+        keyEncrypt: keyEncrypt,
+    }
+}
+
+func (this keyTransRecipient) RecipientInfo(rand io.Reader, key []byte) (asn1.RawValue, error) {
+    if this.cert == nil || this.keyEncrypt == nil {
+        return asn1.RawValue{}, errors.New("pkcs7: cert or keyEncrypt is empty")
+    }
+
+    encrypted, err := this.keyEncrypt.Encrypt(key, this.cert.PublicKey)
+    if err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    ias, err := cert2issuerAndSerial(this.cert)
+    if err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    info := recipientInfo{
+        Version:               0,
+        IssuerAndSerialNumber: ias,
+        KeyEncryptionAlgorithm: pkix.AlgorithmIdentifier{
+            Algorithm: this.keyEncrypt.OID(),
+        },
+        EncryptedKey: encrypted,
+    }
+
+    return marshalRecipientInfo(info, 0)
+}
+
+// ==========
+
+// 证书及私钥
+type certRecipientKey struct {
+    cert *x509.Certificate
+    pkey crypto.PrivateKey
+}
+
+func (this certRecipientKey) decryptKey(ri asn1.RawValue) ([]byte, error) {
+    switch {
+        case ri.Class == asn1.ClassUniversal && ri.Tag == asn1.TagSequence:
+            var info recipientInfo
+            if _, err := asn1.Unmarshal(ri.FullBytes, &info); err != nil {
+                return nil, nil
+            }
+
+            if !isCertMatchForIssuerAndSerial(this.cert, info.IssuerAndSerialNumber) {
+                return nil, nil
+            }
+
+            keyEncrypt, err := parseKeyEncrypt(info.KeyEncryptionAlgorithm)
+            if err != nil {
+                return nil, err
+            }
+
+            return keyEncrypt.Decrypt(info.EncryptedKey, this.pkey)
+
+        case ri.Class == asn1.ClassContextSpecific && ri.Tag == recipientTagKeyAgree:
+            return decryptKeyAgree(ri, this.pkey, func(rid asn1.RawValue) bool {
+                return isCertMatchForRecipientID(this.cert, rid)
+            })
+    }
+
+    return nil, nil
+}
+
+// 使用 SubjectKeyIdentifier 查找接收者
+type keyIDRecipientKey struct {
+    keyID []byte
+    pkey  crypto.PrivateKey
+}
+
+func (this keyIDRecipientKey) decryptKey(ri asn1.RawValue) ([]byte, error) {
+    if ri.Class != asn1.ClassContextSpecific || ri.Tag != recipientTagKeyAgree {
+        return nil, nil
+    }
+
+    return decryptKeyAgree(ri, this.pkey, func(rid asn1.RawValue) bool {
+        keyID, ok := parseRecipientKeyID(rid)
+        return ok && bytes.Equal(keyID, this.keyID)
+    })
+}
+
+// ==========
+
+// 生成 RecipientInfos 及 EnvelopedData 版本
+func makeRecipientInfos(rand io.Reader, key []byte, recipients []Recipient) ([]asn1.RawValue, int, error) {
+    if len(recipients) == 0 {
+        return nil, 0, errors.New("pkcs7: recipients is empty")
+    }
+
+    version := 0
+
+    infos := make([]asn1.RawValue, 0, len(recipients))
+    for _, recipient := range recipients {
+        info, err := recipient.RecipientInfo(rand, key)
+        if err != nil {
+            return nil, 0, err
+        }
+
+        if info.Class == asn1.ClassContextSpecific {
+            switch info.Tag {
+                case recipientTagPassword:
+                    version = 3
+                default:
+                    if version < 2 {
+                        version = 2
+                    }
+            }
+        }
+
+        infos = append(infos, info)
+    }
+
+    return infos, version, nil
+}
+
+// 解出内容加密密钥
+func decryptRecipientInfos(infos []asn1.RawValue, key recipientKey) ([]byte, error) {
+    var lastErr error
+
+    for _, info := range infos {
+        contentKey, err := key.decryptKey(info)
+        if err != nil {
+            lastErr = err
+            continue
+        }
+
+        if contentKey != nil {
+            return contentKey, nil
+        }
+    }
+
+    if lastErr != nil {
+        return nil, lastErr
+    }
+
+    return nil, errNoRecipient
+}
+
+// 编码 RecipientInfo, tag 为 0 时不设置标签
+func marshalRecipientInfo(val any, tag int) (asn1.RawValue, error) {
+    var data []byte
+    var err error
+
+    if tag > 0 {
+        data, err = asn1.MarshalWithParams(val, fmt.Sprintf("tag:%d", tag))
+    } else {
+        data, err = asn1.Marshal(val)
+    }
+
+    if err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    var info asn1.RawValue
+    if _, err = asn1.Unmarshal(data, &info); err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    return info, nil
+}
@@ -0,0 +1,291 @@
+package encrypt
+
+import (
+    "io"
+    "time"
+    "bytes"
+    "errors"
+    "crypto"
+    "crypto/x509"
+    "crypto/x509/pkix"
+    "encoding/asn1"
+    "encoding/binary"
+)
+
+type keyAgreeRecipientInfo struct {
+    Version                int
+    Originator             asn1.RawValue
+    UKM                    []byte `asn1:"explicit,optional,tag:1"`
+    KeyEncryptionAlgorithm pkix.AlgorithmIdentifier
+    RecipientEncryptedKeys []recipientEncryptedKey
+}
+
+type recipientEncryptedKey struct {
+    RID          asn1.RawValue
+    EncryptedKey []byte
+}
+
+type recipientKeyIdentifier struct {
+    SubjectKeyIdentifier []byte
+    Date                 time.Time     `asn1:"optional,generalized"`
+    Other                asn1.RawValue `asn1:"optional"`
+}
+
+// RFC 5753 ECC-CMS-SharedInfo
+type eccCMSSharedInfo struct {
+    KeyInfo     pkix.AlgorithmIdentifier
+    EntityUInfo []byte `asn1:"explicit,optional,tag:0"`
+    SuppPubInfo []byte `asn1:"explicit,tag:2"`
+}
+
+// 密钥协商接收者
+type keyAgreeRecipient struct {
+    cert     *x509.Certificate
+    keyID    []byte
+    pub      crypto.PublicKey
+    keyAgree KeyAgree
+    keyWrap  KeyWrap
+}
+
+// 密钥协商接收者 (KeyAgreeRecipientInfo), 使用证书的公钥
+// 公钥可用 ECDSA (NIST, Brainpool), SM2, X25519 及 X448
+// keyWrap 为空时使用 KeyWrapAES256
+func NewKeyAgreeRecipient(cert *x509.Certificate, keyAgree KeyAgree, keyWrap KeyWrap) Recipient {
+    var pub crypto.PublicKey
+    if cert != nil {
+        pub = cert.PublicKey
+    }
+
+    return keyAgreeRecipient{
+        cert:     cert,
+        pub:      pub,
+        keyAgree: keyAgree,
+        keyWrap:  keyWrap,
+    }
+}
+
+// 密钥协商接收者, 使用 SubjectKeyIdentifier 标识接收者
+// 用于标准库不能解析证书的公钥, 如 Brainpool 及 X448
+func NewKeyAgreeRecipientWithKeyID(keyID []byte, pub crypto.PublicKey, keyAgree KeyAgree, keyWrap KeyWrap) Recipient {
+    return keyAgreeRecipient{
+        keyID:    keyID,
+        pub:      pub,
+        keyAgree: keyAgree,
+        keyWrap:  keyWrap,
+    }
+}
+
+func (this keyAgreeRecipient) RecipientInfo(rand io.Reader, key []byte) (asn1.RawValue, error) {
+    if this.pub == nil || this.keyAgree == nil {
+        return asn1.RawValue{}, errors.New("pkcs7: publicKey or keyAgree is empty")
+    }
+
+    keyWrap := this.keyWrap
+    if keyWrap == nil {
+        keyWrap = KeyWrapAES256
+    }
+
+    originator, secret, err := ecdhEphemeral(rand, this.pub)
+    if err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    wrapAlgorithm := pkix.AlgorithmIdentifier{
+        Algorithm: keyWrap.OID(),
+    }
+
+    kek, err := deriveKeyAgreeKEK(this.keyAgree, keyWrap, wrapAlgorithm, secret, nil)
+    if err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    encryptedKey, err := keyWrap.Wrap(kek, key)
+    if err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    originatorBytes, err := asn1.MarshalWithParams(originator, "tag:1")
+    if err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    wrapAlgorithmBytes, err := asn1.Marshal(wrapAlgorithm)
+    if err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    var rid []byte
+    if this.cert != nil {
+        ias, err := cert2issuerAndSerial(this.cert)
+        if err != nil {
+            return asn1.RawValue{}, err
+        }
+
+        rid, err = asn1.Marshal(ias)
+    } else {
+        rid, err = asn1.MarshalWithParams(recipientKeyIdentifier{
+            SubjectKeyIdentifier: this.keyID,
+        }, "tag:0")
+    }
+
+    if err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    info := keyAgreeRecipientInfo{
+        Version: 3,
+        Originator: asn1.RawValue{
+            Class:      asn1.ClassContextSpecific,
+            Tag:        0,
+            IsCompound: true,
+            Bytes:      originatorBytes,
+        },
+        KeyEncryptionAlgorithm: pkix.AlgorithmIdentifier{
+            Algorithm:  this.keyAgree.OID(),
+            Parameters: asn1.RawValue{
+                FullBytes: wrapAlgorithmBytes,
+            },
+        },
+        RecipientEncryptedKeys: []recipientEncryptedKey{
+            {
+                RID:          asn1.RawValue{FullBytes: rid},
+                EncryptedKey: encryptedKey,
+            },
+        },
+    }
+
+    return marshalRecipientInfo(info, recipientTagKeyAgree)
+}
+
+// 派生密钥加密密钥
+func deriveKeyAgreeKEK(
+    keyAgree KeyAgree,
+    keyWrap KeyWrap,
+    wrapAlgorithm pkix.AlgorithmIdentifier,
+    secret []byte,
+    ukm []byte,
+) ([]byte, error) {
+    size := keyWrap.KeySize()
+
+    // 密钥长度, 单位为 bit
+    suppPubInfo := make([]byte, 4)
+    binary.BigEndian.PutUint32(suppPubInfo, uint32(size) * 8)
+
+    sharedInfo, err := asn1.Marshal(eccCMSSharedInfo{
+        KeyInfo:     wrapAlgorithm,
+        EntityUInfo: ukm,
+        SuppPubInfo: suppPubInfo,
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return keyAgree.DeriveKey(secret, sharedInfo, size)
+}
+
+// 解出密钥协商接收者的内容加密密钥
+func decryptKeyAgree(ri asn1.RawValue, pkey crypto.PrivateKey, match func(rid asn1.RawValue) bool) ([]byte, error) {
+    var info keyAgreeRecipientInfo
+    if _, err := asn1.UnmarshalWithParams(ri.FullBytes, &info, "tag:1"); err != nil {
+        return nil, nil
+    }
+
+    var encryptedKey []byte
+    for _, rek := range info.RecipientEncryptedKeys {
+        if match(rek.RID) {
+            encryptedKey = rek.EncryptedKey
+            break
+        }
+    }
+
+    if encryptedKey == nil {
+        return nil, nil
+    }
+
+    var originatorKey asn1.RawValue
+    if _, err := asn1.Unmarshal(info.Originator.Bytes, &originatorKey); err != nil {
+        return nil, err
+    }
+
+    if originatorKey.Class != asn1.ClassContextSpecific || originatorKey.Tag != 1 {
+        return nil, errors.New("pkcs7: unsupported originator identifier")
+    }
+
+    var originator originatorPublicKey
+    if _, err := asn1.UnmarshalWithParams(originatorKey.FullBytes, &originator, "tag:1"); err != nil {
+        return nil, err
+    }
+
+    keyAgree, err := parseKeyAgree(info.KeyEncryptionAlgorithm.Algorithm)
+    if err != nil {
+        return nil, err
+    }
+
+    var wrapAlgorithm pkix.AlgorithmIdentifier
+    if _, err := asn1.Unmarshal(info.KeyEncryptionAlgorithm.Parameters.FullBytes, &wrapAlgorithm); err != nil {
+        return nil, err
+    }
+
+    keyWrap, err := parseKeyWrap(wrapAlgorithm.Algorithm)
+    if err != nil {
+        return nil, err
+    }
+
+    secret, err := ecdhSharedSecret(pkey, originator)
+    if err != nil {
+        return nil, err
+    }
+
+    kek, err := deriveKeyAgreeKEK(keyAgree, keyWrap, wrapAlgorithm, secret, info.UKM)
+    if err != nil {
+        return nil, err
+    }
+
+    return keyWrap.Unwrap(kek, encryptedKey)
+}
+
+// 证书是否匹配 KeyAgreeRecipientIdentifier
+func isCertMatchForRecipientID(cert *x509.Certificate, rid asn1.RawValue) bool {
+    if keyID, ok := parseRecipientKeyID(rid); ok {
+        return len(cert.SubjectKeyId) > 0 && bytes.Equal(keyID, cert.SubjectKeyId)
+    }
+
+    var ias issuerAndSerial
+    if _, err := asn1.Unmarshal(rid.FullBytes, &ias); err != nil {
+        return false
+    }
+
+    return isCertMatchForIssuerAndSerial(cert, ias)
+}
+
+// 解析 rKeyId
+func parseRecipientKeyID(rid asn1.RawValue) ([]byte, bool) {
+    if rid.Class != asn1.ClassContextSpecific || rid.Tag != 0 {
+        return nil, false
+    }
+
+    var keyID recipientKeyIdentifier
+    if _, err := asn1.UnmarshalWithParams(rid.FullBytes, &keyID, "tag:0"); err != nil {
+        return nil, false
+    }
+
+    return keyID.SubjectKeyIdentifier, true
+}
+
+func parseKeyAgree(oid asn1.ObjectIdentifier) (KeyAgree, error) {
+    fn, ok := keyAgrees[oid.String()]
+    if !ok {
+        return nil, errors.New("pkcs7: unsupported key agreement (OID: " + oid.String() + ")")
+    }
+
+    return fn(), nil
+}
+
+func parseKeyWrap(oid asn1.ObjectIdentifier) (KeyWrap, error) {
+    fn, ok := keyWraps[oid.String()]
+    if !ok {
+        return nil, errors.New("pkcs7: unsupported key wrap (OID: " + oid.String() + ")")
+    }
+
+    return fn(), nil
+}
@@ -0,0 +1,105 @@
+package encrypt
+
+import (
+    "io"
+    "time"
+    "bytes"
+    "errors"
+    "crypto/x509/pkix"
+    "encoding/asn1"
+)
+
+type kekRecipientInfo struct {
+    Version                int
+    KEKID                  kekIdentifier
+    KeyEncryptionAlgorithm pkix.AlgorithmIdentifier
+    EncryptedKey           []byte
+}
+
+type kekIdentifier struct {
+    KeyIdentifier []byte
+    Date          time.Time     `asn1:"optional,generalized"`
+    Other         asn1.RawValue `asn1:"optional"`
+}
+
+// 预共享密钥接收者
+type kekRecipient struct {
+    keyID []byte
+    kek   []byte
+}
+
+// 预共享密钥接收者 (KEKRecipientInfo)
+// 使用 AES key wrap, kek 长度可为 16, 24 或 32
+func NewKEKRecipient(keyID, kek []byte) Recipient {
+    return kekRecipient{
+        keyID: keyID,
+        kek:   kek,
+    }
+}
+
+func (this kekRecipient) RecipientInfo(rand io.Reader, key []byte) (asn1.RawValue, error) {
+    keyWrap, err := getKEKKeyWrap(this.kek)
+    if err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    encryptedKey, err := keyWrap.Wrap(this.kek, key)
+    if err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    info := kekRecipientInfo{
+        Version: 4,
+        KEKID: kekIdentifier{
+            KeyIdentifier: this.keyID,
+        },
+        KeyEncryptionAlgorithm: pkix.AlgorithmIdentifier{
+            Algorithm: keyWrap.OID(),
+        },
+        EncryptedKey: encryptedKey,
+    }
+
+    return marshalRecipientInfo(info, recipientTagKEK)
+}
+
+// 预共享密钥
+type kekRecipientKey struct {
+    keyID []byte
+    kek   []byte
+}
+
+func (this kekRecipientKey) decryptKey(ri asn1.RawValue) ([]byte, error) {
+    if ri.Class != asn1.ClassContextSpecific || ri.Tag != recipientTagKEK {
+        return nil, nil
+    }
+
+    var info kekRecipientInfo
+    if _, err := asn1.UnmarshalWithParams(ri.FullBytes, &info, "tag:2"); err != nil {
+        return nil, nil
+    }
+
+    if !bytes.Equal(info.KEKID.KeyIdentifier, this.keyID) {
+        return nil, nil
+    }
+
+    keyWrap, err := parseKeyWrap(info.KeyEncryptionAlgorithm.Algorithm)
+    if err != nil {
+        return nil, err
+    }
+
+    return keyWrap.Unwrap(this.kek, info.EncryptedKey)
+}
+
+// 根据 kek 长度选择 key wrap
+func getKEKKeyWrap(kek []byte) (KeyWrap, error) {
+    switch len(kek) {
+        case 16:
+            return KeyWrapAES128, nil
+        case 24:
+            return KeyWrapAES192, nil
+        case 32:
+            return KeyWrapAES256, nil
+    }
+
+    return nil, errors.New("pkcs7: invalid key encryption key length")
+}
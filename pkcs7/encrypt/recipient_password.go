@@ -0,0 +1,380 @@
+package encrypt
+
+import (
+    "io"
+    "hash"
+    "errors"
+    "crypto/aes"
+    "crypto/sha1"
+    "crypto/sha256"
+    "crypto/sha512"
+    "crypto/cipher"
+    "crypto/subtle"
+    "crypto/x509/pkix"
+    "encoding/asn1"
+
+    "golang.org/x/crypto/pbkdf2"
+
+    "github.com/deatil/go-cryptobin/hash/sm3"
+    "github.com/deatil/go-cryptobin/cipher/sm4"
+)
+
+var (
+    oidPWRIKEK = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 3, 9}
+    oidPBKDF2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+
+    oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+    oidHMACWithSHA224 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 8}
+    oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+    oidHMACWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+    oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+    oidHMACWithSM3    = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 401, 2}
+)
+
+// PBKDF2 及 HMAC 使用的 hash 方式
+type Hash uint
+
+const (
+    SHA1 Hash = 1 + iota
+    SHA224
+    SHA256
+    SHA384
+    SHA512
+    SM3
+)
+
+// hash 方式
+var hmacHashs = map[Hash]struct{
+    oid  asn1.ObjectIdentifier
+    hash func() hash.Hash
+}{
+    SHA1:   {oidHMACWithSHA1, sha1.New},
+    SHA224: {oidHMACWithSHA224, sha256.New224},
+    SHA256: {oidHMACWithSHA256, sha256.New},
+    SHA384: {oidHMACWithSHA384, sha512.New384},
+    SHA512: {oidHMACWithSHA512, sha512.New},
+    SM3:    {oidHMACWithSM3, sm3.New},
+}
+
+// 返回使用的 Hash 方式
+func prfByOID(oid asn1.ObjectIdentifier) (func() hash.Hash, error) {
+    // 默认为 SHA1
+    if len(oid) == 0 {
+        return sha1.New, nil
+    }
+
+    for _, h := range hmacHashs {
+        if h.oid.Equal(oid) {
+            return h.hash, nil
+        }
+    }
+
+    return nil, errors.New("pkcs7: unsupported hash function")
+}
+
+type pbkdf2Params struct {
+    Salt           []byte
+    IterationCount int
+    KeyLength      int                      `asn1:"optional"`
+    PrfParam       pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+type passwordRecipientInfo struct {
+    Version                int
+    KeyDerivationAlgorithm pkix.AlgorithmIdentifier `asn1:"optional,tag:0"`
+    KeyEncryptionAlgorithm pkix.AlgorithmIdentifier
+    EncryptedKey           []byte
+}
+
+// 密码接收者配置
+type PasswordOpts struct {
+    // 密钥加密方式, 可用 [AES128CBC | AES192CBC | AES256CBC | SM4CBC], 默认为 AES256CBC
+    Cipher Cipher
+
+    // 盐长度, 默认为 16
+    SaltSize int
+
+    // 迭代次数, 默认为 10000
+    IterationCount int
+
+    // PBKDF2 hash 方式, 默认为 SHA256
+    HMACHash Hash
+}
+
+// 默认密码接收者配置
+var DefaultPasswordOpts = PasswordOpts{
+    Cipher:         AES256CBC,
+    SaltSize:       16,
+    IterationCount: 10000,
+    HMACHash:       SHA256,
+}
+
+// 密码接收者
+type passwordRecipient struct {
+    password []byte
+    opts     PasswordOpts
+}
+
+// 密码接收者 (PasswordRecipientInfo, RFC 3211), 使用 PBKDF2 派生密钥
+func NewPasswordRecipient(password []byte, opts ...PasswordOpts) Recipient {
+    opt := DefaultPasswordOpts
+    if len(opts) > 0 {
+        opt = opts[0]
+    }
+
+    return passwordRecipient{
+        password: password,
+        opts:     opt,
+    }
+}
+
+func (this passwordRecipient) RecipientInfo(rand io.Reader, key []byte) (asn1.RawValue, error) {
+    opts := this.opts
+
+    if opts.Cipher == nil {
+        opts.Cipher = DefaultPasswordOpts.Cipher
+    }
+    if opts.SaltSize <= 0 {
+        opts.SaltSize = DefaultPasswordOpts.SaltSize
+    }
+    if opts.IterationCount <= 0 {
+        opts.IterationCount = DefaultPasswordOpts.IterationCount
+    }
+    if opts.HMACHash == 0 {
+        opts.HMACHash = DefaultPasswordOpts.HMACHash
+    }
+
+    newBlock, err := getPWRIBlock(opts.Cipher.OID())
+    if err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    prf, ok := hmacHashs[opts.HMACHash]
+    if !ok {
+        return asn1.RawValue{}, errors.New("pkcs7: unsupported hash function")
+    }
+
+    salt := make([]byte, opts.SaltSize)
+    if _, err := io.ReadFull(rand, salt); err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    kek := pbkdf2.Key(this.password, salt, opts.IterationCount, opts.Cipher.KeySize(), prf.hash)
+
+    block, err := newBlock(kek)
+    if err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    iv := make([]byte, block.BlockSize())
+    if _, err := io.ReadFull(rand, iv); err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    encryptedKey, err := pwriWrap(rand, block, iv, key)
+    if err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    kdfParams, err := asn1.Marshal(pbkdf2Params{
+        Salt:           salt,
+        IterationCount: opts.IterationCount,
+        PrfParam: pkix.AlgorithmIdentifier{
+            Algorithm:  prf.oid,
+            Parameters: asn1.NullRawValue,
+        },
+    })
+    if err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    ivBytes, err := asn1.Marshal(iv)
+    if err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    cipherParams, err := asn1.Marshal(pkix.AlgorithmIdentifier{
+        Algorithm:  opts.Cipher.OID(),
+        Parameters: asn1.RawValue{
+            FullBytes: ivBytes,
+        },
+    })
+    if err != nil {
+        return asn1.RawValue{}, err
+    }
+
+    info := passwordRecipientInfo{
+        Version: 0,
+        KeyDerivationAlgorithm: pkix.AlgorithmIdentifier{
+            Algorithm:  oidPBKDF2,
+            Parameters: asn1.RawValue{
+                FullBytes: kdfParams,
+            },
+        },
+        KeyEncryptionAlgorithm: pkix.AlgorithmIdentifier{
+            Algorithm:  oidPWRIKEK,
+            Parameters: asn1.RawValue{
+                FullBytes: cipherParams,
+            },
+        },
+        EncryptedKey: encryptedKey,
+    }
+
+    return marshalRecipientInfo(info, recipientTagPassword)
+}
+
+// 密码
+type passwordRecipientKey struct {
+    password []byte
+}
+
+func (this passwordRecipientKey) decryptKey(ri asn1.RawValue) ([]byte, error) {
+    if ri.Class != asn1.ClassContextSpecific || ri.Tag != recipientTagPassword {
+        return nil, nil
+    }
+
+    var info passwordRecipientInfo
+    if _, err := asn1.UnmarshalWithParams(ri.FullBytes, &info, "tag:3"); err != nil {
+        return nil, nil
+    }
+
+    if !info.KeyDerivationAlgorithm.Algorithm.Equal(oidPBKDF2) {
+        return nil, errors.New("pkcs7: unsupported key derivation algorithm")
+    }
+
+    if !info.KeyEncryptionAlgorithm.Algorithm.Equal(oidPWRIKEK) {
+        return nil, errors.New("pkcs7: unsupported password key encryption algorithm")
+    }
+
+    var kdfParams pbkdf2Params
+    if _, err := asn1.Unmarshal(info.KeyDerivationAlgorithm.Parameters.FullBytes, &kdfParams); err != nil {
+        return nil, err
+    }
+
+    var cipherAlgorithm pkix.AlgorithmIdentifier
+    if _, err := asn1.Unmarshal(info.KeyEncryptionAlgorithm.Parameters.FullBytes, &cipherAlgorithm); err != nil {
+        return nil, err
+    }
+
+    var iv []byte
+    if _, err := asn1.Unmarshal(cipherAlgorithm.Parameters.FullBytes, &iv); err != nil {
+        return nil, err
+    }
+
+    newBlock, err := getPWRIBlock(cipherAlgorithm.Algorithm)
+    if err != nil {
+        return nil, err
+    }
+
+    c, err := GetCipher(cipherAlgorithm.Algorithm.String())
+    if err != nil {
+        return nil, err
+    }
+
+    prf, err := prfByOID(kdfParams.PrfParam.Algorithm)
+    if err != nil {
+        return nil, err
+    }
+
+    size := c.KeySize()
+    if kdfParams.KeyLength > 0 {
+        size = kdfParams.KeyLength
+    }
+
+    kek := pbkdf2.Key(this.password, kdfParams.Salt, kdfParams.IterationCount, size, prf)
+
+    block, err := newBlock(kek)
+    if err != nil {
+        return nil, err
+    }
+
+    if len(iv) != block.BlockSize() {
+        return nil, errors.New("pkcs7: invalid iv length")
+    }
+
+    return pwriUnwrap(block, iv, info.EncryptedKey)
+}
+
+// 获取 PWRI 使用的分组加密
+func getPWRIBlock(oid asn1.ObjectIdentifier) (func([]byte) (cipher.Block, error), error) {
+    switch {
+        case oid.Equal(AES128CBC.OID()),
+            oid.Equal(AES192CBC.OID()),
+            oid.Equal(AES256CBC.OID()):
+            return aes.NewCipher, nil
+        case oid.Equal(SM4CBC.OID()):
+            return sm4.NewCipher, nil
+    }
+
+    return nil, errors.New("pkcs7: unsupported password key encryption cipher (OID: " + oid.String() + ")")
+}
+
+// RFC 3211 2.3.1 密钥包装
+func pwriWrap(rand io.Reader, block cipher.Block, iv, key []byte) ([]byte, error) {
+    bs := block.BlockSize()
+
+    if len(key) < 3 || len(key) > 255 {
+        return nil, errors.New("pkcs7: invalid content encryption key length")
+    }
+
+    n := 4 + len(key)
+    if n < 2 * bs {
+        n = 2 * bs
+    }
+    if n % bs != 0 {
+        n += bs - n % bs
+    }
+
+    buf := make([]byte, n)
+    buf[0] = byte(len(key))
+    buf[1] = ^key[0]
+    buf[2] = ^key[1]
+    buf[3] = ^key[2]
+    copy(buf[4:], key)
+
+    if _, err := io.ReadFull(rand, buf[4+len(key):]); err != nil {
+        return nil, err
+    }
+
+    cipher.NewCBCEncrypter(block, iv).CryptBlocks(buf, buf)
+
+    lastBlock := make([]byte, bs)
+    copy(lastBlock, buf[n-bs:])
+
+    cipher.NewCBCEncrypter(block, lastBlock).CryptBlocks(buf, buf)
+
+    return buf, nil
+}
+
+// RFC 3211 2.3.2 密钥解包装
+func pwriUnwrap(block cipher.Block, iv, wrapped []byte) ([]byte, error) {
+    bs := block.BlockSize()
+    n := len(wrapped)
+
+    if n < 2 * bs || n % bs != 0 {
+        return nil, errors.New("pkcs7: invalid encrypted key length")
+    }
+
+    tmp := make([]byte, n)
+
+    // 使用倒数第二块作为 IV 解密最后一块
+    cipher.NewCBCDecrypter(block, wrapped[n-2*bs:n-bs]).CryptBlocks(tmp[n-bs:], wrapped[n-bs:])
+
+    // 使用解密后的最后一块作为 IV 解密其他块
+    cipher.NewCBCDecrypter(block, tmp[n-bs:]).CryptBlocks(tmp[:n-bs], wrapped[:n-bs])
+
+    // 解密内层
+    cipher.NewCBCDecrypter(block, iv).CryptBlocks(tmp, tmp)
+
+    keyLen := int(tmp[0])
+    if keyLen < 3 || keyLen > n - 4 {
+        return nil, errors.New("pkcs7: password key decrypt failed")
+    }
+
+    check := []byte{^tmp[4], ^tmp[5], ^tmp[6]}
+    if subtle.ConstantTimeCompare(check, tmp[1:4]) != 1 {
+        return nil, errors.New("pkcs7: password key decrypt failed")
+    }
+
+    return tmp[4:4+keyLen], nil
+}
@@ -12,6 +12,9 @@ var (
     // 添加签名数据
     NewSignedData = sign.NewSignedData
 
+    // 流式读取数据并添加签名数据
+    NewSignedDataFromReader = sign.NewSignedDataFromReader
+
     // DegenerateCertificate
     DegenerateCertificate = sign.DegenerateCertificate
 
@@ -26,6 +29,30 @@ var (
 
     // 解密
     DecryptUsingPSK = encrypt.DecryptUsingPSK
+
+    // 多接收者加密
+    EncryptWithRecipients = encrypt.EncryptWithRecipients
+
+    // 认证加密
+    EncryptAuth = encrypt.EncryptAuth
+
+    // 多接收者认证加密
+    EncryptAuthWithRecipients = encrypt.EncryptAuthWithRecipients
+
+    // 生成认证数据
+    Authenticate = encrypt.Authenticate
+
+    // 多接收者生成认证数据
+    AuthenticateWithRecipients = encrypt.AuthenticateWithRecipients
+
+    // 使用 keyID 解密
+    DecryptWithKeyID = encrypt.DecryptWithKeyID
+
+    // 使用 KEK 解密
+    DecryptUsingKEK = encrypt.DecryptUsingKEK
+
+    // 使用密码解密
+    DecryptUsingPassword = encrypt.DecryptUsingPassword
 )
 
 type (
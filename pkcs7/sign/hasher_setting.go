@@ -6,6 +6,8 @@ import (
     "crypto/sha512"
     "encoding/asn1"
 
+    "golang.org/x/crypto/sha3"
+
     "github.com/deatil/go-cryptobin/hash/sm3"
 )
 
@@ -18,6 +20,11 @@ var (
     oidDigestAlgorithmSHA512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
     oidDigestAlgorithmSHA224 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 4}
 
+    oidDigestAlgorithmSHA3_224 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 7}
+    oidDigestAlgorithmSHA3_256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 8}
+    oidDigestAlgorithmSHA3_384 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 9}
+    oidDigestAlgorithmSHA3_512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 10}
+
     oidDigestAlgorithmSM3    = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 401}
 )
 
@@ -47,6 +54,26 @@ var SignHashWithSHA224 = SignHashWithFunc{
     identifier: oidDigestAlgorithmSHA224,
 }
 
+var SignHashWithSHA3_224 = SignHashWithFunc{
+    hashFunc:   sha3.New224,
+    identifier: oidDigestAlgorithmSHA3_224,
+}
+
+var SignHashWithSHA3_256 = SignHashWithFunc{
+    hashFunc:   sha3.New256,
+    identifier: oidDigestAlgorithmSHA3_256,
+}
+
+var SignHashWithSHA3_384 = SignHashWithFunc{
+    hashFunc:   sha3.New384,
+    identifier: oidDigestAlgorithmSHA3_384,
+}
+
+var SignHashWithSHA3_512 = SignHashWithFunc{
+    hashFunc:   sha3.New512,
+    identifier: oidDigestAlgorithmSHA3_512,
+}
+
 var SignHashWithSM3 = SignHashWithFunc{
     hashFunc:   sm3.New,
     identifier: oidDigestAlgorithmSM3,
@@ -68,6 +95,18 @@ func init() {
     AddSignHash(oidDigestAlgorithmSHA224, func() SignHash {
         return SignHashWithSHA224
     })
+    AddSignHash(oidDigestAlgorithmSHA3_224, func() SignHash {
+        return SignHashWithSHA3_224
+    })
+    AddSignHash(oidDigestAlgorithmSHA3_256, func() SignHash {
+        return SignHashWithSHA3_256
+    })
+    AddSignHash(oidDigestAlgorithmSHA3_384, func() SignHash {
+        return SignHashWithSHA3_384
+    })
+    AddSignHash(oidDigestAlgorithmSHA3_512, func() SignHash {
+        return SignHashWithSHA3_512
+    })
     AddSignHash(oidDigestAlgorithmSM3, func() SignHash {
         return SignHashWithSM3
     })
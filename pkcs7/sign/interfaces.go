@@ -1,6 +1,7 @@
 package sign
 
 import (
+    "hash"
     "crypto"
     "encoding/asn1"
 )
@@ -14,6 +15,14 @@ type SignHash interface {
     Sum(data []byte) []byte
 }
 
+// 流式 hash 接口
+type StreamSignHash interface {
+    SignHash
+
+    // 流式摘要
+    New() hash.Hash
+}
+
 // 签名接口
 type KeySign interface {
     // oid
@@ -10,6 +10,7 @@ import (
     "encoding/asn1"
 
     "github.com/deatil/go-cryptobin/ber"
+    gmsm_x509 "github.com/deatil/go-cryptobin/gm/x509"
 )
 
 var (
@@ -45,6 +46,10 @@ type contentInfo struct {
 // and Enveloped Data are supported (1.2.840.113549.1.7.3)
 var ErrUnsupportedContentType = errors.New("pkcs7: cannot parse data: unimplemented content type")
 
+// ErrNotStreamSignHash is returned when the digest algorithm used for streaming
+// does not implement StreamSignHash.
+var ErrNotStreamSignHash = errors.New("pkcs7: digest algorithm does not support streaming")
+
 // Parse decodes a DER encoded PKCS7 package
 func Parse(data []byte) (p7 *PKCS7, err error) {
     if len(data) == 0 {
@@ -85,7 +90,21 @@ func (raw rawCertificates) Parse() ([]*x509.Certificate, error) {
         return nil, err
     }
 
-    return x509.ParseCertificates(val.Bytes)
+    certs, err := x509.ParseCertificates(val.Bytes)
+    if err != nil {
+        // SM2 证书
+        gmsmCerts, gmsmErr := gmsm_x509.ParseCertificates(val.Bytes)
+        if gmsmErr != nil {
+            return nil, err
+        }
+
+        certs = make([]*x509.Certificate, 0, len(gmsmCerts))
+        for _, cert := range gmsmCerts {
+            certs = append(certs, cert.ToX509Certificate())
+        }
+    }
+
+    return certs, nil
 }
 
 // Attribute represents a key value pair attribute. Value must be marshalable byte
@@ -1,7 +1,9 @@
 package sign
 
 import (
+    "io"
     "fmt"
+    "errors"
     "time"
     "bytes"
     "math/big"
@@ -18,6 +20,9 @@ type SignedData struct {
     data, messageDigest []byte
     digestOid           asn1.ObjectIdentifier
     encryptionOid       asn1.ObjectIdentifier
+
+    // 流式计算摘要时使用的摘要方式
+    streamDigestOid     asn1.ObjectIdentifier
 }
 
 // NewSignedData takes data and initializes a PKCS7 SignedData struct that is
@@ -44,6 +49,34 @@ func NewSignedData(data []byte) (*SignedData, error) {
     }, nil
 }
 
+// NewSignedDataFromReader reads the content from r and hashes it on the fly
+// with signHash, the content itself is never held in memory. The returned
+// SignedData is detached and can only be signed with AddSigner/AddSignerChain
+// using the same digest algorithm. signHash must implement StreamSignHash.
+func NewSignedDataFromReader(r io.Reader, signHash SignHash) (*SignedData, error) {
+    streamHash, ok := signHash.(StreamSignHash)
+    if !ok {
+        return nil, ErrNotStreamSignHash
+    }
+
+    h := streamHash.New()
+    if _, err := io.Copy(h, r); err != nil {
+        return nil, err
+    }
+
+    sd := signedData{
+        ContentInfo: contentInfo{ContentType: oidData},
+        Version:     1,
+    }
+    return &SignedData{
+        sd: sd,
+        messageDigest: h.Sum(nil),
+        digestOid: signHash.OID(),
+        encryptionOid: oidDigestAlgorithmRSASHA1,
+        streamDigestOid: signHash.OID(),
+    }, nil
+}
+
 // SignerInfoConfig are optional values to include when adding a signer
 type SignerInfoConfig struct {
     ExtraSignedAttributes   []Attribute
@@ -149,12 +182,18 @@ func (this *SignedData) AddSignerChain(ee *x509.Certificate, pkey crypto.Private
         pkix.AlgorithmIdentifier{Algorithm: this.digestOid},
     )
 
-    hashFunc, err := parseHashFromOid(this.digestOid)
-    if err != nil {
-        return err
-    }
+    if this.streamDigestOid != nil {
+        if !this.digestOid.Equal(this.streamDigestOid) {
+            return fmt.Errorf("pkcs7: digest algorithm %s is not the streamed digest algorithm %s", this.digestOid, this.streamDigestOid)
+        }
+    } else {
+        hashFunc, err := parseHashFromOid(this.digestOid)
+        if err != nil {
+            return err
+        }
 
-    this.messageDigest = hashFunc.Sum(this.data)
+        this.messageDigest = hashFunc.Sum(this.data)
+    }
 
     attrs := &attributes{}
     attrs.Add(oidAttributeContentType, this.sd.ContentInfo.ContentType)
@@ -219,6 +258,10 @@ func (this *SignedData) AddSignerChain(ee *x509.Certificate, pkey crypto.Private
 // shouldn't do unless you're maintaining backward compatibility for old
 // applications.
 func (this *SignedData) SignWithoutAttr(ee *x509.Certificate, pkey crypto.PrivateKey, config SignerInfoConfig) error {
+    if this.streamDigestOid != nil {
+        return errors.New("pkcs7: streamed content can not be signed without attributes")
+    }
+
     var signature []byte
     this.sd.DigestAlgorithmIdentifiers = append(this.sd.DigestAlgorithmIdentifiers, pkix.AlgorithmIdentifier{Algorithm: this.digestOid})
 
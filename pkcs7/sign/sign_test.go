@@ -0,0 +1,234 @@
+package sign
+
+import (
+    "io"
+    "time"
+    "bytes"
+    "testing"
+    "math/big"
+    "crypto"
+    "crypto/rsa"
+    "crypto/rand"
+    "crypto/x509"
+    "crypto/ecdsa"
+    "crypto/elliptic"
+    "crypto/x509/pkix"
+    "encoding/asn1"
+
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+func newTestCert(t *testing.T, pub crypto.PublicKey, signer crypto.Signer) *x509.Certificate {
+    serial, err := rand.Int(rand.Reader, big.NewInt(1 << 62))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    template := &x509.Certificate{
+        SerialNumber: serial,
+        Subject:      pkix.Name{CommonName: "test"},
+        NotBefore:    time.Now().Add(-time.Hour),
+        NotAfter:     time.Now().Add(time.Hour),
+    }
+
+    der, err := x509.CreateCertificate(rand.Reader, template, template, pub, signer)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    cert, err := x509.ParseCertificate(der)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return cert
+}
+
+// 生成固定内容的数据流
+type testReader struct {
+    n, off int64
+}
+
+func (this *testReader) Read(p []byte) (int, error) {
+    if this.n <= 0 {
+        return 0, io.EOF
+    }
+
+    if int64(len(p)) > this.n {
+        p = p[:this.n]
+    }
+
+    for i := range p {
+        p[i] = byte(this.off + int64(i))
+    }
+
+    this.n -= int64(len(p))
+    this.off += int64(len(p))
+
+    return len(p), nil
+}
+
+func Test_SignHashNew(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+
+    data := []byte("test data")
+
+    for _, signHash := range []StreamSignHash{
+        SignHashWithSHA1,
+        SignHashWithSHA256,
+        SignHashWithSHA512,
+        SignHashWithSHA3_256,
+        SignHashWithSHA3_512,
+        SignHashWithSM3,
+    } {
+        h := signHash.New()
+        h.Write(data)
+
+        assertEqual(h.Sum(nil), signHash.Sum(data), "Test_SignHashNew-" + signHash.OID().String())
+    }
+}
+
+func Test_StreamSign(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    ecCert := newTestCert(t, &ecKey.PublicKey, ecKey)
+
+    rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+    rsaCert := newTestCert(t, &rsaKey.PublicKey, rsaKey)
+
+    const size = 4 << 20
+
+    for name, c := range map[string]struct{
+        cert     *x509.Certificate
+        key      crypto.PrivateKey
+        signHash SignHash
+        keySign  KeySign
+    }{
+        "EcdsaSHA256":   {ecCert, ecKey, SignHashWithSHA256, KeySignWithEcdsaSHA256},
+        "EcdsaSHA3_256": {ecCert, ecKey, SignHashWithSHA3_256, KeySignWithEcdsaSHA3_256},
+        "RsaSHA384":     {rsaCert, rsaKey, SignHashWithSHA384, KeySignWithRsaSHA384},
+        "RsaSHA3_512":   {rsaCert, rsaKey, SignHashWithSHA3_512, KeySignWithRsaSHA3_512},
+    } {
+        signedData, err := NewSignedDataFromReader(&testReader{n: size}, c.signHash)
+        assertError(err, "Test_StreamSign-NewSignedDataFromReader-" + name)
+
+        signedData.SetEncryptionAlgorithm(c.keySign.OID())
+
+        err = signedData.AddSigner(c.cert, c.key, SignerInfoConfig{})
+        assertError(err, "Test_StreamSign-AddSigner-" + name)
+
+        signed, err := signedData.Finish()
+        assertError(err, "Test_StreamSign-Finish-" + name)
+
+        p7, err := Parse(signed)
+        assertError(err, "Test_StreamSign-Parse-" + name)
+
+        err = p7.VerifyDetached(&testReader{n: size})
+        assertError(err, "Test_StreamSign-VerifyDetached-" + name)
+
+        // 内容不一致
+        err = p7.VerifyDetached(&testReader{n: size - 1})
+        assertNotErrorNil(err, "Test_StreamSign-VerifyDetached-bad-" + name)
+
+        // 使用内存中的内容验证
+        content, _ := io.ReadAll(&testReader{n: size})
+        p7.Content = content
+
+        err = p7.Verify()
+        assertError(err, "Test_StreamSign-Verify-" + name)
+    }
+}
+
+func Test_StreamSignDigestMismatch(t *testing.T) {
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    ecCert := newTestCert(t, &ecKey.PublicKey, ecKey)
+
+    signedData, err := NewSignedDataFromReader(bytes.NewReader([]byte("data")), SignHashWithSHA256)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    signedData.SetDigestAlgorithm(SignHashWithSHA384.OID())
+    signedData.SetEncryptionAlgorithm(KeySignWithEcdsaSHA384.OID())
+
+    err = signedData.AddSigner(ecCert, ecKey, SignerInfoConfig{})
+    assertNotErrorNil(err, "Test_StreamSignDigestMismatch-AddSigner")
+
+    err = signedData.SignWithoutAttr(ecCert, ecKey, SignerInfoConfig{})
+    assertNotErrorNil(err, "Test_StreamSignDigestMismatch-SignWithoutAttr")
+}
+
+// 不支持流式摘要的 hash
+type testSumOnlyHash struct{}
+
+func (testSumOnlyHash) OID() asn1.ObjectIdentifier {
+    return SignHashWithSHA256.OID()
+}
+
+func (testSumOnlyHash) Sum(data []byte) []byte {
+    return SignHashWithSHA256.Sum(data)
+}
+
+func Test_StreamSignNotStreamSignHash(t *testing.T) {
+    assertTrue := cryptobin_test.AssertTrueT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+
+    signedData, err := NewSignedDataFromReader(bytes.NewReader([]byte("data")), testSumOnlyHash{})
+    assertTrue(signedData == nil, "Test_StreamSignNotStreamSignHash")
+    assertEqual(err, ErrNotStreamSignHash, "Test_StreamSignNotStreamSignHash")
+}
+
+func Test_VerifyDetachedMultiSigner(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    data := []byte("release artifact")
+
+    ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    ecCert := newTestCert(t, &ecKey.PublicKey, ecKey)
+
+    rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+    rsaCert := newTestCert(t, &rsaKey.PublicKey, rsaKey)
+
+    signedData, err := NewSignedData(data)
+    assertError(err, "Test_VerifyDetachedMultiSigner-NewSignedData")
+
+    signedData.SetDigestAlgorithm(SignHashWithSHA3_256.OID())
+    signedData.SetEncryptionAlgorithm(KeySignWithEcdsaSHA3_256.OID())
+
+    err = signedData.AddSigner(ecCert, ecKey, SignerInfoConfig{})
+    assertError(err, "Test_VerifyDetachedMultiSigner-AddSigner-ec")
+
+    signedData.SetDigestAlgorithm(SignHashWithSHA512.OID())
+    signedData.SetEncryptionAlgorithm(KeySignWithRsaSHA512.OID())
+
+    err = signedData.AddSigner(rsaCert, rsaKey, SignerInfoConfig{})
+    assertError(err, "Test_VerifyDetachedMultiSigner-AddSigner-rsa")
+
+    signedData.Detach()
+
+    signed, err := signedData.Finish()
+    assertError(err, "Test_VerifyDetachedMultiSigner-Finish")
+
+    p7, err := Parse(signed)
+    assertError(err, "Test_VerifyDetachedMultiSigner-Parse")
+
+    err = p7.VerifyDetached(bytes.NewReader(data))
+    assertError(err, "Test_VerifyDetachedMultiSigner-VerifyDetached")
+
+    roots := x509.NewCertPool()
+    roots.AddCert(ecCert)
+
+    // rsa 证书不在根证书中
+    err = p7.VerifyDetachedWithChain(bytes.NewReader(data), roots)
+    assertNotErrorNil(err, "Test_VerifyDetachedMultiSigner-VerifyDetachedWithChain")
+
+    roots.AddCert(rsaCert)
+
+    err = p7.VerifyDetachedWithChain(bytes.NewReader(data), roots)
+    assertError(err, "Test_VerifyDetachedMultiSigner-VerifyDetachedWithChain")
+}
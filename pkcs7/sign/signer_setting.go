@@ -8,6 +8,8 @@ import (
     _ "crypto/sha512"
     "encoding/asn1"
 
+    _ "golang.org/x/crypto/sha3"
+
     "github.com/deatil/go-cryptobin/hash/sm3"
 )
 
@@ -24,6 +26,11 @@ var (
     oidDigestAlgorithmECDSASHA384 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 3}
     oidDigestAlgorithmECDSASHA512 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 4}
 
+    oidDigestAlgorithmECDSASHA3_224 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 9}
+    oidDigestAlgorithmECDSASHA3_256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 10}
+    oidDigestAlgorithmECDSASHA3_384 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 11}
+    oidDigestAlgorithmECDSASHA3_512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 12}
+
     // rsa 签名
     oidDigestAlgorithmRSAMD5    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 4}
     oidDigestAlgorithmRSASHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 5}
@@ -33,6 +40,11 @@ var (
     oidDigestAlgorithmRSASHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 13}
     oidDigestAlgorithmRSASM3    = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 504}
 
+    oidDigestAlgorithmRSASHA3_224 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 13}
+    oidDigestAlgorithmRSASHA3_256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 14}
+    oidDigestAlgorithmRSASHA3_384 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 15}
+    oidDigestAlgorithmRSASHA3_512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 16}
+
     // eddsa 签名
     oidDigestAlgorithmEd25519   = asn1.ObjectIdentifier{1, 3, 101, 112}
 
@@ -81,6 +93,26 @@ var KeySignWithEcdsaSHA512 = KeySignWithEcdsa{
     hashId:     oidDigestAlgorithmSHA512,
     identifier: oidDigestAlgorithmECDSASHA512,
 }
+var KeySignWithEcdsaSHA3_224 = KeySignWithEcdsa{
+    hashFunc:   crypto.SHA3_224,
+    hashId:     oidDigestAlgorithmSHA3_224,
+    identifier: oidDigestAlgorithmECDSASHA3_224,
+}
+var KeySignWithEcdsaSHA3_256 = KeySignWithEcdsa{
+    hashFunc:   crypto.SHA3_256,
+    hashId:     oidDigestAlgorithmSHA3_256,
+    identifier: oidDigestAlgorithmECDSASHA3_256,
+}
+var KeySignWithEcdsaSHA3_384 = KeySignWithEcdsa{
+    hashFunc:   crypto.SHA3_384,
+    hashId:     oidDigestAlgorithmSHA3_384,
+    identifier: oidDigestAlgorithmECDSASHA3_384,
+}
+var KeySignWithEcdsaSHA3_512 = KeySignWithEcdsa{
+    hashFunc:   crypto.SHA3_512,
+    hashId:     oidDigestAlgorithmSHA3_512,
+    identifier: oidDigestAlgorithmECDSASHA3_512,
+}
 
 var KeySignWithRsaMD5 = KeySignWithRsa{
     hashFunc:   crypto.MD5,
@@ -112,6 +144,26 @@ var KeySignWithRsaSHA512 = KeySignWithRsa{
     hashId:     oidDigestAlgorithmSHA512,
     identifier: oidDigestAlgorithmRSASHA512,
 }
+var KeySignWithRsaSHA3_224 = KeySignWithRsa{
+    hashFunc:   crypto.SHA3_224,
+    hashId:     oidDigestAlgorithmSHA3_224,
+    identifier: oidDigestAlgorithmRSASHA3_224,
+}
+var KeySignWithRsaSHA3_256 = KeySignWithRsa{
+    hashFunc:   crypto.SHA3_256,
+    hashId:     oidDigestAlgorithmSHA3_256,
+    identifier: oidDigestAlgorithmRSASHA3_256,
+}
+var KeySignWithRsaSHA3_384 = KeySignWithRsa{
+    hashFunc:   crypto.SHA3_384,
+    hashId:     oidDigestAlgorithmSHA3_384,
+    identifier: oidDigestAlgorithmRSASHA3_384,
+}
+var KeySignWithRsaSHA3_512 = KeySignWithRsa{
+    hashFunc:   crypto.SHA3_512,
+    hashId:     oidDigestAlgorithmSHA3_512,
+    identifier: oidDigestAlgorithmRSASHA3_512,
+}
 
 var KeySignWithEdDsaSHA1 = KeySignWithRsa{
     hashFunc:   crypto.SHA1,
@@ -151,6 +203,18 @@ func init() {
     AddKeySign(oidDigestAlgorithmECDSASHA512, func() KeySign {
         return KeySignWithEcdsaSHA512
     })
+    AddKeySign(oidDigestAlgorithmECDSASHA3_224, func() KeySign {
+        return KeySignWithEcdsaSHA3_224
+    })
+    AddKeySign(oidDigestAlgorithmECDSASHA3_256, func() KeySign {
+        return KeySignWithEcdsaSHA3_256
+    })
+    AddKeySign(oidDigestAlgorithmECDSASHA3_384, func() KeySign {
+        return KeySignWithEcdsaSHA3_384
+    })
+    AddKeySign(oidDigestAlgorithmECDSASHA3_512, func() KeySign {
+        return KeySignWithEcdsaSHA3_512
+    })
 
     AddKeySign(oidDigestAlgorithmRSAMD5, func() KeySign {
         return KeySignWithRsaMD5
@@ -170,6 +234,18 @@ func init() {
     AddKeySign(oidDigestAlgorithmRSASHA512, func() KeySign {
         return KeySignWithRsaSHA512
     })
+    AddKeySign(oidDigestAlgorithmRSASHA3_224, func() KeySign {
+        return KeySignWithRsaSHA3_224
+    })
+    AddKeySign(oidDigestAlgorithmRSASHA3_256, func() KeySign {
+        return KeySignWithRsaSHA3_256
+    })
+    AddKeySign(oidDigestAlgorithmRSASHA3_384, func() KeySign {
+        return KeySignWithRsaSHA3_384
+    })
+    AddKeySign(oidDigestAlgorithmRSASHA3_512, func() KeySign {
+        return KeySignWithRsaSHA3_512
+    })
 
     AddKeySign(oidDigestAlgorithmEd25519, func() KeySign {
         return KeySignWithEdDsaSHA1
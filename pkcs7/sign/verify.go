@@ -1,7 +1,9 @@
 package sign
 
 import (
+    "io"
     "fmt"
+    "hash"
     "time"
     "bytes"
     "errors"
@@ -132,6 +134,11 @@ func verifySignatureAtTime(p7 *PKCS7, signer signerInfo, truststore *x509.CertPo
 }
 
 func verifySignature(p7 *PKCS7, signer signerInfo, truststore *x509.CertPool) (err error) {
+    return verifySignatureWithDigest(p7, signer, truststore, nil)
+}
+
+// computed 为已计算的内容摘要, 为 nil 时使用 p7.Content 计算
+func verifySignatureWithDigest(p7 *PKCS7, signer signerInfo, truststore *x509.CertPool, computed []byte) (err error) {
     signedData := p7.Content
     ee := getCertFromCertsByIssuerAndSerial(p7.Certificates, signer.IssuerAndSerialNumber)
     if ee == nil {
@@ -152,7 +159,9 @@ func verifySignature(p7 *PKCS7, signer signerInfo, truststore *x509.CertPool) (e
             return err
         }
 
-        computed := hashFunc.Sum(p7.Content)
+        if computed == nil {
+            computed = hashFunc.Sum(p7.Content)
+        }
 
         if subtle.ConstantTimeCompare(digest, computed) != 1 {
             return &MessageDigestMismatchError{
@@ -201,6 +210,65 @@ func verifySignature(p7 *PKCS7, signer signerInfo, truststore *x509.CertPool) (e
     return nil
 }
 
+// VerifyDetached is a wrapper around VerifyDetachedWithChain() that
+// disables certificate chain verification.
+func (this *PKCS7) VerifyDetached(r io.Reader) (err error) {
+    return this.VerifyDetachedWithChain(r, nil)
+}
+
+// VerifyDetachedWithChain checks the signatures of a detached PKCS7 object,
+// the content is read from r and hashed on the fly, so it never needs to be
+// held in memory. Every signer must have authenticated attributes.
+func (this *PKCS7) VerifyDetachedWithChain(r io.Reader, truststore *x509.CertPool) (err error) {
+    if len(this.Signers) == 0 {
+        return errors.New("pkcs7: Message has no signers")
+    }
+
+    // 每种摘要方式只计算一次
+    hashs := make(map[string]hash.Hash)
+    writers := make([]io.Writer, 0)
+
+    for _, signer := range this.Signers {
+        if len(signer.AuthenticatedAttributes) == 0 {
+            return errors.New("pkcs7: detached verification requires authenticated attributes")
+        }
+
+        oid := signer.DigestAlgorithm.Algorithm.String()
+        if _, ok := hashs[oid]; ok {
+            continue
+        }
+
+        signHash, err := parseHashFromOid(signer.DigestAlgorithm.Algorithm)
+        if err != nil {
+            return err
+        }
+
+        streamHash, ok := signHash.(StreamSignHash)
+        if !ok {
+            return ErrNotStreamSignHash
+        }
+
+        h := streamHash.New()
+
+        hashs[oid] = h
+        writers = append(writers, h)
+    }
+
+    if _, err = io.Copy(io.MultiWriter(writers...), r); err != nil {
+        return err
+    }
+
+    for _, signer := range this.Signers {
+        computed := hashs[signer.DigestAlgorithm.Algorithm.String()].Sum(nil)
+
+        if err := verifySignatureWithDigest(this, signer, truststore, computed); err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
 // GetOnlySigner returns an x509.Certificate for the first signer of the signed
 // data payload. If there are more or less than one signer, nil is returned
 func (this *PKCS7) GetOnlySigner() *x509.Certificate {
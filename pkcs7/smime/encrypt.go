@@ -0,0 +1,69 @@
+package smime
+
+import (
+    "io"
+    "bytes"
+    "crypto/x509"
+
+    "github.com/deatil/go-cryptobin/pkcs7/encrypt"
+)
+
+// 生成 application/pkcs7-mime; smime-type=enveloped-data 消息
+// content 为需要加密的 MIME 实体, 可以是已签名的消息
+// recipients 可用 [*x509.Certificate | *sm2X509.Certificate],
+// SM2 证书需设置 opts 为 encrypt.Opts{Cipher: encrypt.SM4CBC, KeyEncrypt: encrypt.KeyEncryptSM2}
+func Encrypt(rand io.Reader, content []byte, recipients []any, opts ...encrypt.Opts) ([]byte, error) {
+    certs, err := parseCerts(recipients)
+    if err != nil {
+        return nil, err
+    }
+
+    enveloped, err := encrypt.Encrypt(rand, Canonicalize(content), certs, opts...)
+    if err != nil {
+        return nil, err
+    }
+
+    return newPKCS7Mime(TypeEnvelopedData, enveloped), nil
+}
+
+// 生成 application/pkcs7-mime; smime-type=authEnveloped-data 消息
+func EncryptAuth(rand io.Reader, content []byte, recipients []any, cipher encrypt.AuthCipher, opts ...encrypt.Opts) ([]byte, error) {
+    certs, err := parseCerts(recipients)
+    if err != nil {
+        return nil, err
+    }
+
+    enveloped, err := encrypt.EncryptAuth(rand, Canonicalize(content), certs, cipher, opts...)
+    if err != nil {
+        return nil, err
+    }
+
+    return newPKCS7Mime(TypeAuthEnvelopedData, enveloped), nil
+}
+
+// 生成 application/pkcs7-mime 消息
+func newPKCS7Mime(typ Type, data []byte) []byte {
+    var buf bytes.Buffer
+    buf.WriteString("MIME-Version: 1.0\r\n")
+    buf.WriteString("Content-Disposition: attachment; filename=\"smime.p7m\"\r\n")
+    buf.WriteString("Content-Type: application/pkcs7-mime; smime-type=" + typ.String() + "; name=\"smime.p7m\"\r\n")
+    buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+    buf.WriteString("\r\n")
+    buf.Write(encodeBase64(data))
+
+    return buf.Bytes()
+}
+
+func parseCerts(certs []any) ([]*x509.Certificate, error) {
+    newCerts := make([]*x509.Certificate, 0, len(certs))
+    for _, cert := range certs {
+        c, err := parseCert(cert)
+        if err != nil {
+            return nil, err
+        }
+
+        newCerts = append(newCerts, c)
+    }
+
+    return newCerts, nil
+}
@@ -0,0 +1,287 @@
+package smime
+
+import (
+    "io"
+    "bytes"
+    "bufio"
+    "errors"
+    "strings"
+    "mime"
+    "mime/quotedprintable"
+    "net/textproto"
+    "encoding/asn1"
+    "encoding/base64"
+
+    "github.com/deatil/go-cryptobin/ber"
+    "github.com/deatil/go-cryptobin/pkcs7/sign"
+)
+
+// S/MIME 消息
+type Message struct {
+    // 消息头
+    Header textproto.MIMEHeader
+
+    // 消息类型
+    Type Type
+
+    // multipart/signed 消息的 micalg 参数
+    Micalg string
+
+    // 签名的 MIME 实体.
+    // multipart/signed 时为转换为规范格式的第一部分, signed-data 时为签名数据中的内容
+    Content []byte
+
+    // DER 编码的 CMS 数据
+    Data []byte
+}
+
+// 解析 S/MIME 消息, 换行可以为 CRLF 或者 LF
+// 只有 multipart/signed 的签名部分会转换为规范格式, 其他内容保持原样
+func Parse(msg []byte) (*Message, error) {
+    header, body, err := readEntity(msg)
+    if err != nil {
+        return nil, err
+    }
+
+    mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+    if err != nil {
+        return nil, ErrNotSMIME
+    }
+
+    switch mediaType {
+        case "multipart/signed":
+            return parseMultipartSigned(header, body, params)
+        case "application/pkcs7-mime", "application/x-pkcs7-mime":
+            return parsePKCS7Mime(header, body)
+    }
+
+    return nil, ErrNotSMIME
+}
+
+// multipart/signed
+func parseMultipartSigned(header textproto.MIMEHeader, body []byte, params map[string]string) (*Message, error) {
+    boundary := params["boundary"]
+    if boundary == "" {
+        return nil, errors.New("pkcs7/smime: multipart/signed boundary is empty")
+    }
+
+    parts, err := splitMultipart(body, boundary)
+    if err != nil {
+        return nil, err
+    }
+
+    if len(parts) != 2 {
+        return nil, errors.New("pkcs7/smime: multipart/signed must have two parts")
+    }
+
+    sigHeader, sigBody, err := readEntity(parts[1])
+    if err != nil {
+        return nil, err
+    }
+
+    sigType, _, err := mime.ParseMediaType(sigHeader.Get("Content-Type"))
+    if err != nil {
+        return nil, err
+    }
+
+    if sigType != "application/pkcs7-signature" && sigType != "application/x-pkcs7-signature" {
+        return nil, errors.New("pkcs7/smime: unsupported signature type " + sigType)
+    }
+
+    data, err := decodeBody(sigHeader, sigBody)
+    if err != nil {
+        return nil, err
+    }
+
+    content, err := canonicalizeEntity(parts[0])
+    if err != nil {
+        return nil, err
+    }
+
+    return &Message{
+        Header:  header,
+        Type:    TypeSigned,
+        Micalg:  strings.ToLower(params["micalg"]),
+        Content: content,
+        Data:    data,
+    }, nil
+}
+
+// 签名部分转换为规范格式, binary 编码的内容不能修改
+func canonicalizeEntity(part []byte) ([]byte, error) {
+    header, _, err := readEntity(part)
+    if err != nil {
+        return nil, err
+    }
+
+    encoding := strings.ToLower(strings.TrimSpace(header.Get("Content-Transfer-Encoding")))
+    if encoding == "binary" {
+        return part, nil
+    }
+
+    return Canonicalize(part), nil
+}
+
+// application/pkcs7-mime, 消息类型以 CMS 数据为准
+func parsePKCS7Mime(header textproto.MIMEHeader, body []byte) (*Message, error) {
+    data, err := decodeBody(header, body)
+    if err != nil {
+        return nil, err
+    }
+
+    der, err := ber.Ber2der(data)
+    if err != nil {
+        return nil, err
+    }
+
+    var info struct {
+        ContentType asn1.ObjectIdentifier
+        Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+    }
+    if _, err = asn1.Unmarshal(der, &info); err != nil {
+        return nil, err
+    }
+
+    m := &Message{
+        Header: header,
+        Data:   data,
+    }
+
+    switch {
+        case info.ContentType.Equal(oidSignedData):
+            p7, err := sign.Parse(data)
+            if err != nil {
+                return nil, err
+            }
+
+            m.Type = TypeSignedData
+            m.Content = p7.Content
+        case info.ContentType.Equal(oidEnvelopedData):
+            m.Type = TypeEnvelopedData
+        case info.ContentType.Equal(oidAuthEnvelopedData):
+            m.Type = TypeAuthEnvelopedData
+        default:
+            return nil, errors.New("pkcs7/smime: unsupported content type " + info.ContentType.String())
+    }
+
+    return m, nil
+}
+
+// 读取 MIME 实体的消息头及内容, 内容保持原样
+func readEntity(data []byte) (textproto.MIMEHeader, []byte, error) {
+    idx := headerEnd(data)
+    if idx < 0 {
+        return nil, nil, errors.New("pkcs7/smime: message header is not terminated")
+    }
+
+    headerBytes, body := data[:idx], data[idx:]
+
+    if len(bytes.TrimSpace(headerBytes)) == 0 {
+        return textproto.MIMEHeader{}, body, nil
+    }
+
+    r := textproto.NewReader(bufio.NewReader(bytes.NewReader(Canonicalize(headerBytes))))
+
+    header, err := r.ReadMIMEHeader()
+    if err != nil {
+        return nil, nil, err
+    }
+
+    return header, body, nil
+}
+
+// 消息头结束位置, 为第一个空行之后的位置
+func headerEnd(data []byte) int {
+    for pos := 0; pos < len(data); {
+        end := bytes.IndexByte(data[pos:], '\n')
+        if end < 0 {
+            return -1
+        }
+
+        end += pos
+
+        if len(bytes.TrimSuffix(data[pos:end], []byte("\r"))) == 0 {
+            return end + 1
+        }
+
+        pos = end + 1
+    }
+
+    return -1
+}
+
+// 拆分 multipart 内容, 分隔符之前的换行属于分隔符 (RFC 2046 5.1.1)
+func splitMultipart(body []byte, boundary string) ([][]byte, error) {
+    delimiter := []byte("--" + boundary)
+
+    var parts [][]byte
+    start := -1
+
+    for pos := 0; pos < len(body); {
+        end := bytes.IndexByte(body[pos:], '\n')
+
+        next := len(body)
+        if end < 0 {
+            end = len(body)
+        } else {
+            end += pos
+            next = end + 1
+        }
+
+        line := bytes.TrimSuffix(body[pos:end], []byte("\r"))
+
+        if bytes.HasPrefix(line, delimiter) {
+            rest := bytes.TrimRight(line[len(delimiter):], " \t")
+            isClose := bytes.Equal(rest, []byte("--"))
+
+            if len(rest) == 0 || isClose {
+                if start >= 0 {
+                    partEnd := pos
+                    if partEnd > start && body[partEnd-1] == '\n' {
+                        partEnd--
+                    }
+                    if partEnd > start && body[partEnd-1] == '\r' {
+                        partEnd--
+                    }
+
+                    parts = append(parts, body[start:partEnd])
+                }
+
+                if isClose {
+                    return parts, nil
+                }
+
+                start = next
+            }
+        }
+
+        pos = next
+    }
+
+    return nil, errors.New("pkcs7/smime: multipart close delimiter not found")
+}
+
+// 按 Content-Transfer-Encoding 解码内容
+func decodeBody(header textproto.MIMEHeader, body []byte) ([]byte, error) {
+    encoding := strings.ToLower(strings.TrimSpace(header.Get("Content-Transfer-Encoding")))
+
+    switch encoding {
+        case "base64":
+            cleaned := bytes.Map(func(r rune) rune {
+                switch r {
+                    case ' ', '\t', '\r', '\n':
+                        return -1
+                }
+
+                return r
+            }, body)
+
+            return base64.StdEncoding.DecodeString(string(cleaned))
+        case "quoted-printable":
+            return io.ReadAll(quotedprintable.NewReader(bytes.NewReader(body)))
+        case "", "7bit", "8bit", "binary":
+            return body, nil
+    }
+
+    return nil, errors.New("pkcs7/smime: unsupported content transfer encoding " + encoding)
+}
@@ -0,0 +1,159 @@
+package smime
+
+import (
+    "bytes"
+    "errors"
+    "crypto"
+    "crypto/ecdsa"
+    "crypto/x509"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+    "github.com/deatil/go-cryptobin/pkcs7/sign"
+    sm2X509 "github.com/deatil/go-cryptobin/gm/x509"
+)
+
+var errCertificateType = errors.New("pkcs7/smime: certificate type must be *x509.Certificate or *sm2X509.Certificate")
+
+// 签名配置
+type SignOpts struct {
+    // 签名方式, 为空时根据私钥选择
+    // [*sm2.PrivateKey: SM2SM3 | *ecdsa.PrivateKey: EcdsaSHA256 | 其他: RsaSHA256]
+    KeySign sign.KeySign
+
+    // 证书链, 会添加到签名数据中
+    Parents []any
+
+    // 额外的签名属性
+    ExtraSignedAttributes []sign.Attribute
+}
+
+// 生成 multipart/signed 分离签名消息
+// content 为需要签名的 MIME 实体, 可以使用 NewEntity 生成
+// cert 可用 [*x509.Certificate | *sm2X509.Certificate]
+func Sign(content []byte, cert any, pkey crypto.PrivateKey, opts SignOpts) ([]byte, error) {
+    content = Canonicalize(content)
+
+    sd, keySign, err := newSignedData(content, cert, pkey, opts)
+    if err != nil {
+        return nil, err
+    }
+
+    sd.Detach()
+
+    signature, err := sd.Finish()
+    if err != nil {
+        return nil, err
+    }
+
+    micalg, err := Micalg(keySign.HashOID())
+    if err != nil {
+        return nil, err
+    }
+
+    boundary, err := newBoundary()
+    if err != nil {
+        return nil, err
+    }
+
+    var buf bytes.Buffer
+    buf.WriteString("MIME-Version: 1.0\r\n")
+    buf.WriteString("Content-Type: multipart/signed; protocol=\"application/pkcs7-signature\"; micalg=\"" + micalg + "\"; boundary=\"" + boundary + "\"\r\n")
+    buf.WriteString("\r\n")
+    buf.WriteString("This is an S/MIME signed message\r\n")
+    buf.WriteString("\r\n--" + boundary + "\r\n")
+    buf.Write(content)
+    buf.WriteString("\r\n--" + boundary + "\r\n")
+    buf.WriteString("Content-Type: application/pkcs7-signature; name=\"smime.p7s\"\r\n")
+    buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+    buf.WriteString("Content-Disposition: attachment; filename=\"smime.p7s\"\r\n")
+    buf.WriteString("\r\n")
+    buf.Write(encodeBase64(signature))
+    buf.WriteString("\r\n--" + boundary + "--\r\n")
+
+    return buf.Bytes(), nil
+}
+
+// 生成 application/pkcs7-mime; smime-type=signed-data 消息
+// 签名数据中包含原始内容
+func SignOpaque(content []byte, cert any, pkey crypto.PrivateKey, opts SignOpts) ([]byte, error) {
+    content = Canonicalize(content)
+
+    sd, _, err := newSignedData(content, cert, pkey, opts)
+    if err != nil {
+        return nil, err
+    }
+
+    signed, err := sd.Finish()
+    if err != nil {
+        return nil, err
+    }
+
+    return newPKCS7Mime(TypeSignedData, signed), nil
+}
+
+func newSignedData(content []byte, cert any, pkey crypto.PrivateKey, opts SignOpts) (*sign.SignedData, sign.KeySign, error) {
+    ee, err := parseCert(cert)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    keySign := opts.KeySign
+    if keySign == nil {
+        switch pkey.(type) {
+            case *sm2.PrivateKey:
+                keySign = sign.KeySignWithSM2SM3
+            case *ecdsa.PrivateKey:
+                keySign = sign.KeySignWithEcdsaSHA256
+            default:
+                keySign = sign.KeySignWithRsaSHA256
+        }
+    }
+
+    sd, err := sign.NewSignedData(content)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    sd.SetDigestAlgorithm(keySign.HashOID())
+    sd.SetEncryptionAlgorithm(keySign.OID())
+
+    err = sd.AddSigner(ee, pkey, sign.SignerInfoConfig{
+        ExtraSignedAttributes: opts.ExtraSignedAttributes,
+    })
+    if err != nil {
+        return nil, nil, err
+    }
+
+    // crypto/x509 不能验证 SM2 证书链, 证书链只添加到签名数据中
+    for _, parent := range opts.Parents {
+        p, err := parseCert(parent)
+        if err != nil {
+            return nil, nil, err
+        }
+
+        sd.AddCertificate(p)
+    }
+
+    return sd, keySign, nil
+}
+
+// 解析证书
+// 可用 [*x509.Certificate | *sm2X509.Certificate]
+func parseCert(cert any) (*x509.Certificate, error) {
+    switch c := cert.(type) {
+        case *x509.Certificate:
+            if c == nil {
+                break
+            }
+
+            return c, nil
+        case *sm2X509.Certificate:
+            if c == nil {
+                break
+            }
+
+            return c.ToX509Certificate(), nil
+    }
+
+    return nil, errCertificateType
+}
@@ -0,0 +1,146 @@
+package smime
+
+import (
+    "bytes"
+    "errors"
+    "strings"
+    "crypto/rand"
+    "encoding/hex"
+    "encoding/asn1"
+    "encoding/base64"
+
+    "github.com/deatil/go-cryptobin/pkcs7/sign"
+)
+
+/**
+ * S/MIME 消息 (RFC 8551)
+ *
+ * multipart/signed:       分离签名, 签名为 application/pkcs7-signature
+ * application/pkcs7-mime: signed-data, enveloped-data 及 authEnveloped-data
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+
+var (
+    oidSignedData        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+    oidEnvelopedData     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 3}
+    oidAuthEnvelopedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 23}
+)
+
+// 消息类型
+type Type int
+
+const (
+    // multipart/signed
+    TypeSigned Type = 1 + iota
+
+    // application/pkcs7-mime; smime-type=signed-data
+    TypeSignedData
+
+    // application/pkcs7-mime; smime-type=enveloped-data
+    TypeEnvelopedData
+
+    // application/pkcs7-mime; smime-type=authEnveloped-data
+    TypeAuthEnvelopedData
+)
+
+func (t Type) String() string {
+    switch t {
+        case TypeSigned:
+            return "multipart/signed"
+        case TypeSignedData:
+            return "signed-data"
+        case TypeEnvelopedData:
+            return "enveloped-data"
+        case TypeAuthEnvelopedData:
+            return "authEnveloped-data"
+    }
+
+    return "unknown"
+}
+
+var (
+    ErrNotSMIME      = errors.New("pkcs7/smime: message is not a S/MIME message")
+    ErrNotDetached   = errors.New("pkcs7/smime: multipart/signed signature is not detached")
+    ErrMicalgInvalid = errors.New("pkcs7/smime: micalg does not match signer digest algorithm")
+)
+
+// 摘要方式对应的 micalg 名称
+var micalgs = map[string]string{}
+
+// 添加 micalg 名称
+func AddMicalg(oid asn1.ObjectIdentifier, name string) {
+    micalgs[oid.String()] = name
+}
+
+// 获取摘要方式对应的 micalg 名称
+func Micalg(oid asn1.ObjectIdentifier) (string, error) {
+    name, ok := micalgs[oid.String()]
+    if !ok {
+        return "", errors.New("pkcs7/smime: unsupported micalg digest algorithm " + oid.String())
+    }
+
+    return name, nil
+}
+
+func init() {
+    // RFC 8551 3.4.3.2
+    AddMicalg(asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 5}, "md5")
+    AddMicalg(sign.SignHashWithSHA1.OID(), "sha-1")
+    AddMicalg(sign.SignHashWithSHA224.OID(), "sha-224")
+    AddMicalg(sign.SignHashWithSHA256.OID(), "sha-256")
+    AddMicalg(sign.SignHashWithSHA384.OID(), "sha-384")
+    AddMicalg(sign.SignHashWithSHA512.OID(), "sha-512")
+
+    // 和 openssl 保持一致
+    AddMicalg(sign.SignHashWithSHA3_224.OID(), "sha3-224")
+    AddMicalg(sign.SignHashWithSHA3_256.OID(), "sha3-256")
+    AddMicalg(sign.SignHashWithSHA3_384.OID(), "sha3-384")
+    AddMicalg(sign.SignHashWithSHA3_512.OID(), "sha3-512")
+    AddMicalg(sign.SignHashWithSM3.OID(), "sm3")
+}
+
+// 转换为规范格式, 换行统一为 CRLF
+func Canonicalize(data []byte) []byte {
+    data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+    data = bytes.ReplaceAll(data, []byte("\r"), []byte("\n"))
+
+    return bytes.ReplaceAll(data, []byte("\n"), []byte("\r\n"))
+}
+
+// 生成 MIME 实体, 签名及加密的内容需要为 MIME 实体
+func NewEntity(contentType string, body []byte) []byte {
+    var buf bytes.Buffer
+    buf.WriteString("Content-Type: " + contentType + "\r\n\r\n")
+    buf.Write(Canonicalize(body))
+
+    return buf.Bytes()
+}
+
+// base64 编码, 每行 64 个字符
+func encodeBase64(data []byte) []byte {
+    encoded := base64.StdEncoding.EncodeToString(data)
+
+    var buf bytes.Buffer
+    for len(encoded) > 64 {
+        buf.WriteString(encoded[:64] + "\r\n")
+        encoded = encoded[64:]
+    }
+
+    if len(encoded) > 0 {
+        buf.WriteString(encoded + "\r\n")
+    }
+
+    return buf.Bytes()
+}
+
+// 生成分隔符
+func newBoundary() (string, error) {
+    buf := make([]byte, 16)
+    if _, err := rand.Read(buf); err != nil {
+        return "", err
+    }
+
+    return "----" + strings.ToUpper(hex.EncodeToString(buf)), nil
+}
@@ -0,0 +1,563 @@
+package smime
+
+import (
+    "time"
+    "bytes"
+    "testing"
+    "math/big"
+    "crypto"
+    "crypto/rsa"
+    "crypto/rand"
+    "crypto/ecdsa"
+    "crypto/elliptic"
+    "crypto/x509"
+    "crypto/x509/pkix"
+    "encoding/pem"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+    "github.com/deatil/go-cryptobin/pkcs7/sign"
+    "github.com/deatil/go-cryptobin/pkcs7/encrypt"
+    sm2X509 "github.com/deatil/go-cryptobin/gm/x509"
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+// openssl smime -sign -text -md sha256
+var testOpenSSLSigned = `MIME-Version: 1.0
+Content-Type: multipart/signed; protocol="application/x-pkcs7-signature"; micalg="sha-256"; boundary="----A4E07602B2BD5D44A58160150885FED1"
+
+This is an S/MIME signed message
+
+------A4E07602B2BD5D44A58160150885FED1
+Content-Type: text/plain
+
+hello smime
+second line
+
+------A4E07602B2BD5D44A58160150885FED1
+Content-Type: application/x-pkcs7-signature; name="smime.p7s"
+Content-Transfer-Encoding: base64
+Content-Disposition: attachment; filename="smime.p7s"
+
+MIIDKwYJKoZIhvcNAQcCoIIDHDCCAxgCAQExDzANBglghkgBZQMEAgEFADALBgkq
+hkiG9w0BBwGgggF2MIIBcjCCARegAwIBAgIUBZVJTNEaZprZFSLT1G+aCNhGIkQw
+CgYIKoZIzj0EAwIwDTELMAkGA1UEAwwCZWMwIBcNMjYxMDE4MDExNDQ0WhgPMjEy
+NjA5MjQwMTE0NDRaMA0xCzAJBgNVBAMMAmVjMFkwEwYHKoZIzj0CAQYIKoZIzj0D
+AQcDQgAE1qYIFWSXafT6uZHzcESpvsb6BEEth43EDmtMn42oQdYB1leFw0AU9B35
+XpSa25MucIiIrvtAxPfUV5QKytyEp6NTMFEwHQYDVR0OBBYEFE3lxQyRK4tQKQYz
+bFQRULW7WhW/MB8GA1UdIwQYMBaAFE3lxQyRK4tQKQYzbFQRULW7WhW/MA8GA1Ud
+EwEB/wQFMAMBAf8wCgYIKoZIzj0EAwIDSQAwRgIhAMpfJiu/lVycMe0o+WFeoeFG
+d+oiz8HIrEk7adTWPkQyAiEA4vxJg2DpUeNTOSCUw6xhjEOzzphr3MCajZnBklsK
+2xExggF5MIIBdQIBATAlMA0xCzAJBgNVBAMMAmVjAhQFlUlM0RpmmtkVItPUb5oI
+2EYiRDANBglghkgBZQMEAgEFAKCB5DAYBgkqhkiG9w0BCQMxCwYJKoZIhvcNAQcB
+MBwGCSqGSIb3DQEJBTEPFw0yNjEwMTgwMTMwMjBaMC8GCSqGSIb3DQEJBDEiBCBz
+VHMKrjU70RfooGeqh4H/xf6GrXiu6PscMlAO8LlQpjB5BgkqhkiG9w0BCQ8xbDBq
+MAsGCWCGSAFlAwQBKjALBglghkgBZQMEARYwCwYJYIZIAWUDBAECMAoGCCqGSIb3
+DQMHMA4GCCqGSIb3DQMCAgIAgDANBggqhkiG9w0DAgIBQDAHBgUrDgMCBzANBggq
+hkiG9w0DAgIBKDAKBggqhkjOPQQDAgRHMEUCIQCojMklb3xIWaLJq9r+swCOFNjB
+8H/kC6XR0vx/IldIdQIgFnU4tgTVpOfazyx7srKUqyLJUvijb7rheBvtD27vLiU=
+
+------A4E07602B2BD5D44A58160150885FED1--
+
+`
+
+var testOpenSSLCert = `-----BEGIN CERTIFICATE-----
+MIIBcjCCARegAwIBAgIUBZVJTNEaZprZFSLT1G+aCNhGIkQwCgYIKoZIzj0EAwIw
+DTELMAkGA1UEAwwCZWMwIBcNMjYxMDE4MDExNDQ0WhgPMjEyNjA5MjQwMTE0NDRa
+MA0xCzAJBgNVBAMMAmVjMFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAE1qYIFWSX
+afT6uZHzcESpvsb6BEEth43EDmtMn42oQdYB1leFw0AU9B35XpSa25MucIiIrvtA
+xPfUV5QKytyEp6NTMFEwHQYDVR0OBBYEFE3lxQyRK4tQKQYzbFQRULW7WhW/MB8G
+A1UdIwQYMBaAFE3lxQyRK4tQKQYzbFQRULW7WhW/MA8GA1UdEwEB/wQFMAMBAf8w
+CgYIKoZIzj0EAwIDSQAwRgIhAMpfJiu/lVycMe0o+WFeoeFGd+oiz8HIrEk7adTW
+PkQyAiEA4vxJg2DpUeNTOSCUw6xhjEOzzphr3MCajZnBklsK2xE=
+-----END CERTIFICATE-----
+`
+
+func newTestCert(t *testing.T, serial int64, name string, pub crypto.PublicKey, parent *x509.Certificate, signer crypto.Signer, isCA bool) *x509.Certificate {
+    template := &x509.Certificate{
+        SerialNumber:          big.NewInt(serial),
+        Subject:               pkix.Name{CommonName: name},
+        NotBefore:             time.Now().Add(-time.Hour),
+        NotAfter:              time.Now().Add(time.Hour),
+        BasicConstraintsValid: true,
+        IsCA:                  isCA,
+        EmailAddresses:        []string{name + "@example.com"},
+    }
+
+    if isCA {
+        template.KeyUsage = x509.KeyUsageCertSign
+    }
+
+    if parent == nil {
+        parent = template
+    }
+
+    der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, signer)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    cert, err := x509.ParseCertificate(der)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return cert
+}
+
+func newSM2Cert(t *testing.T, serial int64, name string, pub *sm2.PublicKey, parent *sm2X509.Certificate, signer crypto.Signer, isCA bool) *sm2X509.Certificate {
+    template := &sm2X509.Certificate{
+        SerialNumber:          big.NewInt(serial),
+        Subject:               pkix.Name{CommonName: name},
+        NotBefore:             time.Now().Add(-time.Hour),
+        NotAfter:              time.Now().Add(time.Hour),
+        BasicConstraintsValid: true,
+        IsCA:                  isCA,
+        SignatureAlgorithm:    sm2X509.SM2WithSM3,
+    }
+
+    if isCA {
+        template.KeyUsage = sm2X509.KeyUsageCertSign
+    }
+
+    if parent == nil {
+        parent = template
+    }
+
+    der, err := sm2X509.CreateCertificate(template, parent, pub, signer)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    cert, err := sm2X509.ParseCertificate(der)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return cert
+}
+
+func Test_Canonicalize(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+
+    assertEqual(Canonicalize([]byte("a\nb\r\nc\rd")), []byte("a\r\nb\r\nc\r\nd"), "Test_Canonicalize")
+    assertEqual(NewEntity("text/plain", []byte("a\nb")), []byte("Content-Type: text/plain\r\n\r\na\r\nb"), "Test_Canonicalize-NewEntity")
+}
+
+func Test_Micalg(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    for _, c := range []struct{
+        hash sign.SignHash
+        name string
+    }{
+        {sign.SignHashWithSHA1, "sha-1"},
+        {sign.SignHashWithSHA256, "sha-256"},
+        {sign.SignHashWithSHA512, "sha-512"},
+        {sign.SignHashWithSHA3_256, "sha3-256"},
+        {sign.SignHashWithSM3, "sm3"},
+    } {
+        name, err := Micalg(c.hash.OID())
+        assertError(err, "Test_Micalg-" + c.name)
+        assertEqual(name, c.name, "Test_Micalg-" + c.name)
+    }
+
+    _, err := Micalg(encrypt.AES128CBC.OID())
+    assertNotErrorNil(err, "Test_Micalg-unknown")
+}
+
+func Test_ParseOpenSSLSigned(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    block, _ := pem.Decode([]byte(testOpenSSLCert))
+    cert, err := x509.ParseCertificate(block.Bytes)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    m, err := Parse([]byte(testOpenSSLSigned))
+    assertError(err, "Test_ParseOpenSSLSigned-Parse")
+
+    assertEqual(m.Type, TypeSigned, "Test_ParseOpenSSLSigned-Type")
+    assertEqual(m.Micalg, "sha-256", "Test_ParseOpenSSLSigned-Micalg")
+    assertEqual(m.Content, []byte("Content-Type: text/plain\r\n\r\nhello smime\r\nsecond line\r\n"), "Test_ParseOpenSSLSigned-Content")
+
+    roots := x509.NewCertPool()
+    roots.AddCert(cert)
+
+    signers, err := m.Verify(VerifyOpts{Roots: roots})
+    assertError(err, "Test_ParseOpenSSLSigned-Verify")
+    assertEqual(signers[0].Raw, cert.Raw, "Test_ParseOpenSSLSigned-Signer")
+
+    // 修改内容
+    tampered := bytes.Replace([]byte(testOpenSSLSigned), []byte("hello smime"), []byte("hello SMIME"), 1)
+
+    m, err = Parse(tampered)
+    assertError(err, "Test_ParseOpenSSLSigned-Parse-tampered")
+
+    _, err = m.Verify(VerifyOpts{})
+    assertNotErrorNil(err, "Test_ParseOpenSSLSigned-Verify-tampered")
+}
+
+func Test_SignRSA(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    caKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+    ca := newTestCert(t, 1, "ca", &caKey.PublicKey, nil, caKey, true)
+
+    key, _ := rsa.GenerateKey(rand.Reader, 2048)
+    cert := newTestCert(t, 2, "alice", &key.PublicKey, ca, caKey, false)
+
+    content := NewEntity("text/plain; charset=utf-8", []byte("hello\nworld\n"))
+
+    roots := x509.NewCertPool()
+    roots.AddCert(ca)
+
+    for _, c := range []struct{
+        name    string
+        keySign sign.KeySign
+        micalg  string
+    }{
+        {"default", nil, "sha-256"},
+        {"SHA512", sign.KeySignWithRsaSHA512, "sha-512"},
+        {"SHA3_256", sign.KeySignWithRsaSHA3_256, "sha3-256"},
+    } {
+        opts := SignOpts{
+            KeySign: c.keySign,
+            Parents: []any{ca},
+        }
+
+        signed, err := Sign(content, cert, key, opts)
+        assertError(err, "Test_SignRSA-Sign-" + c.name)
+
+        // 传输过程中换行被转换
+        signed = bytes.ReplaceAll(signed, []byte("\r\n"), []byte("\n"))
+
+        m, err := Parse(signed)
+        assertError(err, "Test_SignRSA-Parse-" + c.name)
+
+        assertEqual(m.Type, TypeSigned, "Test_SignRSA-Type-" + c.name)
+        assertEqual(m.Micalg, c.micalg, "Test_SignRSA-Micalg-" + c.name)
+        assertEqual(m.Content, content, "Test_SignRSA-Content-" + c.name)
+
+        signers, err := m.Verify(VerifyOpts{Roots: roots})
+        assertError(err, "Test_SignRSA-Verify-" + c.name)
+        assertEqual(signers[0].Raw, cert.Raw, "Test_SignRSA-Signer-" + c.name)
+
+        // 不受信任的根证书
+        _, err = m.Verify(VerifyOpts{Roots: x509.NewCertPool()})
+        assertNotErrorNil(err, "Test_SignRSA-Verify-roots-" + c.name)
+
+        opaque, err := SignOpaque(content, cert, key, opts)
+        assertError(err, "Test_SignRSA-SignOpaque-" + c.name)
+
+        m, err = Parse(opaque)
+        assertError(err, "Test_SignRSA-Parse-opaque-" + c.name)
+
+        assertEqual(m.Type, TypeSignedData, "Test_SignRSA-Type-opaque-" + c.name)
+        assertEqual(m.Content, content, "Test_SignRSA-Content-opaque-" + c.name)
+
+        _, err = m.Verify(VerifyOpts{Roots: roots})
+        assertError(err, "Test_SignRSA-Verify-opaque-" + c.name)
+    }
+}
+
+func Test_SignSM2(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    caKey, _ := sm2.GenerateKey(rand.Reader)
+    ca := newSM2Cert(t, 1, "sm2 ca", &caKey.PublicKey, nil, caKey, true)
+
+    key, _ := sm2.GenerateKey(rand.Reader)
+    cert := newSM2Cert(t, 2, "sm2 alice", &key.PublicKey, ca, caKey, false)
+
+    content := NewEntity("text/plain", []byte("sm2 message"))
+
+    signed, err := Sign(content, cert, key, SignOpts{
+        Parents: []any{ca},
+    })
+    assertError(err, "Test_SignSM2-Sign")
+
+    m, err := Parse(signed)
+    assertError(err, "Test_SignSM2-Parse")
+
+    assertEqual(m.Micalg, "sm3", "Test_SignSM2-Micalg")
+
+    roots := sm2X509.NewCertPool()
+    roots.AddCert(ca)
+
+    signers, err := m.Verify(VerifyOpts{SM2Roots: roots})
+    assertError(err, "Test_SignSM2-Verify")
+    assertEqual(signers[0].Raw, cert.Raw, "Test_SignSM2-Signer")
+
+    _, err = m.Verify(VerifyOpts{SM2Roots: sm2X509.NewCertPool()})
+    assertNotErrorNil(err, "Test_SignSM2-Verify-roots")
+
+    m.Content = NewEntity("text/plain", []byte("sm2 message 2"))
+
+    _, err = m.Verify(VerifyOpts{})
+    assertNotErrorNil(err, "Test_SignSM2-Verify-tampered")
+}
+
+func Test_Encrypt(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+    rsaCert := newTestCert(t, 1, "rsa", &rsaKey.PublicKey, nil, rsaKey, false)
+
+    sm2Key, _ := sm2.GenerateKey(rand.Reader)
+    sm2Cert := newSM2Cert(t, 1, "sm2", &sm2Key.PublicKey, nil, sm2Key, false)
+
+    content := NewEntity("text/plain", []byte("secret\nmessage"))
+
+    enveloped, err := Encrypt(rand.Reader, content, []any{rsaCert})
+    assertError(err, "Test_Encrypt-Encrypt")
+
+    m, err := Parse(enveloped)
+    assertError(err, "Test_Encrypt-Parse")
+    assertEqual(m.Type, TypeEnvelopedData, "Test_Encrypt-Type")
+
+    plaintext, err := m.Decrypt(rsaCert, rsaKey)
+    assertError(err, "Test_Encrypt-Decrypt")
+    assertEqual(plaintext, content, "Test_Encrypt-Decrypt")
+
+    _, err = m.Verify(VerifyOpts{})
+    assertNotErrorNil(err, "Test_Encrypt-Verify")
+
+    // 认证加密
+    enveloped, err = EncryptAuth(rand.Reader, content, []any{rsaCert}, encrypt.AuthAES256GCM)
+    assertError(err, "Test_Encrypt-EncryptAuth")
+
+    m, err = Parse(enveloped)
+    assertError(err, "Test_Encrypt-Parse-auth")
+    assertEqual(m.Type, TypeAuthEnvelopedData, "Test_Encrypt-Type-auth")
+
+    plaintext, err = m.Decrypt(rsaCert, rsaKey)
+    assertError(err, "Test_Encrypt-Decrypt-auth")
+    assertEqual(plaintext, content, "Test_Encrypt-Decrypt-auth")
+
+    // SM2 证书
+    enveloped, err = Encrypt(rand.Reader, content, []any{sm2Cert}, encrypt.Opts{
+        Cipher:     encrypt.SM4CBC,
+        KeyEncrypt: encrypt.KeyEncryptSM2,
+    })
+    assertError(err, "Test_Encrypt-Encrypt-sm2")
+
+    m, err = Parse(enveloped)
+    assertError(err, "Test_Encrypt-Parse-sm2")
+
+    plaintext, err = m.Decrypt(sm2Cert, sm2Key)
+    assertError(err, "Test_Encrypt-Decrypt-sm2")
+    assertEqual(plaintext, content, "Test_Encrypt-Decrypt-sm2")
+
+    _, err = m.Decrypt(rsaCert, rsaKey)
+    assertNotErrorNil(err, "Test_Encrypt-Decrypt-wrong")
+}
+
+func Test_SignThenEncrypt(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    key, _ := rsa.GenerateKey(rand.Reader, 2048)
+    cert := newTestCert(t, 1, "alice", &key.PublicKey, nil, key, false)
+
+    signed, err := Sign(NewEntity("text/plain", []byte("hello")), cert, key, SignOpts{})
+    assertError(err, "Test_SignThenEncrypt-Sign")
+
+    enveloped, err := Encrypt(rand.Reader, signed, []any{cert})
+    assertError(err, "Test_SignThenEncrypt-Encrypt")
+
+    m, err := Parse(enveloped)
+    assertError(err, "Test_SignThenEncrypt-Parse")
+
+    plaintext, err := m.Decrypt(cert, key)
+    assertError(err, "Test_SignThenEncrypt-Decrypt")
+
+    m, err = Parse(plaintext)
+    assertError(err, "Test_SignThenEncrypt-Parse-signed")
+
+    _, err = m.Verify(VerifyOpts{})
+    assertError(err, "Test_SignThenEncrypt-Verify")
+}
+
+func Test_ParseNotSMIME(t *testing.T) {
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    _, err := Parse(NewEntity("text/plain", []byte("hello")))
+    assertNotErrorNil(err, "Test_ParseNotSMIME")
+
+    _, err = Parse([]byte("Content-Type: multipart/signed; boundary=abc\r\n\r\n--abc\r\npart"))
+    assertNotErrorNil(err, "Test_ParseNotSMIME-unclosed")
+}
+
+func Test_ParseBinary(t *testing.T) {
+    key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    cert := newTestCert(t, 1, "alice", &key.PublicKey, nil, key, false)
+
+    // binary 编码的内容包含单独的 LF 及 CR
+    content := []byte("Content-Type: application/octet-stream\r\n\r\n\x00\n\x01\r\x02")
+
+    sd, err := sign.NewSignedData(content)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    sd.SetDigestAlgorithm(sign.SignHashWithSHA256.OID())
+    sd.SetEncryptionAlgorithm(sign.KeySignWithEcdsaSHA256.OID())
+
+    if err = sd.AddSigner(cert, key, sign.SignerInfoConfig{}); err != nil {
+        t.Fatal(err)
+    }
+
+    der, err := sd.Finish()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    t.Run("signed-data", func(t *testing.T) {
+        msg := []byte("Content-Type: application/pkcs7-mime; smime-type=signed-data\n" +
+            "Content-Transfer-Encoding: binary\n\n")
+        msg = append(msg, der...)
+
+        m, err := Parse(msg)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if !bytes.Equal(m.Data, der) {
+            t.Error("binary body is changed")
+        }
+
+        if !bytes.Equal(m.Content, content) {
+            t.Errorf("Content got %q, want %q", m.Content, content)
+        }
+
+        if _, err = m.Verify(VerifyOpts{}); err != nil {
+            t.Error(err)
+        }
+    })
+
+    t.Run("multipart/signed", func(t *testing.T) {
+        part := []byte("Content-Type: text/plain\nContent-Transfer-Encoding: binary\n\na\nb")
+
+        msg := []byte("Content-Type: multipart/signed; boundary=abc\n\n--abc\n")
+        msg = append(msg, part...)
+        msg = append(msg, "\n--abc\nContent-Type: application/pkcs7-signature\nContent-Transfer-Encoding: base64\n\n"...)
+        msg = append(msg, encodeBase64(der)...)
+        msg = append(msg, "\n--abc--\n"...)
+
+        m, err := Parse(msg)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if !bytes.Equal(m.Content, part) {
+            t.Errorf("Content got %q, want %q", m.Content, part)
+        }
+    })
+}
+
+func Test_VerifySignedData(t *testing.T) {
+    key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    cert := newTestCert(t, 1, "alice", &key.PublicKey, nil, key, false)
+
+    content := NewEntity("text/plain", []byte("hello"))
+
+    opaque, err := SignOpaque(content, cert, key, SignOpts{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    m, err := Parse(opaque)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // signed-data 使用签名数据中的内容验证
+    m.Content = NewEntity("text/plain", []byte("other"))
+
+    if _, err = m.Verify(VerifyOpts{}); err != nil {
+        t.Error(err)
+    }
+}
+
+func Test_VerifyNotDetached(t *testing.T) {
+    key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    cert := newTestCert(t, 1, "alice", &key.PublicKey, nil, key, false)
+
+    content := NewEntity("text/plain", []byte("hello"))
+
+    signed, err := Sign(content, cert, key, SignOpts{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    opaque, err := SignOpaque(NewEntity("text/plain", []byte("other")), cert, key, SignOpts{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    m, err := Parse(signed)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    m2, err := Parse(opaque)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // multipart/signed 中使用包含内容的签名
+    m.Data = m2.Data
+
+    if _, err = m.Verify(VerifyOpts{}); err != ErrNotDetached {
+        t.Errorf("Verify got %v, want ErrNotDetached", err)
+    }
+}
+
+func Test_VerifyMicalg(t *testing.T) {
+    key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    cert := newTestCert(t, 1, "alice", &key.PublicKey, nil, key, false)
+
+    signed, err := Sign(NewEntity("text/plain", []byte("hello")), cert, key, SignOpts{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    check := func(t *testing.T, micalg string, want error) {
+        msg := bytes.Replace(signed, []byte(`micalg="sha-256"`), []byte(micalg), 1)
+
+        m, err := Parse(msg)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if _, err = m.Verify(VerifyOpts{}); err != want {
+            t.Errorf("Verify got %v, want %v", err, want)
+        }
+    }
+
+    t.Run("match", func(t *testing.T) {
+        check(t, `micalg="SHA-256"`, nil)
+    })
+
+    t.Run("list", func(t *testing.T) {
+        check(t, `micalg="sha-1, sha-256"`, nil)
+    })
+
+    t.Run("empty", func(t *testing.T) {
+        check(t, `x-micalg="sha-512"`, nil)
+    })
+
+    t.Run("mismatch", func(t *testing.T) {
+        check(t, `micalg="sha-512"`, ErrMicalgInvalid)
+    })
+}
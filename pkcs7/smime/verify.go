@@ -0,0 +1,154 @@
+package smime
+
+import (
+    "bytes"
+    "errors"
+    "strings"
+    "time"
+    "crypto"
+    "crypto/x509"
+
+    "github.com/deatil/go-cryptobin/pkcs7/sign"
+    "github.com/deatil/go-cryptobin/pkcs7/encrypt"
+    sm2X509 "github.com/deatil/go-cryptobin/gm/x509"
+)
+
+// 验证配置
+type VerifyOpts struct {
+    // 根证书, 不为空时使用 crypto/x509 验证签名者证书链
+    Roots *x509.CertPool
+
+    // SM2 根证书, 不为空时使用 gm/x509 验证签名者证书链
+    SM2Roots *sm2X509.CertPool
+
+    // 证书链验证时间, 为空时使用当前时间
+    CurrentTime time.Time
+}
+
+// 验证签名, 返回签名者证书
+func (this *Message) Verify(opts VerifyOpts) ([]*x509.Certificate, error) {
+    if this.Type != TypeSigned && this.Type != TypeSignedData {
+        return nil, errors.New("pkcs7/smime: message is not signed")
+    }
+
+    p7, err := sign.Parse(this.Data)
+    if err != nil {
+        return nil, err
+    }
+
+    // multipart/signed 为分离签名, 签名内容为第一部分
+    // signed-data 使用签名数据中的内容
+    if this.Type == TypeSigned {
+        if len(p7.Content) > 0 {
+            return nil, ErrNotDetached
+        }
+
+        if err = checkMicalg(this.Micalg, p7); err != nil {
+            return nil, err
+        }
+
+        p7.Content = this.Content
+    }
+
+    if opts.Roots != nil {
+        err = p7.VerifyWithChainAtTime(opts.Roots, opts.CurrentTime)
+    } else {
+        err = p7.Verify()
+    }
+
+    if err != nil {
+        return nil, err
+    }
+
+    signers := make([]*x509.Certificate, 0, len(p7.Signers))
+    for _, signer := range p7.Signers {
+        for _, cert := range p7.Certificates {
+            if cert.SerialNumber.Cmp(signer.IssuerAndSerialNumber.SerialNumber) == 0 &&
+                bytes.Equal(cert.RawIssuer, signer.IssuerAndSerialNumber.IssuerName.FullBytes) {
+                signers = append(signers, cert)
+                break
+            }
+        }
+    }
+
+    if opts.SM2Roots != nil {
+        if err = verifySM2Chains(signers, p7.Certificates, opts); err != nil {
+            return nil, err
+        }
+    }
+
+    return signers, nil
+}
+
+// 解密 enveloped-data 及 authEnveloped-data 消息
+// cert 可用 [*x509.Certificate | *sm2X509.Certificate]
+func (this *Message) Decrypt(cert any, pkey crypto.PrivateKey) ([]byte, error) {
+    if this.Type != TypeEnvelopedData && this.Type != TypeAuthEnvelopedData {
+        return nil, errors.New("pkcs7/smime: message is not encrypted")
+    }
+
+    c, err := parseCert(cert)
+    if err != nil {
+        return nil, err
+    }
+
+    return encrypt.Decrypt(this.Data, c, pkey)
+}
+
+// 检查 micalg 参数和签名者的摘要算法是否一致
+// 多个签名者时 micalg 为逗号分隔的列表, 没有 micalg 参数时不检查
+func checkMicalg(micalg string, p7 *sign.PKCS7) error {
+    if micalg == "" {
+        return nil
+    }
+
+    names := make(map[string]bool)
+    for _, name := range strings.Split(micalg, ",") {
+        names[strings.TrimSpace(name)] = true
+    }
+
+    for _, signer := range p7.Signers {
+        name, err := Micalg(signer.DigestAlgorithm.Algorithm)
+        if err != nil {
+            return err
+        }
+
+        if !names[name] {
+            return ErrMicalgInvalid
+        }
+    }
+
+    return nil
+}
+
+// 使用 gm/x509 验证证书链
+func verifySM2Chains(signers, certs []*x509.Certificate, opts VerifyOpts) error {
+    intermediates := sm2X509.NewCertPool()
+    for _, cert := range certs {
+        c, err := sm2X509.ParseCertificate(cert.Raw)
+        if err != nil {
+            return err
+        }
+
+        intermediates.AddCert(c)
+    }
+
+    for _, signer := range signers {
+        ee, err := sm2X509.ParseCertificate(signer.Raw)
+        if err != nil {
+            return err
+        }
+
+        _, err = ee.Verify(sm2X509.VerifyOptions{
+            Roots:         opts.SM2Roots,
+            Intermediates: intermediates,
+            CurrentTime:   opts.CurrentTime,
+            KeyUsages:     []sm2X509.ExtKeyUsage{sm2X509.ExtKeyUsageAny},
+        })
+        if err != nil {
+            return errors.New("pkcs7/smime: failed to verify certificate chain: " + err.Error())
+        }
+    }
+
+    return nil
+}
@@ -0,0 +1,83 @@
+package timestamp
+
+import (
+    "errors"
+    "crypto/x509"
+    "encoding/asn1"
+
+    "github.com/deatil/go-cryptobin/pkcs7/sign"
+)
+
+// 为 SignedData 的每个签名添加 signatureTimeStampToken 属性
+// tokenFunc 为签名值获取时间戳令牌, 可以使用 Client.Token
+// 需要在 AddSigner 之后, Finish 之前调用
+func AttachTimestamp(signedData *sign.SignedData, tokenFunc func(signature []byte) ([]byte, error)) error {
+    sd := signedData.GetSignedData()
+    if len(sd.SignerInfos) == 0 {
+        return errors.New("pkcs7/timestamp: signed data has no signers")
+    }
+
+    for i := range sd.SignerInfos {
+        signer := &sd.SignerInfos[i]
+
+        token, err := tokenFunc(signer.EncryptedDigest)
+        if err != nil {
+            return err
+        }
+
+        attrs := make([]sign.Attribute, 0, len(signer.UnauthenticatedAttributes)+1)
+        for _, attr := range signer.UnauthenticatedAttributes {
+            if attr.Type.Equal(oidSignatureTimeStampToken) {
+                continue
+            }
+
+            attrs = append(attrs, sign.Attribute{
+                Type:  attr.Type,
+                Value: asn1.RawValue{FullBytes: attr.Value.Bytes},
+            })
+        }
+
+        attrs = append(attrs, sign.Attribute{
+            Type:  oidSignatureTimeStampToken,
+            Value: asn1.RawValue{FullBytes: token},
+        })
+
+        if err = signer.SetUnauthenticatedAttributes(attrs); err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+// 验证 PKCS7 每个签名的 signatureTimeStampToken 属性
+// roots 不为 nil 时同时验证 TSA 证书链
+func VerifyAttachedTimestamps(p7 *sign.PKCS7, roots *x509.CertPool) ([]*Timestamp, error) {
+    if len(p7.Signers) == 0 {
+        return nil, errors.New("pkcs7/timestamp: message has no signers")
+    }
+
+    timestamps := make([]*Timestamp, 0, len(p7.Signers))
+    for _, signer := range p7.Signers {
+        var token []byte
+        for _, attr := range signer.UnauthenticatedAttributes {
+            if attr.Type.Equal(oidSignatureTimeStampToken) {
+                token = attr.Value.Bytes
+                break
+            }
+        }
+
+        if len(token) == 0 {
+            return nil, errors.New("pkcs7/timestamp: signer has no timestamp token")
+        }
+
+        ts, err := VerifyToken(token, signer.EncryptedDigest, roots)
+        if err != nil {
+            return nil, err
+        }
+
+        timestamps = append(timestamps, ts)
+    }
+
+    return timestamps, nil
+}
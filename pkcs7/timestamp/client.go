@@ -0,0 +1,96 @@
+package timestamp
+
+import (
+    "io"
+    "bytes"
+    "errors"
+    "strconv"
+    "net/http"
+    "encoding/asn1"
+
+    "github.com/deatil/go-cryptobin/pkcs7/sign"
+)
+
+// 响应最大长度
+const maxResponseSize = 1 << 20
+
+// TSA 客户端
+type Client struct {
+    // TSA 地址
+    URL string
+
+    // http 客户端, 为空时使用 http.DefaultClient
+    HTTPClient *http.Client
+
+    // 摘要算法, 默认为 SHA256
+    Hash sign.SignHash
+
+    // 请求的 TSA 策略
+    Policy asn1.ObjectIdentifier
+}
+
+// TSA 客户端
+func NewClient(url string) *Client {
+    return &Client{
+        URL: url,
+    }
+}
+
+// 获取数据的时间戳, 并检测响应和请求是否匹配
+// 令牌签名需要另外使用 Timestamp.Verify 验证
+func (this *Client) Timestamp(data []byte) (*Timestamp, error) {
+    req, err := NewRequest(data, &RequestOptions{
+        Hash:         this.Hash,
+        Policy:       this.Policy,
+        Certificates: true,
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    reqBytes, err := req.Marshal()
+    if err != nil {
+        return nil, err
+    }
+
+    httpClient := this.HTTPClient
+    if httpClient == nil {
+        httpClient = http.DefaultClient
+    }
+
+    resp, err := httpClient.Post(this.URL, "application/timestamp-query", bytes.NewReader(reqBytes))
+    if err != nil {
+        return nil, err
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode != http.StatusOK {
+        return nil, errors.New("pkcs7/timestamp: bad http status " + strconv.Itoa(resp.StatusCode))
+    }
+
+    body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+    if err != nil {
+        return nil, err
+    }
+
+    ts, err := ParseResponse(body)
+    if err != nil {
+        return nil, err
+    }
+
+    if err = ts.VerifyRequest(req); err != nil {
+        return nil, err
+    }
+
+    return ts, nil
+}
+
+// 获取数据的时间戳令牌
+func (this *Client) Token(data []byte) ([]byte, error) {
+    ts, err := this.Timestamp(data)
+    if err != nil {
+        return nil, err
+    }
+
+    return ts.RawToken, nil
+}
@@ -0,0 +1,143 @@
+package timestamp
+
+import (
+    "errors"
+    "math/big"
+    "crypto/rand"
+    "encoding/asn1"
+    "crypto/x509/pkix"
+
+    "github.com/deatil/go-cryptobin/pkcs7/sign"
+)
+
+// 时间戳请求
+type Request struct {
+    // 摘要算法
+    HashAlgorithm asn1.ObjectIdentifier
+
+    // 数据摘要
+    HashedMessage []byte
+
+    // 请求的 TSA 策略, 可为空
+    Policy asn1.ObjectIdentifier
+
+    // 随机数, 可为空
+    Nonce *big.Int
+
+    // 是否需要在令牌中包含 TSA 证书
+    Certificates bool
+
+    // 扩展
+    Extensions []pkix.Extension
+}
+
+// 生成请求数据
+func (this *Request) Marshal() ([]byte, error) {
+    if len(this.HashAlgorithm) == 0 || len(this.HashedMessage) == 0 {
+        return nil, errors.New("pkcs7/timestamp: message imprint is empty")
+    }
+
+    req := timeStampReq{
+        Version: 1,
+        MessageImprint: messageImprint{
+            HashAlgorithm: pkix.AlgorithmIdentifier{
+                Algorithm:  this.HashAlgorithm,
+                Parameters: asn1.NullRawValue,
+            },
+            HashedMessage: this.HashedMessage,
+        },
+        ReqPolicy:  this.Policy,
+        Nonce:      this.Nonce,
+        CertReq:    this.Certificates,
+        Extensions: this.Extensions,
+    }
+
+    return asn1.Marshal(req)
+}
+
+// 请求设置
+type RequestOptions struct {
+    // 摘要算法, 默认为 SHA256
+    Hash sign.SignHash
+
+    // 请求的 TSA 策略
+    Policy asn1.ObjectIdentifier
+
+    // 是否添加随机数, 默认添加
+    NoNonce bool
+
+    // 是否需要在令牌中包含 TSA 证书
+    Certificates bool
+}
+
+// 为数据生成时间戳请求
+func NewRequest(data []byte, opts *RequestOptions) (*Request, error) {
+    if opts == nil {
+        opts = &RequestOptions{}
+    }
+
+    hash := opts.Hash
+    if hash == nil {
+        hash = sign.SignHashWithSHA256
+    }
+
+    req := &Request{
+        HashAlgorithm: hash.OID(),
+        HashedMessage: hash.Sum(data),
+        Policy:        opts.Policy,
+        Certificates:  opts.Certificates,
+    }
+
+    if !opts.NoNonce {
+        nonce, err := randSerial()
+        if err != nil {
+            return nil, err
+        }
+
+        req.Nonce = nonce
+    }
+
+    return req, nil
+}
+
+// 为数据生成时间戳请求数据
+func CreateRequest(data []byte, opts *RequestOptions) ([]byte, error) {
+    req, err := NewRequest(data, opts)
+    if err != nil {
+        return nil, err
+    }
+
+    return req.Marshal()
+}
+
+// 解析时间戳请求
+func ParseRequest(der []byte) (*Request, error) {
+    var req timeStampReq
+    rest, err := asn1.Unmarshal(der, &req)
+    if err != nil {
+        return nil, err
+    }
+    if len(rest) > 0 {
+        return nil, errors.New("pkcs7/timestamp: trailing data in request")
+    }
+
+    if req.Version != 1 {
+        return nil, errors.New("pkcs7/timestamp: unsupported request version")
+    }
+
+    return &Request{
+        HashAlgorithm: req.MessageImprint.HashAlgorithm.Algorithm,
+        HashedMessage: req.MessageImprint.HashedMessage,
+        Policy:        req.ReqPolicy,
+        Nonce:         req.Nonce,
+        Certificates:  req.CertReq,
+        Extensions:    req.Extensions,
+    }, nil
+}
+
+// 随机序列号
+func randSerial() (*big.Int, error) {
+    limit := new(big.Int).Lsh(big.NewInt(1), 128)
+
+    return rand.Int(rand.Reader, limit)
+}
@@ -0,0 +1,269 @@
+package timestamp
+
+import (
+    "fmt"
+    "time"
+    "bytes"
+    "errors"
+    "math/big"
+    "crypto/x509"
+    "encoding/asn1"
+    "crypto/x509/pkix"
+
+    "github.com/deatil/go-cryptobin/pkcs7/sign"
+)
+
+// 时间戳令牌信息
+type Timestamp struct {
+    // 摘要算法
+    HashAlgorithm asn1.ObjectIdentifier
+
+    // 数据摘要
+    HashedMessage []byte
+
+    // 时间
+    Time time.Time
+
+    // 精度
+    Accuracy time.Duration
+
+    // 序列号
+    SerialNumber *big.Int
+
+    // TSA 策略
+    Policy asn1.ObjectIdentifier
+
+    // 是否有序
+    Ordering bool
+
+    // 随机数
+    Nonce *big.Int
+
+    // 扩展
+    Extensions []pkix.Extension
+
+    // 令牌中包含的证书
+    Certificates []*x509.Certificate
+
+    // 原始令牌, 为 SignedData 数据
+    RawToken []byte
+
+    p7 *sign.PKCS7
+}
+
+// 解析时间戳响应, 状态不为 Granted 或者 GrantedWithMods 时返回 *ResponseError
+func ParseResponse(der []byte) (*Timestamp, error) {
+    var resp timeStampResp
+    rest, err := asn1.Unmarshal(der, &resp)
+    if err != nil {
+        return nil, err
+    }
+    if len(rest) > 0 {
+        return nil, errors.New("pkcs7/timestamp: trailing data in response")
+    }
+
+    status := Status(resp.Status.Status)
+    if status != Granted && status != GrantedWithMods {
+        respErr := &ResponseError{
+            Status:      status,
+            FailureInfo: -1,
+        }
+
+        for i := 0; i < resp.Status.FailInfo.BitLength; i++ {
+            if resp.Status.FailInfo.At(i) != 0 {
+                respErr.FailureInfo = FailureInfo(i)
+                break
+            }
+        }
+
+        if len(resp.Status.StatusString) > 0 {
+            respErr.StatusText = string(resp.Status.StatusString[0].Bytes)
+        }
+
+        return nil, respErr
+    }
+
+    if len(resp.TimeStampToken.FullBytes) == 0 {
+        return nil, errors.New("pkcs7/timestamp: response has no token")
+    }
+
+    return ParseToken(resp.TimeStampToken.FullBytes)
+}
+
+// 解析时间戳令牌, 不验证签名
+func ParseToken(token []byte) (*Timestamp, error) {
+    p7, err := sign.Parse(token)
+    if err != nil {
+        return nil, err
+    }
+
+    var contentType asn1.ObjectIdentifier
+    if err := p7.UnmarshalSignedAttribute(oidAttributeContentType, &contentType); err != nil {
+        return nil, err
+    }
+
+    if !contentType.Equal(oidTSTInfo) {
+        return nil, errors.New("pkcs7/timestamp: token content is not TSTInfo")
+    }
+
+    var info tstInfo
+    rest, err := asn1.Unmarshal(p7.Content, &info)
+    if err != nil {
+        return nil, err
+    }
+    if len(rest) > 0 {
+        return nil, errors.New("pkcs7/timestamp: trailing data in TSTInfo")
+    }
+
+    if info.Version != 1 {
+        return nil, errors.New("pkcs7/timestamp: unsupported TSTInfo version")
+    }
+
+    return &Timestamp{
+        HashAlgorithm: info.MessageImprint.HashAlgorithm.Algorithm,
+        HashedMessage: info.MessageImprint.HashedMessage,
+        Time:          info.GenTime,
+        Accuracy:      info.Accuracy.duration(),
+        SerialNumber:  info.SerialNumber,
+        Policy:        info.Policy,
+        Ordering:      info.Ordering,
+        Nonce:         info.Nonce,
+        Extensions:    info.Extensions,
+        Certificates:  p7.Certificates,
+        RawToken:      token,
+        p7:            p7,
+    }, nil
+}
+
+// 验证令牌签名及 TSA 证书链
+// 证书链使用令牌时间验证, TSA 证书需要有 timeStamping 用途
+func (this *Timestamp) Verify(roots *x509.CertPool) error {
+    if this.p7 == nil {
+        return errors.New("pkcs7/timestamp: token is not parsed")
+    }
+
+    if roots == nil {
+        return errors.New("pkcs7/timestamp: roots is empty")
+    }
+
+    if err := this.p7.Verify(); err != nil {
+        return err
+    }
+
+    signer := this.p7.GetOnlySigner()
+    if signer == nil {
+        return errors.New("pkcs7/timestamp: token must have only one signer")
+    }
+
+    if !hasTimeStampingUsage(signer) {
+        return errors.New("pkcs7/timestamp: signer certificate is not for time stamping")
+    }
+
+    intermediates := x509.NewCertPool()
+    for _, cert := range this.Certificates {
+        intermediates.AddCert(cert)
+    }
+
+    _, err := signer.Verify(x509.VerifyOptions{
+        Roots:         roots,
+        Intermediates: intermediates,
+        CurrentTime:   this.Time,
+        KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping},
+    })
+    if err != nil {
+        return fmt.Errorf("pkcs7/timestamp: failed to verify certificate chain: %v", err)
+    }
+
+    return this.checkSigningCertificate(signer)
+}
+
+// 验证令牌是否为数据的时间戳
+func (this *Timestamp) VerifyData(data []byte) error {
+    hash, err := getHash(this.HashAlgorithm)
+    if err != nil {
+        return err
+    }
+
+    if !bytes.Equal(hash.Sum(data), this.HashedMessage) {
+        return errors.New("pkcs7/timestamp: message imprint mismatch")
+    }
+
+    return nil
+}
+
+// 验证令牌是否为请求的响应
+func (this *Timestamp) VerifyRequest(req *Request) error {
+    if !this.HashAlgorithm.Equal(req.HashAlgorithm) ||
+        !bytes.Equal(this.HashedMessage, req.HashedMessage) {
+        return errors.New("pkcs7/timestamp: message imprint mismatch")
+    }
+
+    if req.Nonce != nil {
+        if this.Nonce == nil || this.Nonce.Cmp(req.Nonce) != 0 {
+            return errors.New("pkcs7/timestamp: nonce mismatch")
+        }
+    }
+
+    if len(req.Policy) > 0 && !this.Policy.Equal(req.Policy) {
+        return errors.New("pkcs7/timestamp: policy mismatch")
+    }
+
+    return nil
+}
+
+// 解析并验证数据的时间戳令牌
+func VerifyToken(token []byte, data []byte, roots *x509.CertPool) (*Timestamp, error) {
+    ts, err := ParseToken(token)
+    if err != nil {
+        return nil, err
+    }
+
+    if err = ts.Verify(roots); err != nil {
+        return nil, err
+    }
+
+    if err = ts.VerifyData(data); err != nil {
+        return nil, err
+    }
+
+    return ts, nil
+}
+
+// 检测 signingCertificateV2 属性
+func (this *Timestamp) checkSigningCertificate(signer *x509.Certificate) error {
+    var attr signingCertificateV2
+    if err := this.p7.UnmarshalSignedAttribute(oidSigningCertificateV2, &attr); err != nil {
+        // 属性为可选
+        return nil
+    }
+
+    if len(attr.Certs) == 0 {
+        return errors.New("pkcs7/timestamp: signing certificate attribute is empty")
+    }
+
+    hashOid := sign.SignHashWithSHA256.OID()
+    if len(attr.Certs[0].HashAlgorithm.Algorithm) > 0 {
+        hashOid = attr.Certs[0].HashAlgorithm.Algorithm
+    }
+
+    hash, err := getHash(hashOid)
+    if err != nil {
+        return err
+    }
+
+    if !bytes.Equal(hash.Sum(signer.Raw), attr.Certs[0].CertHash) {
+        return errors.New("pkcs7/timestamp: signing certificate mismatch")
+    }
+
+    return nil
+}
+
+func hasTimeStampingUsage(cert *x509.Certificate) bool {
+    for _, usage := range cert.ExtKeyUsage {
+        if usage == x509.ExtKeyUsageTimeStamping {
+            return true
+        }
+    }
+
+    return false
+}
@@ -0,0 +1,210 @@
+package timestamp
+
+import (
+    "time"
+    "errors"
+    "strconv"
+    "math/big"
+    "encoding/asn1"
+    "crypto/x509/pkix"
+
+    "github.com/deatil/go-cryptobin/pkcs7/sign"
+)
+
+/**
+ * RFC 3161 时间戳协议
+ *
+ * 时间戳令牌为 SignedData, 签名使用 pkcs7/sign 的 KeySign
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+
+var (
+    oidTSTInfo                 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4}
+    oidAttributeContentType    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+    oidSigningCertificateV2    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 47}
+    oidSignatureTimeStampToken = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 14}
+)
+
+// 响应状态
+type Status int
+
+const (
+    Granted                Status = 0
+    GrantedWithMods        Status = 1
+    Rejection              Status = 2
+    Waiting                Status = 3
+    RevocationWarning      Status = 4
+    RevocationNotification Status = 5
+)
+
+func (s Status) String() string {
+    switch s {
+        case Granted:
+            return "granted"
+        case GrantedWithMods:
+            return "granted with modifications"
+        case Rejection:
+            return "rejection"
+        case Waiting:
+            return "waiting"
+        case RevocationWarning:
+            return "revocation warning"
+        case RevocationNotification:
+            return "revocation notification"
+    }
+
+    return "unknown status: " + strconv.Itoa(int(s))
+}
+
+// 失败原因, 为 PKIFailureInfo 的位
+type FailureInfo int
+
+const (
+    BadAlgorithm          FailureInfo = 0
+    BadRequest            FailureInfo = 2
+    BadDataFormat         FailureInfo = 5
+    TimeNotAvailable      FailureInfo = 14
+    UnacceptedPolicy      FailureInfo = 15
+    UnacceptedExtension   FailureInfo = 16
+    AddInfoNotAvailable   FailureInfo = 17
+    SystemFailure         FailureInfo = 25
+)
+
+func (f FailureInfo) String() string {
+    switch f {
+        case BadAlgorithm:
+            return "unrecognized or unsupported algorithm"
+        case BadRequest:
+            return "transaction not permitted or supported"
+        case BadDataFormat:
+            return "the data submitted has the wrong format"
+        case TimeNotAvailable:
+            return "the TSA's time source is not available"
+        case UnacceptedPolicy:
+            return "the requested TSA policy is not supported"
+        case UnacceptedExtension:
+            return "the requested extension is not supported"
+        case AddInfoNotAvailable:
+            return "the additional information requested is not available"
+        case SystemFailure:
+            return "the request cannot be handled due to system failure"
+    }
+
+    return "unknown failure: " + strconv.Itoa(int(f))
+}
+
+// TSA 拒绝请求时返回的错误
+type ResponseError struct {
+    Status      Status
+    FailureInfo FailureInfo
+    StatusText  string
+}
+
+func (e *ResponseError) Error() string {
+    msg := "pkcs7/timestamp: bad response status: " + e.Status.String()
+    if e.FailureInfo >= 0 {
+        msg += " (" + e.FailureInfo.String() + ")"
+    }
+    if e.StatusText != "" {
+        msg += ": " + e.StatusText
+    }
+
+    return msg
+}
+
+// 支持的摘要算法
+var hashs = map[string]sign.SignHash{
+    sign.SignHashWithSHA1.OID().String():     sign.SignHashWithSHA1,
+    sign.SignHashWithSHA224.OID().String():   sign.SignHashWithSHA224,
+    sign.SignHashWithSHA256.OID().String():   sign.SignHashWithSHA256,
+    sign.SignHashWithSHA384.OID().String():   sign.SignHashWithSHA384,
+    sign.SignHashWithSHA512.OID().String():   sign.SignHashWithSHA512,
+    sign.SignHashWithSHA3_256.OID().String(): sign.SignHashWithSHA3_256,
+    sign.SignHashWithSHA3_384.OID().String(): sign.SignHashWithSHA3_384,
+    sign.SignHashWithSHA3_512.OID().String(): sign.SignHashWithSHA3_512,
+    sign.SignHashWithSM3.OID().String():      sign.SignHashWithSM3,
+}
+
+// 添加摘要算法
+func AddHash(hash sign.SignHash) {
+    hashs[hash.OID().String()] = hash
+}
+
+func getHash(oid asn1.ObjectIdentifier) (sign.SignHash, error) {
+    hash, ok := hashs[oid.String()]
+    if !ok {
+        return nil, errors.New("pkcs7/timestamp: unsupported hash algorithm " + oid.String())
+    }
+
+    return hash, nil
+}
+
+type messageImprint struct {
+    HashAlgorithm pkix.AlgorithmIdentifier
+    HashedMessage []byte
+}
+
+type timeStampReq struct {
+    Version        int
+    MessageImprint messageImprint
+    ReqPolicy      asn1.ObjectIdentifier `asn1:"optional"`
+    Nonce          *big.Int              `asn1:"optional"`
+    CertReq        bool                  `asn1:"optional"`
+    Extensions     []pkix.Extension      `asn1:"tag:0,optional"`
+}
+
+type pkiStatusInfo struct {
+    Status       int
+    StatusString []asn1.RawValue `asn1:"optional"`
+    FailInfo     asn1.BitString  `asn1:"optional"`
+}
+
+type timeStampResp struct {
+    Status         pkiStatusInfo
+    TimeStampToken asn1.RawValue `asn1:"optional"`
+}
+
+type accuracy struct {
+    Seconds int `asn1:"optional"`
+    Millis  int `asn1:"tag:0,optional"`
+    Micros  int `asn1:"tag:1,optional"`
+}
+
+type tstInfo struct {
+    Version        int
+    Policy         asn1.ObjectIdentifier
+    MessageImprint messageImprint
+    SerialNumber   *big.Int
+    GenTime        time.Time        `asn1:"generalized"`
+    Accuracy       accuracy         `asn1:"optional"`
+    Ordering       bool             `asn1:"optional"`
+    Nonce          *big.Int         `asn1:"optional"`
+    TSA            asn1.RawValue    `asn1:"tag:0,explicit,optional"`
+    Extensions     []pkix.Extension `asn1:"tag:1,optional"`
+}
+
+type essCertIDv2 struct {
+    HashAlgorithm pkix.AlgorithmIdentifier `asn1:"optional"`
+    CertHash      []byte
+    IssuerSerial  asn1.RawValue `asn1:"optional"`
+}
+
+type signingCertificateV2 struct {
+    Certs []essCertIDv2
+}
+
+func (a accuracy) duration() time.Duration {
+    return time.Duration(a.Seconds) * time.Second +
+        time.Duration(a.Millis) * time.Millisecond +
+        time.Duration(a.Micros) * time.Microsecond
+}
+
+func newAccuracy(d time.Duration) accuracy {
+    return accuracy{
+        Seconds: int(d / time.Second),
+        Millis:  int(d % time.Second / time.Millisecond),
+        Micros:  int(d % time.Millisecond / time.Microsecond),
+    }
+}
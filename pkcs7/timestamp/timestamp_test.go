@@ -0,0 +1,314 @@
+package timestamp
+
+import (
+    "time"
+    "errors"
+    "testing"
+    "math/big"
+    "crypto"
+    "crypto/rsa"
+    "crypto/rand"
+    "crypto/x509"
+    "crypto/ecdsa"
+    "crypto/elliptic"
+    "crypto/x509/pkix"
+    "encoding/asn1"
+    "net/http/httptest"
+
+    "github.com/deatil/go-cryptobin/pkcs7/sign"
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+var testPolicy = asn1.ObjectIdentifier{1, 2, 3, 4, 1}
+
+func newTestCert(t *testing.T, serial int64, name string, pub crypto.PublicKey, parent *x509.Certificate, signer crypto.Signer, isCA bool, usage []x509.ExtKeyUsage) *x509.Certificate {
+    template := &x509.Certificate{
+        SerialNumber:          big.NewInt(serial),
+        Subject:               pkix.Name{CommonName: name},
+        NotBefore:             time.Now().Add(-time.Hour),
+        NotAfter:              time.Now().Add(time.Hour),
+        BasicConstraintsValid: true,
+        IsCA:                  isCA,
+        ExtKeyUsage:           usage,
+    }
+
+    if isCA {
+        template.KeyUsage = x509.KeyUsageCertSign
+    }
+
+    if parent == nil {
+        parent = template
+    }
+
+    der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, signer)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    cert, err := x509.ParseCertificate(der)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return cert
+}
+
+type testTSA struct {
+    root  *x509.Certificate
+    roots *x509.CertPool
+    tsa   *TSA
+}
+
+func newTestTSA(t *testing.T) testTSA {
+    caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    caCert := newTestCert(t, 1, "ca", &caKey.PublicKey, nil, caKey, true, nil)
+
+    tsaKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    tsaCert := newTestCert(t, 2, "tsa", &tsaKey.PublicKey, caCert, caKey, false, []x509.ExtKeyUsage{
+        x509.ExtKeyUsageTimeStamping,
+    })
+
+    tsa, err := NewTSA(tsaCert, tsaKey, sign.KeySignWithEcdsaSHA256, testPolicy)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    tsa.Parents = []*x509.Certificate{caCert}
+    tsa.Accuracy = 1500 * time.Millisecond
+
+    roots := x509.NewCertPool()
+    roots.AddCert(caCert)
+
+    return testTSA{
+        root:  caCert,
+        roots: roots,
+        tsa:   tsa,
+    }
+}
+
+func Test_Token(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+
+    tt := newTestTSA(t)
+
+    data := []byte("firmware image data")
+
+    req, err := NewRequest(data, &RequestOptions{
+        Hash:         sign.SignHashWithSM3,
+        Certificates: true,
+    })
+    assertError(err, "Test_Token-NewRequest")
+
+    reqBytes, err := req.Marshal()
+    assertError(err, "Test_Token-Marshal")
+
+    req2, err := ParseRequest(reqBytes)
+    assertError(err, "Test_Token-ParseRequest")
+    assertEqual(req2, req, "Test_Token-ParseRequest")
+
+    token, err := tt.tsa.CreateToken(req)
+    assertError(err, "Test_Token-CreateToken")
+
+    ts, err := VerifyToken(token, data, tt.roots)
+    assertError(err, "Test_Token-VerifyToken")
+
+    assertEqual(ts.Policy, testPolicy, "Test_Token-Policy")
+    assertEqual(ts.Nonce, req.Nonce, "Test_Token-Nonce")
+    assertEqual(ts.Accuracy, 1500 * time.Millisecond, "Test_Token-Accuracy")
+    assertError(ts.VerifyRequest(req), "Test_Token-VerifyRequest")
+
+    assertNotErrorNil(ts.VerifyData([]byte("other data")), "Test_Token-VerifyData")
+
+    // 不受信任的根证书
+    other := newTestTSA(t)
+    _, err = VerifyToken(token, data, other.roots)
+    assertNotErrorNil(err, "Test_Token-VerifyToken-roots")
+}
+
+func Test_TokenVerify(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    data := []byte("firmware image data")
+
+    t.Run("Roots", func(t *testing.T) {
+        tt := newTestTSA(t)
+
+        req, err := NewRequest(data, &RequestOptions{
+            Certificates: true,
+        })
+        assertError(err, "Test_TokenVerify-NewRequest")
+
+        token, err := tt.tsa.CreateToken(req)
+        assertError(err, "Test_TokenVerify-CreateToken")
+
+        _, err = VerifyToken(token, data, nil)
+        assertNotErrorNil(err, "Test_TokenVerify-VerifyToken-nil")
+    })
+
+    // 证书链使用令牌时间验证
+    t.Run("GenTime", func(t *testing.T) {
+        tt := newTestTSA(t)
+        tt.tsa.Now = func() time.Time {
+            return time.Now().Add(-2 * time.Hour)
+        }
+
+        req, err := NewRequest(data, &RequestOptions{
+            Certificates: true,
+        })
+        assertError(err, "Test_TokenVerify-NewRequest")
+
+        token, err := tt.tsa.CreateToken(req)
+        assertError(err, "Test_TokenVerify-CreateToken")
+
+        _, err = VerifyToken(token, data, tt.roots)
+        assertNotErrorNil(err, "Test_TokenVerify-VerifyToken-genTime")
+    })
+
+    // 中间证书需要允许 timeStamping 用途
+    t.Run("KeyUsage", func(t *testing.T) {
+        caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+        caCert := newTestCert(t, 1, "ca", &caKey.PublicKey, nil, caKey, true, nil)
+
+        subKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+        subCert := newTestCert(t, 2, "sub", &subKey.PublicKey, caCert, caKey, true, []x509.ExtKeyUsage{
+            x509.ExtKeyUsageServerAuth,
+        })
+
+        tsaKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+        tsaCert := newTestCert(t, 3, "tsa", &tsaKey.PublicKey, subCert, subKey, false, []x509.ExtKeyUsage{
+            x509.ExtKeyUsageTimeStamping,
+        })
+
+        tsa, err := NewTSA(tsaCert, tsaKey, sign.KeySignWithEcdsaSHA256, testPolicy)
+        assertError(err, "Test_TokenVerify-NewTSA")
+
+        tsa.Parents = []*x509.Certificate{subCert}
+
+        req, err := NewRequest(data, &RequestOptions{
+            Certificates: true,
+        })
+        assertError(err, "Test_TokenVerify-NewRequest")
+
+        token, err := tsa.CreateToken(req)
+        assertError(err, "Test_TokenVerify-CreateToken")
+
+        roots := x509.NewCertPool()
+        roots.AddCert(caCert)
+
+        _, err = VerifyToken(token, data, roots)
+        assertNotErrorNil(err, "Test_TokenVerify-VerifyToken-usage")
+    })
+}
+
+func Test_Response(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertTrue := cryptobin_test.AssertTrueT(t)
+
+    tt := newTestTSA(t)
+
+    data := []byte("test data")
+
+    reqBytes, err := CreateRequest(data, &RequestOptions{
+        Policy: asn1.ObjectIdentifier{1, 2, 3, 4, 2},
+    })
+    assertError(err, "Test_Response-CreateRequest")
+
+    resp, err := tt.tsa.CreateResponse(reqBytes)
+    assertError(err, "Test_Response-CreateResponse")
+
+    _, err = ParseResponse(resp)
+    assertNotErrorNil(err, "Test_Response-ParseResponse")
+
+    var respErr *ResponseError
+    assertTrue(errors.As(err, &respErr), "Test_Response-ResponseError")
+    if respErr != nil {
+        assertEqual(respErr.Status, Rejection, "Test_Response-Status")
+        assertEqual(respErr.FailureInfo, UnacceptedPolicy, "Test_Response-FailureInfo")
+    }
+
+    resp, err = tt.tsa.CreateResponse([]byte("bad request"))
+    assertError(err, "Test_Response-CreateResponse-bad")
+
+    _, err = ParseResponse(resp)
+    if errors.As(err, &respErr) {
+        assertEqual(respErr.FailureInfo, BadDataFormat, "Test_Response-FailureInfo-bad")
+    } else {
+        t.Error("Test_Response-ResponseError-bad")
+    }
+}
+
+func Test_Client(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    tt := newTestTSA(t)
+
+    server := httptest.NewServer(tt.tsa)
+    defer server.Close()
+
+    data := []byte("test data")
+
+    client := NewClient(server.URL)
+
+    ts, err := client.Timestamp(data)
+    assertError(err, "Test_Client-Timestamp")
+
+    if ts != nil {
+        assertError(ts.Verify(tt.roots), "Test_Client-Verify")
+        assertError(ts.VerifyData(data), "Test_Client-VerifyData")
+    }
+}
+
+func Test_AttachTimestamp(t *testing.T) {
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+    assertEqual := cryptobin_test.AssertEqualT(t)
+
+    tt := newTestTSA(t)
+
+    server := httptest.NewServer(tt.tsa)
+    defer server.Close()
+
+    key, _ := rsa.GenerateKey(rand.Reader, 2048)
+    cert := newTestCert(t, 3, "signer", &key.PublicKey, nil, key, false, nil)
+
+    data := []byte("firmware image data")
+
+    signedData, err := sign.NewSignedData(data)
+    assertError(err, "Test_AttachTimestamp-NewSignedData")
+
+    signedData.SetDigestAlgorithm(sign.SignHashWithSHA256.OID())
+    signedData.SetEncryptionAlgorithm(sign.KeySignWithRsaSHA256.OID())
+
+    err = signedData.AddSigner(cert, key, sign.SignerInfoConfig{})
+    assertError(err, "Test_AttachTimestamp-AddSigner")
+
+    // 没有时间戳
+    unsigned, err := signedData.Finish()
+    assertError(err, "Test_AttachTimestamp-Finish")
+
+    p7, err := sign.Parse(unsigned)
+    assertError(err, "Test_AttachTimestamp-Parse")
+
+    _, err = VerifyAttachedTimestamps(p7, tt.roots)
+    assertNotErrorNil(err, "Test_AttachTimestamp-VerifyAttachedTimestamps-empty")
+
+    err = AttachTimestamp(signedData, NewClient(server.URL).Token)
+    assertError(err, "Test_AttachTimestamp-AttachTimestamp")
+
+    signed, err := signedData.Finish()
+    assertError(err, "Test_AttachTimestamp-Finish")
+
+    p7, err = sign.Parse(signed)
+    assertError(err, "Test_AttachTimestamp-Parse")
+
+    assertError(p7.Verify(), "Test_AttachTimestamp-Verify")
+
+    timestamps, err := VerifyAttachedTimestamps(p7, tt.roots)
+    assertError(err, "Test_AttachTimestamp-VerifyAttachedTimestamps")
+    assertEqual(len(timestamps), 1, "Test_AttachTimestamp-timestamps")
+}
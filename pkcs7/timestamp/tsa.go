@@ -0,0 +1,233 @@
+package timestamp
+
+import (
+    "io"
+    "time"
+    "errors"
+    "net/http"
+    "crypto"
+    "crypto/x509"
+    "encoding/asn1"
+    "crypto/x509/pkix"
+
+    "github.com/deatil/go-cryptobin/pkcs7/sign"
+)
+
+// 请求最大长度
+const maxRequestSize = 1 << 16
+
+// 请求处理失败的错误
+type failureError struct {
+    info FailureInfo
+    msg  string
+}
+
+func (e *failureError) Error() string {
+    return "pkcs7/timestamp: " + e.msg
+}
+
+// 时间戳服务
+type TSA struct {
+    cert    *x509.Certificate
+    key     crypto.PrivateKey
+    keySign sign.KeySign
+    policy  asn1.ObjectIdentifier
+
+    // 证书链, 不包含 TSA 证书
+    Parents []*x509.Certificate
+
+    // 时间精度
+    Accuracy time.Duration
+
+    // 是否有序
+    Ordering bool
+
+    // 当前时间, 为空时使用 time.Now
+    Now func() time.Time
+}
+
+// 时间戳服务
+// cert 为 TSA 证书, 需要有 timeStamping 用途
+// keySign 为签名方式, 自定义的签名方式需要先用 sign.AddKeySign 添加
+// policy 为 TSA 策略
+func NewTSA(cert *x509.Certificate, key crypto.PrivateKey, keySign sign.KeySign, policy asn1.ObjectIdentifier) (*TSA, error) {
+    if cert == nil || key == nil || keySign == nil {
+        return nil, errors.New("pkcs7/timestamp: cert, key or keySign is empty")
+    }
+
+    if !hasTimeStampingUsage(cert) {
+        return nil, errors.New("pkcs7/timestamp: certificate is not for time stamping")
+    }
+
+    if len(policy) == 0 {
+        return nil, errors.New("pkcs7/timestamp: policy is empty")
+    }
+
+    return &TSA{
+        cert:    cert,
+        key:     key,
+        keySign: keySign,
+        policy:  policy,
+    }, nil
+}
+
+// 为请求生成时间戳令牌
+func (this *TSA) CreateToken(req *Request) ([]byte, error) {
+    hash, err := getHash(req.HashAlgorithm)
+    if err != nil {
+        return nil, &failureError{BadAlgorithm, err.Error()}
+    }
+
+    if len(req.HashedMessage) != len(hash.Sum(nil)) {
+        return nil, &failureError{BadDataFormat, "hashed message length error"}
+    }
+
+    if len(req.Policy) > 0 && !req.Policy.Equal(this.policy) {
+        return nil, &failureError{UnacceptedPolicy, "policy is not supported"}
+    }
+
+    if len(req.Extensions) > 0 {
+        return nil, &failureError{UnacceptedExtension, "extensions are not supported"}
+    }
+
+    serial, err := randSerial()
+    if err != nil {
+        return nil, err
+    }
+
+    now := time.Now
+    if this.Now != nil {
+        now = this.Now
+    }
+
+    info := tstInfo{
+        Version: 1,
+        Policy:  this.policy,
+        MessageImprint: messageImprint{
+            HashAlgorithm: pkix.AlgorithmIdentifier{
+                Algorithm:  req.HashAlgorithm,
+                Parameters: asn1.NullRawValue,
+            },
+            HashedMessage: req.HashedMessage,
+        },
+        SerialNumber: serial,
+        GenTime:      now().UTC().Truncate(time.Second),
+        Accuracy:     newAccuracy(this.Accuracy),
+        Ordering:     this.Ordering,
+        Nonce:        req.Nonce,
+    }
+
+    infoBytes, err := asn1.Marshal(info)
+    if err != nil {
+        return nil, err
+    }
+
+    signingCert := signingCertificateV2{
+        Certs: []essCertIDv2{
+            {
+                CertHash: sign.SignHashWithSHA256.Sum(this.cert.Raw),
+            },
+        },
+    }
+
+    signedData, err := sign.NewSignedData(infoBytes)
+    if err != nil {
+        return nil, err
+    }
+
+    signedData.SetContentType(oidTSTInfo)
+    signedData.SetDigestAlgorithm(this.keySign.HashOID())
+    signedData.SetEncryptionAlgorithm(this.keySign.OID())
+
+    err = signedData.AddSignerChain(this.cert, this.key, this.Parents, sign.SignerInfoConfig{
+        ExtraSignedAttributes: []sign.Attribute{
+            {
+                Type:  oidSigningCertificateV2,
+                Value: signingCert,
+            },
+        },
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return signedData.Finish()
+}
+
+// 处理请求数据, 返回时间戳响应数据
+// 请求不被接受时返回拒绝状态的响应
+func (this *TSA) CreateResponse(reqDer []byte) ([]byte, error) {
+    req, err := ParseRequest(reqDer)
+    if err != nil {
+        return marshalFailure(BadDataFormat, err.Error())
+    }
+
+    token, err := this.CreateToken(req)
+    if err != nil {
+        var failErr *failureError
+        if errors.As(err, &failErr) {
+            return marshalFailure(failErr.info, failErr.msg)
+        }
+
+        return marshalFailure(SystemFailure, "")
+    }
+
+    return asn1.Marshal(timeStampResp{
+        Status: pkiStatusInfo{
+            Status: int(Granted),
+        },
+        TimeStampToken: asn1.RawValue{FullBytes: token},
+    })
+}
+
+// http 服务
+func (this *TSA) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodPost {
+        w.WriteHeader(http.StatusMethodNotAllowed)
+        return
+    }
+
+    if r.Header.Get("Content-Type") != "application/timestamp-query" {
+        w.WriteHeader(http.StatusUnsupportedMediaType)
+        return
+    }
+
+    body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize))
+    if err != nil {
+        w.WriteHeader(http.StatusBadRequest)
+        return
+    }
+
+    resp, err := this.CreateResponse(body)
+    if err != nil {
+        w.WriteHeader(http.StatusInternalServerError)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/timestamp-reply")
+    w.Write(resp)
+}
+
+// 拒绝状态的响应
+func marshalFailure(info FailureInfo, text string) ([]byte, error) {
+    failInfo := asn1.BitString{
+        Bytes:     make([]byte, int(info)/8+1),
+        BitLength: int(info) + 1,
+    }
+    failInfo.Bytes[int(info)/8] |= 0x80 >> (uint(info) % 8)
+
+    status := pkiStatusInfo{
+        Status:   int(Rejection),
+        FailInfo: failInfo,
+    }
+
+    if text != "" {
+        status.StatusString = []asn1.RawValue{
+            {Tag: asn1.TagUTF8String, Bytes: []byte(text)},
+        }
+    }
+
+    return asn1.Marshal(timeStampResp{
+        Status: status,
+    })
+}
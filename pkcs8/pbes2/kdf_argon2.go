@@ -0,0 +1,131 @@
+package pbes2
+
+import (
+    "errors"
+    "encoding/asn1"
+
+    "github.com/deatil/go-cryptobin/argon2"
+)
+
+var (
+    oidArgon2i  = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 19722, 3, 2}
+    oidArgon2id = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 19722, 3, 3}
+)
+
+// argon2 数据
+// Argon2-Parameters ::= SEQUENCE {
+//     salt           OCTET STRING,
+//     passes         INTEGER,
+//     parallelism    INTEGER,
+//     memory         INTEGER, -- KiB
+//     outputLen      INTEGER,
+//     secret         OCTET STRING OPTIONAL,
+//     associatedData OCTET STRING OPTIONAL }
+type argon2Params struct {
+    Salt           []byte
+    Passes         int
+    Parallelism    int
+    Memory         int
+    OutputLen      int
+    Secret         []byte `asn1:"optional"`
+    AssociatedData []byte `asn1:"optional"`
+}
+
+func (this argon2Params) deriveKey(typ string, password []byte, size int) (key []byte, err error) {
+    if len(this.Secret) > 0 || len(this.AssociatedData) > 0 {
+        return nil, errors.New("pkcs8: argon2 secret and associated data are not supported")
+    }
+
+    if this.Passes < 1 || this.Memory < 1 ||
+        this.Parallelism < 1 || this.Parallelism > 255 {
+        return nil, errors.New("pkcs8: invalid argon2 parameters")
+    }
+
+    // 如果有自定义长度，使用自定义长度
+    if this.OutputLen > 0 {
+        size = this.OutputLen
+    }
+
+    return argon2.Key(
+        typ, password, this.Salt,
+        uint32(this.Passes), uint32(this.Memory),
+        uint8(this.Parallelism), uint32(size),
+    )
+}
+
+// argon2i 数据
+type argon2iParams argon2Params
+
+func (this argon2iParams) DeriveKey(password []byte, size int) (key []byte, err error) {
+    return argon2Params(this).deriveKey("argon2i", password, size)
+}
+
+// argon2id 数据
+type argon2idParams argon2Params
+
+func (this argon2idParams) DeriveKey(password []byte, size int) (key []byte, err error) {
+    return argon2Params(this).deriveKey("argon2id", password, size)
+}
+
+// Argon2Opts 设置
+type Argon2Opts struct {
+    // 类型, 可用 [argon2id | argon2i], 为空时使用 argon2id
+    Type        string
+    SaltSize    int
+    Passes      int
+    Parallelism int
+    // 内存大小, 单位 KiB
+    Memory      int
+}
+
+func (this Argon2Opts) GetSaltSize() int {
+    return this.SaltSize
+}
+
+func (this Argon2Opts) OID() asn1.ObjectIdentifier {
+    if this.Type == "argon2i" {
+        return oidArgon2i
+    }
+
+    return oidArgon2id
+}
+
+// outputLen 为必须数据, 总是设置
+func (this Argon2Opts) WithHasKeyLength(hasKeyLength bool) KDFOpts {
+    return this
+}
+
+func (this Argon2Opts) DeriveKey(password, salt []byte, size int) (key []byte, params KDFParameters, err error) {
+    parameters := argon2Params{
+        Salt:        salt,
+        Passes:      this.Passes,
+        Parallelism: this.Parallelism,
+        Memory:      this.Memory,
+        OutputLen:   size,
+    }
+
+    if this.Type == "argon2i" {
+        key, err = parameters.deriveKey("argon2i", password, size)
+        if err != nil {
+            return nil, nil, err
+        }
+
+        return key, argon2iParams(parameters), nil
+    }
+
+    key, err = parameters.deriveKey("argon2id", password, size)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    return key, argon2idParams(parameters), nil
+}
+
+func init() {
+    AddKDF(oidArgon2i, func() KDFParameters {
+        return new(argon2iParams)
+    })
+    AddKDF(oidArgon2id, func() KDFParameters {
+        return new(argon2idParams)
+    })
+}
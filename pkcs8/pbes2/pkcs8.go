@@ -37,6 +37,15 @@ var DefaultScryptOpts = ScryptOpts{
     ParallelizationParameter: 1,
 }
 
+// 默认配置 Argon2
+var DefaultArgon2Opts = Argon2Opts{
+    Type:        "argon2id",
+    SaltSize:    16,
+    Passes:      3,
+    Parallelism: 4,
+    Memory:      64 * 1024,
+}
+
 // 默认配置
 var DefaultOpts = Opts{
     Cipher:  AES256CBC,
@@ -71,6 +71,45 @@ var (
     SM4CFB8 = pbes2.SM4CFB8
     SM4GCM  = pbes2.SM4GCM
     SM4CCM  = pbes2.SM4CCM
+
+    Aria128ECB = pbes2.Aria128ECB
+    Aria128CBC = pbes2.Aria128CBC
+    Aria128OFB = pbes2.Aria128OFB
+    Aria128CFB = pbes2.Aria128CFB
+    Aria128GCM = pbes2.Aria128GCM
+    Aria128CCM = pbes2.Aria128CCM
+
+    Aria192ECB = pbes2.Aria192ECB
+    Aria192CBC = pbes2.Aria192CBC
+    Aria192OFB = pbes2.Aria192OFB
+    Aria192CFB = pbes2.Aria192CFB
+    Aria192GCM = pbes2.Aria192GCM
+    Aria192CCM = pbes2.Aria192CCM
+
+    Aria256ECB = pbes2.Aria256ECB
+    Aria256CBC = pbes2.Aria256CBC
+    Aria256OFB = pbes2.Aria256OFB
+    Aria256CFB = pbes2.Aria256CFB
+    Aria256GCM = pbes2.Aria256GCM
+    Aria256CCM = pbes2.Aria256CCM
+
+    Camellia128ECB = pbes2.Camellia128ECB
+    Camellia128CBC = pbes2.Camellia128CBC
+    Camellia128OFB = pbes2.Camellia128OFB
+    Camellia128CFB = pbes2.Camellia128CFB
+
+    Camellia192ECB = pbes2.Camellia192ECB
+    Camellia192CBC = pbes2.Camellia192CBC
+    Camellia192OFB = pbes2.Camellia192OFB
+    Camellia192CFB = pbes2.Camellia192CFB
+
+    Camellia256ECB = pbes2.Camellia256ECB
+    Camellia256CBC = pbes2.Camellia256CBC
+    Camellia256OFB = pbes2.Camellia256OFB
+    Camellia256CFB = pbes2.Camellia256CFB
+
+    GostKuznyechikCTRACPKM = pbes2.GostKuznyechikCTRACPKM
+    GostMagmaCTRACPKM      = pbes2.GostMagmaCTRACPKM
 )
 
 type (
@@ -78,6 +117,7 @@ type (
     Opts       = pbes2.Opts
     PBKDF2Opts = pbes2.PBKDF2Opts
     ScryptOpts = pbes2.ScryptOpts
+    Argon2Opts = pbes2.Argon2Opts
 )
 
 var (
@@ -93,6 +133,9 @@ var DefaultPBKDF2Opts = pbes2.DefaultPBKDF2Opts
 // 默认配置 Scrypt
 var DefaultScryptOpts = pbes2.DefaultScryptOpts
 
+// 默认配置 Argon2
+var DefaultArgon2Opts = pbes2.DefaultArgon2Opts
+
 // 默认配置
 var DefaultOpts = pbes2.DefaultOpts
 
@@ -0,0 +1,81 @@
+package agent
+
+import (
+    "errors"
+
+    ssh_agent "golang.org/x/crypto/ssh/agent"
+)
+
+/**
+ * ssh-agent 协议 (draft-miller-ssh-agent)
+ *
+ * 支持 SM2 密钥, 接口和 golang.org/x/crypto/ssh/agent 保持一致
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+
+type (
+    // agent 接口
+    Agent = ssh_agent.ExtendedAgent
+
+    // 添加的密钥
+    AddedKey = ssh_agent.AddedKey
+
+    // 密钥信息
+    Key = ssh_agent.Key
+
+    // 签名标识
+    SignatureFlags = ssh_agent.SignatureFlags
+)
+
+const (
+    SignatureFlagRsaSha256 = ssh_agent.SignatureFlagRsaSha256
+    SignatureFlagRsaSha512 = ssh_agent.SignatureFlagRsaSha512
+)
+
+// 消息类型
+const (
+    agentFailure = 5
+    agentSuccess = 6
+
+    agentRequestIdentities = 11
+    agentIdentitiesAnswer  = 12
+    agentSignRequest       = 13
+    agentSignResponse      = 14
+
+    agentAddIdentity         = 17
+    agentRemoveIdentity      = 18
+    agentRemoveAllIdentities = 19
+    agentAddIDConstrained    = 25
+
+    agentLock   = 22
+    agentUnlock = 23
+
+    agentExtension        = 27
+    agentExtensionFailure = 28
+)
+
+// 密钥约束
+const (
+    agentConstrainLifetime  = 1
+    agentConstrainConfirm   = 2
+    agentConstrainExtension = 255
+)
+
+// 消息最大长度, 和 OpenSSH 保持一致
+const maxAgentResponseBytes = 256 * 1024
+
+var (
+    // agent 已锁定
+    ErrLocked = errors.New("agent: locked")
+
+    // 密钥不存在
+    ErrKeyNotFound = errors.New("agent: key not found")
+
+    // 使用密钥未确认
+    ErrNotConfirmed = errors.New("agent: key use not confirmed")
+
+    // 不支持的扩展
+    ErrExtensionUnsupported = ssh_agent.ErrExtensionUnsupported
+)
@@ -0,0 +1,294 @@
+package agent
+
+import (
+    "net"
+    "time"
+    "testing"
+    "path/filepath"
+    "crypto/rsa"
+    "crypto/rand"
+    "crypto/ecdsa"
+    "crypto/ed25519"
+    "crypto/elliptic"
+
+    "golang.org/x/crypto/ssh"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+    ssh_agent "golang.org/x/crypto/ssh/agent"
+    cryptobin_ssh "github.com/deatil/go-cryptobin/ssh"
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+func testClient(t *testing.T, keyring Agent) Agent {
+    c1, c2 := net.Pipe()
+    t.Cleanup(func() {
+        c1.Close()
+        c2.Close()
+    })
+
+    go ServeAgent(keyring, c2)
+
+    return NewClient(c1)
+}
+
+func testKeys() map[string]any {
+    rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+    ecdsaKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+    _, eddsaKey, _ := ed25519.GenerateKey(rand.Reader)
+    sm2Key, _ := sm2.GenerateKey(rand.Reader)
+
+    return map[string]any{
+        "RSA":     rsaKey,
+        "ECDSA":   ecdsaKey,
+        "ED25519": eddsaKey,
+        "SM2":     sm2Key,
+    }
+}
+
+func Test_Agent(t *testing.T) {
+    for name, key := range testKeys() {
+        t.Run(name, func(t *testing.T) {
+            assertEqual := cryptobin_test.AssertEqualT(t)
+            assertError := cryptobin_test.AssertErrorT(t)
+            assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+            client := testClient(t, NewKeyring())
+
+            err := client.Add(AddedKey{
+                PrivateKey: key,
+                Comment:    "test-" + name,
+            })
+            assertError(err, "Add")
+
+            keys, err := client.List()
+            assertError(err, "List")
+
+            signer, _ := cryptobin_ssh.NewSignerFromKey(key)
+            pub := signer.PublicKey()
+
+            assertEqual(len(keys), 1, "List-len")
+            assertEqual(keys[0].Format, pub.Type(), "List-Format")
+            assertEqual(keys[0].Blob, pub.Marshal(), "List-Blob")
+            assertEqual(keys[0].Comment, "test-" + name, "List-Comment")
+
+            data := []byte("test-agent-data")
+
+            sig, err := client.Sign(pub, data)
+            assertError(err, "Sign")
+
+            err = pub.Verify(data, sig)
+            assertError(err, "Verify")
+
+            signers, err := client.Signers()
+            assertError(err, "Signers")
+            assertEqual(len(signers), 1, "Signers-len")
+
+            sig, err = signers[0].Sign(rand.Reader, data)
+            assertError(err, "Signers-Sign")
+
+            err = pub.Verify(data, sig)
+            assertError(err, "Signers-Verify")
+
+            err = client.Remove(pub)
+            assertError(err, "Remove")
+
+            _, err = client.Sign(pub, data)
+            assertNotErrorNil(err, "Sign-removed")
+
+            keys, _ = client.List()
+            assertEqual(len(keys), 0, "List-removed")
+        })
+    }
+}
+
+func Test_AgentSignWithFlags(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    key, _ := rsa.GenerateKey(rand.Reader, 2048)
+    pub, _ := ssh.NewPublicKey(&key.PublicKey)
+
+    client := testClient(t, NewKeyring())
+
+    err := client.Add(AddedKey{PrivateKey: key})
+    assertError(err, "Test_AgentSignWithFlags-Add")
+
+    data := []byte("test-agent-data")
+
+    sig, err := client.SignWithFlags(pub, data, SignatureFlagRsaSha512)
+    assertError(err, "Test_AgentSignWithFlags")
+    assertEqual(sig.Format, ssh.KeyAlgoRSASHA512, "Test_AgentSignWithFlags-Format")
+
+    err = pub.Verify(data, sig)
+    assertError(err, "Test_AgentSignWithFlags-Verify")
+
+    signers, _ := client.Signers()
+    sig, err = signers[0].(ssh.AlgorithmSigner).SignWithAlgorithm(rand.Reader, data, ssh.KeyAlgoRSASHA256)
+    assertError(err, "Test_AgentSignWithFlags-SignWithAlgorithm")
+    assertEqual(sig.Format, ssh.KeyAlgoRSASHA256, "Test_AgentSignWithFlags-SignWithAlgorithm-Format")
+}
+
+func Test_AgentOpenSSHKey(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    sm2Key, _ := sm2.GenerateKey(rand.Reader)
+
+    block, err := cryptobin_ssh.MarshalOpenSSHPrivateKeyWithPassword(rand.Reader, sm2Key, "sm2-key", []byte("pass"))
+    assertError(err, "Test_AgentOpenSSHKey-Marshal")
+
+    key, comment, err := cryptobin_ssh.ParseOpenSSHPrivateKeyWithPassword(block.Bytes, []byte("pass"))
+    assertError(err, "Test_AgentOpenSSHKey-Parse")
+
+    client := testClient(t, NewKeyring())
+
+    err = client.Add(AddedKey{
+        PrivateKey: key,
+        Comment:    comment,
+    })
+    assertError(err, "Test_AgentOpenSSHKey-Add")
+
+    keys, _ := client.List()
+    assertEqual(len(keys), 1, "Test_AgentOpenSSHKey-List")
+    assertEqual(keys[0].Format, cryptobin_ssh.KeyAlgoSM2, "Test_AgentOpenSSHKey-Format")
+    assertEqual(keys[0].Comment, "sm2-key", "Test_AgentOpenSSHKey-Comment")
+
+    pub := cryptobin_ssh.NewSM2PrivateKey(sm2Key).PublicKey()
+    data := []byte("test-agent-data")
+
+    sig, err := client.Sign(pub, data)
+    assertError(err, "Test_AgentOpenSSHKey-Sign")
+
+    err = pub.Verify(data, sig)
+    assertError(err, "Test_AgentOpenSSHKey-Verify")
+}
+
+func Test_AgentLock(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    _, key, _ := ed25519.GenerateKey(rand.Reader)
+    signer, _ := cryptobin_ssh.NewSignerFromKey(key)
+
+    client := testClient(t, NewKeyring())
+
+    err := client.Add(AddedKey{PrivateKey: key})
+    assertError(err, "Test_AgentLock-Add")
+
+    err = client.Lock([]byte("pass"))
+    assertError(err, "Test_AgentLock-Lock")
+
+    keys, err := client.List()
+    assertError(err, "Test_AgentLock-List")
+    assertEqual(len(keys), 0, "Test_AgentLock-List-len")
+
+    _, err = client.Sign(signer.PublicKey(), []byte("data"))
+    assertNotErrorNil(err, "Test_AgentLock-Sign")
+
+    err = client.Unlock([]byte("bad"))
+    assertNotErrorNil(err, "Test_AgentLock-Unlock-bad")
+
+    err = client.Unlock([]byte("pass"))
+    assertError(err, "Test_AgentLock-Unlock")
+
+    _, err = client.Sign(signer.PublicKey(), []byte("data"))
+    assertError(err, "Test_AgentLock-Sign-unlocked")
+
+    err = client.RemoveAll()
+    assertError(err, "Test_AgentLock-RemoveAll")
+
+    keys, _ = client.List()
+    assertEqual(len(keys), 0, "Test_AgentLock-RemoveAll-List")
+}
+
+func Test_AgentConstraints(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    _, key, _ := ed25519.GenerateKey(rand.Reader)
+    signer, _ := cryptobin_ssh.NewSignerFromKey(key)
+
+    confirmed := false
+    keyring := NewKeyring(KeyringOpts{
+        Confirm: func(key ssh.PublicKey, comment string) bool {
+            return confirmed
+        },
+    })
+
+    client := testClient(t, keyring)
+
+    err := client.Add(AddedKey{
+        PrivateKey:       key,
+        LifetimeSecs:     60,
+        ConfirmBeforeUse: true,
+    })
+    assertError(err, "Test_AgentConstraints-Add")
+
+    _, err = client.Sign(signer.PublicKey(), []byte("data"))
+    assertNotErrorNil(err, "Test_AgentConstraints-Sign-not-confirmed")
+
+    confirmed = true
+    _, err = client.Sign(signer.PublicKey(), []byte("data"))
+    assertError(err, "Test_AgentConstraints-Sign-confirmed")
+
+    assertEqual(keyring.keys[0].expire.IsZero(), false, "Test_AgentConstraints-expire")
+
+    // 过期
+    keyring.keys[0].expire = time.Now().Add(-time.Second)
+
+    keys, _ := client.List()
+    assertEqual(len(keys), 0, "Test_AgentConstraints-expired")
+
+    // 未设置确认方式
+    client2 := testClient(t, NewKeyring())
+    err = client2.Add(AddedKey{
+        PrivateKey:       key,
+        ConfirmBeforeUse: true,
+    })
+    assertNotErrorNil(err, "Test_AgentConstraints-no-confirm")
+}
+
+func Test_AgentUnixSocket(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+
+    l, err := net.Listen("unix", filepath.Join(t.TempDir(), "agent.sock"))
+    if err != nil {
+        t.Skip("unix socket is not supported: " + err.Error())
+    }
+    defer l.Close()
+
+    go Serve(l, NewKeyring())
+
+    conn, err := net.Dial("unix", l.Addr().String())
+    assertError(err, "Test_AgentUnixSocket-Dial")
+    defer conn.Close()
+
+    // golang.org/x/crypto/ssh/agent 客户端
+    client := ssh_agent.NewClient(conn)
+
+    key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    pub, _ := ssh.NewPublicKey(&key.PublicKey)
+
+    err = client.Add(ssh_agent.AddedKey{
+        PrivateKey:   key,
+        Comment:      "ecdsa",
+        LifetimeSecs: 60,
+    })
+    assertError(err, "Test_AgentUnixSocket-Add")
+
+    keys, err := client.List()
+    assertError(err, "Test_AgentUnixSocket-List")
+    assertEqual(len(keys), 1, "Test_AgentUnixSocket-List-len")
+    assertEqual(keys[0].Comment, "ecdsa", "Test_AgentUnixSocket-List-Comment")
+
+    data := []byte("test-agent-data")
+
+    sig, err := client.Sign(pub, data)
+    assertError(err, "Test_AgentUnixSocket-Sign")
+
+    err = pub.Verify(data, sig)
+    assertError(err, "Test_AgentUnixSocket-Verify")
+}
@@ -0,0 +1,280 @@
+package agent
+
+import (
+    "io"
+    "fmt"
+    "sync"
+    "errors"
+    "encoding/binary"
+
+    "golang.org/x/crypto/ssh"
+
+    cryptobin_ssh "github.com/deatil/go-cryptobin/ssh"
+)
+
+// ssh-agent 客户端
+type client struct {
+    mu   sync.Mutex
+    conn io.ReadWriter
+}
+
+// 生成客户端, conn 可以为 unix socket 或者 net.Pipe 连接
+func NewClient(conn io.ReadWriter) Agent {
+    return &client{
+        conn: conn,
+    }
+}
+
+// 发送请求并读取返回数据
+func (this *client) call(req []byte) ([]byte, error) {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    msg := make([]byte, 4+len(req))
+    binary.BigEndian.PutUint32(msg, uint32(len(req)))
+    copy(msg[4:], req)
+
+    if _, err := this.conn.Write(msg); err != nil {
+        return nil, err
+    }
+
+    var length [4]byte
+    if _, err := io.ReadFull(this.conn, length[:]); err != nil {
+        return nil, err
+    }
+
+    l := binary.BigEndian.Uint32(length[:])
+    if l == 0 {
+        return nil, errors.New("agent: response size is 0")
+    }
+
+    if l > maxAgentResponseBytes {
+        return nil, fmt.Errorf("agent: response too large: %d", l)
+    }
+
+    res := make([]byte, l)
+    if _, err := io.ReadFull(this.conn, res); err != nil {
+        return nil, err
+    }
+
+    return res, nil
+}
+
+// 请求只返回成功或者失败
+func (this *client) simpleCall(req []byte) error {
+    res, err := this.call(req)
+    if err != nil {
+        return err
+    }
+
+    switch res[0] {
+        case agentSuccess:
+            return nil
+        case agentFailure:
+            return errors.New("agent: failure")
+    }
+
+    return fmt.Errorf("agent: unexpected response type %d", res[0])
+}
+
+// 列出密钥
+func (this *client) List() ([]*Key, error) {
+    res, err := this.call([]byte{agentRequestIdentities})
+    if err != nil {
+        return nil, err
+    }
+
+    if res[0] == agentFailure {
+        return nil, errors.New("agent: failed to list keys")
+    }
+
+    var msg identitiesAnswerMsg
+    if err := ssh.Unmarshal(res, &msg); err != nil {
+        return nil, err
+    }
+
+    keys := make([]*Key, 0, msg.NumKeys)
+
+    data := msg.Keys
+    for i := uint32(0); i < msg.NumKeys; i++ {
+        var blob, comment []byte
+        var ok bool
+
+        if blob, data, ok = parseString(data); !ok {
+            return nil, errors.New("agent: short read")
+        }
+
+        if comment, data, ok = parseString(data); !ok {
+            return nil, errors.New("agent: short read")
+        }
+
+        format, _, ok := parseString(blob)
+        if !ok {
+            return nil, errors.New("agent: short read")
+        }
+
+        keys = append(keys, &Key{
+            Format:  string(format),
+            Blob:    blob,
+            Comment: string(comment),
+        })
+    }
+
+    return keys, nil
+}
+
+// 签名
+func (this *client) Sign(key ssh.PublicKey, data []byte) (*ssh.Signature, error) {
+    return this.SignWithFlags(key, data, 0)
+}
+
+// 签名, RSA 密钥可以使用 SignatureFlagRsaSha256 及 SignatureFlagRsaSha512
+func (this *client) SignWithFlags(key ssh.PublicKey, data []byte, flags SignatureFlags) (*ssh.Signature, error) {
+    res, err := this.call(ssh.Marshal(signRequestMsg{
+        KeyBlob: key.Marshal(),
+        Data:    data,
+        Flags:   uint32(flags),
+    }))
+    if err != nil {
+        return nil, err
+    }
+
+    if res[0] == agentFailure {
+        return nil, errors.New("agent: failed to sign challenge")
+    }
+
+    var msg signResponseMsg
+    if err := ssh.Unmarshal(res, &msg); err != nil {
+        return nil, err
+    }
+
+    sig := new(ssh.Signature)
+    if err := ssh.Unmarshal(msg.SigBlob, sig); err != nil {
+        return nil, err
+    }
+
+    return sig, nil
+}
+
+// 添加密钥, 私钥可用 [RSA | ECDSA | ED25519 | SM2]
+// 私钥数据使用 ssh.AddKey 添加的编码方式, 不支持证书
+func (this *client) Add(key AddedKey) error {
+    if key.Certificate != nil {
+        return errors.New("agent: adding certificates is not supported")
+    }
+
+    newKey, err := cryptobin_ssh.ParseKeytype(cryptobin_ssh.GetStructName(key.PrivateKey))
+    if err != nil {
+        return err
+    }
+
+    keyType, _, rest, err := newKey.Marshal(key.PrivateKey, key.Comment)
+    if err != nil {
+        return err
+    }
+
+    var constraints []byte
+
+    if key.LifetimeSecs > 0 {
+        constraints = append(constraints, agentConstrainLifetime)
+        constraints = binary.BigEndian.AppendUint32(constraints, key.LifetimeSecs)
+    }
+
+    if key.ConfirmBeforeUse {
+        constraints = append(constraints, agentConstrainConfirm)
+    }
+
+    for _, ext := range key.ConstraintExtensions {
+        constraints = append(constraints, agentConstrainExtension)
+        constraints = append(constraints, ssh.Marshal(struct {
+            Name    string
+            Details []byte
+        }{ext.ExtensionName, ext.ExtensionDetails})...)
+    }
+
+    msgType := byte(agentAddIdentity)
+    if len(constraints) > 0 {
+        msgType = agentAddIDConstrained
+    }
+
+    req := []byte{msgType}
+    req = append(req, ssh.Marshal(struct {
+        KeyType string
+    }{keyType})...)
+    req = append(req, rest...)
+    req = append(req, constraints...)
+
+    return this.simpleCall(req)
+}
+
+// 移除密钥
+func (this *client) Remove(key ssh.PublicKey) error {
+    req := append([]byte{agentRemoveIdentity}, ssh.Marshal(keyBlobMsg{
+        KeyBlob: key.Marshal(),
+    })...)
+
+    return this.simpleCall(req)
+}
+
+// 移除全部密钥
+func (this *client) RemoveAll() error {
+    return this.simpleCall([]byte{agentRemoveAllIdentities})
+}
+
+// 锁定
+func (this *client) Lock(passphrase []byte) error {
+    req := append([]byte{agentLock}, ssh.Marshal(passphraseMsg{
+        Passphrase: passphrase,
+    })...)
+
+    return this.simpleCall(req)
+}
+
+// 解锁
+func (this *client) Unlock(passphrase []byte) error {
+    req := append([]byte{agentUnlock}, ssh.Marshal(passphraseMsg{
+        Passphrase: passphrase,
+    })...)
+
+    return this.simpleCall(req)
+}
+
+// 返回全部密钥的 ssh.Signer, 签名时请求 agent
+func (this *client) Signers() ([]ssh.Signer, error) {
+    keys, err := this.List()
+    if err != nil {
+        return nil, err
+    }
+
+    signers := make([]ssh.Signer, 0, len(keys))
+    for _, k := range keys {
+        pub, err := cryptobin_ssh.ParsePublicKey(k.Blob)
+        if err != nil {
+            return nil, err
+        }
+
+        signers = append(signers, &agentSigner{this, pub})
+    }
+
+    return signers, nil
+}
+
+// 扩展, 返回的数据包括消息类型
+func (this *client) Extension(extensionType string, contents []byte) ([]byte, error) {
+    res, err := this.call(ssh.Marshal(extensionMsg{
+        ExtensionType: extensionType,
+        Contents:      contents,
+    }))
+    if err != nil {
+        return nil, err
+    }
+
+    switch res[0] {
+        case agentFailure:
+            return nil, ErrExtensionUnsupported
+        case agentExtensionFailure:
+            return nil, errors.New("agent: extension failure")
+    }
+
+    return res, nil
+}
@@ -0,0 +1,339 @@
+package agent
+
+import (
+    "io"
+    "sync"
+    "time"
+    "bytes"
+    "errors"
+    "crypto/rand"
+    "crypto/subtle"
+
+    "golang.org/x/crypto/ssh"
+
+    cryptobin_ssh "github.com/deatil/go-cryptobin/ssh"
+)
+
+// 确认使用密钥, 返回 false 时拒绝签名
+type ConfirmFunc func(key ssh.PublicKey, comment string) bool
+
+// 配置
+type KeyringOpts struct {
+    // 使用 ConfirmBeforeUse 密钥签名前调用, 为空时不能添加 ConfirmBeforeUse 密钥
+    Confirm ConfirmFunc
+
+    // 随机数, 为空时使用 crypto/rand
+    Rand io.Reader
+}
+
+type keyringKey struct {
+    signer  ssh.Signer
+    comment string
+
+    // 过期时间, 为空时不过期
+    expire time.Time
+
+    // 签名前需要确认
+    confirm bool
+}
+
+// 内存密钥列表
+type Keyring struct {
+    mu   sync.Mutex
+    keys []*keyringKey
+
+    locked     bool
+    passphrase []byte
+
+    opts KeyringOpts
+}
+
+// 生成内存密钥列表
+func NewKeyring(opts ...KeyringOpts) *Keyring {
+    k := &Keyring{}
+
+    if len(opts) > 0 {
+        k.opts = opts[0]
+    }
+
+    if k.opts.Rand == nil {
+        k.opts.Rand = rand.Reader
+    }
+
+    return k
+}
+
+// 列出密钥, 锁定时返回空列表
+func (this *Keyring) List() ([]*Key, error) {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    if this.locked {
+        return nil, nil
+    }
+
+    this.expireKeysLocked()
+
+    ids := make([]*Key, 0, len(this.keys))
+    for _, k := range this.keys {
+        pub := k.signer.PublicKey()
+
+        ids = append(ids, &Key{
+            Format:  pub.Type(),
+            Blob:    pub.Marshal(),
+            Comment: k.comment,
+        })
+    }
+
+    return ids, nil
+}
+
+// 添加密钥, 私钥可用 [RSA | ECDSA | ED25519 | SM2]
+func (this *Keyring) Add(key AddedKey) error {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    if this.locked {
+        return ErrLocked
+    }
+
+    if key.ConfirmBeforeUse && this.opts.Confirm == nil {
+        return errors.New("agent: confirm before use is not supported")
+    }
+
+    if len(key.ConstraintExtensions) > 0 {
+        return errors.New("agent: constraint extensions are not supported")
+    }
+
+    signer, err := cryptobin_ssh.NewSignerFromKey(key.PrivateKey)
+    if err != nil {
+        return err
+    }
+
+    if cert := key.Certificate; cert != nil {
+        signer, err = ssh.NewCertSigner(cert, signer)
+        if err != nil {
+            return err
+        }
+    }
+
+    k := &keyringKey{
+        signer:  signer,
+        comment: key.Comment,
+        confirm: key.ConfirmBeforeUse,
+    }
+
+    if key.LifetimeSecs > 0 {
+        k.expire = time.Now().Add(time.Duration(key.LifetimeSecs) * time.Second)
+    }
+
+    // 相同的密钥替换已有数据
+    blob := signer.PublicKey().Marshal()
+    for i, old := range this.keys {
+        if bytes.Equal(old.signer.PublicKey().Marshal(), blob) {
+            this.keys[i] = k
+            return nil
+        }
+    }
+
+    this.keys = append(this.keys, k)
+
+    return nil
+}
+
+// 移除密钥
+func (this *Keyring) Remove(key ssh.PublicKey) error {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    if this.locked {
+        return ErrLocked
+    }
+
+    blob := key.Marshal()
+    for i, k := range this.keys {
+        if bytes.Equal(k.signer.PublicKey().Marshal(), blob) {
+            this.keys = append(this.keys[:i], this.keys[i+1:]...)
+            return nil
+        }
+    }
+
+    return ErrKeyNotFound
+}
+
+// 移除全部密钥
+func (this *Keyring) RemoveAll() error {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    if this.locked {
+        return ErrLocked
+    }
+
+    this.keys = nil
+
+    return nil
+}
+
+// 锁定
+func (this *Keyring) Lock(passphrase []byte) error {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    if this.locked {
+        return ErrLocked
+    }
+
+    this.locked = true
+    this.passphrase = append([]byte(nil), passphrase...)
+
+    return nil
+}
+
+// 解锁
+func (this *Keyring) Unlock(passphrase []byte) error {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    if !this.locked {
+        return errors.New("agent: not locked")
+    }
+
+    if subtle.ConstantTimeCompare(passphrase, this.passphrase) != 1 {
+        return errors.New("agent: incorrect passphrase")
+    }
+
+    this.locked = false
+    this.passphrase = nil
+
+    return nil
+}
+
+// 签名
+func (this *Keyring) Sign(key ssh.PublicKey, data []byte) (*ssh.Signature, error) {
+    return this.SignWithFlags(key, data, 0)
+}
+
+// 签名, RSA 密钥可以使用 SignatureFlagRsaSha256 及 SignatureFlagRsaSha512
+func (this *Keyring) SignWithFlags(key ssh.PublicKey, data []byte, flags SignatureFlags) (*ssh.Signature, error) {
+    k, err := this.findKey(key)
+    if err != nil {
+        return nil, err
+    }
+
+    // 确认时不持有锁
+    if k.confirm && !this.opts.Confirm(k.signer.PublicKey(), k.comment) {
+        return nil, ErrNotConfirmed
+    }
+
+    var algorithm string
+    switch {
+        case flags&SignatureFlagRsaSha512 != 0:
+            algorithm = ssh.KeyAlgoRSASHA512
+        case flags&SignatureFlagRsaSha256 != 0:
+            algorithm = ssh.KeyAlgoRSASHA256
+        default:
+            return k.signer.Sign(this.opts.Rand, data)
+    }
+
+    algorithmSigner, ok := k.signer.(ssh.AlgorithmSigner)
+    if !ok {
+        return nil, errors.New("agent: signature flags are not supported by the key")
+    }
+
+    return algorithmSigner.SignWithAlgorithm(this.opts.Rand, data, algorithm)
+}
+
+// 返回全部密钥的 ssh.Signer
+func (this *Keyring) Signers() ([]ssh.Signer, error) {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    if this.locked {
+        return nil, ErrLocked
+    }
+
+    this.expireKeysLocked()
+
+    signers := make([]ssh.Signer, 0, len(this.keys))
+    for _, k := range this.keys {
+        // 需要确认的密钥通过 agent 签名
+        if k.confirm {
+            signers = append(signers, &agentSigner{this, k.signer.PublicKey()})
+            continue
+        }
+
+        signers = append(signers, k.signer)
+    }
+
+    return signers, nil
+}
+
+// 扩展
+func (this *Keyring) Extension(extensionType string, contents []byte) ([]byte, error) {
+    return nil, ErrExtensionUnsupported
+}
+
+func (this *Keyring) findKey(key ssh.PublicKey) (*keyringKey, error) {
+    this.mu.Lock()
+    defer this.mu.Unlock()
+
+    if this.locked {
+        return nil, ErrLocked
+    }
+
+    this.expireKeysLocked()
+
+    blob := key.Marshal()
+    for _, k := range this.keys {
+        if bytes.Equal(k.signer.PublicKey().Marshal(), blob) {
+            return k, nil
+        }
+    }
+
+    return nil, ErrKeyNotFound
+}
+
+// 移除过期的密钥
+func (this *Keyring) expireKeysLocked() {
+    now := time.Now()
+
+    keys := this.keys[:0]
+    for _, k := range this.keys {
+        if k.expire.IsZero() || now.Before(k.expire) {
+            keys = append(keys, k)
+        }
+    }
+
+    this.keys = keys
+}
+
+// 通过 Agent 签名的 ssh.Signer
+type agentSigner struct {
+    agent Agent
+    pub   ssh.PublicKey
+}
+
+func (this *agentSigner) PublicKey() ssh.PublicKey {
+    return this.pub
+}
+
+func (this *agentSigner) Sign(rand io.Reader, data []byte) (*ssh.Signature, error) {
+    return this.agent.Sign(this.pub, data)
+}
+
+func (this *agentSigner) SignWithAlgorithm(rand io.Reader, data []byte, algorithm string) (*ssh.Signature, error) {
+    var flags SignatureFlags
+
+    switch algorithm {
+        case ssh.KeyAlgoRSASHA256:
+            flags = SignatureFlagRsaSha256
+        case ssh.KeyAlgoRSASHA512:
+            flags = SignatureFlagRsaSha512
+        case "", this.pub.Type():
+        default:
+            return nil, errors.New("agent: unsupported signature algorithm " + algorithm)
+    }
+
+    return this.agent.SignWithFlags(this.pub, data, flags)
+}
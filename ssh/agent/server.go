@@ -0,0 +1,327 @@
+package agent
+
+import (
+    "io"
+    "fmt"
+    "net"
+    "errors"
+    "encoding/binary"
+
+    "golang.org/x/crypto/ssh"
+
+    ssh_agent "golang.org/x/crypto/ssh/agent"
+    cryptobin_ssh "github.com/deatil/go-cryptobin/ssh"
+)
+
+// 私钥数据包含的字段数量, 包括最后的备注
+var keyFields = map[string]int{
+    ssh.KeyAlgoRSA:           7,
+    ssh.KeyAlgoECDSA256:      4,
+    ssh.KeyAlgoECDSA384:      4,
+    ssh.KeyAlgoECDSA521:      4,
+    ssh.KeyAlgoED25519:       3,
+    cryptobin_ssh.KeyAlgoSM2: 3,
+}
+
+// 添加私钥字段数量, 私钥数据需要能用 ssh.ParseKeytype 解析
+func AddKeyFields(keyType string, fields int) {
+    keyFields[keyType] = fields
+}
+
+// 监听并处理请求, 如 unix socket
+func Serve(l net.Listener, agent Agent) error {
+    for {
+        conn, err := l.Accept()
+        if err != nil {
+            return err
+        }
+
+        go func() {
+            defer conn.Close()
+
+            ServeAgent(agent, conn)
+        }()
+    }
+}
+
+// 处理 ssh-agent 协议请求, 直到读取出错
+func ServeAgent(agent Agent, c io.ReadWriter) error {
+    s := &server{agent}
+
+    var length [4]byte
+    for {
+        if _, err := io.ReadFull(c, length[:]); err != nil {
+            return err
+        }
+
+        l := binary.BigEndian.Uint32(length[:])
+        if l == 0 {
+            return errors.New("agent: request size is 0")
+        }
+
+        if l > maxAgentResponseBytes {
+            return fmt.Errorf("agent: request too large: %d", l)
+        }
+
+        req := make([]byte, l)
+        if _, err := io.ReadFull(c, req); err != nil {
+            return err
+        }
+
+        reply, err := s.processRequest(req)
+        if err != nil {
+            reply = []byte{agentFailure}
+        }
+
+        if len(reply) > maxAgentResponseBytes {
+            return fmt.Errorf("agent: reply too large: %d", len(reply))
+        }
+
+        binary.BigEndian.PutUint32(length[:], uint32(len(reply)))
+        if _, err := c.Write(append(length[:], reply...)); err != nil {
+            return err
+        }
+    }
+}
+
+type server struct {
+    agent Agent
+}
+
+type signRequestMsg struct {
+    KeyBlob []byte `sshtype:"13"`
+    Data    []byte
+    Flags   uint32
+}
+
+type signResponseMsg struct {
+    SigBlob []byte `sshtype:"14"`
+}
+
+type identitiesAnswerMsg struct {
+    NumKeys uint32 `sshtype:"12"`
+    Keys    []byte `ssh:"rest"`
+}
+
+type keyBlobMsg struct {
+    KeyBlob []byte
+}
+
+type passphraseMsg struct {
+    Passphrase []byte
+}
+
+type extensionMsg struct {
+    ExtensionType string `sshtype:"27"`
+    Contents      []byte `ssh:"rest"`
+}
+
+func (this *server) processRequest(data []byte) ([]byte, error) {
+    switch data[0] {
+        case agentRequestIdentities:
+            keys, err := this.agent.List()
+            if err != nil {
+                return nil, err
+            }
+
+            var blobs []byte
+            for _, k := range keys {
+                blobs = append(blobs, ssh.Marshal(struct {
+                    Blob    []byte
+                    Comment string
+                }{k.Blob, k.Comment})...)
+            }
+
+            return ssh.Marshal(identitiesAnswerMsg{
+                NumKeys: uint32(len(keys)),
+                Keys:    blobs,
+            }), nil
+
+        case agentSignRequest:
+            var req signRequestMsg
+            if err := ssh.Unmarshal(data, &req); err != nil {
+                return nil, err
+            }
+
+            pub, err := cryptobin_ssh.ParsePublicKey(req.KeyBlob)
+            if err != nil {
+                return nil, err
+            }
+
+            sig, err := this.agent.SignWithFlags(pub, req.Data, SignatureFlags(req.Flags))
+            if err != nil {
+                return nil, err
+            }
+
+            return ssh.Marshal(signResponseMsg{
+                SigBlob: ssh.Marshal(sig),
+            }), nil
+
+        case agentAddIdentity, agentAddIDConstrained:
+            key, err := parseAddedKey(data)
+            if err != nil {
+                return nil, err
+            }
+
+            return this.status(this.agent.Add(key))
+
+        case agentRemoveIdentity:
+            var req keyBlobMsg
+            if err := ssh.Unmarshal(data[1:], &req); err != nil {
+                return nil, err
+            }
+
+            pub, err := cryptobin_ssh.ParsePublicKey(req.KeyBlob)
+            if err != nil {
+                return nil, err
+            }
+
+            return this.status(this.agent.Remove(pub))
+
+        case agentRemoveAllIdentities:
+            return this.status(this.agent.RemoveAll())
+
+        case agentLock, agentUnlock:
+            var req passphraseMsg
+            if err := ssh.Unmarshal(data[1:], &req); err != nil {
+                return nil, err
+            }
+
+            if data[0] == agentLock {
+                return this.status(this.agent.Lock(req.Passphrase))
+            }
+
+            return this.status(this.agent.Unlock(req.Passphrase))
+
+        case agentExtension:
+            var req extensionMsg
+            if err := ssh.Unmarshal(data, &req); err != nil {
+                return nil, err
+            }
+
+            res, err := this.agent.Extension(req.ExtensionType, req.Contents)
+            if err != nil {
+                if err == ErrExtensionUnsupported {
+                    return []byte{agentFailure}, nil
+                }
+
+                return []byte{agentExtensionFailure}, nil
+            }
+
+            if len(res) == 0 {
+                return []byte{agentSuccess}, nil
+            }
+
+            return res, nil
+    }
+
+    return nil, fmt.Errorf("agent: unknown type tag %d", data[0])
+}
+
+func (this *server) status(err error) ([]byte, error) {
+    if err != nil {
+        return nil, err
+    }
+
+    return []byte{agentSuccess}, nil
+}
+
+// 解析添加的私钥, 私钥数据和 OpenSSH 私钥格式相同
+func parseAddedKey(data []byte) (AddedKey, error) {
+    keyType, rest, ok := parseString(data[1:])
+    if !ok {
+        return AddedKey{}, errors.New("agent: short read")
+    }
+
+    fields, ok := keyFields[string(keyType)]
+    if !ok {
+        return AddedKey{}, fmt.Errorf("agent: unsupported key type %s", keyType)
+    }
+
+    constraints := rest
+    for i := 0; i < fields; i++ {
+        if _, constraints, ok = parseString(constraints); !ok {
+            return AddedKey{}, errors.New("agent: short read")
+        }
+    }
+
+    newKey, err := cryptobin_ssh.ParseKeytype(string(keyType))
+    if err != nil {
+        return AddedKey{}, err
+    }
+
+    priv, comment, err := newKey.Parse(rest[:len(rest)-len(constraints)])
+    if err != nil {
+        return AddedKey{}, err
+    }
+
+    key := AddedKey{
+        PrivateKey: priv,
+        Comment:    comment,
+    }
+
+    if data[0] == agentAddIdentity {
+        if len(constraints) > 0 {
+            return AddedKey{}, errors.New("agent: unexpected trailing data")
+        }
+
+        return key, nil
+    }
+
+    if err := parseConstraints(constraints, &key); err != nil {
+        return AddedKey{}, err
+    }
+
+    return key, nil
+}
+
+// 解析密钥约束
+func parseConstraints(constraints []byte, key *AddedKey) error {
+    for len(constraints) > 0 {
+        switch constraints[0] {
+            case agentConstrainLifetime:
+                if len(constraints) < 5 {
+                    return errors.New("agent: short read")
+                }
+
+                key.LifetimeSecs = binary.BigEndian.Uint32(constraints[1:5])
+                constraints = constraints[5:]
+            case agentConstrainConfirm:
+                key.ConfirmBeforeUse = true
+                constraints = constraints[1:]
+            case agentConstrainExtension:
+                var msg struct {
+                    Name    string
+                    Details []byte
+                    Rest    []byte `ssh:"rest"`
+                }
+                if err := ssh.Unmarshal(constraints[1:], &msg); err != nil {
+                    return err
+                }
+
+                key.ConstraintExtensions = append(key.ConstraintExtensions, ssh_agent.ConstraintExtension{
+                    ExtensionName:    msg.Name,
+                    ExtensionDetails: msg.Details,
+                })
+                constraints = msg.Rest
+            default:
+                return fmt.Errorf("agent: unknown constraint type %d", constraints[0])
+        }
+    }
+
+    return nil
+}
+
+func parseString(in []byte) (out, rest []byte, ok bool) {
+    if len(in) < 4 {
+        return
+    }
+
+    length := binary.BigEndian.Uint32(in)
+    in = in[4:]
+    if uint32(len(in)) < length {
+        return
+    }
+
+    return in[:length], in[length:], true
+}
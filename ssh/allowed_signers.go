@@ -0,0 +1,294 @@
+package ssh
+
+import (
+    "io"
+    "fmt"
+    "time"
+    "bytes"
+    "errors"
+    "strings"
+
+    "golang.org/x/crypto/ssh"
+)
+
+// allowed_signers 文件中的一行
+type AllowedSigner struct {
+    // 用户名, 可以使用 * 及 ? 通配符, ! 开头为排除
+    Principals []string
+
+    // 公钥为 CA 公钥
+    CertAuthority bool
+
+    // 允许的命名空间, 为空时不限制
+    Namespaces []string
+
+    // 有效期, 为空时不限制
+    ValidAfter  time.Time
+    ValidBefore time.Time
+
+    // 公钥
+    Key ssh.PublicKey
+}
+
+// allowed_signers 列表
+type AllowedSigners []*AllowedSigner
+
+// 解析 allowed_signers 文件
+// 格式: principals [options] keytype base64-key [comment]
+func ParseAllowedSigners(in []byte) (AllowedSigners, error) {
+    var signers AllowedSigners
+
+    for lineNum := 1; len(in) > 0; lineNum++ {
+        var line []byte
+
+        end := bytes.IndexByte(in, '\n')
+        if end != -1 {
+            line, in = in[:end], in[end+1:]
+        } else {
+            line, in = in, nil
+        }
+
+        line = bytes.TrimSpace(line)
+        if len(line) == 0 || line[0] == '#' {
+            continue
+        }
+
+        signer, err := parseAllowedSigner(line)
+        if err != nil {
+            return nil, fmt.Errorf("ssh: allowed_signers line %d: %v", lineNum, err)
+        }
+
+        signers = append(signers, signer)
+    }
+
+    return signers, nil
+}
+
+func parseAllowedSigner(line []byte) (*AllowedSigner, error) {
+    var principals string
+
+    if line[0] == '"' {
+        end := bytes.IndexByte(line[1:], '"')
+        if end == -1 {
+            return nil, errors.New("unmatched quote in principals")
+        }
+
+        principals, line = string(line[1:end+1]), line[end+2:]
+    } else {
+        end := bytes.IndexAny(line, " \t")
+        if end == -1 {
+            return nil, errors.New("missing public key")
+        }
+
+        principals, line = string(line[:end]), line[end:]
+    }
+
+    if principals == "" {
+        return nil, errors.New("empty principals")
+    }
+
+    key, _, options, _, err := ParseAuthorizedKey(line)
+    if err != nil {
+        return nil, err
+    }
+
+    signer := &AllowedSigner{
+        Principals: strings.Split(principals, ","),
+        Key:        key,
+    }
+
+    for _, option := range options {
+        name, value, hasValue := strings.Cut(option, "=")
+        value = strings.Trim(value, "\"")
+
+        switch strings.ToLower(name) {
+            case "cert-authority":
+                signer.CertAuthority = true
+            case "namespaces":
+                if !hasValue {
+                    return nil, errors.New("namespaces option requires a value")
+                }
+
+                signer.Namespaces = strings.Split(value, ",")
+            case "valid-after":
+                if signer.ValidAfter, err = parseAllowedSignerTime(value); err != nil {
+                    return nil, err
+                }
+            case "valid-before":
+                if signer.ValidBefore, err = parseAllowedSignerTime(value); err != nil {
+                    return nil, err
+                }
+            default:
+                return nil, fmt.Errorf("unsupported option %q", name)
+        }
+    }
+
+    return signer, nil
+}
+
+// 时间格式: YYYYMMDD[HHMM[SS]][Z], 以 Z 结尾时为 UTC 时间
+func parseAllowedSignerTime(value string) (time.Time, error) {
+    loc := time.Local
+    if strings.HasSuffix(value, "Z") || strings.HasSuffix(value, "z") {
+        loc = time.UTC
+        value = value[:len(value)-1]
+    }
+
+    var layout string
+    switch len(value) {
+        case 8:
+            layout = "20060102"
+        case 12:
+            layout = "200601021504"
+        case 14:
+            layout = "20060102150405"
+        default:
+            return time.Time{}, fmt.Errorf("invalid time %q", value)
+    }
+
+    return time.ParseInLocation(layout, value, loc)
+}
+
+// 检测是否可以使用
+func (this *AllowedSigner) Allowed(principal, namespace string, key ssh.PublicKey, t time.Time) bool {
+    if !matchPrincipals(this.Principals, principal) {
+        return false
+    }
+
+    return this.allowedKey(namespace, key, principal, t)
+}
+
+func (this *AllowedSigner) allowedKey(namespace string, key ssh.PublicKey, principal string, t time.Time) bool {
+    if len(this.Namespaces) > 0 && !matchPrincipals(this.Namespaces, namespace) {
+        return false
+    }
+
+    if !this.ValidAfter.IsZero() && t.Before(this.ValidAfter) {
+        return false
+    }
+
+    if !this.ValidBefore.IsZero() && !t.Before(this.ValidBefore) {
+        return false
+    }
+
+    if !this.CertAuthority {
+        return bytes.Equal(this.Key.Marshal(), key.Marshal())
+    }
+
+    // CA 签发的用户证书
+    cert, ok := key.(*ssh.Certificate)
+    if !ok || cert.CertType != UserCert {
+        return false
+    }
+
+    err := VerifyCertificate(cert, VerifyCertOpts{
+        CAKeys:      []ssh.PublicKey{this.Key},
+        Principal:   principal,
+        CurrentTime: t,
+    })
+
+    return err == nil
+}
+
+// 验证签名, 并且签名公钥在列表中可以用于 principal 及 namespace
+// t 为验证时间, 为空时使用当前时间
+func (this AllowedSigners) Verify(message io.Reader, sig *SSHSig, principal, namespace string, t time.Time) (*AllowedSigner, error) {
+    if t.IsZero() {
+        t = time.Now()
+    }
+
+    var allowed *AllowedSigner
+    for _, signer := range this {
+        if signer.Allowed(principal, namespace, sig.PublicKey, t) {
+            allowed = signer
+            break
+        }
+    }
+
+    if allowed == nil {
+        return nil, fmt.Errorf("ssh: no allowed signer for principal %q and namespace %q", principal, namespace)
+    }
+
+    if err := sig.Verify(message, namespace); err != nil {
+        return nil, err
+    }
+
+    return allowed, nil
+}
+
+// 查找公钥对应的用户名, 和 ssh-keygen -Y find-principals 相同
+func (this AllowedSigners) FindPrincipals(key ssh.PublicKey, namespace string, t time.Time) []string {
+    if t.IsZero() {
+        t = time.Now()
+    }
+
+    var principals []string
+    for _, signer := range this {
+        if signer.CertAuthority {
+            continue
+        }
+
+        if signer.allowedKey(namespace, key, "", t) {
+            principals = append(principals, signer.Principals...)
+        }
+    }
+
+    return principals
+}
+
+// 匹配列表, 有排除项匹配时返回 false
+func matchPrincipals(patterns []string, s string) bool {
+    matched := false
+    for _, pattern := range patterns {
+        negated := strings.HasPrefix(pattern, "!")
+        if negated {
+            pattern = pattern[1:]
+        }
+
+        if matchPattern(pattern, s) {
+            if negated {
+                return false
+            }
+
+            matched = true
+        }
+    }
+
+    return matched
+}
+
+// 通配符匹配, * 匹配任意字符, ? 匹配一个字符
+func matchPattern(pattern, s string) bool {
+    for len(pattern) > 0 {
+        switch pattern[0] {
+            case '*':
+                for len(pattern) > 0 && pattern[0] == '*' {
+                    pattern = pattern[1:]
+                }
+
+                if len(pattern) == 0 {
+                    return true
+                }
+
+                for i := 0; i <= len(s); i++ {
+                    if matchPattern(pattern, s[i:]) {
+                        return true
+                    }
+                }
+
+                return false
+            case '?':
+                if len(s) == 0 {
+                    return false
+                }
+            default:
+                if len(s) == 0 || s[0] != pattern[0] {
+                    return false
+                }
+        }
+
+        pattern, s = pattern[1:], s[1:]
+    }
+
+    return len(s) == 0
+}
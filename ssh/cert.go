@@ -0,0 +1,397 @@
+package ssh
+
+import (
+    "io"
+    "fmt"
+    "time"
+    "bytes"
+    "errors"
+
+    "golang.org/x/crypto/ssh"
+)
+
+const (
+    UserCert = ssh.UserCert
+    HostCert = ssh.HostCert
+)
+
+// 证书公钥部分包含的字段数量
+var certKeyFields = map[string]int{
+    ssh.KeyAlgoRSA:        2,
+    ssh.KeyAlgoDSA:        4,
+    ssh.KeyAlgoECDSA256:   2,
+    ssh.KeyAlgoECDSA384:   2,
+    ssh.KeyAlgoECDSA521:   2,
+    ssh.KeyAlgoSKECDSA256: 3,
+    ssh.KeyAlgoED25519:    1,
+    ssh.KeyAlgoSKED25519:  2,
+}
+
+// 证书名称对应的公钥类型
+var certKeyAlgos = map[string]string{
+    ssh.CertAlgoRSAv01:        ssh.KeyAlgoRSA,
+    ssh.CertAlgoDSAv01:        ssh.KeyAlgoDSA,
+    ssh.CertAlgoECDSA256v01:   ssh.KeyAlgoECDSA256,
+    ssh.CertAlgoECDSA384v01:   ssh.KeyAlgoECDSA384,
+    ssh.CertAlgoECDSA521v01:   ssh.KeyAlgoECDSA521,
+    ssh.CertAlgoSKECDSA256v01: ssh.KeyAlgoSKECDSA256,
+    ssh.CertAlgoED25519v01:    ssh.KeyAlgoED25519,
+    ssh.CertAlgoSKED25519v01:  ssh.KeyAlgoSKED25519,
+}
+
+func init() {
+    // golang.org/x/crypto/ssh 不能解析 SM2 CA 签发的证书
+    for certAlgo, keyAlgo := range certKeyAlgos {
+        AddPubKeyParser(certAlgo, newCertParser(keyAlgo))
+    }
+}
+
+// 默认的用户证书扩展, 和 ssh-keygen 保持一致
+var DefaultUserCertExtensions = map[string]string{
+    "permit-X11-forwarding":   "",
+    "permit-agent-forwarding": "",
+    "permit-port-forwarding":  "",
+    "permit-pty":              "",
+    "permit-user-rc":          "",
+}
+
+// 证书配置
+type CertOpts struct {
+    // 证书类型 [UserCert | HostCert]
+    CertType uint32
+
+    // 证书标识
+    KeyId string
+
+    // 序列号
+    Serial uint64
+
+    // 用户名或者主机名, 为空时对所有用户或者主机有效
+    Principals []string
+
+    // 有效期, 为空时不限制
+    ValidAfter  time.Time
+    ValidBefore time.Time
+
+    // 关键选项, 如 force-command 及 source-address
+    CriticalOptions map[string]string
+
+    // 扩展, 用户证书为 nil 时使用 DefaultUserCertExtensions
+    Extensions map[string]string
+}
+
+// 签发证书, 和 ssh-keygen -s 相同
+// ca 可用 [RSA | ECDSA | ED25519 | SM2] 私钥生成的 ssh.Signer, 可以使用 NewSignerFromKey 生成
+func SignCertificate(rand io.Reader, pub ssh.PublicKey, ca ssh.Signer, opts CertOpts) (*ssh.Certificate, error) {
+    if _, ok := certKeyFields[pub.Type()]; !ok {
+        return nil, fmt.Errorf("ssh: unsupported certificate key type %s", pub.Type())
+    }
+
+    if opts.CertType != UserCert && opts.CertType != HostCert {
+        return nil, fmt.Errorf("ssh: unsupported certificate type %d", opts.CertType)
+    }
+
+    validAfter := uint64(0)
+    if !opts.ValidAfter.IsZero() {
+        validAfter = uint64(opts.ValidAfter.Unix())
+    }
+
+    validBefore := uint64(ssh.CertTimeInfinity)
+    if !opts.ValidBefore.IsZero() {
+        validBefore = uint64(opts.ValidBefore.Unix())
+    }
+
+    if validBefore <= validAfter {
+        return nil, errors.New("ssh: certificate validity window is empty")
+    }
+
+    extensions := opts.Extensions
+    if extensions == nil && opts.CertType == UserCert {
+        extensions = DefaultUserCertExtensions
+    }
+
+    cert := &ssh.Certificate{
+        Key:             pub,
+        Serial:          opts.Serial,
+        CertType:        opts.CertType,
+        KeyId:           opts.KeyId,
+        ValidPrincipals: opts.Principals,
+        ValidAfter:      validAfter,
+        ValidBefore:     validBefore,
+        Permissions:     ssh.Permissions{
+            CriticalOptions: copyCertTuples(opts.CriticalOptions),
+            Extensions:      copyCertTuples(extensions),
+        },
+    }
+
+    if err := cert.SignCert(rand, ca); err != nil {
+        return nil, err
+    }
+
+    return cert, nil
+}
+
+// 解析证书
+func ParseCertificate(in []byte) (*ssh.Certificate, error) {
+    pub, err := ParsePublicKey(in)
+    if err != nil {
+        return nil, err
+    }
+
+    cert, ok := pub.(*ssh.Certificate)
+    if !ok {
+        return nil, errors.New("ssh: public key is not a certificate")
+    }
+
+    return cert, nil
+}
+
+// 解析 authorized_keys 格式的证书, 如 ssh-keygen 生成的 *-cert.pub 文件
+func ParseAuthorizedCertificate(in []byte) (*ssh.Certificate, string, error) {
+    pub, comment, _, _, err := ParseAuthorizedKey(in)
+    if err != nil {
+        return nil, "", err
+    }
+
+    cert, ok := pub.(*ssh.Certificate)
+    if !ok {
+        return nil, "", errors.New("ssh: public key is not a certificate")
+    }
+
+    return cert, comment, nil
+}
+
+// 证书验证配置
+type VerifyCertOpts struct {
+    // 信任的 CA 公钥
+    CAKeys []ssh.PublicKey
+
+    // 证书类型, 为 0 时不检测
+    CertType uint32
+
+    // 需要检测的用户名或者主机名
+    Principal string
+
+    // 验证时间, 为空时使用当前时间
+    CurrentTime time.Time
+
+    // 支持的关键选项, 证书包含其他关键选项时验证失败
+    SupportedCriticalOptions []string
+
+    // 检测证书是否吊销
+    IsRevoked func(cert *ssh.Certificate) bool
+}
+
+// 验证证书签名, 有效期及用户名或者主机名
+func VerifyCertificate(cert *ssh.Certificate, opts VerifyCertOpts) error {
+    if opts.CertType != 0 && cert.CertType != opts.CertType {
+        return fmt.Errorf("ssh: certificate type %d is not %d", cert.CertType, opts.CertType)
+    }
+
+    trusted := false
+    caKey := cert.SignatureKey.Marshal()
+    for _, key := range opts.CAKeys {
+        if bytes.Equal(key.Marshal(), caKey) {
+            trusted = true
+            break
+        }
+    }
+
+    if !trusted {
+        return errors.New("ssh: certificate signed by unrecognized authority")
+    }
+
+    checker := &ssh.CertChecker{
+        SupportedCriticalOptions: opts.SupportedCriticalOptions,
+        IsRevoked:                opts.IsRevoked,
+    }
+
+    if !opts.CurrentTime.IsZero() {
+        checker.Clock = func() time.Time {
+            return opts.CurrentTime
+        }
+    }
+
+    return checker.CheckCert(opts.Principal, cert)
+}
+
+// 生成证书解析方式
+func newCertParser(keyAlgo string) PubKeyParser {
+    return func(in []byte) (ssh.PublicKey, []byte, error) {
+        return parseCert(in, keyAlgo)
+    }
+}
+
+// 证书通用数据
+type certData struct {
+    Serial          uint64
+    CertType        uint32
+    KeyId           string
+    ValidPrincipals []byte
+    ValidAfter      uint64
+    ValidBefore     uint64
+    CriticalOptions []byte
+    Extensions      []byte
+    Reserved        []byte
+    SignatureKey    []byte
+    Signature       []byte
+}
+
+// 解析证书, SignatureKey 可以为 SM2 公钥
+func parseCert(in []byte, keyAlgo string) (ssh.PublicKey, []byte, error) {
+    nonce, rest, ok := parseString(in)
+    if !ok {
+        return nil, nil, errors.New("ssh: short read")
+    }
+
+    fields := certKeyFields[keyAlgo]
+
+    keyData := rest
+    for i := 0; i < fields; i++ {
+        if _, rest, ok = parseString(rest); !ok {
+            return nil, nil, errors.New("ssh: short read")
+        }
+    }
+
+    key, err := ParsePublicKey(ssh.Marshal(struct {
+        Name string
+        Rest []byte `ssh:"rest"`
+    }{keyAlgo, keyData[:len(keyData)-len(rest)]}))
+    if err != nil {
+        return nil, nil, err
+    }
+
+    var g certData
+    if err := ssh.Unmarshal(rest, &g); err != nil {
+        return nil, nil, err
+    }
+
+    cert := &ssh.Certificate{
+        Nonce:       nonce,
+        Key:         key,
+        Serial:      g.Serial,
+        CertType:    g.CertType,
+        KeyId:       g.KeyId,
+        ValidAfter:  g.ValidAfter,
+        ValidBefore: g.ValidBefore,
+        Reserved:    g.Reserved,
+    }
+
+    for principals := g.ValidPrincipals; len(principals) > 0; {
+        principal, rest, ok := parseString(principals)
+        if !ok {
+            return nil, nil, errors.New("ssh: short read")
+        }
+
+        cert.ValidPrincipals = append(cert.ValidPrincipals, string(principal))
+        principals = rest
+    }
+
+    if cert.CriticalOptions, err = parseCertTuples(g.CriticalOptions); err != nil {
+        return nil, nil, err
+    }
+
+    if cert.Extensions, err = parseCertTuples(g.Extensions); err != nil {
+        return nil, nil, err
+    }
+
+    if cert.SignatureKey, err = ParsePublicKey(g.SignatureKey); err != nil {
+        return nil, nil, err
+    }
+
+    cert.Signature, err = parseCertSignature(g.Signature)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    return cert, nil, nil
+}
+
+// 解析关键选项及扩展
+func parseCertTuples(in []byte) (map[string]string, error) {
+    tups := map[string]string{}
+
+    var lastKey string
+    var haveLastKey bool
+
+    for len(in) > 0 {
+        var key, val, extra []byte
+        var ok bool
+
+        if key, in, ok = parseString(in); !ok {
+            return nil, errors.New("ssh: short read")
+        }
+
+        keyStr := string(key)
+
+        // 按名称排序且不能重复
+        if haveLastKey && keyStr <= lastKey {
+            return nil, fmt.Errorf("ssh: certificate options are not in lexical order")
+        }
+
+        lastKey, haveLastKey = keyStr, true
+
+        if val, in, ok = parseString(in); !ok {
+            return nil, errors.New("ssh: short read")
+        }
+
+        // 值不为空时为嵌套的字符串
+        if len(val) > 0 {
+            val, extra, ok = parseString(val)
+            if !ok {
+                return nil, errors.New("ssh: short read")
+            }
+
+            if len(extra) > 0 {
+                return nil, fmt.Errorf("ssh: unexpected trailing data after certificate option value")
+            }
+
+            tups[keyStr] = string(val)
+        } else {
+            tups[keyStr] = ""
+        }
+    }
+
+    return tups, nil
+}
+
+// 解析签名
+func parseCertSignature(in []byte) (*ssh.Signature, error) {
+    format, in, ok := parseString(in)
+    if !ok {
+        return nil, errors.New("ssh: signature parse error")
+    }
+
+    sig := &ssh.Signature{
+        Format: string(format),
+    }
+
+    if sig.Blob, in, ok = parseString(in); !ok {
+        return nil, errors.New("ssh: signature parse error")
+    }
+
+    switch sig.Format {
+        case ssh.KeyAlgoSKECDSA256, ssh.KeyAlgoSKED25519:
+            sig.Rest = in
+            in = nil
+    }
+
+    if len(in) > 0 {
+        return nil, errors.New("ssh: signature parse error")
+    }
+
+    return sig, nil
+}
+
+func copyCertTuples(in map[string]string) map[string]string {
+    if in == nil {
+        return nil
+    }
+
+    out := make(map[string]string, len(in))
+    for k, v := range in {
+        out[k] = v
+    }
+
+    return out
+}
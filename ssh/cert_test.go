@@ -0,0 +1,207 @@
+package ssh
+
+import (
+    "time"
+    "testing"
+    "crypto/rsa"
+    "crypto/rand"
+    "crypto/ecdsa"
+    "crypto/ed25519"
+    "crypto/elliptic"
+
+    "golang.org/x/crypto/ssh"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+// ssh-keygen -s ca -I alice-id -n alice,bob -V -1h:+1d -z 42 -O force-command=/bin/true -O no-pty user.pub
+var testSSHKeygenCert = `ecdsa-sha2-nistp256-cert-v01@openssh.com AAAAKGVjZHNhLXNoYTItbmlzdHAyNTYtY2VydC12MDFAb3BlbnNzaC5jb20AAAAg40pvsBKwDo2M148TKQhMpo6Qw7kBPAWS/k76yxdn4U8AAAAIbmlzdHAyNTYAAABBBLerqeS6xzoOMlLtahPH4OQuSV2ncNHeoS5ir8W3Nb5o48cVAjT8cXhdA0+3+V9i1Cp0ROIkowuOn1WNuR6u65oAAAAAAAAAKgAAAAEAAAAIYWxpY2UtaWQAAAAQAAAABWFsaWNlAAAAA2JvYgAAAABq1BOzAAAAAGrVc0MAAAAiAAAADWZvcmNlLWNvbW1hbmQAAAANAAAACS9iaW4vdHJ1ZQAAAHAAAAAVcGVybWl0LVgxMS1mb3J3YXJkaW5nAAAAAAAAABdwZXJtaXQtYWdlbnQtZm9yd2FyZGluZwAAAAAAAAAWcGVybWl0LXBvcnQtZm9yd2FyZGluZwAAAAAAAAAOcGVybWl0LXVzZXItcmMAAAAAAAAAAAAAADMAAAALc3NoLWVkMjU1MTkAAAAgOW5noPOlsDreqnOwu5zocZ6B9r2YcKR7b5WGlRuIHoYAAABTAAAAC3NzaC1lZDI1NTE5AAAAQNb3js5tNXCPvJ98Uq2SXrwcV8thBeb7QARm4OIG0IYOxUsyEOJFq5kwl+q4Wy5Ihdc61CupD24dwN3fgIUzAgU= root@vm`
+
+var testSSHKeygenCA = `ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIDluZ6DzpbA63qpzsLuc6HGegfa9mHCke2+VhpUbiB6G root@vm`
+
+func Test_ParseSSHKeygenCertificate(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    cert, comment, err := ParseAuthorizedCertificate([]byte(testSSHKeygenCert))
+    assertError(err, "Test_ParseSSHKeygenCertificate")
+
+    ca, _, _, _, err := ParseAuthorizedKey([]byte(testSSHKeygenCA))
+    assertError(err, "Test_ParseSSHKeygenCertificate-CA")
+
+    assertEqual(comment, "root@vm", "Test_ParseSSHKeygenCertificate-comment")
+    assertEqual(cert.CertType, uint32(UserCert), "Test_ParseSSHKeygenCertificate-CertType")
+    assertEqual(cert.KeyId, "alice-id", "Test_ParseSSHKeygenCertificate-KeyId")
+    assertEqual(cert.Serial, uint64(42), "Test_ParseSSHKeygenCertificate-Serial")
+    assertEqual(cert.ValidPrincipals, []string{"alice", "bob"}, "Test_ParseSSHKeygenCertificate-ValidPrincipals")
+    assertEqual(cert.CriticalOptions, map[string]string{"force-command": "/bin/true"}, "Test_ParseSSHKeygenCertificate-CriticalOptions")
+    assertEqual(len(cert.Extensions), 4, "Test_ParseSSHKeygenCertificate-Extensions")
+
+    now := time.Unix(int64(cert.ValidAfter) + 60, 0)
+
+    opts := VerifyCertOpts{
+        CAKeys:                   []ssh.PublicKey{ca},
+        CertType:                 UserCert,
+        Principal:                "bob",
+        CurrentTime:              now,
+        SupportedCriticalOptions: []string{"force-command"},
+    }
+
+    err = VerifyCertificate(cert, opts)
+    assertError(err, "Test_ParseSSHKeygenCertificate-Verify")
+
+    // 不支持的关键选项
+    opts.SupportedCriticalOptions = nil
+    err = VerifyCertificate(cert, opts)
+    assertNotErrorNil(err, "Test_ParseSSHKeygenCertificate-Verify-CriticalOptions")
+}
+
+func Test_SignCertificate(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+    ecdsaKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+    _, ed25519Key, _ := ed25519.GenerateKey(rand.Reader)
+    sm2Key, _ := sm2.GenerateKey(rand.Reader)
+
+    userKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    userPub, _ := NewPublicKey(&userKey.PublicKey)
+
+    otherKey, _ := sm2.GenerateKey(rand.Reader)
+    otherCA, _ := NewSignerFromKey(otherKey)
+
+    now := time.Now()
+
+    for name, key := range map[string]any{
+        "RSA":     rsaKey,
+        "ECDSA":   ecdsaKey,
+        "ED25519": ed25519Key,
+        "SM2":     sm2Key,
+    } {
+        ca, err := NewSignerFromKey(key)
+        assertError(err, "Test_SignCertificate-NewSignerFromKey-" + name)
+
+        cert, err := SignCertificate(rand.Reader, userPub, ca, CertOpts{
+            CertType:    UserCert,
+            KeyId:       "alice@example.com",
+            Serial:      1001,
+            Principals:  []string{"alice", "root"},
+            ValidAfter:  now.Add(-time.Minute),
+            ValidBefore: now.Add(time.Hour),
+            CriticalOptions: map[string]string{
+                "source-address": "10.0.0.0/8",
+            },
+        })
+        assertError(err, "Test_SignCertificate-Sign-" + name)
+
+        assertEqual(cert.Extensions, DefaultUserCertExtensions, "Test_SignCertificate-Extensions-" + name)
+
+        // authorized_keys 格式
+        cert2, _, err := ParseAuthorizedCertificate(MarshalAuthorizedKeyWithComment(cert, "alice"))
+        assertError(err, "Test_SignCertificate-Parse-" + name)
+
+        assertEqual(cert2.Marshal(), cert.Marshal(), "Test_SignCertificate-Marshal-" + name)
+        assertEqual(cert2.KeyId, "alice@example.com", "Test_SignCertificate-KeyId-" + name)
+        assertEqual(cert2.Serial, uint64(1001), "Test_SignCertificate-Serial-" + name)
+        assertEqual(cert2.CriticalOptions["source-address"], "10.0.0.0/8", "Test_SignCertificate-CriticalOptions-" + name)
+
+        opts := VerifyCertOpts{
+            CAKeys:    []ssh.PublicKey{otherCA.PublicKey(), ca.PublicKey()},
+            CertType:  UserCert,
+            Principal: "alice",
+        }
+
+        err = VerifyCertificate(cert2, opts)
+        assertError(err, "Test_SignCertificate-Verify-" + name)
+
+        // 不在证书中的用户名
+        badOpts := opts
+        badOpts.Principal = "bob"
+        err = VerifyCertificate(cert2, badOpts)
+        assertNotErrorNil(err, "Test_SignCertificate-Verify-Principal-" + name)
+
+        // 证书类型不匹配
+        badOpts = opts
+        badOpts.CertType = HostCert
+        err = VerifyCertificate(cert2, badOpts)
+        assertNotErrorNil(err, "Test_SignCertificate-Verify-CertType-" + name)
+
+        // 过期
+        badOpts = opts
+        badOpts.CurrentTime = now.Add(2 * time.Hour)
+        err = VerifyCertificate(cert2, badOpts)
+        assertNotErrorNil(err, "Test_SignCertificate-Verify-Expired-" + name)
+
+        // 不信任的 CA
+        badOpts = opts
+        badOpts.CAKeys = []ssh.PublicKey{otherCA.PublicKey()}
+        err = VerifyCertificate(cert2, badOpts)
+        assertNotErrorNil(err, "Test_SignCertificate-Verify-CA-" + name)
+
+        // 吊销
+        badOpts = opts
+        badOpts.IsRevoked = func(c *ssh.Certificate) bool {
+            return c.Serial == 1001
+        }
+        err = VerifyCertificate(cert2, badOpts)
+        assertNotErrorNil(err, "Test_SignCertificate-Verify-Revoked-" + name)
+
+        // 修改证书内容
+        cert2.Serial = 1002
+        err = VerifyCertificate(cert2, opts)
+        assertNotErrorNil(err, "Test_SignCertificate-Verify-Tampered-" + name)
+    }
+}
+
+func Test_SignHostCertificate(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    _, caKey, _ := ed25519.GenerateKey(rand.Reader)
+    ca, _ := NewSignerFromKey(caKey)
+
+    hostPub, _, _ := ed25519.GenerateKey(rand.Reader)
+    pub, _ := NewPublicKey(hostPub)
+
+    cert, err := SignCertificate(rand.Reader, pub, ca, CertOpts{
+        CertType:   HostCert,
+        KeyId:      "host",
+        Principals: []string{"host.example.com"},
+    })
+    assertError(err, "Test_SignHostCertificate")
+
+    assertEqual(len(cert.Extensions), 0, "Test_SignHostCertificate-Extensions")
+    assertEqual(cert.ValidBefore, uint64(ssh.CertTimeInfinity), "Test_SignHostCertificate-ValidBefore")
+
+    cert2, err := ParseCertificate(cert.Marshal())
+    assertError(err, "Test_SignHostCertificate-Parse")
+
+    err = VerifyCertificate(cert2, VerifyCertOpts{
+        CAKeys:    []ssh.PublicKey{ca.PublicKey()},
+        CertType:  HostCert,
+        Principal: "host.example.com",
+    })
+    assertError(err, "Test_SignHostCertificate-Verify")
+
+    _, err = ParseCertificate(pub.Marshal())
+    assertNotErrorNil(err, "Test_SignHostCertificate-ParseNotCert")
+
+    // SM2 公钥不能签发证书
+    sm2Key, _ := sm2.GenerateKey(rand.Reader)
+    _, err = SignCertificate(rand.Reader, NewSM2PublicKey(&sm2Key.PublicKey), ca, CertOpts{
+        CertType: UserCert,
+    })
+    assertNotErrorNil(err, "Test_SignHostCertificate-SM2Key")
+
+    _, err = SignCertificate(rand.Reader, pub, ca, CertOpts{
+        CertType:    HostCert,
+        ValidAfter:  time.Now(),
+        ValidBefore: time.Now().Add(-time.Hour),
+    })
+    assertNotErrorNil(err, "Test_SignHostCertificate-Validity")
+}
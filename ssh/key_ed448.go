@@ -0,0 +1,83 @@
+package ssh
+
+import (
+    "fmt"
+    "errors"
+    "crypto"
+
+    "golang.org/x/crypto/ssh"
+
+    "github.com/deatil/go-cryptobin/ed448"
+)
+
+var (
+    KeyAlgoED448 = "ssh-ed448"
+)
+
+// Ed448
+type KeyEd448 struct {}
+
+// 包装
+func (this KeyEd448) Marshal(key crypto.PrivateKey, comment string) (string, []byte, []byte, error) {
+    k, ok := key.(ed448.PrivateKey)
+    if !ok {
+        return "", nil, nil, errors.New(fmt.Sprintf("unsupported key type %T", key))
+    }
+
+    keyType := KeyAlgoED448
+
+    pub := make([]byte, ed448.PublicKeySize)
+    priv := make([]byte, ed448.PrivateKeySize)
+    copy(pub, k[ed448.SeedSize:])
+    copy(priv, k)
+
+    // Marshal public key.
+    pubKey := struct {
+        KeyType string
+        Pub     []byte
+    }{
+        keyType,
+        pub,
+    }
+    pubkey := ssh.Marshal(pubKey)
+
+    // Marshal private key.
+    prikey := struct {
+        Pub     []byte
+        Priv    []byte
+        Comment string
+    }{
+        pub, priv,
+        comment,
+    }
+    rest := ssh.Marshal(prikey)
+
+    return keyType, pubkey, rest, nil
+}
+
+// 解析
+func (this KeyEd448) Parse(rest []byte) (crypto.PrivateKey, string, error) {
+    key := struct {
+        Pub     []byte
+        Priv    []byte
+        Comment string
+        Pad     []byte `ssh:"rest"`
+    }{}
+
+    if err := ssh.Unmarshal(rest, &key); err != nil {
+        return nil, "", err
+    }
+
+    if err := checkOpenSSHKeyPadding(key.Pad); err != nil {
+        return nil, "", err
+    }
+
+    if len(key.Priv) != ed448.PrivateKeySize {
+        return nil, "", errors.New("private key unexpected length")
+    }
+
+    pk := ed448.PrivateKey(make([]byte, ed448.PrivateKeySize))
+    copy(pk, key.Priv)
+
+    return pk, key.Comment, nil
+}
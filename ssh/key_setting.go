@@ -8,6 +8,7 @@ import (
     "golang.org/x/crypto/ssh"
 
     "github.com/deatil/go-cryptobin/gm/sm2"
+    "github.com/deatil/go-cryptobin/ed448"
 )
 
 func init() {
@@ -38,10 +39,52 @@ func init() {
         return new(KeyEdDsa)
     })
 
+    AddKey(GetStructName(ed448.PrivateKey{}), func() Key {
+        return new(KeyEd448)
+    })
+    AddKey(KeyAlgoED448, func() Key {
+        return new(KeyEd448)
+    })
+
     AddKey(GetStructName(&sm2.PrivateKey{}), func() Key {
         return new(KeySM2)
     })
     AddKey(KeyAlgoSM2, func() Key {
         return new(KeySM2)
     })
+
+    // PuTTY PPK
+    AddPPKKey(GetStructName(&rsa.PrivateKey{}), func() PPKKey {
+        return new(PPKKeyRsa)
+    })
+    AddPPKKey(ssh.KeyAlgoRSA, func() PPKKey {
+        return new(PPKKeyRsa)
+    })
+
+    AddPPKKey(GetStructName(&ecdsa.PrivateKey{}), func() PPKKey {
+        return new(PPKKeyEcdsa)
+    })
+    AddPPKKey(ssh.KeyAlgoECDSA256, func() PPKKey {
+        return new(PPKKeyEcdsa)
+    })
+    AddPPKKey(ssh.KeyAlgoECDSA384, func() PPKKey {
+        return new(PPKKeyEcdsa)
+    })
+    AddPPKKey(ssh.KeyAlgoECDSA521, func() PPKKey {
+        return new(PPKKeyEcdsa)
+    })
+
+    AddPPKKey(GetStructName(ed25519.PrivateKey{}), func() PPKKey {
+        return new(PPKKeyEdDsa)
+    })
+    AddPPKKey(ssh.KeyAlgoED25519, func() PPKKey {
+        return new(PPKKeyEdDsa)
+    })
+
+    AddPPKKey(GetStructName(ed448.PrivateKey{}), func() PPKKey {
+        return new(PPKKeyEd448)
+    })
+    AddPPKKey(KeyAlgoED448, func() PPKKey {
+        return new(PPKKeyEd448)
+    })
 }
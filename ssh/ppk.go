@@ -0,0 +1,496 @@
+package ssh
+
+import (
+    "io"
+    "fmt"
+    "hash"
+    "bytes"
+    "errors"
+    "strconv"
+    "strings"
+    "crypto"
+    "crypto/hmac"
+    "crypto/sha1"
+    "crypto/sha256"
+    "crypto/subtle"
+    "encoding/hex"
+    "encoding/base64"
+    "encoding/binary"
+
+    "golang.org/x/crypto/ssh"
+    "golang.org/x/crypto/argon2"
+)
+
+/**
+ * PuTTY PPK 私钥
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+
+const (
+    ppkHeaderPrefix = "PuTTY-User-Key-File-"
+
+    ppkEncryptionNone   = "none"
+    ppkEncryptionAES256 = "aes256-cbc"
+
+    // v2 生成 MAC 密钥使用的前缀
+    ppkV2MacKeyPrefix = "putty-private-key-file-mac-key"
+
+    // base64 每行长度
+    ppkLineSize = 64
+)
+
+// v3 密钥派生方式
+const (
+    PPKArgon2id = "Argon2id"
+    PPKArgon2i  = "Argon2i"
+)
+
+// PPK 配置
+type PPKOpts struct {
+    // 版本 [2 | 3]
+    Version int
+
+    // v3 密钥派生方式 [PPKArgon2id | PPKArgon2i]
+    KeyDerivation string
+
+    // Argon2 内存大小, 单位为 KiB
+    Argon2Memory uint32
+
+    // Argon2 迭代次数
+    Argon2Passes uint32
+
+    // Argon2 并行数
+    Argon2Parallelism uint8
+
+    // 盐长度
+    SaltSize int
+}
+
+// 默认配置, 和 puttygen 保持一致
+var DefaultPPKOpts = PPKOpts{
+    Version:           3,
+    KeyDerivation:     PPKArgon2id,
+    Argon2Memory:      8192,
+    Argon2Passes:      13,
+    Argon2Parallelism: 1,
+    SaltSize:          16,
+}
+
+// 解析 PPK 私钥
+func ParsePPKPrivateKey(data []byte) (crypto.PrivateKey, string, error) {
+    return ParsePPKPrivateKeyWithPassword(data, nil)
+}
+
+// 解析带密码的 PPK 私钥
+func ParsePPKPrivateKeyWithPassword(data []byte, password []byte) (crypto.PrivateKey, string, error) {
+    f, err := parsePPKFile(data)
+    if err != nil {
+        return nil, "", err
+    }
+
+    if f.Version != 2 && f.Version != 3 {
+        return nil, "", fmt.Errorf("ssh: unsupported ppk version %d", f.Version)
+    }
+
+    encrypted := false
+    switch f.Encryption {
+        case ppkEncryptionNone:
+        case ppkEncryptionAES256:
+            encrypted = true
+        default:
+            return nil, "", fmt.Errorf("ssh: unsupported ppk encryption %s", f.Encryption)
+    }
+
+    if !encrypted {
+        password = nil
+    }
+
+    var cipherKey, macKey []byte
+    var newHash func() hash.Hash
+
+    if f.Version == 2 {
+        cipherKey, macKey = derivePPKV2Keys(password)
+        newHash = sha1.New
+    } else {
+        if encrypted {
+            cipherKey, macKey, err = derivePPKV3Keys(password, f)
+            if err != nil {
+                return nil, "", err
+            }
+        }
+
+        newHash = sha256.New
+    }
+
+    privBlob := f.PrivateBlob
+    if encrypted {
+        privBlob, err = AES256CBC.Decrypt(cipherKey, privBlob)
+        if err != nil {
+            return nil, "", err
+        }
+    }
+
+    mac := ppkMac(newHash, macKey, f, privBlob)
+    if subtle.ConstantTimeCompare(mac, f.PrivateMAC) != 1 {
+        if encrypted {
+            return nil, "", errors.New("ssh: ppk wrong password")
+        }
+
+        return nil, "", errors.New("ssh: ppk mac check failed")
+    }
+
+    newKey, err := ParsePPKKeytype(f.Algorithm)
+    if err != nil {
+        return nil, "", err
+    }
+
+    pk, err := newKey.Parse(f.PublicBlob, privBlob)
+    if err != nil {
+        return nil, "", err
+    }
+
+    return pk, f.Comment, nil
+}
+
+// 编码为 PPK 私钥
+func MarshalPPKPrivateKey(rand io.Reader, key crypto.PrivateKey, comment string) ([]byte, error) {
+    return MarshalPPKPrivateKeyWithPassword(rand, key, comment, nil)
+}
+
+// 编码为带密码的 PPK 私钥
+func MarshalPPKPrivateKeyWithPassword(rand io.Reader, key crypto.PrivateKey, comment string, password []byte, opts ...PPKOpts) ([]byte, error) {
+    opt := DefaultPPKOpts
+    if len(opts) > 0 {
+        opt = opts[0]
+    }
+
+    if opt.Version != 2 && opt.Version != 3 {
+        return nil, fmt.Errorf("ssh: unsupported ppk version %d", opt.Version)
+    }
+
+    newKey, err := ParsePPKKeytype(GetStructName(key))
+    if err != nil {
+        return nil, err
+    }
+
+    keyType, pubBlob, privBlob, err := newKey.Marshal(key)
+    if err != nil {
+        return nil, err
+    }
+
+    f := &ppkFile{
+        Version:    opt.Version,
+        Algorithm:  keyType,
+        Encryption: ppkEncryptionNone,
+        Comment:    comment,
+        PublicBlob: pubBlob,
+    }
+
+    if password != nil {
+        f.Encryption = ppkEncryptionAES256
+
+        // 填充到块大小, 填充数据为私钥数据的 SHA-1 摘要, 和 PuTTY 保持一致
+        if padLen := len(privBlob) % AES256CBC.BlockSize(); padLen != 0 {
+            sum := sha1.Sum(privBlob)
+            privBlob = append(privBlob, sum[:AES256CBC.BlockSize() - padLen]...)
+        }
+    }
+
+    var cipherKey, macKey []byte
+    var newHash func() hash.Hash
+
+    if opt.Version == 2 {
+        cipherKey, macKey = derivePPKV2Keys(password)
+        newHash = sha1.New
+    } else {
+        if password != nil {
+            f.KeyDerivation = opt.KeyDerivation
+            f.Argon2Memory = opt.Argon2Memory
+            f.Argon2Passes = opt.Argon2Passes
+            f.Argon2Parallelism = opt.Argon2Parallelism
+
+            f.Argon2Salt = make([]byte, opt.SaltSize)
+            if _, err := io.ReadFull(rand, f.Argon2Salt); err != nil {
+                return nil, err
+            }
+
+            cipherKey, macKey, err = derivePPKV3Keys(password, f)
+            if err != nil {
+                return nil, err
+            }
+        }
+
+        newHash = sha256.New
+    }
+
+    f.PrivateMAC = ppkMac(newHash, macKey, f, privBlob)
+
+    f.PrivateBlob = privBlob
+    if password != nil {
+        f.PrivateBlob, err = AES256CBC.Encrypt(cipherKey, privBlob)
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    return f.Marshal(), nil
+}
+
+// PPK 文件数据
+type ppkFile struct {
+    Version    int
+    Algorithm  string
+    Encryption string
+    Comment    string
+    PublicBlob []byte
+
+    // v3 加密时使用
+    KeyDerivation     string
+    Argon2Memory      uint32
+    Argon2Passes      uint32
+    Argon2Parallelism uint8
+    Argon2Salt        []byte
+
+    PrivateBlob []byte
+    PrivateMAC  []byte
+}
+
+// 编码
+func (this *ppkFile) Marshal() []byte {
+    var buf bytes.Buffer
+
+    fmt.Fprintf(&buf, "%s%d: %s\n", ppkHeaderPrefix, this.Version, this.Algorithm)
+    fmt.Fprintf(&buf, "Encryption: %s\n", this.Encryption)
+    fmt.Fprintf(&buf, "Comment: %s\n", this.Comment)
+
+    writePPKLines(&buf, "Public-Lines", this.PublicBlob)
+
+    if this.KeyDerivation != "" {
+        fmt.Fprintf(&buf, "Key-Derivation: %s\n", this.KeyDerivation)
+        fmt.Fprintf(&buf, "Argon2-Memory: %d\n", this.Argon2Memory)
+        fmt.Fprintf(&buf, "Argon2-Passes: %d\n", this.Argon2Passes)
+        fmt.Fprintf(&buf, "Argon2-Parallelism: %d\n", this.Argon2Parallelism)
+        fmt.Fprintf(&buf, "Argon2-Salt: %s\n", hex.EncodeToString(this.Argon2Salt))
+    }
+
+    writePPKLines(&buf, "Private-Lines", this.PrivateBlob)
+
+    fmt.Fprintf(&buf, "Private-MAC: %s\n", hex.EncodeToString(this.PrivateMAC))
+
+    return buf.Bytes()
+}
+
+// 解析 PPK 文件
+func parsePPKFile(data []byte) (*ppkFile, error) {
+    lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+    // 读取一行 "Key: Value" 数据
+    next := func(name string) (string, error) {
+        for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+            lines = lines[1:]
+        }
+
+        if len(lines) == 0 {
+            return "", fmt.Errorf("ssh: ppk missing %s", name)
+        }
+
+        key, value, ok := strings.Cut(lines[0], ": ")
+        if !ok || key != name {
+            return "", fmt.Errorf("ssh: ppk expected %s", name)
+        }
+
+        lines = lines[1:]
+
+        return value, nil
+    }
+
+    // 读取多行 base64 数据
+    nextBlob := func(name string) ([]byte, error) {
+        value, err := next(name)
+        if err != nil {
+            return nil, err
+        }
+
+        n, err := strconv.Atoi(value)
+        if err != nil || n < 0 || n > len(lines) {
+            return nil, fmt.Errorf("ssh: ppk invalid %s", name)
+        }
+
+        encoded := strings.Join(lines[:n], "")
+        lines = lines[n:]
+
+        return base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+    }
+
+    for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+        lines = lines[1:]
+    }
+
+    if len(lines) == 0 || !strings.HasPrefix(lines[0], ppkHeaderPrefix) {
+        return nil, errors.New("ssh: data is not ppk")
+    }
+
+    header, algorithm, ok := strings.Cut(lines[0], ": ")
+    if !ok {
+        return nil, errors.New("ssh: data is not ppk")
+    }
+
+    version, err := strconv.Atoi(strings.TrimPrefix(header, ppkHeaderPrefix))
+    if err != nil {
+        return nil, errors.New("ssh: data is not ppk")
+    }
+
+    lines = lines[1:]
+
+    f := &ppkFile{
+        Version:   version,
+        Algorithm: algorithm,
+    }
+
+    if f.Encryption, err = next("Encryption"); err != nil {
+        return nil, err
+    }
+
+    if f.Comment, err = next("Comment"); err != nil {
+        return nil, err
+    }
+
+    if f.PublicBlob, err = nextBlob("Public-Lines"); err != nil {
+        return nil, err
+    }
+
+    if version == 3 && f.Encryption != ppkEncryptionNone {
+        if f.KeyDerivation, err = next("Key-Derivation"); err != nil {
+            return nil, err
+        }
+
+        var memory, passes, parallelism, salt string
+
+        if memory, err = next("Argon2-Memory"); err != nil {
+            return nil, err
+        }
+        if passes, err = next("Argon2-Passes"); err != nil {
+            return nil, err
+        }
+        if parallelism, err = next("Argon2-Parallelism"); err != nil {
+            return nil, err
+        }
+        if salt, err = next("Argon2-Salt"); err != nil {
+            return nil, err
+        }
+
+        m, err1 := strconv.ParseUint(memory, 10, 32)
+        p, err2 := strconv.ParseUint(passes, 10, 32)
+        t, err3 := strconv.ParseUint(parallelism, 10, 8)
+        if err1 != nil || err2 != nil || err3 != nil {
+            return nil, errors.New("ssh: ppk invalid argon2 parameters")
+        }
+
+        f.Argon2Memory = uint32(m)
+        f.Argon2Passes = uint32(p)
+        f.Argon2Parallelism = uint8(t)
+
+        if f.Argon2Salt, err = hex.DecodeString(salt); err != nil {
+            return nil, errors.New("ssh: ppk invalid argon2 salt")
+        }
+    }
+
+    if f.PrivateBlob, err = nextBlob("Private-Lines"); err != nil {
+        return nil, err
+    }
+
+    mac, err := next("Private-MAC")
+    if err != nil {
+        return nil, err
+    }
+
+    if f.PrivateMAC, err = hex.DecodeString(strings.TrimSpace(mac)); err != nil {
+        return nil, errors.New("ssh: ppk invalid mac")
+    }
+
+    return f, nil
+}
+
+// v2 密钥, 加密密钥为 SHA-1(0 || password) || SHA-1(1 || password), IV 为 0
+func derivePPKV2Keys(password []byte) ([]byte, []byte) {
+    var key []byte
+    for i := uint32(0); i < 2; i++ {
+        h := sha1.New()
+        binary.Write(h, binary.BigEndian, i)
+        h.Write(password)
+        key = h.Sum(key)
+    }
+
+    cipherKey := make([]byte, AES256CBC.KeySize() + AES256CBC.BlockSize())
+    copy(cipherKey, key[:AES256CBC.KeySize()])
+
+    h := sha1.New()
+    h.Write([]byte(ppkV2MacKeyPrefix))
+    h.Write(password)
+
+    return cipherKey, h.Sum(nil)
+}
+
+// v3 密钥, Argon2 生成 80 字节数据: 加密密钥(32) || IV(16) || MAC 密钥(32)
+func derivePPKV3Keys(password []byte, f *ppkFile) ([]byte, []byte, error) {
+    if f.Argon2Parallelism == 0 {
+        return nil, nil, errors.New("ssh: ppk invalid argon2 parallelism")
+    }
+
+    keyLen := uint32(AES256CBC.KeySize() + AES256CBC.BlockSize() + 32)
+
+    var key []byte
+    switch f.KeyDerivation {
+        case PPKArgon2id:
+            key = argon2.IDKey(password, f.Argon2Salt, f.Argon2Passes, f.Argon2Memory, f.Argon2Parallelism, keyLen)
+        case PPKArgon2i:
+            key = argon2.Key(password, f.Argon2Salt, f.Argon2Passes, f.Argon2Memory, f.Argon2Parallelism, keyLen)
+        default:
+            return nil, nil, fmt.Errorf("ssh: unsupported ppk key derivation %s", f.KeyDerivation)
+    }
+
+    n := AES256CBC.KeySize() + AES256CBC.BlockSize()
+
+    return key[:n], key[n:], nil
+}
+
+// MAC 数据为 string 编码的 [算法, 加密方式, 备注, 公钥数据, 私钥数据]
+func ppkMac(h func() hash.Hash, key []byte, f *ppkFile, privBlob []byte) []byte {
+    data := ssh.Marshal(struct {
+        Algorithm   string
+        Encryption  string
+        Comment     string
+        PublicBlob  []byte
+        PrivateBlob []byte
+    }{
+        f.Algorithm,
+        f.Encryption,
+        f.Comment,
+        f.PublicBlob,
+        privBlob,
+    })
+
+    mac := hmac.New(h, key)
+    mac.Write(data)
+
+    return mac.Sum(nil)
+}
+
+// 写入多行 base64 数据
+func writePPKLines(buf *bytes.Buffer, name string, data []byte) {
+    encoded := base64.StdEncoding.EncodeToString(data)
+
+    n := (len(encoded) + ppkLineSize - 1) / ppkLineSize
+    fmt.Fprintf(buf, "%s: %d\n", name, n)
+
+    for len(encoded) > ppkLineSize {
+        buf.WriteString(encoded[:ppkLineSize] + "\n")
+        encoded = encoded[ppkLineSize:]
+    }
+
+    if len(encoded) > 0 {
+        buf.WriteString(encoded + "\n")
+    }
+}
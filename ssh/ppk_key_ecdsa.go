@@ -0,0 +1,118 @@
+package ssh
+
+import (
+    "fmt"
+    "errors"
+    "math/big"
+    "crypto"
+    "crypto/ecdsa"
+    "crypto/elliptic"
+
+    "golang.org/x/crypto/ssh"
+)
+
+// PPK ecdsa
+type PPKKeyEcdsa struct {}
+
+// 包装
+func (this PPKKeyEcdsa) Marshal(key crypto.PrivateKey) (string, []byte, []byte, error) {
+    k, ok := key.(*ecdsa.PrivateKey)
+    if !ok {
+        return "", nil, nil, errors.New(fmt.Sprintf("unsupported key type %T", key))
+    }
+
+    var curve, keyType string
+    switch k.Curve.Params().Name {
+        case "P-256":
+            curve = "nistp256"
+            keyType = ssh.KeyAlgoECDSA256
+        case "P-384":
+            curve = "nistp384"
+            keyType = ssh.KeyAlgoECDSA384
+        case "P-521":
+            curve = "nistp521"
+            keyType = ssh.KeyAlgoECDSA521
+        default:
+            return "", nil, nil, errors.New(fmt.Sprintf("error serializing key: unsupported curve %s", k.Curve.Params().Name))
+    }
+
+    pubKey := struct {
+        KeyType string
+        Curve   string
+        Pub     []byte
+    }{
+        keyType, curve,
+        elliptic.Marshal(k.Curve, k.PublicKey.X, k.PublicKey.Y),
+    }
+    pubkey := ssh.Marshal(pubKey)
+
+    prikey := struct {
+        D *big.Int
+    }{
+        k.D,
+    }
+    privkey := ssh.Marshal(prikey)
+
+    return keyType, pubkey, privkey, nil
+}
+
+// 解析
+func (this PPKKeyEcdsa) Parse(pubKey, privKey []byte) (crypto.PrivateKey, error) {
+    pub := struct {
+        KeyType string
+        Curve   string
+        Pub     []byte
+    }{}
+
+    if err := ssh.Unmarshal(pubKey, &pub); err != nil {
+        return nil, err
+    }
+
+    priv := struct {
+        D   *big.Int
+        Pad []byte `ssh:"rest"`
+    }{}
+
+    if err := ssh.Unmarshal(privKey, &priv); err != nil {
+        return nil, err
+    }
+
+    var curve elliptic.Curve
+    switch pub.Curve {
+        case "nistp256":
+            curve = elliptic.P256()
+        case "nistp384":
+            curve = elliptic.P384()
+        case "nistp521":
+            curve = elliptic.P521()
+        default:
+            return nil, errors.New(fmt.Sprintf("error decoding key: unsupported elliptic curve %s", pub.Curve))
+    }
+
+    if pub.KeyType != "ecdsa-sha2-" + pub.Curve {
+        return nil, errors.New("error decoding key: key type does not match curve")
+    }
+
+    X, Y := elliptic.Unmarshal(curve, pub.Pub)
+    if X == nil || Y == nil {
+        return nil, errors.New("error decoding key: failed to unmarshal public key")
+    }
+
+    if priv.D.Sign() <= 0 || priv.D.Cmp(curve.Params().N) >= 0 {
+        return nil, errors.New("error decoding key: scalar is out of range")
+    }
+
+    x, y := curve.ScalarBaseMult(priv.D.Bytes())
+    if x.Cmp(X) != 0 || y.Cmp(Y) != 0 {
+        return nil, errors.New("error decoding key: public key does not match private key")
+    }
+
+    return &ecdsa.PrivateKey{
+        PublicKey: ecdsa.PublicKey{
+            Curve: curve,
+            X:     X,
+            Y:     Y,
+        },
+        D: priv.D,
+    }, nil
+}
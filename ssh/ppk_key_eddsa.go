@@ -0,0 +1,120 @@
+package ssh
+
+import (
+    "fmt"
+    "bytes"
+    "errors"
+    "crypto"
+    "crypto/ed25519"
+
+    "golang.org/x/crypto/ssh"
+
+    "github.com/deatil/go-cryptobin/ed448"
+)
+
+// PPK EdDsa
+type PPKKeyEdDsa struct {}
+
+// 包装
+func (this PPKKeyEdDsa) Marshal(key crypto.PrivateKey) (string, []byte, []byte, error) {
+    k, ok := key.(ed25519.PrivateKey)
+    if !ok {
+        return "", nil, nil, errors.New(fmt.Sprintf("unsupported key type %T", key))
+    }
+
+    pubkey, privkey := marshalPPKEdDsa(ssh.KeyAlgoED25519, k[ed25519.SeedSize:], k.Seed())
+
+    return ssh.KeyAlgoED25519, pubkey, privkey, nil
+}
+
+// 解析
+func (this PPKKeyEdDsa) Parse(pubKey, privKey []byte) (crypto.PrivateKey, error) {
+    pub, seed, err := parsePPKEdDsa(pubKey, privKey, ed25519.SeedSize)
+    if err != nil {
+        return nil, err
+    }
+
+    pk := ed25519.NewKeyFromSeed(seed)
+    if !bytes.Equal(pk[ed25519.SeedSize:], pub) {
+        return nil, errors.New("error decoding key: public key does not match private key")
+    }
+
+    return pk, nil
+}
+
+// PPK Ed448
+type PPKKeyEd448 struct {}
+
+// 包装
+func (this PPKKeyEd448) Marshal(key crypto.PrivateKey) (string, []byte, []byte, error) {
+    k, ok := key.(ed448.PrivateKey)
+    if !ok {
+        return "", nil, nil, errors.New(fmt.Sprintf("unsupported key type %T", key))
+    }
+
+    pubkey, privkey := marshalPPKEdDsa(KeyAlgoED448, k[ed448.SeedSize:], k.Seed())
+
+    return KeyAlgoED448, pubkey, privkey, nil
+}
+
+// 解析
+func (this PPKKeyEd448) Parse(pubKey, privKey []byte) (crypto.PrivateKey, error) {
+    pub, seed, err := parsePPKEdDsa(pubKey, privKey, ed448.SeedSize)
+    if err != nil {
+        return nil, err
+    }
+
+    pk := ed448.NewKeyFromSeed(seed)
+    if !bytes.Equal(pk[ed448.SeedSize:], pub) {
+        return nil, errors.New("error decoding key: public key does not match private key")
+    }
+
+    return pk, nil
+}
+
+// PuTTY 将私钥作为小端序整数保存, 去掉高位的 0
+func marshalPPKEdDsa(keyType string, pub, seed []byte) ([]byte, []byte) {
+    pubKey := struct {
+        KeyType string
+        Pub     []byte
+    }{
+        keyType, pub,
+    }
+
+    prikey := struct {
+        Priv []byte
+    }{
+        bytes.TrimRight(seed, "\x00"),
+    }
+
+    return ssh.Marshal(pubKey), ssh.Marshal(prikey)
+}
+
+func parsePPKEdDsa(pubKey, privKey []byte, seedSize int) ([]byte, []byte, error) {
+    pub := struct {
+        KeyType string
+        Pub     []byte
+    }{}
+
+    if err := ssh.Unmarshal(pubKey, &pub); err != nil {
+        return nil, nil, err
+    }
+
+    priv := struct {
+        Priv []byte
+        Pad  []byte `ssh:"rest"`
+    }{}
+
+    if err := ssh.Unmarshal(privKey, &priv); err != nil {
+        return nil, nil, err
+    }
+
+    if len(priv.Priv) > seedSize {
+        return nil, nil, errors.New("private key unexpected length")
+    }
+
+    seed := make([]byte, seedSize)
+    copy(seed, priv.Priv)
+
+    return pub.Pub, seed, nil
+}
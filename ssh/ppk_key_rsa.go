@@ -0,0 +1,100 @@
+package ssh
+
+import (
+    "fmt"
+    "errors"
+    "math/big"
+    "crypto"
+    "crypto/rsa"
+
+    "golang.org/x/crypto/ssh"
+)
+
+// PPK rsa
+type PPKKeyRsa struct {}
+
+// 包装
+func (this PPKKeyRsa) Marshal(key crypto.PrivateKey) (string, []byte, []byte, error) {
+    k, ok := key.(*rsa.PrivateKey)
+    if !ok {
+        return "", nil, nil, errors.New(fmt.Sprintf("unsupported key type %T", key))
+    }
+
+    if len(k.Primes) != 2 {
+        return "", nil, nil, errors.New("ssh: ppk does not support multi-prime rsa keys")
+    }
+
+    keyType := ssh.KeyAlgoRSA
+
+    pubKey := struct {
+        KeyType string
+        E       *big.Int
+        N       *big.Int
+    }{
+        keyType,
+        big.NewInt(int64(k.PublicKey.E)),
+        k.PublicKey.N,
+    }
+    pubkey := ssh.Marshal(pubKey)
+
+    k.Precompute()
+
+    prikey := struct {
+        D    *big.Int
+        P    *big.Int
+        Q    *big.Int
+        Iqmp *big.Int
+    }{
+        k.D, k.Primes[0], k.Primes[1], k.Precomputed.Qinv,
+    }
+    privkey := ssh.Marshal(prikey)
+
+    return keyType, pubkey, privkey, nil
+}
+
+// 解析
+func (this PPKKeyRsa) Parse(pubKey, privKey []byte) (crypto.PrivateKey, error) {
+    pub := struct {
+        KeyType string
+        E       *big.Int
+        N       *big.Int
+    }{}
+
+    if err := ssh.Unmarshal(pubKey, &pub); err != nil {
+        return nil, err
+    }
+
+    // 私钥数据后面可能有填充
+    priv := struct {
+        D    *big.Int
+        P    *big.Int
+        Q    *big.Int
+        Iqmp *big.Int
+        Pad  []byte `ssh:"rest"`
+    }{}
+
+    if err := ssh.Unmarshal(privKey, &priv); err != nil {
+        return nil, err
+    }
+
+    if !pub.E.IsInt64() || pub.E.Int64() > 1<<31-1 {
+        return nil, errors.New("ssh: rsa public exponent too large")
+    }
+
+    pk := &rsa.PrivateKey{
+        PublicKey: rsa.PublicKey{
+            N: pub.N,
+            E: int(pub.E.Int64()),
+        },
+        D:      priv.D,
+        Primes: []*big.Int{priv.P, priv.Q},
+    }
+
+    if err := pk.Validate(); err != nil {
+        return nil, err
+    }
+
+    pk.Precompute()
+
+    return pk, nil
+}
@@ -49,6 +49,15 @@ type Key interface {
     Parse(data []byte) (crypto.PrivateKey, string, error)
 }
 
+// PPK Key 接口
+type PPKKey interface {
+    // 包装, 返回: [类型, 公钥数据, 私钥数据, error]
+    Marshal(key crypto.PrivateKey) (string, []byte, []byte, error)
+
+    // 解析
+    Parse(pubKey, privKey []byte) (crypto.PrivateKey, error)
+}
+
 var ciphers = make(map[string]func() Cipher)
 
 // 添加加密
@@ -70,3 +79,9 @@ func AddKey(name string, key func() Key) {
     keys[name] = key
 }
 
+var ppkKeys = make(map[string]func() PPKKey)
+
+// 添加 PPK Key
+func AddPPKKey(name string, key func() PPKKey) {
+    ppkKeys[name] = key
+}
@@ -36,3 +36,14 @@ func ParseKeytype(keytype string) (Key, error) {
 
     return newKeytype, nil
 }
+
+func ParsePPKKeytype(keytype string) (PPKKey, error) {
+    keyType, ok := ppkKeys[keytype]
+    if !ok {
+        return nil, fmt.Errorf("ssh: unsupported ppk key type %s", keytype)
+    }
+
+    newKeytype := keyType()
+
+    return newKeytype, nil
+}
@@ -0,0 +1,237 @@
+package ssh
+
+import (
+    "io"
+    "hash"
+    "bytes"
+    "errors"
+    "strings"
+    "crypto/sha256"
+    "crypto/sha512"
+    "encoding/base64"
+
+    "golang.org/x/crypto/ssh"
+
+    "github.com/deatil/go-cryptobin/hash/sm3"
+)
+
+/**
+ * SSHSIG 签名 (ssh-keygen -Y sign/verify)
+ *
+ * @create 2026-10-18
+ * @author deatil
+ */
+
+const (
+    sshsigMagic   = "SSHSIG"
+    sshsigVersion = 1
+
+    sshsigArmorStart = "-----BEGIN SSH SIGNATURE-----"
+    sshsigArmorEnd   = "-----END SSH SIGNATURE-----"
+)
+
+// 摘要方式
+var sshsigHashs = make(map[string]func() hash.Hash)
+
+// 添加 SSHSIG 摘要方式
+func AddSSHSigHash(name string, h func() hash.Hash) {
+    sshsigHashs[name] = h
+}
+
+func init() {
+    AddSSHSigHash("sha256", sha256.New)
+    AddSSHSigHash("sha512", sha512.New)
+    AddSSHSigHash("sm3", sm3.New)
+}
+
+// SSHSIG 签名数据
+type SSHSig struct {
+    // 签名公钥
+    PublicKey ssh.PublicKey
+
+    // 命名空间, 如 file 及 git
+    Namespace string
+
+    // 摘要方式 [sha256 | sha512 | sm3]
+    HashAlgorithm string
+
+    // 签名
+    Signature *ssh.Signature
+}
+
+type sshsigBlob struct {
+    Version       uint32
+    PublicKey     []byte
+    Namespace     string
+    Reserved      []byte
+    HashAlgorithm string
+    Signature     []byte
+}
+
+type sshsigSignedData struct {
+    Namespace     string
+    Reserved      []byte
+    HashAlgorithm string
+    Hash          []byte
+}
+
+// 签名, hashAlg 为空时使用 sha512
+// signer 可以使用 NewSignerFromKey 生成
+func SignSSHSig(rand io.Reader, signer ssh.Signer, message io.Reader, namespace string, hashAlg string) (*SSHSig, error) {
+    if namespace == "" {
+        return nil, errors.New("ssh: sshsig namespace is empty")
+    }
+
+    if hashAlg == "" {
+        hashAlg = "sha512"
+    }
+
+    signedData, err := sshsigSignedBytes(message, namespace, hashAlg)
+    if err != nil {
+        return nil, err
+    }
+
+    var sig *ssh.Signature
+
+    // RSA 密钥使用 rsa-sha2-512 签名
+    pubType := signer.PublicKey().Type()
+    if algSigner, ok := signer.(ssh.AlgorithmSigner); ok && (pubType == ssh.KeyAlgoRSA || pubType == ssh.CertAlgoRSAv01) {
+        sig, err = algSigner.SignWithAlgorithm(rand, signedData, ssh.KeyAlgoRSASHA512)
+    } else {
+        sig, err = signer.Sign(rand, signedData)
+    }
+
+    if err != nil {
+        return nil, err
+    }
+
+    return &SSHSig{
+        PublicKey:     signer.PublicKey(),
+        Namespace:     namespace,
+        HashAlgorithm: hashAlg,
+        Signature:     sig,
+    }, nil
+}
+
+// 验证签名, 只验证签名数据, 签名公钥是否可信需要另外检测
+func (this *SSHSig) Verify(message io.Reader, namespace string) error {
+    if this.Namespace != namespace {
+        return errors.New("ssh: sshsig namespace mismatch")
+    }
+
+    // 和 ssh-keygen 相同, 不接受 ssh-rsa (SHA1) 签名
+    if this.Signature.Format == ssh.KeyAlgoRSA {
+        return errors.New("ssh: sshsig rsa signature must use rsa-sha2-256 or rsa-sha2-512")
+    }
+
+    signedData, err := sshsigSignedBytes(message, this.Namespace, this.HashAlgorithm)
+    if err != nil {
+        return err
+    }
+
+    return this.PublicKey.Verify(signedData, this.Signature)
+}
+
+// 编码为二进制数据
+func (this *SSHSig) Marshal() []byte {
+    blob := ssh.Marshal(sshsigBlob{
+        Version:       sshsigVersion,
+        PublicKey:     this.PublicKey.Marshal(),
+        Namespace:     this.Namespace,
+        HashAlgorithm: this.HashAlgorithm,
+        Signature:     ssh.Marshal(this.Signature),
+    })
+
+    return append([]byte(sshsigMagic), blob...)
+}
+
+// 编码为 ssh-keygen 使用的格式
+func (this *SSHSig) Armor() []byte {
+    encoded := base64.StdEncoding.EncodeToString(this.Marshal())
+
+    var buf bytes.Buffer
+    buf.WriteString(sshsigArmorStart + "\n")
+
+    for len(encoded) > 70 {
+        buf.WriteString(encoded[:70] + "\n")
+        encoded = encoded[70:]
+    }
+
+    buf.WriteString(encoded + "\n")
+    buf.WriteString(sshsigArmorEnd + "\n")
+
+    return buf.Bytes()
+}
+
+// 解析 ssh-keygen 使用的格式
+func ParseArmoredSSHSig(in []byte) (*SSHSig, error) {
+    data := strings.TrimSpace(string(in))
+
+    if !strings.HasPrefix(data, sshsigArmorStart) || !strings.HasSuffix(data, sshsigArmorEnd) {
+        return nil, errors.New("ssh: sshsig armor is invalid")
+    }
+
+    data = data[len(sshsigArmorStart):len(data)-len(sshsigArmorEnd)]
+    data = strings.Join(strings.Fields(data), "")
+
+    blob, err := base64.StdEncoding.DecodeString(data)
+    if err != nil {
+        return nil, err
+    }
+
+    return ParseSSHSig(blob)
+}
+
+// 解析二进制数据
+func ParseSSHSig(in []byte) (*SSHSig, error) {
+    if !bytes.HasPrefix(in, []byte(sshsigMagic)) {
+        return nil, errors.New("ssh: sshsig magic preamble is invalid")
+    }
+
+    var blob sshsigBlob
+    if err := ssh.Unmarshal(in[len(sshsigMagic):], &blob); err != nil {
+        return nil, err
+    }
+
+    if blob.Version != sshsigVersion {
+        return nil, errors.New("ssh: sshsig version is not supported")
+    }
+
+    pub, err := ParsePublicKey(blob.PublicKey)
+    if err != nil {
+        return nil, err
+    }
+
+    sig := new(ssh.Signature)
+    if err := ssh.Unmarshal(blob.Signature, sig); err != nil {
+        return nil, err
+    }
+
+    return &SSHSig{
+        PublicKey:     pub,
+        Namespace:     blob.Namespace,
+        HashAlgorithm: blob.HashAlgorithm,
+        Signature:     sig,
+    }, nil
+}
+
+// 需要签名的数据
+func sshsigSignedBytes(message io.Reader, namespace, hashAlg string) ([]byte, error) {
+    newHash, ok := sshsigHashs[hashAlg]
+    if !ok {
+        return nil, errors.New("ssh: sshsig hash algorithm is not supported: " + hashAlg)
+    }
+
+    h := newHash()
+    if _, err := io.Copy(h, message); err != nil {
+        return nil, err
+    }
+
+    data := ssh.Marshal(sshsigSignedData{
+        Namespace:     namespace,
+        HashAlgorithm: hashAlg,
+        Hash:          h.Sum(nil),
+    })
+
+    return append([]byte(sshsigMagic), data...), nil
+}
@@ -0,0 +1,215 @@
+package ssh
+
+import (
+    "time"
+    "bytes"
+    "testing"
+    "crypto/rsa"
+    "crypto/rand"
+    "crypto/ecdsa"
+    "crypto/ed25519"
+    "crypto/elliptic"
+
+    "golang.org/x/crypto/ssh"
+
+    "github.com/deatil/go-cryptobin/gm/sm2"
+    cryptobin_test "github.com/deatil/go-cryptobin/tool/test"
+)
+
+// echo "hello sshsig" | ssh-keygen -Y sign -f ed -n file
+var testSSHKeygenSig = `-----BEGIN SSH SIGNATURE-----
+U1NIU0lHAAAAAQAAADMAAAALc3NoLWVkMjU1MTkAAAAgN5xxFLhu3E4Jgd5qGl0T1uM9wx
+qtTTfckEgpxk6qafMAAAAEZmlsZQAAAAAAAAAGc2hhNTEyAAAAUwAAAAtzc2gtZWQyNTUx
+OQAAAEAiZyX0OAu5uvL/aIhvB0YhivdR+NBgeAPW72oa83n/TcqbBWK64OFZUQ6bhQyNET
+RoOkodZTCs1zcyJ32EC7UH
+-----END SSH SIGNATURE-----
+`
+
+var testSSHKeygenSigMsg = "hello sshsig\n"
+
+var testAllowedSigners = `
+# comment
+alice@example.com namespaces="file,git" ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIDeccRS4btxOCYHeahpdE9bjPcMarU033JBIKcZOqmnz alice
+"*@example.org,!mallory@example.org" valid-after="20200101",valid-before="20300101Z" ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIDeccRS4btxOCYHeahpdE9bjPcMarU033JBIKcZOqmnz
+`
+
+func Test_ParseSSHKeygenSig(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    sig, err := ParseArmoredSSHSig([]byte(testSSHKeygenSig))
+    assertError(err, "Test_ParseSSHKeygenSig")
+
+    assertEqual(sig.Namespace, "file", "Test_ParseSSHKeygenSig-Namespace")
+    assertEqual(sig.HashAlgorithm, "sha512", "Test_ParseSSHKeygenSig-HashAlgorithm")
+    assertEqual(sig.PublicKey.Type(), ssh.KeyAlgoED25519, "Test_ParseSSHKeygenSig-PublicKey")
+
+    err = sig.Verify(bytes.NewReader([]byte(testSSHKeygenSigMsg)), "file")
+    assertError(err, "Test_ParseSSHKeygenSig-Verify")
+
+    err = sig.Verify(bytes.NewReader([]byte(testSSHKeygenSigMsg)), "git")
+    assertNotErrorNil(err, "Test_ParseSSHKeygenSig-Verify-Namespace")
+
+    err = sig.Verify(bytes.NewReader([]byte("hello sshsig")), "file")
+    assertNotErrorNil(err, "Test_ParseSSHKeygenSig-Verify-Message")
+
+    assertEqual(string(sig.Armor()), testSSHKeygenSig, "Test_ParseSSHKeygenSig-Armor")
+}
+
+func Test_SignSSHSig(t *testing.T) {
+    rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+    ecdsaKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    _, eddsaKey, _ := ed25519.GenerateKey(rand.Reader)
+    sm2Key, _ := sm2.GenerateKey(rand.Reader)
+
+    cases := []struct {
+        name    string
+        key     any
+        hashAlg string
+    }{
+        {"RSA", rsaKey, ""},
+        {"RSA-sha256", rsaKey, "sha256"},
+        {"ECDSA", ecdsaKey, "sha512"},
+        {"ED25519", eddsaKey, "sha256"},
+        {"SM2", sm2Key, "sm3"},
+    }
+
+    for _, c := range cases {
+        t.Run(c.name, func(t *testing.T) {
+            assertEqual := cryptobin_test.AssertEqualT(t)
+            assertError := cryptobin_test.AssertErrorT(t)
+            assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+            signer, err := NewSignerFromKey(c.key)
+            assertError(err, "NewSignerFromKey")
+
+            msg := []byte("test-sshsig-data")
+
+            sig, err := SignSSHSig(rand.Reader, signer, bytes.NewReader(msg), "git", c.hashAlg)
+            assertError(err, "SignSSHSig")
+
+            parsed, err := ParseArmoredSSHSig(sig.Armor())
+            assertError(err, "ParseArmoredSSHSig")
+
+            assertEqual(parsed.PublicKey.Marshal(), signer.PublicKey().Marshal(), "PublicKey")
+            assertEqual(parsed.Marshal(), sig.Marshal(), "Marshal")
+
+            err = parsed.Verify(bytes.NewReader(msg), "git")
+            assertError(err, "Verify")
+
+            err = parsed.Verify(bytes.NewReader([]byte("test-sshsig-data2")), "git")
+            assertNotErrorNil(err, "Verify-fail")
+        })
+    }
+}
+
+func Test_SignSSHSigError(t *testing.T) {
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    _, eddsaKey, _ := ed25519.GenerateKey(rand.Reader)
+    signer, _ := NewSignerFromKey(eddsaKey)
+
+    _, err := SignSSHSig(rand.Reader, signer, bytes.NewReader([]byte("data")), "", "")
+    assertNotErrorNil(err, "Test_SignSSHSigError-namespace")
+
+    _, err = SignSSHSig(rand.Reader, signer, bytes.NewReader([]byte("data")), "file", "md5")
+    assertNotErrorNil(err, "Test_SignSSHSigError-hash")
+
+    _, err = ParseSSHSig([]byte("SSHSIX"))
+    assertNotErrorNil(err, "Test_SignSSHSigError-magic")
+}
+
+func Test_ParseAllowedSigners(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    signers, err := ParseAllowedSigners([]byte(testAllowedSigners))
+    assertError(err, "Test_ParseAllowedSigners")
+
+    assertEqual(len(signers), 2, "Test_ParseAllowedSigners-len")
+    assertEqual(signers[0].Principals, []string{"alice@example.com"}, "Test_ParseAllowedSigners-Principals")
+    assertEqual(signers[0].Namespaces, []string{"file", "git"}, "Test_ParseAllowedSigners-Namespaces")
+    assertEqual(signers[1].Principals, []string{"*@example.org", "!mallory@example.org"}, "Test_ParseAllowedSigners-Principals-2")
+    assertEqual(signers[1].ValidBefore, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), "Test_ParseAllowedSigners-ValidBefore")
+
+    sig, err := ParseArmoredSSHSig([]byte(testSSHKeygenSig))
+    assertError(err, "Test_ParseAllowedSigners-sig")
+
+    now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+    signer, err := signers.Verify(bytes.NewReader([]byte(testSSHKeygenSigMsg)), sig, "alice@example.com", "file", now)
+    assertError(err, "Test_ParseAllowedSigners-Verify")
+    assertEqual(signer, signers[0], "Test_ParseAllowedSigners-Verify-signer")
+
+    _, err = signers.Verify(bytes.NewReader([]byte(testSSHKeygenSigMsg)), sig, "bob@example.org", "file", now)
+    assertError(err, "Test_ParseAllowedSigners-Verify-pattern")
+
+    _, err = signers.Verify(bytes.NewReader([]byte(testSSHKeygenSigMsg)), sig, "mallory@example.org", "file", now)
+    assertNotErrorNil(err, "Test_ParseAllowedSigners-Verify-negated")
+
+    _, err = signers.Verify(bytes.NewReader([]byte(testSSHKeygenSigMsg)), sig, "bob@example.org", "file", time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC))
+    assertNotErrorNil(err, "Test_ParseAllowedSigners-Verify-expired")
+
+    _, err = signers.Verify(bytes.NewReader([]byte(testSSHKeygenSigMsg)), sig, "bob@example.com", "file", now)
+    assertNotErrorNil(err, "Test_ParseAllowedSigners-Verify-principal")
+
+    principals := signers.FindPrincipals(sig.PublicKey, "file", now)
+    assertEqual(principals, []string{"alice@example.com", "*@example.org", "!mallory@example.org"}, "Test_ParseAllowedSigners-FindPrincipals")
+
+    _, err = ParseAllowedSigners([]byte(`alice@example.com unknown-opt ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIDeccRS4btxOCYHeahpdE9bjPcMarU033JBIKcZOqmnz`))
+    assertNotErrorNil(err, "Test_ParseAllowedSigners-option")
+}
+
+func Test_AllowedSignersCertAuthority(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+    assertError := cryptobin_test.AssertErrorT(t)
+    assertNotErrorNil := cryptobin_test.AssertNotErrorNilT(t)
+
+    caKey, _ := sm2.GenerateKey(rand.Reader)
+    caSigner, _ := NewSignerFromKey(caKey)
+
+    _, userKey, _ := ed25519.GenerateKey(rand.Reader)
+    userSigner, _ := NewSignerFromKey(userKey)
+
+    cert, err := SignCertificate(rand.Reader, userSigner.PublicKey(), caSigner, CertOpts{
+        CertType:   UserCert,
+        KeyId:      "alice",
+        Principals: []string{"alice"},
+    })
+    assertError(err, "Test_AllowedSignersCertAuthority-SignCertificate")
+
+    certSigner, err := ssh.NewCertSigner(cert, userSigner)
+    assertError(err, "Test_AllowedSignersCertAuthority-NewCertSigner")
+
+    msg := []byte("test-sshsig-data")
+
+    sig, err := SignSSHSig(rand.Reader, certSigner, bytes.NewReader(msg), "file", "")
+    assertError(err, "Test_AllowedSignersCertAuthority-SignSSHSig")
+
+    sig, err = ParseSSHSig(sig.Marshal())
+    assertError(err, "Test_AllowedSignersCertAuthority-ParseSSHSig")
+
+    allowed := "* cert-authority " + string(ssh.MarshalAuthorizedKey(caSigner.PublicKey()))
+
+    signers, err := ParseAllowedSigners([]byte(allowed))
+    assertError(err, "Test_AllowedSignersCertAuthority-ParseAllowedSigners")
+    assertEqual(signers[0].CertAuthority, true, "Test_AllowedSignersCertAuthority-CertAuthority")
+
+    _, err = signers.Verify(bytes.NewReader(msg), sig, "alice", "file", time.Time{})
+    assertError(err, "Test_AllowedSignersCertAuthority-Verify")
+
+    _, err = signers.Verify(bytes.NewReader(msg), sig, "bob", "file", time.Time{})
+    assertNotErrorNil(err, "Test_AllowedSignersCertAuthority-Verify-principal")
+}
+
+func Test_MatchPattern(t *testing.T) {
+    assertEqual := cryptobin_test.AssertEqualT(t)
+
+    assertEqual(matchPattern("*", ""), true, "Test_MatchPattern-1")
+    assertEqual(matchPattern("a*c", "abbbc"), true, "Test_MatchPattern-2")
+    assertEqual(matchPattern("a?c", "abc"), true, "Test_MatchPattern-3")
+    assertEqual(matchPattern("a?c", "ac"), false, "Test_MatchPattern-4")
+    assertEqual(matchPattern("*@example.com", "alice@example.org"), false, "Test_MatchPattern-5")
+}